@@ -0,0 +1,45 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var (
+	auditFrom     string
+	auditTo       string
+	auditItemType string
+	auditLimit    int32
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [flags]",
+	Short: "Show the audit log of your account",
+	Long: `This command shows the audit log of your account in GophKeeper, most recent first: who accessed
+which item, when, from which address and session, and whether the request succeeded.
+Failed authorization attempts for your login are shown too. Times are in UTC. For example:
+	- client audit
+	- client audit --type card --from 2024-01-01 --to "2024-02-01 12:00:00" --limit 20`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetAuditLog(auditFrom, auditTo, auditItemType, auditLimit); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	auditCmd.PersistentFlags().StringVar(&auditFrom, "from", "", "show events since this time (2006-01-02 or \"2006-01-02 15:04:05\", UTC)")
+	auditCmd.PersistentFlags().StringVar(&auditTo, "to", "", "show events before this time (2006-01-02 or \"2006-01-02 15:04:05\", UTC)")
+	auditCmd.PersistentFlags().StringVar(&auditItemType, "type", "", "item type: user, card, credentials, note, file, otp, custom, template, totp, session, vault, share, org, folder, tag or audit")
+	auditCmd.PersistentFlags().Int32Var(&auditLimit, "limit", 0, "maximum number of events (100 by default, up to 1000)")
+
+	rootCmd.AddCommand(auditCmd)
+}
@@ -6,27 +6,103 @@ Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
 package commands
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 )
 
+var (
+	otpCode        string
+	device         string
+	masterPassword string
+)
+
 // authCmd represents the authorize command
 var authCmd = &cobra.Command{
-	Use:   "auth [login] [password]",
+	Use:   "auth [login] [password] [flags]",
 	Short: "Authorize user",
 	Long: `Authorize user in GophKeeper and get JWT. For example:
-	client auth login password`,
+	client auth login password
+	client auth login password --otp 123456
+	client auth login password --master "correct horse battery staple"
+
+If two-factor authentication is enabled and the --otp flag isn't set,
+the code from the authenticator app (or a recovery code) is asked interactively.
+If the account has a master password vault and the --master flag isn't set,
+the master password is asked interactively as well.`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.Auth(args[0], args[1]); err != nil {
+		if device != "" {
+			viper.Set("device_name", device)
+		}
+		err := client.Auth(args[0], args[1], otpCode)
+		if errors.Is(err, client.ErrSecondFactorRequired) {
+			code, readErr := readLine("Enter the code from your authenticator app or a recovery code: ")
+			if readErr != nil {
+				fmt.Println(readErr)
+				return
+			}
+			err = client.Auth(args[0], args[1], code)
+		}
+		if err != nil {
 			fmt.Println(err)
+			return
+		}
+		if client.HasVault() {
+			unlockVault()
 		}
 	},
 }
 
+// unlockCmd represents the unlock command
+var unlockCmd = &cobra.Command{
+	Use:   "unlock [flags]",
+	Short: "Unlock the vault with the master password",
+	Long: `Derive the vault encryption key from the master password after authorization. For example:
+	client unlock
+	client unlock --master "correct horse battery staple"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		unlockVault()
+	},
+}
+
+// unlockVault unlocks the vault with the master password from the --master flag, or asks for it.
+func unlockVault() {
+	if masterPassword == "" {
+		var err error
+		masterPassword, err = readLine("Enter the master password: ")
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if err := client.UnlockVault(masterPassword); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// readLine prints the prompt and reads a single line from stdin.
+func readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
 func init() {
+	authCmd.PersistentFlags().StringVar(&otpCode, "otp", "", "TOTP code from the authenticator app or a recovery code")
+	authCmd.PersistentFlags().StringVar(&device, "device", "", "device name shown in the sessions list (host name by default)")
+	authCmd.PersistentFlags().StringVar(&masterPassword, "master", "", "master password to unlock the vault")
+	unlockCmd.PersistentFlags().StringVar(&masterPassword, "master", "", "master password to unlock the vault")
 	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(unlockCmd)
 }
@@ -27,7 +27,9 @@ var cardsCmd = &cobra.Command{
 	- client cards get cardID
 	- client cards getAll
 	- client cards add cardInfo
+	- client cards edit --id 9 --owner "Name Surname"
 	- client cards remove cardID`,
+	PersistentPreRun: requireSecretKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := cmd.Help()
 		if err != nil {
@@ -42,7 +44,7 @@ var addCardCmd = &cobra.Command{
 	Long: `This command allows you to add a new bank card to your account in GophKeeper. For example:
 	- client cards add --owner "Name Surname" --cvv 123 --expire 2024-12-26 --number 78878877 --desc "Test card"`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.AddCard(&card); err != nil {
+		if err := client.AddCard(&card, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -58,7 +60,7 @@ var getCardCmd = &cobra.Command{
 			fmt.Println("You must provide a bank card ID")
 			os.Exit(1)
 		}
-		if err := client.GetCard(cardID); err != nil {
+		if err := client.GetCard(cardID, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -74,7 +76,43 @@ var removeCardCmd = &cobra.Command{
 			fmt.Println("You must provide a bank card ID")
 			os.Exit(1)
 		}
-		if err := client.RemoveCard(cardID); err != nil {
+		if err := client.RemoveCard(cardID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var editCardCmd = &cobra.Command{
+	Use:   "edit [flags]",
+	Short: "Edit bank card by ID in GophKeeper",
+	Long: `This command allows you to change bank card info by ID in your account in GophKeeper keeping its ID.
+Only the fields of the passed flags are changed. For example:
+	- client cards edit --id 9 --owner "Name Surname"
+	- client cards edit --id 9 --expire 2026-12-26 --cvv 321`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if cardID < 0 {
+			fmt.Println("You must provide a bank card ID")
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		err := client.EditCard(cardID, collectionID, func(c *proto.BankCard) {
+			if flags.Changed("owner") {
+				c.Owner = card.Owner
+			}
+			if flags.Changed("cvv") {
+				c.Cvv = card.Cvv
+			}
+			if flags.Changed("expire") {
+				c.ExpireDate = card.ExpireDate
+			}
+			if flags.Changed("number") {
+				c.Number = card.Number
+			}
+			if flags.Changed("desc") {
+				c.Description = card.Description
+			}
+		})
+		if err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -84,9 +122,10 @@ var getAllCardsCmd = &cobra.Command{
 	Use:   "getAll",
 	Short: "Get all bank cards from GophKeeper",
 	Long: `This command allows you to get all bank cards from your account in GophKeeper. For example:
-	- client cards getAll`,
+	- client cards getAll
+	- client cards getAll --folder Work/Servers --tag prod`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.GetAllCards(); err != nil {
+		if err := client.GetAllCards(collectionID, itemFilter); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -103,9 +142,21 @@ func init() {
 
 	removeCardCmd.PersistentFlags().Int64Var(&cardID, "id", -1, "bank card id")
 
+	editCardCmd.PersistentFlags().Int64Var(&cardID, "id", -1, "bank card id")
+	editCardCmd.PersistentFlags().StringVar(&card.Owner, "owner", "", "new bank card owner")
+	editCardCmd.PersistentFlags().StringVar(&card.Cvv, "cvv", "", "new bank card CVV")
+	editCardCmd.PersistentFlags().StringVar(&card.ExpireDate, "expire", "", "new bank card expire date")
+	editCardCmd.PersistentFlags().StringVar(&card.Number, "number", "", "new bank card number")
+	editCardCmd.PersistentFlags().StringVar(&card.Description, "desc", "", "new bank card description")
+
+	addFilterFlags(getAllCardsCmd)
+
+	cardsCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "organization collection id, the personal cards if not set")
+
 	cardsCmd.AddCommand(getCardCmd)
 	cardsCmd.AddCommand(removeCardCmd)
 	cardsCmd.AddCommand(addCardCmd)
+	cardsCmd.AddCommand(editCardCmd)
 	cardsCmd.AddCommand(getAllCardsCmd)
 	rootCmd.AddCommand(cardsCmd)
 }
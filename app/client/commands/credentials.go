@@ -18,6 +18,9 @@ import (
 var (
 	credID      int64
 	credentials proto.Credentials
+	corpusPath  string
+	maxAgeDays  int
+	generate    bool
 )
 
 // credentialsCmd represents the user credentials management command
@@ -28,7 +31,10 @@ var credentialsCmd = &cobra.Command{
 	- client credentials get credID
 	- client credentials getAll
 	- client credentials add credentials info
-	- client credentials remove credID`,
+	- client credentials edit --id 9 --pass NewPassword
+	- client credentials remove credID
+	- client credentials audit --corpus pwned-passwords-sha1-ordered-by-hash.txt`,
+	PersistentPreRun: requireSecretKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := cmd.Help()
 		if err != nil {
@@ -41,10 +47,30 @@ var addCredentialCmd = &cobra.Command{
 	Use:   "add [flags]",
 	Short: "Add a new user credentials to GophKeeper",
 	Long: `This command allows you to add a new user credentials to your account in GophKeeper. For example:
-	- client credentials add --login Login --pass Password --desc Description`,
+	- client credentials add --login Login --pass Password --desc Description
+	- client credentials add --login Login --generate --length 24 --desc Description`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.AddCredentials(&credentials); err != nil {
+		var generatedPassword string
+		if generate {
+			if credentials.Password != "" {
+				fmt.Println("You must provide either the password or the generate flag")
+				os.Exit(1)
+			}
+			var err error
+			generatedPassword, err = generatePassword()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			credentials.Password = generatedPassword
+		}
+		if err := client.AddCredentials(&credentials, collectionID); err != nil {
 			fmt.Println(err)
+			return
+		}
+		if generate {
+			// AddCredentials encrypts the credentials in place, so the password is printed from the local copy.
+			fmt.Println("Generated password:", generatedPassword)
 		}
 	},
 }
@@ -59,7 +85,7 @@ var getCredentialsCmd = &cobra.Command{
 			fmt.Println("You must provide a credential ID")
 			os.Exit(1)
 		}
-		if err := client.GetCredentials(credID); err != nil {
+		if err := client.GetCredentials(credID, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -75,8 +101,55 @@ var removeCredentialsCmd = &cobra.Command{
 			fmt.Println("You must provide a credential ID")
 			os.Exit(1)
 		}
-		if err := client.RemoveCredentials(credID); err != nil {
+		if err := client.RemoveCredentials(credID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var editCredentialsCmd = &cobra.Command{
+	Use:   "edit [flags]",
+	Short: "Edit user credentials by ID in GophKeeper",
+	Long: `This command allows you to change user credentials by ID in your account in GophKeeper keeping their ID.
+Only the fields of the passed flags are changed. For example:
+	- client credentials edit --id 9 --pass NewPassword
+	- client credentials edit --id 9 --generate --length 24
+	- client credentials edit --id 9 --login NewLogin --desc NewDescription`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if credID < 0 {
+			fmt.Println("You must provide a credential ID")
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		if generate {
+			if flags.Changed("pass") {
+				fmt.Println("You must provide either the password or the generate flag")
+				os.Exit(1)
+			}
+			password, err := generatePassword()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			credentials.Password = password
+		}
+		err := client.EditCredentials(credID, collectionID, func(c *proto.Credentials) {
+			if flags.Changed("login") {
+				c.Login = credentials.Login
+			}
+			if flags.Changed("pass") || generate {
+				c.Password = credentials.Password
+			}
+			if flags.Changed("desc") {
+				c.Description = credentials.Description
+			}
+		})
+		if err != nil {
 			fmt.Println(err)
+			return
+		}
+		if generate {
+			fmt.Println("Generated password:", credentials.Password)
 		}
 	},
 }
@@ -85,9 +158,29 @@ var getAllCredentialsCmd = &cobra.Command{
 	Use:   "getAll",
 	Short: "Get all user credentials from GophKeeper",
 	Long: `This command allows you to get all user credentials from your account in GophKeeper. For example:
-	- client credentials getAll`,
+	- client credentials getAll
+	- client credentials getAll --folder Work/Servers --tag prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetAllCredentials(collectionID, itemFilter); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var auditCredentialsCmd = &cobra.Command{
+	Use:   "audit [flags]",
+	Short: "Check the stored passwords for breaches and weaknesses",
+	Long: `This command decrypts all your credentials locally and reports the passwords found in a breach corpus,
+the weak passwords, the passwords reused across the credentials and the passwords older than --max-age days.
+The corpus is a sorted file of SHA-1 hashes in the Have I Been Pwned format, the passwords are never sent
+anywhere. For example:
+	- client credentials audit --corpus pwned-passwords-sha1-ordered-by-hash.txt --max-age 180`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.GetAllCredentials(); err != nil {
+		if maxAgeDays < 0 {
+			fmt.Println("The maximum age must not be negative")
+			os.Exit(1)
+		}
+		if err := client.AuditCredentials(corpusPath, maxAgeDays, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -97,13 +190,31 @@ func init() {
 	addCredentialCmd.PersistentFlags().StringVar(&credentials.Login, "login", "", "login")
 	addCredentialCmd.PersistentFlags().StringVar(&credentials.Password, "pass", "", "password")
 	addCredentialCmd.PersistentFlags().StringVar(&credentials.Description, "desc", "", "credentials description")
+	addCredentialCmd.PersistentFlags().BoolVar(&generate, "generate", false, "generate a random password with the policy flags")
+	addGenerateFlags(addCredentialCmd)
 
 	getCredentialsCmd.PersistentFlags().Int64Var(&credID, "id", -1, "credentials id")
 	removeCredentialsCmd.PersistentFlags().Int64Var(&credID, "id", -1, "credentials id")
 
+	editCredentialsCmd.PersistentFlags().Int64Var(&credID, "id", -1, "credentials id")
+	editCredentialsCmd.PersistentFlags().StringVar(&credentials.Login, "login", "", "new login")
+	editCredentialsCmd.PersistentFlags().StringVar(&credentials.Password, "pass", "", "new password")
+	editCredentialsCmd.PersistentFlags().StringVar(&credentials.Description, "desc", "", "new credentials description")
+	editCredentialsCmd.PersistentFlags().BoolVar(&generate, "generate", false, "generate a new random password with the policy flags")
+	addGenerateFlags(editCredentialsCmd)
+
+	auditCredentialsCmd.PersistentFlags().StringVar(&corpusPath, "corpus", "", "path to the breach corpus, the breach check is skipped if not set")
+	auditCredentialsCmd.PersistentFlags().IntVar(&maxAgeDays, "max-age", 365, "age of the passwords in days to report them as old, 0 to skip the check")
+
+	addFilterFlags(getAllCredentialsCmd)
+
+	credentialsCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "organization collection id, the personal credentials if not set")
+
 	credentialsCmd.AddCommand(getCredentialsCmd)
 	credentialsCmd.AddCommand(removeCredentialsCmd)
 	credentialsCmd.AddCommand(addCredentialCmd)
+	credentialsCmd.AddCommand(editCredentialsCmd)
 	credentialsCmd.AddCommand(getAllCredentialsCmd)
+	credentialsCmd.AddCommand(auditCredentialsCmd)
 	rootCmd.AddCommand(credentialsCmd)
 }
@@ -12,12 +12,16 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/proto"
 )
 
 var (
 	filePath    string
 	fileName    string
 	description string
+	newFileName string
+
+	allowPlaintext bool
 )
 
 // filesCmd represents the files management command
@@ -28,7 +32,9 @@ var filesCmd = &cobra.Command{
 	- client files download --id fileID --path /path/to/file
 	- client files upload --path /path/to/file --desc "File description"
 	- client files getAll
+	- client files edit --name FileName --new-name NewFileName
 	- client files remove fileID`,
+	PersistentPreRun: requireSecretKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := cmd.Help()
 		if err != nil {
@@ -41,13 +47,15 @@ var downloadCmd = &cobra.Command{
 	Use:   "download [flags]",
 	Short: "Download file from GophKeeper",
 	Long: `This command allows you to download file from your account in GophKeeper. For example:
-	- client files download --name FileName --dir /path/to/file`,
+	- client files download --name FileName --dir /path/to/file
+Files uploaded by older client versions are not encrypted, they are only saved with --allow-legacy-plaintext,
+because such a file can't be told apart from a file replaced on the server.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if fileName == "" || filePath == "" {
 			fmt.Println("You must provide a correct file name and dir")
 			os.Exit(1)
 		}
-		if err := client.DownloadFile(fileName, filePath); err != nil {
+		if err := client.DownloadFile(fileName, filePath, collectionID, allowPlaintext); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -64,7 +72,7 @@ var uploadCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if err := client.UploadFile(filePath, description); err != nil {
+		if err := client.UploadFile(filePath, description, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -80,7 +88,38 @@ var removeCmd = &cobra.Command{
 			fmt.Println("You must provide a file name")
 			os.Exit(1)
 		}
-		if err := client.RemoveFile(fileName); err != nil {
+		if err := client.RemoveFile(fileName, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var editFileCmd = &cobra.Command{
+	Use:   "edit [flags]",
+	Short: "Edit file info by name in GophKeeper",
+	Long: `This command allows you to rename the file or change its description in your account in GophKeeper
+without uploading it again. Only the fields of the passed flags are changed. For example:
+	- client files edit --name FileName --new-name NewFileName
+	- client files edit --name FileName --desc "New file description"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if fileName == "" {
+			fmt.Println("You must provide a file name")
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("new-name") && newFileName == "" {
+			fmt.Println("You must provide a non-empty new file name")
+			os.Exit(1)
+		}
+		err := client.EditFile(fileName, collectionID, func(f *proto.File) {
+			if flags.Changed("new-name") {
+				f.FileName = newFileName
+			}
+			if flags.Changed("desc") {
+				f.Description = description
+			}
+		})
+		if err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -90,9 +129,10 @@ var getAllCmd = &cobra.Command{
 	Use:   "getAll",
 	Short: "Get all files infos from GophKeeper",
 	Long: `This command allows you to get all files infos from your account in GophKeeper. For example:
-	- client files getAll`,
+	- client files getAll
+	- client files getAll --folder Work/Servers --tag prod`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.GetAllFiles(); err != nil {
+		if err := client.GetAllFiles(collectionID, itemFilter); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -101,15 +141,25 @@ var getAllCmd = &cobra.Command{
 func init() {
 	downloadCmd.PersistentFlags().StringVar(&fileName, "name", "", "file name to download")
 	downloadCmd.PersistentFlags().StringVar(&filePath, "dir", "", "dir where to download file")
+	downloadCmd.PersistentFlags().BoolVar(&allowPlaintext, "allow-legacy-plaintext", false, "save the file as is if it is not encrypted")
 
 	uploadCmd.PersistentFlags().StringVar(&filePath, "path", "", "path to source file")
 	uploadCmd.PersistentFlags().StringVar(&description, "desc", "", "file description")
 
 	removeCmd.PersistentFlags().StringVar(&fileName, "name", "", "file name to remove")
 
+	editFileCmd.PersistentFlags().StringVar(&fileName, "name", "", "file name to edit")
+	editFileCmd.PersistentFlags().StringVar(&newFileName, "new-name", "", "new file name")
+	editFileCmd.PersistentFlags().StringVar(&description, "desc", "", "new file description")
+
+	addFilterFlags(getAllCmd)
+
+	filesCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "organization collection id, the personal files if not set")
+
 	filesCmd.AddCommand(downloadCmd)
 	filesCmd.AddCommand(uploadCmd)
 	filesCmd.AddCommand(removeCmd)
+	filesCmd.AddCommand(editFileCmd)
 	filesCmd.AddCommand(getAllCmd)
 	rootCmd.AddCommand(filesCmd)
 }
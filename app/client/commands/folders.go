@@ -0,0 +1,169 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var (
+	folderPath    string
+	folderName    string
+	folderParent  string
+	labelItemType string
+	labelItemID   int64
+	itemFilter    client.ItemFilter
+)
+
+// addFilterFlags adds the flags selecting the items by their folder and tag to a getAll command.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&itemFilter.Folder, "folder", "", "list only the items of the folder and its subfolders, e.g. Work/Servers")
+	cmd.PersistentFlags().StringVar(&itemFilter.Tag, "tag", "", "list only the items with the tag")
+}
+
+// checkLabelItemFlags stops a command if the item to place in a folder or to tag isn't provided.
+func checkLabelItemFlags() {
+	if labelItemType == "" || labelItemID < 0 {
+		fmt.Println("You must provide the item type and the item ID")
+		os.Exit(1)
+	}
+}
+
+// checkFolderPath stops a command if the folder path isn't provided.
+func checkFolderPath() {
+	if folderPath == "" {
+		fmt.Println("You must provide the folder path")
+		os.Exit(1)
+	}
+}
+
+var foldersCmd = &cobra.Command{
+	Use:   "folders [command] [flags]",
+	Short: "Folders management",
+	Long: `Hierarchical folders of your personal items in GophKeeper. A folder is selected by its path,
+the names of the folders are encrypted with your secret key. For example:
+	- client folders getAll
+	- client folders create --path Work/Servers
+	- client folders put --type credentials --id 3 --path Work/Servers
+	- client credentials getAll --folder Work`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var createFolderCmd = &cobra.Command{
+	Use:   "create [flags]",
+	Short: "Create a folder",
+	Long: `This command creates a folder, the parent folders in the path must exist. For example:
+	- client folders create --path Work
+	- client folders create --path Work/Servers`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkFolderPath()
+		if err := client.CreateFolder(folderPath); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var renameFolderCmd = &cobra.Command{
+	Use:   "rename [flags]",
+	Short: "Rename a folder",
+	Long: `This command changes the name of a folder, the folder stays in its parent folder. For example:
+	- client folders rename --path Work/Servers --name Hosts`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkFolderPath()
+		if folderName == "" {
+			fmt.Println("You must provide the new folder name")
+			os.Exit(1)
+		}
+		if err := client.RenameFolder(folderPath, folderName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var moveFolderCmd = &cobra.Command{
+	Use:   "move [flags]",
+	Short: "Move a folder into another folder",
+	Long: `This command moves a folder with its subfolders and items into another folder,
+or to the top level if --to is not set. For example:
+	- client folders move --path Work/Servers --to Personal
+	- client folders move --path Personal/Servers`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkFolderPath()
+		if err := client.MoveFolder(folderPath, folderParent); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeFolderCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove a folder",
+	Long: `This command removes a folder with its subfolders, the items of the folders are kept without a folder.
+For example:
+	- client folders remove --path Work/Servers`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkFolderPath()
+		if err := client.RemoveFolder(folderPath); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getAllFoldersCmd = &cobra.Command{
+	Use:   "getAll",
+	Short: "Show the folder tree",
+	Long: `This command shows your folders with the number of the items in every folder. For example:
+	- client folders getAll`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetAllFolders(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var putFolderCmd = &cobra.Command{
+	Use:   "put [flags]",
+	Short: "Put an item into a folder",
+	Long: `This command puts your bank card, credentials, note, file, otp item or custom item into a folder,
+an item is placed in one folder at most. Without --path the item is taken out of its folder. For example:
+	- client folders put --type credentials --id 3 --path Work/Servers
+	- client folders put --type file --id 7`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLabelItemFlags()
+		if err := client.SetItemFolder(labelItemType, labelItemID, folderPath); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{createFolderCmd, renameFolderCmd, moveFolderCmd, removeFolderCmd, putFolderCmd} {
+		cmd.PersistentFlags().StringVar(&folderPath, "path", "", "folder path, e.g. Work/Servers")
+	}
+	renameFolderCmd.PersistentFlags().StringVar(&folderName, "name", "", "new folder name")
+	moveFolderCmd.PersistentFlags().StringVar(&folderParent, "to", "", "path of the new parent folder, the top level if not set")
+	putFolderCmd.PersistentFlags().StringVar(&labelItemType, "type", "", "item type: card, credentials, note, file, otp or custom")
+	putFolderCmd.PersistentFlags().Int64Var(&labelItemID, "id", -1, "item id")
+
+	foldersCmd.AddCommand(createFolderCmd)
+	foldersCmd.AddCommand(renameFolderCmd)
+	foldersCmd.AddCommand(moveFolderCmd)
+	foldersCmd.AddCommand(removeFolderCmd)
+	foldersCmd.AddCommand(getAllFoldersCmd)
+	foldersCmd.AddCommand(putFolderCmd)
+	rootCmd.AddCommand(foldersCmd)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/pkg/passgen"
+)
+
+var (
+	genPolicy     = passgen.DefaultPolicy()
+	genNoLower    bool
+	genNoUpper    bool
+	genNoDigits   bool
+	genNoSymbols  bool
+	genPassphrase bool
+	genWords      int
+	genSeparator  string
+	genCapitalize bool
+)
+
+// addGenerateFlags adds the flags of the password policy to a command.
+func addGenerateFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.IntVar(&genPolicy.Length, "length", genPolicy.Length, "password length")
+	flags.BoolVar(&genNoLower, "no-lower", false, "don't use lower-case letters")
+	flags.BoolVar(&genNoUpper, "no-upper", false, "don't use upper-case letters")
+	flags.BoolVar(&genNoDigits, "no-digits", false, "don't use digits")
+	flags.BoolVar(&genNoSymbols, "no-symbols", false, "don't use symbols")
+	flags.IntVar(&genPolicy.MinLower, "min-lower", genPolicy.MinLower, "minimum number of lower-case letters")
+	flags.IntVar(&genPolicy.MinUpper, "min-upper", genPolicy.MinUpper, "minimum number of upper-case letters")
+	flags.IntVar(&genPolicy.MinDigits, "min-digits", genPolicy.MinDigits, "minimum number of digits")
+	flags.IntVar(&genPolicy.MinSymbols, "min-symbols", genPolicy.MinSymbols, "minimum number of symbols")
+	flags.BoolVar(&genPolicy.ExcludeAmbiguous, "exclude-ambiguous", false, "exclude the ambiguous characters "+passgen.AmbiguousChars)
+	flags.BoolVar(&genPassphrase, "passphrase", false, "generate a passphrase of words instead of a password")
+	flags.IntVar(&genWords, "words", 6, "number of words in the passphrase")
+	flags.StringVar(&genSeparator, "separator", "-", "separator of the words in the passphrase")
+	flags.BoolVar(&genCapitalize, "capitalize", false, "capitalize the words of the passphrase")
+}
+
+// generatePassword generates a password or a passphrase with the flags of the policy.
+func generatePassword() (string, error) {
+	if genPassphrase {
+		return passgen.Passphrase(genWords, genSeparator, genCapitalize)
+	}
+
+	policy := genPolicy
+	policy.Lower, policy.Upper, policy.Digits, policy.Symbols = !genNoLower, !genNoUpper, !genNoDigits, !genNoSymbols
+	// Excluding a class drops its default minimum.
+	for _, class := range []struct {
+		excluded bool
+		min      *int
+	}{{genNoLower, &policy.MinLower}, {genNoUpper, &policy.MinUpper}, {genNoDigits, &policy.MinDigits}, {genNoSymbols, &policy.MinSymbols}} {
+		if class.excluded && *class.min == 1 {
+			*class.min = 0
+		}
+	}
+	return passgen.Password(policy)
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate [flags]",
+	Short: "Generate a random password or passphrase",
+	Long: `This command generates a random password following a policy, or a passphrase of random words
+from the EFF large word list. Nothing is sent to the server. For example:
+	- client generate --length 24 --exclude-ambiguous
+	- client generate --no-symbols --min-digits 4
+	- client generate --passphrase --words 7 --separator " "`,
+	Run: func(cmd *cobra.Command, args []string) {
+		password, err := generatePassword()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(password)
+	},
+}
+
+func init() {
+	addGenerateFlags(generateCmd)
+	rootCmd.AddCommand(generateCmd)
+}
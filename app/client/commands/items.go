@@ -0,0 +1,206 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+var (
+	customItemID     int64
+	customItem       proto.CustomItem
+	itemFieldValues  []string
+	itemFieldFiles   []string
+	revealItemValues bool
+)
+
+// parseItemValues returns the field values of the --field name=value flags and the values read from
+// the files of the --field-file name=path flags.
+func parseItemValues(values, files []string) (map[string]string, error) {
+	result := make(map[string]string, len(values)+len(files))
+	for _, spec := range values {
+		name, value, found := strings.Cut(spec, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid field %q, use the name=value form", spec)
+		}
+		result[name] = value
+	}
+	for _, spec := range files {
+		name, path, found := strings.Cut(spec, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid field file %q, use the name=path form", spec)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = string(data)
+	}
+	return result, nil
+}
+
+var itemsCmd = &cobra.Command{
+	Use:   "items [command] [flags]",
+	Short: "Custom items management",
+	Long: `Management of the items of custom types in GophKeeper: SSH keys, API tokens, Wi-Fi passwords,
+licences, identity documents or your own templates (see the templates command). For example:
+	- client items add --template wifi --name Home --field ssid=HomeNet --field password=secret
+	- client items get --id 1 --reveal
+	- client items edit --id 1 --field password=newSecret
+	- client items getAll
+	- client items remove --id 1`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var addItemCmd = &cobra.Command{
+	Use:   "add [flags]",
+	Short: "Add a new custom item to GophKeeper",
+	Long: `This command allows you to add a new item of a template to your account in GophKeeper.
+The fields are set by the --field name=value flags, the multiline values can be read from files
+by the --field-file name=path flags, the other fields are left empty. For example:
+	- client items add --template api-token --name GitHub --field url=https://api.github.com --field token=ghp_123
+	- client items add --template ssh-key --name Server --field host=example.com --field-file private_key=/home/user/.ssh/id_ed25519`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if customItem.Template == "" || customItem.Name == "" {
+			fmt.Println("You must provide a template and an item name")
+			os.Exit(1)
+		}
+		values, err := parseItemValues(itemFieldValues, itemFieldFiles)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err = client.AddCustomItem(customItem.Template, customItem.Name, customItem.Description, values, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getItemCmd = &cobra.Command{
+	Use:   "get [flags]",
+	Short: "Get custom item by ID from GophKeeper",
+	Long: `This command allows you to get the custom item with its fields by ID from your account in GophKeeper.
+The values of the secret fields are hidden unless --reveal is passed. For example:
+	- client items get --id 1
+	- client items get --id 1 --reveal`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if customItemID < 0 {
+			fmt.Println("You must provide an item ID")
+			os.Exit(1)
+		}
+		if err := client.GetCustomItem(customItemID, collectionID, revealItemValues); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var editItemCmd = &cobra.Command{
+	Use:   "edit [flags]",
+	Short: "Edit custom item by ID in GophKeeper",
+	Long: `This command allows you to change the custom item by ID in your account in GophKeeper keeping its ID and template.
+Only the name, the description and the fields of the passed flags are changed. For example:
+	- client items edit --id 1 --field token=ghp_456 --field expires=2027-12-31
+	- client items edit --id 1 --name NewName --desc NewDescription`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if customItemID < 0 {
+			fmt.Println("You must provide an item ID")
+			os.Exit(1)
+		}
+		values, err := parseItemValues(itemFieldValues, itemFieldFiles)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("name") && customItem.Name == "" {
+			fmt.Println("You must provide a non-empty item name")
+			os.Exit(1)
+		}
+		err = client.EditCustomItem(customItemID, collectionID, values, func(item *proto.CustomItem) {
+			if flags.Changed("name") {
+				item.Name = customItem.Name
+			}
+			if flags.Changed("desc") {
+				item.Description = customItem.Description
+			}
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeItemCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove custom item by ID from GophKeeper",
+	Long: `This command allows you to remove the custom item by ID from your account in GophKeeper. For example:
+	- client items remove --id 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if customItemID < 0 {
+			fmt.Println("You must provide an item ID")
+			os.Exit(1)
+		}
+		if err := client.RemoveCustomItem(customItemID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getAllItemsCmd = &cobra.Command{
+	Use:   "getAll",
+	Short: "Get all custom items from GophKeeper",
+	Long: `This command allows you to get all custom items from your account in GophKeeper. For example:
+	- client items getAll
+	- client items getAll --folder Work/Servers --tag prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetAllCustomItems(collectionID, itemFilter); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	addItemCmd.PersistentFlags().StringVar(&customItem.Template, "template", "", "item template name")
+	addItemCmd.PersistentFlags().StringVar(&customItem.Name, "name", "", "item name")
+	addItemCmd.PersistentFlags().StringVar(&customItem.Description, "desc", "", "item description")
+	addItemCmd.PersistentFlags().StringArrayVar(&itemFieldValues, "field", nil, "field value in the name=value form, repeat for every field")
+	addItemCmd.PersistentFlags().StringArrayVar(&itemFieldFiles, "field-file", nil, "field value read from a file in the name=path form")
+
+	getItemCmd.PersistentFlags().Int64Var(&customItemID, "id", -1, "item id")
+	getItemCmd.PersistentFlags().BoolVar(&revealItemValues, "reveal", false, "show the values of the secret fields")
+
+	editItemCmd.PersistentFlags().Int64Var(&customItemID, "id", -1, "item id")
+	editItemCmd.PersistentFlags().StringVar(&customItem.Name, "name", "", "new item name")
+	editItemCmd.PersistentFlags().StringVar(&customItem.Description, "desc", "", "new item description")
+	editItemCmd.PersistentFlags().StringArrayVar(&itemFieldValues, "field", nil, "new field value in the name=value form, repeat for every field")
+	editItemCmd.PersistentFlags().StringArrayVar(&itemFieldFiles, "field-file", nil, "new field value read from a file in the name=path form")
+
+	removeItemCmd.PersistentFlags().Int64Var(&customItemID, "id", -1, "item id")
+
+	addFilterFlags(getAllItemsCmd)
+
+	itemsCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "organization collection id, the personal items if not set")
+
+	itemsCmd.AddCommand(addItemCmd)
+	itemsCmd.AddCommand(getItemCmd)
+	itemsCmd.AddCommand(editItemCmd)
+	itemsCmd.AddCommand(removeItemCmd)
+	itemsCmd.AddCommand(getAllItemsCmd)
+	rootCmd.AddCommand(itemsCmd)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Log out user",
+	Long: `Revoke the current session in GophKeeper and remove the stored tokens. For example:
+	client logout`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.Logout(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+}
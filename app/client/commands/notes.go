@@ -28,7 +28,9 @@ var notesCmd = &cobra.Command{
 	- client notes get noteID
 	- client notes getAll
 	- client notes add note info
+	- client notes edit --id 9 --text NewText
 	- client notes remove noteID`,
+	PersistentPreRun: requireSecretKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := cmd.Help()
 		if err != nil {
@@ -43,7 +45,7 @@ var addNoteCmd = &cobra.Command{
 	Long: `This command allows you to add a new user note to your account in GophKeeper. For example:
 	- client notes add --text NoteText --desc Description`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.AddNote(&note); err != nil {
+		if err := client.AddNote(&note, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -59,7 +61,7 @@ var getNoteCmd = &cobra.Command{
 			fmt.Println("You must provide a note ID")
 			os.Exit(1)
 		}
-		if err := client.GetNote(noteID); err != nil {
+		if err := client.GetNote(noteID, collectionID); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -75,7 +77,34 @@ var removeNoteCmd = &cobra.Command{
 			fmt.Println("You must provide a note ID")
 			os.Exit(1)
 		}
-		if err := client.RemoveNote(noteID); err != nil {
+		if err := client.RemoveNote(noteID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var editNoteCmd = &cobra.Command{
+	Use:   "edit [flags]",
+	Short: "Edit note by ID in GophKeeper",
+	Long: `This command allows you to change the note by ID in your account in GophKeeper keeping its ID.
+Only the fields of the passed flags are changed. For example:
+	- client notes edit --id 9 --text NewText
+	- client notes edit --id 9 --desc NewDescription`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if noteID < 0 {
+			fmt.Println("You must provide a note ID")
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		err := client.EditNote(noteID, collectionID, func(n *proto.Note) {
+			if flags.Changed("text") {
+				n.Text = note.Text
+			}
+			if flags.Changed("desc") {
+				n.Description = note.Description
+			}
+		})
+		if err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -85,9 +114,10 @@ var getAllNotesCmd = &cobra.Command{
 	Use:   "getAll",
 	Short: "Get all user notes from GophKeeper",
 	Long: `This command allows you to get all user notes from your account in GophKeeper. For example:
-	- client notes getAll`,
+	- client notes getAll
+	- client notes getAll --folder Work/Servers --tag prod`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.GetAllNotes(); err != nil {
+		if err := client.GetAllNotes(collectionID, itemFilter); err != nil {
 			fmt.Println(err)
 		}
 	},
@@ -101,9 +131,18 @@ func init() {
 
 	removeNoteCmd.PersistentFlags().Int64Var(&noteID, "id", -1, "note id")
 
+	editNoteCmd.PersistentFlags().Int64Var(&noteID, "id", -1, "note id")
+	editNoteCmd.PersistentFlags().StringVar(&note.Text, "text", "", "new text")
+	editNoteCmd.PersistentFlags().StringVar(&note.Description, "desc", "", "new note description")
+
+	addFilterFlags(getAllNotesCmd)
+
+	notesCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "organization collection id, the personal notes if not set")
+
 	notesCmd.AddCommand(getNoteCmd)
 	notesCmd.AddCommand(removeNoteCmd)
 	notesCmd.AddCommand(addNoteCmd)
+	notesCmd.AddCommand(editNoteCmd)
 	notesCmd.AddCommand(getAllNotesCmd)
 	rootCmd.AddCommand(notesCmd)
 }
@@ -0,0 +1,227 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var (
+	orgID        int64
+	orgName      string
+	orgUser      string
+	orgRole      string
+	collectionID int64
+)
+
+var orgCmd = &cobra.Command{
+	Use:   "org [command] [flags]",
+	Short: "Organizations management",
+	Long: `Organizations management in GophKeeper. An organization owns collections of bank cards, credentials,
+notes and files, its members have the owner, admin, editor or viewer role. For example:
+	- client org create --name "Team"
+	- client org add-member --org 1 --user alice --role editor
+	- client org add-collection --org 1 --name "Servers"
+	- client notes add --collection 1 --text "text" --desc "description"`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+// requireOrgID stops a command if the organization ID isn't provided.
+func requireOrgID() {
+	if orgID < 0 {
+		fmt.Println("You must provide an organization ID")
+		os.Exit(1)
+	}
+}
+
+// requireOrgUser stops a command if the organization ID or the login of the member isn't provided.
+func requireOrgUser() {
+	requireOrgID()
+	if orgUser == "" {
+		fmt.Println("You must provide the login of the member")
+		os.Exit(1)
+	}
+}
+
+var createOrgCmd = &cobra.Command{
+	Use:   "create [flags]",
+	Short: "Create an organization",
+	Long: `This command creates an organization you are the owner of. For example:
+	- client org create --name "Team"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if orgName == "" {
+			fmt.Println("You must provide the organization name")
+			os.Exit(1)
+		}
+		if err := client.CreateOrganization(orgName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var listOrgsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show your organizations",
+	Long: `This command shows the organizations you are a member of and your role in them. For example:
+	- client org list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.ListOrganizations(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeOrgCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove an organization",
+	Long: `This command removes an organization with all its collections and items, only the owners can do it.
+For example:
+	- client org remove --org 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgID()
+		if err := client.RemoveOrganization(orgID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var listOrgMembersCmd = &cobra.Command{
+	Use:   "members [flags]",
+	Short: "Show the members of an organization",
+	Long: `This command shows the members of an organization and their roles. For example:
+	- client org members --org 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgID()
+		if err := client.ListOrgMembers(orgID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var addOrgMemberCmd = &cobra.Command{
+	Use:   "add-member [flags]",
+	Short: "Add a user to an organization",
+	Long: `This command adds a user to an organization with a role not higher than yours. The user must have run
+the share list command at least once. For example:
+	- client org add-member --org 1 --user alice --role viewer`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgUser()
+		if err := client.AddOrgMember(orgID, orgUser, orgRole); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var setOrgRoleCmd = &cobra.Command{
+	Use:   "set-role [flags]",
+	Short: "Change the role of an organization member",
+	Long: `This command changes the role of an organization member. For example:
+	- client org set-role --org 1 --user alice --role admin`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgUser()
+		if err := client.SetOrgMemberRole(orgID, orgUser, orgRole); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeOrgMemberCmd = &cobra.Command{
+	Use:   "remove-member [flags]",
+	Short: "Remove a member from an organization",
+	Long: `This command removes a member from an organization, remove yourself to leave it. For example:
+	- client org remove-member --org 1 --user alice`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgUser()
+		if err := client.RemoveOrgMember(orgID, orgUser); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var listCollectionsCmd = &cobra.Command{
+	Use:   "collections [flags]",
+	Short: "Show the collections of an organization",
+	Long: `This command shows the collections of an organization. For example:
+	- client org collections --org 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgID()
+		if err := client.ListCollections(orgID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var addCollectionCmd = &cobra.Command{
+	Use:   "add-collection [flags]",
+	Short: "Create a collection in an organization",
+	Long: `This command creates a collection in an organization. For example:
+	- client org add-collection --org 1 --name "Servers"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireOrgID()
+		if orgName == "" {
+			fmt.Println("You must provide the collection name")
+			os.Exit(1)
+		}
+		if err := client.CreateCollection(orgID, orgName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeCollectionCmd = &cobra.Command{
+	Use:   "remove-collection [flags]",
+	Short: "Remove a collection",
+	Long: `This command removes a collection with all its items. For example:
+	- client org remove-collection --collection 2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if collectionID <= 0 {
+			fmt.Println("You must provide a collection ID")
+			os.Exit(1)
+		}
+		if err := client.RemoveCollection(collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	createOrgCmd.PersistentFlags().StringVar(&orgName, "name", "", "organization name")
+	addCollectionCmd.PersistentFlags().StringVar(&orgName, "name", "", "collection name")
+	removeCollectionCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "collection id")
+
+	for _, cmd := range []*cobra.Command{removeOrgCmd, listOrgMembersCmd, addOrgMemberCmd, setOrgRoleCmd,
+		removeOrgMemberCmd, listCollectionsCmd, addCollectionCmd} {
+		cmd.PersistentFlags().Int64Var(&orgID, "org", -1, "organization id")
+	}
+	for _, cmd := range []*cobra.Command{addOrgMemberCmd, setOrgRoleCmd, removeOrgMemberCmd} {
+		cmd.PersistentFlags().StringVar(&orgUser, "user", "", "login of the member")
+	}
+	addOrgMemberCmd.PersistentFlags().StringVar(&orgRole, "role", "viewer", "role: owner, admin, editor or viewer")
+	setOrgRoleCmd.PersistentFlags().StringVar(&orgRole, "role", "", "role: owner, admin, editor or viewer")
+
+	orgCmd.AddCommand(createOrgCmd)
+	orgCmd.AddCommand(listOrgsCmd)
+	orgCmd.AddCommand(removeOrgCmd)
+	orgCmd.AddCommand(listOrgMembersCmd)
+	orgCmd.AddCommand(addOrgMemberCmd)
+	orgCmd.AddCommand(setOrgRoleCmd)
+	orgCmd.AddCommand(removeOrgMemberCmd)
+	orgCmd.AddCommand(listCollectionsCmd)
+	orgCmd.AddCommand(addCollectionCmd)
+	orgCmd.AddCommand(removeCollectionCmd)
+	rootCmd.AddCommand(orgCmd)
+}
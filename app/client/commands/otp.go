@@ -0,0 +1,207 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+var (
+	otpItemID int64
+	otpItem   proto.OTPItem
+	otpURI    string
+)
+
+var otpCmd = &cobra.Command{
+	Use:   "otp [command] [flags]",
+	Short: "TOTP authenticator management",
+	Long: `TOTP authenticator management in GophKeeper: the secrets are stored encrypted and the codes
+are generated locally. For example:
+	- client otp add --uri "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example"
+	- client otp code --id 3
+	- client otp edit --id 3 --desc "Work account"
+	- client otp getAll
+	- client otp remove --id 3`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var addOTPCmd = &cobra.Command{
+	Use:   "add [flags]",
+	Short: "Add a new TOTP authenticator entry to GophKeeper",
+	Long: `This command allows you to add a new TOTP authenticator entry to your account in GophKeeper,
+either from the parameters or from an otpauth:// URI. For example:
+	- client otp add --secret JBSWY3DPEHPK3PXP --issuer Example --account alice --desc "Example 2FA"
+	- client otp add --secret JBSWY3DPEHPK3PXP --algorithm SHA256 --digits 8 --period 60
+	- client otp add --uri "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch {
+		case otpURI != "":
+			err = client.ImportOTPItem(otpURI, otpItem.Description, collectionID)
+		case otpItem.Secret != "":
+			err = client.AddOTPItem(&otpItem, collectionID)
+		default:
+			fmt.Println("You must provide a secret or an otpauth:// URI")
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getOTPCmd = &cobra.Command{
+	Use:   "get [flags]",
+	Short: "Get TOTP authenticator entry by ID from GophKeeper",
+	Long: `This command allows you to get the TOTP authenticator entry with its secret and otpauth:// URI
+by ID from your account in GophKeeper. For example:
+	- client otp get --id 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if otpItemID < 0 {
+			fmt.Println("You must provide an otp item ID")
+			os.Exit(1)
+		}
+		if err := client.GetOTPItem(otpItemID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var codeOTPCmd = &cobra.Command{
+	Use:   "code [flags]",
+	Short: "Print the current code of a TOTP authenticator entry",
+	Long: `This command prints the current code of the TOTP authenticator entry and the number of seconds
+it remains valid. The code is generated locally. For example:
+	- client otp code --id 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if otpItemID < 0 {
+			fmt.Println("You must provide an otp item ID")
+			os.Exit(1)
+		}
+		if err := client.OTPCode(otpItemID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var editOTPCmd = &cobra.Command{
+	Use:   "edit [flags]",
+	Short: "Edit TOTP authenticator entry by ID in GophKeeper",
+	Long: `This command allows you to change the TOTP authenticator entry by ID in your account in GophKeeper keeping its ID.
+Only the fields of the passed flags are changed. For example:
+	- client otp edit --id 3 --account bob@example.com
+	- client otp edit --id 3 --secret JBSWY3DPEHPK3PXP --digits 8`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if otpItemID < 0 {
+			fmt.Println("You must provide an otp item ID")
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		err := client.EditOTPItem(otpItemID, collectionID, func(item *proto.OTPItem) {
+			if flags.Changed("secret") {
+				item.Secret = otpItem.Secret
+			}
+			if flags.Changed("issuer") {
+				item.Issuer = otpItem.Issuer
+			}
+			if flags.Changed("account") {
+				item.Account = otpItem.Account
+			}
+			if flags.Changed("algorithm") {
+				item.Algorithm = otpItem.Algorithm
+			}
+			if flags.Changed("digits") {
+				item.Digits = otpItem.Digits
+			}
+			if flags.Changed("period") {
+				item.Period = otpItem.Period
+			}
+			if flags.Changed("desc") {
+				item.Description = otpItem.Description
+			}
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeOTPCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove TOTP authenticator entry by ID from GophKeeper",
+	Long: `This command allows you to remove the TOTP authenticator entry by ID from your account in GophKeeper. For example:
+	- client otp remove --id 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if otpItemID < 0 {
+			fmt.Println("You must provide an otp item ID")
+			os.Exit(1)
+		}
+		if err := client.RemoveOTPItem(otpItemID, collectionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getAllOTPCmd = &cobra.Command{
+	Use:   "getAll",
+	Short: "Get all TOTP authenticator entries from GophKeeper",
+	Long: `This command allows you to get all TOTP authenticator entries from your account in GophKeeper. For example:
+	- client otp getAll
+	- client otp getAll --folder Work/Servers --tag prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetAllOTPItems(collectionID, itemFilter); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	addOTPCmd.PersistentFlags().StringVar(&otpItem.Secret, "secret", "", "base32-encoded secret")
+	addOTPCmd.PersistentFlags().StringVar(&otpItem.Issuer, "issuer", "", "service issuing the secret")
+	addOTPCmd.PersistentFlags().StringVar(&otpItem.Account, "account", "", "account name")
+	addOTPCmd.PersistentFlags().StringVar(&otpItem.Algorithm, "algorithm", "SHA1", "HMAC algorithm: SHA1, SHA256 or SHA512")
+	addOTPCmd.PersistentFlags().Int32Var(&otpItem.Digits, "digits", 6, "number of digits in a code")
+	addOTPCmd.PersistentFlags().Int32Var(&otpItem.Period, "period", 30, "validity period of a code, in seconds")
+	addOTPCmd.PersistentFlags().StringVar(&otpItem.Description, "desc", "", "otp item description")
+	addOTPCmd.PersistentFlags().StringVar(&otpURI, "uri", "", "otpauth:// URI to import, replaces the other parameters")
+
+	getOTPCmd.PersistentFlags().Int64Var(&otpItemID, "id", -1, "otp item id")
+	codeOTPCmd.PersistentFlags().Int64Var(&otpItemID, "id", -1, "otp item id")
+	removeOTPCmd.PersistentFlags().Int64Var(&otpItemID, "id", -1, "otp item id")
+
+	editOTPCmd.PersistentFlags().Int64Var(&otpItemID, "id", -1, "otp item id")
+	editOTPCmd.PersistentFlags().StringVar(&otpItem.Secret, "secret", "", "new base32-encoded secret")
+	editOTPCmd.PersistentFlags().StringVar(&otpItem.Issuer, "issuer", "", "new issuer")
+	editOTPCmd.PersistentFlags().StringVar(&otpItem.Account, "account", "", "new account name")
+	editOTPCmd.PersistentFlags().StringVar(&otpItem.Algorithm, "algorithm", "SHA1", "new HMAC algorithm: SHA1, SHA256 or SHA512")
+	editOTPCmd.PersistentFlags().Int32Var(&otpItem.Digits, "digits", 6, "new number of digits in a code")
+	editOTPCmd.PersistentFlags().Int32Var(&otpItem.Period, "period", 30, "new validity period of a code, in seconds")
+	editOTPCmd.PersistentFlags().StringVar(&otpItem.Description, "desc", "", "new otp item description")
+
+	addFilterFlags(getAllOTPCmd)
+
+	otpCmd.PersistentFlags().Int64Var(&collectionID, "collection", 0, "organization collection id, the personal otp items if not set")
+
+	otpCmd.AddCommand(addOTPCmd)
+	otpCmd.AddCommand(getOTPCmd)
+	otpCmd.AddCommand(codeOTPCmd)
+	otpCmd.AddCommand(editOTPCmd)
+	otpCmd.AddCommand(removeOTPCmd)
+	otpCmd.AddCommand(getAllOTPCmd)
+	rootCmd.AddCommand(otpCmd)
+}
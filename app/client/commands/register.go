@@ -7,26 +7,47 @@ package commands
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 )
 
 // registerCmd represents the register command
 var registerCmd = &cobra.Command{
-	Use:   "register [login] [password]",
+	Use:   "register [login] [password] [flags]",
 	Short: "Register user",
 	Long: `Register user in GophKeeper. For example:
-	client register login password`,
+	client register login password
+	client register login password --master "correct horse battery staple"
+
+The vault encryption key is derived from the master password, so a new device only needs
+the login, the password and the master password. If the --master flag isn't set, the master
+password is asked interactively, unless the secret_key setting is used to encrypt the data instead.
+The master password can't be recovered, keep it in a safe place.`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.Register(args[0], args[1]); err != nil {
+		if masterPassword == "" && viper.GetString("secret_key") == "" {
+			var err error
+			masterPassword, err = readLine("Enter a master password (leave empty to use secret_key): ")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+		if masterPassword != "" && masterPassword == args[1] {
+			fmt.Println("The master password must differ from the account password")
+			os.Exit(1)
+		}
+		if err := client.Register(args[0], args[1], masterPassword); err != nil {
 			fmt.Println(err)
 		}
 	},
 }
 
 func init() {
+	registerCmd.PersistentFlags().StringVar(&masterPassword, "master", "", "master password to derive the vault encryption key from")
 	rootCmd.AddCommand(registerCmd)
 }
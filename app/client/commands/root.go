@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
 )
 
 const DefaultConfigPath = "./cfgclient.yaml"
@@ -25,7 +27,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFilePath, "config", "", "client config file")
 	rootCmd.PersistentFlags().StringVar(&hashKey, "hash_key", "", "key for calculate request data hash")
-	rootCmd.PersistentFlags().StringVar(&secretKey, "secret_key", "", "key to encrypt data in database")
+	rootCmd.PersistentFlags().StringVar(&secretKey, "secret_key", "", "key to encrypt data in database (derived from the master password if not set)")
 }
 
 func initConfig() {
@@ -64,12 +66,22 @@ func initConfig() {
 	} else {
 		secretKey = viper.GetString("secret_key")
 		if secretKey == "" {
-			fmt.Println("You must provide the secret_key flag or set it in the config file, see --help")
-			panic("You must provide the secret_key flag or set it in the config file, see --help")
+			// Accounts with a master password use the vault key unlocked by the auth command.
+			if key, err := client.LoadVaultKey(); err == nil {
+				viper.Set("secret_key", key)
+			}
 		}
 	}
 }
 
+// requireSecretKey stops a command that encrypts or decrypts user data if there is no key to do it.
+func requireSecretKey(cmd *cobra.Command, args []string) {
+	if viper.GetString("secret_key") == "" {
+		fmt.Println("The vault is locked: authorize with the master password or provide the secret_key flag, see --help")
+		os.Exit(1)
+	}
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "client",
 	Short: "GophKeeper client application",
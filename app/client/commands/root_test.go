@@ -68,7 +68,7 @@ func TestInitConfig_MissingHashKey(t *testing.T) {
 	}, "Expected panic when hash_key is missing")
 }
 
-func TestInitConfig_MissingSecretKey(t *testing.T) {
+func TestInitConfig_MissingSecretKeyUsesVault(t *testing.T) {
 	viper.Reset()
 	secretKey = ""
 	hashKey = "test_hash_key"
@@ -83,5 +83,5 @@ func TestInitConfig_MissingSecretKey(t *testing.T) {
 
 	cfgFilePath = tmpFile.Name()
 
-	assert.Panics(t, func() { initConfig() }, "Expected panic when secret_key is missing")
+	assert.NotPanics(t, func() { initConfig() }, "secret_key may be derived from the master password")
 }
@@ -0,0 +1,50 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var (
+	searchTypes    []string
+	searchLimit    int
+	searchUseIndex bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query> [flags]",
+	Short: "Search your items",
+	Long: `This command decrypts your personal items and searches them for the query. The terms of the query
+are matched fuzzily against the descriptions, logins, card owners, note texts, file names, OTP issuers
+and accounts, non-secret fields of custom items, folders and tags, an item must match all the terms.
+Passwords and other secrets are never searched. The results are ranked, the best matches first.
+With --index the decrypted items are cached in a local index encrypted with your secret key, so repeated
+searches only decrypt the new and changed items. For example:
+	- client search github
+	- client search "home router" --type note --type credentials
+	- client search prod --index --limit 10`,
+	Args:             cobra.MinimumNArgs(1),
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := client.SearchOptions{Types: searchTypes, Limit: searchLimit, UseIndex: searchUseIndex}
+		if err := client.Search(strings.Join(args, " "), opts); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	searchCmd.PersistentFlags().StringArrayVar(&searchTypes, "type", nil, "item type to search: card, credentials, note, file, otp or custom, repeat for every type")
+	searchCmd.PersistentFlags().IntVar(&searchLimit, "limit", 0, "maximum number of results, 0 for all")
+	searchCmd.PersistentFlags().BoolVar(&searchUseIndex, "index", false, "use the local encrypted search index")
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var sessionID int64
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions [command] [flags]",
+	Short: "Active sessions management",
+	Long: `Active sessions (logged in devices) management in GophKeeper. For example:
+	- client sessions list
+	- client sessions revoke --id 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var listSessionsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show all active sessions",
+	Long: `This command shows all active sessions of your account in GophKeeper with the device name,
+client version, address, creation and last-seen time. For example:
+	- client sessions list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.ListSessions(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var revokeSessionCmd = &cobra.Command{
+	Use:   "revoke [flags]",
+	Short: "Revoke session by ID",
+	Long: `This command revokes a session of your account in GophKeeper, for example the one of a lost device.
+The device will have to authorize again. For example:
+	- client sessions revoke --id 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if sessionID < 0 {
+			fmt.Println("You must provide a session ID")
+			os.Exit(1)
+		}
+		if err := client.RevokeSession(sessionID); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	revokeSessionCmd.PersistentFlags().Int64Var(&sessionID, "id", -1, "session id")
+
+	sessionsCmd.AddCommand(listSessionsCmd)
+	sessionsCmd.AddCommand(revokeSessionCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
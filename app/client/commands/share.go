@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var (
+	shareItemType string
+	shareItemID   int64
+	shareUser     string
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share [command] [flags]",
+	Short: "Share items with other users",
+	Long: `Share bank cards, credentials and notes with other GophKeeper users. For example:
+	- client share add --type card --id 1 --user alice
+	- client share list
+	- client share revoke --type card --id 1 --user alice`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+// checkShareFlags stops a command if the item or the user to share it with isn't provided.
+func checkShareFlags() {
+	if shareItemType == "" || shareItemID < 0 || shareUser == "" {
+		fmt.Println("You must provide the item type, the item ID and the user")
+		os.Exit(1)
+	}
+}
+
+var addShareCmd = &cobra.Command{
+	Use:   "add [flags]",
+	Short: "Share an item with a user",
+	Long: `This command gives another user access to your bank card, credentials or note. The item is encrypted
+so that only you and the users you have shared it with can decrypt it. The user must have run
+the share list command at least once. For example:
+	- client share add --type credentials --id 3 --user alice`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkShareFlags()
+		if err := client.ShareItem(shareItemType, shareItemID, shareUser); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var listSharesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the items shared with you",
+	Long: `This command shows the bank cards, credentials and notes other users have shared with you.
+For example:
+	- client share list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.ListSharedWithMe(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var revokeShareCmd = &cobra.Command{
+	Use:   "revoke [flags]",
+	Short: "Revoke the access of a user to an item",
+	Long: `This command revokes the access of another user to your bank card, credentials or note.
+The item is re-encrypted with a new key for the remaining users. For example:
+	- client share revoke --type credentials --id 3 --user alice`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkShareFlags()
+		if err := client.RevokeShare(shareItemType, shareItemID, shareUser); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{addShareCmd, revokeShareCmd} {
+		cmd.PersistentFlags().StringVar(&shareItemType, "type", "", "item type: card, credentials or note")
+		cmd.PersistentFlags().Int64Var(&shareItemID, "id", -1, "item id")
+		cmd.PersistentFlags().StringVar(&shareUser, "user", "", "login of the user")
+	}
+
+	shareCmd.AddCommand(addShareCmd)
+	shareCmd.AddCommand(listSharesCmd)
+	shareCmd.AddCommand(revokeShareCmd)
+	rootCmd.AddCommand(shareCmd)
+}
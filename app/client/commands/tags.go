@@ -0,0 +1,134 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var (
+	tagName    string
+	newTagName string
+)
+
+// checkTagName stops a command if the tag name isn't provided.
+func checkTagName() {
+	if tagName == "" {
+		fmt.Println("You must provide the tag name")
+		os.Exit(1)
+	}
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags [command] [flags]",
+	Short: "Tags management",
+	Long: `Free-form tags of your personal items in GophKeeper, the names of the tags are encrypted
+with your secret key. For example:
+	- client tags add --type credentials --id 3 --tag prod
+	- client tags getAll
+	- client credentials getAll --tag prod`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var addTagCmd = &cobra.Command{
+	Use:   "add [flags]",
+	Short: "Tag an item",
+	Long: `This command tags your bank card, credentials, note, file, otp item or custom item,
+the tag is created if it doesn't exist yet. For example:
+	- client tags add --type note --id 2 --tag personal`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLabelItemFlags()
+		checkTagName()
+		if err := client.TagItem(labelItemType, labelItemID, tagName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeTagCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove a tag from an item",
+	Long: `This command removes a tag from an item, the tag itself is kept. For example:
+	- client tags remove --type note --id 2 --tag personal`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLabelItemFlags()
+		checkTagName()
+		if err := client.UntagItem(labelItemType, labelItemID, tagName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var renameTagCmd = &cobra.Command{
+	Use:   "rename [flags]",
+	Short: "Rename a tag",
+	Long: `This command changes the name of a tag. For example:
+	- client tags rename --tag prod --name production`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTagName()
+		if newTagName == "" {
+			fmt.Println("You must provide the new tag name")
+			os.Exit(1)
+		}
+		if err := client.RenameTag(tagName, newTagName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var deleteTagCmd = &cobra.Command{
+	Use:   "delete [flags]",
+	Short: "Delete a tag",
+	Long: `This command removes a tag from all the items and deletes it. For example:
+	- client tags delete --tag prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTagName()
+		if err := client.RemoveTag(tagName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getAllTagsCmd = &cobra.Command{
+	Use:   "getAll",
+	Short: "Show the tags",
+	Long: `This command shows your tags with the number of the items tagged with every tag. For example:
+	- client tags getAll`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetAllTags(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{addTagCmd, removeTagCmd} {
+		cmd.PersistentFlags().StringVar(&labelItemType, "type", "", "item type: card, credentials, note, file, otp or custom")
+		cmd.PersistentFlags().Int64Var(&labelItemID, "id", -1, "item id")
+	}
+	for _, cmd := range []*cobra.Command{addTagCmd, removeTagCmd, renameTagCmd, deleteTagCmd} {
+		cmd.PersistentFlags().StringVar(&tagName, "tag", "", "tag name")
+	}
+	renameTagCmd.PersistentFlags().StringVar(&newTagName, "name", "", "new tag name")
+
+	tagsCmd.AddCommand(addTagCmd)
+	tagsCmd.AddCommand(removeTagCmd)
+	tagsCmd.AddCommand(renameTagCmd)
+	tagsCmd.AddCommand(deleteTagCmd)
+	tagsCmd.AddCommand(getAllTagsCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
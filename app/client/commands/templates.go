@@ -0,0 +1,109 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+var (
+	templateName   string
+	templateDesc   string
+	templateFields []string
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates [command] [flags]",
+	Short: "Custom item types management",
+	Long: `Custom item types management in GophKeeper: a template is a list of named fields of a kind,
+the custom items are added against the templates. For example:
+	- client templates getAll
+	- client templates add --name db-account --field host:text --field password:secret
+	- client templates remove --name db-account`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var addTemplateCmd = &cobra.Command{
+	Use:   "add [flags]",
+	Short: "Define a new custom item type in GophKeeper",
+	Long: `This command allows you to define a new custom item type in your account in GophKeeper.
+Every --field flag adds a field in the name:kind form, the kind is one of ` + strings.Join(itemtype.Kinds, ", ") + `.
+The fields keep the order of the flags. For example:
+	- client templates add --name db-account --desc "Database account" --field host:text --field url:url --field password:secret`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if templateName == "" || len(templateFields) == 0 {
+			fmt.Println("You must provide a template name and at least one field")
+			os.Exit(1)
+		}
+		template := &proto.ItemTemplate{Name: templateName, Description: templateDesc}
+		for _, spec := range templateFields {
+			name, kind, found := strings.Cut(spec, ":")
+			if !found {
+				fmt.Printf("Invalid field %q, use the name:kind form\n", spec)
+				os.Exit(1)
+			}
+			template.Fields = append(template.Fields, &proto.TemplateField{Name: name, Kind: kind})
+		}
+		if err := client.AddItemTemplate(template); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var getAllTemplatesCmd = &cobra.Command{
+	Use:   "getAll",
+	Short: "Get all custom item types from GophKeeper",
+	Long: `This command allows you to get the built-in item templates and your own templates with their fields. For example:
+	- client templates getAll`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.GetItemTemplates(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var removeTemplateCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove custom item type by name from GophKeeper",
+	Long: `This command allows you to remove your item template by name from GophKeeper,
+the items added against the template are kept. For example:
+	- client templates remove --name db-account`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if templateName == "" {
+			fmt.Println("You must provide a template name")
+			os.Exit(1)
+		}
+		if err := client.RemoveItemTemplate(templateName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	addTemplateCmd.PersistentFlags().StringVar(&templateName, "name", "", "template name")
+	addTemplateCmd.PersistentFlags().StringVar(&templateDesc, "desc", "", "template description")
+	addTemplateCmd.PersistentFlags().StringArrayVar(&templateFields, "field", nil, "template field in the name:kind form, repeat for every field")
+
+	removeTemplateCmd.PersistentFlags().StringVar(&templateName, "name", "", "template name")
+
+	templatesCmd.AddCommand(addTemplateCmd)
+	templatesCmd.AddCommand(getAllTemplatesCmd)
+	templatesCmd.AddCommand(removeTemplateCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
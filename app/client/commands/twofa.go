@@ -0,0 +1,105 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var secondFactorCode string
+
+var twoFACmd = &cobra.Command{
+	Use:   "2fa [command] [flags]",
+	Short: "Two-factor authentication management",
+	Long: `Two-factor authentication (TOTP) management in GophKeeper. For example:
+	- client 2fa enable
+	- client 2fa disable --code 123456
+	- client 2fa recovery-codes --code 123456`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var enableTwoFACmd = &cobra.Command{
+	Use:   "enable [flags]",
+	Short: "Enable two-factor authentication",
+	Long: `This command enrolls a new TOTP secret for your account and prints it together with an otpauth:// URI
+for your authenticator app. Then the enrollment is confirmed with the current code from the app
+and the recovery codes are printed. For example:
+	- client 2fa enable
+	- client 2fa enable --code 123456 (confirm an already printed secret)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		code := secondFactorCode
+		if code == "" {
+			if err := client.EnrollTOTP(); err != nil {
+				fmt.Println(err)
+				return
+			}
+			var err error
+			code, err = readLine("Enter the code from your authenticator app: ")
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		if err := client.ConfirmTOTP(code); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var disableTwoFACmd = &cobra.Command{
+	Use:   "disable [flags]",
+	Short: "Disable two-factor authentication",
+	Long: `This command disables two-factor authentication for your account in GophKeeper. For example:
+	- client 2fa disable --code 123456`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if secondFactorCode == "" {
+			fmt.Println("You must provide a TOTP code or a recovery code")
+			os.Exit(1)
+		}
+		if err := client.DisableTOTP(secondFactorCode); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var recoveryCodesCmd = &cobra.Command{
+	Use:   "recovery-codes [flags]",
+	Short: "Regenerate recovery codes",
+	Long: `This command replaces all your recovery codes with a new set. For example:
+	- client 2fa recovery-codes --code 123456`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if secondFactorCode == "" {
+			fmt.Println("You must provide a TOTP code or a recovery code")
+			os.Exit(1)
+		}
+		if err := client.RegenerateRecoveryCodes(secondFactorCode); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	enableTwoFACmd.PersistentFlags().StringVar(&secondFactorCode, "code", "", "TOTP code from the authenticator app")
+
+	disableTwoFACmd.PersistentFlags().StringVar(&secondFactorCode, "code", "", "TOTP code or recovery code")
+
+	recoveryCodesCmd.PersistentFlags().StringVar(&secondFactorCode, "code", "", "TOTP code or recovery code")
+
+	twoFACmd.AddCommand(enableTwoFACmd)
+	twoFACmd.AddCommand(disableTwoFACmd)
+	twoFACmd.AddCommand(recoveryCodesCmd)
+	rootCmd.AddCommand(twoFACmd)
+}
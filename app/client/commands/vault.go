@@ -0,0 +1,74 @@
+/*
+Copyright © 2024 MIKHAIL SIRKIN <skim991@gmail.com>
+*/
+
+// Package cmd contains the commands for the GophKeeper client application.
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+)
+
+var newSecretKey string
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault [command] [flags]",
+	Short: "Vault key management",
+	Long: `Vault encryption key management in GophKeeper. For example:
+	- client vault rekey --master "new correct horse battery staple"
+	- client vault rekey --new_secret_key examplekey1234567890123456789012`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var rekeyVaultCmd = &cobra.Command{
+	Use:   "rekey [flags]",
+	Short: "Re-encrypt all data under a new key",
+	Long: `This command changes the master password (or the secret key) and re-encrypts all your bank cards,
+notes, credentials and files under the new key. The data is replaced on the server at once, so either
+everything is re-encrypted or nothing. For example:
+	- client vault rekey --master "new correct horse battery staple"
+	- client vault rekey --new_secret_key examplekey1234567890123456789012
+
+If the rekey is interrupted, repeat the command with the same new master password or secret key
+to resume it. With --new_secret_key, set secret_key in the configuration to the new key afterwards.`,
+	PersistentPreRun: requireSecretKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		if masterPassword != "" && newSecretKey != "" {
+			fmt.Println("Provide either a new master password or a new secret key")
+			os.Exit(1)
+		}
+		if masterPassword == "" && newSecretKey == "" {
+			var err error
+			masterPassword, err = readLine("Enter a new master password: ")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if masterPassword == "" {
+				fmt.Println("You must provide a new master password or a new secret key")
+				os.Exit(1)
+			}
+		}
+		if err := client.RekeyVault(masterPassword, newSecretKey); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rekeyVaultCmd.PersistentFlags().StringVar(&masterPassword, "master", "", "new master password to derive the vault encryption key from")
+	rekeyVaultCmd.PersistentFlags().StringVar(&newSecretKey, "new_secret_key", "", "new key to encrypt data with instead of a master password")
+
+	vaultCmd.AddCommand(rekeyVaultCmd)
+	rootCmd.AddCommand(vaultCmd)
+}
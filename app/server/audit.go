@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/srvconfig"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+)
+
+// VerifyAuditLog checks the hash chain of the whole audit log. It is meant to run as a maintenance
+// command while the server keeps serving requests.
+//
+// Parameters:
+//   - cfg: A pointer to the ServerConfig struct containing the database DSN.
+//
+// Returns:
+//   - An error if the verification fails or an event of the log has been edited or deleted.
+//
+// The hash of the last event is logged, keeping it elsewhere allows to detect the removal of the
+// latest events on the next verification too.
+func VerifyAuditLog(cfg *config.ServerConfig) error {
+	if err := logger.Initialize(cfg.LogLevel); err != nil {
+		return err
+	}
+	repo, err := storage.NewPostgresStorage(cfg.DatabaseDSN)
+	if err != nil {
+		logger.Log.Error("error init postgres storage", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Log.Error("error closing storage before exit", zap.Error(err))
+		}
+	}()
+
+	verified, lastHash, err := repo.VerifyAuditLog(context.Background())
+	if err != nil {
+		logger.Log.Error("error verify audit log", zap.Int("verified", verified), zap.String("lastHash", lastHash), zap.Error(err))
+		return err
+	}
+	logger.Log.Info("audit log verified", zap.Int("verified", verified), zap.String("lastHash", lastHash))
+	return nil
+}
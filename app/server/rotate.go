@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/srvconfig"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+)
+
+// RotateDatabaseKey re-encrypts all the secrets stored in the database under the active database key
+// (see the -db-key-id flag). It is meant to run as a maintenance command while the server, already
+// configured with the new active key, keeps serving requests.
+//
+// Parameters:
+//   - cfg: A pointer to the ServerConfig struct containing the database DSN and the database keys.
+//
+// Returns:
+//   - An error if the rotation fails or some values could not be re-encrypted. The rotation can be repeated.
+func RotateDatabaseKey(cfg *config.ServerConfig) error {
+	if err := logger.Initialize(cfg.LogLevel); err != nil {
+		return err
+	}
+	keys, err := cfg.Keyring()
+	if err != nil {
+		return err
+	}
+	repo, err := storage.NewPostgresStorage(cfg.DatabaseDSN)
+	if err != nil {
+		logger.Log.Error("error init postgres storage", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Log.Error("error closing storage before exit", zap.Error(err))
+		}
+	}()
+
+	logger.Log.Info("rotating database key", zap.String("keyID", keys.ActiveID()))
+	rotated, err := storage.RotateDatabaseKey(context.Background(), repo, keys)
+	if err != nil {
+		logger.Log.Error("error rotate database key", zap.Int("rotated", rotated), zap.Error(err))
+		return err
+	}
+	logger.Log.Info("database key rotation completed", zap.Int("rotated", rotated))
+	return nil
+}
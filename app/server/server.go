@@ -5,7 +5,10 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"net"
 	"os"
 	"os/signal"
@@ -13,12 +16,14 @@ import (
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/Vidkin/gophkeeper/internal/handlers"
 	"github.com/Vidkin/gophkeeper/internal/logger"
 	"github.com/Vidkin/gophkeeper/internal/srvconfig"
 	"github.com/Vidkin/gophkeeper/internal/storage"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -32,12 +37,14 @@ type ServerApp struct {
 	gRPCServer *grpc.Server
 	listener   net.Listener
 	storage    *storage.PostgresStorage
+	jwtKeys    *jwt.KeySet
 }
 
 // NewServerApp creates and returns a new instance of the ServerApp initialized with the provided
 // configuration. It sets up logging, initializes storage connections (PostgreSQL and MinIO),
-// configures gRPC server with interceptors for logging, hashing, and token validation,
-// and prepares a TLS listener.
+// configures gRPC server with TLS credentials (requiring client certificates in the mutual
+// TLS mode) and unary and stream interceptors for logging, audit, request signatures, and token validation, and
+// prepares a listener.
 //
 // Parameters:
 //   - cfg: A pointer to the ServerConfig struct containing all necessary server configurations.
@@ -49,6 +56,14 @@ func NewServerApp(cfg *config.ServerConfig) (*ServerApp, error) {
 	if err := logger.Initialize(cfg.LogLevel); err != nil {
 		return nil, err
 	}
+	keys, err := cfg.Keyring()
+	if err != nil {
+		return nil, err
+	}
+	jwtKeys, err := cfg.JWTKeys()
+	if err != nil {
+		return nil, err
+	}
 	repo, err := storage.NewPostgresStorage(cfg.DatabaseDSN)
 	if err != nil {
 		logger.Log.Error("error init postgres storage", zap.Error(err))
@@ -60,23 +75,43 @@ func NewServerApp(cfg *config.ServerConfig) (*ServerApp, error) {
 		logger.Log.Error("error init minio storage", zap.Error(err))
 		return nil, err
 	}
+	if err = storage.MigrateLegacyObjects(context.Background(), repo, minioClient); err != nil {
+		logger.Log.Error("error migrate legacy file objects", zap.Error(err))
+	}
+	tlsConfig, err := GetTLSConfig(cfg.CryptoKeyPublic, cfg.CryptoKeyPrivate, cfg.ClientCA)
+	if err != nil {
+		logger.Log.Error("error load TLS certificates", zap.Error(err))
+		return nil, err
+	}
 	gRPCServer := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
 		grpc.ChainUnaryInterceptor(
+			interceptors.ClientCertificate,
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor(cfg.Key),
-			interceptors.ValidateToken(cfg.JWTKey),
+			interceptors.Audit(repo),
+			interceptors.SignatureInterceptor(cfg.Key, cfg.SignatureSkew),
+			interceptors.RateLimit(repo, cfg.RateLimits()),
+			interceptors.ValidateToken(jwtKeys, repo),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptors.ClientCertificateStream,
+			interceptors.LoggingStreamInterceptor,
+			interceptors.AuditStream(repo),
+			interceptors.SignatureStreamInterceptor(cfg.Key, cfg.SignatureSkew),
+			interceptors.ValidateTokenStream(jwtKeys, repo),
 		),
 	)
 	proto.RegisterGophkeeperServer(gRPCServer, &handlers.GophkeeperServer{
-		RetryCount:  cfg.RetryCount,
-		Storage:     repo,
-		Minio:       minioClient,
-		DatabaseKey: cfg.DatabaseKey,
-		JWTKey:      cfg.JWTKey,
+		RetryCount:     cfg.RetryCount,
+		Storage:        repo,
+		Minio:          minioClient,
+		PasswordParams: cfg.PasswordParams(),
+		Keyring:        keys,
+		JWTKeys:        jwtKeys,
 	})
-	listener, err := GetTLSListener(cfg.ServerAddress.Address, cfg.CryptoKeyPublic, cfg.CryptoKeyPrivate)
+	listener, err := net.Listen("tcp", cfg.ServerAddress.Address)
 	if err != nil {
-		logger.Log.Fatal("failed to create TLS listener", zap.Error(err))
+		logger.Log.Fatal("failed to create listener", zap.Error(err))
 		return nil, err
 	}
 	return &ServerApp{
@@ -84,6 +119,7 @@ func NewServerApp(cfg *config.ServerConfig) (*ServerApp, error) {
 		gRPCServer: gRPCServer,
 		listener:   listener,
 		storage:    repo,
+		jwtKeys:    jwtKeys,
 	}, nil
 }
 
@@ -98,6 +134,25 @@ func NewServerApp(cfg *config.ServerConfig) (*ServerApp, error) {
 //   - A net.Listener instance configured for TLS.
 //   - An error if the listener creation fails.
 func GetTLSListener(addr, certFile, keyFile string) (net.Listener, error) {
+	cfg, err := GetTLSConfig(certFile, keyFile, "")
+	if err != nil {
+		return nil, err
+	}
+	return tls.Listen("tcp", addr, cfg)
+}
+
+// GetTLSConfig creates the server TLS configuration using provided public and private keys.
+//
+// Parameters:
+//   - certFile: Path to the certificate file.
+//   - keyFile: Path to the private key file.
+//   - clientCAFile: Path to the CA certificate file. If not empty, enables the mutual TLS mode:
+//     clients must present a certificate signed by this CA.
+//
+// Returns:
+//   - A tls.Config instance.
+//   - An error if the certificates could not be loaded.
+func GetTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
 	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	if err != nil {
 		return nil, err
@@ -105,11 +160,24 @@ func GetTLSListener(addr, certFile, keyFile string) (net.Listener, error) {
 	cfg := &tls.Config{
 		Certificates: []tls.Certificate{cert},
 		NextProtos:   []string{"h2"}}
-	return tls.Listen("tcp", addr, cfg)
+	if clientCAFile != "" {
+		caCert, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(caCert); !ok {
+			return nil, errors.New("error add client CA cert into the pool")
+		}
+		cfg.ClientCAs = certPool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
 }
 
 // Run starts the gRPC server on the configured listener and handles graceful shutdown.
-// It listens for OS signals to trigger a graceful stop of the server.
+// It listens for OS signals to trigger a graceful stop of the server, SIGHUP reloads the JWT keys
+// from their files, so they can be rotated without a restart.
 func (s *ServerApp) Run() {
 	logger.Log.Info("running server", zap.String("address", s.config.ServerAddress.Address))
 	go func() {
@@ -117,10 +185,31 @@ func (s *ServerApp) Run() {
 			logger.Log.Fatal("failed to serve", zap.Error(err))
 		}
 	}()
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-	<-quit
-	s.Stop()
+	for {
+		select {
+		case <-reload:
+			s.ReloadJWTKeys()
+		case <-quit:
+			s.Stop()
+			return
+		}
+	}
+}
+
+// ReloadJWTKeys loads the JWT keys from their files again and replaces the keys in use,
+// the keys in use are kept if the new ones could not be loaded.
+func (s *ServerApp) ReloadJWTKeys() {
+	keys, err := s.config.JWTKeys()
+	if err != nil {
+		logger.Log.Error("error reload JWT keys", zap.Error(err))
+		return
+	}
+	s.jwtKeys.Update(keys)
+	logger.Log.Info("JWT keys reloaded", zap.String("signingKeyID", keys.SigningKeyID()))
 }
 
 // Stop gracefully shuts down the gRPC server and closes the storage connection.
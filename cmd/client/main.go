@@ -12,6 +12,7 @@ import (
 	"fmt"
 
 	"github.com/Vidkin/gophkeeper/app/client/commands"
+	"github.com/Vidkin/gophkeeper/internal/client"
 )
 
 // buildVersion holds the build version of the application.
@@ -29,5 +30,6 @@ var buildDate = "N/A"
 //     which handles all user commands and interactions.
 func main() {
 	fmt.Printf("Build version: %s\nBuild date: %s\n---------------\n", buildVersion, buildDate)
+	client.Version = buildVersion
 	commands.Execute()
 }
@@ -9,6 +9,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/srvconfig"
@@ -22,18 +23,47 @@ var buildVersion = "N/A"
 // It is set during the build process and defaults to "N/A" if not specified.
 var buildDate = "N/A"
 
+// Maintenance commands run instead of the server.
+const (
+	// rotateDBKeyCommand re-encrypts the database secrets under the active database key.
+	rotateDBKeyCommand = "rotate-db-key"
+	// verifyAuditLogCommand checks the hash chain of the audit log.
+	verifyAuditLogCommand = "verify-audit-log"
+)
+
 // main serves as the entry point for the GophKeeper server. It performs the following tasks:
 //
 // 1. Prints the build version and build date of the application.
 // 2. Loads the server configuration using config.NewServerConfig().
 // 3. Initializes the server application using app.NewServerApp(cfg).
 // 4. Starts the server by calling serverApp.Run().
+//
+// If the first argument is "rotate-db-key" or "verify-audit-log", the database key rotation or the audit
+// log verification runs instead of the server.
 func main() {
 	fmt.Printf("Build version: %s\nBuild date: %s\n---------------\n", buildVersion, buildDate)
+	var command string
+	if len(os.Args) > 1 && (os.Args[1] == rotateDBKeyCommand || os.Args[1] == verifyAuditLogCommand) {
+		command = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
 	cfg, err := config.NewServerConfig()
 	if err != nil {
 		panic(err)
 	}
+	switch command {
+	case rotateDBKeyCommand:
+		err = server.RotateDatabaseKey(cfg)
+	case verifyAuditLogCommand:
+		err = server.VerifyAuditLog(cfg)
+	}
+	if command != "" {
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
 	serverApp, err := server.NewServerApp(cfg)
 	if err != nil {
 		panic(err)
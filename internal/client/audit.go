@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetAuditLog retrieves the audit log of the user from the GophKeeper server and prints it.
+//
+// Parameters:
+//   - from: The start of the time range in the "2006-01-02" or "2006-01-02 15:04:05" format (UTC), empty for no start.
+//   - to: The end of the time range in the same format, empty for no end.
+//   - itemType: The type of the items, e.g. "card" or "file", empty for all the types.
+//   - limit: The maximum number of the events, 0 for the server default.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func GetAuditLog(from, to, itemType string, limit int32) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.GetAuditLogRequest{
+		From:     from,
+		To:       to,
+		ItemType: itemType,
+		Limit:    limit,
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.GetAuditLog(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	for _, e := range resp.Events {
+		fmt.Printf("%s, method=%s, type=%s, item=%s, session=%d, address=%s, outcome=%s",
+			e.CreatedAt, path.Base(e.Method), e.ItemType, e.ItemId, e.SessionId, e.PeerAddress, e.Outcome)
+		if e.ClientSubject != "" {
+			fmt.Printf(", certificate=%s", e.ClientSubject)
+		}
+		fmt.Println()
+	}
+	return err
+}
@@ -2,31 +2,40 @@ package client
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
-	"path"
 	"time"
 
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
-	pb "google.golang.org/protobuf/proto"
 
-	"github.com/Vidkin/gophkeeper/pkg/hash"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
+// Version is the build version of the client application, which is reported to the server
+// and shown in the sessions list.
+var Version = "N/A"
+
+// ErrSecondFactorRequired is returned by Auth when the user has enabled two-factor authentication
+// and no TOTP or recovery code has been provided.
+var ErrSecondFactorRequired = errors.New("second factor required: provide a code from your authenticator app or a recovery code")
+
 // Auth authenticates a user with the GophKeeper server using the provided login and password.
 //
+// If the user has a master password vault, its parameters are stored as well and the vault
+// must be unlocked with UnlockVault before the user data can be encrypted or decrypted.
+//
 // Parameters:
 //   - login: The user's login name.
 //   - password: The user's password.
+//   - otpCode: A TOTP or recovery code, required only if the user has enabled two-factor authentication.
 //
 // Returns:
+//   - ErrSecondFactorRequired if the server requested a second factor and otpCode is empty.
 //   - An error if any step in the process fails, including gRPC connection issues,
-//     marshaling errors, or file operations related to the JWT token.
-func Auth(login, password string) error {
+//     marshaling errors, or file operations related to the JWT and refresh tokens.
+func Auth(login, password, otpCode string) error {
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
 		return err
@@ -43,50 +52,43 @@ func Auth(login, password string) error {
 		Password: password,
 	}
 	req := &proto.AuthorizeRequest{
-		Credentials: &cred,
+		Credentials:   &cred,
+		OtpCode:       otpCode,
+		DeviceName:    deviceName(),
+		ClientVersion: Version,
 	}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md := metadata.New(map[string]string{"HashSHA256": hEnc})
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.Authorize(ctxTimeout, req)
 	if err != nil {
 		return err
 	}
-
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return err
-		}
+	if resp.SecondFactorRequired {
+		return ErrSecondFactorRequired
 	}
 
-	f, err := os.Create(path.Join(os.TempDir(), TokenFileName))
-	if err != nil {
+	if err = saveTokens(resp.Token, resp.RefreshToken); err != nil {
 		return err
 	}
-	defer func(f *os.File) {
-		err = f.Close()
-		if err != nil {
-			fmt.Println("failed to close jwt temp file")
-		}
-	}(f)
-
-	if _, err = f.WriteString(resp.Token); err != nil {
+	if err = saveVault(resp.Vault); err != nil {
 		return err
 	}
 
 	fmt.Println("Successfully authorized!")
 	return nil
 }
+
+// deviceName returns the device name reported to the server: the "device_name" setting if it is set,
+// or the host name otherwise.
+func deviceName() string {
+	if name := viper.GetString("device_name"); name != "" {
+		return name
+	}
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
@@ -2,8 +2,8 @@ package client
 
 import (
 	"os"
-	"path"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +13,7 @@ import (
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/handlers"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -29,8 +30,8 @@ func TestAuth(t *testing.T) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor("defaultHashKey"),
-			interceptors.ValidateToken("JWTKey")))
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := server.GetTLSListener(
@@ -48,29 +49,107 @@ func TestAuth(t *testing.T) {
 	viper.Set("crypto_key_public_path", "../../certs/public.crt")
 	viper.Set("hash_key", "defaultHashKey")
 
-	err = Register("test_login", "test_pass")
+	err = Register("test_login", "test_pass", "")
 	require.NoError(t, err)
 
 	t.Run("test bad login", func(t *testing.T) {
-		err = Auth("test_loginBad", "test_pass")
+		err = Auth("test_loginBad", "test_pass", "")
 		require.ErrorContains(t, err, "invalid user login or password")
 	})
 
 	t.Run("test bad password", func(t *testing.T) {
-		err = Auth("test_login", "test_passBad")
+		err = Auth("test_login", "test_passBad", "")
 		require.ErrorContains(t, err, "invalid user login or password")
 	})
 
 	t.Run("test auth ok", func(t *testing.T) {
-		err = Auth("test_login", "test_pass")
+		err = Auth("test_login", "test_pass", "")
 		require.NoError(t, err)
 	})
 
-	tokenFilePath := path.Join(os.TempDir(), TokenFileName)
+	tokenFilePath := stateFilePath(t, TokenFileName)
+	refreshTokenFilePath := stateFilePath(t, RefreshTokenFileName)
 	defer os.Remove(tokenFilePath)
-	t.Run("check token file exists", func(t *testing.T) {
+	defer os.Remove(refreshTokenFilePath)
+	t.Run("check token files exist", func(t *testing.T) {
 		content, err := os.ReadFile(tokenFilePath)
 		require.NoError(t, err)
 		assert.NotEmpty(t, string(content))
+
+		content, err = os.ReadFile(refreshTokenFilePath)
+		require.NoError(t, err)
+		assert.NotEmpty(t, string(content))
+	})
+
+	t.Run("test expired token is refreshed", func(t *testing.T) {
+		oldRefreshToken, err := os.ReadFile(refreshTokenFilePath)
+		require.NoError(t, err)
+		err = os.WriteFile(tokenFilePath, []byte(expiredToken), 0600)
+		require.NoError(t, err)
+
+		token, err := loadToken()
+		require.NoError(t, err)
+		assert.NotEqual(t, expiredToken, token)
+
+		newRefreshToken, err := os.ReadFile(refreshTokenFilePath)
+		require.NoError(t, err)
+		assert.NotEqual(t, oldRefreshToken, newRefreshToken)
+	})
+
+	t.Run("test logout", func(t *testing.T) {
+		refreshToken, err := os.ReadFile(refreshTokenFilePath)
+		require.NoError(t, err)
+
+		err = Logout()
+		require.NoError(t, err)
+
+		_, err = os.Stat(tokenFilePath)
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(refreshTokenFilePath)
+		assert.True(t, os.IsNotExist(err))
+
+		_, err = refreshTokens(string(refreshToken))
+		require.ErrorContains(t, err, "invalid refresh token")
+	})
+
+	t.Run("test master password vault", func(t *testing.T) {
+		err = Register("vault_login", "vault_pass", "master_pass")
+		require.NoError(t, err)
+
+		err = Auth("vault_login", "vault_pass", "")
+		require.NoError(t, err)
+		require.True(t, HasVault())
+
+		_, err = LoadVaultKey()
+		require.Error(t, err, "the vault must stay locked until the master password is provided")
+
+		err = UnlockVault("wrong_master_pass")
+		require.ErrorIs(t, err, ErrWrongMasterPassword)
+
+		err = UnlockVault("master_pass")
+		require.NoError(t, err)
+		key, err := LoadVaultKey()
+		require.NoError(t, err)
+		assert.Len(t, key, 32)
+
+		err = Auth("test_login", "test_pass", "")
+		require.NoError(t, err)
+		assert.False(t, HasVault())
+		err = UnlockVault("master_pass")
+		require.ErrorIs(t, err, ErrNoVault)
+
+		err = Auth("vault_login", "vault_pass", "")
+		require.NoError(t, err)
+		err = UnlockVault("master_pass")
+		require.NoError(t, err)
+		sameKey, err := LoadVaultKey()
+		require.NoError(t, err)
+		assert.Equal(t, key, sameKey)
+
+		err = Logout()
+		require.NoError(t, err)
+		assert.False(t, HasVault())
+		_, err = LoadVaultKey()
+		require.Error(t, err)
 	})
 }
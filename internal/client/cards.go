@@ -2,23 +2,17 @@ package client
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
-	"path"
 	"strconv"
 	"time"
 
-	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	pb "google.golang.org/protobuf/proto"
 
 	"github.com/Vidkin/gophkeeper/pkg/aes"
-	"github.com/Vidkin/gophkeeper/pkg/hash"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -26,51 +20,55 @@ import (
 //
 // Parameters:
 //   - card: A pointer to the proto.BankCard struct containing the card details to be added.
+//   - collectionID: The ID of the organization collection of the card, or zero for the personal cards.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, encryption, or gRPC communication.
-func AddCard(card *proto.BankCard) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func AddCard(card *proto.BankCard, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
+	}
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
 	}
-	token := string(f)
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
 
-	secretKey := viper.GetString("secret_key")
-	card.Cvv, err = aes.Encrypt(secretKey, card.Cvv)
+	key, err := itemKey(client, token, collectionID)
 	if err != nil {
 		return err
 	}
-	card.Owner, err = aes.Encrypt(secretKey, card.Owner)
+
+	card.Cvv, err = aes.Encrypt(key, card.Cvv)
 	if err != nil {
 		return err
 	}
-	card.Number, err = aes.Encrypt(secretKey, card.Number)
+	card.Owner, err = aes.Encrypt(key, card.Owner)
 	if err != nil {
 		return err
 	}
-	card.Description, err = aes.Encrypt(secretKey, card.Description)
+	card.Number, err = aes.Encrypt(key, card.Number)
 	if err != nil {
 		return err
 	}
-	card.ExpireDate, err = aes.Encrypt(secretKey, card.ExpireDate)
+	card.Description, err = aes.Encrypt(key, card.Description)
 	if err != nil {
 		return err
 	}
-
-	client, conn, err := NewGophkeeperClient()
+	card.ExpireDate, err = aes.Encrypt(key, card.ExpireDate)
 	if err != nil {
 		return err
 	}
-	defer func(conn *grpc.ClientConn) {
-		err = conn.Close()
-		if err != nil {
-			fmt.Println("failed to close grpc connection")
-		}
-	}(conn)
 
 	req := &proto.AddBankCardRequest{
-		Card: card,
+		Card:         card,
+		CollectionId: collectionID,
 	}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
@@ -79,22 +77,11 @@ func AddCard(card *proto.BankCard) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.AddBankCard(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -107,14 +94,17 @@ func AddCard(card *proto.BankCard) error {
 
 // GetAllCards retrieves all bank cards from the GophKeeper server and decrypts their information for display.
 //
+// Parameters:
+//   - collectionID: The ID of the organization collection to retrieve the cards of, or zero for the personal cards.
+//   - filter: Selects the cards by their folder and tag, an empty filter selects all of them.
+//
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
-func GetAllCards() error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetAllCards(collectionID int64, filter ItemFilter) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -127,7 +117,17 @@ func GetAllCards() error {
 		}
 	}(conn)
 
-	req := &proto.GetBankCardsRequest{}
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	match, err := itemMatcher(client, token, "card", filter, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetBankCardsRequest{CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -135,22 +135,11 @@ func GetAllCards() error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetBankCards(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -159,23 +148,26 @@ func GetAllCards() error {
 
 	fmt.Println("Bank cards:")
 	for _, card := range resp.Cards {
-		card.Owner, err = aes.Decrypt(viper.GetString("secret_key"), card.Owner)
+		if match != nil && !match(card.Id) {
+			continue
+		}
+		card.Owner, err = aes.Decrypt(key, card.Owner)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 		}
-		card.Description, err = aes.Decrypt(viper.GetString("secret_key"), card.Description)
+		card.Description, err = aes.Decrypt(key, card.Description)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 		}
-		card.Number, err = aes.Decrypt(viper.GetString("secret_key"), card.Number)
+		card.Number, err = aes.Decrypt(key, card.Number)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 		}
-		card.Cvv, err = aes.Decrypt(viper.GetString("secret_key"), card.Cvv)
+		card.Cvv, err = aes.Decrypt(key, card.Cvv)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 		}
-		card.ExpireDate, err = aes.Decrypt(viper.GetString("secret_key"), card.ExpireDate)
+		card.ExpireDate, err = aes.Decrypt(key, card.ExpireDate)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 		}
@@ -188,15 +180,15 @@ func GetAllCards() error {
 //
 // Parameters:
 //   - cardID: The ID of the bank card to retrieve.
+//   - collectionID: The ID of the organization collection of the card, or zero for the personal cards.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
-func GetCard(cardID int64) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetCard(cardID, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -209,7 +201,12 @@ func GetCard(cardID int64) error {
 		}
 	}(conn)
 
-	req := &proto.GetBankCardRequest{Id: strconv.FormatInt(cardID, 10)}
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetBankCardRequest{Id: strconv.FormatInt(cardID, 10), CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -217,22 +214,11 @@ func GetCard(cardID int64) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetBankCard(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -240,23 +226,23 @@ func GetCard(cardID int64) error {
 	}
 
 	fmt.Println("Bank card:")
-	resp.Card.Owner, err = aes.Decrypt(viper.GetString("secret_key"), resp.Card.Owner)
+	resp.Card.Owner, err = aes.Decrypt(key, resp.Card.Owner)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 	}
-	resp.Card.Description, err = aes.Decrypt(viper.GetString("secret_key"), resp.Card.Description)
+	resp.Card.Description, err = aes.Decrypt(key, resp.Card.Description)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 	}
-	resp.Card.Number, err = aes.Decrypt(viper.GetString("secret_key"), resp.Card.Number)
+	resp.Card.Number, err = aes.Decrypt(key, resp.Card.Number)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 	}
-	resp.Card.Cvv, err = aes.Decrypt(viper.GetString("secret_key"), resp.Card.Cvv)
+	resp.Card.Cvv, err = aes.Decrypt(key, resp.Card.Cvv)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 	}
-	resp.Card.ExpireDate, err = aes.Decrypt(viper.GetString("secret_key"), resp.Card.ExpireDate)
+	resp.Card.ExpireDate, err = aes.Decrypt(key, resp.Card.ExpireDate)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt card info, check secret key, original error: %v", err)
 	}
@@ -270,15 +256,15 @@ func GetCard(cardID int64) error {
 //
 // Parameters:
 //   - cardID: The ID of the bank card to remove.
+//   - collectionID: The ID of the organization collection of the card, or zero for the personal cards.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
-func RemoveCard(cardID int64) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func RemoveCard(cardID, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -291,7 +277,7 @@ func RemoveCard(cardID int64) error {
 		}
 	}(conn)
 
-	req := &proto.RemoveBankCardRequest{Id: strconv.FormatInt(cardID, 10)}
+	req := &proto.RemoveBankCardRequest{Id: strconv.FormatInt(cardID, 10), CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -299,22 +285,11 @@ func RemoveCard(cardID int64) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.RemoveBankCard(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -324,3 +299,58 @@ func RemoveCard(cardID int64) error {
 	fmt.Println("Bank card has been successfully removed")
 	return err
 }
+
+// EditCard changes a bank card on the GophKeeper server keeping its ID. The bank card is fetched and decrypted, changed
+// by the edit function and sent back encrypted; the change is rejected if the bank card has been changed
+// by another client in the meantime. If the bank card is shared, its shared copy is replaced with the changed
+// bank card, encrypted with the data key of the copy, so that the recipients see the change.
+//
+// Parameters:
+//   - cardID: The ID of the bank card to change.
+//   - collectionID: The ID of the organization collection of the bank card, or zero for the personal cards.
+//   - edit: Changes the decrypted bank card.
+//
+// Returns:
+//   - ErrItemChanged if the bank card has been changed by another client, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, encryption or decryption.
+func EditCard(cardID, collectionID int64, edit func(card *proto.BankCard)) error {
+	var card *proto.BankCard
+	var dataKey, sharedData string
+	version, err := editItem(
+		collectionID,
+		func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error) {
+			resp, err := client.GetBankCard(ctx, &proto.GetBankCardRequest{Id: strconv.FormatInt(cardID, 10), CollectionId: collectionID})
+			if err != nil {
+				return nil, err
+			}
+			card = resp.Card
+			if collectionID == 0 {
+				if dataKey, err = sharedDataKey(ctx, client, "card", cardID); err != nil {
+					return nil, err
+				}
+			}
+			return []*string{&card.Number, &card.ExpireDate, &card.Cvv, &card.Owner, &card.Description}, nil
+		},
+		func() error {
+			edit(card)
+			if dataKey == "" {
+				return nil
+			}
+			var err error
+			sharedData, err = encryptSharedItem(dataKey, card)
+			return err
+		},
+		func(ctx context.Context, client proto.GophkeeperClient) (int64, error) {
+			resp, err := client.UpdateBankCard(ctx, &proto.UpdateBankCardRequest{Card: card, CollectionId: collectionID, SharedData: sharedData})
+			if err != nil {
+				return 0, err
+			}
+			return resp.Version, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully update the bank card! version=%d\n", version)
+	return nil
+}
@@ -2,8 +2,8 @@ package client
 
 import (
 	"os"
-	"path"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -12,6 +12,7 @@ import (
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/handlers"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -28,8 +29,8 @@ func TestCards(t *testing.T) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor("defaultHashKey"),
-			interceptors.ValidateToken("JWTKey")))
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := server.GetTLSListener(
@@ -47,10 +48,10 @@ func TestCards(t *testing.T) {
 	viper.Set("crypto_key_public_path", "../../certs/public.crt")
 	viper.Set("hash_key", "defaultHashKey")
 
-	err = Register("test_login", "test_pass")
+	err = Register("test_login", "test_pass", "")
 	require.NoError(t, err)
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 
 	card := proto.BankCard{
@@ -63,48 +64,48 @@ func TestCards(t *testing.T) {
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test add card: invalid key size", func(t *testing.T) {
-		err = AddCard(&card)
+		err = AddCard(&card, 0)
 		require.ErrorContains(t, err, "invalid key size")
 	})
 
 	viper.Set("secret_key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x")
 	t.Run("test add card: missing hash", func(t *testing.T) {
-		err = AddCard(&card)
+		err = AddCard(&card, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test add card: missed token file", func(t *testing.T) {
-		err = AddCard(&card)
+		err = AddCard(&card, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test add card: expired token", func(t *testing.T) {
-		err = AddCard(&card)
+		err = AddCard(&card, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test add card: ok", func(t *testing.T) {
-		err = AddCard(&card)
+		err = AddCard(&card, 0)
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get all cards: missing hash", func(t *testing.T) {
-		err = GetAllCards()
+		err = GetAllCards(0, ItemFilter{})
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get all cards: missed token file", func(t *testing.T) {
-		err = GetAllCards()
+		err = GetAllCards(0, ItemFilter{})
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -112,28 +113,28 @@ func TestCards(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get all cards: expired token", func(t *testing.T) {
-		err = GetAllCards()
+		err = GetAllCards(0, ItemFilter{})
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get all cards: ok", func(t *testing.T) {
-		err = GetAllCards()
+		err = GetAllCards(0, ItemFilter{})
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get card: missing hash", func(t *testing.T) {
-		err = GetCard(1)
+		err = GetCard(1, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get card: missed token file", func(t *testing.T) {
-		err = GetCard(1)
+		err = GetCard(1, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -141,28 +142,28 @@ func TestCards(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get card: expired token", func(t *testing.T) {
-		err = GetCard(1)
+		err = GetCard(1, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get card: ok", func(t *testing.T) {
-		err = GetCard(1)
+		err = GetCard(1, 0)
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test remove card: missing hash", func(t *testing.T) {
-		err = RemoveCard(1)
+		err = RemoveCard(1, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test remove card: missed token file", func(t *testing.T) {
-		err = RemoveCard(1)
+		err = RemoveCard(1, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -170,19 +171,19 @@ func TestCards(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test remove card: expired token", func(t *testing.T) {
-		err = RemoveCard(1)
+		err = RemoveCard(1, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test remove unknown card: ok", func(t *testing.T) {
-		err = RemoveCard(765)
+		err = RemoveCard(765, 0)
 		require.NoError(t, err)
 	})
 
 	t.Run("test remove card: ok", func(t *testing.T) {
-		err = RemoveCard(1)
+		err = RemoveCard(1, 0)
 		require.NoError(t, err)
 	})
 }
@@ -13,20 +13,41 @@ import (
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
-// TokenFileName is the name of the temporary file used to store the JWT token.
+// StateDirName is the name of the directory in the user cache directory where the client keeps its state files:
+// the tokens, the vault key, the rekey journal and the search index. The directory is only accessible to its owner.
+const StateDirName = "gophkeeper"
+
+// TokenFileName is the name of the state file used to store the JWT token.
 const TokenFileName = "gophkeeperJWT.tmp"
 
+// RefreshTokenFileName is the name of the state file used to store the refresh token.
+const RefreshTokenFileName = "gophkeeperRefresh.tmp"
+
+// VaultFileName is the name of the state file used to store the vault key derivation parameters.
+const VaultFileName = "gophkeeperVault.tmp"
+
+// VaultKeyFileName is the name of the state file used to store the vault key derived from the master password.
+const VaultKeyFileName = "gophkeeperVaultKey.tmp"
+
+// RekeyJournalFileName is the name of the state file used to store the progress of an interrupted vault rekey.
+const RekeyJournalFileName = "gophkeeperRekey.tmp"
+
+// SearchIndexFileName is the name of the state file used to store the search index encrypted with the vault key.
+const SearchIndexFileName = "gophkeeperSearch.tmp"
+
 // NewGophkeeperClient creates a new gRPC client for the GophKeeper server with secure TLS credentials.
 //
 // It reads the server address and the public key certificate path from the configuration,
 // establishes a TLS connection using the provided CA certificate, and returns a new GophkeeperClient instance
-// along with the gRPC connection.
+// along with the gRPC connection. If client_cert and client_key are configured, the client certificate
+// is presented to the server, which is required when the server runs in the mutual TLS mode. If hash_key
+// is configured, every request is signed with it (see signUnary and signStream).
 //
 // Returns:
 //   - A pointer to the GophkeeperClient interface for making gRPC calls.
 //   - A pointer to the grpc.ClientConn for managing the connection.
 //   - An error if any step in the process fails, including reading the certificate,
-//     appending it to the certificate pool, or establishing the gRPC connection.
+//     appending it to the certificate pool, loading the client certificate, or establishing the gRPC connection.
 func NewGophkeeperClient() (proto.GophkeeperClient, *grpc.ClientConn, error) {
 	serverAddress := viper.GetString("address")
 	certPath := viper.GetString("crypto_key_public_path")
@@ -44,8 +65,23 @@ func NewGophkeeperClient() (proto.GophkeeperClient, *grpc.ClientConn, error) {
 		RootCAs:    certPool,
 		NextProtos: []string{"h2"},
 	}
+	clientCertPath := viper.GetString("client_cert")
+	clientKeyPath := viper.GetString("client_key")
+	if clientCertPath != "" || clientKeyPath != "" {
+		if clientCertPath == "" || clientKeyPath == "" {
+			return nil, nil, errors.New("client_cert and client_key must be set together")
+		}
+		clientCert, err := tls.LoadX509KeyPair(clientCertPath, clientKeyPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
 	creds := credentials.NewTLS(tlsConfig)
-	conn, err := grpc.NewClient(serverAddress, grpc.WithTransportCredentials(creds))
+	conn, err := grpc.NewClient(serverAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(signUnary),
+		grpc.WithChainStreamInterceptor(signStream))
 	if err != nil {
 		return nil, nil, err
 	}
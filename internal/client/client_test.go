@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/app/server"
+	"github.com/Vidkin/gophkeeper/pkg/cert/x509"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+type subjectServer struct {
+	proto.UnimplementedGophkeeperServer
+	subject string
+}
+
+func (s *subjectServer) RegisterUser(ctx context.Context, in *proto.RegisterUserRequest) (*emptypb.Empty, error) {
+	s.subject, _ = ctx.Value(interceptors.ClientSubject).(string)
+	return &emptypb.Empty{}, nil
+}
+
+func TestNewGophkeeperClient_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := filepath.Join(dir, "public.crt"), filepath.Join(dir, "private.key")
+	caCert, caKey := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	clientCert, clientKey := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	require.NoError(t, x509.CreateAndSave("test", "US", serverCert, serverKey))
+	require.NoError(t, x509.CreateCAAndSave("test", "US", caCert, caKey))
+	require.NoError(t, x509.IssueClientAndSave(caCert, caKey, "alice", clientCert, clientKey))
+
+	tlsConfig, err := server.GetTLSConfig(serverCert, serverKey, caCert)
+	require.NoError(t, err)
+	gs := &subjectServer{}
+	s := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.ChainUnaryInterceptor(interceptors.ClientCertificate))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(listen)
+	}()
+	defer s.Stop()
+
+	viper.Set("address", listen.Addr().String())
+	viper.Set("crypto_key_public_path", serverCert)
+	defer viper.Set("client_cert", "")
+	defer viper.Set("client_key", "")
+
+	call := func() error {
+		c, conn, err := NewGophkeeperClient()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = c.RegisterUser(context.Background(), &proto.RegisterUserRequest{})
+		return err
+	}
+
+	t.Run("test client certificate is required", func(t *testing.T) {
+		assert.Error(t, call())
+	})
+
+	t.Run("test client certificate and key must be set together", func(t *testing.T) {
+		viper.Set("client_cert", clientCert)
+		err := call()
+		assert.ErrorContains(t, err, "client_cert and client_key must be set together")
+	})
+
+	t.Run("test client certificate subject", func(t *testing.T) {
+		viper.Set("client_cert", clientCert)
+		viper.Set("client_key", clientKey)
+		require.NoError(t, call())
+		assert.Equal(t, "CN=alice,O=test,C=US", gs.subject)
+	})
+}
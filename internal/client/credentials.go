@@ -2,23 +2,17 @@ package client
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
-	"path"
 	"strconv"
 	"time"
 
-	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	pb "google.golang.org/protobuf/proto"
 
 	"github.com/Vidkin/gophkeeper/pkg/aes"
-	"github.com/Vidkin/gophkeeper/pkg/hash"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -26,42 +20,47 @@ import (
 //
 // Parameters:
 //   - credentials: A pointer to the proto.Credentials struct containing the login, password, and description.
+//   - collectionID: The ID of the organization collection of the credentials, or zero for the personal credentials.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, encryption, or gRPC communication.
-func AddCredentials(credentials *proto.Credentials) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func AddCredentials(credentials *proto.Credentials, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
+	}
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
 	}
-	token := string(f)
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
 
-	credentials.Login, err = aes.Encrypt(viper.GetString("secret_key"), credentials.Login)
+	key, err := itemKey(client, token, collectionID)
 	if err != nil {
 		return err
 	}
-	credentials.Password, err = aes.Encrypt(viper.GetString("secret_key"), credentials.Password)
+
+	credentials.Login, err = aes.Encrypt(key, credentials.Login)
 	if err != nil {
 		return err
 	}
-	credentials.Description, err = aes.Encrypt(viper.GetString("secret_key"), credentials.Description)
+	credentials.Password, err = aes.Encrypt(key, credentials.Password)
 	if err != nil {
 		return err
 	}
-
-	client, conn, err := NewGophkeeperClient()
+	credentials.Description, err = aes.Encrypt(key, credentials.Description)
 	if err != nil {
 		return err
 	}
-	defer func(conn *grpc.ClientConn) {
-		err = conn.Close()
-		if err != nil {
-			fmt.Println("failed to close grpc connection")
-		}
-	}(conn)
 
 	req := &proto.AddUserCredentialsRequest{
-		Credentials: credentials,
+		Credentials:  credentials,
+		CollectionId: collectionID,
 	}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
@@ -70,22 +69,11 @@ func AddCredentials(credentials *proto.Credentials) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.AddUserCredentials(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -98,18 +86,35 @@ func AddCredentials(credentials *proto.Credentials) error {
 
 // GetAllCredentials retrieves all user credentials from the GophKeeper server and decrypts their information for display.
 //
+// Parameters:
+//   - collectionID: The ID of the organization collection to retrieve the credentials of, or zero for the personal credentials.
+//   - filter: Selects the credentials by their folder and tag, an empty filter selects all of them.
+//
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
-func GetAllCredentials() error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetAllCredentials(collectionID int64, filter ItemFilter) error {
+	creds, err := getAllCredentials(collectionID, filter)
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
+	}
+
+	fmt.Println("User credentials:")
+	for _, cred := range creds {
+		fmt.Printf("id=%d, login=%s, password=%s, description=%s\n", cred.Id, cred.Login, cred.Password, cred.Description)
+	}
+	return nil
+}
+
+// getAllCredentials retrieves the user credentials selected by the filter from the GophKeeper server and decrypts them.
+func getAllCredentials(collectionID int64, filter ItemFilter) ([]*proto.Credentials, error) {
+	token, err := loadToken()
+	if err != nil {
+		return nil, err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func(conn *grpc.ClientConn) {
 		err = conn.Close()
@@ -118,7 +123,17 @@ func GetAllCredentials() error {
 		}
 	}(conn)
 
-	req := &proto.GetUserCredentialsRequest{}
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := itemMatcher(client, token, "credentials", filter, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &proto.GetUserCredentialsRequest{CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -126,60 +141,52 @@ func GetAllCredentials() error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetUserCredentials(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
-				return errors.New("need to re-authorize, call auth command")
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return nil, errors.New("need to re-authorize, call auth command")
 			}
 		}
-		return err
+		return nil, err
 	}
 
-	fmt.Println("User credentials:")
+	creds := make([]*proto.Credentials, 0, len(resp.Credentials))
 	for _, cred := range resp.Credentials {
-		cred.Login, err = aes.Decrypt(viper.GetString("secret_key"), cred.Login)
+		if match != nil && !match(cred.Id) {
+			continue
+		}
+		cred.Login, err = aes.Decrypt(key, cred.Login)
 		if err != nil {
-			return fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
+			return nil, fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
 		}
-		cred.Password, err = aes.Decrypt(viper.GetString("secret_key"), cred.Password)
+		cred.Password, err = aes.Decrypt(key, cred.Password)
 		if err != nil {
-			return fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
+			return nil, fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
 		}
-		cred.Description, err = aes.Decrypt(viper.GetString("secret_key"), cred.Description)
+		cred.Description, err = aes.Decrypt(key, cred.Description)
 		if err != nil {
-			return fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
+			return nil, fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
 		}
-		fmt.Printf("id=%d, login=%s, password=%s, description=%s\n", cred.Id, cred.Login, cred.Password, cred.Description)
+		creds = append(creds, cred)
 	}
-	return err
+	return creds, nil
 }
 
 // GetCredentials retrieves a specific user credential by its ID from the GophKeeper server and decrypts its information for display.
 //
 // Parameters:
 //   - credID: The ID of the user credential to retrieve.
+//   - collectionID: The ID of the organization collection of the credentials, or zero for the personal credentials.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
-func GetCredentials(credID int64) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetCredentials(credID, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -192,7 +199,12 @@ func GetCredentials(credID int64) error {
 		}
 	}(conn)
 
-	req := &proto.GetUserCredentialRequest{Id: strconv.FormatInt(credID, 10)}
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetUserCredentialRequest{Id: strconv.FormatInt(credID, 10), CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -200,22 +212,11 @@ func GetCredentials(credID int64) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetUserCredential(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -223,17 +224,17 @@ func GetCredentials(credID int64) error {
 	}
 
 	fmt.Println("Credentials:")
-	resp.Credentials.Login, err = aes.Decrypt(viper.GetString("secret_key"), resp.Credentials.Login)
+	resp.Credentials.Login, err = aes.Decrypt(key, resp.Credentials.Login)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
 	}
 
-	resp.Credentials.Password, err = aes.Decrypt(viper.GetString("secret_key"), resp.Credentials.Password)
+	resp.Credentials.Password, err = aes.Decrypt(key, resp.Credentials.Password)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
 	}
 
-	resp.Credentials.Description, err = aes.Decrypt(viper.GetString("secret_key"), resp.Credentials.Description)
+	resp.Credentials.Description, err = aes.Decrypt(key, resp.Credentials.Description)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt credentials info, check secret key, original error: %v", err)
 	}
@@ -248,15 +249,15 @@ func GetCredentials(credID int64) error {
 //
 // Parameters:
 //   - credID: The ID of the user credential to remove.
+//   - collectionID: The ID of the organization collection of the credentials, or zero for the personal credentials.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
-func RemoveCredentials(credID int64) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func RemoveCredentials(credID, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -269,7 +270,7 @@ func RemoveCredentials(credID int64) error {
 		}
 	}(conn)
 
-	req := &proto.RemoveUserCredentialsRequest{Id: strconv.FormatInt(credID, 10)}
+	req := &proto.RemoveUserCredentialsRequest{Id: strconv.FormatInt(credID, 10), CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -277,22 +278,11 @@ func RemoveCredentials(credID int64) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.RemoveUserCredentials(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -302,3 +292,58 @@ func RemoveCredentials(credID int64) error {
 	fmt.Println("Credentials has been successfully removed")
 	return err
 }
+
+// EditCredentials changes user credentials on the GophKeeper server keeping its ID. The credentials is fetched and decrypted, changed
+// by the edit function and sent back encrypted; the change is rejected if the credentials has been changed
+// by another client in the meantime. If the credentials are shared, their shared copy is replaced with the changed
+// credentials, encrypted with the data key of the copy, so that the recipients see the change.
+//
+// Parameters:
+//   - credID: The ID of the credentials to change.
+//   - collectionID: The ID of the organization collection of the credentials, or zero for the personal credentials.
+//   - edit: Changes the decrypted credentials.
+//
+// Returns:
+//   - ErrItemChanged if the credentials has been changed by another client, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, encryption or decryption.
+func EditCredentials(credID, collectionID int64, edit func(cred *proto.Credentials)) error {
+	var cred *proto.Credentials
+	var dataKey, sharedData string
+	version, err := editItem(
+		collectionID,
+		func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error) {
+			resp, err := client.GetUserCredential(ctx, &proto.GetUserCredentialRequest{Id: strconv.FormatInt(credID, 10), CollectionId: collectionID})
+			if err != nil {
+				return nil, err
+			}
+			cred = resp.Credentials
+			if collectionID == 0 {
+				if dataKey, err = sharedDataKey(ctx, client, "credentials", credID); err != nil {
+					return nil, err
+				}
+			}
+			return []*string{&cred.Login, &cred.Password, &cred.Description}, nil
+		},
+		func() error {
+			edit(cred)
+			if dataKey == "" {
+				return nil
+			}
+			var err error
+			sharedData, err = encryptSharedItem(dataKey, cred)
+			return err
+		},
+		func(ctx context.Context, client proto.GophkeeperClient) (int64, error) {
+			resp, err := client.UpdateUserCredentials(ctx, &proto.UpdateUserCredentialsRequest{Credentials: cred, CollectionId: collectionID, SharedData: sharedData})
+			if err != nil {
+				return 0, err
+			}
+			return resp.Version, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully update the credentials! version=%d\n", version)
+	return nil
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Vidkin/gophkeeper/pkg/breach"
+	"github.com/Vidkin/gophkeeper/pkg/strength"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// credentialsAudit is the result of the audit of one credentials entry.
+//
+// Fields:
+//   - Credentials: The audited credentials.
+//   - Weakness: The strength estimation of the password.
+//   - ReusedIn: The IDs of the other credentials with the same password.
+//   - Breaches: How many times the password has been seen in the breach corpus.
+//   - AgeDays: The number of days since the password was stored or last changed, if it's older than the limit.
+type credentialsAudit struct {
+	Credentials *proto.Credentials
+	Weakness    strength.Report
+	ReusedIn    []int64
+	Breaches    int64
+	AgeDays     int
+}
+
+// problems returns the descriptions of the problems found, empty if the password is fine.
+func (a *credentialsAudit) problems() []string {
+	var problems []string
+	if a.Breaches > 0 {
+		problems = append(problems, fmt.Sprintf("found %d times in the breach corpus", a.Breaches))
+	}
+	if a.Weakness.Weak() {
+		problem := fmt.Sprintf("weak password (about %.0f bits)", a.Weakness.Entropy)
+		if len(a.Weakness.Issues) > 0 {
+			problem += ": " + strings.Join(a.Weakness.Issues, ", ")
+		}
+		problems = append(problems, problem)
+	}
+	if len(a.ReusedIn) > 0 {
+		ids := make([]string, len(a.ReusedIn))
+		for i, id := range a.ReusedIn {
+			ids[i] = fmt.Sprint(id)
+		}
+		problems = append(problems, "reused in credentials "+strings.Join(ids, ", "))
+	}
+	if a.AgeDays > 0 {
+		problems = append(problems, fmt.Sprintf("not changed for %d days", a.AgeDays))
+	}
+	return problems
+}
+
+// auditCredentials checks the decrypted credentials for breached, weak, reused and old passwords.
+//
+// Parameters:
+//   - creds: The decrypted credentials.
+//   - corpus: The breach corpus, or nil to skip the breach check.
+//   - maxAgeDays: The age in days after which a password is reported as old, zero to skip the check.
+//   - now: The moment the age of the passwords is calculated at.
+//
+// Returns:
+//   - The audit results in the order of the credentials.
+//   - An error if the breach corpus can't be read.
+func auditCredentials(creds []*proto.Credentials, corpus *breach.Corpus, maxAgeDays int, now time.Time) ([]*credentialsAudit, error) {
+	byPassword := make(map[string][]int64)
+	for _, cred := range creds {
+		if cred.Password != "" {
+			byPassword[cred.Password] = append(byPassword[cred.Password], cred.Id)
+		}
+	}
+
+	audits := make([]*credentialsAudit, len(creds))
+	for i, cred := range creds {
+		audit := &credentialsAudit{Credentials: cred, Weakness: strength.Estimate(cred.Password)}
+		for _, id := range byPassword[cred.Password] {
+			if id != cred.Id {
+				audit.ReusedIn = append(audit.ReusedIn, id)
+			}
+		}
+		sort.Slice(audit.ReusedIn, func(i, j int) bool { return audit.ReusedIn[i] < audit.ReusedIn[j] })
+
+		if corpus != nil && cred.Password != "" {
+			n, err := corpus.Count(cred.Password)
+			if err != nil {
+				return nil, err
+			}
+			audit.Breaches = n
+		}
+
+		// The entries received from the servers that don't track the password changes have no password change time,
+		// their creation time is used.
+		changedAt := cred.PasswordChangedAt
+		if changedAt == "" {
+			changedAt = cred.CreatedAt
+		}
+		if maxAgeDays > 0 && changedAt != "" {
+			changed, err := time.Parse(time.DateTime, changedAt)
+			if err == nil {
+				if days := int(now.Sub(changed).Hours() / 24); days > maxAgeDays {
+					audit.AgeDays = days
+				}
+			}
+		}
+		audits[i] = audit
+	}
+	return audits, nil
+}
+
+// AuditCredentials retrieves and decrypts all user credentials and reports the passwords found in a local
+// breach corpus, the weak passwords, the passwords reused across the entries and the passwords not changed
+// for a long time. The passwords are only checked locally and are never sent anywhere.
+//
+// Parameters:
+//   - corpusPath: The path to the breach corpus, a sorted file of SHA-1 hashes in the Have I Been Pwned format,
+//     or an empty string to skip the breach check.
+//   - maxAgeDays: The age in days after which a password is reported as old, zero to skip the check.
+//   - collectionID: The ID of the organization collection to audit, or zero for the personal credentials.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, decryption
+//     or reading the breach corpus.
+func AuditCredentials(corpusPath string, maxAgeDays int, collectionID int64) error {
+	var corpus *breach.Corpus
+	if corpusPath != "" {
+		var err error
+		corpus, err = breach.Open(corpusPath)
+		if err != nil {
+			return fmt.Errorf("failed to open the breach corpus: %w", err)
+		}
+		defer corpus.Close()
+	}
+
+	creds, err := getAllCredentials(collectionID, ItemFilter{})
+	if err != nil {
+		return err
+	}
+	audits, err := auditCredentials(creds, corpus, maxAgeDays, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to read the breach corpus: %w", err)
+	}
+
+	fmt.Println("Credentials audit:")
+	if corpus == nil {
+		fmt.Println("breach check skipped, no corpus provided")
+	}
+	found := 0
+	for _, audit := range audits {
+		problems := audit.problems()
+		if len(problems) == 0 {
+			continue
+		}
+		found++
+		cred := audit.Credentials
+		fmt.Printf("id=%d, login=%s, description=%s\n", cred.Id, cred.Login, cred.Description)
+		for _, problem := range problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+	}
+	fmt.Printf("Checked %d credentials, %d with problems\n", len(audits), found)
+	return nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/breach"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestAuditCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("%X:3861493\n", sha1.Sum([]byte("P@ssw0rd")))), 0o600))
+	corpus, err := breach.Open(path)
+	require.NoError(t, err)
+	defer corpus.Close()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	creds := []*proto.Credentials{
+		{Id: 1, Login: "breached", Password: "P@ssw0rd", CreatedAt: "2025-12-31 00:00:00"},
+		{Id: 2, Login: "reused", Password: "x7#Kq9vL2!mZ", CreatedAt: "2025-12-31 00:00:00"},
+		{Id: 3, Login: "reused", Password: "x7#Kq9vL2!mZ", CreatedAt: "2025-12-31 00:00:00"},
+		{Id: 4, Login: "old", Password: "Tq4$wM8z!rB2", CreatedAt: "2024-01-01 00:00:00"},
+		{Id: 5, Login: "fine", Password: "correct horse battery staple", CreatedAt: "2025-12-31 00:00:00"},
+		{Id: 6, Login: "changed", Password: "Hv3!pX9s@Lm7", CreatedAt: "2024-01-01 00:00:00", PasswordChangedAt: "2025-12-01 00:00:00"},
+		{Id: 7, Login: "rekeyed", Password: "Zr5#nQ2w!Kd8", CreatedAt: "2023-01-01 00:00:00", UpdatedAt: "2025-12-30 00:00:00", PasswordChangedAt: "2024-12-01 00:00:00"},
+	}
+
+	audits, err := auditCredentials(creds, corpus, 365, now)
+	require.NoError(t, err)
+	require.Len(t, audits, len(creds))
+
+	assert.Equal(t, int64(3861493), audits[0].Breaches)
+	assert.True(t, audits[0].Weakness.Weak())
+	assert.Empty(t, audits[0].ReusedIn)
+
+	assert.Equal(t, []int64{3}, audits[1].ReusedIn)
+	assert.Equal(t, []int64{2}, audits[2].ReusedIn)
+	assert.Zero(t, audits[1].Breaches)
+	assert.False(t, audits[1].Weakness.Weak())
+
+	assert.Equal(t, 731, audits[3].AgeDays)
+	assert.Len(t, audits[3].problems(), 1)
+	assert.Empty(t, audits[4].problems())
+	assert.Zero(t, audits[5].AgeDays)
+	assert.Equal(t, 396, audits[6].AgeDays)
+
+	t.Run("checks are optional", func(t *testing.T) {
+		audits, err := auditCredentials(creds, nil, 0, now)
+		require.NoError(t, err)
+		assert.Zero(t, audits[0].Breaches)
+		assert.Zero(t, audits[3].AgeDays)
+	})
+}
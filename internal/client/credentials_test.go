@@ -1,17 +1,21 @@
 package client
 
 import (
+	"context"
 	"os"
-	"path"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/handlers"
+	"github.com/Vidkin/gophkeeper/pkg/aes"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -28,8 +32,8 @@ func TestCredentials(t *testing.T) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor("defaultHashKey"),
-			interceptors.ValidateToken("JWTKey")))
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := server.GetTLSListener(
@@ -47,10 +51,10 @@ func TestCredentials(t *testing.T) {
 	viper.Set("crypto_key_public_path", "../../certs/public.crt")
 	viper.Set("hash_key", "defaultHashKey")
 
-	err = Register("test_login", "test_pass")
+	err = Register("test_login", "test_pass", "")
 	require.NoError(t, err)
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 
 	cred := proto.Credentials{
@@ -61,48 +65,48 @@ func TestCredentials(t *testing.T) {
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test add credentials: invalid key size", func(t *testing.T) {
-		err = AddCredentials(&cred)
+		err = AddCredentials(&cred, 0)
 		require.ErrorContains(t, err, "invalid key size")
 	})
 
 	viper.Set("secret_key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x")
 	t.Run("test add credentials: missing hash", func(t *testing.T) {
-		err = AddCredentials(&cred)
+		err = AddCredentials(&cred, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test add credentials: missed token file", func(t *testing.T) {
-		err = AddCredentials(&cred)
+		err = AddCredentials(&cred, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test add credentials: expired token", func(t *testing.T) {
-		err = AddCredentials(&cred)
+		err = AddCredentials(&cred, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test add credentials: ok", func(t *testing.T) {
-		err = AddCredentials(&cred)
+		err = AddCredentials(&cred, 0)
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get all credentials: missing hash", func(t *testing.T) {
-		err = GetAllCredentials()
+		err = GetAllCredentials(0, ItemFilter{})
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get all credentials: missed token file", func(t *testing.T) {
-		err = GetAllCredentials()
+		err = GetAllCredentials(0, ItemFilter{})
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -110,28 +114,28 @@ func TestCredentials(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get all credentials: expired token", func(t *testing.T) {
-		err = GetAllCredentials()
+		err = GetAllCredentials(0, ItemFilter{})
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get all credentials: ok", func(t *testing.T) {
-		err = GetAllCredentials()
+		err = GetAllCredentials(0, ItemFilter{})
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get credential: missing hash", func(t *testing.T) {
-		err = GetCredentials(1)
+		err = GetCredentials(1, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get credential: missed token file", func(t *testing.T) {
-		err = GetCredentials(1)
+		err = GetCredentials(1, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -139,28 +143,54 @@ func TestCredentials(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get credential: expired token", func(t *testing.T) {
-		err = GetCredentials(1)
+		err = GetCredentials(1, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get credential: ok", func(t *testing.T) {
-		err = GetCredentials(1)
+		err = GetCredentials(1, 0)
 		require.NoError(t, err)
 	})
 
+	t.Run("test edit credentials: only the edited fields change", func(t *testing.T) {
+		before, err := storage.GetUserCredential(context.Background(), 1, 1)
+		require.NoError(t, err)
+
+		err = EditCredentials(1, 0, func(c *proto.Credentials) {
+			c.Description = "new description"
+		})
+		require.NoError(t, err)
+
+		after, err := storage.GetUserCredential(context.Background(), 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, before.Version+1, after.Version)
+		// The unchanged fields are sent back as they were stored, so the password age isn't reset.
+		assert.Equal(t, before.Login, after.Login)
+		assert.Equal(t, before.Password, after.Password)
+		assert.Equal(t, before.PasswordChangedAt, after.PasswordChangedAt)
+		description, err := aes.Decrypt("strongDBKey2Ks5nM2J5JaI59PPEhL1x", after.Description)
+		require.NoError(t, err)
+		assert.Equal(t, "new description", description)
+	})
+
+	t.Run("test edit credentials: unknown id", func(t *testing.T) {
+		err = EditCredentials(765, 0, func(c *proto.Credentials) {})
+		require.ErrorContains(t, err, "credentials not found")
+	})
+
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test remove credentials: missing hash", func(t *testing.T) {
-		err = RemoveCredentials(1)
+		err = RemoveCredentials(1, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test remove credentials: missed token file", func(t *testing.T) {
-		err = RemoveCredentials(1)
+		err = RemoveCredentials(1, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -168,19 +198,19 @@ func TestCredentials(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test remove credentials: expired token", func(t *testing.T) {
-		err = RemoveCredentials(1)
+		err = RemoveCredentials(1, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test remove unknown credentials: ok", func(t *testing.T) {
-		err = RemoveCredentials(765)
+		err = RemoveCredentials(765, 0)
 		require.NoError(t, err)
 	})
 
 	t.Run("test remove credentials: ok", func(t *testing.T) {
-		err = RemoveCredentials(1)
+		err = RemoveCredentials(1, 0)
 		require.NoError(t, err)
 	})
 }
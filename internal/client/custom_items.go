@@ -0,0 +1,553 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// hiddenValue is shown instead of the values of the secret fields unless they are revealed.
+const hiddenValue = "********"
+
+// customItemValues returns pointers to the encrypted values of a custom item: its name, the values
+// of its fields and its description.
+func customItemValues(item *proto.CustomItem) []*string {
+	values := []*string{&item.Name}
+	for _, f := range item.Fields {
+		values = append(values, &f.Value)
+	}
+	return append(values, &item.Description)
+}
+
+// decryptCustomItem decrypts the name, the field values and the description of a custom item in place.
+func decryptCustomItem(key string, item *proto.CustomItem) error {
+	for _, v := range customItemValues(item) {
+		text, err := aes.Decrypt(key, *v)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt custom item info, check secret key, original error: %v", err)
+		}
+		*v = text
+	}
+	return nil
+}
+
+// setCustomItemFields sets the values of the fields of a custom item by the names of the fields
+// and checks the values against the kinds of the fields.
+func setCustomItemFields(item *proto.CustomItem, values map[string]string) error {
+	byName := make(map[string]*proto.ItemField, len(item.Fields))
+	for _, f := range item.Fields {
+		byName[f.Name] = f
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("unknown field %q of template %s", name, item.Template)
+		}
+		if err := itemtype.ValidateValue(f.Kind, values[name]); err != nil {
+			return fmt.Errorf("field %s: %w", name, err)
+		}
+		f.Value = values[name]
+	}
+	return nil
+}
+
+// AddItemTemplate adds a new custom item type to the GophKeeper server. The template is not encrypted,
+// only the values of the items are.
+//
+// Parameters:
+//   - template: A pointer to the proto.ItemTemplate struct containing the name, the description
+//     and the ordered fields of the template.
+//
+// Returns:
+//   - An error if the template is invalid or any step in the process fails, including JWT file access
+//     or gRPC communication.
+func AddItemTemplate(template *proto.ItemTemplate) error {
+	t := itemtype.Template{Name: template.Name, Description: template.Description}
+	for _, f := range template.Fields {
+		t.Fields = append(t.Fields, itemtype.Field{Name: f.Name, Kind: f.Kind})
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.AddItemTemplate(ctxTimeout, &proto.AddItemTemplateRequest{Template: template})
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Successfully add a new item template!")
+	return err
+}
+
+// getItemTemplates retrieves the built-in item templates and the templates of the user.
+func getItemTemplates(ctx context.Context, client proto.GophkeeperClient) ([]*proto.ItemTemplate, error) {
+	resp, err := client.GetItemTemplates(ctx, &proto.GetItemTemplatesRequest{})
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return nil, errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return nil, err
+	}
+	return resp.Templates, nil
+}
+
+// GetItemTemplates retrieves the built-in item templates and the templates of the user from the GophKeeper server
+// and prints them with their fields.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func GetItemTemplates() error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	templates, err := getItemTemplates(withToken(ctxTimeout, token), client)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Item templates:")
+	for _, t := range templates {
+		origin := "custom"
+		if t.Builtin {
+			origin = "built-in"
+		}
+		fmt.Printf("name=%s (%s), description=%s\n", t.Name, origin, t.Description)
+		for _, f := range t.Fields {
+			fmt.Printf("  - %s: %s\n", f.Name, f.Kind)
+		}
+	}
+	return nil
+}
+
+// RemoveItemTemplate removes a custom item type of the user from the GophKeeper server by its name.
+// The items of the template are kept.
+//
+// Parameters:
+//   - name: The name of the template to remove.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func RemoveItemTemplate(name string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.RemoveItemTemplate(ctxTimeout, &proto.RemoveItemTemplateRequest{Name: name})
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Item template has been successfully removed")
+	return err
+}
+
+// AddCustomItem adds a new item of a custom type to the GophKeeper server. The fields of the item are
+// the fields of the template in their order, the fields without a value are left empty. The name,
+// the values and the description are encrypted before sending.
+//
+// Parameters:
+//   - templateName: The name of a built-in template or a template of the user.
+//   - name: The name of the item.
+//   - description: The description of the item.
+//   - values: The values of the fields by the names of the fields.
+//   - collectionID: The ID of the organization collection of the item, or zero for the personal items.
+//
+// Returns:
+//   - An error if the template is unknown, a value doesn't match the kind of its field, or any step
+//     in the process fails, including JWT file access, encryption, or gRPC communication.
+func AddCustomItem(templateName, name, description string, values map[string]string, collectionID int64) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	templates, err := getItemTemplates(ctxTimeout, client)
+	if err != nil {
+		return err
+	}
+	var template *proto.ItemTemplate
+	for _, t := range templates {
+		if t.Name == templateName {
+			template = t
+			break
+		}
+	}
+	if template == nil {
+		return fmt.Errorf("unknown item template %q, see the templates command", templateName)
+	}
+
+	item := &proto.CustomItem{Template: template.Name, Name: name, Description: description}
+	for _, f := range template.Fields {
+		item.Fields = append(item.Fields, &proto.ItemField{Name: f.Name, Kind: f.Kind})
+	}
+	if err = setCustomItemFields(item, values); err != nil {
+		return err
+	}
+	for _, v := range customItemValues(item) {
+		*v, err = aes.Encrypt(key, *v)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = client.AddCustomItem(ctxTimeout, &proto.AddCustomItemRequest{Item: item, CollectionId: collectionID})
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Successfully add a new custom item!")
+	return err
+}
+
+// GetAllCustomItems retrieves all custom items from the GophKeeper server and prints their names,
+// templates and descriptions. The values of the fields are shown by GetCustomItem.
+//
+// Parameters:
+//   - collectionID: The ID of the organization collection to retrieve the items of, or zero for the personal items.
+//   - filter: Selects the items by their folder and tag, an empty filter selects all of them.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func GetAllCustomItems(collectionID int64, filter ItemFilter) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	match, err := itemMatcher(client, token, "custom", filter, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetCustomItemsRequest{CollectionId: collectionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.GetCustomItems(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Custom items:")
+	for _, item := range resp.Items {
+		if match != nil && !match(item.Id) {
+			continue
+		}
+		if err = decryptCustomItem(key, item); err != nil {
+			return err
+		}
+		fmt.Printf("id=%d, template=%s, name=%s, description=%s\n", item.Id, item.Template, item.Name, item.Description)
+	}
+	return err
+}
+
+// formatCustomItem returns the decrypted custom item with a line for every field. The values of the secret
+// fields are hidden unless reveal is set, the lines of the multiline values are indented.
+func formatCustomItem(item *proto.CustomItem, reveal bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "id=%d, template=%s, name=%s, description=%s, version=%d, updated_at=%s\n",
+		item.Id, item.Template, item.Name, item.Description, item.Version, item.UpdatedAt)
+	for _, f := range item.Fields {
+		value := f.Value
+		switch {
+		case f.Kind == itemtype.KindSecret && !reveal && value != "":
+			value = hiddenValue
+		case f.Kind == itemtype.KindMultiline && strings.Contains(value, "\n"):
+			value = "\n    " + strings.ReplaceAll(strings.TrimRight(value, "\n"), "\n", "\n    ")
+		}
+		fmt.Fprintf(&b, "  %s (%s): %s\n", f.Name, f.Kind, value)
+	}
+	return b.String()
+}
+
+// GetCustomItem retrieves a specific custom item by its ID from the GophKeeper server, decrypts it
+// and prints its fields.
+//
+// Parameters:
+//   - itemID: The ID of the item to retrieve.
+//   - collectionID: The ID of the organization collection of the item, or zero for the personal items.
+//   - reveal: Whether the values of the secret fields are shown.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func GetCustomItem(itemID, collectionID int64, reveal bool) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetCustomItemRequest{Id: strconv.FormatInt(itemID, 10), CollectionId: collectionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.GetCustomItem(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	if err = decryptCustomItem(key, resp.Item); err != nil {
+		return err
+	}
+	fmt.Println("Custom item:")
+	fmt.Print(formatCustomItem(resp.Item, reveal))
+	return nil
+}
+
+// EditCustomItem changes a custom item on the GophKeeper server keeping its ID and template. The item is fetched
+// and decrypted, changed by the edit function, its fields are set to the values and it's sent back encrypted;
+// the change is rejected if the item has been changed by another client in the meantime.
+//
+// Parameters:
+//   - itemID: The ID of the item to change.
+//   - collectionID: The ID of the organization collection of the item, or zero for the personal items.
+//   - values: The new values of the fields by the names of the fields, the other fields are kept.
+//   - edit: Changes the decrypted item.
+//
+// Returns:
+//   - ErrItemChanged if the item has been changed by another client, or an error if a value doesn't match
+//     its field or any step in the process fails, including JWT file access, gRPC communication, encryption
+//     or decryption.
+func EditCustomItem(itemID, collectionID int64, values map[string]string, edit func(item *proto.CustomItem)) error {
+	var item *proto.CustomItem
+	version, err := editItem(
+		collectionID,
+		func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error) {
+			resp, err := client.GetCustomItem(ctx, &proto.GetCustomItemRequest{Id: strconv.FormatInt(itemID, 10), CollectionId: collectionID})
+			if err != nil {
+				return nil, err
+			}
+			item = resp.Item
+			return customItemValues(item), nil
+		},
+		func() error {
+			edit(item)
+			return setCustomItemFields(item, values)
+		},
+		func(ctx context.Context, client proto.GophkeeperClient) (int64, error) {
+			resp, err := client.UpdateCustomItem(ctx, &proto.UpdateCustomItemRequest{Item: item, CollectionId: collectionID})
+			if err != nil {
+				return 0, err
+			}
+			return resp.Version, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully update the custom item! version=%d\n", version)
+	return nil
+}
+
+// RemoveCustomItem removes a custom item from the GophKeeper server by its ID.
+//
+// Parameters:
+//   - itemID: The ID of the item to remove.
+//   - collectionID: The ID of the organization collection of the item, or zero for the personal items.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func RemoveCustomItem(itemID, collectionID int64) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.RemoveCustomItemRequest{Id: strconv.FormatInt(itemID, 10), CollectionId: collectionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.RemoveCustomItem(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Custom item has been successfully removed")
+	return err
+}
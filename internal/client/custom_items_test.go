@@ -0,0 +1,53 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestCustomItemFields(t *testing.T) {
+	newItem := func() *proto.CustomItem {
+		return &proto.CustomItem{
+			Template: "api-token",
+			Name:     "GitHub",
+			Fields: []*proto.ItemField{
+				{Name: "service", Kind: itemtype.KindText},
+				{Name: "url", Kind: itemtype.KindURL},
+				{Name: "token", Kind: itemtype.KindSecret},
+				{Name: "expires", Kind: itemtype.KindDate},
+			},
+		}
+	}
+
+	item := newItem()
+	require.NoError(t, setCustomItemFields(item, map[string]string{"token": "ghp_123", "expires": "2027-01-31"}))
+	assert.Equal(t, "ghp_123", item.Fields[2].Value)
+	assert.Equal(t, "2027-01-31", item.Fields[3].Value)
+	assert.Empty(t, item.Fields[0].Value)
+
+	assert.ErrorContains(t, setCustomItemFields(newItem(), map[string]string{"password": "x"}), "unknown field")
+	assert.ErrorIs(t, setCustomItemFields(newItem(), map[string]string{"url": "github.com"}), itemtype.ErrInvalidValue)
+	assert.ErrorIs(t, setCustomItemFields(newItem(), map[string]string{"expires": "tomorrow"}), itemtype.ErrInvalidValue)
+
+	hidden := formatCustomItem(item, false)
+	assert.NotContains(t, hidden, "ghp_123")
+	assert.Contains(t, hidden, "token (secret): "+hiddenValue)
+	assert.Contains(t, formatCustomItem(item, true), "token (secret): ghp_123")
+
+	key := "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+	for _, v := range customItemValues(item) {
+		var err error
+		*v, err = aes.Encrypt(key, *v)
+		require.NoError(t, err)
+	}
+	assert.NotEqual(t, "GitHub", item.Name)
+	require.NoError(t, decryptCustomItem(key, item))
+	assert.Equal(t, "GitHub", item.Name)
+	assert.Equal(t, "ghp_123", item.Fields[2].Value)
+}
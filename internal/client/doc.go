@@ -13,4 +13,12 @@
 // notes.go includes functions for adding, retrieving, and removing notes, as well as handling authorization using JWT tokens
 //
 // register.go includes functions for user registration and handling authorization using JWT tokens
+//
+// session.go includes functions for storing and refreshing JWT tokens and logging out
+//
+// vault.go includes functions for deriving the vault encryption key from the master password
+//
+// rekey.go includes functions for re-encrypting all the user data under a new master password or secret key
+//
+// totp.go includes functions for enabling and disabling two-factor authentication and managing recovery codes
 package client
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// editTimeout limits the time of an edit: fetching the item and sending the new version.
+const editTimeout = time.Second
+
+// ErrItemChanged is returned by the edit functions when the item has been changed by another client
+// after it was fetched. The edit can be repeated then.
+var ErrItemChanged = errors.New("the item has been changed by another client, run edit again")
+
+// editItem fetches an item, changes it and sends it back with the version it was fetched at. The fields left
+// unchanged by the edit are sent encrypted as they were fetched, so that the server can tell which ones changed.
+//
+// Parameters:
+//   - collectionID: The ID of the organization collection of the item, or zero for the personal items.
+//   - get: Fetches the item and returns pointers to its encrypted fields.
+//   - edit: Changes the item after its fields have been decrypted.
+//   - update: Sends the item after its fields have been encrypted again and returns the new version.
+//
+// Returns:
+//   - The new version of the item.
+//   - ErrItemChanged if the version is stale, or an error if any step fails, including JWT file access,
+//     gRPC communication, encryption or decryption.
+func editItem(
+	collectionID int64,
+	get func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error),
+	edit func() error,
+	update func(ctx context.Context, client proto.GophkeeperClient) (int64, error),
+) (int64, error) {
+	token, err := loadToken()
+	if err != nil {
+		return 0, err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return 0, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), editTimeout)
+	defer cancel()
+	ctx = withToken(ctx, token)
+
+	fields, err := get(ctx, client)
+	if err != nil {
+		return 0, editError(err)
+	}
+	encrypted := make([]string, len(fields))
+	decrypted := make([]string, len(fields))
+	for i, field := range fields {
+		encrypted[i] = *field
+		if *field, err = aes.Decrypt(key, *field); err != nil {
+			return 0, fmt.Errorf("failed to decrypt item info, check secret key, original error: %v", err)
+		}
+		decrypted[i] = *field
+	}
+	if err = edit(); err != nil {
+		return 0, err
+	}
+	for i, field := range fields {
+		if *field == decrypted[i] {
+			*field = encrypted[i]
+			continue
+		}
+		if *field, err = aes.Encrypt(key, *field); err != nil {
+			return 0, err
+		}
+	}
+
+	version, err := update(ctx, client)
+	if err != nil {
+		return 0, editError(err)
+	}
+	return version, nil
+}
+
+// editError converts the errors of the edit calls to the messages for the user.
+func editError(err error) error {
+	if e, ok := status.FromError(err); ok {
+		switch {
+		case e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage:
+			return errors.New("need to re-authorize, call auth command")
+		case e.Code() == codes.Aborted, e.Code() == codes.FailedPrecondition:
+			return ErrItemChanged
+		}
+	}
+	return err
+}
@@ -3,7 +3,6 @@ package client
 import (
 	"bufio"
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -11,33 +10,34 @@ import (
 	"path"
 	"time"
 
-	"github.com/spf13/viper"
 	"golang.org/x/text/unicode/norm"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	pb "google.golang.org/protobuf/proto"
 
-	"github.com/Vidkin/gophkeeper/pkg/hash"
+	"github.com/Vidkin/gophkeeper/pkg/aes"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
-// UploadFile uploads a file to the GophKeeper server with an optional description.
+// UploadFile encrypts a file with the secret key and uploads it to the GophKeeper server with an optional description.
+//
+// The file is encrypted chunk by chunk while it is streamed (see aes.NewEncryptReader), so the server
+// and MinIO only store the ciphertext.
 //
 // Parameters:
 //   - filePath: The path to the file to be uploaded.
 //   - description: A description of the file being uploaded.
+//   - collectionID: The ID of the organization collection of the file, or zero for the personal files.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, file opening,
 //     gRPC communication, or streaming errors.
-func UploadFile(filePath, description string) error {
-	tokenFile, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func UploadFile(filePath, description string, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(tokenFile)
 
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -55,7 +55,7 @@ func UploadFile(filePath, description string) error {
 	}
 
 	file := proto.File{}
-	file.FileSize = fStat.Size()
+	file.FileSize = aes.EncryptedSize(fStat.Size())
 	file.Description = description
 	file.FileName = norm.NFC.String(path.Base(filePath))
 
@@ -70,12 +70,20 @@ func UploadFile(filePath, description string) error {
 		}
 	}(conn)
 
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 	md := metadata.New(map[string]string{"token": token})
 	ctx = metadata.NewOutgoingContext(ctx, md)
 
-	reader := bufio.NewReader(f)
-	buffer := make([]byte, 1024*1024)
+	reader, err := aes.NewEncryptReader(bufio.NewReader(f), key)
+	if err != nil {
+		return err
+	}
+	buffer := make([]byte, aes.StreamChunkSize)
 
 	stream, err := client.Upload(ctx)
 	for {
@@ -88,10 +96,11 @@ func UploadFile(filePath, description string) error {
 		}
 
 		req := &proto.FileUploadRequest{
-			FileName:    file.FileName,
-			Description: file.Description,
-			FileSize:    file.FileSize,
-			Chunk:       buffer[:n],
+			FileName:     file.FileName,
+			Description:  file.Description,
+			FileSize:     file.FileSize,
+			Chunk:        buffer[:n],
+			CollectionId: collectionID,
 		}
 		err = stream.Send(req)
 		if err != nil {
@@ -108,32 +117,33 @@ func UploadFile(filePath, description string) error {
 	return err
 }
 
-// DownloadFile downloads a file from the GophKeeper server and saves it to the specified path.
+// ErrUnencryptedFile is returned when a downloaded file is not encrypted and plaintext files are not allowed.
+var ErrUnencryptedFile = errors.New("the file is not encrypted: it was uploaded by an older client version or replaced on the server, " +
+	"download it with --allow-legacy-plaintext if you trust it")
+
+// DownloadFile downloads a file from the GophKeeper server, decrypts it with the secret key and saves it to the specified path.
+//
+// The file is written to a temporary file in the same directory, which replaces the destination file only after
+// the whole file has been received and authenticated, so a failed download never destroys an existing file.
+//
+// Files uploaded by older client versions are stored unencrypted. They can't be told apart from a file replaced
+// by the server, so they are only saved, with a warning, if allowPlaintext is set.
 //
 // Parameters:
 //   - fileName: The name of the file to download from the server.
 //   - filePath: The local path where the file will be saved.
+//   - collectionID: The ID of the organization collection of the file, or zero for the personal files.
+//   - allowPlaintext: Whether an unencrypted file is saved as is.
 //
 // Returns:
-//   - An error if any step in the process fails, including JWT file access, file creation,
-//     gRPC communication, or streaming errors.
-func DownloadFile(fileName, filePath string) error {
-	tokenFile, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
-	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
-	}
-	token := string(tokenFile)
-
-	f, err := os.OpenFile(path.Join(filePath, fileName), os.O_WRONLY|os.O_CREATE, 0666)
+//   - ErrUnencryptedFile if the file isn't encrypted and allowPlaintext isn't set, or an error if any step
+//     in the process fails, including JWT file access, file creation, gRPC communication, streaming errors,
+//     or decryption.
+func DownloadFile(fileName, filePath string, collectionID int64, allowPlaintext bool) error {
+	token, err := loadToken()
 	if err != nil {
 		return err
 	}
-	defer func(f *os.File) {
-		err = f.Close()
-		if err != nil {
-			fmt.Println("failed to close file")
-		}
-	}(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -146,18 +156,60 @@ func DownloadFile(fileName, filePath string) error {
 		}
 	}(conn)
 
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 	md := metadata.New(map[string]string{"token": token})
 	ctx = metadata.NewOutgoingContext(ctx, md)
 
-	writer := bufio.NewWriter(f)
 	req := &proto.FileDownloadRequest{
-		FileName: norm.NFC.String(fileName),
+		FileName:     norm.NFC.String(fileName),
+		CollectionId: collectionID,
 	}
 	stream, err := client.Download(ctx, req)
-	for {
+	if err != nil {
+		fmt.Println("error receive file")
+		return err
+	}
+
+	f, err := os.CreateTemp(filePath, "."+path.Base(fileName)+".*.part")
+	if err != nil {
+		return err
+	}
+	if err = receiveFile(stream, f, key, allowPlaintext); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		fmt.Println("failed to close file")
+		return err
+	}
+	if err = os.Rename(f.Name(), path.Join(filePath, fileName)); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	fmt.Println("Successfully download file!")
+	return nil
+}
+
+// receiveFile writes the downloaded file to f, decrypting it with the key. The data written to f must not be used
+// unless receiveFile succeeds: the encrypted stream is only authenticated once it has been received completely.
+func receiveFile(stream proto.Gophkeeper_DownloadClient, f *os.File, key string, allowPlaintext bool) error {
+	writer := bufio.NewWriter(f)
+	var out io.Writer = writer
+	var decrypter io.WriteCloser
+	for first := true; ; first = false {
 		res, err := stream.Recv()
 		if err == io.EOF {
+			if first && !allowPlaintext {
+				return ErrUnencryptedFile
+			}
 			break
 		}
 		if err != nil {
@@ -165,36 +217,50 @@ func DownloadFile(fileName, filePath string) error {
 			return err
 		}
 
-		_, err = writer.Write(res.Chunk)
+		if first {
+			if aes.IsEncryptedStream(res.Chunk) {
+				decrypter = aes.NewDecryptWriter(writer, key)
+				out = decrypter
+			} else if !allowPlaintext {
+				return ErrUnencryptedFile
+			} else {
+				fmt.Println("Warning: the file is not encrypted, it was uploaded by an older client version")
+			}
+		}
+
+		_, err = out.Write(res.Chunk)
 		if err != nil {
 			fmt.Println("failed to write chunk")
 			return err
 		}
 	}
-	err = writer.Flush()
-	if err != nil {
+	if decrypter != nil {
+		if err := decrypter.Close(); err != nil {
+			fmt.Println("failed to decrypt file")
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
 		fmt.Println("failed to flush file")
 		return err
 	}
-
-	fmt.Println("Successfully download file!")
-	return err
+	return nil
 }
 
 // RemoveFile removes a file from the GophKeeper server by its name.
 //
 // Parameters:
 //   - fileName: The name of the file to be removed from the server.
+//   - collectionID: The ID of the organization collection of the file, or zero for the personal files.
 //
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication,
 //     or authorization issues.
-func RemoveFile(fileName string) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func RemoveFile(fileName string, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -207,7 +273,7 @@ func RemoveFile(fileName string) error {
 		}
 	}(conn)
 
-	req := &proto.FileRemoveRequest{FileName: fileName}
+	req := &proto.FileRemoveRequest{FileName: fileName, CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -215,22 +281,11 @@ func RemoveFile(fileName string) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.RemoveFile(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -243,15 +298,18 @@ func RemoveFile(fileName string) error {
 
 // GetAllFiles retrieves a list of all files stored on the GophKeeper server and displays their details.
 //
+// Parameters:
+//   - collectionID: The ID of the organization collection to retrieve the files of, or zero for the personal files.
+//   - filter: Selects the files by their folder and tag, an empty filter selects all of them.
+//
 // Returns:
 //   - An error if any step in the process fails, including JWT file access, gRPC communication,
 //     or errors in retrieving the file list.
-func GetAllFiles() error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetAllFiles(collectionID int64, filter ItemFilter) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -264,7 +322,12 @@ func GetAllFiles() error {
 		}
 	}(conn)
 
-	req := &proto.GetFilesRequest{}
+	match, err := itemMatcher(client, token, "file", filter, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetFilesRequest{CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -272,22 +335,11 @@ func GetAllFiles() error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetFiles(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -296,7 +348,68 @@ func GetAllFiles() error {
 
 	fmt.Println("Files:")
 	for _, file := range resp.Files {
+		if match != nil && !match(file.Id) {
+			continue
+		}
 		fmt.Printf("id=%d, fileName=%s, size=%d, description=%s\n", file.Id, norm.NFC.String(file.FileName), file.FileSize, file.Description)
 	}
 	return err
 }
+
+// EditFile changes the metadata of a file on the GophKeeper server: the edit function may change its description
+// and its name. The content of the file is not changed, the change is rejected if the file has been changed
+// by another client in the meantime.
+//
+// Parameters:
+//   - fileName: The name of the file to change.
+//   - collectionID: The ID of the organization collection of the file, or zero for the personal files.
+//   - edit: Changes the file metadata.
+//
+// Returns:
+//   - ErrItemChanged if the file has been changed by another client, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, or if the file is not found.
+func EditFile(fileName string, collectionID int64, edit func(file *proto.File)) error {
+	fileName = norm.NFC.String(fileName)
+	var file *proto.File
+	version, err := editItem(
+		collectionID,
+		func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error) {
+			resp, err := client.GetFiles(ctx, &proto.GetFilesRequest{CollectionId: collectionID})
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range resp.Files {
+				if norm.NFC.String(f.FileName) == fileName {
+					file = f
+					return nil, nil
+				}
+			}
+			return nil, fmt.Errorf("file %s not found", fileName)
+		},
+		func() error {
+			edit(file)
+			return nil
+		},
+		func(ctx context.Context, client proto.GophkeeperClient) (int64, error) {
+			req := &proto.UpdateFileRequest{
+				FileName:     fileName,
+				Description:  file.Description,
+				Version:      file.Version,
+				CollectionId: collectionID,
+			}
+			if newFileName := norm.NFC.String(file.FileName); newFileName != fileName {
+				req.NewFileName = newFileName
+			}
+			resp, err := client.UpdateFile(ctx, req)
+			if err != nil {
+				return 0, err
+			}
+			return resp.Version, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully update the file! version=%d\n", version)
+	return nil
+}
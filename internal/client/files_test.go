@@ -1,18 +1,24 @@
 package client
 
 import (
+	"io"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/handlers"
 	minioStorage "github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/aes"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -38,8 +44,12 @@ func TestFiles(t *testing.T) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor("defaultHashKey"),
-			interceptors.ValidateToken("JWTKey")))
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)),
+		grpc.ChainStreamInterceptor(
+			interceptors.LoggingStreamInterceptor,
+			interceptors.SignatureStreamInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateTokenStream(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := server.GetTLSListener(
@@ -57,23 +67,23 @@ func TestFiles(t *testing.T) {
 	viper.Set("crypto_key_public_path", "../../certs/public.crt")
 	viper.Set("hash_key", "defaultHashKey")
 
-	err = Register("test_login", "test_pass")
+	err = Register("test_login", "test_pass", "")
 	require.NoError(t, err)
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get all files: missing hash", func(t *testing.T) {
-		err = GetAllFiles()
+		err = GetAllFiles(0, ItemFilter{})
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get all files: missed token file", func(t *testing.T) {
-		err = GetAllFiles()
+		err = GetAllFiles(0, ItemFilter{})
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -81,28 +91,28 @@ func TestFiles(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get all files: expired token", func(t *testing.T) {
-		err = GetAllFiles()
+		err = GetAllFiles(0, ItemFilter{})
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get all files: ok", func(t *testing.T) {
-		err = GetAllFiles()
+		err = GetAllFiles(0, ItemFilter{})
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test remove file: missing hash", func(t *testing.T) {
-		err = RemoveFile("file")
+		err = RemoveFile("file", 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test remove file: missed token file", func(t *testing.T) {
-		err = RemoveFile("file")
+		err = RemoveFile("file", 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -110,44 +120,98 @@ func TestFiles(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test remove file: expired token", func(t *testing.T) {
-		err = RemoveFile("file")
+		err = RemoveFile("file", 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test remove unknown file error", func(t *testing.T) {
-		err = RemoveFile("fileUnknown")
+		err = RemoveFile("fileUnknown", 0)
 		require.ErrorContains(t, err, "file not found")
 	})
 
 	t.Run("test upload: file does not exist", func(t *testing.T) {
-		err = UploadFile(path.Join(os.TempDir(), "/badPath/badFile"), "token_file")
+		err = UploadFile(path.Join(os.TempDir(), "/badPath/badFile"), "token_file", 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
 	t.Run("test upload: ok", func(t *testing.T) {
-		err = UploadFile(path.Join(os.TempDir(), TokenFileName), "token_file")
+		err = UploadFile(stateFilePath(t, TokenFileName), "token_file", 0)
 		require.NoError(t, err)
 	})
 
 	t.Run("test download: bad path", func(t *testing.T) {
-		err = DownloadFile(TokenFileName, "/badPath//")
+		err = DownloadFile(TokenFileName, "/badPath//", 0, false)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
 	t.Run("test download: unknown file error", func(t *testing.T) {
-		err = DownloadFile("fileUnknown", os.TempDir())
-		require.ErrorContains(t, err, "error getting file info")
+		err = DownloadFile("fileUnknown", os.TempDir(), 0, false)
+		require.ErrorContains(t, err, "file not found")
 	})
 
 	t.Run("test download: ok", func(t *testing.T) {
-		err = DownloadFile(TokenFileName, os.TempDir())
+		uploaded, err := os.ReadFile(stateFilePath(t, TokenFileName))
+		require.NoError(t, err)
+		err = DownloadFile(TokenFileName, os.TempDir(), 0, false)
+		require.NoError(t, err)
+		downloaded, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
 		require.NoError(t, err)
+		require.Equal(t, uploaded, downloaded)
 	})
 
 	t.Run("test remove file: ok", func(t *testing.T) {
-		err = RemoveFile(TokenFileName)
+		err = RemoveFile(TokenFileName, 0)
 		require.NoError(t, err)
 	})
 }
+
+// chunkStream is a download stream returning the chunks of the data.
+type chunkStream struct {
+	grpc.ClientStream
+	chunks [][]byte
+}
+
+func (s *chunkStream) Recv() (*proto.FileDownloadResponse, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return &proto.FileDownloadResponse{Chunk: chunk}, nil
+}
+
+func TestReceiveFile(t *testing.T) {
+	key := "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+	r, err := aes.NewEncryptReader(strings.NewReader("secret data"), key)
+	require.NoError(t, err)
+	encrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	receive := func(allowPlaintext bool, chunks ...[]byte) (string, error) {
+		f, err := os.CreateTemp(t.TempDir(), "file")
+		require.NoError(t, err)
+		defer f.Close()
+		err = receiveFile(&chunkStream{chunks: chunks}, f, key, allowPlaintext)
+		data, errRead := os.ReadFile(f.Name())
+		require.NoError(t, errRead)
+		return string(data), err
+	}
+
+	data, err := receive(false, encrypted[:aes.StreamHeaderSize+1], encrypted[aes.StreamHeaderSize+1:])
+	require.NoError(t, err)
+	assert.Equal(t, "secret data", data)
+
+	_, err = receive(false, encrypted[:len(encrypted)-1])
+	assert.ErrorIs(t, err, aes.ErrStreamAuthentication)
+
+	_, err = receive(false, []byte("replaced by the server"))
+	assert.ErrorIs(t, err, ErrUnencryptedFile)
+	_, err = receive(false)
+	assert.ErrorIs(t, err, ErrUnencryptedFile)
+
+	data, err = receive(true, []byte("legacy file"))
+	require.NoError(t, err)
+	assert.Equal(t, "legacy file", data)
+}
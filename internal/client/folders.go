@@ -0,0 +1,486 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// labelsTimeout limits the duration of the calls made by the folder and tag commands, which make several calls each.
+const labelsTimeout = 5 * time.Second
+
+// folderSeparator separates the names of the folders in a folder path, e.g. "Work/Servers".
+const folderSeparator = "/"
+
+var (
+	// ErrFolderNotFound is returned when no folder has the given path.
+	ErrFolderNotFound = errors.New("folder not found")
+	// ErrFolderExists is returned when the parent folder already has a subfolder with the name.
+	ErrFolderExists = errors.New("folder already exists")
+	// ErrTagNotFound is returned when no tag has the given name.
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagExists is returned when a tag with the name already exists.
+	ErrTagExists = errors.New("tag already exists")
+	// errCollectionLabels is returned when the items of a collection are filtered by a folder or a tag.
+	errCollectionLabels = errors.New("folders and tags are only available for the personal items")
+)
+
+// ItemFilter selects the items listed by the getAll commands, an empty filter selects all the items.
+type ItemFilter struct {
+	Folder string // The path of a folder, the items of its subfolders are selected too
+	Tag    string // The name of a tag
+}
+
+// empty reports whether the filter selects all the items.
+func (f ItemFilter) empty() bool {
+	return f.Folder == "" && f.Tag == ""
+}
+
+// itemRef identifies an item of any type.
+type itemRef struct {
+	itemType string
+	id       int64
+}
+
+// folderTree contains the decrypted folders of the user and the folders the items are placed in.
+type folderTree struct {
+	folders map[int64]*proto.Folder
+	items   map[itemRef]int64
+}
+
+// newFolderTree decrypts the names of the folders with the key.
+func newFolderTree(key string, resp *proto.GetFoldersResponse) (*folderTree, error) {
+	t := &folderTree{folders: make(map[int64]*proto.Folder), items: make(map[itemRef]int64)}
+	for _, f := range resp.Folders {
+		name, err := aes.Decrypt(key, f.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt folder name, check secret key, original error: %v", err)
+		}
+		t.folders[f.Id] = &proto.Folder{Id: f.Id, ParentId: f.ParentId, Name: name}
+	}
+	for _, item := range resp.Items {
+		t.items[itemRef{itemType: item.ItemType, id: item.ItemId}] = item.FolderId
+	}
+	return t, nil
+}
+
+// splitFolderPath splits a folder path into the names of the folders, ignoring empty names.
+func splitFolderPath(folderPath string) []string {
+	var names []string
+	for _, name := range strings.Split(folderPath, folderSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validFolderName checks that a folder name is not empty and can be a part of a folder path.
+func validFolderName(name string) error {
+	if strings.TrimSpace(name) == "" || strings.Contains(name, folderSeparator) {
+		return fmt.Errorf("folder name must be non-empty and must not contain %q", folderSeparator)
+	}
+	return nil
+}
+
+// child returns the ID of the subfolder of the parent folder with the name, a zero parent is the top level.
+func (t *folderTree) child(parentID int64, name string) (int64, bool) {
+	for _, f := range t.folders {
+		if f.ParentId == parentID && f.Name == name {
+			return f.Id, true
+		}
+	}
+	return 0, false
+}
+
+// find returns the ID of the folder with the path, an empty path is the top level with the zero ID.
+func (t *folderTree) find(folderPath string) (int64, error) {
+	var id int64
+	for _, name := range splitFolderPath(folderPath) {
+		var ok bool
+		if id, ok = t.child(id, name); !ok {
+			return 0, fmt.Errorf("%w: %s", ErrFolderNotFound, folderPath)
+		}
+	}
+	return id, nil
+}
+
+// path returns the path of the folder.
+func (t *folderTree) path(id int64) string {
+	var names []string
+	for f, ok := t.folders[id]; ok; f, ok = t.folders[f.ParentId] {
+		names = append([]string{f.Name}, names...)
+	}
+	return strings.Join(names, folderSeparator)
+}
+
+// contains reports whether the folder is the ancestor folder or one of its subfolders.
+func (t *folderTree) contains(ancestorID, id int64) bool {
+	for f, ok := t.folders[id]; ok; f, ok = t.folders[f.ParentId] {
+		if f.Id == ancestorID {
+			return true
+		}
+	}
+	return false
+}
+
+// tagSet contains the decrypted tags of the user and the tags of the items.
+type tagSet struct {
+	tags  map[int64]string
+	items map[itemRef][]int64
+}
+
+// newTagSet decrypts the names of the tags with the key.
+func newTagSet(key string, resp *proto.GetTagsResponse) (*tagSet, error) {
+	s := &tagSet{tags: make(map[int64]string), items: make(map[itemRef][]int64)}
+	for _, t := range resp.Tags {
+		name, err := aes.Decrypt(key, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt tag name, check secret key, original error: %v", err)
+		}
+		s.tags[t.Id] = name
+	}
+	for _, item := range resp.Items {
+		ref := itemRef{itemType: item.ItemType, id: item.ItemId}
+		s.items[ref] = append(s.items[ref], item.TagId)
+	}
+	return s, nil
+}
+
+// find returns the ID of the tag with the name.
+func (s *tagSet) find(name string) (int64, bool) {
+	for id, tagName := range s.tags {
+		if tagName == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// has reports whether the item is tagged with the tag.
+func (s *tagSet) has(ref itemRef, tagID int64) bool {
+	for _, id := range s.items[ref] {
+		if id == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+// matcher returns the function reporting whether an item of the type passes the filter.
+func matcher(folders *folderTree, tags *tagSet, itemType string, filter ItemFilter) (func(id int64) bool, error) {
+	var folderID, tagID int64
+	if filter.Folder != "" {
+		var err error
+		if folderID, err = folders.find(filter.Folder); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Tag != "" {
+		var ok bool
+		if tagID, ok = tags.find(filter.Tag); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTagNotFound, filter.Tag)
+		}
+	}
+	return func(id int64) bool {
+		ref := itemRef{itemType: itemType, id: id}
+		if filter.Folder != "" && !folders.contains(folderID, folders.items[ref]) {
+			return false
+		}
+		return filter.Tag == "" || tags.has(ref, tagID)
+	}, nil
+}
+
+// loadLabels retrieves the folders and the tags of the user and decrypts their names.
+func loadLabels(ctx context.Context, client proto.GophkeeperClient) (*folderTree, *tagSet, error) {
+	key := viper.GetString("secret_key")
+	foldersResp, err := client.GetFolders(ctx, &proto.GetFoldersRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	folders, err := newFolderTree(key, foldersResp)
+	if err != nil {
+		return nil, nil, err
+	}
+	tagsResp, err := client.GetTags(ctx, &proto.GetTagsRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	tags, err := newTagSet(key, tagsResp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return folders, tags, nil
+}
+
+// itemMatcher returns the function reporting whether an item of the type passes the filter,
+// or nil if the filter is empty and all the items are listed.
+func itemMatcher(client proto.GophkeeperClient, token, itemType string, filter ItemFilter, collectionID int64) (func(id int64) bool, error) {
+	if filter.empty() {
+		return nil, nil
+	}
+	if collectionID != 0 {
+		return nil, errCollectionLabels
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), labelsTimeout)
+	defer cancel()
+	folders, tags, err := loadLabels(withToken(ctx, token), client)
+	if err != nil {
+		return nil, labelsError(err)
+	}
+	return matcher(folders, tags, itemType, filter)
+}
+
+// labelsError converts the errors of the folder and tag calls to the messages for the user.
+func labelsError(err error) error {
+	if e, ok := status.FromError(err); ok {
+		switch e.Code() {
+		case codes.PermissionDenied:
+			return errors.New("need to re-authorize, call auth command")
+		case codes.NotFound, codes.InvalidArgument:
+			return errors.New(e.Message())
+		}
+	}
+	return err
+}
+
+// withLabels connects to the GophKeeper server and calls the function with the context of the calls
+// and the decrypted folders and tags of the user.
+func withLabels(call func(ctx context.Context, client proto.GophkeeperClient, folders *folderTree, tags *tagSet) error) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), labelsTimeout)
+	defer cancel()
+	ctx = withToken(ctx, token)
+
+	folders, tags, err := loadLabels(ctx, client)
+	if err != nil {
+		return labelsError(err)
+	}
+	return labelsError(call(ctx, client, folders, tags))
+}
+
+// CreateFolder creates a folder with the path, e.g. "Work/Servers" creates the folder "Servers"
+// in the existing folder "Work". The name of the folder is encrypted with the secret key.
+//
+// Parameters:
+//   - folderPath: The path of the new folder.
+//
+// Returns:
+//   - ErrFolderExists if the folder already exists, ErrFolderNotFound if the parent folder doesn't exist,
+//     or an error if any step in the process fails, including JWT file access or gRPC communication.
+func CreateFolder(folderPath string) error {
+	names := splitFolderPath(folderPath)
+	if len(names) == 0 {
+		return errors.New("you must provide the folder path")
+	}
+	name := names[len(names)-1]
+	parentPath := strings.Join(names[:len(names)-1], folderSeparator)
+
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, folders *folderTree, _ *tagSet) error {
+		parentID, err := folders.find(parentPath)
+		if err != nil {
+			return err
+		}
+		if _, ok := folders.child(parentID, name); ok {
+			return fmt.Errorf("%w: %s", ErrFolderExists, folderPath)
+		}
+		encrypted, err := aes.Encrypt(viper.GetString("secret_key"), name)
+		if err != nil {
+			return err
+		}
+		if _, err = client.CreateFolder(ctx, &proto.CreateFolderRequest{Name: encrypted, ParentId: parentID}); err != nil {
+			return err
+		}
+		fmt.Println("Successfully create a new folder!")
+		return nil
+	})
+}
+
+// RenameFolder changes the name of the folder with the path, the folder stays in its parent folder.
+//
+// Parameters:
+//   - folderPath: The path of the folder.
+//   - name: The new name of the folder.
+//
+// Returns:
+//   - ErrFolderNotFound if the folder doesn't exist, ErrFolderExists if the parent folder already has a subfolder
+//     with the name, or an error if any step in the process fails.
+func RenameFolder(folderPath, name string) error {
+	if err := validFolderName(name); err != nil {
+		return err
+	}
+
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, folders *folderTree, _ *tagSet) error {
+		id, err := folders.find(folderPath)
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return errors.New("you must provide the folder path")
+		}
+		if _, ok := folders.child(folders.folders[id].ParentId, name); ok {
+			return fmt.Errorf("%w: %s", ErrFolderExists, name)
+		}
+		encrypted, err := aes.Encrypt(viper.GetString("secret_key"), name)
+		if err != nil {
+			return err
+		}
+		if _, err = client.RenameFolder(ctx, &proto.RenameFolderRequest{Id: id, Name: encrypted}); err != nil {
+			return err
+		}
+		fmt.Println("Folder has been successfully renamed")
+		return nil
+	})
+}
+
+// MoveFolder moves the folder with the path into another folder together with its subfolders and items.
+//
+// Parameters:
+//   - folderPath: The path of the folder.
+//   - parentPath: The path of the new parent folder, or an empty string to move the folder to the top level.
+//
+// Returns:
+//   - ErrFolderNotFound if any of the folders doesn't exist, ErrFolderExists if the new parent folder
+//     already has a subfolder with the name, or an error if any step in the process fails.
+func MoveFolder(folderPath, parentPath string) error {
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, folders *folderTree, _ *tagSet) error {
+		id, err := folders.find(folderPath)
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return errors.New("you must provide the folder path")
+		}
+		parentID, err := folders.find(parentPath)
+		if err != nil {
+			return err
+		}
+		if existing, ok := folders.child(parentID, folders.folders[id].Name); ok && existing != id {
+			return fmt.Errorf("%w: %s", ErrFolderExists, folders.path(existing))
+		}
+		if _, err = client.MoveFolder(ctx, &proto.MoveFolderRequest{Id: id, ParentId: parentID}); err != nil {
+			return err
+		}
+		fmt.Println("Folder has been successfully moved")
+		return nil
+	})
+}
+
+// RemoveFolder removes the folder with the path together with its subfolders, the items of the folders
+// are kept without a folder.
+//
+// Parameters:
+//   - folderPath: The path of the folder.
+//
+// Returns:
+//   - ErrFolderNotFound if the folder doesn't exist, or an error if any step in the process fails.
+func RemoveFolder(folderPath string) error {
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, folders *folderTree, _ *tagSet) error {
+		id, err := folders.find(folderPath)
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return errors.New("you must provide the folder path")
+		}
+		if _, err = client.RemoveFolder(ctx, &proto.RemoveFolderRequest{Id: id}); err != nil {
+			return err
+		}
+		fmt.Println("Folder has been successfully removed")
+		return nil
+	})
+}
+
+// formatFolders returns the folder tree, one folder per line indented by its depth, with the number
+// of the items placed directly in every folder.
+func formatFolders(folders *folderTree) string {
+	counts := make(map[int64]int)
+	for _, folderID := range folders.items {
+		counts[folderID]++
+	}
+	children := make(map[int64][]*proto.Folder)
+	for _, f := range folders.folders {
+		children[f.ParentId] = append(children[f.ParentId], f)
+	}
+
+	var b strings.Builder
+	var write func(parentID int64, depth int)
+	write = func(parentID int64, depth int) {
+		subfolders := children[parentID]
+		sort.Slice(subfolders, func(i, j int) bool { return subfolders[i].Name < subfolders[j].Name })
+		for _, f := range subfolders {
+			fmt.Fprintf(&b, "%s%s (items: %d)\n", strings.Repeat("  ", depth), f.Name, counts[f.Id])
+			write(f.Id, depth+1)
+		}
+	}
+	write(0, 0)
+	return b.String()
+}
+
+// GetAllFolders prints the folder tree of the user.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func GetAllFolders() error {
+	return withLabels(func(_ context.Context, _ proto.GophkeeperClient, folders *folderTree, _ *tagSet) error {
+		fmt.Println("Folders:")
+		fmt.Print(formatFolders(folders))
+		return nil
+	})
+}
+
+// SetItemFolder places a personal item in the folder with the path, replacing its previous folder.
+//
+// Parameters:
+//   - itemType: The type of the item: "card", "credentials", "note", "file", "otp" or "custom".
+//   - itemID: The ID of the item.
+//   - folderPath: The path of the folder, or an empty string to take the item out of its folder.
+//
+// Returns:
+//   - ErrFolderNotFound if the folder doesn't exist, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, or if the item is not found.
+func SetItemFolder(itemType string, itemID int64, folderPath string) error {
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, folders *folderTree, _ *tagSet) error {
+		folderID, err := folders.find(folderPath)
+		if err != nil {
+			return err
+		}
+		_, err = client.SetItemFolder(ctx, &proto.SetItemFolderRequest{ItemType: itemType, ItemId: itemID, FolderId: folderID})
+		if err != nil {
+			return err
+		}
+		if folderID == 0 {
+			fmt.Println("Item has been successfully taken out of its folder")
+			return nil
+		}
+		fmt.Printf("Successfully put the %s into %s!\n", itemType, folders.path(folderID))
+		return nil
+	})
+}
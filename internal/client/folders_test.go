@@ -0,0 +1,87 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestItemFilter(t *testing.T) {
+	key := "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+	encrypt := func(text string) string {
+		encrypted, err := aes.Encrypt(key, text)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	folders, err := newFolderTree(key, &proto.GetFoldersResponse{
+		Folders: []*proto.Folder{
+			{Id: 1, Name: encrypt("Work")},
+			{Id: 2, ParentId: 1, Name: encrypt("Servers")},
+			{Id: 3, Name: encrypt("Personal")},
+			{Id: 4, ParentId: 3, Name: encrypt("Servers")},
+		},
+		Items: []*proto.ItemFolder{
+			{ItemType: "credentials", ItemId: 10, FolderId: 1},
+			{ItemType: "credentials", ItemId: 11, FolderId: 2},
+			{ItemType: "credentials", ItemId: 12, FolderId: 4},
+			{ItemType: "note", ItemId: 10, FolderId: 3},
+		},
+	})
+	require.NoError(t, err)
+	tags, err := newTagSet(key, &proto.GetTagsResponse{
+		Tags: []*proto.Tag{{Id: 1, Name: encrypt("prod")}, {Id: 2, Name: encrypt("ssh")}},
+		Items: []*proto.ItemTag{
+			{ItemType: "credentials", ItemId: 11, TagId: 1},
+			{ItemType: "credentials", ItemId: 11, TagId: 2},
+			{ItemType: "credentials", ItemId: 12, TagId: 2},
+			{ItemType: "credentials", ItemId: 13, TagId: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	id, err := folders.find("Work/Servers")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+	id, err = folders.find(" /Personal/ Servers/")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), id)
+	assert.Equal(t, "Personal/Servers", folders.path(4))
+	_, err = folders.find("Work/Personal")
+	assert.ErrorIs(t, err, ErrFolderNotFound)
+
+	tests := []struct {
+		name     string
+		itemType string
+		filter   ItemFilter
+		selected []int64
+	}{
+		{name: "folder with subfolders", itemType: "credentials", filter: ItemFilter{Folder: "Work"}, selected: []int64{10, 11}},
+		{name: "subfolder", itemType: "credentials", filter: ItemFilter{Folder: "Personal/Servers"}, selected: []int64{12}},
+		{name: "tag", itemType: "credentials", filter: ItemFilter{Tag: "ssh"}, selected: []int64{11, 12}},
+		{name: "folder and tag", itemType: "credentials", filter: ItemFilter{Folder: "Work", Tag: "prod"}, selected: []int64{11}},
+		{name: "item type", itemType: "note", filter: ItemFilter{Folder: "Personal"}, selected: []int64{10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := matcher(folders, tags, tt.itemType, tt.filter)
+			require.NoError(t, err)
+			var selected []int64
+			for id := int64(10); id <= 13; id++ {
+				if match(id) {
+					selected = append(selected, id)
+				}
+			}
+			assert.Equal(t, tt.selected, selected)
+		})
+	}
+
+	_, err = matcher(folders, tags, "credentials", ItemFilter{Tag: "dev"})
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	assert.Equal(t, "Personal (items: 1)\n  Servers (items: 1)\nWork (items: 1)\n  Servers (items: 1)\n", formatFolders(folders))
+}
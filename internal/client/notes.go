@@ -2,23 +2,17 @@ package client
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
-	"path"
 	"strconv"
 	"time"
 
-	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	pb "google.golang.org/protobuf/proto"
 
 	"github.com/Vidkin/gophkeeper/pkg/aes"
-	"github.com/Vidkin/gophkeeper/pkg/hash"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -26,24 +20,14 @@ import (
 //
 // Parameters:
 //   - note: A pointer to the proto.Note structure containing the text and description of the note.
+//   - collectionID: The ID of the organization collection of the note, or zero for the personal notes.
 //
 // Returns an error if the operation fails, for example, if re-authorization is required.
-func AddNote(note *proto.Note) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
-	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
-	}
-	token := string(f)
-
-	note.Text, err = aes.Encrypt(viper.GetString("secret_key"), note.Text)
+func AddNote(note *proto.Note, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
 		return err
 	}
-	note.Description, err = aes.Encrypt(viper.GetString("secret_key"), note.Description)
-	if err != nil {
-		return err
-	}
-
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
 		return err
@@ -55,8 +39,23 @@ func AddNote(note *proto.Note) error {
 		}
 	}(conn)
 
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	note.Text, err = aes.Encrypt(key, note.Text)
+	if err != nil {
+		return err
+	}
+	note.Description, err = aes.Encrypt(key, note.Description)
+	if err != nil {
+		return err
+	}
+
 	req := &proto.AddNoteRequest{
-		Note: note,
+		Note:         note,
+		CollectionId: collectionID,
 	}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
@@ -65,22 +64,11 @@ func AddNote(note *proto.Note) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.AddNote(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -93,13 +81,16 @@ func AddNote(note *proto.Note) error {
 
 // GetAllNotes retrieves all user notes from the GophKeeper server.
 //
+// Parameters:
+//   - collectionID: The ID of the organization collection to retrieve the notes of, or zero for the personal notes.
+//   - filter: Selects the notes by their folder and tag, an empty filter selects all of them.
+//
 // Returns an error if the operation fails, for example, if re-authorization is required.
-func GetAllNotes() error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetAllNotes(collectionID int64, filter ItemFilter) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -112,7 +103,17 @@ func GetAllNotes() error {
 		}
 	}(conn)
 
-	req := &proto.GetNotesRequest{}
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	match, err := itemMatcher(client, token, "note", filter, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetNotesRequest{CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -120,22 +121,11 @@ func GetAllNotes() error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetNotes(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -144,11 +134,14 @@ func GetAllNotes() error {
 
 	fmt.Println("User notes:")
 	for _, note := range resp.Notes {
-		note.Text, err = aes.Decrypt(viper.GetString("secret_key"), note.Text)
+		if match != nil && !match(note.Id) {
+			continue
+		}
+		note.Text, err = aes.Decrypt(key, note.Text)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt note info, check secret key, original error: %v", err)
 		}
-		note.Description, err = aes.Decrypt(viper.GetString("secret_key"), note.Description)
+		note.Description, err = aes.Decrypt(key, note.Description)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt note info, check secret key, original error: %v", err)
 		}
@@ -161,14 +154,14 @@ func GetAllNotes() error {
 //
 // Parameters:
 //   - noteID: The ID of the note to retrieve.
+//   - collectionID: The ID of the organization collection of the note, or zero for the personal notes.
 //
 // Returns an error if the operation fails, for example, if re-authorization is required.
-func GetNote(noteID int64) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func GetNote(noteID, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -181,7 +174,12 @@ func GetNote(noteID int64) error {
 		}
 	}(conn)
 
-	req := &proto.GetNoteRequest{Id: strconv.FormatInt(noteID, 10)}
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetNoteRequest{Id: strconv.FormatInt(noteID, 10), CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -189,22 +187,11 @@ func GetNote(noteID int64) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	resp, err := client.GetNote(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -212,12 +199,12 @@ func GetNote(noteID int64) error {
 	}
 
 	fmt.Println("Note:")
-	resp.Note.Text, err = aes.Decrypt(viper.GetString("secret_key"), resp.Note.Text)
+	resp.Note.Text, err = aes.Decrypt(key, resp.Note.Text)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt note, check secret key, original error: %v", err)
 	}
 
-	resp.Note.Description, err = aes.Decrypt(viper.GetString("secret_key"), resp.Note.Description)
+	resp.Note.Description, err = aes.Decrypt(key, resp.Note.Description)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt note, check secret key, original error: %v", err)
 	}
@@ -232,14 +219,14 @@ func GetNote(noteID int64) error {
 //
 // Parameters:
 //   - noteID: The ID of the note to remove.
+//   - collectionID: The ID of the organization collection of the note, or zero for the personal notes.
 //
 // Returns an error if the operation fails, for example, if re-authorization is required.
-func RemoveNote(noteID int64) error {
-	f, err := os.ReadFile(path.Join(os.TempDir(), TokenFileName))
+func RemoveNote(noteID, collectionID int64) error {
+	token, err := loadToken()
 	if err != nil {
-		return fmt.Errorf("error open JWT file, need to authorize: %v", err)
+		return err
 	}
-	token := string(f)
 
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
@@ -252,7 +239,7 @@ func RemoveNote(noteID int64) error {
 		}
 	}(conn)
 
-	req := &proto.RemoveNoteRequest{Id: strconv.FormatInt(noteID, 10)}
+	req := &proto.RemoveNoteRequest{Id: strconv.FormatInt(noteID, 10), CollectionId: collectionID}
 
 	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -260,22 +247,11 @@ func RemoveNote(noteID int64) error {
 	md := metadata.New(map[string]string{"token": token})
 	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
-			return err
-		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md.Append("HashSHA256", hEnc)
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
-	}
-
 	_, err = client.RemoveNote(ctxTimeout, req)
 
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.PermissionDenied {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
 				return errors.New("need to re-authorize, call auth command")
 			}
 		}
@@ -285,3 +261,58 @@ func RemoveNote(noteID int64) error {
 	fmt.Println("Note has been successfully removed")
 	return err
 }
+
+// EditNote changes a note on the GophKeeper server keeping its ID. The note is fetched and decrypted, changed
+// by the edit function and sent back encrypted; the change is rejected if the note has been changed
+// by another client in the meantime. If the note is shared, its shared copy is replaced with the changed
+// note, encrypted with the data key of the copy, so that the recipients see the change.
+//
+// Parameters:
+//   - noteID: The ID of the note to change.
+//   - collectionID: The ID of the organization collection of the note, or zero for the personal notes.
+//   - edit: Changes the decrypted note.
+//
+// Returns:
+//   - ErrItemChanged if the note has been changed by another client, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, encryption or decryption.
+func EditNote(noteID, collectionID int64, edit func(note *proto.Note)) error {
+	var note *proto.Note
+	var dataKey, sharedData string
+	version, err := editItem(
+		collectionID,
+		func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error) {
+			resp, err := client.GetNote(ctx, &proto.GetNoteRequest{Id: strconv.FormatInt(noteID, 10), CollectionId: collectionID})
+			if err != nil {
+				return nil, err
+			}
+			note = resp.Note
+			if collectionID == 0 {
+				if dataKey, err = sharedDataKey(ctx, client, "note", noteID); err != nil {
+					return nil, err
+				}
+			}
+			return []*string{&note.Text, &note.Description}, nil
+		},
+		func() error {
+			edit(note)
+			if dataKey == "" {
+				return nil
+			}
+			var err error
+			sharedData, err = encryptSharedItem(dataKey, note)
+			return err
+		},
+		func(ctx context.Context, client proto.GophkeeperClient) (int64, error) {
+			resp, err := client.UpdateNote(ctx, &proto.UpdateNoteRequest{Note: note, CollectionId: collectionID, SharedData: sharedData})
+			if err != nil {
+				return 0, err
+			}
+			return resp.Version, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully update the note! version=%d\n", version)
+	return nil
+}
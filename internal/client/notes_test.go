@@ -2,8 +2,8 @@ package client
 
 import (
 	"os"
-	"path"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -12,6 +12,7 @@ import (
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/handlers"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -28,8 +29,8 @@ func TestNotes(t *testing.T) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor("defaultHashKey"),
-			interceptors.ValidateToken("JWTKey")))
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := server.GetTLSListener(
@@ -47,10 +48,10 @@ func TestNotes(t *testing.T) {
 	viper.Set("crypto_key_public_path", "../../certs/public.crt")
 	viper.Set("hash_key", "defaultHashKey")
 
-	err = Register("test_login", "test_pass")
+	err = Register("test_login", "test_pass", "")
 	require.NoError(t, err)
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 
 	note := proto.Note{
@@ -60,48 +61,48 @@ func TestNotes(t *testing.T) {
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test add note: invalid key size", func(t *testing.T) {
-		err = AddNote(&note)
+		err = AddNote(&note, 0)
 		require.ErrorContains(t, err, "invalid key size")
 	})
 
 	viper.Set("secret_key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x")
 	t.Run("test add note: missing hash", func(t *testing.T) {
-		err = AddNote(&note)
+		err = AddNote(&note, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test add note: missed token file", func(t *testing.T) {
-		err = AddNote(&note)
+		err = AddNote(&note, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test add note: expired token", func(t *testing.T) {
-		err = AddNote(&note)
+		err = AddNote(&note, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test add note: ok", func(t *testing.T) {
-		err = AddNote(&note)
+		err = AddNote(&note, 0)
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get all notes: missing hash", func(t *testing.T) {
-		err = GetAllNotes()
+		err = GetAllNotes(0, ItemFilter{})
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get all notes: missed token file", func(t *testing.T) {
-		err = GetAllNotes()
+		err = GetAllNotes(0, ItemFilter{})
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -109,28 +110,28 @@ func TestNotes(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get all notes: expired token", func(t *testing.T) {
-		err = GetAllNotes()
+		err = GetAllNotes(0, ItemFilter{})
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get all notes: ok", func(t *testing.T) {
-		err = GetAllNotes()
+		err = GetAllNotes(0, ItemFilter{})
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test get note: missing hash", func(t *testing.T) {
-		err = GetNote(1)
+		err = GetNote(1, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test get note: missed token file", func(t *testing.T) {
-		err = GetNote(1)
+		err = GetNote(1, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -138,28 +139,28 @@ func TestNotes(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test get note: expired token", func(t *testing.T) {
-		err = GetNote(1)
+		err = GetNote(1, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test get note: ok", func(t *testing.T) {
-		err = GetNote(1)
+		err = GetNote(1, 0)
 		require.NoError(t, err)
 	})
 
 	viper.Set("secret_key", "")
 	viper.Set("hash_key", "")
 	t.Run("test remove note: missing hash", func(t *testing.T) {
-		err = RemoveNote(1)
+		err = RemoveNote(1, 0)
 		require.ErrorContains(t, err, "missing hash")
 	})
 
-	err = os.Remove(path.Join(os.TempDir(), TokenFileName))
+	err = os.Remove(stateFilePath(t, TokenFileName))
 	require.NoError(t, err)
 	t.Run("test remove note: missed token file", func(t *testing.T) {
-		err = RemoveNote(1)
+		err = RemoveNote(1, 0)
 		require.ErrorContains(t, err, "no such file or directory")
 	})
 
@@ -167,19 +168,19 @@ func TestNotes(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 	setExpiredToken(t)
 	t.Run("test remove note: expired token", func(t *testing.T) {
-		err = RemoveNote(1)
+		err = RemoveNote(1, 0)
 		require.ErrorContains(t, err, "need to re-authorize")
 	})
 
-	err = Auth("test_login", "test_pass")
+	err = Auth("test_login", "test_pass", "")
 	require.NoError(t, err)
 	t.Run("test remove unknown note: ok", func(t *testing.T) {
-		err = RemoveNote(765)
+		err = RemoveNote(765, 0)
 		require.NoError(t, err)
 	})
 
 	t.Run("test remove note: ok", func(t *testing.T) {
-		err = RemoveNote(1)
+		err = RemoveNote(1, 0)
 		require.NoError(t, err)
 	})
 }
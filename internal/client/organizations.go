@@ -0,0 +1,315 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/pkg/sharing"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// insufficientRoleMessage is the message of the PermissionDenied status the server returns when the role
+// of the user in an organization doesn't allow the call.
+const insufficientRoleMessage = "insufficient role in the organization"
+
+// orgError converts the errors of the organization calls to the messages for the user.
+func orgError(err error) error {
+	if e, ok := status.FromError(err); ok {
+		switch {
+		case e.Code() == codes.PermissionDenied && e.Message() == insufficientRoleMessage:
+			return errors.New("your role in the organization doesn't allow this")
+		case e.Code() == codes.PermissionDenied:
+			return errors.New("need to re-authorize, call auth command")
+		case e.Code() == codes.FailedPrecondition:
+			return errors.New("the organization must keep at least one owner")
+		}
+	}
+	return err
+}
+
+// callOrg connects to the server and makes the organization calls with the token of the user.
+func callOrg(call func(ctx context.Context, client proto.GophkeeperClient) error) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharingTimeout)
+	defer cancel()
+	return orgError(call(withToken(ctx, token), client))
+}
+
+// orgKey retrieves the key of the organization selected by the request and unwraps it with the private sharing key of the user.
+func orgKey(ctx context.Context, client proto.GophkeeperClient, req *proto.GetOrgKeyRequest) (string, error) {
+	resp, err := client.GetOrgKey(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	_, private, err := sharingKeys(ctx, client, viper.GetString("secret_key"))
+	if err != nil {
+		return "", err
+	}
+	return sharing.UnwrapKey(private, resp.WrappedKey)
+}
+
+// itemKey returns the key the items are encrypted with: the secret key of the user for the personal items
+// or the key of the organization for the items of a collection.
+func itemKey(client proto.GophkeeperClient, token string, collectionID int64) (string, error) {
+	if collectionID == 0 {
+		return viper.GetString("secret_key"), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharingTimeout)
+	defer cancel()
+	key, err := orgKey(withToken(ctx, token), client, &proto.GetOrgKeyRequest{CollectionId: collectionID})
+	if err != nil {
+		return "", orgError(err)
+	}
+	return key, nil
+}
+
+// CreateOrganization creates an organization owned by the user.
+//
+// The organization key is a new random key wrapped with the public sharing key of the user, the items
+// of the organization collections are encrypted with it.
+//
+// Parameters:
+//   - name: The name of the organization.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, encryption or gRPC communication.
+func CreateOrganization(name string) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		public, _, err := sharingKeys(ctx, client, viper.GetString("secret_key"))
+		if err != nil {
+			return err
+		}
+		key, err := sharing.NewDataKey()
+		if err != nil {
+			return err
+		}
+		wrapped, err := sharing.WrapKey(public, key)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.CreateOrganization(ctx, &proto.CreateOrganizationRequest{Name: name, WrappedKey: wrapped})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Successfully created the organization, id=%d\n", resp.Id)
+		return nil
+	})
+}
+
+// ListOrganizations prints the organizations the user is a member of.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func ListOrganizations() error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		resp, err := client.ListOrganizations(ctx, &proto.ListOrganizationsRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Organizations:")
+		for _, org := range resp.Organizations {
+			fmt.Printf("id=%d, name=%s, role=%s\n", org.Id, org.Name, org.Role)
+		}
+		return nil
+	})
+}
+
+// RemoveOrganization removes an organization with all its collections and items.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func RemoveOrganization(orgID int64) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		if _, err := client.RemoveOrganization(ctx, &proto.RemoveOrganizationRequest{OrgId: orgID}); err != nil {
+			return err
+		}
+		fmt.Println("Organization has been successfully removed")
+		return nil
+	})
+}
+
+// ListOrgMembers prints the members of an organization.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func ListOrgMembers(orgID int64) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		resp, err := client.ListOrgMembers(ctx, &proto.ListOrgMembersRequest{OrgId: orgID})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Members:")
+		for _, member := range resp.Members {
+			fmt.Printf("login=%s, role=%s\n", member.Login, member.Role)
+		}
+		return nil
+	})
+}
+
+// AddOrgMember adds a user to an organization with a role.
+//
+// The organization key is unwrapped with the private sharing key of the user and wrapped with the public
+// sharing key of the new member, so the new member must have sharing keys.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//   - login: The login of the new member.
+//   - role: The role of the new member: "owner", "admin", "editor" or "viewer".
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, encryption, gRPC communication,
+//     or if the new member has no sharing keys yet.
+func AddOrgMember(orgID int64, login, role string) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		return addOrgMember(ctx, client, orgID, login, role)
+	})
+}
+
+func addOrgMember(ctx context.Context, client proto.GophkeeperClient, orgID int64, login, role string) error {
+	key, err := orgKey(ctx, client, &proto.GetOrgKeyRequest{OrgId: orgID})
+	if err != nil {
+		return err
+	}
+	memberKey, err := client.GetPublicKey(ctx, &proto.GetPublicKeyRequest{Login: login})
+	if status.Code(err) == codes.NotFound {
+		return errNoSharingKeys
+	}
+	if err != nil {
+		return err
+	}
+	wrapped, err := sharing.WrapKey(memberKey.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.AddOrgMember(ctx, &proto.AddOrgMemberRequest{OrgId: orgID, Login: login, Role: role, WrappedKey: wrapped})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Successfully added %s to the organization as %s\n", login, role)
+	return nil
+}
+
+// SetOrgMemberRole changes the role of a member of an organization.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//   - login: The login of the member.
+//   - role: The new role: "owner", "admin", "editor" or "viewer".
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func SetOrgMemberRole(orgID int64, login, role string) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		_, err := client.SetOrgMemberRole(ctx, &proto.SetOrgMemberRoleRequest{OrgId: orgID, Login: login, Role: role})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Successfully set the role of %s to %s\n", login, role)
+		return nil
+	})
+}
+
+// RemoveOrgMember removes a member from an organization, the users may remove themselves to leave it.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//   - login: The login of the member.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func RemoveOrgMember(orgID int64, login string) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		if _, err := client.RemoveOrgMember(ctx, &proto.RemoveOrgMemberRequest{OrgId: orgID, Login: login}); err != nil {
+			return err
+		}
+		fmt.Printf("Successfully removed %s from the organization\n", login)
+		return nil
+	})
+}
+
+// CreateCollection creates a collection in an organization.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//   - name: The name of the collection.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func CreateCollection(orgID int64, name string) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		resp, err := client.CreateCollection(ctx, &proto.CreateCollectionRequest{OrgId: orgID, Name: name})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Successfully created the collection, id=%d\n", resp.Id)
+		return nil
+	})
+}
+
+// ListCollections prints the collections of an organization.
+//
+// Parameters:
+//   - orgID: The ID of the organization.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func ListCollections(orgID int64) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		resp, err := client.ListCollections(ctx, &proto.ListCollectionsRequest{OrgId: orgID})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Collections:")
+		for _, collection := range resp.Collections {
+			fmt.Printf("id=%d, name=%s\n", collection.Id, collection.Name)
+		}
+		return nil
+	})
+}
+
+// RemoveCollection removes a collection with all its items.
+//
+// Parameters:
+//   - collectionID: The ID of the collection.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access or gRPC communication.
+func RemoveCollection(collectionID int64) error {
+	return callOrg(func(ctx context.Context, client proto.GophkeeperClient) error {
+		if _, err := client.RemoveCollection(ctx, &proto.RemoveCollectionRequest{CollectionId: collectionID}); err != nil {
+			return err
+		}
+		fmt.Println("Collection has been successfully removed")
+		return nil
+	})
+}
@@ -0,0 +1,413 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/totp"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// normalizeOTPItem fills in the default code parameters of a TOTP authenticator entry and checks
+// that the codes can be generated with its secret.
+func normalizeOTPItem(item *proto.OTPItem) error {
+	item.Secret = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(item.Secret), " ", "")), "=")
+	item.Algorithm = strings.ToUpper(item.Algorithm)
+	if item.Algorithm == "" {
+		item.Algorithm = totp.DefaultOptions.Algorithm
+	}
+	if item.Digits == 0 {
+		item.Digits = int32(totp.DefaultOptions.Digits)
+	}
+	if item.Period == 0 {
+		item.Period = int32(totp.DefaultOptions.Period)
+	}
+	if item.Period < 0 {
+		return fmt.Errorf("invalid period: %d", item.Period)
+	}
+	_, _, err := otpCode(item, time.Now())
+	return err
+}
+
+// otpCode returns the code of a decrypted TOTP authenticator entry for the given moment
+// and the number of seconds the code remains valid.
+func otpCode(item *proto.OTPItem, t time.Time) (string, int64, error) {
+	opts := &totp.Options{Algorithm: item.Algorithm, Digits: int(item.Digits), Period: uint(item.Period)}
+	code, err := totp.GenerateCode(item.Secret, t, opts)
+	if err != nil {
+		return "", 0, err
+	}
+	period := int64(item.Period)
+	return code, period - t.Unix()%period, nil
+}
+
+// decryptOTPItem decrypts the secret, the issuer, the account and the description of a TOTP authenticator entry in place.
+func decryptOTPItem(key string, item *proto.OTPItem) error {
+	for _, v := range []*string{&item.Secret, &item.Issuer, &item.Account, &item.Description} {
+		text, err := aes.Decrypt(key, *v)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt otp item info, check secret key, original error: %v", err)
+		}
+		*v = text
+	}
+	return nil
+}
+
+// AddOTPItem adds a new TOTP authenticator entry to the GophKeeper server after encrypting its secret
+// and account information. Missing code parameters are set to SHA1, 6 digits and 30 seconds.
+//
+// Parameters:
+//   - item: A pointer to the proto.OTPItem struct containing the entry details to be added.
+//   - collectionID: The ID of the organization collection of the entry, or zero for the personal entries.
+//
+// Returns:
+//   - An error if any step in the process fails, including an invalid secret or code parameters,
+//     JWT file access, encryption, or gRPC communication.
+func AddOTPItem(item *proto.OTPItem, collectionID int64) error {
+	if err := normalizeOTPItem(item); err != nil {
+		return err
+	}
+
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range []*string{&item.Secret, &item.Issuer, &item.Account, &item.Description} {
+		*v, err = aes.Encrypt(key, *v)
+		if err != nil {
+			return err
+		}
+	}
+
+	req := &proto.AddOTPItemRequest{
+		Item:         item,
+		CollectionId: collectionID,
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.AddOTPItem(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Successfully add a new otp item!")
+	return err
+}
+
+// ImportOTPItem adds a new TOTP authenticator entry from an otpauth:// provisioning URI,
+// as shown in the QR codes of the services.
+//
+// Parameters:
+//   - uri: The otpauth://totp/ URI.
+//   - description: The description of the entry.
+//   - collectionID: The ID of the organization collection of the entry, or zero for the personal entries.
+//
+// Returns:
+//   - An error if the URI is invalid or adding the entry fails.
+func ImportOTPItem(uri, description string, collectionID int64) error {
+	issuer, account, secret, opts, err := totp.ParseURI(uri)
+	if err != nil {
+		return err
+	}
+	return AddOTPItem(&proto.OTPItem{
+		Secret:      secret,
+		Issuer:      issuer,
+		Account:     account,
+		Algorithm:   opts.Algorithm,
+		Digits:      int32(opts.Digits),
+		Period:      int32(opts.Period),
+		Description: description,
+	}, collectionID)
+}
+
+// GetAllOTPItems retrieves all TOTP authenticator entries from the GophKeeper server and decrypts them for display.
+//
+// Parameters:
+//   - collectionID: The ID of the organization collection to retrieve the entries of, or zero for the personal entries.
+//   - filter: Selects the items by their folder and tag, an empty filter selects all of them.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func GetAllOTPItems(collectionID int64, filter ItemFilter) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return err
+	}
+
+	match, err := itemMatcher(client, token, "otp", filter, collectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetOTPItemsRequest{CollectionId: collectionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.GetOTPItems(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("OTP items:")
+	for _, item := range resp.Items {
+		if match != nil && !match(item.Id) {
+			continue
+		}
+		if err = decryptOTPItem(key, item); err != nil {
+			return err
+		}
+		fmt.Printf(
+			"id=%d, issuer=%s, account=%s, algorithm=%s, digits=%d, period=%d, description=%s\n",
+			item.Id, item.Issuer, item.Account, item.Algorithm, item.Digits, item.Period, item.Description)
+	}
+	return err
+}
+
+// getOTPItem retrieves a specific TOTP authenticator entry by its ID and decrypts it.
+func getOTPItem(itemID, collectionID int64) (*proto.OTPItem, error) {
+	token, err := loadToken()
+	if err != nil {
+		return nil, err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return nil, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	key, err := itemKey(client, token, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &proto.GetOTPItemRequest{Id: strconv.FormatInt(itemID, 10), CollectionId: collectionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.GetOTPItem(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return nil, errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return nil, err
+	}
+
+	if err = decryptOTPItem(key, resp.Item); err != nil {
+		return nil, err
+	}
+	return resp.Item, nil
+}
+
+// GetOTPItem retrieves a specific TOTP authenticator entry by its ID from the GophKeeper server and decrypts it for display.
+//
+// Parameters:
+//   - itemID: The ID of the entry to retrieve.
+//   - collectionID: The ID of the organization collection of the entry, or zero for the personal entries.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func GetOTPItem(itemID, collectionID int64) error {
+	item, err := getOTPItem(itemID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("OTP item:")
+	fmt.Printf(
+		"id=%d, secret=%s, issuer=%s, account=%s, algorithm=%s, digits=%d, period=%d, description=%s\n",
+		item.Id, item.Secret, item.Issuer, item.Account, item.Algorithm, item.Digits, item.Period, item.Description)
+	fmt.Println("uri:", totp.URI(item.Issuer, item.Account, item.Secret,
+		&totp.Options{Algorithm: item.Algorithm, Digits: int(item.Digits), Period: uint(item.Period)}))
+	return nil
+}
+
+// OTPCode retrieves a TOTP authenticator entry by its ID, generates its current code locally
+// and prints it together with the number of seconds the code remains valid.
+//
+// Parameters:
+//   - itemID: The ID of the entry.
+//   - collectionID: The ID of the organization collection of the entry, or zero for the personal entries.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, decryption
+//     or an invalid secret.
+func OTPCode(itemID, collectionID int64) error {
+	item, err := getOTPItem(itemID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	code, remaining, err := otpCode(item, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("code=%s, valid for %ds\n", code, remaining)
+	return nil
+}
+
+// RemoveOTPItem removes a TOTP authenticator entry from the GophKeeper server by its ID.
+//
+// Parameters:
+//   - itemID: The ID of the entry to remove.
+//   - collectionID: The ID of the organization collection of the entry, or zero for the personal entries.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func RemoveOTPItem(itemID, collectionID int64) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.RemoveOTPItemRequest{Id: strconv.FormatInt(itemID, 10), CollectionId: collectionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.RemoveOTPItem(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != insufficientRoleMessage {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("OTP item has been successfully removed")
+	return err
+}
+
+// EditOTPItem changes a TOTP authenticator entry on the GophKeeper server keeping its ID. The otp item is fetched and decrypted, changed
+// by the edit function and sent back encrypted; the change is rejected if the otp item has been changed
+// by another client in the meantime.
+//
+// Parameters:
+//   - itemID: The ID of the otp item to change.
+//   - collectionID: The ID of the organization collection of the otp item, or zero for the personal otp items.
+//   - edit: Changes the decrypted otp item.
+//
+// Returns:
+//   - ErrItemChanged if the otp item has been changed by another client, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, encryption or decryption.
+func EditOTPItem(itemID, collectionID int64, edit func(item *proto.OTPItem)) error {
+	var item *proto.OTPItem
+	version, err := editItem(
+		collectionID,
+		func(ctx context.Context, client proto.GophkeeperClient) ([]*string, error) {
+			resp, err := client.GetOTPItem(ctx, &proto.GetOTPItemRequest{Id: strconv.FormatInt(itemID, 10), CollectionId: collectionID})
+			if err != nil {
+				return nil, err
+			}
+			item = resp.Item
+			return []*string{&item.Secret, &item.Issuer, &item.Account, &item.Description}, nil
+		},
+		func() error {
+			edit(item)
+			return normalizeOTPItem(item)
+		},
+		func(ctx context.Context, client proto.GophkeeperClient) (int64, error) {
+			resp, err := client.UpdateOTPItem(ctx, &proto.UpdateOTPItemRequest{Item: item, CollectionId: collectionID})
+			if err != nil {
+				return 0, err
+			}
+			return resp.Version, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully update the otp item! version=%d\n", version)
+	return nil
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestOTPCode(t *testing.T) {
+	// The SHA1 test vector from RFC 6238, Appendix B.
+	item := &proto.OTPItem{Secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", Digits: 8}
+	require.NoError(t, normalizeOTPItem(item))
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", item.Secret)
+	assert.Equal(t, "SHA1", item.Algorithm)
+	assert.Equal(t, int32(30), item.Period)
+
+	code, remaining, err := otpCode(item, time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "94287082", code)
+	assert.Equal(t, int64(1), remaining)
+
+	_, remaining, err = otpCode(item, time.Unix(60, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), remaining)
+
+	assert.Error(t, normalizeOTPItem(&proto.OTPItem{Secret: "not base32!"}))
+	assert.Error(t, normalizeOTPItem(&proto.OTPItem{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "md5"}))
+	assert.Error(t, normalizeOTPItem(&proto.OTPItem{Secret: "JBSWY3DPEHPK3PXP", Period: -1}))
+}
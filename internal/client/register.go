@@ -2,16 +2,11 @@ package client
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"time"
 
-	"github.com/spf13/viper"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
-	pb "google.golang.org/protobuf/proto"
 
-	"github.com/Vidkin/gophkeeper/pkg/hash"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -20,11 +15,13 @@ import (
 // Parameters:
 //   - login: The login name of the user.
 //   - password: The password for the user account.
+//   - master: The master password to derive the vault encryption key from. If empty, no vault is created
+//     and the user data is encrypted with the secret_key setting.
 //
 // Returns an error if the registration fails, for example, if the user already exists
 // or if there is a connection issue with the server. If successful, a confirmation message
 // is printed to the console.
-func Register(login, password string) error {
+func Register(login, password, master string) error {
 	client, conn, err := NewGophkeeperClient()
 	if err != nil {
 		return err
@@ -43,19 +40,13 @@ func Register(login, password string) error {
 	req := &proto.RegisterUserRequest{
 		Credentials: &cred,
 	}
-	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
-	defer cancel()
-
-	if viper.GetString("hash_key") != "" {
-		data, err := pb.Marshal(req)
-		if err != nil {
+	if master != "" {
+		if req.Vault, _, err = newVault(master); err != nil {
 			return err
 		}
-		h := hash.GetHashSHA256(viper.GetString("hash_key"), data)
-		hEnc := base64.StdEncoding.EncodeToString(h)
-		md := metadata.New(map[string]string{"HashSHA256": hEnc})
-		ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
 	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
 
 	_, err = client.RegisterUser(ctxTimeout, req)
 	if err == nil {
@@ -2,6 +2,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -10,6 +11,7 @@ import (
 	"github.com/Vidkin/gophkeeper/app/server"
 	"github.com/Vidkin/gophkeeper/internal/handlers"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -26,8 +28,8 @@ func TestRegister(t *testing.T) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.LoggingInterceptor,
-			interceptors.HashInterceptor("defaultHashKey"),
-			interceptors.ValidateToken("JWTKey")))
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := server.GetTLSListener(
@@ -46,12 +48,12 @@ func TestRegister(t *testing.T) {
 	viper.Set("hash_key", "defaultHashKey")
 
 	t.Run("test register ok", func(t *testing.T) {
-		err = Register("test_login", "test_pass")
+		err = Register("test_login", "test_pass", "")
 		require.NoError(t, err)
 	})
 
 	t.Run("test register already exists", func(t *testing.T) {
-		err = Register("test_login", "test_pass")
+		err = Register("test_login", "test_pass", "")
 		require.ErrorContains(t, err, "already exists")
 	})
 }
@@ -0,0 +1,491 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// rekeyTimeout limits the duration of the calls made by RekeyVault, which transfer all the user data at once.
+const rekeyTimeout = 30 * time.Second
+
+// ErrRekeyKeyMismatch is returned when an interrupted rekey is resumed with a different new key.
+var ErrRekeyKeyMismatch = errors.New("an interrupted rekey to another key exists, repeat it with the same new master password or secret key")
+
+// errRekeyApplied is returned while re-encrypting the data if it is already encrypted with the new key,
+// which means that the server has applied the rekey, but the client has been interrupted before finishing it.
+var errRekeyApplied = errors.New("rekey is already applied")
+
+// rekeyJournal is the state of a rekey stored on the client, so that an interrupted rekey can be resumed
+// with the same new key, without uploading the already re-encrypted files again.
+type rekeyJournal struct {
+	Files map[string]stagedFile `json:"staged"`          // File names mapped to the staged uploads of their re-encrypted content
+	Check string                `json:"check"`           // A known value encrypted with the new key
+	Vault []byte                `json:"vault,omitempty"` // The marshaled parameters of the new vault, if the new key is derived from a master password
+}
+
+// stagedFile is the staged upload of a re-encrypted file together with the file it was made from.
+// The upload is only reused if the file still has the same ID and version.
+type stagedFile struct {
+	ObjectKey string `json:"object_key"` // The object key of the staged upload, empty if the file is kept as is
+	ID        int64  `json:"id"`         // The ID of the file
+	Version   int64  `json:"version"`    // The version of the file
+}
+
+// matches reports whether the staged upload has been made from the current content of the file.
+func (f stagedFile) matches(file *proto.File) bool {
+	return f.ID == file.Id && f.Version == file.Version
+}
+
+// loadRekeyJournal reads the journal of an interrupted rekey. It returns nil if there is no such rekey.
+func loadRekeyJournal() (*rekeyJournal, error) {
+	data, err := readStateFile(RekeyJournalFileName)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var j rekeyJournal
+	if err = json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	if j.Files == nil {
+		j.Files = make(map[string]stagedFile)
+	}
+	return &j, nil
+}
+
+// save writes the journal, replacing the previous state.
+func (j *rekeyJournal) save() error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return writeStateFile(RekeyJournalFileName, string(data))
+}
+
+// removeRekeyJournal deletes the journal of a finished rekey.
+func removeRekeyJournal() error {
+	return removeStateFiles(RekeyJournalFileName)
+}
+
+// rekeyTarget returns the new key and the new vault for a rekey, together with its journal.
+// A new journal is created and stored unless an interrupted rekey is resumed, in which case
+// the new key must be the key of the interrupted rekey.
+func rekeyTarget(newMaster, newSecretKey string) (string, *proto.Vault, *rekeyJournal, error) {
+	j, err := loadRekeyJournal()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if j != nil {
+		var vault *proto.Vault
+		key := newSecretKey
+		if len(j.Vault) != 0 {
+			if newMaster == "" {
+				return "", nil, nil, ErrRekeyKeyMismatch
+			}
+			vault = &proto.Vault{}
+			if err = pb.Unmarshal(j.Vault, vault); err != nil {
+				return "", nil, nil, err
+			}
+			if key, err = deriveVaultKey(newMaster, vault); err != nil {
+				if errors.Is(err, ErrWrongMasterPassword) {
+					return "", nil, nil, ErrRekeyKeyMismatch
+				}
+				return "", nil, nil, err
+			}
+		} else if newMaster != "" {
+			return "", nil, nil, ErrRekeyKeyMismatch
+		}
+		if text, err := aes.Decrypt(key, j.Check); err != nil || text != vaultVerifierText {
+			return "", nil, nil, ErrRekeyKeyMismatch
+		}
+		fmt.Println("Resuming the interrupted rekey")
+		return key, vault, j, nil
+	}
+
+	j = &rekeyJournal{Files: make(map[string]stagedFile)}
+	var vault *proto.Vault
+	key := newSecretKey
+	if newMaster != "" {
+		if vault, key, err = newVault(newMaster); err != nil {
+			return "", nil, nil, err
+		}
+		if j.Vault, err = pb.Marshal(vault); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if j.Check, err = aes.Encrypt(key, vaultVerifierText); err != nil {
+		return "", nil, nil, fmt.Errorf("invalid new secret key: %v", err)
+	}
+	if err = j.save(); err != nil {
+		return "", nil, nil, err
+	}
+	return key, vault, j, nil
+}
+
+// reencrypt decrypts the values with the old key and encrypts them with the new key in place.
+// It returns errRekeyApplied if the values are already encrypted with the new key.
+func reencrypt(oldKey, newKey string, values ...*string) error {
+	for _, v := range values {
+		text, err := aes.Decrypt(oldKey, *v)
+		if err != nil {
+			if _, errNew := aes.Decrypt(newKey, *v); errNew == nil {
+				return errRekeyApplied
+			}
+			return fmt.Errorf("failed to decrypt data, check secret key, original error: %v", err)
+		}
+		if *v, err = aes.Encrypt(newKey, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withToken adds the JWT token to the context.
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"token": token}))
+}
+
+// checkFileKey downloads a file and checks that it is encrypted with the key.
+func checkFileKey(ctx context.Context, client proto.GophkeeperClient, fileName, key string) error {
+	stream, err := client.Download(ctx, &proto.FileDownloadRequest{FileName: fileName})
+	if err != nil {
+		return err
+	}
+	decrypter := aes.NewDecryptWriter(io.Discard, key)
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = decrypter.Write(res.Chunk); err != nil {
+			return err
+		}
+	}
+	return decrypter.Close()
+}
+
+// rekeyFile streams a file from the server, decrypts it with the old key, encrypts it with the new key
+// and uploads the result as a staged upload.
+//
+// Returns:
+//   - The object key of the staged upload, or an empty string if the file is not encrypted and is kept as is.
+//   - errRekeyApplied if the file is already encrypted with the new key.
+//   - An error if any step in the process fails.
+func rekeyFile(ctx context.Context, client proto.GophkeeperClient, file *proto.File, oldKey, newKey string) (string, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	download, err := client.Download(streamCtx, &proto.FileDownloadRequest{FileName: file.FileName})
+	if err != nil {
+		return "", err
+	}
+	first, err := download.Recv()
+	if err == io.EOF {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !aes.IsEncryptedStream(first.Chunk) {
+		fmt.Printf("Warning: the file %s is not encrypted, it is kept as is\n", file.FileName)
+		return "", nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		decrypter := aes.NewDecryptWriter(pw, oldKey)
+		chunk := first.Chunk
+		for {
+			if _, err := decrypter.Write(chunk); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			res, err := download.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			chunk = res.Chunk
+		}
+		pw.CloseWithError(decrypter.Close())
+	}()
+	defer pr.Close()
+
+	reader, err := aes.NewEncryptReader(pr, newKey)
+	if err != nil {
+		return "", err
+	}
+
+	upload, err := client.Upload(streamCtx)
+	if err != nil {
+		return "", err
+	}
+	buffer := make([]byte, aes.StreamChunkSize)
+	for {
+		n, err := reader.Read(buffer)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, aes.ErrStreamAuthentication) {
+			cancel()
+			if checkFileKey(ctx, client, file.FileName, newKey) == nil {
+				return "", errRekeyApplied
+			}
+			return "", fmt.Errorf("failed to decrypt file %s, check secret key, original error: %v", file.FileName, err)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		err = upload.Send(&proto.FileUploadRequest{
+			FileName: file.FileName,
+			FileSize: file.FileSize,
+			Chunk:    buffer[:n],
+			Staged:   true,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	resp, err := upload.CloseAndRecv()
+	if err != nil {
+		return "", err
+	}
+	return resp.ObjectKey, nil
+}
+
+// RekeyVault re-encrypts all the bank cards, notes, credentials, TOTP authenticator entries, custom items,
+// folder and tag names, encrypted files and the private sharing key of the user under a new key and replaces them on the GophKeeper server in a single transaction.
+//
+// The current key is the secret key from the configuration (or the unlocked vault key). The new key is derived
+// from the new master password, or, if it is empty, the new secret key is used as is. Files are re-encrypted
+// one by one and uploaded as staged uploads first; the progress is stored in a journal, so an interrupted rekey
+// is resumed when the command is repeated with the same new master password or secret key.
+//
+// Parameters:
+//   - newMaster: The new master password, or an empty string to use newSecretKey.
+//   - newSecretKey: The new secret key, used if newMaster is empty.
+//
+// Returns:
+//   - ErrRekeyKeyMismatch if an interrupted rekey to another key exists.
+//   - An error if any step in the process fails, including JWT file access, decryption, gRPC communication,
+//     or if the items have been changed by another client in the meantime. The command can be repeated then.
+func RekeyVault(newMaster, newSecretKey string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	oldKey := viper.GetString("secret_key")
+	newKey, vault, journal, err := rekeyTarget(newMaster, newSecretKey)
+	if err != nil {
+		return err
+	}
+	if newKey == oldKey {
+		return errors.New("the new key must differ from the current one")
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	err = rekey(client, token, oldKey, newKey, vault, journal)
+	if errors.Is(err, errRekeyApplied) {
+		err = nil
+	}
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			switch e.Code() {
+			case codes.PermissionDenied:
+				return errors.New("need to re-authorize, call auth command")
+			case codes.FailedPrecondition:
+				return errors.New("the data has been changed by another client, run rekey again")
+			}
+		}
+		return err
+	}
+
+	if err = removeRekeyJournal(); err != nil {
+		return err
+	}
+	if vault == nil {
+		if err = removeVault(); err != nil {
+			return err
+		}
+		fmt.Println("Successfully rekey the vault! Set secret_key in the client configuration to the new key")
+		return nil
+	}
+	if err = saveVault(vault); err != nil {
+		return err
+	}
+	if err = writeStateFile(VaultKeyFileName, newKey); err != nil {
+		return err
+	}
+	fmt.Println("Successfully rekey the vault! Use the new master password from now on")
+	return nil
+}
+
+// rekey fetches all the user data, re-encrypts it and sends it back to the server.
+func rekey(client proto.GophkeeperClient, token, oldKey, newKey string, vault *proto.Vault, journal *rekeyJournal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rekeyTimeout)
+	defer cancel()
+
+	req := &proto.RekeyVaultRequest{Vault: vault}
+	ctxToken := withToken(ctx, token)
+	cards, err := client.GetBankCards(ctxToken, &proto.GetBankCardsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, card := range cards.Cards {
+		if err = reencrypt(oldKey, newKey, &card.Owner, &card.Number, &card.Cvv, &card.ExpireDate, &card.Description); err != nil {
+			return err
+		}
+	}
+	req.Cards = cards.Cards
+
+	notes, err := client.GetNotes(ctxToken, &proto.GetNotesRequest{})
+	if err != nil {
+		return err
+	}
+	for _, note := range notes.Notes {
+		if err = reencrypt(oldKey, newKey, &note.Text, &note.Description); err != nil {
+			return err
+		}
+	}
+	req.Notes = notes.Notes
+
+	creds, err := client.GetUserCredentials(ctxToken, &proto.GetUserCredentialsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, cred := range creds.Credentials {
+		if err = reencrypt(oldKey, newKey, &cred.Login, &cred.Password, &cred.Description); err != nil {
+			return err
+		}
+	}
+	req.Credentials = creds.Credentials
+
+	otpItems, err := client.GetOTPItems(ctxToken, &proto.GetOTPItemsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, item := range otpItems.Items {
+		if err = reencrypt(oldKey, newKey, &item.Secret, &item.Issuer, &item.Account, &item.Description); err != nil {
+			return err
+		}
+	}
+	req.OtpItems = otpItems.Items
+
+	customItems, err := client.GetCustomItems(ctxToken, &proto.GetCustomItemsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, item := range customItems.Items {
+		if err = reencrypt(oldKey, newKey, customItemValues(item)...); err != nil {
+			return err
+		}
+	}
+	req.CustomItems = customItems.Items
+
+	folders, err := client.GetFolders(ctxToken, &proto.GetFoldersRequest{})
+	if err != nil {
+		return err
+	}
+	for _, folder := range folders.Folders {
+		if err = reencrypt(oldKey, newKey, &folder.Name); err != nil {
+			return err
+		}
+	}
+	req.Folders = folders.Folders
+
+	tags, err := client.GetTags(ctxToken, &proto.GetTagsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags.Tags {
+		if err = reencrypt(oldKey, newKey, &tag.Name); err != nil {
+			return err
+		}
+	}
+	req.Tags = tags.Tags
+
+	keys, err := client.GetSharingKeys(ctxToken, &proto.GetSharingKeysRequest{})
+	switch {
+	case status.Code(err) == codes.NotFound:
+	case err != nil:
+		return err
+	default:
+		if err = reencrypt(oldKey, newKey, &keys.Keys.PrivateKey); err != nil {
+			return err
+		}
+		req.SharingPrivateKey = keys.Keys.PrivateKey
+	}
+
+	files, err := client.GetFiles(ctxToken, &proto.GetFilesRequest{})
+	if err != nil {
+		return err
+	}
+	streamCtx := withToken(context.Background(), token)
+	for _, file := range files.Files {
+		// A file uploaded or edited since it was staged is staged again, the stale upload is not referenced.
+		staged, ok := journal.Files[file.FileName]
+		if !ok || !staged.matches(file) {
+			objectKey, err := rekeyFile(streamCtx, client, file, oldKey, newKey)
+			if err != nil {
+				return err
+			}
+			staged = stagedFile{ObjectKey: objectKey, ID: file.Id, Version: file.Version}
+			journal.Files[file.FileName] = staged
+			if err = journal.save(); err != nil {
+				return err
+			}
+		}
+		req.Files = append(req.Files, &proto.RekeyFile{FileName: file.FileName, ObjectKey: staged.ObjectKey, Version: file.Version})
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), rekeyTimeout)
+	defer cancel()
+	_, err = client.RekeyVault(withToken(ctx, token), req)
+	if status.Code(err) == codes.FailedPrecondition {
+		// The staged uploads are consumed by the server, so if the rekey has been applied before the client
+		// was interrupted, the re-encrypted files from the journal can't be referenced again.
+		for _, file := range req.Files {
+			if file.ObjectKey != "" && checkFileKey(streamCtx, client, file.FileName, newKey) == nil {
+				return errRekeyApplied
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+const (
+	rekeyOldKey = "examplekey1234567890123456789011"
+	rekeyNewKey = "examplekey1234567890123456789012"
+)
+
+func TestRekeyTarget(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	t.Run("new secret key", func(t *testing.T) {
+		key, vault, j, err := rekeyTarget("", rekeyNewKey)
+		require.NoError(t, err)
+		assert.Equal(t, rekeyNewKey, key)
+		assert.Nil(t, vault)
+		j.Files["file.txt"] = stagedFile{ObjectKey: "1/staged", ID: 1, Version: 2}
+		require.NoError(t, j.save())
+
+		_, _, _, err = rekeyTarget("", rekeyOldKey)
+		assert.ErrorIs(t, err, ErrRekeyKeyMismatch)
+		_, _, _, err = rekeyTarget("master", "")
+		assert.ErrorIs(t, err, ErrRekeyKeyMismatch)
+
+		key, _, j, err = rekeyTarget("", rekeyNewKey)
+		require.NoError(t, err)
+		assert.Equal(t, rekeyNewKey, key)
+		staged := j.Files["file.txt"]
+		assert.Equal(t, "1/staged", staged.ObjectKey)
+		assert.True(t, staged.matches(&proto.File{Id: 1, Version: 2}))
+		assert.False(t, staged.matches(&proto.File{Id: 1, Version: 3}))
+		assert.False(t, staged.matches(&proto.File{Id: 4, Version: 2}))
+		require.NoError(t, removeRekeyJournal())
+	})
+
+	t.Run("new master password", func(t *testing.T) {
+		key, vault, _, err := rekeyTarget("master", "")
+		require.NoError(t, err)
+		require.NotNil(t, vault)
+
+		_, _, _, err = rekeyTarget("other", "")
+		assert.ErrorIs(t, err, ErrRekeyKeyMismatch)
+		_, _, _, err = rekeyTarget("", rekeyNewKey)
+		assert.ErrorIs(t, err, ErrRekeyKeyMismatch)
+
+		resumed, resumedVault, _, err := rekeyTarget("master", "")
+		require.NoError(t, err)
+		assert.Equal(t, key, resumed)
+		assert.Equal(t, vault.Salt, resumedVault.Salt)
+		require.NoError(t, removeRekeyJournal())
+	})
+
+	t.Run("invalid secret key", func(t *testing.T) {
+		_, _, _, err := rekeyTarget("", "short")
+		assert.Error(t, err)
+	})
+}
+
+func TestReencrypt(t *testing.T) {
+	encrypted, err := aes.Encrypt(rekeyOldKey, "secret")
+	require.NoError(t, err)
+
+	value := encrypted
+	require.NoError(t, reencrypt(rekeyOldKey, rekeyNewKey, &value))
+	text, err := aes.Decrypt(rekeyNewKey, value)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", text)
+
+	assert.ErrorIs(t, reencrypt(rekeyOldKey, rekeyNewKey, &value), errRekeyApplied)
+
+	other, err := aes.Encrypt("examplekey1234567890123456789013", "secret")
+	require.NoError(t, err)
+	err = reencrypt(rekeyOldKey, rekeyNewKey, &other)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errRekeyApplied)
+}
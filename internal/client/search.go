@@ -0,0 +1,506 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/text/unicode/norm"
+	"google.golang.org/grpc"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/fuzzy"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// searchTimeout limits the duration of the calls made by Search, which fetch all the items of the user.
+const searchTimeout = 10 * time.Second
+
+// snippetLength is the maximum number of the characters of a matched value shown in the search results.
+const snippetLength = 60
+
+// SearchItemTypes are the types of the items Search looks through.
+var SearchItemTypes = []string{"card", "credentials", "note", "file", "otp", "custom"}
+
+// SearchOptions configures a search.
+type SearchOptions struct {
+	Types    []string // The types of the items to search, all the types if empty
+	Limit    int      // The maximum number of the results, unlimited if zero
+	UseIndex bool     // Whether the decrypted items are cached in the local encrypted search index
+}
+
+// searchField is a searchable value of an item. The secrets, such as passwords, card numbers and the values
+// of the secret fields of the custom items, are never searchable.
+type searchField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// searchDoc is the decrypted searchable content of an item.
+type searchDoc struct {
+	Type    string        `json:"type"`
+	ID      int64         `json:"id"`
+	Version int64         `json:"version"`
+	Title   string        `json:"title"`
+	Fields  []searchField `json:"fields"`
+}
+
+// key returns the key of the document in the search index.
+func (d *searchDoc) key() string {
+	return fmt.Sprintf("%s/%d", d.Type, d.ID)
+}
+
+// searchIndex is the local cache of the decrypted items, an item is decrypted again only if its version changes.
+type searchIndex struct {
+	Docs map[string]*searchDoc `json:"docs"`
+}
+
+// searchResult is an item matching a search query.
+type searchResult struct {
+	doc     *searchDoc
+	score   int
+	matched []searchField
+}
+
+// loadSearchIndex reads the search index and decrypts it with the key. A missing index, or an index
+// that can't be decrypted, for example, because the vault has been rekeyed, is replaced with an empty one.
+func loadSearchIndex(key string) *searchIndex {
+	index := &searchIndex{Docs: make(map[string]*searchDoc)}
+	data, err := readStateFile(SearchIndexFileName)
+	if err != nil {
+		return index
+	}
+	text, err := aes.Decrypt(key, string(data))
+	if err != nil {
+		return index
+	}
+	var stored searchIndex
+	if err = json.Unmarshal([]byte(text), &stored); err != nil || stored.Docs == nil {
+		return index
+	}
+	return &stored
+}
+
+// save encrypts the search index with the key and writes it, replacing the previous index.
+func (index *searchIndex) save(key string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	encrypted, err := aes.Encrypt(key, string(data))
+	if err != nil {
+		return err
+	}
+	return writeStateFile(SearchIndexFileName, encrypted)
+}
+
+// cached returns the indexed document of the item if it has been indexed at the version.
+func (index *searchIndex) cached(itemType string, id, version int64) *searchDoc {
+	doc, ok := index.Docs[fmt.Sprintf("%s/%d", itemType, id)]
+	if !ok || version == 0 || doc.Version != version {
+		return nil
+	}
+	return doc
+}
+
+// newSearchDoc returns the document of an item with the non-empty fields.
+func newSearchDoc(itemType string, id, version int64, title string, fields ...searchField) *searchDoc {
+	doc := &searchDoc{Type: itemType, ID: id, Version: version, Title: title}
+	for _, f := range fields {
+		if strings.TrimSpace(f.Value) != "" {
+			doc.Fields = append(doc.Fields, f)
+		}
+	}
+	return doc
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// firstLine returns the first line of a text.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+// cardDoc decrypts the searchable values of a bank card.
+func cardDoc(key string, card *proto.BankCard) (*searchDoc, error) {
+	if err := decryptValues(key, &card.Owner, &card.Description); err != nil {
+		return nil, err
+	}
+	return newSearchDoc("card", card.Id, card.Version, firstNonEmpty(card.Description, card.Owner),
+		searchField{Name: "owner", Value: card.Owner},
+		searchField{Name: "description", Value: card.Description}), nil
+}
+
+// credentialsDoc decrypts the searchable values of credentials.
+func credentialsDoc(key string, cred *proto.Credentials) (*searchDoc, error) {
+	if err := decryptValues(key, &cred.Login, &cred.Description); err != nil {
+		return nil, err
+	}
+	return newSearchDoc("credentials", cred.Id, cred.Version, firstNonEmpty(cred.Login, cred.Description),
+		searchField{Name: "login", Value: cred.Login},
+		searchField{Name: "description", Value: cred.Description}), nil
+}
+
+// noteDoc decrypts the searchable values of a note.
+func noteDoc(key string, note *proto.Note) (*searchDoc, error) {
+	if err := decryptValues(key, &note.Text, &note.Description); err != nil {
+		return nil, err
+	}
+	return newSearchDoc("note", note.Id, note.Version, firstNonEmpty(note.Description, firstLine(note.Text)),
+		searchField{Name: "description", Value: note.Description},
+		searchField{Name: "text", Value: note.Text}), nil
+}
+
+// fileDoc returns the searchable values of a file, which are not encrypted.
+func fileDoc(file *proto.File) *searchDoc {
+	name := norm.NFC.String(file.FileName)
+	return newSearchDoc("file", file.Id, file.Version, name,
+		searchField{Name: "file name", Value: name},
+		searchField{Name: "description", Value: file.Description})
+}
+
+// otpDoc decrypts the searchable values of a TOTP authenticator entry, the secret is not searchable.
+func otpDoc(key string, item *proto.OTPItem) (*searchDoc, error) {
+	if err := decryptValues(key, &item.Issuer, &item.Account, &item.Description); err != nil {
+		return nil, err
+	}
+	title := strings.Trim(item.Issuer+":"+item.Account, ":")
+	return newSearchDoc("otp", item.Id, item.Version, firstNonEmpty(title, item.Description),
+		searchField{Name: "issuer", Value: item.Issuer},
+		searchField{Name: "account", Value: item.Account},
+		searchField{Name: "description", Value: item.Description}), nil
+}
+
+// customItemDoc decrypts the searchable values of a custom item, the values of the secret fields are not searchable.
+func customItemDoc(key string, item *proto.CustomItem) (*searchDoc, error) {
+	if err := decryptCustomItem(key, item); err != nil {
+		return nil, err
+	}
+	fields := []searchField{{Name: "name", Value: item.Name}, {Name: "description", Value: item.Description}}
+	for _, f := range item.Fields {
+		if f.Kind != itemtype.KindSecret {
+			fields = append(fields, searchField{Name: f.Name, Value: f.Value})
+		}
+	}
+	return newSearchDoc("custom", item.Id, item.Version, firstNonEmpty(item.Name, item.Template), fields...), nil
+}
+
+// fetchSearchDocs retrieves the personal items of the types and returns their documents. The items indexed
+// at their current versions are taken from the index, the other items are decrypted.
+func fetchSearchDocs(ctx context.Context, client proto.GophkeeperClient, key string, types map[string]bool, index *searchIndex) ([]*searchDoc, error) {
+	var docs []*searchDoc
+	add := func(doc *searchDoc, err error) error {
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	}
+
+	if types["card"] {
+		resp, err := client.GetBankCards(ctx, &proto.GetBankCardsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range resp.Cards {
+			if doc := index.cached("card", card.Id, card.Version); doc != nil {
+				docs = append(docs, doc)
+				continue
+			}
+			if err = add(cardDoc(key, card)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if types["credentials"] {
+		resp, err := client.GetUserCredentials(ctx, &proto.GetUserCredentialsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, cred := range resp.Credentials {
+			if doc := index.cached("credentials", cred.Id, cred.Version); doc != nil {
+				docs = append(docs, doc)
+				continue
+			}
+			if err = add(credentialsDoc(key, cred)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if types["note"] {
+		resp, err := client.GetNotes(ctx, &proto.GetNotesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range resp.Notes {
+			if doc := index.cached("note", note.Id, note.Version); doc != nil {
+				docs = append(docs, doc)
+				continue
+			}
+			if err = add(noteDoc(key, note)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if types["file"] {
+		resp, err := client.GetFiles(ctx, &proto.GetFilesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range resp.Files {
+			docs = append(docs, fileDoc(file))
+		}
+	}
+	if types["otp"] {
+		resp, err := client.GetOTPItems(ctx, &proto.GetOTPItemsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			if doc := index.cached("otp", item.Id, item.Version); doc != nil {
+				docs = append(docs, doc)
+				continue
+			}
+			if err = add(otpDoc(key, item)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if types["custom"] {
+		resp, err := client.GetCustomItems(ctx, &proto.GetCustomItemsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			if doc := index.cached("custom", item.Id, item.Version); doc != nil {
+				docs = append(docs, doc)
+				continue
+			}
+			if err = add(customItemDoc(key, item)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return docs, nil
+}
+
+// labelFields returns the folder path and the tags of the item as searchable values.
+func labelFields(folders *folderTree, tags *tagSet, doc *searchDoc) []searchField {
+	ref := itemRef{itemType: doc.Type, id: doc.ID}
+	var fields []searchField
+	if folderID, ok := folders.items[ref]; ok {
+		fields = append(fields, searchField{Name: "folder", Value: folders.path(folderID)})
+	}
+	for _, tagID := range tags.items[ref] {
+		fields = append(fields, searchField{Name: "tag", Value: tags.tags[tagID]})
+	}
+	return fields
+}
+
+// rankSearchDocs returns the documents matching every term of the query, the best matches first.
+// A term may match any of the fields of a document, the score of a document is the sum of the best
+// scores of the terms. The labels function returns the additional fields of a document.
+func rankSearchDocs(docs []*searchDoc, query string, labels func(doc *searchDoc) []searchField) []searchResult {
+	terms := fuzzy.Terms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []searchResult
+	for _, doc := range docs {
+		fields := doc.Fields
+		if labels != nil {
+			fields = append(fields[:len(fields):len(fields)], labels(doc)...)
+		}
+		result := searchResult{doc: doc}
+		for _, term := range terms {
+			best, bestField := 0, -1
+			for i, f := range fields {
+				if score := fuzzy.Score(term, f.Value); score > best {
+					best, bestField = score, i
+				}
+			}
+			if best == 0 {
+				result.score = 0
+				break
+			}
+			result.score += best
+			if !containsField(result.matched, fields[bestField]) {
+				result.matched = append(result.matched, fields[bestField])
+			}
+		}
+		if result.score > 0 {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		if results[i].doc.Type != results[j].doc.Type {
+			return results[i].doc.Type < results[j].doc.Type
+		}
+		return results[i].doc.ID < results[j].doc.ID
+	})
+	return results
+}
+
+// containsField reports whether the fields contain the field.
+func containsField(fields []searchField, field searchField) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// snippet returns a value shortened to a single line of snippetLength characters at most.
+func snippet(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > snippetLength {
+		return string(runes[:snippetLength]) + "..."
+	}
+	return value
+}
+
+// formatSearchResults returns the search results, one result per line with its type, ID and the matched values.
+func formatSearchResults(results []searchResult) string {
+	var b strings.Builder
+	for i, r := range results {
+		matched := make([]string, len(r.matched))
+		for j, f := range r.matched {
+			matched[j] = fmt.Sprintf("%s=%q", f.Name, snippet(f.Value))
+		}
+		fmt.Fprintf(&b, "%d. [%s] id=%d, %s (score %d, matched: %s)\n",
+			i+1, r.doc.Type, r.doc.ID, snippet(r.doc.Title), r.score, strings.Join(matched, ", "))
+	}
+	return b.String()
+}
+
+// Search fetches the personal items of the user, decrypts them and prints the items matching the query,
+// the best matches first. The query terms are matched fuzzily against the descriptions, the logins,
+// the card owners, the note texts, the file names, the OTP issuers and accounts, the non-secret fields
+// of the custom items, and the folders and tags of the items. The passwords and the other secrets are
+// never searched.
+//
+// With the index option the decrypted items are cached in a local search index encrypted with the vault key,
+// so that repeated searches only decrypt the items added or changed since the previous search.
+//
+// Parameters:
+//   - query: The search query, an item must match all its terms.
+//   - opts: The types of the items to search, the maximum number of the results and whether to use the index.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func Search(query string, opts SearchOptions) error {
+	if len(fuzzy.Terms(query)) == 0 {
+		return errors.New("you must provide the search query")
+	}
+	types := make(map[string]bool)
+	for _, t := range opts.Types {
+		types[t] = true
+	}
+	for t := range types {
+		if !containsString(SearchItemTypes, t) {
+			return fmt.Errorf("unknown item type %s, use one of: %s", t, strings.Join(SearchItemTypes, ", "))
+		}
+	}
+	if len(types) == 0 {
+		for _, t := range SearchItemTypes {
+			types[t] = true
+		}
+	}
+
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	defer cancel()
+	ctx = withToken(ctx, token)
+
+	key := viper.GetString("secret_key")
+	index := &searchIndex{Docs: make(map[string]*searchDoc)}
+	if opts.UseIndex {
+		index = loadSearchIndex(key)
+	}
+
+	docs, err := fetchSearchDocs(ctx, client, key, types, index)
+	if err != nil {
+		return labelsError(err)
+	}
+	folders, tags, err := loadLabels(ctx, client)
+	if err != nil {
+		return labelsError(err)
+	}
+
+	if opts.UseIndex {
+		// The documents of the types that haven't been searched are kept, the removed items are dropped.
+		for k, doc := range index.Docs {
+			if types[doc.Type] {
+				delete(index.Docs, k)
+			}
+		}
+		for _, doc := range docs {
+			if doc.Type != "file" {
+				index.Docs[doc.key()] = doc
+			}
+		}
+		if err = index.save(key); err != nil {
+			return err
+		}
+	}
+
+	results := rankSearchDocs(docs, query, func(doc *searchDoc) []searchField {
+		return labelFields(folders, tags, doc)
+	})
+	if len(results) == 0 {
+		fmt.Println("Nothing found")
+		return nil
+	}
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	fmt.Println("Search results:")
+	fmt.Print(formatSearchResults(results))
+	return nil
+}
+
+// containsString reports whether the values contain the value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
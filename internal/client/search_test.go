@@ -0,0 +1,85 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestSearch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	key := "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+	encrypt := func(text string) string {
+		encrypted, err := aes.Encrypt(key, text)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	cred, err := credentialsDoc(key, &proto.Credentials{
+		Id: 1, Version: 2, Login: encrypt("admin@github.com"), Password: encrypt("secret"), Description: encrypt("GitHub account"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "admin@github.com", cred.Title)
+	for _, f := range cred.Fields {
+		assert.NotEqual(t, "secret", f.Value)
+	}
+
+	note, err := noteDoc(key, &proto.Note{Id: 1, Version: 1, Text: encrypt("Wi-Fi at home\nrouter password is on the box"), Description: encrypt("")})
+	require.NoError(t, err)
+	assert.Equal(t, "Wi-Fi at home", note.Title)
+	assert.Len(t, note.Fields, 1)
+
+	custom, err := customItemDoc(key, &proto.CustomItem{
+		Id: 3, Version: 1, Template: encrypt("api-token"), Name: encrypt("Deploy token"), Description: encrypt(""),
+		Fields: []*proto.ItemField{
+			{Name: "service", Kind: itemtype.KindText, Value: encrypt("github")},
+			{Name: "token", Kind: itemtype.KindSecret, Value: encrypt("ghp_123")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []searchField{{Name: "name", Value: "Deploy token"}, {Name: "service", Value: "github"}}, custom.Fields)
+
+	card, err := cardDoc(key, &proto.BankCard{Id: 2, Version: 1, Owner: encrypt("IVAN IVANOV"), Description: encrypt("salary card")})
+	require.NoError(t, err)
+
+	docs := []*searchDoc{note, card, custom, cred}
+	results := rankSearchDocs(docs, "GitHub", nil)
+	require.Len(t, results, 2)
+	assert.Equal(t, cred, results[0].doc)
+	assert.Equal(t, custom, results[1].doc)
+
+	assert.Len(t, rankSearchDocs(docs, "ghp_123", nil), 0)
+	assert.Len(t, rankSearchDocs(docs, "github salary", nil), 0)
+
+	results = rankSearchDocs(docs, "salary", func(doc *searchDoc) []searchField {
+		if doc.Type == "credentials" {
+			return []searchField{{Name: "tag", Value: "salary"}}
+		}
+		return nil
+	})
+	require.Len(t, results, 2)
+	assert.Equal(t, card, results[0].doc)
+	assert.Equal(t, cred, results[1].doc)
+	assert.Equal(t, []searchField{{Name: "tag", Value: "salary"}}, results[1].matched)
+	assert.Len(t, cred.Fields, 2)
+
+	results = rankSearchDocs(docs, "ivanov salery", nil)
+	require.Len(t, results, 1)
+	assert.Equal(t, card, results[0].doc)
+	assert.Equal(t, "1. [card] id=2, salary card (score 140, matched: owner=\"IVAN IVANOV\", description=\"salary card\")\n",
+		formatSearchResults(results))
+
+	index := &searchIndex{Docs: map[string]*searchDoc{cred.key(): cred, note.key(): note}}
+	require.NoError(t, index.save(key))
+	loaded := loadSearchIndex(key)
+	assert.Equal(t, index, loaded)
+	assert.Equal(t, cred, loaded.cached("credentials", 1, 2))
+	assert.Nil(t, loaded.cached("credentials", 1, 3))
+	assert.Nil(t, loaded.cached("note", 2, 1))
+	assert.Empty(t, loadSearchIndex("anotherKey2Ks5nM2J5JaI59PPEhL1xx").Docs)
+}
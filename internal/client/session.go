@@ -0,0 +1,334 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	jwtPKG "github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// tokenRefreshMargin is how long before its expiration a JWT token is refreshed.
+const tokenRefreshMargin = 30 * time.Second
+
+// loadToken reads the JWT token from the state file. If the token is about to expire and a refresh
+// token is available, the tokens are refreshed first, so that the user doesn't need to call the auth
+// command again until the session itself expires or is revoked.
+//
+// Returns:
+//   - The JWT token.
+//   - An error if the token file can't be read.
+//
+// If the refresh fails, the stored token is returned as is and the server decides whether it is still valid.
+func loadToken() (string, error) {
+	f, err := readStateFile(TokenFileName)
+	if err != nil {
+		return "", fmt.Errorf("error open JWT file, need to authorize: %v", err)
+	}
+	token := string(f)
+
+	claims := &jwtPKG.Claims{}
+	if _, _, err = jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return token, nil
+	}
+	if claims.ExpiresAt != nil && time.Until(claims.ExpiresAt.Time) > tokenRefreshMargin {
+		return token, nil
+	}
+
+	refreshToken, err := readStateFile(RefreshTokenFileName)
+	if err != nil {
+		return token, nil
+	}
+	newToken, err := refreshTokens(string(refreshToken))
+	if err != nil {
+		return token, nil
+	}
+	return newToken, nil
+}
+
+// refreshTokens exchanges the refresh token for a new pair of tokens and stores them.
+//
+// Parameters:
+//   - refreshToken: The current refresh token.
+//
+// Returns:
+//   - The new JWT token.
+//   - An error if any step in the process fails, including gRPC communication or file operations.
+func refreshTokens(refreshToken string) (string, error) {
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return "", err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.RefreshTokenRequest{RefreshToken: refreshToken}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	resp, err := client.RefreshToken(ctxTimeout, req)
+	if err != nil {
+		return "", err
+	}
+
+	if err = saveTokens(resp.Token, resp.RefreshToken); err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+// saveTokens stores the JWT token and the refresh token in the state files.
+func saveTokens(token, refreshToken string) error {
+	if err := writeStateFile(TokenFileName, token); err != nil {
+		return err
+	}
+	return writeStateFile(RefreshTokenFileName, refreshToken)
+}
+
+// removeTokens deletes the state files with the JWT token, the refresh token, the vault key and the search index.
+func removeTokens() error {
+	return removeStateFiles(TokenFileName, RefreshTokenFileName, VaultFileName, VaultKeyFileName, SearchIndexFileName)
+}
+
+// stateDir returns the directory of the state files, creating it if needed. The directory is kept in the user
+// cache directory rather than in the shared temporary directory, and is only accessible to its owner, so that
+// other local users can't read the stored secrets or plant the files in advance.
+func stateDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user cache directory: %w", err)
+	}
+	dir := filepath.Join(base, StateDirName)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	if info.Mode().Perm() != 0700 {
+		if err = os.Chmod(dir, 0700); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// statePath returns the path of a state file.
+func statePath(name string) (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// readStateFile returns the content of a state file.
+func readStateFile(name string) ([]byte, error) {
+	p, err := statePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+// writeStateFile replaces a state file with the data. The data is written to a new file created with mode 0600
+// and renamed over the previous one, so an existing file or symlink is never written through.
+func writeStateFile(name, data string) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(data)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(dir, name))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// removeStateFiles deletes the state files, the missing files are skipped.
+func removeStateFiles(names ...string) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err = os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Logout revokes the current session on the GophKeeper server and removes the stored tokens.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or file operations.
+//
+// The stored tokens are removed even if the session has already been revoked or has expired.
+func Logout() error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.LogoutRequest{}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.Logout(ctxTimeout, req)
+	if err != nil {
+		if e, ok := status.FromError(err); !ok || (e.Code() != codes.PermissionDenied && e.Code() != codes.NotFound) {
+			return err
+		}
+	}
+
+	if err = removeTokens(); err != nil {
+		return err
+	}
+
+	fmt.Println("Successfully logged out!")
+	return nil
+}
+
+// ListSessions retrieves all active sessions of the user from the GophKeeper server and prints them.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func ListSessions() error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.ListSessionsRequest{}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.ListSessions(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	for _, s := range resp.Sessions {
+		current := ""
+		if s.Current {
+			current = " (current)"
+		}
+		fmt.Printf("id=%d, device=%s, version=%s, address=%s, created=%s, last_seen=%s%s\n",
+			s.Id, s.DeviceName, s.ClientVersion, s.PeerAddress, s.CreatedAt, s.LastSeenAt, current)
+	}
+	return err
+}
+
+// RevokeSession revokes a session of the user on the GophKeeper server.
+//
+// Parameters:
+//   - sessionID: The ID of the session to revoke.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func RevokeSession(sessionID int64) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.RevokeSessionRequest{Id: sessionID}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.RevokeSession(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Session has been successfully revoked")
+	return err
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateFiles(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	dir := filepath.Join(cache, StateDirName)
+	require.NoError(t, os.Mkdir(dir, 0755))
+
+	// A file planted in place of the vault key must be replaced, not written through.
+	target := filepath.Join(t.TempDir(), "stolen")
+	require.NoError(t, os.WriteFile(target, nil, 0644))
+	require.NoError(t, os.Symlink(target, filepath.Join(dir, VaultKeyFileName)))
+
+	require.NoError(t, writeStateFile(VaultKeyFileName, "key"))
+	stolen, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Empty(t, stolen)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	info, err = os.Lstat(filepath.Join(dir, VaultKeyFileName))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	key, err := LoadVaultKey()
+	require.NoError(t, err)
+	assert.Equal(t, "key", key)
+
+	require.NoError(t, removeVault())
+	_, err = LoadVaultKey()
+	assert.True(t, os.IsNotExist(err))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
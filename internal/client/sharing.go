@@ -0,0 +1,426 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/sharing"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// sharingTimeout limits the duration of the calls made by the sharing commands, which make several calls each.
+const sharingTimeout = 5 * time.Second
+
+// errNoSharingKeys is returned when a user has no sharing keys yet.
+var errNoSharingKeys = errors.New("the user has no sharing keys yet, they must run the share list command once")
+
+// sharingKeys returns the public key and the private key the user shares items with. The keys are generated
+// and stored on the server on the first use, the private key is encrypted with the vault key.
+func sharingKeys(ctx context.Context, client proto.GophkeeperClient, vaultKey string) ([]byte, []byte, error) {
+	resp, err := client.GetSharingKeys(ctx, &proto.GetSharingKeysRequest{})
+	if status.Code(err) == codes.NotFound {
+		public, private, err := sharing.GenerateKeyPair()
+		if err != nil {
+			return nil, nil, err
+		}
+		encrypted, err := aes.Encrypt(vaultKey, base64.StdEncoding.EncodeToString(private))
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = client.SetSharingKeys(ctx, &proto.SetSharingKeysRequest{
+			Keys: &proto.SharingKeys{PublicKey: public, PrivateKey: encrypted},
+		})
+		if status.Code(err) != codes.AlreadyExists {
+			return public, private, err
+		}
+		// The keys have just been generated by another client.
+		resp, err = client.GetSharingKeys(ctx, &proto.GetSharingKeysRequest{})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	private, err := decryptSharingKey(vaultKey, resp.Keys.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The public key is derived from the private one rather than trusted as stored on the server.
+	public, err := sharing.PublicKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+	return public, private, nil
+}
+
+// decryptSharingKey decrypts the private sharing key with the vault key.
+func decryptSharingKey(vaultKey, encrypted string) ([]byte, error) {
+	text, err := aes.Decrypt(vaultKey, encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt sharing key, check secret key, original error: %v", err)
+	}
+	return base64.StdEncoding.DecodeString(text)
+}
+
+// decryptValues decrypts the values with the key in place.
+func decryptValues(key string, values ...*string) error {
+	for _, v := range values {
+		text, err := aes.Decrypt(key, *v)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt data, check secret key, original error: %v", err)
+		}
+		*v = text
+	}
+	return nil
+}
+
+// sharedItemData fetches an item of the user, decrypts it with the vault key and encrypts it with the data key.
+func sharedItemData(ctx context.Context, client proto.GophkeeperClient, itemType string, itemID int64, vaultKey, dataKey string) (string, error) {
+	var item pb.Message
+	id := strconv.FormatInt(itemID, 10)
+	switch itemType {
+	case "card":
+		resp, err := client.GetBankCard(ctx, &proto.GetBankCardRequest{Id: id})
+		if err != nil {
+			return "", err
+		}
+		c := resp.Card
+		if err = decryptValues(vaultKey, &c.Number, &c.ExpireDate, &c.Cvv, &c.Owner, &c.Description); err != nil {
+			return "", err
+		}
+		item = c
+	case "credentials":
+		resp, err := client.GetUserCredential(ctx, &proto.GetUserCredentialRequest{Id: id})
+		if err != nil {
+			return "", err
+		}
+		c := resp.Credentials
+		if err = decryptValues(vaultKey, &c.Login, &c.Password, &c.Description); err != nil {
+			return "", err
+		}
+		item = c
+	case "note":
+		resp, err := client.GetNote(ctx, &proto.GetNoteRequest{Id: id})
+		if err != nil {
+			return "", err
+		}
+		n := resp.Note
+		if err = decryptValues(vaultKey, &n.Text, &n.Description); err != nil {
+			return "", err
+		}
+		item = n
+	default:
+		return "", fmt.Errorf("unknown item type %q, use card, credentials or note", itemType)
+	}
+
+	return encryptSharedItem(dataKey, item)
+}
+
+// encryptSharedItem encrypts a decrypted item with the data key of its shared copy.
+func encryptSharedItem(dataKey string, item pb.Message) (string, error) {
+	data, err := pb.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+	return aes.Encrypt(dataKey, string(data))
+}
+
+// sharedDataKey returns the data key of the shared copy of an item of the user, or an empty string if the item isn't shared.
+func sharedDataKey(ctx context.Context, client proto.GophkeeperClient, itemType string, itemID int64) (string, error) {
+	shares, err := client.GetItemShares(ctx, &proto.GetItemSharesRequest{ItemType: itemType, ItemId: itemID})
+	if status.Code(err) == codes.NotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	_, private, err := sharingKeys(ctx, client, viper.GetString("secret_key"))
+	if err != nil {
+		return "", err
+	}
+	return sharing.UnwrapKey(private, shares.OwnerKey)
+}
+
+// sharingError converts the errors of the sharing calls to the messages for the user.
+func sharingError(err error) error {
+	if e, ok := status.FromError(err); ok {
+		switch e.Code() {
+		case codes.PermissionDenied:
+			return errors.New("need to re-authorize, call auth command")
+		case codes.FailedPrecondition:
+			return errors.New("the item has been shared or unshared by another client, try again")
+		}
+	}
+	return err
+}
+
+// ShareItem gives another user access to a bank card, credentials or a note.
+//
+// If the item isn't shared yet, it is encrypted with a new random data key, otherwise the data key of the shared
+// copy is unwrapped with the private sharing key of the user. The data key is wrapped with the public key of the
+// recipient, so only the recipient can decrypt the item.
+//
+// Parameters:
+//   - itemType: The type of the item: "card", "credentials" or "note".
+//   - itemID: The ID of the item.
+//   - recipient: The login of the recipient.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, encryption, gRPC communication,
+//     or if the recipient has no sharing keys yet.
+func ShareItem(itemType string, itemID int64, recipient string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharingTimeout)
+	defer cancel()
+	ctx = withToken(ctx, token)
+
+	if err = shareItem(ctx, client, itemType, itemID, recipient); err != nil {
+		return sharingError(err)
+	}
+
+	fmt.Printf("Successfully shared the %s with %s!\n", itemType, recipient)
+	return nil
+}
+
+func shareItem(ctx context.Context, client proto.GophkeeperClient, itemType string, itemID int64, recipient string) error {
+	vaultKey := viper.GetString("secret_key")
+	public, private, err := sharingKeys(ctx, client, vaultKey)
+	if err != nil {
+		return err
+	}
+
+	recipientKey, err := client.GetPublicKey(ctx, &proto.GetPublicKeyRequest{Login: recipient})
+	if status.Code(err) == codes.NotFound {
+		return errNoSharingKeys
+	}
+	if err != nil {
+		return err
+	}
+
+	req := &proto.ShareItemRequest{ItemType: itemType, ItemId: itemID}
+	var dataKey string
+	shares, err := client.GetItemShares(ctx, &proto.GetItemSharesRequest{ItemType: itemType, ItemId: itemID})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		if dataKey, err = sharing.NewDataKey(); err != nil {
+			return err
+		}
+		if req.Data, err = sharedItemData(ctx, client, itemType, itemID, vaultKey, dataKey); err != nil {
+			return err
+		}
+		if req.OwnerKey, err = sharing.WrapKey(public, dataKey); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		if dataKey, err = sharing.UnwrapKey(private, shares.OwnerKey); err != nil {
+			return err
+		}
+	}
+
+	wrapped, err := sharing.WrapKey(recipientKey.PublicKey, dataKey)
+	if err != nil {
+		return err
+	}
+	req.Share = &proto.ItemShare{RecipientLogin: recipient, WrappedKey: wrapped}
+	_, err = client.ShareItem(ctx, req)
+	return err
+}
+
+// ListSharedWithMe retrieves the items other users have shared with the user, decrypts them and prints them.
+// The sharing keys of the user are generated on the first call, so that other users can share items with the user.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func ListSharedWithMe() error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharingTimeout)
+	defer cancel()
+	ctx = withToken(ctx, token)
+
+	_, private, err := sharingKeys(ctx, client, viper.GetString("secret_key"))
+	if err != nil {
+		return sharingError(err)
+	}
+	resp, err := client.ListSharedWithMe(ctx, &proto.ListSharedWithMeRequest{})
+	if err != nil {
+		return sharingError(err)
+	}
+
+	fmt.Println("Shared with me:")
+	for _, item := range resp.Items {
+		text, err := openSharedItem(private, item)
+		if err != nil {
+			fmt.Printf("id=%d, owner=%s, type=%s: failed to decrypt: %v\n", item.Id, item.OwnerLogin, item.ItemType, err)
+			continue
+		}
+		fmt.Printf("id=%d, owner=%s, type=%s, %s\n", item.Id, item.OwnerLogin, item.ItemType, text)
+	}
+	return nil
+}
+
+// openSharedItem decrypts an item shared with the user and formats it for display.
+func openSharedItem(private []byte, item *proto.SharedItem) (string, error) {
+	dataKey, err := sharing.UnwrapKey(private, item.WrappedKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := aes.Decrypt(dataKey, item.Data)
+	if err != nil {
+		return "", err
+	}
+
+	switch item.ItemType {
+	case "card":
+		var c proto.BankCard
+		if err = pb.Unmarshal([]byte(data), &c); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("number=%s, owner=%s, cvv=%s, expire=%s, description=%s",
+			c.Number, c.Owner, c.Cvv, c.ExpireDate, c.Description), nil
+	case "credentials":
+		var c proto.Credentials
+		if err = pb.Unmarshal([]byte(data), &c); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("login=%s, password=%s, description=%s", c.Login, c.Password, c.Description), nil
+	case "note":
+		var n proto.Note
+		if err = pb.Unmarshal([]byte(data), &n); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("text=%s, description=%s", n.Text, n.Description), nil
+	}
+	return "", fmt.Errorf("unknown item type %q", item.ItemType)
+}
+
+// RevokeShare revokes the access of another user to a bank card, credentials or a note.
+//
+// The revoked user may have kept the data key of the item, so the item is re-encrypted with a new data key,
+// which is wrapped for the user and every remaining recipient.
+//
+// Parameters:
+//   - itemType: The type of the item: "card", "credentials" or "note".
+//   - itemID: The ID of the item.
+//   - recipient: The login of the revoked recipient.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, encryption, gRPC communication,
+//     or if the item isn't shared with the recipient.
+func RevokeShare(itemType string, itemID int64, recipient string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharingTimeout)
+	defer cancel()
+	ctx = withToken(ctx, token)
+
+	if err = revokeShare(ctx, client, itemType, itemID, recipient); err != nil {
+		return sharingError(err)
+	}
+
+	fmt.Printf("Successfully revoked the access of %s to the %s\n", recipient, itemType)
+	return nil
+}
+
+func revokeShare(ctx context.Context, client proto.GophkeeperClient, itemType string, itemID int64, recipient string) error {
+	vaultKey := viper.GetString("secret_key")
+	shares, err := client.GetItemShares(ctx, &proto.GetItemSharesRequest{ItemType: itemType, ItemId: itemID})
+	if status.Code(err) == codes.NotFound {
+		return errors.New("the item isn't shared")
+	}
+	if err != nil {
+		return err
+	}
+
+	req := &proto.RevokeShareRequest{ItemType: itemType, ItemId: itemID, RecipientLogin: recipient}
+	var remaining []*proto.ItemShare
+	for _, share := range shares.Shares {
+		if share.RecipientLogin != recipient {
+			remaining = append(remaining, share)
+		}
+	}
+	if len(remaining) == len(shares.Shares) {
+		return fmt.Errorf("the item isn't shared with %s", recipient)
+	}
+
+	if len(remaining) != 0 {
+		public, _, err := sharingKeys(ctx, client, vaultKey)
+		if err != nil {
+			return err
+		}
+		dataKey, err := sharing.NewDataKey()
+		if err != nil {
+			return err
+		}
+		if req.Data, err = sharedItemData(ctx, client, itemType, itemID, vaultKey, dataKey); err != nil {
+			return err
+		}
+		if req.OwnerKey, err = sharing.WrapKey(public, dataKey); err != nil {
+			return err
+		}
+		for _, share := range remaining {
+			wrapped, err := sharing.WrapKey(share.PublicKey, dataKey)
+			if err != nil {
+				return err
+			}
+			req.Shares = append(req.Shares, &proto.ItemShare{RecipientLogin: share.RecipientLogin, WrappedKey: wrapped})
+		}
+	}
+
+	_, err = client.RevokeShare(ctx, req)
+	return err
+}
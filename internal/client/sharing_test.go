@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/app/server"
+	"github.com/Vidkin/gophkeeper/internal/handlers"
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/sharing"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestSharing(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &handlers.GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptors.LoggingInterceptor,
+			interceptors.SignatureInterceptor("defaultHashKey", time.Minute),
+			interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := server.GetTLSListener(
+		"127.0.0.1:8080",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	const secretKey = "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+	viper.Set("address", "127.0.0.1:8080")
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	viper.Set("hash_key", "defaultHashKey")
+	viper.Set("secret_key", secretKey)
+
+	ctxs := make(map[string]context.Context)
+	for _, login := range []string{"bob", "carol", "dave", "alice"} {
+		require.NoError(t, Register(login, "pass", ""))
+		require.NoError(t, Auth(login, "pass", ""))
+		token, err := loadToken()
+		require.NoError(t, err)
+		ctxs[login] = withToken(context.Background(), token)
+	}
+	client, conn, err := NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, bobPrivate, err := sharingKeys(ctxs["bob"], client, secretKey)
+	require.NoError(t, err)
+	_, carolPrivate, err := sharingKeys(ctxs["carol"], client, secretKey)
+	require.NoError(t, err)
+
+	// The tokens of alice are stored the last.
+	require.NoError(t, AddCard(&proto.BankCard{Number: "4111111111111111", ExpireDate: "12/30", Cvv: "123", Owner: "alice"}, 0))
+
+	t.Run("test share item: recipient without keys", func(t *testing.T) {
+		err := shareItem(ctxs["alice"], client, "card", 1, "dave")
+		assert.ErrorIs(t, err, errNoSharingKeys)
+	})
+
+	t.Run("test share item: with yourself", func(t *testing.T) {
+		_, _, err := sharingKeys(ctxs["alice"], client, secretKey)
+		require.NoError(t, err)
+		err = shareItem(ctxs["alice"], client, "card", 1, "alice")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test share item: another user's item", func(t *testing.T) {
+		err := shareItem(ctxs["bob"], client, "card", 1, "carol")
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	var bobWrappedKey string
+	t.Run("test share item: ok", func(t *testing.T) {
+		require.NoError(t, ShareItem("card", 1, "bob"))
+		require.NoError(t, ShareItem("card", 1, "carol"))
+
+		resp, err := client.ListSharedWithMe(ctxs["bob"], &proto.ListSharedWithMeRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, "alice", resp.Items[0].OwnerLogin)
+		text, err := openSharedItem(bobPrivate, resp.Items[0])
+		require.NoError(t, err)
+		assert.Contains(t, text, "number=4111111111111111")
+		bobWrappedKey = resp.Items[0].WrappedKey
+
+		// The item is stored encrypted, the data key doesn't leave the clients unwrapped.
+		shared, err := storage.GetSharedItem(context.Background(), 4, "card", 1)
+		require.NoError(t, err)
+		assert.NotContains(t, shared.Data, "4111111111111111")
+		assert.Len(t, shared.Shares, 2)
+	})
+
+	t.Run("test edit shared item", func(t *testing.T) {
+		require.NoError(t, EditCard(1, 0, func(card *proto.BankCard) { card.Owner = "ALICE" }))
+
+		resp, err := client.ListSharedWithMe(ctxs["bob"], &proto.ListSharedWithMeRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		text, err := openSharedItem(bobPrivate, resp.Items[0])
+		require.NoError(t, err)
+		assert.Contains(t, text, "number=4111111111111111, owner=ALICE")
+
+		// An edit that doesn't update the shared copy is rejected.
+		card, err := client.GetBankCard(ctxs["alice"], &proto.GetBankCardRequest{Id: "1"})
+		require.NoError(t, err)
+		_, err = client.UpdateBankCard(ctxs["alice"], &proto.UpdateBankCardRequest{Card: card.Card})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("test revoke share: ok", func(t *testing.T) {
+		require.NoError(t, RevokeShare("card", 1, "bob"))
+
+		resp, err := client.ListSharedWithMe(ctxs["bob"], &proto.ListSharedWithMeRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+
+		resp, err = client.ListSharedWithMe(ctxs["carol"], &proto.ListSharedWithMeRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		_, err = openSharedItem(carolPrivate, resp.Items[0])
+		require.NoError(t, err)
+
+		// The item has been re-keyed, the data key bob may have kept doesn't decrypt it anymore.
+		oldDataKey, err := sharing.UnwrapKey(bobPrivate, bobWrappedKey)
+		require.NoError(t, err)
+		_, err = aes.Decrypt(oldDataKey, resp.Items[0].Data)
+		assert.Error(t, err)
+	})
+
+	t.Run("test revoke share: not shared with the user", func(t *testing.T) {
+		err := RevokeShare("card", 1, "bob")
+		assert.ErrorContains(t, err, "isn't shared with bob")
+	})
+
+	t.Run("test revoke share: last recipient", func(t *testing.T) {
+		require.NoError(t, RevokeShare("card", 1, "carol"))
+		_, err := client.GetItemShares(ctxs["alice"], &proto.GetItemSharesRequest{ItemType: "card", ItemId: 1})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test remove shared item", func(t *testing.T) {
+		require.NoError(t, ShareItem("card", 1, "bob"))
+		require.NoError(t, RemoveCard(1, 0))
+
+		resp, err := client.ListSharedWithMe(ctxs["bob"], &proto.ListSharedWithMeRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+	})
+}
+
+func TestOpenSharedItem(t *testing.T) {
+	public, private, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	dataKey, err := sharing.NewDataKey()
+	require.NoError(t, err)
+	wrapped, err := sharing.WrapKey(public, dataKey)
+	require.NoError(t, err)
+
+	data, err := pb.Marshal(&proto.Note{Text: "shared text", Description: "description"})
+	require.NoError(t, err)
+	encrypted, err := aes.Encrypt(dataKey, string(data))
+	require.NoError(t, err)
+
+	text, err := openSharedItem(private, &proto.SharedItem{ItemType: "note", Data: encrypted, WrappedKey: wrapped})
+	require.NoError(t, err)
+	assert.Equal(t, "text=shared text, description=description", text)
+
+	_, err = openSharedItem(private, &proto.SharedItem{ItemType: "file", Data: encrypted, WrappedKey: wrapped})
+	assert.Error(t, err)
+
+	_, otherPrivate, err := sharing.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = openSharedItem(otherPrivate, &proto.SharedItem{ItemType: "note", Data: encrypted, WrappedKey: wrapped})
+	assert.ErrorIs(t, err, sharing.ErrUnwrap)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	pb "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/signature"
+)
+
+// signRequest adds the signature of a request to the outgoing metadata.
+//
+// Returns:
+//   - The context with the signature and the nonce of the request.
+//   - An error if the nonce could not be generated.
+func signRequest(ctx context.Context, key, method string, body []byte) (context.Context, string, error) {
+	nonce, err := signature.NewNonce()
+	if err != nil {
+		return nil, "", err
+	}
+	timestamp := time.Now().Unix()
+	sig := signature.Sign(key, method, timestamp, nonce, body)
+	ctx = metadata.AppendToOutgoingContext(ctx,
+		signature.VersionKey, signature.Version,
+		signature.TimestampKey, strconv.FormatInt(timestamp, 10),
+		signature.NonceKey, nonce,
+		signature.SignatureKey, base64.StdEncoding.EncodeToString(sig))
+	return ctx, nonce, nil
+}
+
+// signUnary is a gRPC unary client interceptor that signs every request with the hash_key, if it is configured.
+func signUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	key := viper.GetString("hash_key")
+	if key == "" {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	msg, ok := req.(pb.Message)
+	if !ok {
+		return errors.New("failed to get proto.Message")
+	}
+	body, err := pb.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	ctx, _, err = signRequest(ctx, key, method, body)
+	if err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// signStream is a gRPC stream client interceptor that signs every stream and every message sent in it
+// with the hash_key, if it is configured.
+func signStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	key := viper.GetString("hash_key")
+	if key == "" {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	ctx, nonce, err := signRequest(ctx, key, method, nil)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &signedStream{ClientStream: cs, key: key, nonce: nonce}, nil
+}
+
+// signedStream is a grpc.ClientStream signing every sent message with the nonce of the stream and the message index.
+type signedStream struct {
+	grpc.ClientStream
+	key   string
+	nonce string
+	index int
+}
+
+// SendMsg signs the message and sends it.
+func (s *signedStream) SendMsg(m interface{}) error {
+	msg, ok := m.(pb.Message)
+	if !ok {
+		return errors.New("failed to get proto.Message")
+	}
+	field := msg.ProtoReflect().Descriptor().Fields().ByName(interceptors.SignatureFieldName)
+	if field == nil || field.Kind() != protoreflect.BytesKind {
+		return errors.New("streamed message has no signature field")
+	}
+	msg.ProtoReflect().Clear(field)
+	body, err := pb.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	sig := signature.SignMessage(s.key, s.nonce, s.index, body)
+	msg.ProtoReflect().Set(field, protoreflect.ValueOfBytes(sig))
+	s.index++
+	return s.ClientStream.SendMsg(m)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// TagItem tags a personal item, the tag is created if the user has no tag with the name yet.
+// The name of the tag is encrypted with the secret key.
+//
+// Parameters:
+//   - itemType: The type of the item: "card", "credentials", "note", "file", "otp" or "custom".
+//   - itemID: The ID of the item.
+//   - name: The name of the tag.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication,
+//     or if the item is not found.
+func TagItem(itemType string, itemID int64, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("you must provide the tag name")
+	}
+
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, _ *folderTree, tags *tagSet) error {
+		tagID, ok := tags.find(name)
+		if !ok {
+			encrypted, err := aes.Encrypt(viper.GetString("secret_key"), name)
+			if err != nil {
+				return err
+			}
+			resp, err := client.CreateTag(ctx, &proto.CreateTagRequest{Name: encrypted})
+			if err != nil {
+				return err
+			}
+			tagID = resp.Id
+		}
+		_, err := client.TagItem(ctx, &proto.TagItemRequest{ItemType: itemType, ItemId: itemID, TagId: tagID})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Successfully tag the %s with %s!\n", itemType, name)
+		return nil
+	})
+}
+
+// UntagItem removes a tag from a personal item, the tag itself is kept.
+//
+// Parameters:
+//   - itemType: The type of the item: "card", "credentials", "note", "file", "otp" or "custom".
+//   - itemID: The ID of the item.
+//   - name: The name of the tag.
+//
+// Returns:
+//   - ErrTagNotFound if the tag doesn't exist, or an error if any step in the process fails,
+//     including JWT file access, gRPC communication, or if the item isn't tagged with the tag.
+func UntagItem(itemType string, itemID int64, name string) error {
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, _ *folderTree, tags *tagSet) error {
+		tagID, ok := tags.find(name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+		}
+		_, err := client.UntagItem(ctx, &proto.UntagItemRequest{ItemType: itemType, ItemId: itemID, TagId: tagID})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Tag has been successfully removed from the item")
+		return nil
+	})
+}
+
+// RenameTag changes the name of a tag.
+//
+// Parameters:
+//   - name: The name of the tag.
+//   - newName: The new name of the tag.
+//
+// Returns:
+//   - ErrTagNotFound if the tag doesn't exist, ErrTagExists if another tag has the new name,
+//     or an error if any step in the process fails.
+func RenameTag(name, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return errors.New("you must provide the new tag name")
+	}
+
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, _ *folderTree, tags *tagSet) error {
+		tagID, ok := tags.find(name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+		}
+		if existing, ok := tags.find(newName); ok && existing != tagID {
+			return fmt.Errorf("%w: %s", ErrTagExists, newName)
+		}
+		encrypted, err := aes.Encrypt(viper.GetString("secret_key"), newName)
+		if err != nil {
+			return err
+		}
+		if _, err = client.RenameTag(ctx, &proto.RenameTagRequest{Id: tagID, Name: encrypted}); err != nil {
+			return err
+		}
+		fmt.Println("Tag has been successfully renamed")
+		return nil
+	})
+}
+
+// RemoveTag removes a tag from all the items and deletes it.
+//
+// Parameters:
+//   - name: The name of the tag.
+//
+// Returns:
+//   - ErrTagNotFound if the tag doesn't exist, or an error if any step in the process fails.
+func RemoveTag(name string) error {
+	return withLabels(func(ctx context.Context, client proto.GophkeeperClient, _ *folderTree, tags *tagSet) error {
+		tagID, ok := tags.find(name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+		}
+		if _, err := client.RemoveTag(ctx, &proto.RemoveTagRequest{Id: tagID}); err != nil {
+			return err
+		}
+		fmt.Println("Tag has been successfully removed")
+		return nil
+	})
+}
+
+// GetAllTags prints the tags of the user with the number of the items tagged with every tag.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or decryption.
+func GetAllTags() error {
+	return withLabels(func(_ context.Context, _ proto.GophkeeperClient, _ *folderTree, tags *tagSet) error {
+		counts := make(map[int64]int)
+		for _, ids := range tags.items {
+			for _, id := range ids {
+				counts[id]++
+			}
+		}
+		ids := make([]int64, 0, len(tags.tags))
+		for id := range tags.tags {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return tags.tags[ids[i]] < tags.tags[ids[j]] })
+
+		fmt.Println("Tags:")
+		for _, id := range ids {
+			fmt.Printf("%s (items: %d)\n", tags.tags[id], counts[id])
+		}
+		return nil
+	})
+}
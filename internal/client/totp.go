@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// EnrollTOTP starts the enrollment of two-factor authentication and prints the new TOTP secret
+// together with its otpauth:// URI, which can be imported into an authenticator app.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func EnrollTOTP() error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.EnrollTOTPRequest{}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.EnrollTOTP(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Add this secret to your authenticator app:")
+	fmt.Printf("secret=%s\nuri=%s\n", resp.Secret, resp.Uri)
+	return err
+}
+
+// ConfirmTOTP confirms the enrollment of two-factor authentication with a code from the authenticator app
+// and prints the recovery codes.
+//
+// Parameters:
+//   - code: The current code from the authenticator app.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func ConfirmTOTP(code string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.ConfirmTOTPRequest{Code: code}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.ConfirmTOTP(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Two-factor authentication has been successfully enabled!")
+	printRecoveryCodes(resp.RecoveryCodes)
+	return err
+}
+
+// DisableTOTP turns off two-factor authentication.
+//
+// Parameters:
+//   - code: A code from the authenticator app or a recovery code.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func DisableTOTP(code string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.DisableTOTPRequest{Code: code}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	_, err = client.DisableTOTP(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != "invalid second factor code" {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	fmt.Println("Two-factor authentication has been successfully disabled")
+	return err
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes with a new set and prints them.
+//
+// Parameters:
+//   - code: A code from the authenticator app or a recovery code.
+//
+// Returns:
+//   - An error if any step in the process fails, including JWT file access, gRPC communication, or authorization issues.
+func RegenerateRecoveryCodes(code string) error {
+	token, err := loadToken()
+	if err != nil {
+		return err
+	}
+
+	client, conn, err := NewGophkeeperClient()
+	if err != nil {
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err = conn.Close()
+		if err != nil {
+			fmt.Println("failed to close grpc connection")
+		}
+	}(conn)
+
+	req := &proto.RegenerateRecoveryCodesRequest{Code: code}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	md := metadata.New(map[string]string{"token": token})
+	ctxTimeout = metadata.NewOutgoingContext(ctxTimeout, md)
+
+	resp, err := client.RegenerateRecoveryCodes(ctxTimeout, req)
+
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.PermissionDenied && e.Message() != "invalid second factor code" {
+				return errors.New("need to re-authorize, call auth command")
+			}
+		}
+		return err
+	}
+
+	printRecoveryCodes(resp.RecoveryCodes)
+	return err
+}
+
+func printRecoveryCodes(recoveryCodes []string) {
+	fmt.Println("Recovery codes (each code can be used only once, keep them in a safe place):")
+	for _, code := range recoveryCodes {
+		fmt.Println(code)
+	}
+}
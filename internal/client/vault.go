@@ -0,0 +1,147 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/password"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// vaultVerifierText is the known plaintext encrypted with the vault key to produce the verifier.
+const vaultVerifierText = "gophkeeper vault key verifier"
+
+// ErrWrongMasterPassword is returned when the key derived from the master password doesn't match the vault verifier.
+var ErrWrongMasterPassword = errors.New("wrong master password")
+
+// ErrNoVault is returned by UnlockVault when the user has no master password vault.
+var ErrNoVault = errors.New("vault is not set up for the user, use the secret_key setting")
+
+// newVault generates a new salt for the master password and returns the vault parameters,
+// including the verifier of the derived key, together with the key itself.
+func newVault(master string) (*proto.Vault, string, error) {
+	p := password.DefaultParams
+	salt, err := password.NewSalt(p)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := password.DeriveKey(master, salt, p)
+	if err != nil {
+		return nil, "", err
+	}
+	verifier, err := aes.Encrypt(string(key), vaultVerifierText)
+	if err != nil {
+		return nil, "", err
+	}
+	return &proto.Vault{
+		Salt:        salt,
+		Memory:      p.Memory,
+		Iterations:  p.Iterations,
+		Parallelism: uint32(p.Parallelism),
+		Verifier:    verifier,
+	}, string(key), nil
+}
+
+// deriveVaultKey derives the vault key from the master password and checks it against the verifier.
+func deriveVaultKey(master string, v *proto.Vault) (string, error) {
+	if v.Parallelism > math.MaxUint8 {
+		return "", password.ErrWeakKDFParams
+	}
+	key, err := password.DeriveKey(master, v.Salt, &password.Params{
+		Memory:      v.Memory,
+		Iterations:  v.Iterations,
+		Parallelism: uint8(v.Parallelism),
+	})
+	if err != nil {
+		return "", err
+	}
+	text, err := aes.Decrypt(string(key), v.Verifier)
+	if err != nil || text != vaultVerifierText {
+		return "", ErrWrongMasterPassword
+	}
+	return string(key), nil
+}
+
+// saveVault stores the vault parameters received at login, so that the vault can be unlocked later.
+// The previously unlocked vault key is removed. A nil vault removes the stored parameters as well.
+func saveVault(v *proto.Vault) error {
+	if err := removeVault(); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	data, err := pb.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeStateFile(VaultFileName, string(data))
+}
+
+// removeVault deletes the state files with the vault parameters and the vault key.
+func removeVault() error {
+	return removeStateFiles(VaultFileName, VaultKeyFileName)
+}
+
+// HasVault reports whether the authorized user has a master password vault.
+func HasVault() bool {
+	p, err := statePath(VaultFileName)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+// UnlockVault derives the vault key from the master password and stores it until logout,
+// so that other commands can encrypt and decrypt the user data.
+//
+// Parameters:
+//   - master: The master password of the user.
+//
+// Returns:
+//   - ErrNoVault if the user has no master password vault or hasn't been authorized.
+//   - ErrWrongMasterPassword if the master password is wrong.
+//   - An error if the vault parameters are invalid or the key could not be stored.
+func UnlockVault(master string) error {
+	data, err := readStateFile(VaultFileName)
+	if os.IsNotExist(err) {
+		return ErrNoVault
+	}
+	if err != nil {
+		return err
+	}
+
+	var v proto.Vault
+	if err = pb.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	key, err := deriveVaultKey(master, &v)
+	if err != nil {
+		return err
+	}
+	if err = writeStateFile(VaultKeyFileName, key); err != nil {
+		return err
+	}
+
+	fmt.Println("Vault unlocked!")
+	return nil
+}
+
+// LoadVaultKey returns the vault key stored by UnlockVault.
+//
+// Returns:
+//   - The vault key.
+//   - An error if the vault hasn't been unlocked.
+func LoadVaultKey() (string, error) {
+	key, err := readStateFile(VaultKeyFileName)
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/pkg/password"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestVaultKey(t *testing.T) {
+	vault, key, err := newVault("master")
+	require.NoError(t, err)
+	assert.Len(t, key, password.KeyLength)
+	assert.NotEmpty(t, vault.Verifier)
+
+	t.Run("derive with the right master password", func(t *testing.T) {
+		derived, err := deriveVaultKey("master", vault)
+		require.NoError(t, err)
+		assert.Equal(t, key, derived)
+	})
+
+	t.Run("derive with a wrong master password", func(t *testing.T) {
+		_, err := deriveVaultKey("wrong", vault)
+		assert.ErrorIs(t, err, ErrWrongMasterPassword)
+	})
+
+	t.Run("weak parameters are rejected", func(t *testing.T) {
+		weak := pb.Clone(vault).(*proto.Vault)
+		weak.Memory = 8
+		_, err := deriveVaultKey("master", weak)
+		assert.ErrorIs(t, err, password.ErrWeakKDFParams)
+
+		weak = pb.Clone(vault).(*proto.Vault)
+		weak.Parallelism = 1 << 10
+		_, err = deriveVaultKey("master", weak)
+		assert.ErrorIs(t, err, password.ErrWeakKDFParams)
+	})
+
+	t.Run("each vault gets its own salt", func(t *testing.T) {
+		other, otherKey, err := newVault("master")
+		require.NoError(t, err)
+		assert.NotEqual(t, vault.Salt, other.Salt)
+		assert.NotEqual(t, key, otherKey)
+	})
+}
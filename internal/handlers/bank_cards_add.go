@@ -27,6 +27,8 @@ import (
 //
 // The function validates the input fields and logs an error if any required field is missing.
 // It then creates a model.BankCard instance and attempts to add it to the storage.
+//
+// A non-zero collection ID adds the card to the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) AddBankCard(ctx context.Context, in *proto.AddBankCardRequest) (*emptypb.Empty, error) {
 	if in.Card.Cvv == "" || in.Card.ExpireDate == "" || in.Card.Number == "" || in.Card.Owner == "" {
 		logger.Log.Error("you must provide: CVV, expire date, card number, card owner")
@@ -34,7 +36,6 @@ func (g *GophkeeperServer) AddBankCard(ctx context.Context, in *proto.AddBankCar
 	}
 
 	card := &model.BankCard{
-		UserID:      ctx.Value(interceptors.UserID).(int64),
 		CVV:         in.Card.Cvv,
 		Owner:       in.Card.Owner,
 		Number:      in.Card.Number,
@@ -42,6 +43,15 @@ func (g *GophkeeperServer) AddBankCard(ctx context.Context, in *proto.AddBankCar
 		Description: in.Card.Description,
 	}
 
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		card.CollectionID = in.CollectionId
+	} else {
+		card.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
 	if err := g.Storage.AddCard(ctx, card); err != nil {
 		logger.Log.Error("error add bank card", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error add bank card")
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
@@ -26,10 +28,21 @@ import (
 // The function fetches the user's bank cards from the storage and constructs a response
 // containing the card details. If an error occurs during the retrieval, it logs the error
 // and returns an appropriate gRPC status code.
-func (g *GophkeeperServer) GetBankCards(ctx context.Context, _ *proto.GetBankCardsRequest) (*proto.GetBankCardsResponse, error) {
+//
+// A non-zero collection ID retrieves the cards of the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetBankCards(ctx context.Context, in *proto.GetBankCardsRequest) (*proto.GetBankCardsResponse, error) {
 	var response proto.GetBankCardsResponse
 
-	cards, err := g.Storage.GetBankCards(ctx, ctx.Value(interceptors.UserID).(int64))
+	var cards []*model.BankCard
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		cards, err = g.Storage.GetCollectionBankCards(ctx, in.CollectionId)
+	} else {
+		cards, err = g.Storage.GetBankCards(ctx, ctx.Value(interceptors.UserID).(int64))
+	}
 	if err != nil {
 		logger.Log.Error("error get bank cards from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get bank cards from DB")
@@ -44,6 +57,8 @@ func (g *GophkeeperServer) GetBankCards(ctx context.Context, _ *proto.GetBankCar
 			Cvv:         card.CVV,
 			Description: card.Description,
 			Id:          card.ID,
+			Version:     card.Version,
+			UpdatedAt:   card.UpdatedAt.Format(time.DateTime),
 		}
 	}
 	response.Cards = protoCards
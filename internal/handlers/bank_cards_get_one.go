@@ -2,13 +2,18 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -24,8 +29,10 @@ import (
 //     an internal error while retrieving the card from the storage.
 //
 // The function converts the card ID from a string to an integer and fetches the corresponding
-// bank card from the storage. If an error occurs during the retrieval, it logs the error
-// and returns an appropriate gRPC status code.
+// bank card owned by the current user from the storage. If an error occurs during the retrieval,
+// it logs the error and returns an appropriate gRPC status code (NotFound for unknown or foreign cards).
+//
+// A non-zero collection ID looks the card up in the organization collection instead, which requires the viewer role.
 func (g *GophkeeperServer) GetBankCard(ctx context.Context, in *proto.GetBankCardRequest) (*proto.GetBankCardResponse, error) {
 	cardID, err := strconv.Atoi(in.Id)
 	if err != nil {
@@ -33,7 +40,19 @@ func (g *GophkeeperServer) GetBankCard(ctx context.Context, in *proto.GetBankCar
 	}
 	var response proto.GetBankCardResponse
 
-	card, err := g.Storage.GetBankCard(ctx, int64(cardID))
+	var card *model.BankCard
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		card, err = g.Storage.GetCollectionBankCard(ctx, in.CollectionId, int64(cardID))
+	} else {
+		card, err = g.Storage.GetBankCard(ctx, ctx.Value(interceptors.UserID).(int64), int64(cardID))
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("bank card not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "bank card not found")
+	}
 	if err != nil {
 		logger.Log.Error("error get bank card from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get bank card from DB")
@@ -46,6 +65,8 @@ func (g *GophkeeperServer) GetBankCard(ctx context.Context, in *proto.GetBankCar
 		Cvv:         card.CVV,
 		Description: card.Description,
 		Id:          card.ID,
+		Version:     card.Version,
+		UpdatedAt:   card.UpdatedAt.Format(time.DateTime),
 	}
 	response.Card = protoCard
 	return &response, nil
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"go.uber.org/zap"
@@ -10,6 +12,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -22,11 +26,13 @@ import (
 // Returns:
 //   - An empty response (emptypb.Empty) if the operation is successful.
 //   - An error if the operation fails, for example, if the provided ID is missing or invalid,
-//     or if there is an internal error while removing the card from the storage.
+//     if the card is not found among the user's cards, or if there is an internal error while removing the card from the storage.
 //
 // The function validates the input ID, converts it to an integer, and attempts to remove the
 // corresponding bank card from the storage. If an error occurs during the removal, it logs the
 // error and returns an appropriate gRPC status code.
+//
+// A non-zero collection ID removes the card from the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) RemoveBankCard(ctx context.Context, in *proto.RemoveBankCardRequest) (*emptypb.Empty, error) {
 	if in.Id == "" {
 		logger.Log.Error("you must provide card id")
@@ -39,7 +45,19 @@ func (g *GophkeeperServer) RemoveBankCard(ctx context.Context, in *proto.RemoveB
 		return nil, status.Errorf(codes.InvalidArgument, "invalid card id")
 	}
 
-	if err = g.Storage.RemoveBankCard(ctx, cardID); err != nil {
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		err = g.Storage.RemoveCollectionBankCard(ctx, in.CollectionId, cardID)
+	} else {
+		err = g.Storage.RemoveBankCard(ctx, ctx.Value(interceptors.UserID).(int64), cardID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("bank card not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "bank card not found")
+	}
+	if err != nil {
 		logger.Log.Error("error remove bank card", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error remove bank card")
 	}
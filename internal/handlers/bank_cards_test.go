@@ -10,10 +10,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -27,7 +30,7 @@ func TestBankCards(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
@@ -99,7 +102,7 @@ func TestBankCards(t *testing.T) {
 
 	t.Run("test get bank card: unknown card id", func(t *testing.T) {
 		_, err = client.GetBankCard(ctx, &proto.GetBankCardRequest{Id: "435"})
-		require.ErrorContains(t, err, "error get bank card from DB")
+		require.ErrorContains(t, err, "bank card not found")
 	})
 
 	t.Run("test get bank card: ok", func(t *testing.T) {
@@ -112,6 +115,34 @@ func TestBankCards(t *testing.T) {
 		assert.Equal(t, card.Cvv, resp.Card.Cvv)
 	})
 
+	t.Run("test update bank card: missing version", func(t *testing.T) {
+		_, err = client.UpdateBankCard(ctx, &proto.UpdateBankCardRequest{Card: &proto.BankCard{Id: 1, Number: "7654321", ExpireDate: "12.07.2030", Cvv: "321", Owner: "new owner"}})
+		require.ErrorContains(t, err, "you must provide: id, version, CVV, expire date, card number, card owner")
+	})
+
+	t.Run("test update bank card: unknown id", func(t *testing.T) {
+		_, err = client.UpdateBankCard(ctx, &proto.UpdateBankCardRequest{Card: &proto.BankCard{Id: 435, Version: 1, Number: "7654321", ExpireDate: "12.07.2030", Cvv: "321", Owner: "new owner"}})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test update bank card: ok", func(t *testing.T) {
+		resp, err := client.UpdateBankCard(ctx, &proto.UpdateBankCardRequest{Card: &proto.BankCard{Id: 1, Version: 1, Number: "7654321", ExpireDate: "12.07.2030", Cvv: "321", Owner: "new owner"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Version)
+
+		got, err := client.GetBankCard(ctx, &proto.GetBankCardRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, "7654321", got.Card.Number)
+		assert.Equal(t, "new owner", got.Card.Owner)
+		assert.Empty(t, got.Card.Description)
+		assert.Equal(t, int64(2), got.Card.Version)
+	})
+
+	t.Run("test update bank card: stale version", func(t *testing.T) {
+		_, err = client.UpdateBankCard(ctx, &proto.UpdateBankCardRequest{Card: &proto.BankCard{Id: 1, Version: 1, Number: "1111111", ExpireDate: "12.07.2030", Cvv: "321", Owner: "owner"}})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
 	t.Run("test remove bank card: empty id", func(t *testing.T) {
 		_, err = client.RemoveBankCard(ctx, &proto.RemoveBankCardRequest{Id: ""})
 		require.ErrorContains(t, err, "you must provide card id")
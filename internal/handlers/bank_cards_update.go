@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UpdateBankCard replaces the bank card with the given ID by the new version sent by the client.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UpdateBankCardRequest structure containing the new bank card details and the version they are based on.
+//
+// Returns:
+//   - A pointer to the proto.UpdateBankCardResponse containing the new version of the bank card.
+//   - An error if the operation fails, for example, if required fields are missing, if the bank card is not found
+//     among the user's bank cards, if the bank card has been changed by another client since the version was read
+//     (Aborted), or if there is an internal error while updating the bank card in the storage.
+//
+// A non-zero collection ID updates the bank card of the organization collection instead, which requires the editor role.
+// If the personal bank card is shared, the request must carry the changed shared copy, which replaces the old one,
+// otherwise the update is rejected (FailedPrecondition), so that the recipients never keep a stale copy.
+func (g *GophkeeperServer) UpdateBankCard(ctx context.Context, in *proto.UpdateBankCardRequest) (*proto.UpdateBankCardResponse, error) {
+	if in.Card == nil || in.Card.Id == 0 || in.Card.Version == 0 || in.Card.Cvv == "" || in.Card.ExpireDate == "" || in.Card.Number == "" || in.Card.Owner == "" {
+		logger.Log.Error("you must provide: id, version, CVV, expire date, card number, card owner")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: id, version, CVV, expire date, card number, card owner")
+	}
+
+	card := &model.BankCard{
+		ID:          in.Card.Id,
+		Version:     in.Card.Version,
+		CVV:         in.Card.Cvv,
+		Owner:       in.Card.Owner,
+		Number:      in.Card.Number,
+		ExpireDate:  in.Card.ExpireDate,
+		Description: in.Card.Description,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		card.CollectionID = in.CollectionId
+	} else {
+		card.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	version, err := g.Storage.UpdateBankCard(ctx, card, in.SharedData)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Log.Error("bank card not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "bank card not found")
+	case errors.Is(err, storage.ErrVersionConflict):
+		logger.Log.Error("bank card version conflict", zap.Error(err))
+		return nil, status.Errorf(codes.Aborted, "bank card has been changed by another client, get it and try again")
+	case errors.Is(err, storage.ErrSharedItemChanged):
+		logger.Log.Error("shared bank card changed", zap.Error(err))
+		return nil, status.Errorf(codes.FailedPrecondition, "bank card sharing has changed, get it and try again")
+	case err != nil:
+		logger.Log.Error("error update bank card", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error update bank card")
+	}
+	return &proto.UpdateBankCardResponse{Version: version}, nil
+}
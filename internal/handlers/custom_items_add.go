@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// customItemFields checks the names and the kinds of the fields of a client-provided custom item
+// and converts the fields to the model form. The values are encrypted and can't be checked.
+func customItemFields(fields []*proto.ItemField) ([]model.ItemField, error) {
+	if len(fields) == 0 || len(fields) > itemtype.MaxFields {
+		return nil, fmt.Errorf("%w: an item must have from 1 to %d fields", itemtype.ErrInvalidTemplate, itemtype.MaxFields)
+	}
+	types := make([]itemtype.Field, len(fields))
+	result := make([]model.ItemField, len(fields))
+	for i, f := range fields {
+		types[i] = itemtype.Field{Name: f.Name, Kind: f.Kind}
+		result[i] = model.ItemField{Name: f.Name, Kind: f.Kind, Value: f.Value}
+	}
+	if err := itemtype.ValidateFields(types); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// matchesTemplate reports whether the fields have the names and the kinds of the fields of the template in its order.
+func matchesTemplate(fields []model.ItemField, template itemtype.Template) bool {
+	if len(fields) != len(template.Fields) {
+		return false
+	}
+	for i, f := range template.Fields {
+		if fields[i].Name != f.Name || fields[i].Kind != f.Kind {
+			return false
+		}
+	}
+	return true
+}
+
+// AddCustomItem adds a new item of a custom type for the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.AddCustomItemRequest structure containing the item details.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the name or the fields are missing, if the template
+//     is neither a built-in template nor a template of the user, if the fields don't match the fields
+//     of the template, or if there is an internal error while adding the item to the storage.
+//
+// The name, the field values and the description are encrypted by the client, the server only checks
+// the names and the kinds of the fields against the template.
+//
+// A non-zero collection ID adds the item to the organization collection instead, which requires the editor role.
+func (g *GophkeeperServer) AddCustomItem(ctx context.Context, in *proto.AddCustomItemRequest) (*emptypb.Empty, error) {
+	if in.Item == nil || in.Item.Template == "" || in.Item.Name == "" {
+		logger.Log.Error("you must provide: template, name, fields")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: template, name, fields")
+	}
+	fields, err := customItemFields(in.Item.Fields)
+	if err != nil {
+		logger.Log.Error("invalid custom item fields", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	userID := ctx.Value(interceptors.UserID).(int64)
+	template, err := g.itemTemplate(ctx, userID, in.Item.Template)
+	if err != nil {
+		return nil, errTemplateNotFound(err)
+	}
+	if !matchesTemplate(fields, template) {
+		logger.Log.Error("custom item fields don't match the template")
+		return nil, status.Errorf(codes.InvalidArgument, "custom item fields don't match the template %s", template.Name)
+	}
+
+	item := &model.CustomItem{
+		Template:    in.Item.Template,
+		Name:        in.Item.Name,
+		Description: in.Item.Description,
+		Fields:      fields,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		item.CollectionID = in.CollectionId
+	} else {
+		item.UserID = userID
+	}
+
+	if err = g.Storage.AddCustomItem(ctx, item); err != nil {
+		logger.Log.Error("error add custom item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add custom item")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// customItemToProto converts a custom item to its proto form.
+func customItemToProto(item *model.CustomItem) *proto.CustomItem {
+	fields := make([]*proto.ItemField, len(item.Fields))
+	for i, f := range item.Fields {
+		fields[i] = &proto.ItemField{Name: f.Name, Kind: f.Kind, Value: f.Value}
+	}
+	return &proto.CustomItem{
+		Id:          item.ID,
+		Template:    item.Template,
+		Name:        item.Name,
+		Fields:      fields,
+		Description: item.Description,
+		CreatedAt:   item.CreatedAt.Format(time.DateTime),
+		Version:     item.Version,
+		UpdatedAt:   item.UpdatedAt.Format(time.DateTime),
+	}
+}
+
+// GetCustomItems retrieves all custom items associated with the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetCustomItemsRequest structure.
+//
+// Returns:
+//   - A pointer to the proto.GetCustomItemsResponse containing the list of items with their fields.
+//   - An error if the operation fails, for example, if there is an internal error while
+//     retrieving the items from the storage.
+//
+// A non-zero collection ID retrieves the items of the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetCustomItems(ctx context.Context, in *proto.GetCustomItemsRequest) (*proto.GetCustomItemsResponse, error) {
+	var response proto.GetCustomItemsResponse
+
+	var items []*model.CustomItem
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		items, err = g.Storage.GetCollectionCustomItems(ctx, in.CollectionId)
+	} else {
+		items, err = g.Storage.GetCustomItems(ctx, ctx.Value(interceptors.UserID).(int64))
+	}
+	if err != nil {
+		logger.Log.Error("error get custom items from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get custom items from DB")
+	}
+
+	response.Items = make([]*proto.CustomItem, len(items))
+	for i, item := range items {
+		response.Items[i] = customItemToProto(item)
+	}
+	return &response, nil
+}
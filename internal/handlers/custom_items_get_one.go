@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetCustomItem retrieves a specific custom item by its ID.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetCustomItemRequest structure containing the ID of the item.
+//
+// Returns:
+//   - A pointer to the proto.GetCustomItemResponse containing the details of the requested item and its fields.
+//   - An error if the operation fails, for example, if the provided ID is invalid, if the item is not found
+//     among the user's items, or if there is an internal error while retrieving the item from the storage.
+//
+// A non-zero collection ID looks the item up in the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetCustomItem(ctx context.Context, in *proto.GetCustomItemRequest) (*proto.GetCustomItemResponse, error) {
+	itemID, err := strconv.ParseInt(in.Id, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "missing custom item id")
+	}
+
+	var item *model.CustomItem
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		item, err = g.Storage.GetCollectionCustomItem(ctx, in.CollectionId, itemID)
+	} else {
+		item, err = g.Storage.GetCustomItem(ctx, ctx.Value(interceptors.UserID).(int64), itemID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("custom item not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "custom item not found")
+	}
+	if err != nil {
+		logger.Log.Error("error get custom item from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get custom item from DB")
+	}
+	return &proto.GetCustomItemResponse{Item: customItemToProto(item)}, nil
+}
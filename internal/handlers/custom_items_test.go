@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestCustomItems(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	md := metadata.New(map[string]string{"token": resp.Token})
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	template := &proto.ItemTemplate{
+		Name:        "db-account",
+		Description: "Database account",
+		Fields: []*proto.TemplateField{
+			{Name: "host", Kind: "text"},
+			{Name: "password", Kind: "secret"},
+		},
+	}
+
+	t.Run("test add item template: unknown kind", func(t *testing.T) {
+		_, err = client.AddItemTemplate(ctx, &proto.AddItemTemplateRequest{Template: &proto.ItemTemplate{
+			Name:   "db-account",
+			Fields: []*proto.TemplateField{{Name: "port", Kind: "number"}},
+		}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test add item template: built-in name", func(t *testing.T) {
+		_, err = client.AddItemTemplate(ctx, &proto.AddItemTemplateRequest{Template: &proto.ItemTemplate{
+			Name:   "wifi",
+			Fields: []*proto.TemplateField{{Name: "ssid", Kind: "text"}},
+		}})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("test add item template: ok", func(t *testing.T) {
+		_, err = client.AddItemTemplate(ctx, &proto.AddItemTemplateRequest{Template: template})
+		require.NoError(t, err)
+	})
+
+	t.Run("test add item template: already exists", func(t *testing.T) {
+		_, err = client.AddItemTemplate(ctx, &proto.AddItemTemplateRequest{Template: template})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("test get item templates: ok", func(t *testing.T) {
+		resp, err := client.GetItemTemplates(ctx, &proto.GetItemTemplatesRequest{})
+		require.NoError(t, err)
+		last := resp.Templates[len(resp.Templates)-1]
+		assert.True(t, resp.Templates[0].Builtin)
+		assert.False(t, last.Builtin)
+		assert.Equal(t, template.Name, last.Name)
+		require.Len(t, last.Fields, 2)
+		assert.Equal(t, "password", last.Fields[1].Name)
+		assert.Equal(t, "secret", last.Fields[1].Kind)
+	})
+
+	item := &proto.CustomItem{
+		Template: "db-account",
+		Name:     "name",
+		Fields: []*proto.ItemField{
+			{Name: "password", Kind: "secret", Value: "password"},
+			{Name: "host", Kind: "text", Value: "host"},
+		},
+		Description: "description",
+	}
+
+	t.Run("test add custom item: fields don't match the template", func(t *testing.T) {
+		_, err = client.AddCustomItem(ctx, &proto.AddCustomItemRequest{Item: item})
+		require.ErrorContains(t, err, "custom item fields don't match the template")
+	})
+
+	t.Run("test add custom item: unknown template", func(t *testing.T) {
+		_, err = client.AddCustomItem(ctx, &proto.AddCustomItemRequest{Item: &proto.CustomItem{
+			Template: "unknown",
+			Name:     "name",
+			Fields:   []*proto.ItemField{{Name: "host", Kind: "text", Value: "host"}},
+		}})
+		require.ErrorContains(t, err, "item template not found")
+	})
+
+	item.Fields[0], item.Fields[1] = item.Fields[1], item.Fields[0]
+	t.Run("test add custom item: ok", func(t *testing.T) {
+		_, err = client.AddCustomItem(ctx, &proto.AddCustomItemRequest{Item: item})
+		require.NoError(t, err)
+	})
+
+	t.Run("test add custom item: built-in template", func(t *testing.T) {
+		_, err = client.AddCustomItem(ctx, &proto.AddCustomItemRequest{Item: &proto.CustomItem{
+			Template: "wifi",
+			Name:     "home",
+			Fields: []*proto.ItemField{
+				{Name: "ssid", Kind: "text", Value: "ssid"},
+				{Name: "password", Kind: "secret", Value: "password"},
+				{Name: "security", Kind: "text", Value: "security"},
+			},
+		}})
+		require.NoError(t, err)
+	})
+
+	t.Run("test get custom items: ok", func(t *testing.T) {
+		resp, err := client.GetCustomItems(ctx, &proto.GetCustomItemsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 2)
+		assert.Equal(t, item.Name, resp.Items[0].Name)
+		require.Len(t, resp.Items[0].Fields, 2)
+		assert.Equal(t, "host", resp.Items[0].Fields[0].Name)
+		assert.Equal(t, "password", resp.Items[0].Fields[1].Value)
+		assert.Len(t, resp.Items[1].Fields, 3)
+	})
+
+	t.Run("test get custom item: unknown id", func(t *testing.T) {
+		_, err = client.GetCustomItem(ctx, &proto.GetCustomItemRequest{Id: "435"})
+		require.ErrorContains(t, err, "custom item not found")
+	})
+
+	t.Run("test update custom item: ok", func(t *testing.T) {
+		updated := &proto.CustomItem{
+			Id:      1,
+			Version: 1,
+			Name:    "new name",
+			Fields: []*proto.ItemField{
+				{Name: "host", Kind: "text", Value: "new host"},
+				{Name: "password", Kind: "secret", Value: "new password"},
+			},
+		}
+		resp, err := client.UpdateCustomItem(ctx, &proto.UpdateCustomItemRequest{Item: updated})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Version)
+
+		got, err := client.GetCustomItem(ctx, &proto.GetCustomItemRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, "new name", got.Item.Name)
+		assert.Equal(t, "db-account", got.Item.Template)
+		assert.Equal(t, "new password", got.Item.Fields[1].Value)
+	})
+
+	t.Run("test update custom item: stale version", func(t *testing.T) {
+		_, err = client.UpdateCustomItem(ctx, &proto.UpdateCustomItemRequest{Item: &proto.CustomItem{
+			Id:      1,
+			Version: 1,
+			Name:    "name",
+			Fields:  []*proto.ItemField{{Name: "host", Kind: "text", Value: "host"}},
+		}})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("test remove item template: built-in", func(t *testing.T) {
+		_, err = client.RemoveItemTemplate(ctx, &proto.RemoveItemTemplateRequest{Name: "wifi"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test remove item template: ok", func(t *testing.T) {
+		_, err = client.RemoveItemTemplate(ctx, &proto.RemoveItemTemplateRequest{Name: template.Name})
+		require.NoError(t, err)
+
+		resp, err := client.GetCustomItem(ctx, &proto.GetCustomItemRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Len(t, resp.Item.Fields, 2)
+	})
+
+	t.Run("test remove custom item: ok", func(t *testing.T) {
+		_, err = client.RemoveCustomItem(ctx, &proto.RemoveCustomItemRequest{Id: "1"})
+		require.NoError(t, err)
+	})
+
+	t.Run("test remove custom item: already removed", func(t *testing.T) {
+		_, err = client.RemoveCustomItem(ctx, &proto.RemoveCustomItemRequest{Id: "1"})
+		require.ErrorContains(t, err, "custom item not found")
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UpdateCustomItem replaces the name, the fields and the description of the custom item with the given ID
+// by the new version sent by the client. The template of the item is kept.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UpdateCustomItemRequest structure containing the new item details and the version they are based on.
+//
+// Returns:
+//   - A pointer to the proto.UpdateCustomItemResponse containing the new version of the custom item.
+//   - An error if the operation fails, for example, if required fields are missing or invalid, if the custom item
+//     is not found among the user's custom items, if the custom item has been changed by another client since
+//     the version was read (Aborted), or if there is an internal error while updating the custom item in the storage.
+//
+// A non-zero collection ID updates the custom item of the organization collection instead, which requires the editor role.
+func (g *GophkeeperServer) UpdateCustomItem(ctx context.Context, in *proto.UpdateCustomItemRequest) (*proto.UpdateCustomItemResponse, error) {
+	if in.Item == nil || in.Item.Id == 0 || in.Item.Version == 0 || in.Item.Name == "" {
+		logger.Log.Error("you must provide: id, version, name, fields")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: id, version, name, fields")
+	}
+	fields, err := customItemFields(in.Item.Fields)
+	if err != nil {
+		logger.Log.Error("invalid custom item fields", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	item := &model.CustomItem{
+		ID:          in.Item.Id,
+		Version:     in.Item.Version,
+		Name:        in.Item.Name,
+		Description: in.Item.Description,
+		Fields:      fields,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		item.CollectionID = in.CollectionId
+	} else {
+		item.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	version, err := g.Storage.UpdateCustomItem(ctx, item)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Log.Error("custom item not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "custom item not found")
+	case errors.Is(err, storage.ErrVersionConflict):
+		logger.Log.Error("custom item version conflict", zap.Error(err))
+		return nil, status.Errorf(codes.Aborted, "custom item has been changed by another client, get it and try again")
+	case err != nil:
+		logger.Log.Error("error update custom item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error update custom item")
+	}
+	return &proto.UpdateCustomItemResponse{Version: version}, nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -26,7 +27,7 @@ func TestEcho(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
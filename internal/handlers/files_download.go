@@ -1,19 +1,19 @@
 package handlers
 
 import (
-	"fmt"
+	"database/sql"
+	"errors"
 	"io"
 
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/minio/minio-go/v7"
 	"go.uber.org/zap"
 	"golang.org/x/text/unicode/norm"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
-	jwtPKG "github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -24,39 +24,17 @@ import (
 //   - srv: A proto.Gophkeeper_DownloadServer interface for sending the file chunks back to the client.
 //
 // Returns:
-//   - An error if the operation fails, for example, if the token is missing, invalid, or if there are
-//     issues retrieving the file from storage or MinIO.
+//   - An error if the operation fails, for example, if there are issues retrieving the file from
+//     storage or MinIO.
 //
-// The function first retrieves the JWT token from the incoming context metadata. It then parses the
-// token and validates it. If the token is valid, it retrieves the file information from the storage
-// and streams the file in chunks to the client. If any errors occur during these processes, they are
+// The token is validated by the stream interceptors, which store the user ID in the stream context.
+// The function retrieves the file information from the storage and streams the file in chunks to
+// the client. If any errors occur during these processes, they are
 // logged, and appropriate gRPC status codes are returned.
+//
+// A non-zero collection ID downloads the file of the organization collection instead, which requires the viewer role.
 func (g *GophkeeperServer) Download(in *proto.FileDownloadRequest, srv proto.Gophkeeper_DownloadServer) error {
-	var tokenString string
-	if md, ok := metadata.FromIncomingContext(srv.Context()); ok {
-		values := md.Get("token")
-		if len(values) > 0 {
-			tokenString = values[0]
-		}
-	}
-	if len(tokenString) == 0 {
-		return status.Error(codes.PermissionDenied, "missing token")
-	}
-
-	claims := &jwtPKG.Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims,
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				logger.Log.Error("unexpected signing method")
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(g.JWTKey), nil
-		})
-
-	if err != nil || !token.Valid {
-		logger.Log.Error("error parse claims", zap.Error(err))
-		return status.Errorf(codes.PermissionDenied, "error parse claims")
-	}
+	userID := srv.Context().Value(interceptors.UserID).(int64)
 
 	fileName := norm.NFC.String(in.FileName)
 	if fileName == "" {
@@ -64,13 +42,26 @@ func (g *GophkeeperServer) Download(in *proto.FileDownloadRequest, srv proto.Gop
 		return status.Error(codes.InvalidArgument, "file name is required")
 	}
 
-	fileInfo, err := g.Storage.GetFile(srv.Context(), fileName)
+	var fileInfo *model.File
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(srv.Context(), in.CollectionId, model.RoleViewer); err != nil {
+			return err
+		}
+		fileInfo, err = g.Storage.GetCollectionFile(srv.Context(), in.CollectionId, fileName)
+	} else {
+		fileInfo, err = g.Storage.GetFile(srv.Context(), userID, fileName)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("file not found", zap.Error(err))
+		return status.Error(codes.NotFound, "file not found")
+	}
 	if err != nil {
 		logger.Log.Error("error getting file info", zap.Error(err))
 		return status.Error(codes.Internal, "error getting file info")
 	}
 
-	object, err := g.Minio.GetObject(srv.Context(), fileInfo.BucketName, fileInfo.FileName, minio.GetObjectOptions{})
+	object, err := g.Minio.GetObject(srv.Context(), fileInfo.BucketName, fileInfo.ObjectKey, minio.GetObjectOptions{})
 	if err != nil {
 		logger.Log.Error("error getting object from MinIO", zap.Error(err))
 		return status.Error(codes.Internal, "error getting object from MinIO")
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
@@ -26,10 +28,21 @@ import (
 // The function fetches the user's files from the storage and constructs a response
 // containing the file details. If an error occurs during the retrieval, it logs the error
 // and returns an appropriate gRPC status code.
-func (g *GophkeeperServer) GetFiles(ctx context.Context, _ *proto.GetFilesRequest) (*proto.GetFilesResponse, error) {
+//
+// A non-zero collection ID retrieves the files of the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetFiles(ctx context.Context, in *proto.GetFilesRequest) (*proto.GetFilesResponse, error) {
 	var response proto.GetFilesResponse
 
-	files, err := g.Storage.GetFiles(ctx, ctx.Value(interceptors.UserID).(int64))
+	var files []*model.File
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		files, err = g.Storage.GetCollectionFiles(ctx, in.CollectionId)
+	} else {
+		files, err = g.Storage.GetFiles(ctx, ctx.Value(interceptors.UserID).(int64))
+	}
 	if err != nil {
 		logger.Log.Error("error get files from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get files from DB")
@@ -43,6 +56,8 @@ func (g *GophkeeperServer) GetFiles(ctx context.Context, _ *proto.GetFilesReques
 		protoFiles[i].FileSize = file.FileSize
 		protoFiles[i].Description = file.Description
 		protoFiles[i].CreatedAt = file.CreatedAt
+		protoFiles[i].Version = file.Version
+		protoFiles[i].UpdatedAt = file.UpdatedAt.Format(time.DateTime)
 	}
 	response.Files = protoFiles
 	return &response, nil
@@ -10,7 +10,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
-	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -31,25 +32,42 @@ import (
 // MinIO. If an error occurs during the removal from MinIO, it logs the error and returns an Internal status.
 // Finally, it attempts to remove the file from the database, logging any errors that occur and returning an
 // Internal status if the operation fails. If all operations are successful, it returns an empty response.
+//
+// A non-zero collection ID removes the file from the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) RemoveFile(ctx context.Context, in *proto.FileRemoveRequest) (*emptypb.Empty, error) {
 	if in.FileName == "" {
 		logger.Log.Error("you must provide file name")
 		return nil, status.Errorf(codes.InvalidArgument, "you must provide file name")
 	}
 
-	file, err := g.Storage.GetFile(ctx, in.FileName)
+	userID := ctx.Value(interceptors.UserID).(int64)
+	var file *model.File
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		file, err = g.Storage.GetCollectionFile(ctx, in.CollectionId, in.FileName)
+	} else {
+		file, err = g.Storage.GetFile(ctx, userID, in.FileName)
+	}
 	if err != nil {
 		logger.Log.Error("file not found", zap.Error(err))
 		return nil, status.Errorf(codes.NotFound, "file not found")
 	}
 
-	err = g.Minio.RemoveObject(ctx, storage.MinioBucketName, file.FileName, minio.RemoveObjectOptions{ForceDelete: true})
+	err = g.Minio.RemoveObject(ctx, file.BucketName, file.ObjectKey, minio.RemoveObjectOptions{ForceDelete: true})
 	if err != nil {
 		logger.Log.Error("error remove file from minio", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error remove file from minio")
 	}
 
-	if err = g.Storage.RemoveFile(ctx, in.FileName); err != nil {
+	if in.CollectionId != 0 {
+		err = g.Storage.RemoveCollectionFile(ctx, in.CollectionId, in.FileName)
+	} else {
+		err = g.Storage.RemoveFile(ctx, userID, in.FileName)
+	}
+	if err != nil {
 		logger.Log.Error("error remove file from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error remove file from DB")
 	}
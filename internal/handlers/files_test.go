@@ -13,11 +13,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/unicode/norm"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 	minioStorage "github.com/Vidkin/gophkeeper/internal/storage"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -58,7 +61,9 @@ func TestFiles(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)),
+		grpc.ChainStreamInterceptor(interceptors.ValidateTokenStream(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
@@ -209,7 +214,7 @@ func TestFiles(t *testing.T) {
 		stream, err := client.Download(ctx, req)
 		require.NoError(t, err)
 		_, err = stream.Recv()
-		require.ErrorContains(t, err, "error getting file info")
+		require.ErrorContains(t, err, "file not found")
 	})
 
 	t.Run("test download file: file name is empty", func(t *testing.T) {
@@ -242,6 +247,50 @@ func TestFiles(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test update file: missing version", func(t *testing.T) {
+		_, err = client.UpdateFile(ctx, &proto.UpdateFileRequest{FileName: file.FileName, Description: "new description"})
+		require.ErrorContains(t, err, "you must provide: file name, version")
+	})
+
+	t.Run("test update file: file not found", func(t *testing.T) {
+		_, err = client.UpdateFile(ctx, &proto.UpdateFileRequest{FileName: "badFileName", Version: 1})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test update file: new name exists", func(t *testing.T) {
+		_, err = client.UpdateFile(ctx, &proto.UpdateFileRequest{FileName: file.FileName, NewFileName: file.FileName + "chunks", Version: 1})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("test update file: ok", func(t *testing.T) {
+		resp, err := client.UpdateFile(ctx, &proto.UpdateFileRequest{FileName: file.FileName, NewFileName: "renamed.tmp", Description: "new description", Version: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Version)
+
+		files, err := client.GetFiles(ctx, &proto.GetFilesRequest{})
+		require.NoError(t, err)
+		names := make(map[string]string)
+		for _, f := range files.Files {
+			names[f.FileName] = f.Description
+		}
+		assert.Equal(t, "new description", names["renamed.tmp"])
+		assert.NotContains(t, names, file.FileName)
+
+		// The content stays under its object key, so it's still downloaded by the new name.
+		stream, err := client.Download(ctx, &proto.FileDownloadRequest{FileName: "renamed.tmp"})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+	})
+
+	t.Run("test update file: stale version", func(t *testing.T) {
+		_, err = client.UpdateFile(ctx, &proto.UpdateFileRequest{FileName: "renamed.tmp", NewFileName: file.FileName, Version: 1})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+
+		_, err = client.UpdateFile(ctx, &proto.UpdateFileRequest{FileName: "renamed.tmp", NewFileName: file.FileName, Description: file.Description, Version: 2})
+		require.NoError(t, err)
+	})
+
 	t.Run("test remove file: empty file name", func(t *testing.T) {
 		_, err = client.RemoveFile(ctx, &proto.FileRemoveRequest{FileName: ""})
 		require.ErrorContains(t, err, "you must provide file name")
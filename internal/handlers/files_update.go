@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UpdateFile changes the description of a file associated with the user and optionally renames it.
+// The content of the file is not changed, it is replaced by uploading the file again.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UpdateFileRequest structure containing the name of the file, the new metadata
+//     and the version they are based on.
+//
+// Returns:
+//   - A pointer to the proto.UpdateFileResponse containing the new version of the file.
+//   - An error if the operation fails, for example, if the file name or the version is missing, if the file is not found,
+//     if the file has been changed by another client since the version was read (Aborted), if a file with the new name
+//     already exists, or if there is an internal error while updating the file in the storage.
+//
+// A non-zero collection ID updates the file of the organization collection instead, which requires the editor role.
+func (g *GophkeeperServer) UpdateFile(ctx context.Context, in *proto.UpdateFileRequest) (*proto.UpdateFileResponse, error) {
+	if in.FileName == "" || in.Version == 0 {
+		logger.Log.Error("you must provide: file name, version")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: file name, version")
+	}
+
+	file := &model.File{
+		FileName:    norm.NFC.String(in.FileName),
+		Description: in.Description,
+		Version:     in.Version,
+	}
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		file.CollectionID = in.CollectionId
+	} else {
+		file.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	version, err := g.Storage.UpdateFile(ctx, file, norm.NFC.String(in.NewFileName))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Log.Error("file not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "file not found")
+	case errors.Is(err, storage.ErrVersionConflict):
+		logger.Log.Error("file version conflict", zap.Error(err))
+		return nil, status.Errorf(codes.Aborted, "file has been changed by another client, get it and try again")
+	case errors.Is(err, storage.ErrFileExists):
+		logger.Log.Error("file already exists", zap.Error(err))
+		return nil, status.Errorf(codes.AlreadyExists, "file with the new name already exists")
+	case err != nil:
+		logger.Log.Error("error update file", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error update file")
+	}
+	return &proto.UpdateFileResponse{Version: version}, nil
+}
@@ -5,22 +5,26 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/minio/minio-go/v7"
 	"go.uber.org/zap"
 	"golang.org/x/text/unicode/norm"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
 	"github.com/Vidkin/gophkeeper/internal/storage"
-	jwtPKG "github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
 // Upload handles the streaming upload of a file to MinIO and records its metadata in the database.
 //
+// Every upload is stored under a new per-user object key (see storage.NewObjectKey). When a user uploads a file
+// with a name that already exists, the object of the previous version is removed after the metadata is updated.
+// A staged upload only stores the object and returns its key, which is referenced by RekeyVault later.
+// The token and the hash of every message are validated by the stream interceptors.
+//
 // This function implements the gRPC server-side streaming method for uploading files. It expects a stream of
 // `proto.FileUploadRequest` messages containing file chunks and metadata. The function performs the following
 //
@@ -30,35 +34,14 @@ import (
 //
 // Returns:
 //   - An error if any step in the upload process fails, indicating the type of error encountered.
+//
+// A non-zero collection ID in the first message uploads the file to the organization collection instead, which
+// requires the editor role; collection files can't be staged.
 func (g *GophkeeperServer) Upload(stream proto.Gophkeeper_UploadServer) error {
 	var fileName, description string
-	var fileSize int64
-	var tokenString string
-
-	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
-		values := md.Get("token")
-		if len(values) > 0 {
-			tokenString = values[0]
-		}
-	}
-	if len(tokenString) == 0 {
-		return status.Error(codes.PermissionDenied, "missing token")
-	}
-
-	claims := &jwtPKG.Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims,
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				logger.Log.Error("unexpected signing method")
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(g.JWTKey), nil
-		})
-
-	if err != nil || !token.Valid {
-		logger.Log.Error("error parse claims", zap.Error(err))
-		return status.Errorf(codes.PermissionDenied, "error parse claims")
-	}
+	var fileSize, collectionID int64
+	var staged bool
+	userID := stream.Context().Value(interceptors.UserID).(int64)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -69,6 +52,9 @@ func (g *GophkeeperServer) Upload(stream proto.Gophkeeper_UploadServer) error {
 		logger.Log.Error("empty file")
 		return status.Errorf(codes.FailedPrecondition, "empty file")
 	}
+	if status.Code(err) == codes.InvalidArgument {
+		return err
+	}
 	if err != nil {
 		logger.Log.Error("error receive file", zap.Error(err))
 		return status.Errorf(codes.Internal, "error receive file")
@@ -77,11 +63,22 @@ func (g *GophkeeperServer) Upload(stream proto.Gophkeeper_UploadServer) error {
 	fileName = norm.NFC.String(req.FileName)
 	description = req.Description
 	fileSize = req.FileSize
+	staged = req.Staged
+	collectionID = req.CollectionId
 	if fileName == "" || fileSize == 0 {
 		return status.Errorf(codes.InvalidArgument, "filename, file-size are required")
 	}
+	if collectionID != 0 {
+		if staged {
+			return status.Errorf(codes.InvalidArgument, "collection files can't be staged")
+		}
+		if _, err = g.authorizeCollection(stream.Context(), collectionID, model.RoleEditor); err != nil {
+			return err
+		}
+	}
 
 	chunkChan := make(chan []byte)
+	recvErr := make(chan error, 1)
 	go func() {
 		defer close(chunkChan)
 		for {
@@ -90,51 +87,100 @@ func (g *GophkeeperServer) Upload(stream proto.Gophkeeper_UploadServer) error {
 				chunkChan <- chunk
 			}
 
-			req, err = stream.Recv()
-			if err == io.EOF {
+			var errRecv error
+			req, errRecv = stream.Recv()
+			if errRecv == io.EOF {
 				break
 			}
-			if err != nil {
-				logger.Log.Error("failed to receive data", zap.Error(err))
+			if errRecv != nil {
+				logger.Log.Error("failed to receive data", zap.Error(errRecv))
+				recvErr <- errRecv
 				cancel()
 				return
 			}
 		}
 	}()
 
+	writeErr := make(chan error, 1)
 	go func() {
+		var errWrite error
 		defer func(pw *io.PipeWriter) {
-			err = pw.Close()
-			if err != nil {
-				logger.Log.Error("failed to close pipe writer", zap.Error(err))
+			if errClose := pw.CloseWithError(errWrite); errClose != nil {
+				logger.Log.Error("failed to close pipe writer", zap.Error(errClose))
 			}
 		}(pw)
 
 		for chunk := range chunkChan {
-			if _, err = pw.Write(chunk); err != nil {
-				logger.Log.Error("error writing chunk to pipe", zap.Error(err))
+			if _, errWrite = pw.Write(chunk); errWrite != nil {
+				logger.Log.Error("error writing chunk to pipe", zap.Error(errWrite))
+				writeErr <- errWrite
+				// Drain the rest so that the receiving goroutine doesn't block on a send.
+				for range chunkChan {
+				}
 				return
 			}
 		}
 	}()
 
-	_, err = g.Minio.PutObject(ctx, storage.MinioBucketName, fileName, pr, fileSize, minio.PutObjectOptions{
+	objectKey, err := storage.NewObjectKey(userID)
+	if err != nil {
+		logger.Log.Error("failed to generate object key", zap.Error(err))
+		return status.Errorf(codes.Internal, "failed to upload file to MinIO")
+	}
+
+	_, err = g.Minio.PutObject(ctx, storage.MinioBucketName, objectKey, pr, fileSize, minio.PutObjectOptions{
 		ContentType: "application/octet-stream",
 	})
 	if err != nil {
+		select {
+		case errRecv := <-recvErr:
+			if status.Code(errRecv) == codes.InvalidArgument {
+				return errRecv
+			}
+		case errWrite := <-writeErr:
+			logger.Log.Error("failed to write file to MinIO", zap.Error(errWrite))
+		default:
+		}
 		logger.Log.Error("failed to upload file to MinIO", zap.Error(err))
 		return status.Errorf(codes.Internal, "failed to upload file to MinIO")
 	}
 
-	err = g.Storage.AddFile(stream.Context(), storage.MinioBucketName, fileName, description, claims.UserID, fileSize)
+	if staged {
+		if err = g.Storage.AddStagedUpload(stream.Context(), objectKey, fileName, userID, fileSize); err != nil {
+			if errRm := g.Minio.RemoveObject(stream.Context(), storage.MinioBucketName, objectKey, minio.RemoveObjectOptions{ForceDelete: true}); errRm != nil {
+				logger.Log.Error("failed to remove file from MinIO", zap.Error(errRm))
+			}
+			logger.Log.Error("failed to save staged upload to database", zap.Error(err))
+			return status.Errorf(codes.Internal, "failed to upload file to MinIO")
+		}
+		logger.Log.Info("file staged", zap.String("fileName", fileName), zap.String("objectKey", objectKey))
+		return stream.SendAndClose(&proto.FileUploadResponse{
+			FileName:  fileName,
+			FileSize:  fileSize,
+			ObjectKey: objectKey,
+		})
+	}
+
+	var previousKey string
+	if collectionID != 0 {
+		previousKey, err = g.Storage.AddCollectionFile(stream.Context(), storage.MinioBucketName, fileName, objectKey, description, collectionID, fileSize)
+	} else {
+		previousKey, err = g.Storage.AddFile(stream.Context(), storage.MinioBucketName, fileName, objectKey, description, userID, fileSize)
+	}
 	if err != nil {
-		if errRm := g.Minio.RemoveObject(stream.Context(), storage.MinioBucketName, fileName, minio.RemoveObjectOptions{ForceDelete: true}); errRm != nil {
-			logger.Log.Error("failed to remove file from MinIO", zap.Error(err))
+		if errRm := g.Minio.RemoveObject(stream.Context(), storage.MinioBucketName, objectKey, minio.RemoveObjectOptions{ForceDelete: true}); errRm != nil {
+			logger.Log.Error("failed to remove file from MinIO", zap.Error(errRm))
 		}
 		logger.Log.Error("failed to save file info to database", zap.Error(err))
 		return status.Errorf(codes.Internal, "failed to upload file to MinIO")
 	}
 
+	if previousKey != "" {
+		if errRm := g.Minio.RemoveObject(stream.Context(), storage.MinioBucketName, previousKey, minio.RemoveObjectOptions{ForceDelete: true}); errRm != nil {
+			logger.Log.Error("failed to remove replaced file from MinIO", zap.String("objectKey", previousKey), zap.Error(errRm))
+		}
+	}
+
 	logger.Log.Info("file uploaded", zap.String("fileName", fileName), zap.String("fileSize", fmt.Sprint(fileSize)))
 	return stream.SendAndClose(&proto.FileUploadResponse{
 		FileName: fileName,
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// CreateFolder creates a folder of the personal items of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.CreateFolderRequest structure containing the encrypted name of the folder
+//     and the ID of its parent folder, zero for a top-level folder.
+//
+// Returns:
+//   - A pointer to the proto.CreateFolderResponse containing the ID of the new folder.
+//   - An error if the operation fails, for example, if the name is missing, if the parent folder is not found
+//     among the user's folders, or if there is an internal error while storing the folder.
+func (g *GophkeeperServer) CreateFolder(ctx context.Context, in *proto.CreateFolderRequest) (*proto.CreateFolderResponse, error) {
+	if in.Name == "" {
+		logger.Log.Error("you must provide folder name")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide folder name")
+	}
+
+	id, err := g.Storage.AddFolder(ctx, &model.Folder{
+		UserID:   ctx.Value(interceptors.UserID).(int64),
+		ParentID: in.ParentId,
+		Name:     in.Name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("parent folder not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "parent folder not found")
+	}
+	if err != nil {
+		logger.Log.Error("error add folder to DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add folder to DB")
+	}
+	return &proto.CreateFolderResponse{Id: id}, nil
+}
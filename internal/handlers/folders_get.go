@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetFolders retrieves the folders of the user and the folders the personal items of the user are placed in.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetFoldersRequest structure.
+//
+// Returns:
+//   - A pointer to the proto.GetFoldersResponse containing the folders with their encrypted names and the items
+//     placed in them.
+//   - An error if the operation fails, for example, if there is an internal error while retrieving
+//     the folders from the storage.
+func (g *GophkeeperServer) GetFolders(ctx context.Context, in *proto.GetFoldersRequest) (*proto.GetFoldersResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	folders, err := g.Storage.GetFolders(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get folders from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get folders from DB")
+	}
+	items, err := g.Storage.GetItemFolders(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get item folders from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get folders from DB")
+	}
+
+	var response proto.GetFoldersResponse
+	response.Folders = make([]*proto.Folder, len(folders))
+	for i, f := range folders {
+		response.Folders[i] = &proto.Folder{Id: f.ID, ParentId: f.ParentID, Name: f.Name}
+	}
+	response.Items = make([]*proto.ItemFolder, len(items))
+	for i, item := range items {
+		response.Items[i] = &proto.ItemFolder{ItemType: item.ItemType, ItemId: item.ItemID, FolderId: item.FolderID}
+	}
+	return &response, nil
+}
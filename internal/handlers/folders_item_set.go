@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// invalidLabeledItemType is the message of the error returned for an item that can't be placed in a folder or tagged.
+const invalidLabeledItemType = "item type must be card, credentials, note, file, otp or custom"
+
+// SetItemFolder places a personal item of the user in a folder of the user, replacing its previous folder.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.SetItemFolderRequest structure containing the type and the ID of the item
+//     and the ID of the folder, zero to take the item out of its folder.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the item type is unknown, if the item or the folder
+//     is not found among the user's personal items and folders, or if there is an internal error.
+func (g *GophkeeperServer) SetItemFolder(ctx context.Context, in *proto.SetItemFolderRequest) (*emptypb.Empty, error) {
+	if !storage.IsLabeledItemType(in.ItemType) {
+		logger.Log.Error("invalid item type", zap.String("type", in.ItemType))
+		return nil, status.Errorf(codes.InvalidArgument, invalidLabeledItemType)
+	}
+
+	err := g.Storage.SetItemFolder(ctx, ctx.Value(interceptors.UserID).(int64), &model.ItemFolder{
+		ItemType: in.ItemType,
+		ItemID:   in.ItemId,
+		FolderID: in.FolderId,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("item or folder not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "item or folder not found")
+	}
+	if err != nil {
+		logger.Log.Error("error set item folder", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error set item folder")
+	}
+	return &emptypb.Empty{}, nil
+}
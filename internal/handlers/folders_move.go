@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// MoveFolder moves a folder of the user with its subfolders and items into another folder of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.MoveFolderRequest structure containing the ID of the folder and the ID
+//     of the new parent folder, zero to move the folder to the top level.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the folder would be moved into itself or into one
+//     of its subfolders, if the folder or the parent folder is not found among the user's folders,
+//     or if there is an internal error while moving it.
+func (g *GophkeeperServer) MoveFolder(ctx context.Context, in *proto.MoveFolderRequest) (*emptypb.Empty, error) {
+	err := g.Storage.MoveFolder(ctx, ctx.Value(interceptors.UserID).(int64), in.Id, in.ParentId)
+	if errors.Is(err, storage.ErrFolderCycle) {
+		logger.Log.Error("folder can't be moved into itself", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "folder can't be moved into itself or its subfolder")
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("folder not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "folder not found")
+	}
+	if err != nil {
+		logger.Log.Error("error move folder", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error move folder")
+	}
+	return &emptypb.Empty{}, nil
+}
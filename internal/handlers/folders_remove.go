@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveFolder removes a folder of the user with its subfolders. The items of the removed folders are kept
+// without a folder.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveFolderRequest structure containing the ID of the folder to remove.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the folder is not found among the user's folders,
+//     or if there is an internal error while removing it.
+func (g *GophkeeperServer) RemoveFolder(ctx context.Context, in *proto.RemoveFolderRequest) (*emptypb.Empty, error) {
+	err := g.Storage.RemoveFolder(ctx, ctx.Value(interceptors.UserID).(int64), in.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("folder not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "folder not found")
+	}
+	if err != nil {
+		logger.Log.Error("error remove folder", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove folder")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RenameFolder replaces the encrypted name of a folder of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RenameFolderRequest structure containing the ID of the folder and its new name.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the name is missing, if the folder is not found
+//     among the user's folders, or if there is an internal error while updating it.
+func (g *GophkeeperServer) RenameFolder(ctx context.Context, in *proto.RenameFolderRequest) (*emptypb.Empty, error) {
+	if in.Name == "" {
+		logger.Log.Error("you must provide folder name")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide folder name")
+	}
+
+	err := g.Storage.RenameFolder(ctx, ctx.Value(interceptors.UserID).(int64), in.Id, in.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("folder not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "folder not found")
+	}
+	if err != nil {
+		logger.Log.Error("error rename folder", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error rename folder")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestFoldersAndTags(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	md := metadata.New(map[string]string{"token": resp.Token})
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	_, err = client.AddNote(ctx, &proto.AddNoteRequest{Note: &proto.Note{Text: "text", Description: "description"}})
+	require.NoError(t, err)
+	notes, err := client.GetNotes(ctx, &proto.GetNotesRequest{})
+	require.NoError(t, err)
+	require.Len(t, notes.Notes, 1)
+	noteID := notes.Notes[0].Id
+
+	var workID, serversID, tagID int64
+
+	t.Run("test create folder: empty name", func(t *testing.T) {
+		_, err = client.CreateFolder(ctx, &proto.CreateFolderRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test create folder: unknown parent", func(t *testing.T) {
+		_, err = client.CreateFolder(ctx, &proto.CreateFolderRequest{Name: "servers", ParentId: 435})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test create folder: ok", func(t *testing.T) {
+		work, err := client.CreateFolder(ctx, &proto.CreateFolderRequest{Name: "work"})
+		require.NoError(t, err)
+		workID = work.Id
+		servers, err := client.CreateFolder(ctx, &proto.CreateFolderRequest{Name: "servers", ParentId: workID})
+		require.NoError(t, err)
+		serversID = servers.Id
+	})
+
+	t.Run("test move folder: into its subfolder", func(t *testing.T) {
+		_, err = client.MoveFolder(ctx, &proto.MoveFolderRequest{Id: workID, ParentId: serversID})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = client.MoveFolder(ctx, &proto.MoveFolderRequest{Id: workID, ParentId: workID})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test move folder: ok", func(t *testing.T) {
+		_, err = client.MoveFolder(ctx, &proto.MoveFolderRequest{Id: serversID})
+		require.NoError(t, err)
+		_, err = client.MoveFolder(ctx, &proto.MoveFolderRequest{Id: workID, ParentId: serversID})
+		require.NoError(t, err)
+	})
+
+	t.Run("test rename folder: ok", func(t *testing.T) {
+		_, err = client.RenameFolder(ctx, &proto.RenameFolderRequest{Id: workID, Name: "office"})
+		require.NoError(t, err)
+	})
+
+	t.Run("test set item folder: unknown item type", func(t *testing.T) {
+		_, err = client.SetItemFolder(ctx, &proto.SetItemFolderRequest{ItemType: "user", ItemId: noteID, FolderId: workID})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test set item folder: unknown item", func(t *testing.T) {
+		_, err = client.SetItemFolder(ctx, &proto.SetItemFolderRequest{ItemType: "card", ItemId: noteID, FolderId: workID})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test set item folder: ok", func(t *testing.T) {
+		_, err = client.SetItemFolder(ctx, &proto.SetItemFolderRequest{ItemType: "note", ItemId: noteID, FolderId: serversID})
+		require.NoError(t, err)
+		_, err = client.SetItemFolder(ctx, &proto.SetItemFolderRequest{ItemType: "note", ItemId: noteID, FolderId: workID})
+		require.NoError(t, err)
+	})
+
+	t.Run("test get folders: ok", func(t *testing.T) {
+		resp, err := client.GetFolders(ctx, &proto.GetFoldersRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Folders, 2)
+		assert.Equal(t, workID, resp.Folders[0].Id)
+		assert.Equal(t, serversID, resp.Folders[0].ParentId)
+		assert.Equal(t, "office", resp.Folders[0].Name)
+		assert.Equal(t, serversID, resp.Folders[1].Id)
+		assert.Zero(t, resp.Folders[1].ParentId)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, noteID, resp.Items[0].ItemId)
+		assert.Equal(t, workID, resp.Items[0].FolderId)
+	})
+
+	t.Run("test create tag: ok", func(t *testing.T) {
+		tag, err := client.CreateTag(ctx, &proto.CreateTagRequest{Name: "prod"})
+		require.NoError(t, err)
+		tagID = tag.Id
+	})
+
+	t.Run("test tag item: unknown tag", func(t *testing.T) {
+		_, err = client.TagItem(ctx, &proto.TagItemRequest{ItemType: "note", ItemId: noteID, TagId: 435})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test tag item: ok", func(t *testing.T) {
+		_, err = client.TagItem(ctx, &proto.TagItemRequest{ItemType: "note", ItemId: noteID, TagId: tagID})
+		require.NoError(t, err)
+		_, err = client.TagItem(ctx, &proto.TagItemRequest{ItemType: "note", ItemId: noteID, TagId: tagID})
+		require.NoError(t, err)
+
+		resp, err := client.GetTags(ctx, &proto.GetTagsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Tags, 1)
+		assert.Equal(t, "prod", resp.Tags[0].Name)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, noteID, resp.Items[0].ItemId)
+		assert.Equal(t, tagID, resp.Items[0].TagId)
+	})
+
+	t.Run("test untag item: ok", func(t *testing.T) {
+		_, err = client.UntagItem(ctx, &proto.UntagItemRequest{ItemType: "note", ItemId: noteID, TagId: tagID})
+		require.NoError(t, err)
+		_, err = client.UntagItem(ctx, &proto.UntagItemRequest{ItemType: "note", ItemId: noteID, TagId: tagID})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test remove item: labels removed", func(t *testing.T) {
+		_, err = client.TagItem(ctx, &proto.TagItemRequest{ItemType: "note", ItemId: noteID, TagId: tagID})
+		require.NoError(t, err)
+		_, err = client.RemoveNote(ctx, &proto.RemoveNoteRequest{Id: fmt.Sprint(noteID)})
+		require.NoError(t, err)
+
+		folders, err := client.GetFolders(ctx, &proto.GetFoldersRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, folders.Items)
+		tags, err := client.GetTags(ctx, &proto.GetTagsRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, tags.Items)
+	})
+
+	t.Run("test remove tag: ok", func(t *testing.T) {
+		_, err = client.RemoveTag(ctx, &proto.RemoveTagRequest{Id: tagID})
+		require.NoError(t, err)
+		_, err = client.RenameTag(ctx, &proto.RenameTagRequest{Id: tagID, Name: "dev"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test remove folder: subfolders removed", func(t *testing.T) {
+		_, err = client.RemoveFolder(ctx, &proto.RemoveFolderRequest{Id: serversID})
+		require.NoError(t, err)
+		resp, err := client.GetFolders(ctx, &proto.GetFoldersRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Folders)
+	})
+}
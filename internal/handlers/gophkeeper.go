@@ -1,7 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/peer"
+
 	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/keyring"
+	"github.com/Vidkin/gophkeeper/pkg/password"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -9,9 +17,81 @@ import (
 // main entry point for handling file-related operations.
 type GophkeeperServer struct {
 	proto.UnimplementedGophkeeperServer
-	Storage     *storage.PostgresStorage     // Repository for storing data
-	Minio       storage.MinioClientInterface // Client to minio storage
-	DatabaseKey string                       // Hash key
-	JWTKey      string                       // JWT secret key
-	RetryCount  int                          // Number of retry attempts for database operations
+	Storage        *storage.PostgresStorage     // Repository for storing data
+	Minio          storage.MinioClientInterface // Client to minio storage
+	PasswordParams *password.Params             // Argon2id parameters for user passwords (defaults if nil)
+	DatabaseKey    string                       // Hash key
+	Keyring        *keyring.Keyring             // Database secret keys (DatabaseKey only if nil)
+	JWTKey         string                       // JWT secret key
+	JWTKeys        *jwt.KeySet                  // JWT signing and verification keys (JWTKey only if nil)
+	RetryCount     int                          // Number of retry attempts for database operations
+}
+
+// maxSessionInfoLength limits the length of the client-provided device name and version of a session.
+const maxSessionInfoLength = 128
+
+// peerAddress returns the network address of the client of a gRPC call, or an empty string if it is unknown.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+// databaseKeys returns the keys to encrypt and decrypt the secrets stored on the server,
+// which is a keyring of DatabaseKey alone if Keyring isn't set.
+func (g *GophkeeperServer) databaseKeys() (*keyring.Keyring, error) {
+	if g.Keyring != nil {
+		return g.Keyring, nil
+	}
+	return keyring.New(keyring.LegacyKeyID, map[string]string{keyring.LegacyKeyID: g.DatabaseKey})
+}
+
+// jwtKeys returns the keys to sign and verify the access tokens, which is a key set of the JWTKey
+// HS256 secret key alone if JWTKeys isn't set.
+func (g *GophkeeperServer) jwtKeys() (*jwt.KeySet, error) {
+	if g.JWTKeys != nil {
+		return g.JWTKeys, nil
+	}
+	if keys := jwt.NewHMACKeySet(g.JWTKey); keys != nil {
+		return keys, nil
+	}
+	return nil, fmt.Errorf("%w: JWT keys are not configured", jwt.ErrInvalidKey)
+}
+
+// buildJWTString issues an access token of the user session signed with the signing JWT key.
+func (g *GophkeeperServer) buildJWTString(userID, sessionID int64) (string, error) {
+	keys, err := g.jwtKeys()
+	if err != nil {
+		return "", err
+	}
+	return keys.BuildJWTString(userID, sessionID)
+}
+
+// encryptSecret encrypts a secret stored on the server with the active database key.
+func (g *GophkeeperServer) encryptSecret(src string) (string, error) {
+	keys, err := g.databaseKeys()
+	if err != nil {
+		return "", err
+	}
+	return keys.Encrypt(src)
+}
+
+// decryptSecret decrypts a secret stored on the server with the database key it has been encrypted with.
+func (g *GophkeeperServer) decryptSecret(src string) (string, error) {
+	keys, err := g.databaseKeys()
+	if err != nil {
+		return "", err
+	}
+	return keys.Decrypt(src)
+}
+
+// truncate cuts the string to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
 }
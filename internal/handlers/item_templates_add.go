@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// itemTemplate returns the built-in template or the template of the user with the name,
+// it returns sql.ErrNoRows if there is no such template.
+func (g *GophkeeperServer) itemTemplate(ctx context.Context, userID int64, name string) (itemtype.Template, error) {
+	if t, ok := itemtype.FindBuiltin(name); ok {
+		return t, nil
+	}
+	t, err := g.Storage.GetItemTemplate(ctx, userID, name)
+	if err != nil {
+		return itemtype.Template{}, err
+	}
+	template := itemtype.Template{Name: t.Name, Description: t.Description, Fields: make([]itemtype.Field, len(t.Fields))}
+	for i, f := range t.Fields {
+		template.Fields[i] = itemtype.Field{Name: f.Name, Kind: f.Kind}
+	}
+	return template, nil
+}
+
+// AddItemTemplate adds a new custom item type for the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.AddItemTemplateRequest structure containing the template and its ordered fields.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the name or the fields of the template are invalid,
+//     if a built-in template or a template of the user already has the name, or if there is an internal error
+//     while adding the template to the storage.
+func (g *GophkeeperServer) AddItemTemplate(ctx context.Context, in *proto.AddItemTemplateRequest) (*emptypb.Empty, error) {
+	if in.Template == nil {
+		logger.Log.Error("you must provide item template")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide item template")
+	}
+
+	template := itemtype.Template{Name: in.Template.Name, Description: in.Template.Description}
+	for _, f := range in.Template.Fields {
+		template.Fields = append(template.Fields, itemtype.Field{Name: f.Name, Kind: f.Kind})
+	}
+	if err := template.Validate(); err != nil {
+		logger.Log.Error("invalid item template", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if _, ok := itemtype.FindBuiltin(template.Name); ok {
+		logger.Log.Error("item template already exists")
+		return nil, status.Errorf(codes.AlreadyExists, "item template already exists")
+	}
+
+	t := &model.ItemTemplate{
+		Name:        template.Name,
+		Description: template.Description,
+		Fields:      make([]model.TemplateField, len(template.Fields)),
+		UserID:      ctx.Value(interceptors.UserID).(int64),
+	}
+	for i, f := range template.Fields {
+		t.Fields[i] = model.TemplateField{Name: f.Name, Kind: f.Kind}
+	}
+
+	err := g.Storage.AddItemTemplate(ctx, t)
+	if errors.Is(err, storage.ErrTemplateExists) {
+		logger.Log.Error("item template already exists", zap.Error(err))
+		return nil, status.Errorf(codes.AlreadyExists, "item template already exists")
+	}
+	if err != nil {
+		logger.Log.Error("error add item template", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add item template")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// errTemplateNotFound converts the error of a template lookup to the gRPC status.
+func errTemplateNotFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("item template not found", zap.Error(err))
+		return status.Errorf(codes.NotFound, "item template not found")
+	}
+	logger.Log.Error("error get item template from DB", zap.Error(err))
+	return status.Errorf(codes.Internal, "error get item template from DB")
+}
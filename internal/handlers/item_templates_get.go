@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetItemTemplates retrieves the built-in custom item types followed by the types defined by the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetItemTemplatesRequest structure.
+//
+// Returns:
+//   - A pointer to the proto.GetItemTemplatesResponse containing the list of templates.
+//   - An error if the operation fails, for example, if there is an internal error while
+//     retrieving the templates from the storage.
+func (g *GophkeeperServer) GetItemTemplates(ctx context.Context, in *proto.GetItemTemplatesRequest) (*proto.GetItemTemplatesResponse, error) {
+	var response proto.GetItemTemplatesResponse
+
+	for _, t := range itemtype.Builtin {
+		template := &proto.ItemTemplate{Name: t.Name, Description: t.Description, Builtin: true}
+		for _, f := range t.Fields {
+			template.Fields = append(template.Fields, &proto.TemplateField{Name: f.Name, Kind: f.Kind})
+		}
+		response.Templates = append(response.Templates, template)
+	}
+
+	templates, err := g.Storage.GetItemTemplates(ctx, ctx.Value(interceptors.UserID).(int64))
+	if err != nil {
+		logger.Log.Error("error get item templates from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get item templates from DB")
+	}
+	for _, t := range templates {
+		template := &proto.ItemTemplate{Name: t.Name, Description: t.Description}
+		for _, f := range t.Fields {
+			template.Fields = append(template.Fields, &proto.TemplateField{Name: f.Name, Kind: f.Kind})
+		}
+		response.Templates = append(response.Templates, template)
+	}
+	return &response, nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/itemtype"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveItemTemplate removes a custom item type defined by the user by its name. The items created from
+// the template are kept, they carry the names and the kinds of their fields.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveItemTemplateRequest structure containing the name of the template to remove.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the name is missing or belongs to a built-in template,
+//     if the template is not found among the user's templates, or if there is an internal error while removing it.
+func (g *GophkeeperServer) RemoveItemTemplate(ctx context.Context, in *proto.RemoveItemTemplateRequest) (*emptypb.Empty, error) {
+	if in.Name == "" {
+		logger.Log.Error("you must provide item template name")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide item template name")
+	}
+	if _, ok := itemtype.FindBuiltin(in.Name); ok {
+		logger.Log.Error("built-in item templates can't be removed")
+		return nil, status.Errorf(codes.InvalidArgument, "built-in item templates can't be removed")
+	}
+
+	err := g.Storage.RemoveItemTemplate(ctx, ctx.Value(interceptors.UserID).(int64), in.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("item template not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "item template not found")
+	}
+	if err != nil {
+		logger.Log.Error("error remove item template", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove item template")
+	}
+	return &emptypb.Empty{}, nil
+}
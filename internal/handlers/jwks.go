@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetJWKS returns the public keys verifying the access tokens, so other services can validate them
+// without being able to issue their own.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call (not used in this method).
+//   - in: A pointer to the proto.GetJWKSRequest structure (empty).
+//
+// Returns:
+//   - A pointer to the proto.GetJWKSResponse containing the public keys, including the keys being
+//     rotated in and the retired keys that still verify unexpired tokens. The HS256 secret key is never returned.
+//   - An error if the keys are not configured.
+func (g *GophkeeperServer) GetJWKS(_ context.Context, _ *proto.GetJWKSRequest) (*proto.GetJWKSResponse, error) {
+	keys, err := g.jwtKeys()
+	if err != nil {
+		logger.Log.Error("error get JWT keys", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get JWT keys")
+	}
+
+	var response proto.GetJWKSResponse
+	for _, k := range keys.JWKS() {
+		response.Keys = append(response.Keys, &proto.JWK{
+			Kid: k.KeyID,
+			Kty: k.KeyType,
+			Alg: k.Algorithm,
+			Use: "sig",
+			Crv: k.Curve,
+			X:   k.X,
+			N:   k.N,
+			E:   k.E,
+		})
+	}
+	return &response, nil
+}
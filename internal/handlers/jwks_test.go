@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestGetJWKS(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "jwt.key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	keys, err := jwt.LoadKeySet("JWTKey", keyFile, nil)
+	require.NoError(t, err)
+
+	t.Run("test get jwks: ok", func(t *testing.T) {
+		gs := &GophkeeperServer{JWTKeys: keys}
+		resp, err := gs.GetJWKS(context.Background(), &proto.GetJWKSRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Keys, 1)
+		assert.Equal(t, keys.SigningKeyID(), resp.Keys[0].Kid)
+		assert.Equal(t, "OKP", resp.Keys[0].Kty)
+		assert.Equal(t, "EdDSA", resp.Keys[0].Alg)
+		assert.Equal(t, "sig", resp.Keys[0].Use)
+		assert.Equal(t, "Ed25519", resp.Keys[0].Crv)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(public), resp.Keys[0].X)
+	})
+
+	t.Run("test get jwks: secret key only", func(t *testing.T) {
+		gs := &GophkeeperServer{JWTKey: "JWTKey"}
+		resp, err := gs.GetJWKS(context.Background(), &proto.GetJWKSRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Keys)
+	})
+
+	t.Run("test get jwks: not configured", func(t *testing.T) {
+		gs := &GophkeeperServer{}
+		_, err := gs.GetJWKS(context.Background(), &proto.GetJWKSRequest{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
@@ -24,6 +24,8 @@ import (
 //   - A pointer to an empty proto.Empty response indicating successful addition of the note.
 //   - An error if the operation fails, for example, if the note text is not provided or if there is an
 //     internal error while adding the note to the storage.
+//
+// A non-zero collection ID adds the note to the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) AddNote(ctx context.Context, in *proto.AddNoteRequest) (*emptypb.Empty, error) {
 	if in.Note.Text == "" {
 		logger.Log.Error("you must provide note text")
@@ -31,11 +33,19 @@ func (g *GophkeeperServer) AddNote(ctx context.Context, in *proto.AddNoteRequest
 	}
 
 	note := &model.Note{
-		UserID:      ctx.Value(interceptors.UserID).(int64),
 		Text:        in.Note.Text,
 		Description: in.Note.Description,
 	}
 
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		note.CollectionID = in.CollectionId
+	} else {
+		note.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
 	if err := g.Storage.AddNote(ctx, note); err != nil {
 		logger.Log.Error("error add note", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error add note")
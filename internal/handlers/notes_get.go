@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
@@ -26,10 +28,21 @@ import (
 // The function fetches the user's notes from the storage using the user ID extracted from the context.
 // If an error occurs during the retrieval, it logs the error and returns an Internal status. If the
 // operation is successful, it constructs a response containing the notes and returns it.
-func (g *GophkeeperServer) GetNotes(ctx context.Context, _ *proto.GetNotesRequest) (*proto.GetNotesResponse, error) {
+//
+// A non-zero collection ID retrieves the notes of the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetNotes(ctx context.Context, in *proto.GetNotesRequest) (*proto.GetNotesResponse, error) {
 	var response proto.GetNotesResponse
 
-	notes, err := g.Storage.GetNotes(ctx, ctx.Value(interceptors.UserID).(int64))
+	var notes []*model.Note
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		notes, err = g.Storage.GetCollectionNotes(ctx, in.CollectionId)
+	} else {
+		notes, err = g.Storage.GetNotes(ctx, ctx.Value(interceptors.UserID).(int64))
+	}
 	if err != nil {
 		logger.Log.Error("error get notes from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get notes from DB")
@@ -41,6 +54,8 @@ func (g *GophkeeperServer) GetNotes(ctx context.Context, _ *proto.GetNotesReques
 			Text:        note.Text,
 			Description: note.Description,
 			Id:          note.ID,
+			Version:     note.Version,
+			UpdatedAt:   note.UpdatedAt.Format(time.DateTime),
 		}
 	}
 	response.Notes = protoNotes
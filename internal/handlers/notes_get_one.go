@@ -2,13 +2,18 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -25,8 +30,10 @@ import (
 //
 // The function first attempts to convert the note ID from a string to an integer. If the conversion fails,
 // it returns an InvalidArgument error. It then retrieves the note from the storage using the note ID.
-// If an error occurs during the retrieval, it logs the error and returns an Internal status. If the
-// operation is successful, it constructs a response containing the note and returns it.
+// A note that does not exist or belongs to another user results in a NotFound status, any other
+// retrieval error is logged and returned as an Internal status. If the operation is successful, it constructs a response containing the note and returns it.
+//
+// A non-zero collection ID looks the note up in the organization collection instead, which requires the viewer role.
 func (g *GophkeeperServer) GetNote(ctx context.Context, in *proto.GetNoteRequest) (*proto.GetNoteResponse, error) {
 	noteID, err := strconv.Atoi(in.Id)
 	if err != nil {
@@ -34,7 +41,19 @@ func (g *GophkeeperServer) GetNote(ctx context.Context, in *proto.GetNoteRequest
 	}
 	var response proto.GetNoteResponse
 
-	note, err := g.Storage.GetNote(ctx, int64(noteID))
+	var note *model.Note
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		note, err = g.Storage.GetCollectionNote(ctx, in.CollectionId, int64(noteID))
+	} else {
+		note, err = g.Storage.GetNote(ctx, ctx.Value(interceptors.UserID).(int64), int64(noteID))
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("note not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "note not found")
+	}
 	if err != nil {
 		logger.Log.Error("error get note from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get note from DB")
@@ -44,6 +63,8 @@ func (g *GophkeeperServer) GetNote(ctx context.Context, in *proto.GetNoteRequest
 		Text:        note.Text,
 		Description: note.Description,
 		Id:          note.ID,
+		Version:     note.Version,
+		UpdatedAt:   note.UpdatedAt.Format(time.DateTime),
 	}
 	response.Note = protoNote
 	return &response, nil
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"go.uber.org/zap"
@@ -10,6 +12,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -22,7 +26,9 @@ import (
 // Returns:
 //   - A pointer to an empty proto.Empty response indicating successful removal of the note.
 //   - An error if the operation fails, for example, if the note ID is not provided, if the note ID is
-//     invalid, or if there is an internal error while removing the note from the storage.
+//     invalid, if the note is not found among the user's notes, or if there is an internal error while removing the note from the storage.
+//
+// A non-zero collection ID removes the note from the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) RemoveNote(ctx context.Context, in *proto.RemoveNoteRequest) (*emptypb.Empty, error) {
 	if in.Id == "" {
 		logger.Log.Error("you must provide note id")
@@ -35,7 +41,19 @@ func (g *GophkeeperServer) RemoveNote(ctx context.Context, in *proto.RemoveNoteR
 		return nil, status.Errorf(codes.InvalidArgument, "invalid note id")
 	}
 
-	if err = g.Storage.RemoveNote(ctx, noteID); err != nil {
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		err = g.Storage.RemoveCollectionNote(ctx, in.CollectionId, noteID)
+	} else {
+		err = g.Storage.RemoveNote(ctx, ctx.Value(interceptors.UserID).(int64), noteID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("note not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "note not found")
+	}
+	if err != nil {
 		logger.Log.Error("error remove note", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error remove note")
 	}
@@ -10,10 +10,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -27,7 +30,7 @@ func TestNotes(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
@@ -93,7 +96,7 @@ func TestNotes(t *testing.T) {
 
 	t.Run("test get note: unknown id", func(t *testing.T) {
 		_, err = client.GetNote(ctx, &proto.GetNoteRequest{Id: "435"})
-		require.ErrorContains(t, err, "error get note from DB")
+		require.ErrorContains(t, err, "note not found")
 	})
 
 	t.Run("test get note: ok", func(t *testing.T) {
@@ -103,6 +106,32 @@ func TestNotes(t *testing.T) {
 		assert.Equal(t, note.Description, resp.Note.Description)
 	})
 
+	t.Run("test update note: missing version", func(t *testing.T) {
+		_, err = client.UpdateNote(ctx, &proto.UpdateNoteRequest{Note: &proto.Note{Id: 1, Text: "new text"}})
+		require.ErrorContains(t, err, "you must provide: id, version, note text")
+	})
+
+	t.Run("test update note: unknown id", func(t *testing.T) {
+		_, err = client.UpdateNote(ctx, &proto.UpdateNoteRequest{Note: &proto.Note{Id: 435, Version: 1, Text: "new text"}})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test update note: ok", func(t *testing.T) {
+		resp, err := client.UpdateNote(ctx, &proto.UpdateNoteRequest{Note: &proto.Note{Id: 1, Version: 1, Text: "new text"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Version)
+
+		got, err := client.GetNote(ctx, &proto.GetNoteRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, "new text", got.Note.Text)
+		assert.Equal(t, int64(2), got.Note.Version)
+	})
+
+	t.Run("test update note: stale version", func(t *testing.T) {
+		_, err = client.UpdateNote(ctx, &proto.UpdateNoteRequest{Note: &proto.Note{Id: 1, Version: 1, Text: "other text"}})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
 	t.Run("test remove note: empty id", func(t *testing.T) {
 		_, err = client.RemoveNote(ctx, &proto.RemoveNoteRequest{Id: ""})
 		require.ErrorContains(t, err, "you must provide note id")
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UpdateNote replaces the note with the given ID by the new version sent by the client.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UpdateNoteRequest structure containing the new note details and the version they are based on.
+//
+// Returns:
+//   - A pointer to the proto.UpdateNoteResponse containing the new version of the note.
+//   - An error if the operation fails, for example, if required fields are missing, if the note is not found
+//     among the user's notes, if the note has been changed by another client since the version was read
+//     (Aborted), or if there is an internal error while updating the note in the storage.
+//
+// A non-zero collection ID updates the note of the organization collection instead, which requires the editor role.
+// If the personal note is shared, the request must carry the changed shared copy, which replaces the old one,
+// otherwise the update is rejected (FailedPrecondition), so that the recipients never keep a stale copy.
+func (g *GophkeeperServer) UpdateNote(ctx context.Context, in *proto.UpdateNoteRequest) (*proto.UpdateNoteResponse, error) {
+	if in.Note == nil || in.Note.Id == 0 || in.Note.Version == 0 || in.Note.Text == "" {
+		logger.Log.Error("you must provide: id, version, note text")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: id, version, note text")
+	}
+
+	note := &model.Note{
+		ID:          in.Note.Id,
+		Version:     in.Note.Version,
+		Text:        in.Note.Text,
+		Description: in.Note.Description,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		note.CollectionID = in.CollectionId
+	} else {
+		note.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	version, err := g.Storage.UpdateNote(ctx, note, in.SharedData)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Log.Error("note not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "note not found")
+	case errors.Is(err, storage.ErrVersionConflict):
+		logger.Log.Error("note version conflict", zap.Error(err))
+		return nil, status.Errorf(codes.Aborted, "note has been changed by another client, get it and try again")
+	case errors.Is(err, storage.ErrSharedItemChanged):
+		logger.Log.Error("shared note changed", zap.Error(err))
+		return nil, status.Errorf(codes.FailedPrecondition, "note sharing has changed, get it and try again")
+	case err != nil:
+		logger.Log.Error("error update note", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error update note")
+	}
+	return &proto.UpdateNoteResponse{Version: version}, nil
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+)
+
+// maxOrgNameLength limits the length of the names of the organizations and the collections.
+const maxOrgNameLength = 255
+
+// insufficientRoleMessage is the message of the PermissionDenied status returned to the organization members
+// whose role doesn't allow the call, the clients tell it apart from an invalid token by the message.
+const insufficientRoleMessage = "insufficient role in the organization"
+
+// roleRanks orders the organization roles by their privileges, every role has the privileges of the lower ones.
+var roleRanks = map[string]int{
+	model.RoleViewer: 1,
+	model.RoleEditor: 2,
+	model.RoleAdmin:  3,
+	model.RoleOwner:  4,
+}
+
+// validOrgName reports whether a client-provided organization or collection name is present and not too long.
+func validOrgName(name string) bool {
+	return name != "" && len([]rune(name)) <= maxOrgNameLength
+}
+
+// hasRole reports whether the role has the privileges of the required role.
+func hasRole(role, required string) bool {
+	return roleRanks[role] >= roleRanks[required]
+}
+
+// authorizeOrg checks that the user of the call is a member of the organization with at least the required role
+// and returns the membership. Organizations the user isn't a member of are reported as not found.
+func (g *GophkeeperServer) authorizeOrg(ctx context.Context, orgID int64, required string) (*model.OrgMember, error) {
+	member, err := g.Storage.GetOrgMember(ctx, orgID, ctx.Value(interceptors.UserID).(int64))
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("organization not found", zap.Int64("orgID", orgID))
+		return nil, status.Errorf(codes.NotFound, "organization not found")
+	}
+	return checkRole(member, err, required)
+}
+
+// authorizeCollection checks that the user of the call is a member of the organization owning the collection
+// with at least the required role and returns the membership. Collections of the organizations the user isn't
+// a member of are reported as not found.
+func (g *GophkeeperServer) authorizeCollection(ctx context.Context, collectionID int64, required string) (*model.OrgMember, error) {
+	member, err := g.Storage.GetCollectionMember(ctx, collectionID, ctx.Value(interceptors.UserID).(int64))
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("collection not found", zap.Int64("collectionID", collectionID))
+		return nil, status.Errorf(codes.NotFound, "collection not found")
+	}
+	return checkRole(member, err, required)
+}
+
+// checkRole converts the result of a membership lookup to the membership or to the status of the call.
+func checkRole(member *model.OrgMember, err error, required string) (*model.OrgMember, error) {
+	if err != nil {
+		logger.Log.Error("error get organization member from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get organization member from DB")
+	}
+	if !hasRole(member.Role, required) {
+		logger.Log.Error(insufficientRoleMessage, zap.String("role", member.Role), zap.String("required", required))
+		return nil, status.Errorf(codes.PermissionDenied, insufficientRoleMessage)
+	}
+	return member, nil
+}
+
+// orgMemberByLogin retrieves the membership of a user in the organization by login.
+func (g *GophkeeperServer) orgMemberByLogin(ctx context.Context, orgID int64, login string) (*model.OrgMember, error) {
+	user, err := g.Storage.GetUser(ctx, login)
+	if err == nil {
+		return g.Storage.GetOrgMember(ctx, orgID, user.ID)
+	}
+	return nil, err
+}
+
+// removeCollectionObjects removes the objects of the files of the removed collections from MinIO.
+// The errors are logged, the objects are left in the bucket in this case.
+func (g *GophkeeperServer) removeCollectionObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := g.Minio.RemoveObject(ctx, storage.MinioBucketName, key, minio.RemoveObjectOptions{ForceDelete: true}); err != nil {
+			logger.Log.Error("failed to remove collection file from MinIO", zap.String("objectKey", key), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// CreateCollection creates a collection in an organization, admins and owners may call it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.CreateCollectionRequest structure containing the organization and the name
+//     of the collection.
+//
+// Returns:
+//   - A pointer to the proto.CreateCollectionResponse containing the ID of the collection.
+//   - An error if the name is invalid or already taken, if the organization is not found, if the role
+//     of the caller doesn't allow the call, or if there is an internal error.
+func (g *GophkeeperServer) CreateCollection(ctx context.Context, in *proto.CreateCollectionRequest) (*proto.CreateCollectionResponse, error) {
+	if !validOrgName(in.Name) {
+		logger.Log.Error("invalid collection name")
+		return nil, status.Errorf(codes.InvalidArgument, "invalid collection name")
+	}
+	if _, err := g.authorizeOrg(ctx, in.OrgId, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	id, err := g.Storage.AddCollection(ctx, &model.Collection{OrgID: in.OrgId, Name: in.Name})
+	if errors.Is(err, storage.ErrCollectionExists) {
+		logger.Log.Error("collection already exists")
+		return nil, status.Errorf(codes.AlreadyExists, "collection already exists")
+	}
+	if err != nil {
+		logger.Log.Error("error add collection", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add collection")
+	}
+	return &proto.CreateCollectionResponse{Id: id}, nil
+}
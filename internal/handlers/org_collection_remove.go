@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveCollection deletes a collection with all its items, admins and owners may call it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveCollectionRequest structure containing the ID of the collection.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the collection is not found, if the role of the caller doesn't allow the call,
+//     or if there is an internal error.
+//
+// The objects of the files of the collection are removed from MinIO after the collection is deleted.
+func (g *GophkeeperServer) RemoveCollection(ctx context.Context, in *proto.RemoveCollectionRequest) (*emptypb.Empty, error) {
+	if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	keys, err := g.Storage.RemoveCollection(ctx, in.CollectionId)
+	if err != nil {
+		logger.Log.Error("error remove collection", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove collection")
+	}
+	g.removeCollectionObjects(ctx, keys)
+	return &emptypb.Empty{}, nil
+}
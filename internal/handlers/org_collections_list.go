@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ListCollections retrieves the collections of an organization, any member may call it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.ListCollectionsRequest structure containing the ID of the organization.
+//
+// Returns:
+//   - A pointer to the proto.ListCollectionsResponse containing the collections.
+//   - An error if the organization is not found or if there is an internal error.
+func (g *GophkeeperServer) ListCollections(ctx context.Context, in *proto.ListCollectionsRequest) (*proto.ListCollectionsResponse, error) {
+	if _, err := g.authorizeOrg(ctx, in.OrgId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	collections, err := g.Storage.GetCollections(ctx, in.OrgId)
+	if err != nil {
+		logger.Log.Error("error get collections from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get collections from DB")
+	}
+
+	response := &proto.ListCollectionsResponse{Collections: make([]*proto.Collection, len(collections))}
+	for i, collection := range collections {
+		response.Collections[i] = &proto.Collection{Id: collection.ID, Name: collection.Name}
+	}
+	return response, nil
+}
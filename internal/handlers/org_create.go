@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// CreateOrganization creates an organization owned by the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.CreateOrganizationRequest structure containing the name of the organization
+//     and the new organization key wrapped with the public sharing key of the user.
+//
+// Returns:
+//   - A pointer to the proto.CreateOrganizationResponse containing the ID of the organization.
+//   - An error if the request is invalid or if there is an internal error.
+//
+// The organization key is generated by the client, the server only stores it wrapped for every member.
+func (g *GophkeeperServer) CreateOrganization(ctx context.Context, in *proto.CreateOrganizationRequest) (*proto.CreateOrganizationResponse, error) {
+	if !validOrgName(in.Name) {
+		logger.Log.Error("invalid organization name")
+		return nil, status.Errorf(codes.InvalidArgument, "invalid organization name")
+	}
+	if !validSharedKey(in.WrappedKey) {
+		logger.Log.Error("you must provide the wrapped organization key")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide the wrapped organization key")
+	}
+
+	owner := &model.OrgMember{UserID: ctx.Value(interceptors.UserID).(int64), WrappedKey: in.WrappedKey}
+	orgID, err := g.Storage.AddOrganization(ctx, in.Name, owner)
+	if err != nil {
+		logger.Log.Error("error add organization", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add organization")
+	}
+	return &proto.CreateOrganizationResponse{Id: orgID}, nil
+}
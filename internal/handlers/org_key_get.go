@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetOrgKey retrieves the organization key wrapped with the public sharing key of the user, any member may call it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetOrgKeyRequest structure selecting the organization by its ID or by the ID
+//     of one of its collections.
+//
+// Returns:
+//   - A pointer to the proto.GetOrgKeyResponse containing the ID of the organization and the wrapped key.
+//   - An error if neither ID is provided, if the organization is not found, or if there is an internal error.
+func (g *GophkeeperServer) GetOrgKey(ctx context.Context, in *proto.GetOrgKeyRequest) (*proto.GetOrgKeyResponse, error) {
+	var member *model.OrgMember
+	var err error
+	switch {
+	case in.CollectionId != 0:
+		member, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer)
+	case in.OrgId != 0:
+		member, err = g.authorizeOrg(ctx, in.OrgId, model.RoleViewer)
+	default:
+		logger.Log.Error("you must provide organization or collection id")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide organization or collection id")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetOrgKeyResponse{OrgId: member.OrgID, WrappedKey: member.WrappedKey}, nil
+}
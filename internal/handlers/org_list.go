@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ListOrganizations retrieves the organizations the user is a member of together with the role of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - _: A pointer to the proto.ListOrganizationsRequest structure (not used in this method).
+//
+// Returns:
+//   - A pointer to the proto.ListOrganizationsResponse containing the organizations.
+//   - An error if there is an internal error while retrieving the organizations from the database.
+func (g *GophkeeperServer) ListOrganizations(ctx context.Context, _ *proto.ListOrganizationsRequest) (*proto.ListOrganizationsResponse, error) {
+	orgs, err := g.Storage.GetOrganizations(ctx, ctx.Value(interceptors.UserID).(int64))
+	if err != nil {
+		logger.Log.Error("error get organizations from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get organizations from DB")
+	}
+
+	response := &proto.ListOrganizationsResponse{Organizations: make([]*proto.Organization, len(orgs))}
+	for i, org := range orgs {
+		response.Organizations[i] = &proto.Organization{Id: org.ID, Name: org.Name, Role: org.Role}
+	}
+	return response, nil
+}
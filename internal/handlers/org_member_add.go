@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// AddOrgMember adds a user to an organization.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.AddOrgMemberRequest structure containing the organization, the login and the role
+//     of the new member and the organization key wrapped with the public sharing key of the new member.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the request is invalid, if the organization or the user is not found, if the user is already
+//     a member, if the role of the caller doesn't allow the call, or if there is an internal error.
+//
+// Admins and owners may add members, the role of the new member must not be higher than the role of the caller.
+func (g *GophkeeperServer) AddOrgMember(ctx context.Context, in *proto.AddOrgMemberRequest) (*emptypb.Empty, error) {
+	if roleRanks[in.Role] == 0 {
+		logger.Log.Error("invalid role", zap.String("role", in.Role))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role")
+	}
+	if in.Login == "" || !validSharedKey(in.WrappedKey) {
+		logger.Log.Error("you must provide the login and the wrapped organization key")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide the login and the wrapped organization key")
+	}
+
+	caller, err := g.authorizeOrg(ctx, in.OrgId, model.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRole(caller.Role, in.Role) {
+		logger.Log.Error(insufficientRoleMessage, zap.String("role", caller.Role), zap.String("granted", in.Role))
+		return nil, status.Errorf(codes.PermissionDenied, insufficientRoleMessage)
+	}
+
+	user, err := g.Storage.GetPublicSharingKey(ctx, in.Login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("user not found")
+			return nil, status.Errorf(codes.NotFound, "user not found or has no sharing keys")
+		}
+		logger.Log.Error("error get public key from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get public key from DB")
+	}
+
+	member := &model.OrgMember{OrgID: in.OrgId, UserID: user.UserID, Role: in.Role, WrappedKey: in.WrappedKey}
+	if err = g.Storage.AddOrgMember(ctx, member); err != nil {
+		if errors.Is(err, storage.ErrOrgMemberExists) {
+			logger.Log.Error("user is already a member")
+			return nil, status.Errorf(codes.AlreadyExists, "user is already a member of the organization")
+		}
+		logger.Log.Error("error add organization member", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add organization member")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveOrgMember removes a member from an organization.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveOrgMemberRequest structure containing the organization and the login
+//     of the member.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the organization or the member is not found, if the role of the caller doesn't allow the call,
+//     if the last owner would be removed, or if there is an internal error.
+//
+// Any member may leave the organization, admins and owners may remove the members whose role isn't higher
+// than their own. The removed member loses access to the items of the organization on the server, but
+// the organization key isn't rotated.
+func (g *GophkeeperServer) RemoveOrgMember(ctx context.Context, in *proto.RemoveOrgMemberRequest) (*emptypb.Empty, error) {
+	caller, err := g.authorizeOrg(ctx, in.OrgId, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	member, err := g.orgMemberByLogin(ctx, in.OrgId, in.Login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("member not found")
+			return nil, status.Errorf(codes.NotFound, "member not found")
+		}
+		logger.Log.Error("error get organization member from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get organization member from DB")
+	}
+	if member.UserID != caller.UserID && (!hasRole(caller.Role, model.RoleAdmin) || !hasRole(caller.Role, member.Role)) {
+		logger.Log.Error(insufficientRoleMessage, zap.String("role", caller.Role), zap.String("removed", member.Role))
+		return nil, status.Errorf(codes.PermissionDenied, insufficientRoleMessage)
+	}
+
+	err = g.Storage.RemoveOrgMember(ctx, in.OrgId, member.UserID)
+	if errors.Is(err, storage.ErrLastOwner) {
+		logger.Log.Error("last owner can't be removed")
+		return nil, status.Errorf(codes.FailedPrecondition, "the organization must have an owner")
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("member not found")
+		return nil, status.Errorf(codes.NotFound, "member not found")
+	}
+	if err != nil {
+		logger.Log.Error("error remove organization member", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove organization member")
+	}
+	return &emptypb.Empty{}, nil
+}
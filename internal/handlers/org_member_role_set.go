@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// SetOrgMemberRole changes the role of a member of an organization.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.SetOrgMemberRoleRequest structure containing the organization, the login
+//     of the member and the new role.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the request is invalid, if the organization or the member is not found, if the role of the caller
+//     doesn't allow the call, if the last owner would be demoted, or if there is an internal error.
+//
+// Admins and owners may change the roles, neither the current nor the new role of the member may be higher
+// than the role of the caller.
+func (g *GophkeeperServer) SetOrgMemberRole(ctx context.Context, in *proto.SetOrgMemberRoleRequest) (*emptypb.Empty, error) {
+	if roleRanks[in.Role] == 0 {
+		logger.Log.Error("invalid role", zap.String("role", in.Role))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role")
+	}
+
+	caller, err := g.authorizeOrg(ctx, in.OrgId, model.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	member, err := g.orgMemberByLogin(ctx, in.OrgId, in.Login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("member not found")
+			return nil, status.Errorf(codes.NotFound, "member not found")
+		}
+		logger.Log.Error("error get organization member from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get organization member from DB")
+	}
+	if !hasRole(caller.Role, member.Role) || !hasRole(caller.Role, in.Role) {
+		logger.Log.Error(insufficientRoleMessage, zap.String("role", caller.Role), zap.String("granted", in.Role))
+		return nil, status.Errorf(codes.PermissionDenied, insufficientRoleMessage)
+	}
+
+	err = g.Storage.SetOrgMemberRole(ctx, in.OrgId, member.UserID, in.Role)
+	if errors.Is(err, storage.ErrLastOwner) {
+		logger.Log.Error("last owner can't be demoted")
+		return nil, status.Errorf(codes.FailedPrecondition, "the organization must have an owner")
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("member not found")
+		return nil, status.Errorf(codes.NotFound, "member not found")
+	}
+	if err != nil {
+		logger.Log.Error("error set organization member role", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error set organization member role")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ListOrgMembers retrieves the members of an organization, any member may call it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.ListOrgMembersRequest structure containing the ID of the organization.
+//
+// Returns:
+//   - A pointer to the proto.ListOrgMembersResponse containing the logins and the roles of the members.
+//   - An error if the organization is not found or if there is an internal error.
+func (g *GophkeeperServer) ListOrgMembers(ctx context.Context, in *proto.ListOrgMembersRequest) (*proto.ListOrgMembersResponse, error) {
+	if _, err := g.authorizeOrg(ctx, in.OrgId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := g.Storage.GetOrgMembers(ctx, in.OrgId)
+	if err != nil {
+		logger.Log.Error("error get organization members from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get organization members from DB")
+	}
+
+	response := &proto.ListOrgMembersResponse{Members: make([]*proto.OrgMember, len(members))}
+	for i, member := range members {
+		response.Members[i] = &proto.OrgMember{Login: member.Login, Role: member.Role}
+	}
+	return response, nil
+}
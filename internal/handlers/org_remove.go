@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveOrganization deletes an organization with all its collections and items, only an owner may call it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveOrganizationRequest structure containing the ID of the organization.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the organization is not found, if the user isn't its owner, or if there is an internal error.
+//
+// The objects of the files of the organization are removed from MinIO after the organization is deleted.
+func (g *GophkeeperServer) RemoveOrganization(ctx context.Context, in *proto.RemoveOrganizationRequest) (*emptypb.Empty, error) {
+	if _, err := g.authorizeOrg(ctx, in.OrgId, model.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	keys, err := g.Storage.RemoveOrganization(ctx, in.OrgId)
+	if err != nil {
+		logger.Log.Error("error remove organization", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove organization")
+	}
+	g.removeCollectionObjects(ctx, keys)
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestOrganizationRoles(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)),
+		grpc.ChainStreamInterceptor(interceptors.ValidateTokenStream(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	authorize := func(login string) context.Context {
+		cred := proto.Credentials{Login: login, Password: "password"}
+		_, err := client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+		require.NoError(t, err)
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		md := metadata.New(map[string]string{"token": resp.Token})
+		return metadata.NewOutgoingContext(context.Background(), md)
+	}
+	owner := authorize("owner")
+	viewer := authorize("viewer")
+	stranger := authorize("stranger")
+	for userID := int64(1); userID <= 3; userID++ {
+		keys := &model.SharingKeys{UserID: userID, PublicKey: []byte{byte(userID)}, PrivateKey: "private"}
+		require.NoError(t, storage.AddSharingKeys(context.Background(), keys))
+	}
+
+	org, err := client.CreateOrganization(owner, &proto.CreateOrganizationRequest{Name: "team", WrappedKey: "owner key"})
+	require.NoError(t, err)
+	_, err = client.AddOrgMember(owner, &proto.AddOrgMemberRequest{OrgId: org.Id, Login: "viewer", Role: model.RoleViewer, WrappedKey: "viewer key"})
+	require.NoError(t, err)
+	collection, err := client.CreateCollection(owner, &proto.CreateCollectionRequest{OrgId: org.Id, Name: "servers"})
+	require.NoError(t, err)
+	_, err = client.AddNote(owner, &proto.AddNoteRequest{Note: &proto.Note{Text: "text"}, CollectionId: collection.Id})
+	require.NoError(t, err)
+
+	requireCode := func(t *testing.T, code codes.Code, err error) {
+		require.Error(t, err)
+		assert.Equal(t, code, status.Code(err))
+	}
+
+	t.Run("viewer reads collection items", func(t *testing.T) {
+		notes, err := client.GetNotes(viewer, &proto.GetNotesRequest{CollectionId: collection.Id})
+		require.NoError(t, err)
+		require.Len(t, notes.Notes, 1)
+		key, err := client.GetOrgKey(viewer, &proto.GetOrgKeyRequest{CollectionId: collection.Id})
+		require.NoError(t, err)
+		assert.Equal(t, "viewer key", key.WrappedKey)
+	})
+
+	t.Run("viewer cannot change collection items", func(t *testing.T) {
+		_, err := client.RemoveNote(viewer, &proto.RemoveNoteRequest{Id: "1", CollectionId: collection.Id})
+		requireCode(t, codes.PermissionDenied, err)
+		assert.Equal(t, insufficientRoleMessage, status.Convert(err).Message())
+		_, err = client.AddNote(viewer, &proto.AddNoteRequest{Note: &proto.Note{Text: "text"}, CollectionId: collection.Id})
+		requireCode(t, codes.PermissionDenied, err)
+		_, err = client.AddOrgMember(viewer, &proto.AddOrgMemberRequest{OrgId: org.Id, Login: "stranger", Role: model.RoleViewer, WrappedKey: "key"})
+		requireCode(t, codes.PermissionDenied, err)
+	})
+
+	t.Run("collection items are not personal", func(t *testing.T) {
+		_, err := client.GetNote(owner, &proto.GetNoteRequest{Id: "1"})
+		requireCode(t, codes.NotFound, err)
+		_, err = client.RemoveNote(owner, &proto.RemoveNoteRequest{Id: "1"})
+		requireCode(t, codes.NotFound, err)
+	})
+
+	t.Run("stranger cannot see the organization", func(t *testing.T) {
+		_, err := client.GetNotes(stranger, &proto.GetNotesRequest{CollectionId: collection.Id})
+		requireCode(t, codes.NotFound, err)
+		_, err = client.ListOrgMembers(stranger, &proto.ListOrgMembersRequest{OrgId: org.Id})
+		requireCode(t, codes.NotFound, err)
+	})
+
+	t.Run("admin cannot grant owner", func(t *testing.T) {
+		_, err := client.SetOrgMemberRole(owner, &proto.SetOrgMemberRoleRequest{OrgId: org.Id, Login: "viewer", Role: model.RoleAdmin})
+		require.NoError(t, err)
+		_, err = client.SetOrgMemberRole(viewer, &proto.SetOrgMemberRoleRequest{OrgId: org.Id, Login: "viewer", Role: model.RoleOwner})
+		requireCode(t, codes.PermissionDenied, err)
+		_, err = client.RemoveOrgMember(owner, &proto.RemoveOrgMemberRequest{OrgId: org.Id, Login: "owner"})
+		requireCode(t, codes.FailedPrecondition, err)
+	})
+
+	t.Run("editor removes collection items", func(t *testing.T) {
+		_, err := client.SetOrgMemberRole(owner, &proto.SetOrgMemberRoleRequest{OrgId: org.Id, Login: "viewer", Role: model.RoleEditor})
+		require.NoError(t, err)
+		_, err = client.RemoveNote(viewer, &proto.RemoveNoteRequest{Id: "1", CollectionId: collection.Id})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/totp"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// maxOTPPeriod limits the validity period of the codes of the TOTP authenticator entries, in seconds.
+const maxOTPPeriod = 24 * 60 * 60
+
+// validOTPItem reports whether a client-provided TOTP authenticator entry has the encrypted secret and
+// code parameters the client can generate the codes with.
+func validOTPItem(item *proto.OTPItem) bool {
+	switch item.Algorithm {
+	case totp.AlgorithmSHA1, totp.AlgorithmSHA256, totp.AlgorithmSHA512:
+	default:
+		return false
+	}
+	return item.Secret != "" && item.Digits >= 6 && item.Digits <= 10 && item.Period > 0 && item.Period <= maxOTPPeriod
+}
+
+// AddOTPItem adds a new TOTP authenticator entry for the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.AddOTPItemRequest structure containing the entry details.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the secret is missing, the code parameters are invalid,
+//     or if there is an internal error while adding the entry to the storage.
+//
+// The secret, the issuer, the account and the description are encrypted by the client, the server only
+// validates the algorithm, the digits and the period of the codes.
+//
+// A non-zero collection ID adds the entry to the organization collection instead, which requires the editor role.
+func (g *GophkeeperServer) AddOTPItem(ctx context.Context, in *proto.AddOTPItemRequest) (*emptypb.Empty, error) {
+	if in.Item == nil || !validOTPItem(in.Item) {
+		logger.Log.Error("you must provide: secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+	}
+
+	item := &model.OTPItem{
+		Secret:      in.Item.Secret,
+		Issuer:      in.Item.Issuer,
+		Account:     in.Item.Account,
+		Algorithm:   in.Item.Algorithm,
+		Digits:      in.Item.Digits,
+		Period:      in.Item.Period,
+		Description: in.Item.Description,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		item.CollectionID = in.CollectionId
+	} else {
+		item.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	if err := g.Storage.AddOTPItem(ctx, item); err != nil {
+		logger.Log.Error("error add otp item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add otp item")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// otpItemToProto converts a TOTP authenticator entry to its proto form.
+func otpItemToProto(item *model.OTPItem) *proto.OTPItem {
+	return &proto.OTPItem{
+		Id:          item.ID,
+		Secret:      item.Secret,
+		Issuer:      item.Issuer,
+		Account:     item.Account,
+		Algorithm:   item.Algorithm,
+		Digits:      item.Digits,
+		Period:      item.Period,
+		Description: item.Description,
+		Version:     item.Version,
+		UpdatedAt:   item.UpdatedAt.Format(time.DateTime),
+	}
+}
+
+// GetOTPItems retrieves all TOTP authenticator entries associated with the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetOTPItemsRequest structure.
+//
+// Returns:
+//   - A pointer to the proto.GetOTPItemsResponse containing the list of entries.
+//   - An error if the operation fails, for example, if there is an internal error while
+//     retrieving the entries from the storage.
+//
+// A non-zero collection ID retrieves the entries of the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetOTPItems(ctx context.Context, in *proto.GetOTPItemsRequest) (*proto.GetOTPItemsResponse, error) {
+	var response proto.GetOTPItemsResponse
+
+	var items []*model.OTPItem
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		items, err = g.Storage.GetCollectionOTPItems(ctx, in.CollectionId)
+	} else {
+		items, err = g.Storage.GetOTPItems(ctx, ctx.Value(interceptors.UserID).(int64))
+	}
+	if err != nil {
+		logger.Log.Error("error get otp items from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get otp items from DB")
+	}
+
+	response.Items = make([]*proto.OTPItem, len(items))
+	for i, item := range items {
+		response.Items[i] = otpItemToProto(item)
+	}
+	return &response, nil
+}
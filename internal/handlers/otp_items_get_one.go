@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetOTPItem retrieves a specific TOTP authenticator entry by its ID.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetOTPItemRequest structure containing the ID of the entry.
+//
+// Returns:
+//   - A pointer to the proto.GetOTPItemResponse containing the details of the requested entry.
+//   - An error if the operation fails, for example, if the provided ID is invalid, if the entry is not found
+//     among the user's entries, or if there is an internal error while retrieving the entry from the storage.
+//
+// A non-zero collection ID looks the entry up in the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetOTPItem(ctx context.Context, in *proto.GetOTPItemRequest) (*proto.GetOTPItemResponse, error) {
+	itemID, err := strconv.ParseInt(in.Id, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "missing otp item id")
+	}
+
+	var item *model.OTPItem
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		item, err = g.Storage.GetCollectionOTPItem(ctx, in.CollectionId, itemID)
+	} else {
+		item, err = g.Storage.GetOTPItem(ctx, ctx.Value(interceptors.UserID).(int64), itemID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("otp item not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "otp item not found")
+	}
+	if err != nil {
+		logger.Log.Error("error get otp item from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get otp item from DB")
+	}
+	return &proto.GetOTPItemResponse{Item: otpItemToProto(item)}, nil
+}
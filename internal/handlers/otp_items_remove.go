@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveOTPItem removes a TOTP authenticator entry associated with the user by its ID.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveOTPItemRequest structure containing the ID of the entry to remove.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the provided ID is missing or invalid,
+//     if the entry is not found among the user's entries, or if there is an internal error while removing it.
+//
+// A non-zero collection ID removes the entry from the organization collection instead, which requires the editor role.
+func (g *GophkeeperServer) RemoveOTPItem(ctx context.Context, in *proto.RemoveOTPItemRequest) (*emptypb.Empty, error) {
+	if in.Id == "" {
+		logger.Log.Error("you must provide otp item id")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide otp item id")
+	}
+
+	itemID, err := strconv.ParseInt(in.Id, 10, 64)
+	if err != nil {
+		logger.Log.Error("invalid otp item id")
+		return nil, status.Errorf(codes.InvalidArgument, "invalid otp item id")
+	}
+
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		err = g.Storage.RemoveCollectionOTPItem(ctx, in.CollectionId, itemID)
+	} else {
+		err = g.Storage.RemoveOTPItem(ctx, ctx.Value(interceptors.UserID).(int64), itemID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("otp item not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "otp item not found")
+	}
+	if err != nil {
+		logger.Log.Error("error remove otp item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove otp item")
+	}
+	return &emptypb.Empty{}, nil
+}
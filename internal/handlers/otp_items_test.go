@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestOTPItems(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	item := &proto.OTPItem{
+		Secret:      "secret",
+		Issuer:      "issuer",
+		Account:     "account",
+		Algorithm:   "MD5",
+		Digits:      6,
+		Period:      30,
+		Description: "description",
+	}
+
+	md := metadata.New(map[string]string{"token": resp.Token})
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	t.Run("test add otp item: unsupported algorithm", func(t *testing.T) {
+		_, err = client.AddOTPItem(ctx, &proto.AddOTPItemRequest{Item: item})
+		require.ErrorContains(t, err, "you must provide: secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+	})
+
+	item.Algorithm = "SHA1"
+	item.Digits = 4
+	t.Run("test add otp item: invalid digits", func(t *testing.T) {
+		_, err = client.AddOTPItem(ctx, &proto.AddOTPItemRequest{Item: item})
+		require.ErrorContains(t, err, "you must provide: secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+	})
+
+	item.Digits = 6
+	t.Run("test add otp item: ok", func(t *testing.T) {
+		_, err = client.AddOTPItem(ctx, &proto.AddOTPItemRequest{Item: item})
+		require.NoError(t, err)
+	})
+
+	t.Run("test get otp items: ok", func(t *testing.T) {
+		resp, err := client.GetOTPItems(ctx, &proto.GetOTPItemsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, item.Secret, resp.Items[0].Secret)
+		assert.Equal(t, item.Issuer, resp.Items[0].Issuer)
+		assert.Equal(t, item.Account, resp.Items[0].Account)
+		assert.Equal(t, item.Algorithm, resp.Items[0].Algorithm)
+		assert.Equal(t, item.Digits, resp.Items[0].Digits)
+		assert.Equal(t, item.Period, resp.Items[0].Period)
+		assert.Equal(t, item.Description, resp.Items[0].Description)
+	})
+
+	t.Run("test get otp item: bad id", func(t *testing.T) {
+		_, err = client.GetOTPItem(ctx, &proto.GetOTPItemRequest{Id: ""})
+		require.ErrorContains(t, err, "missing otp item id")
+	})
+
+	t.Run("test get otp item: unknown id", func(t *testing.T) {
+		_, err = client.GetOTPItem(ctx, &proto.GetOTPItemRequest{Id: "435"})
+		require.ErrorContains(t, err, "otp item not found")
+	})
+
+	t.Run("test get otp item: ok", func(t *testing.T) {
+		resp, err := client.GetOTPItem(ctx, &proto.GetOTPItemRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, item.Secret, resp.Item.Secret)
+		assert.Equal(t, item.Issuer, resp.Item.Issuer)
+	})
+
+	t.Run("test update otp item: ok", func(t *testing.T) {
+		updated := &proto.OTPItem{Id: 1, Version: 1, Secret: "new secret", Algorithm: "SHA256", Digits: 8, Period: 60}
+		resp, err := client.UpdateOTPItem(ctx, &proto.UpdateOTPItemRequest{Item: updated})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Version)
+
+		got, err := client.GetOTPItem(ctx, &proto.GetOTPItemRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, updated.Secret, got.Item.Secret)
+		assert.Equal(t, updated.Digits, got.Item.Digits)
+		assert.Equal(t, int64(2), got.Item.Version)
+	})
+
+	t.Run("test update otp item: stale version", func(t *testing.T) {
+		_, err = client.UpdateOTPItem(ctx, &proto.UpdateOTPItemRequest{Item: &proto.OTPItem{Id: 1, Version: 1, Secret: "secret", Algorithm: "SHA1", Digits: 6, Period: 30}})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("test remove otp item: empty id", func(t *testing.T) {
+		_, err = client.RemoveOTPItem(ctx, &proto.RemoveOTPItemRequest{Id: ""})
+		require.ErrorContains(t, err, "you must provide otp item id")
+	})
+
+	t.Run("test remove otp item: bad id", func(t *testing.T) {
+		_, err = client.RemoveOTPItem(ctx, &proto.RemoveOTPItemRequest{Id: "badID"})
+		require.ErrorContains(t, err, "invalid otp item id")
+	})
+
+	t.Run("test remove otp item: ok", func(t *testing.T) {
+		_, err = client.RemoveOTPItem(ctx, &proto.RemoveOTPItemRequest{Id: "1"})
+		require.NoError(t, err)
+	})
+
+	t.Run("test remove otp item: already removed", func(t *testing.T) {
+		_, err = client.RemoveOTPItem(ctx, &proto.RemoveOTPItemRequest{Id: "1"})
+		require.ErrorContains(t, err, "otp item not found")
+	})
+}
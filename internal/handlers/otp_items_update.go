@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UpdateOTPItem replaces the otp item with the given ID by the new version sent by the client.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UpdateOTPItemRequest structure containing the new otp item details and the version they are based on.
+//
+// Returns:
+//   - A pointer to the proto.UpdateOTPItemResponse containing the new version of the otp item.
+//   - An error if the operation fails, for example, if required fields are missing, if the otp item is not found
+//     among the user's otp items, if the otp item has been changed by another client since the version was read
+//     (Aborted), or if there is an internal error while updating the otp item in the storage.
+//
+// A non-zero collection ID updates the otp item of the organization collection instead, which requires the editor role.
+func (g *GophkeeperServer) UpdateOTPItem(ctx context.Context, in *proto.UpdateOTPItemRequest) (*proto.UpdateOTPItemResponse, error) {
+	if in.Item == nil || in.Item.Id == 0 || in.Item.Version == 0 || !validOTPItem(in.Item) {
+		logger.Log.Error("you must provide: id, version, secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: id, version, secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+	}
+
+	item := &model.OTPItem{
+		ID:          in.Item.Id,
+		Version:     in.Item.Version,
+		Secret:      in.Item.Secret,
+		Issuer:      in.Item.Issuer,
+		Account:     in.Item.Account,
+		Algorithm:   in.Item.Algorithm,
+		Digits:      in.Item.Digits,
+		Period:      in.Item.Period,
+		Description: in.Item.Description,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		item.CollectionID = in.CollectionId
+	} else {
+		item.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	version, err := g.Storage.UpdateOTPItem(ctx, item)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Log.Error("otp item not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "otp item not found")
+	case errors.Is(err, storage.ErrVersionConflict):
+		logger.Log.Error("otp item version conflict", zap.Error(err))
+		return nil, status.Errorf(codes.Aborted, "otp item has been changed by another client, get it and try again")
+	case err != nil:
+		logger.Log.Error("error update otp item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error update otp item")
+	}
+	return &proto.UpdateOTPItemResponse{Version: version}, nil
+}
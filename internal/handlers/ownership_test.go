@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestOwnership(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)),
+		grpc.ChainStreamInterceptor(interceptors.ValidateTokenStream(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	authorize := func(login string) context.Context {
+		cred := proto.Credentials{Login: login, Password: "password"}
+		_, err := client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+		require.NoError(t, err)
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		md := metadata.New(map[string]string{"token": resp.Token})
+		return metadata.NewOutgoingContext(context.Background(), md)
+	}
+	owner := authorize("owner")
+	stranger := authorize("stranger")
+
+	_, err = client.AddNote(owner, &proto.AddNoteRequest{Note: &proto.Note{Text: "text"}})
+	require.NoError(t, err)
+	_, err = client.AddBankCard(owner, &proto.AddBankCardRequest{Card: &proto.BankCard{
+		Cvv:        "123",
+		ExpireDate: "2030-01-01",
+		Number:     "4111111111111111",
+		Owner:      "Name Surname",
+	}})
+	require.NoError(t, err)
+	_, err = client.AddUserCredentials(owner, &proto.AddUserCredentialsRequest{Credentials: &proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}})
+	require.NoError(t, err)
+	_, err = storage.AddFile(context.Background(), "bucketName", "owner.txt", "1/owner", "description", 1, 3)
+	require.NoError(t, err)
+
+	requireNotFound := func(t *testing.T, err error) {
+		require.Error(t, err)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	t.Run("foreign note is not accessible", func(t *testing.T) {
+		_, err := client.GetNote(stranger, &proto.GetNoteRequest{Id: "1"})
+		requireNotFound(t, err)
+		_, err = client.RemoveNote(stranger, &proto.RemoveNoteRequest{Id: "1"})
+		requireNotFound(t, err)
+		_, err = client.GetNote(owner, &proto.GetNoteRequest{Id: "1"})
+		require.NoError(t, err)
+	})
+
+	t.Run("foreign bank card is not accessible", func(t *testing.T) {
+		_, err := client.GetBankCard(stranger, &proto.GetBankCardRequest{Id: "1"})
+		requireNotFound(t, err)
+		_, err = client.RemoveBankCard(stranger, &proto.RemoveBankCardRequest{Id: "1"})
+		requireNotFound(t, err)
+		_, err = client.GetBankCard(owner, &proto.GetBankCardRequest{Id: "1"})
+		require.NoError(t, err)
+	})
+
+	t.Run("foreign credentials are not accessible", func(t *testing.T) {
+		_, err := client.GetUserCredential(stranger, &proto.GetUserCredentialRequest{Id: "1"})
+		requireNotFound(t, err)
+		_, err = client.RemoveUserCredentials(stranger, &proto.RemoveUserCredentialsRequest{Id: "1"})
+		requireNotFound(t, err)
+		_, err = client.GetUserCredential(owner, &proto.GetUserCredentialRequest{Id: "1"})
+		require.NoError(t, err)
+	})
+
+	t.Run("foreign file is not accessible", func(t *testing.T) {
+		stream, err := client.Download(stranger, &proto.FileDownloadRequest{FileName: "owner.txt"})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		requireNotFound(t, err)
+		_, err = client.RemoveFile(stranger, &proto.FileRemoveRequest{FileName: "owner.txt"})
+		requireNotFound(t, err)
+		files, err := client.GetFiles(owner, &proto.GetFilesRequest{})
+		require.NoError(t, err)
+		require.Len(t, files.Files, 1)
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/sharing"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+const (
+	// maxSharedDataLength limits the length of the client-provided shared copy of an item.
+	maxSharedDataLength = 64 * 1024
+	// maxWrappedKeyLength limits the length of the client-provided wrapped data keys and private sharing keys.
+	maxWrappedKeyLength = 256
+)
+
+// sharedItemTypes are the types of the items that can be shared.
+var sharedItemTypes = map[string]bool{
+	model.ItemTypeCard:        true,
+	model.ItemTypeCredentials: true,
+	model.ItemTypeNote:        true,
+}
+
+// validSharedKey reports whether a client-provided wrapped key is present and not too long.
+func validSharedKey(key string) bool {
+	return key != "" && len(key) <= maxWrappedKeyLength
+}
+
+// sharedCopyFromProto validates the shared copy of an item sent by a client, which consists of the item encrypted
+// with its data key and the data key wrapped for the owner.
+func sharedCopyFromProto(data, ownerKey string) error {
+	if data == "" || len(data) > maxSharedDataLength {
+		return errors.New("invalid shared item data")
+	}
+	if !validSharedKey(ownerKey) {
+		return errors.New("invalid owner key")
+	}
+	return nil
+}
+
+// sharingKeysFromProto validates the sharing keys sent by a client and converts them to the model.
+func sharingKeysFromProto(keys *proto.SharingKeys) (*model.SharingKeys, error) {
+	if keys == nil || len(keys.PublicKey) != sharing.KeySize {
+		return nil, sharing.ErrInvalidKey
+	}
+	if !validSharedKey(keys.PrivateKey) {
+		return nil, errors.New("invalid private sharing key")
+	}
+	return &model.SharingKeys{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey}, nil
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ShareItem gives another user access to an item of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.ShareItemRequest structure containing the item, the recipient and the data key
+//     of the item wrapped with the public key of the recipient. If the item isn't shared yet, the request contains
+//     the item encrypted with a new data key and the data key wrapped for the owner too.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the request is invalid, if the item or the recipient doesn't exist, if the item has been shared
+//     or unshared in the meantime, or if there is an internal error.
+//
+// The server never sees the item or its data key: the client encrypts the item and wraps the data key.
+func (g *GophkeeperServer) ShareItem(ctx context.Context, in *proto.ShareItemRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	if !sharedItemTypes[in.ItemType] {
+		logger.Log.Error("invalid item type", zap.String("itemType", in.ItemType))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid item type")
+	}
+	if in.Share == nil || in.Share.RecipientLogin == "" || !validSharedKey(in.Share.WrappedKey) {
+		logger.Log.Error("you must provide the recipient and the wrapped data key")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide the recipient and the wrapped data key")
+	}
+	if in.Data != "" || in.OwnerKey != "" {
+		if err := sharedCopyFromProto(in.Data, in.OwnerKey); err != nil {
+			logger.Log.Error("invalid shared item", zap.Error(err))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid shared item")
+		}
+	}
+
+	recipient, err := g.Storage.GetPublicSharingKey(ctx, in.Share.RecipientLogin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("recipient not found")
+			return nil, status.Errorf(codes.NotFound, "recipient not found or has no sharing keys")
+		}
+		logger.Log.Error("error get public key from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get public key from DB")
+	}
+	if recipient.UserID == userID {
+		logger.Log.Error("item can't be shared with its owner")
+		return nil, status.Errorf(codes.InvalidArgument, "item can't be shared with its owner")
+	}
+
+	item := &model.SharedItem{
+		OwnerID:  userID,
+		ItemType: in.ItemType,
+		ItemID:   in.ItemId,
+		Data:     in.Data,
+		OwnerKey: in.OwnerKey,
+	}
+	share := &model.ItemShare{RecipientID: recipient.UserID, WrappedKey: in.Share.WrappedKey}
+	if err = g.Storage.ShareItem(ctx, item, share); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("item not found")
+			return nil, status.Errorf(codes.NotFound, "item not found")
+		}
+		if errors.Is(err, storage.ErrSharedItemChanged) {
+			logger.Log.Error("shared item changed", zap.Error(err))
+			return nil, status.Errorf(codes.FailedPrecondition, "item sharing has changed, try again")
+		}
+		logger.Log.Error("error share item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error share item")
+	}
+	return &emptypb.Empty{}, nil
+}
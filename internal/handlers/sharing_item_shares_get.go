@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetItemShares retrieves the recipients of an item of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetItemSharesRequest structure containing the type and the ID of the item.
+//
+// Returns:
+//   - A pointer to the proto.GetItemSharesResponse containing the data key wrapped for the owner and the recipients
+//     with their public keys.
+//   - An error if the item type is invalid, if the item isn't shared, or if there is an internal error.
+func (g *GophkeeperServer) GetItemShares(ctx context.Context, in *proto.GetItemSharesRequest) (*proto.GetItemSharesResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	if !sharedItemTypes[in.ItemType] {
+		logger.Log.Error("invalid item type", zap.String("itemType", in.ItemType))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid item type")
+	}
+
+	item, err := g.Storage.GetSharedItem(ctx, userID, in.ItemType, in.ItemId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("item is not shared")
+			return nil, status.Errorf(codes.NotFound, "item is not shared")
+		}
+		logger.Log.Error("error get shared item from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get shared item from DB")
+	}
+
+	resp := &proto.GetItemSharesResponse{OwnerKey: item.OwnerKey}
+	for _, share := range item.Shares {
+		resp.Shares = append(resp.Shares, &proto.ItemShare{
+			RecipientLogin: share.RecipientLogin,
+			PublicKey:      share.PublicKey,
+			WrappedKey:     share.WrappedKey,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetSharingKeys retrieves the X25519 key pair the user shares items with.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - _: A pointer to the proto.GetSharingKeysRequest structure (not used in this method).
+//
+// Returns:
+//   - A pointer to the proto.GetSharingKeysResponse containing the public key and the encrypted private key.
+//   - An error if the user has no sharing keys yet, or if there is an internal error.
+func (g *GophkeeperServer) GetSharingKeys(ctx context.Context, _ *proto.GetSharingKeysRequest) (*proto.GetSharingKeysResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	keys, err := g.Storage.GetSharingKeys(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("sharing keys not found")
+			return nil, status.Errorf(codes.NotFound, "sharing keys not found")
+		}
+		logger.Log.Error("error get sharing keys from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get sharing keys from DB")
+	}
+	return &proto.GetSharingKeysResponse{
+		Keys: &proto.SharingKeys{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey},
+	}, nil
+}
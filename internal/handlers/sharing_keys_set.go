@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// SetSharingKeys stores the X25519 key pair the user shares items with.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.SetSharingKeysRequest structure containing the public key and the private key
+//     encrypted by the client with the vault key.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the keys are invalid, if the user already has sharing keys, or if there is an internal error.
+//
+// The keys can't be replaced, otherwise the items shared with the user would become inaccessible. The private
+// key is re-encrypted by RekeyVault when the vault key changes.
+func (g *GophkeeperServer) SetSharingKeys(ctx context.Context, in *proto.SetSharingKeysRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	keys, err := sharingKeysFromProto(in.Keys)
+	if err != nil {
+		logger.Log.Error("invalid sharing keys", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sharing keys")
+	}
+	keys.UserID = userID
+
+	if err = g.Storage.AddSharingKeys(ctx, keys); err != nil {
+		if errors.Is(err, storage.ErrSharingKeysExist) {
+			logger.Log.Error("sharing keys already exist")
+			return nil, status.Errorf(codes.AlreadyExists, "sharing keys already exist")
+		}
+		logger.Log.Error("error add sharing keys to DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add sharing keys to DB")
+	}
+	return &emptypb.Empty{}, nil
+}
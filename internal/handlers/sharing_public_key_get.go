@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetPublicKey retrieves the public sharing key of another user, which the data keys of the items shared with
+// that user are wrapped with.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call.
+//   - in: A pointer to the proto.GetPublicKeyRequest structure containing the login of the user.
+//
+// Returns:
+//   - A pointer to the proto.GetPublicKeyResponse containing the public key.
+//   - An error if there is no such user or the user has no sharing keys, or if there is an internal error.
+func (g *GophkeeperServer) GetPublicKey(ctx context.Context, in *proto.GetPublicKeyRequest) (*proto.GetPublicKeyResponse, error) {
+	keys, err := g.Storage.GetPublicSharingKey(ctx, in.Login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("public key not found")
+			return nil, status.Errorf(codes.NotFound, "user not found or has no sharing keys")
+		}
+		logger.Log.Error("error get public key from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get public key from DB")
+	}
+	return &proto.GetPublicKeyResponse{PublicKey: keys.PublicKey}, nil
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RevokeShare revokes the access of another user to an item of the user and re-keys the item.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RevokeShareRequest structure containing the item, the revoked recipient, the item
+//     re-encrypted with a new data key and the new data key wrapped for the owner and every remaining recipient.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the request is invalid, if the item isn't shared with the recipient, if the remaining recipients
+//     have changed in the meantime, or if there is an internal error.
+//
+// The revoked recipient may have kept the old data key, so the shared copy is replaced with the re-encrypted one.
+// If no recipients remain, the shared copy is removed and the request doesn't need to contain it.
+func (g *GophkeeperServer) RevokeShare(ctx context.Context, in *proto.RevokeShareRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	if !sharedItemTypes[in.ItemType] {
+		logger.Log.Error("invalid item type", zap.String("itemType", in.ItemType))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid item type")
+	}
+	if in.RecipientLogin == "" {
+		logger.Log.Error("you must provide the recipient")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide the recipient")
+	}
+	if len(in.Shares) != 0 {
+		if err := sharedCopyFromProto(in.Data, in.OwnerKey); err != nil {
+			logger.Log.Error("invalid shared item", zap.Error(err))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid shared item")
+		}
+	}
+
+	revoked, err := g.Storage.GetPublicSharingKey(ctx, in.RecipientLogin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("share not found")
+			return nil, status.Errorf(codes.NotFound, "share not found")
+		}
+		logger.Log.Error("error get public key from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get public key from DB")
+	}
+
+	item := &model.SharedItem{
+		OwnerID:  userID,
+		ItemType: in.ItemType,
+		ItemID:   in.ItemId,
+		Data:     in.Data,
+		OwnerKey: in.OwnerKey,
+	}
+	for _, share := range in.Shares {
+		if !validSharedKey(share.WrappedKey) {
+			logger.Log.Error("invalid wrapped data key", zap.String("recipient", share.RecipientLogin))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid wrapped data key")
+		}
+		recipient, err := g.Storage.GetPublicSharingKey(ctx, share.RecipientLogin)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				logger.Log.Error("recipient not found", zap.String("recipient", share.RecipientLogin))
+				return nil, status.Errorf(codes.FailedPrecondition, "item sharing has changed, try again")
+			}
+			logger.Log.Error("error get public key from DB", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, "error get public key from DB")
+		}
+		item.Shares = append(item.Shares, &model.ItemShare{RecipientID: recipient.UserID, WrappedKey: share.WrappedKey})
+	}
+
+	if err = g.Storage.RekeySharedItem(ctx, item, revoked.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Log.Error("share not found")
+			return nil, status.Errorf(codes.NotFound, "share not found")
+		}
+		if errors.Is(err, storage.ErrSharedItemChanged) {
+			logger.Log.Error("shared item changed", zap.Error(err))
+			return nil, status.Errorf(codes.FailedPrecondition, "item sharing has changed, try again")
+		}
+		logger.Log.Error("error revoke share", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error revoke share")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ListSharedWithMe retrieves the items other users have shared with the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - _: A pointer to the proto.ListSharedWithMeRequest structure (not used in this method).
+//
+// Returns:
+//   - A pointer to the proto.ListSharedWithMeResponse containing the encrypted items and their data keys wrapped
+//     with the public key of the user.
+//   - An error if there is an internal error while retrieving the items.
+func (g *GophkeeperServer) ListSharedWithMe(ctx context.Context, _ *proto.ListSharedWithMeRequest) (*proto.ListSharedWithMeResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	items, err := g.Storage.GetReceivedItems(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get shared items from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get shared items from DB")
+	}
+
+	resp := &proto.ListSharedWithMeResponse{}
+	for _, item := range items {
+		resp.Items = append(resp.Items, &proto.SharedItem{
+			Id:         item.ID,
+			OwnerLogin: item.OwnerLogin,
+			ItemType:   item.ItemType,
+			Data:       item.Data,
+			WrappedKey: item.Shares[0].WrappedKey,
+		})
+	}
+	return resp, nil
+}
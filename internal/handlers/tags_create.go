@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// CreateTag creates a tag of the personal items of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.CreateTagRequest structure containing the encrypted name of the tag.
+//
+// Returns:
+//   - A pointer to the proto.CreateTagResponse containing the ID of the new tag.
+//   - An error if the operation fails, for example, if the name is missing or if there is an internal error
+//     while storing the tag.
+//
+// The server can't compare the encrypted names, so the client is responsible for not creating duplicate tags.
+func (g *GophkeeperServer) CreateTag(ctx context.Context, in *proto.CreateTagRequest) (*proto.CreateTagResponse, error) {
+	if in.Name == "" {
+		logger.Log.Error("you must provide tag name")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide tag name")
+	}
+
+	id, err := g.Storage.AddTag(ctx, &model.Tag{UserID: ctx.Value(interceptors.UserID).(int64), Name: in.Name})
+	if err != nil {
+		logger.Log.Error("error add tag to DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add tag to DB")
+	}
+	return &proto.CreateTagResponse{Id: id}, nil
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// GetTags retrieves the tags of the user and the tags of the personal items of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.GetTagsRequest structure.
+//
+// Returns:
+//   - A pointer to the proto.GetTagsResponse containing the tags with their encrypted names and the tagged items.
+//   - An error if the operation fails, for example, if there is an internal error while retrieving
+//     the tags from the storage.
+func (g *GophkeeperServer) GetTags(ctx context.Context, in *proto.GetTagsRequest) (*proto.GetTagsResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	tags, err := g.Storage.GetTags(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get tags from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get tags from DB")
+	}
+	items, err := g.Storage.GetItemTags(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get item tags from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get tags from DB")
+	}
+
+	var response proto.GetTagsResponse
+	response.Tags = make([]*proto.Tag, len(tags))
+	for i, t := range tags {
+		response.Tags[i] = &proto.Tag{Id: t.ID, Name: t.Name}
+	}
+	response.Items = make([]*proto.ItemTag, len(items))
+	for i, item := range items {
+		response.Items[i] = &proto.ItemTag{ItemType: item.ItemType, ItemId: item.ItemID, TagId: item.TagID}
+	}
+	return &response, nil
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// TagItem tags a personal item of the user with a tag of the user. Tagging an item twice with a tag has no effect.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.TagItemRequest structure containing the type and the ID of the item
+//     and the ID of the tag.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the item type is unknown, if the item or the tag
+//     is not found among the user's personal items and tags, or if there is an internal error.
+func (g *GophkeeperServer) TagItem(ctx context.Context, in *proto.TagItemRequest) (*emptypb.Empty, error) {
+	if !storage.IsLabeledItemType(in.ItemType) {
+		logger.Log.Error("invalid item type", zap.String("type", in.ItemType))
+		return nil, status.Errorf(codes.InvalidArgument, invalidLabeledItemType)
+	}
+
+	err := g.Storage.TagItem(ctx, ctx.Value(interceptors.UserID).(int64), &model.ItemTag{
+		ItemType: in.ItemType,
+		ItemID:   in.ItemId,
+		TagID:    in.TagId,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("item or tag not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "item or tag not found")
+	}
+	if err != nil {
+		logger.Log.Error("error tag item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error tag item")
+	}
+	return &emptypb.Empty{}, nil
+}
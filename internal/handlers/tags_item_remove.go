@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UntagItem removes a tag of the user from a personal item of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UntagItemRequest structure containing the type and the ID of the item
+//     and the ID of the tag.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the item type is unknown, if the item isn't tagged
+//     with the tag, or if there is an internal error.
+func (g *GophkeeperServer) UntagItem(ctx context.Context, in *proto.UntagItemRequest) (*emptypb.Empty, error) {
+	if !storage.IsLabeledItemType(in.ItemType) {
+		logger.Log.Error("invalid item type", zap.String("type", in.ItemType))
+		return nil, status.Errorf(codes.InvalidArgument, invalidLabeledItemType)
+	}
+
+	err := g.Storage.UntagItem(ctx, ctx.Value(interceptors.UserID).(int64), &model.ItemTag{
+		ItemType: in.ItemType,
+		ItemID:   in.ItemId,
+		TagID:    in.TagId,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("item tag not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "item is not tagged with the tag")
+	}
+	if err != nil {
+		logger.Log.Error("error untag item", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error untag item")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RemoveTag removes a tag of the user from all the items and deletes it.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RemoveTagRequest structure containing the ID of the tag to remove.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if the tag is not found among the user's tags,
+//     or if there is an internal error while removing it.
+func (g *GophkeeperServer) RemoveTag(ctx context.Context, in *proto.RemoveTagRequest) (*emptypb.Empty, error) {
+	err := g.Storage.RemoveTag(ctx, ctx.Value(interceptors.UserID).(int64), in.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("tag not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "tag not found")
+	}
+	if err != nil {
+		logger.Log.Error("error remove tag", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove tag")
+	}
+	return &emptypb.Empty{}, nil
+}
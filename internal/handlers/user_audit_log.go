@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+const (
+	// defaultAuditLogLimit is the number of the audit events returned if the request doesn't limit them.
+	defaultAuditLogLimit = 100
+	// maxAuditLogLimit is the maximum number of the audit events returned at once.
+	maxAuditLogLimit = 1000
+)
+
+// GetAuditLog retrieves the audit log of the user: the calls made with the user's tokens and the
+// authorization attempts for the user's login.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which contains user identification information.
+//   - in: A pointer to the proto.GetAuditLogRequest structure, which contains the time range, the item type
+//     and the maximum number of the events.
+//
+// Returns:
+//   - A pointer to the proto.GetAuditLogResponse containing the events, most recent first.
+//   - An error if the time range or the limit is invalid, or if there is an internal error while
+//     retrieving the events.
+func (g *GophkeeperServer) GetAuditLog(ctx context.Context, in *proto.GetAuditLogRequest) (*proto.GetAuditLogResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	filter := &model.AuditFilter{
+		UserID:   userID,
+		ItemType: in.ItemType,
+		Limit:    int(in.Limit),
+	}
+	var err error
+	if filter.From, err = parseAuditTime(in.From); err != nil {
+		logger.Log.Error("invalid audit log start time", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid audit log start time")
+	}
+	if filter.To, err = parseAuditTime(in.To); err != nil {
+		logger.Log.Error("invalid audit log end time", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid audit log end time")
+	}
+	if filter.Limit < 0 || filter.Limit > maxAuditLogLimit {
+		logger.Log.Error("invalid audit log limit", zap.Int("limit", filter.Limit))
+		return nil, status.Errorf(codes.InvalidArgument, "audit log limit must be between 0 and %d", maxAuditLogLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLogLimit
+	}
+
+	events, err := g.Storage.GetAuditEvents(ctx, filter)
+	if err != nil {
+		logger.Log.Error("error get audit events from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get audit events from DB")
+	}
+
+	protoEvents := make([]*proto.AuditEvent, len(events))
+	for i, e := range events {
+		protoEvents[i] = &proto.AuditEvent{
+			Id:            e.ID,
+			SessionId:     e.SessionID,
+			Method:        e.Method,
+			ItemType:      e.ItemType,
+			ItemId:        e.ItemID,
+			PeerAddress:   e.PeerAddress,
+			ClientSubject: e.ClientSubject,
+			Outcome:       e.Outcome,
+			CreatedAt:     e.CreatedAt.Format(time.DateTime),
+		}
+	}
+	return &proto.GetAuditLogResponse{Events: protoEvents}, nil
+}
+
+// parseAuditTime parses a time of the audit log range in the time.DateOnly or time.DateTime format (UTC).
+// An empty string is the zero time, which doesn't restrict the range.
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateTime, value)
+}
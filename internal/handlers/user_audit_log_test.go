@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestGetAuditLog(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		interceptors.Audit(storage),
+		interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+	otherCred := proto.Credentials{
+		Login:    "otherLogin",
+		Password: "password",
+	}
+
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &otherCred})
+	require.NoError(t, err)
+	_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{
+		Credentials: &proto.Credentials{Login: "login", Password: "wrong"}})
+	require.Error(t, err)
+	auth, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+	other, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &otherCred})
+	require.NoError(t, err)
+
+	withToken := func(token string) context.Context {
+		return metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{"token": token}))
+	}
+
+	_, err = client.GetNote(withToken(auth.Token), &proto.GetNoteRequest{Id: "12"})
+	require.Error(t, err)
+
+	t.Run("test get audit log: ok", func(t *testing.T) {
+		resp, err := client.GetAuditLog(withToken(auth.Token), &proto.GetAuditLogRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Events, 3)
+
+		note := resp.Events[0]
+		assert.Equal(t, proto.Gophkeeper_GetNote_FullMethodName, note.Method)
+		assert.Equal(t, "note", note.ItemType)
+		assert.Equal(t, "12", note.ItemId)
+		assert.NotEqual(t, codes.OK.String(), note.Outcome)
+		assert.NotZero(t, note.SessionId)
+		assert.NotEmpty(t, note.PeerAddress)
+		assert.NotEmpty(t, note.CreatedAt)
+
+		assert.Equal(t, proto.Gophkeeper_Authorize_FullMethodName, resp.Events[1].Method)
+		assert.Equal(t, codes.OK.String(), resp.Events[1].Outcome)
+		assert.Equal(t, note.SessionId, resp.Events[1].SessionId)
+		assert.Equal(t, proto.Gophkeeper_Authorize_FullMethodName, resp.Events[2].Method)
+		assert.Equal(t, codes.PermissionDenied.String(), resp.Events[2].Outcome)
+	})
+
+	t.Run("test get audit log: filters", func(t *testing.T) {
+		resp, err := client.GetAuditLog(withToken(auth.Token), &proto.GetAuditLogRequest{ItemType: "user", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, resp.Events, 1)
+		assert.Equal(t, codes.OK.String(), resp.Events[0].Outcome)
+
+		resp, err = client.GetAuditLog(withToken(auth.Token), &proto.GetAuditLogRequest{To: "2000-01-01"})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Events)
+	})
+
+	t.Run("test get audit log: other user", func(t *testing.T) {
+		resp, err := client.GetAuditLog(withToken(other.Token), &proto.GetAuditLogRequest{ItemType: "note"})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Events)
+	})
+
+	t.Run("test get audit log: invalid arguments", func(t *testing.T) {
+		_, err := client.GetAuditLog(withToken(auth.Token), &proto.GetAuditLogRequest{From: "yesterday"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.GetAuditLog(withToken(auth.Token), &proto.GetAuditLogRequest{Limit: 100000})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test verify audit log", func(t *testing.T) {
+		verified, _, err := storage.VerifyAuditLog(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, verified, 6)
+	})
+}
@@ -2,35 +2,48 @@ package handlers
 
 import (
 	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
-	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/password"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
-// Authorize authenticates a user based on provided credentials and generates a JWT token.
+// Authorize authenticates a user based on provided credentials, starts a new session and generates
+// a short-lived JWT token together with a refresh token.
 //
 // Parameters:
 //   - ctx: The context for the gRPC call, which may contain user identification information.
 //   - in: A pointer to the proto.AuthorizeRequest structure, which contains the user's login credentials.
 //
 // Returns:
-//   - A pointer to the proto.AuthorizeResponse containing the generated JWT token if authentication is successful.
+//   - A pointer to the proto.AuthorizeResponse containing the generated JWT and refresh tokens and the vault
+//     parameters if authentication is successful,
+//     or a second factor challenge (SecondFactorRequired) if the user has enabled two-factor authentication
+//     and the request doesn't contain a TOTP or recovery code.
 //   - An error if the operation fails, for example, if the login or password is invalid, if there is an
 //     error retrieving the user from the database, or if there is an internal error during token generation.
 //
 // The function first checks if the login and password are provided in the request. If either is missing,
 // it logs an error and returns a PermissionDenied status. It then attempts to retrieve the user from the
 // storage using the provided login. If the user is not found or an error occurs during retrieval, it logs
-// the error and returns a PermissionDenied status. The function then decrypts the stored password and
-// compares it with the provided password. If they do not match, it logs an error and returns a
-// PermissionDenied status. If authentication is successful, it generates a JWT token for the user and
-// returns it in the response.
+// the error and returns a PermissionDenied status. The function then verifies the provided password
+// against the stored Argon2id hash (or the legacy AES-encrypted password, which is re-hashed on success).
+// If they do not match, it logs an error and returns a PermissionDenied status. If the user has enabled
+// two-factor authentication, the OTP code from the request is verified as well. If authentication is
+// successful, it creates a new server-side session for the user, recording the device name and client
+// version from the request and the peer address, and returns its tokens in the response together with
+// the vault key derivation parameters, if the user has a vault. The parameters are only returned after
+// all factors have been verified, so they can't be used to attack the master password offline.
 func (g *GophkeeperServer) Authorize(ctx context.Context, in *proto.AuthorizeRequest) (*proto.AuthorizeResponse, error) {
 	var response proto.AuthorizeResponse
 	if in.Credentials.Login == "" || in.Credentials.Password == "" {
@@ -41,26 +54,118 @@ func (g *GophkeeperServer) Authorize(ctx context.Context, in *proto.AuthorizeReq
 	u, err := g.Storage.GetUser(ctx, in.Credentials.Login)
 	if err != nil {
 		logger.Log.Error("error get user from db", zap.Error(err))
+		// Spend roughly the same time as a real verification so that unknown logins can't be detected by timing.
+		_, _ = password.Hash(in.Credentials.Password, g.PasswordParams)
 		return nil, status.Errorf(codes.PermissionDenied, "invalid user login or password")
 	}
+	// Failed attempts are recorded in the audit log of the user too.
+	interceptors.SetAuditUser(ctx, u.ID, 0)
 
-	decPwd, err := aes.Decrypt(g.DatabaseKey, u.Password)
-	if err != nil {
-		logger.Log.Error("error decrypt password", zap.Error(err))
+	if !g.verifyUserPassword(ctx, u, in.Credentials.Password) {
+		logger.Log.Error("invalid user login or password")
 		return nil, status.Errorf(codes.PermissionDenied, "invalid user login or password")
 	}
 
-	if in.Credentials.Password != decPwd {
-		logger.Log.Error("invalid user login or password", zap.Error(err))
-		return nil, status.Errorf(codes.PermissionDenied, "invalid user login or password")
+	t, err := g.Storage.GetUserTOTP(ctx, u.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("error get user totp from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user totp from DB")
+	}
+	if err == nil && t.Confirmed {
+		if in.OtpCode == "" {
+			response.SecondFactorRequired = true
+			return &response, nil
+		}
+		ok, err := g.verifySecondFactor(ctx, t, in.OtpCode)
+		if err != nil {
+			logger.Log.Error("error verify second factor", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, "error verify second factor")
+		}
+		if !ok {
+			logger.Log.Error("invalid second factor code")
+			return nil, status.Errorf(codes.PermissionDenied, "invalid second factor code")
+		}
+	}
+
+	vault, err := g.Storage.GetUserVault(ctx, u.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("error get user vault from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user vault from DB")
+	}
+	if err == nil {
+		response.Vault = vaultToProto(vault)
+	}
+
+	refreshToken, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		logger.Log.Error("error generate refresh token", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error generate refresh token")
+	}
+
+	session := &model.Session{
+		UserID:        u.ID,
+		DeviceName:    truncate(in.DeviceName, maxSessionInfoLength),
+		ClientVersion: truncate(in.ClientVersion, maxSessionInfoLength),
+		PeerAddress:   peerAddress(ctx),
+	}
+	sessionID, err := g.Storage.AddSession(ctx, session, jwt.HashRefreshToken(refreshToken), jwt.RefreshTokenExpireTime)
+	if err != nil {
+		logger.Log.Error("error add session to DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error add session to DB")
 	}
+	interceptors.SetAuditUser(ctx, u.ID, sessionID)
 
-	token, err := jwt.BuildJWTString(g.JWTKey, u.ID)
+	token, err := g.buildJWTString(u.ID, sessionID)
 	if err != nil {
 		logger.Log.Error("error build jwt string", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error build jwt string")
 	}
 
 	response.Token = token
+	response.RefreshToken = refreshToken
 	return &response, nil
 }
+
+// verifyUserPassword checks the provided password against the stored one.
+//
+// Passwords stored as Argon2id hashes are verified in constant time. Passwords stored by older versions
+// of the server are AES-encrypted with the database key; they are decrypted and compared in constant time,
+// and on success are transparently re-hashed with Argon2id so that no forced reset is needed. Hashes that
+// were produced with outdated Argon2id parameters are re-hashed as well.
+func (g *GophkeeperServer) verifyUserPassword(ctx context.Context, u *model.User, pwd string) bool {
+	if password.IsHash(u.Password) {
+		ok, err := password.Verify(pwd, u.Password)
+		if err != nil {
+			logger.Log.Error("error verify password hash", zap.Error(err))
+			return false
+		}
+		if ok && password.NeedsRehash(u.Password, g.PasswordParams) {
+			g.rehashUserPassword(ctx, u.ID, pwd)
+		}
+		return ok
+	}
+
+	decPwd, err := g.decryptSecret(u.Password)
+	if err != nil {
+		logger.Log.Error("error decrypt password", zap.Error(err))
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(decPwd), []byte(pwd)) != 1 {
+		return false
+	}
+	g.rehashUserPassword(ctx, u.ID, pwd)
+	return true
+}
+
+// rehashUserPassword stores a fresh Argon2id hash of the password. Failures are only logged,
+// since the user has already been authenticated and the migration will be retried on the next login.
+func (g *GophkeeperServer) rehashUserPassword(ctx context.Context, userID int64, pwd string) {
+	hashedPwd, err := password.Hash(pwd, g.PasswordParams)
+	if err != nil {
+		logger.Log.Error("error hash password", zap.Error(err))
+		return
+	}
+	if err = g.Storage.UpdateUserPassword(ctx, userID, hashedPwd); err != nil {
+		logger.Log.Error("error update user password", zap.Error(err))
+	}
+}
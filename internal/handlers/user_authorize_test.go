@@ -7,11 +7,16 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/aes"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/keyring"
+	"github.com/Vidkin/gophkeeper/pkg/password"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -25,7 +30,7 @@ func TestAuthorize(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
@@ -70,21 +75,68 @@ func TestAuthorize(t *testing.T) {
 		require.ErrorContains(t, err, "invalid user login or password")
 	})
 
-	gs.DatabaseKey = ""
-	t.Run("test authorize: empty database key", func(t *testing.T) {
+	t.Run("test authorize: invalid password", func(t *testing.T) {
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &proto.Credentials{Login: "login", Password: "badPassword"}})
+		require.ErrorContains(t, err, "invalid user login or password")
+	})
+
+	t.Run("test authorize: ok", func(t *testing.T) {
 		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+	})
+
+	legacyCred := proto.Credentials{
+		Login:    "legacyLogin",
+		Password: "legacyPassword",
+	}
+	encPwd, err := aes.Encrypt(gs.DatabaseKey, legacyCred.Password)
+	require.NoError(t, err)
+	err = storage.AddUser(context.Background(), legacyCred.Login, encPwd)
+	require.NoError(t, err)
+
+	gs.DatabaseKey = ""
+	t.Run("test authorize legacy user: empty database key", func(t *testing.T) {
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &legacyCred})
 		require.ErrorContains(t, err, "invalid user login or password")
 	})
 
 	gs.DatabaseKey = "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
-	t.Run("test authorize: invalid password", func(t *testing.T) {
-		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &proto.Credentials{Login: "login", Password: "badPassword"}})
+	t.Run("test authorize legacy user: invalid password", func(t *testing.T) {
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &proto.Credentials{Login: legacyCred.Login, Password: "badPassword"}})
 		require.ErrorContains(t, err, "invalid user login or password")
+		u, err := storage.GetUser(context.Background(), legacyCred.Login)
+		require.NoError(t, err)
+		assert.Equal(t, encPwd, u.Password)
 	})
 
-	t.Run("test authorize: ok", func(t *testing.T) {
-		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	t.Run("test authorize legacy user: ok and password re-hashed", func(t *testing.T) {
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &legacyCred})
+		require.NoError(t, err)
+		u, err := storage.GetUser(context.Background(), legacyCred.Login)
+		require.NoError(t, err)
+		assert.True(t, password.IsHash(u.Password))
+
+		gs.DatabaseKey = ""
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &legacyCred})
 		require.NoError(t, err)
+		gs.DatabaseKey = "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
 	})
 
+	t.Run("test authorize legacy user: retired database key", func(t *testing.T) {
+		retiredCred := proto.Credentials{Login: "retiredLogin", Password: "retiredPassword"}
+		encPwd, err := aes.Encrypt(gs.DatabaseKey, retiredCred.Password)
+		require.NoError(t, err)
+		err = storage.AddUser(context.Background(), retiredCred.Login, encPwd)
+		require.NoError(t, err)
+
+		gs.Keyring, err = keyring.New("2025", map[string]string{
+			keyring.LegacyKeyID: gs.DatabaseKey,
+			"2025":              "newDBKey2Ks5nM2J5JaI59PPEhL1xAbc",
+		})
+		require.NoError(t, err)
+		defer func() { gs.Keyring = nil }()
+
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &retiredCred})
+		require.NoError(t, err)
+	})
 }
@@ -31,6 +31,8 @@ import (
 // populating it with the user ID (extracted from the context), login, password, and description from the
 // request. If an error occurs while adding the credentials to the storage, it logs the error and returns
 // an Internal status. If the operation is successful, it returns an empty response.
+//
+// A non-zero collection ID adds the credentials to the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) AddUserCredentials(ctx context.Context, in *proto.AddUserCredentialsRequest) (*emptypb.Empty, error) {
 	if in.Credentials.Login == "" || in.Credentials.Password == "" {
 		logger.Log.Error("you must provide: login and password")
@@ -38,12 +40,20 @@ func (g *GophkeeperServer) AddUserCredentials(ctx context.Context, in *proto.Add
 	}
 
 	cred := &model.Credentials{
-		UserID:      ctx.Value(interceptors.UserID).(int64),
 		Login:       in.Credentials.Login,
 		Password:    in.Credentials.Password,
 		Description: in.Credentials.Description,
 	}
 
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		cred.CollectionID = in.CollectionId
+	} else {
+		cred.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
 	if err := g.Storage.AddUserCredentials(ctx, cred); err != nil {
 		logger.Log.Error("error add user credentials", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error add user credentials")
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
@@ -26,10 +28,21 @@ import (
 // The function fetches the user's credentials from the storage using the user ID extracted from the context.
 // If an error occurs during the retrieval, it logs the error and returns an Internal status. If the
 // operation is successful, it constructs a response containing the credentials and returns it.
-func (g *GophkeeperServer) GetUserCredentials(ctx context.Context, _ *proto.GetUserCredentialsRequest) (*proto.GetUserCredentialsResponse, error) {
+//
+// A non-zero collection ID retrieves the credentials of the organization collection instead, which requires the viewer role.
+func (g *GophkeeperServer) GetUserCredentials(ctx context.Context, in *proto.GetUserCredentialsRequest) (*proto.GetUserCredentialsResponse, error) {
 	var response proto.GetUserCredentialsResponse
 
-	creds, err := g.Storage.GetUserCredentials(ctx, ctx.Value(interceptors.UserID).(int64))
+	var creds []*model.Credentials
+	var err error
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		creds, err = g.Storage.GetCollectionCredentials(ctx, in.CollectionId)
+	} else {
+		creds, err = g.Storage.GetUserCredentials(ctx, ctx.Value(interceptors.UserID).(int64))
+	}
 	if err != nil {
 		logger.Log.Error("error get user credentials from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get user credentials from DB")
@@ -38,10 +51,14 @@ func (g *GophkeeperServer) GetUserCredentials(ctx context.Context, _ *proto.GetU
 	protoCreds := make([]*proto.Credentials, len(creds))
 	for i, cred := range creds {
 		protoCreds[i] = &proto.Credentials{
-			Login:       cred.Login,
-			Password:    cred.Password,
-			Description: cred.Description,
-			Id:          cred.ID,
+			Login:             cred.Login,
+			Password:          cred.Password,
+			Description:       cred.Description,
+			Id:                cred.ID,
+			Version:           cred.Version,
+			UpdatedAt:         cred.UpdatedAt.Format(time.DateTime),
+			CreatedAt:         cred.CreatedAt.Format(time.DateTime),
+			PasswordChangedAt: cred.PasswordChangedAt.Format(time.DateTime),
 		}
 	}
 	response.Credentials = protoCreds
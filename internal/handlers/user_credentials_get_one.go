@@ -2,13 +2,18 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -26,8 +31,11 @@ import (
 //
 // The function first attempts to convert the credential ID from a string to an integer. If the conversion
 // fails, it returns an InvalidArgument error. It then retrieves the credential from the storage using the
-// credential ID. If an error occurs during the retrieval, it logs the error and returns an Internal status.
+// credential ID. A credential that does not exist or belongs to another user results in a NotFound status,
+// any other retrieval error is logged and returned as an Internal status.
 // If the operation is successful, it constructs a response containing the credential and returns it.
+//
+// A non-zero collection ID looks the credentials up in the organization collection instead, which requires the viewer role.
 func (g *GophkeeperServer) GetUserCredential(ctx context.Context, in *proto.GetUserCredentialRequest) (*proto.GetUserCredentialResponse, error) {
 	credID, err := strconv.Atoi(in.Id)
 	if err != nil {
@@ -35,17 +43,33 @@ func (g *GophkeeperServer) GetUserCredential(ctx context.Context, in *proto.GetU
 	}
 	var response proto.GetUserCredentialResponse
 
-	cred, err := g.Storage.GetUserCredential(ctx, int64(credID))
+	var cred *model.Credentials
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+		cred, err = g.Storage.GetCollectionCredential(ctx, in.CollectionId, int64(credID))
+	} else {
+		cred, err = g.Storage.GetUserCredential(ctx, ctx.Value(interceptors.UserID).(int64), int64(credID))
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("credentials not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "credentials not found")
+	}
 	if err != nil {
 		logger.Log.Error("error get credentials from DB", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error get credentials from DB")
 	}
 
 	protoCreds := &proto.Credentials{
-		Login:       cred.Login,
-		Password:    cred.Password,
-		Description: cred.Description,
-		Id:          cred.ID,
+		Login:             cred.Login,
+		Password:          cred.Password,
+		Description:       cred.Description,
+		Id:                cred.ID,
+		Version:           cred.Version,
+		UpdatedAt:         cred.UpdatedAt.Format(time.DateTime),
+		CreatedAt:         cred.CreatedAt.Format(time.DateTime),
+		PasswordChangedAt: cred.PasswordChangedAt.Format(time.DateTime),
 	}
 	response.Credentials = protoCreds
 	return &response, nil
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"go.uber.org/zap"
@@ -10,6 +12,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -23,13 +27,15 @@ import (
 // Returns:
 //   - A pointer to an empty proto.Empty response indicating successful removal of the user credential.
 //   - An error if the operation fails, for example, if the credential ID is not provided, if the credential
-//     ID is invalid, or if there is an internal error while removing the credential from the storage.
+//     ID is invalid, if the credential is not found among the user's credentials, or if there is an internal error while removing the credential from the storage.
 //
 // The function first checks if the credential ID is provided in the request. If not, it logs an error and
 // returns an InvalidArgument status. It then attempts to parse the credential ID from a string to an int64.
 // If the parsing fails, it logs the error and returns an InvalidArgument status. If the credential ID is
 // valid, it proceeds to remove the credential from the storage. If an error occurs during the removal, it
 // logs the error and returns an Internal status. If the operation is successful, it returns an empty response.
+//
+// A non-zero collection ID removes the credentials from the organization collection instead, which requires the editor role.
 func (g *GophkeeperServer) RemoveUserCredentials(ctx context.Context, in *proto.RemoveUserCredentialsRequest) (*emptypb.Empty, error) {
 	if in.Id == "" {
 		logger.Log.Error("you must provide credentials id")
@@ -42,7 +48,19 @@ func (g *GophkeeperServer) RemoveUserCredentials(ctx context.Context, in *proto.
 		return nil, status.Errorf(codes.InvalidArgument, "invalid credentials id")
 	}
 
-	if err = g.Storage.RemoveUserCredential(ctx, credID); err != nil {
+	if in.CollectionId != 0 {
+		if _, err = g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		err = g.Storage.RemoveCollectionCredential(ctx, in.CollectionId, credID)
+	} else {
+		err = g.Storage.RemoveUserCredential(ctx, ctx.Value(interceptors.UserID).(int64), credID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("credentials not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "credentials not found")
+	}
+	if err != nil {
 		logger.Log.Error("error remove user credentials", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error remove user credentials")
 	}
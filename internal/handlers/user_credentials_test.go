@@ -10,10 +10,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -27,7 +30,7 @@ func TestUserCredentials(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
@@ -90,7 +93,7 @@ func TestUserCredentials(t *testing.T) {
 
 	t.Run("test get credential: unknown id", func(t *testing.T) {
 		_, err = client.GetUserCredential(ctx, &proto.GetUserCredentialRequest{Id: "435"})
-		require.ErrorContains(t, err, "error get credentials from DB")
+		require.ErrorContains(t, err, "credentials not found")
 	})
 
 	t.Run("test get credential: ok", func(t *testing.T) {
@@ -101,6 +104,37 @@ func TestUserCredentials(t *testing.T) {
 		assert.Equal(t, cred.Description, resp.Credentials.Description)
 	})
 
+	t.Run("test update credentials: missing version", func(t *testing.T) {
+		_, err = client.UpdateUserCredentials(ctx, &proto.UpdateUserCredentialsRequest{Credentials: &proto.Credentials{Id: 1, Login: "new login", Password: "password"}})
+		require.ErrorContains(t, err, "you must provide: id, version, login and password")
+	})
+
+	t.Run("test update credentials: unknown id", func(t *testing.T) {
+		_, err = client.UpdateUserCredentials(ctx, &proto.UpdateUserCredentialsRequest{Credentials: &proto.Credentials{Id: 435, Version: 1, Login: "new login", Password: "password"}})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("test update credentials: ok", func(t *testing.T) {
+		before, err := client.GetUserCredential(ctx, &proto.GetUserCredentialRequest{Id: "1"})
+		require.NoError(t, err)
+
+		resp, err := client.UpdateUserCredentials(ctx, &proto.UpdateUserCredentialsRequest{Credentials: &proto.Credentials{Id: 1, Version: 1, Login: "new login", Password: cred.Password, Description: "new description"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Version)
+
+		got, err := client.GetUserCredential(ctx, &proto.GetUserCredentialRequest{Id: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, "new login", got.Credentials.Login)
+		assert.Equal(t, "new description", got.Credentials.Description)
+		assert.Equal(t, int64(2), got.Credentials.Version)
+		assert.Equal(t, before.Credentials.PasswordChangedAt, got.Credentials.PasswordChangedAt)
+	})
+
+	t.Run("test update credentials: stale version", func(t *testing.T) {
+		_, err = client.UpdateUserCredentials(ctx, &proto.UpdateUserCredentialsRequest{Credentials: &proto.Credentials{Id: 1, Version: 1, Login: "other login", Password: "password"}})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
 	t.Run("test remove credentials: empty id", func(t *testing.T) {
 		_, err = client.RemoveUserCredentials(ctx, &proto.RemoveUserCredentialsRequest{Id: ""})
 		require.ErrorContains(t, err, "you must provide credentials id")
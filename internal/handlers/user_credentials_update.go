@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// UpdateUserCredentials replaces the credentials with the given ID by the new version sent by the client.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.UpdateUserCredentialsRequest structure containing the new credentials details and the version they are based on.
+//
+// Returns:
+//   - A pointer to the proto.UpdateUserCredentialsResponse containing the new version of the credentials.
+//   - An error if the operation fails, for example, if required fields are missing, if the credentials is not found
+//     among the user's credentials, if the credentials has been changed by another client since the version was read
+//     (Aborted), or if there is an internal error while updating the credentials in the storage.
+//
+// A non-zero collection ID updates the credentials of the organization collection instead, which requires the editor role.
+// If the personal credentials are shared, the request must carry the changed shared copy, which replaces the old one,
+// otherwise the update is rejected (FailedPrecondition), so that the recipients never keep a stale copy.
+func (g *GophkeeperServer) UpdateUserCredentials(ctx context.Context, in *proto.UpdateUserCredentialsRequest) (*proto.UpdateUserCredentialsResponse, error) {
+	if in.Credentials == nil || in.Credentials.Id == 0 || in.Credentials.Version == 0 || in.Credentials.Login == "" || in.Credentials.Password == "" {
+		logger.Log.Error("you must provide: id, version, login and password")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide: id, version, login and password")
+	}
+
+	cred := &model.Credentials{
+		ID:          in.Credentials.Id,
+		Version:     in.Credentials.Version,
+		Login:       in.Credentials.Login,
+		Password:    in.Credentials.Password,
+		Description: in.Credentials.Description,
+	}
+
+	if in.CollectionId != 0 {
+		if _, err := g.authorizeCollection(ctx, in.CollectionId, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		cred.CollectionID = in.CollectionId
+	} else {
+		cred.UserID = ctx.Value(interceptors.UserID).(int64)
+	}
+
+	version, err := g.Storage.UpdateUserCredentials(ctx, cred, in.SharedData)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logger.Log.Error("credentials not found", zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "credentials not found")
+	case errors.Is(err, storage.ErrVersionConflict):
+		logger.Log.Error("credentials version conflict", zap.Error(err))
+		return nil, status.Errorf(codes.Aborted, "credentials has been changed by another client, get it and try again")
+	case errors.Is(err, storage.ErrSharedItemChanged):
+		logger.Log.Error("shared credentials changed", zap.Error(err))
+		return nil, status.Errorf(codes.FailedPrecondition, "credentials sharing has changed, get it and try again")
+	case err != nil:
+		logger.Log.Error("error update credentials", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error update credentials")
+	}
+	return &proto.UpdateUserCredentialsResponse{Version: version}, nil
+}
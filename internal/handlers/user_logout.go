@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// Logout revokes the session of the current JWT token.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which contains user and session identification information.
+//   - _: A pointer to the proto.LogoutRequest structure (not used in this method).
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the session is already revoked or if there is an internal error.
+//
+// After logout neither the JWT tokens nor the refresh tokens of the session are accepted anymore.
+func (g *GophkeeperServer) Logout(ctx context.Context, _ *proto.LogoutRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	sessionID, _ := ctx.Value(interceptors.SessionID).(int64)
+
+	ok, err := g.Storage.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		logger.Log.Error("error revoke session", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error revoke session")
+	}
+	if !ok {
+		logger.Log.Error("session not found", zap.Int64("sessionID", sessionID))
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+	return &emptypb.Empty{}, nil
+}
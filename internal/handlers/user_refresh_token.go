@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RefreshToken exchanges a refresh token for a new JWT token and a new refresh token.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call.
+//   - in: A pointer to the proto.RefreshTokenRequest structure containing the refresh token.
+//
+// Returns:
+//   - A pointer to the proto.RefreshTokenResponse containing the new JWT and refresh tokens.
+//   - An error if the operation fails, for example, if the refresh token is unknown, if its session is
+//     revoked or expired, if the token has already been used, or if there is an internal error.
+//
+// Refresh tokens are rotated: every token can be exchanged only once, and the new one extends the
+// session lifetime. If an already used token is presented again, the token must have been stolen,
+// so the whole session is revoked and both the attacker and the legitimate client have to authorize again.
+func (g *GophkeeperServer) RefreshToken(ctx context.Context, in *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	if in.RefreshToken == "" {
+		logger.Log.Error("invalid refresh token")
+		return nil, status.Errorf(codes.PermissionDenied, "invalid refresh token")
+	}
+
+	refreshToken, err := jwt.GenerateRefreshToken()
+	if err != nil {
+		logger.Log.Error("error generate refresh token", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error generate refresh token")
+	}
+
+	s, err := g.Storage.RotateRefreshToken(
+		ctx, jwt.HashRefreshToken(in.RefreshToken), jwt.HashRefreshToken(refreshToken), peerAddress(ctx), jwt.RefreshTokenExpireTime)
+	if errors.Is(err, storage.ErrRefreshTokenReused) {
+		logger.Log.Error("refresh token reuse detected, session revoked")
+		return nil, status.Errorf(codes.PermissionDenied, "refresh token reuse detected, session revoked")
+	}
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, storage.ErrSessionRevoked) {
+		logger.Log.Error("invalid refresh token", zap.Error(err))
+		return nil, status.Errorf(codes.PermissionDenied, "invalid refresh token")
+	}
+	if err != nil {
+		logger.Log.Error("error rotate refresh token", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error rotate refresh token")
+	}
+
+	interceptors.SetAuditUser(ctx, s.UserID, s.ID)
+
+	token, err := g.buildJWTString(s.UserID, s.ID)
+	if err != nil {
+		logger.Log.Error("error build jwt string", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error build jwt string")
+	}
+
+	return &proto.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestRefreshToken(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	authResp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+	require.NotEmpty(t, authResp.RefreshToken)
+
+	withToken := func(token string) context.Context {
+		return metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{"token": token}))
+	}
+
+	t.Run("test refresh token: empty token", func(t *testing.T) {
+		_, err = client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{})
+		require.ErrorContains(t, err, "invalid refresh token")
+	})
+
+	t.Run("test refresh token: unknown token", func(t *testing.T) {
+		_, err = client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: "unknown"})
+		require.ErrorContains(t, err, "invalid refresh token")
+	})
+
+	refreshResp, err := client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: authResp.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, authResp.RefreshToken, refreshResp.RefreshToken)
+	assert.NotEqual(t, authResp.Token, refreshResp.Token)
+
+	t.Run("test refreshed token is accepted", func(t *testing.T) {
+		_, err = client.GetNotes(withToken(refreshResp.Token), &proto.GetNotesRequest{})
+		require.NoError(t, err)
+	})
+
+	t.Run("test refresh token: reuse revokes session", func(t *testing.T) {
+		_, err = client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: authResp.RefreshToken})
+		require.ErrorContains(t, err, "refresh token reuse detected")
+
+		_, err = client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: refreshResp.RefreshToken})
+		require.ErrorContains(t, err, "invalid refresh token")
+
+		_, err = client.GetNotes(withToken(refreshResp.Token), &proto.GetNotesRequest{})
+		require.ErrorContains(t, err, "session is revoked")
+	})
+
+	authResp, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+	otherResp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	t.Run("test logout", func(t *testing.T) {
+		_, err = client.Logout(withToken(authResp.Token), &proto.LogoutRequest{})
+		require.NoError(t, err)
+
+		_, err = client.GetNotes(withToken(authResp.Token), &proto.GetNotesRequest{})
+		require.ErrorContains(t, err, "session is revoked")
+
+		_, err = client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: authResp.RefreshToken})
+		require.ErrorContains(t, err, "invalid refresh token")
+	})
+
+	t.Run("test logout keeps other sessions", func(t *testing.T) {
+		_, err = client.GetNotes(withToken(otherResp.Token), &proto.GetNotesRequest{})
+		require.NoError(t, err)
+	})
+}
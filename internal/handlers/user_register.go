@@ -9,7 +9,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
-	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/password"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -23,15 +23,16 @@ import (
 // Returns:
 //   - A pointer to an empty proto.Empty response indicating successful registration of the user.
 //   - An error if the operation fails, for example, if the user login or password is invalid, if the
-//     user already exists, or if there is an internal error while encrypting the password or adding the
+//     user already exists, or if there is an internal error while hashing the password or adding the
 //     user to the storage.
 //
 // The function first checks if the user login and password are provided in the request. If either is
 // missing, it logs an error and returns an InvalidArgument status. It then checks if the user already
 // exists in the storage. If the user exists, it logs an error and returns an AlreadyExists status. If
-// the user does not exist, it encrypts the password using AES encryption. If the encryption fails, it
-// logs the error and returns an Internal status. Finally, if the user is successfully added to the
-// storage, it returns an empty response.
+// the user does not exist, it hashes the password with Argon2id. If the hashing fails, it logs the error
+// and returns an Internal status. If the request contains the vault key derivation parameters, they are
+// validated and stored together with the user, otherwise the user manages the encryption key on their own.
+// Finally, if the user is successfully added to the storage, it returns an empty response.
 func (g *GophkeeperServer) RegisterUser(ctx context.Context, in *proto.RegisterUserRequest) (*emptypb.Empty, error) {
 	if in.Credentials.Login == "" {
 		logger.Log.Error("invalid user login")
@@ -48,13 +49,23 @@ func (g *GophkeeperServer) RegisterUser(ctx context.Context, in *proto.RegisterU
 		return nil, status.Errorf(codes.AlreadyExists, "user already exists")
 	}
 
-	encPwd, err := aes.Encrypt(g.DatabaseKey, in.Credentials.Password)
+	hashedPwd, err := password.Hash(in.Credentials.Password, g.PasswordParams)
 	if err != nil {
-		logger.Log.Error("error encrypt password", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "error encrypt password")
+		logger.Log.Error("error hash password", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error hash password")
 	}
 
-	if err := g.Storage.AddUser(ctx, in.Credentials.Login, encPwd); err != nil {
+	if in.Vault == nil {
+		err = g.Storage.AddUser(ctx, in.Credentials.Login, hashedPwd)
+	} else {
+		vault, errVault := vaultFromProto(in.Vault)
+		if errVault != nil {
+			logger.Log.Error("invalid vault parameters", zap.Error(errVault))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid vault parameters")
+		}
+		err = g.Storage.AddUserWithVault(ctx, in.Credentials.Login, hashedPwd, vault)
+	}
+	if err != nil {
 		logger.Log.Error("error create user", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "error create user")
 	}
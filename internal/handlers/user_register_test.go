@@ -7,11 +7,14 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
 	"github.com/Vidkin/gophkeeper/internal/client"
 	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/password"
 	"github.com/Vidkin/gophkeeper/proto"
 )
 
@@ -25,7 +28,7 @@ func TestRegister(t *testing.T) {
 		DatabaseKey: "",
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken("JWTKey")))
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
 	proto.RegisterGophkeeperServer(s, gs)
 
 	listen, err := GetTLSListener(
@@ -61,19 +64,61 @@ func TestRegister(t *testing.T) {
 		require.ErrorContains(t, err, "invalid user password")
 	})
 
-	t.Run("test register: empty database key", func(t *testing.T) {
+	t.Run("test register ok", func(t *testing.T) {
 		_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
-		require.ErrorContains(t, err, "error encrypt password")
+		require.NoError(t, err)
 	})
 
-	gs.DatabaseKey = "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
-	t.Run("test register ok", func(t *testing.T) {
-		_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	t.Run("test register: password stored as argon2id hash", func(t *testing.T) {
+		u, err := storage.GetUser(context.Background(), cred.Login)
 		require.NoError(t, err)
+		assert.True(t, password.IsHash(u.Password))
+		assert.NotContains(t, u.Password, cred.Password)
 	})
 
 	t.Run("test register already exists", func(t *testing.T) {
 		_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
 		require.ErrorContains(t, err, "already exists")
 	})
+
+	vault := &proto.Vault{
+		Salt:        make([]byte, 16),
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		Verifier:    "verifier",
+	}
+	vaultCred := &proto.Credentials{Login: "vaultLogin", Password: "password"}
+
+	t.Run("test register with vault: weak parameters", func(t *testing.T) {
+		weak := &proto.Vault{Salt: vault.Salt, Memory: 1024, Iterations: 1, Parallelism: 1, Verifier: "verifier"}
+		_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: vaultCred, Vault: weak})
+		require.ErrorContains(t, err, "invalid vault parameters")
+		_, err = storage.GetUser(context.Background(), vaultCred.Login)
+		require.Error(t, err)
+	})
+
+	t.Run("test register with vault: empty verifier", func(t *testing.T) {
+		noVerifier := &proto.Vault{Salt: vault.Salt, Memory: vault.Memory, Iterations: vault.Iterations, Parallelism: vault.Parallelism}
+		_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: vaultCred, Vault: noVerifier})
+		require.ErrorContains(t, err, "invalid vault parameters")
+	})
+
+	t.Run("test register with vault ok", func(t *testing.T) {
+		_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: vaultCred, Vault: vault})
+		require.NoError(t, err)
+
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: vaultCred})
+		require.NoError(t, err)
+		require.NotNil(t, resp.Vault)
+		assert.Equal(t, vault.Salt, resp.Vault.Salt)
+		assert.Equal(t, vault.Memory, resp.Vault.Memory)
+		assert.Equal(t, vault.Iterations, resp.Vault.Iterations)
+		assert.Equal(t, vault.Parallelism, resp.Vault.Parallelism)
+		assert.Equal(t, vault.Verifier, resp.Vault.Verifier)
+
+		resp, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Vault)
+	})
 }
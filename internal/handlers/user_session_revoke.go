@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RevokeSession revokes one of the sessions of the user, for example the one of a lost device.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RevokeSessionRequest structure containing the session ID.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the session doesn't exist, belongs to another user or is already revoked,
+//     or if there is an internal error.
+//
+// After revocation neither the JWT tokens nor the refresh tokens of the session are accepted anymore.
+func (g *GophkeeperServer) RevokeSession(ctx context.Context, in *proto.RevokeSessionRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	ok, err := g.Storage.RevokeSession(ctx, userID, in.Id)
+	if err != nil {
+		logger.Log.Error("error revoke session", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error revoke session")
+	}
+	if !ok {
+		logger.Log.Error("session not found", zap.Int64("sessionID", in.Id))
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ListSessions retrieves all active sessions of the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which contains user and session identification information.
+//   - _: A pointer to the proto.ListSessionsRequest structure (not used in this method).
+//
+// Returns:
+//   - A pointer to the proto.ListSessionsResponse containing the sessions with their device name,
+//     client version, peer address, creation and last-seen time. The session of the current JWT
+//     token is marked as current.
+//   - An error if there is an internal error while retrieving the sessions.
+func (g *GophkeeperServer) ListSessions(ctx context.Context, _ *proto.ListSessionsRequest) (*proto.ListSessionsResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	sessionID, _ := ctx.Value(interceptors.SessionID).(int64)
+
+	sessions, err := g.Storage.GetSessions(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get sessions from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get sessions from DB")
+	}
+
+	protoSessions := make([]*proto.Session, len(sessions))
+	for i, s := range sessions {
+		protoSessions[i] = &proto.Session{
+			Id:            s.ID,
+			DeviceName:    s.DeviceName,
+			ClientVersion: s.ClientVersion,
+			PeerAddress:   s.PeerAddress,
+			CreatedAt:     s.CreatedAt.Format(time.DateTime),
+			LastSeenAt:    s.LastSeenAt.Format(time.DateTime),
+			Current:       s.ID == sessionID,
+		}
+	}
+	return &proto.ListSessionsResponse{Sessions: protoSessions}, nil
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestSessions(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+	otherCred := proto.Credentials{
+		Login:    "otherLogin",
+		Password: "password",
+	}
+
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &otherCred})
+	require.NoError(t, err)
+
+	laptop, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{
+		Credentials: &cred, DeviceName: "laptop", ClientVersion: "v1.0.0"})
+	require.NoError(t, err)
+	phone, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{
+		Credentials: &cred, DeviceName: "phone", ClientVersion: "v1.1.0"})
+	require.NoError(t, err)
+	other, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &otherCred})
+	require.NoError(t, err)
+
+	withToken := func(token string) context.Context {
+		return metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{"token": token}))
+	}
+
+	var laptopID, phoneID int64
+	t.Run("test list sessions: ok", func(t *testing.T) {
+		resp, err := client.ListSessions(withToken(laptop.Token), &proto.ListSessionsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Sessions, 2)
+
+		for _, s := range resp.Sessions {
+			assert.NotEmpty(t, s.PeerAddress)
+			assert.NotEmpty(t, s.CreatedAt)
+			assert.NotEmpty(t, s.LastSeenAt)
+			switch s.DeviceName {
+			case "laptop":
+				laptopID = s.Id
+				assert.True(t, s.Current)
+				assert.Equal(t, "v1.0.0", s.ClientVersion)
+			case "phone":
+				phoneID = s.Id
+				assert.False(t, s.Current)
+				assert.Equal(t, "v1.1.0", s.ClientVersion)
+			default:
+				t.Errorf("unexpected session %q", s.DeviceName)
+			}
+		}
+	})
+
+	t.Run("test revoke session: other user's session", func(t *testing.T) {
+		_, err = client.RevokeSession(withToken(other.Token), &proto.RevokeSessionRequest{Id: phoneID})
+		require.ErrorContains(t, err, "session not found")
+	})
+
+	t.Run("test revoke session: ok", func(t *testing.T) {
+		_, err = client.RevokeSession(withToken(laptop.Token), &proto.RevokeSessionRequest{Id: phoneID})
+		require.NoError(t, err)
+
+		_, err = client.ListSessions(withToken(phone.Token), &proto.ListSessionsRequest{})
+		require.ErrorContains(t, err, "session is revoked")
+
+		_, err = client.RefreshToken(context.Background(), &proto.RefreshTokenRequest{RefreshToken: phone.RefreshToken})
+		require.ErrorContains(t, err, "invalid refresh token")
+
+		resp, err := client.ListSessions(withToken(laptop.Token), &proto.ListSessionsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Sessions, 1)
+		assert.Equal(t, laptopID, resp.Sessions[0].Id)
+	})
+
+	t.Run("test revoke session: already revoked", func(t *testing.T) {
+		_, err = client.RevokeSession(withToken(laptop.Token), &proto.RevokeSessionRequest{Id: phoneID})
+		require.ErrorContains(t, err, "session not found")
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/totp"
+)
+
+const (
+	// TOTPIssuer is the issuer name shown in authenticator apps.
+	TOTPIssuer = "GophKeeper"
+	// RecoveryCodesCount is the number of recovery codes generated for a user.
+	RecoveryCodesCount = 10
+	// totpSkew is the number of time steps accepted before and after the current one.
+	totpSkew = 1
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns a new set of random recovery codes in the "xxxxx-xxxxx" form.
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodesCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode strips separators and case so that codes can be typed loosely.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// encryptRecoveryCodes encrypts every recovery code with the database key.
+func (g *GophkeeperServer) encryptRecoveryCodes(codes []string) ([]string, error) {
+	encCodes := make([]string, len(codes))
+	for i, code := range codes {
+		encCode, err := g.encryptSecret(normalizeRecoveryCode(code))
+		if err != nil {
+			return nil, err
+		}
+		encCodes[i] = encCode
+	}
+	return encCodes, nil
+}
+
+// verifySecondFactor checks a TOTP code or, failing that, a single-use recovery code of the user.
+//
+// An accepted TOTP code can't be reused: the time step of the last accepted code is persisted and
+// codes from the same or older steps are rejected. An accepted recovery code is deleted.
+func (g *GophkeeperServer) verifySecondFactor(ctx context.Context, t *model.TOTP, code string) (bool, error) {
+	secret, err := g.decryptSecret(t.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok, err := totp.Validate(code, secret, time.Now(), totp.DefaultOptions, totpSkew)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		if step <= t.LastUsedStep {
+			return false, nil
+		}
+		return g.Storage.UpdateUserTOTPStep(ctx, t.UserID, step)
+	}
+
+	codes, err := g.Storage.GetRecoveryCodes(ctx, t.UserID)
+	if err != nil {
+		return false, err
+	}
+	normalized := []byte(normalizeRecoveryCode(code))
+	for _, c := range codes {
+		decCode, err := g.decryptSecret(c.Code)
+		if err != nil {
+			return false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(decCode), normalized) == 1 {
+			return g.Storage.RemoveRecoveryCode(ctx, c.ID)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/totp"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// ConfirmTOTP completes the enrollment of a TOTP second factor and returns recovery codes.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.ConfirmTOTPRequest structure containing a code from the authenticator app.
+//
+// Returns:
+//   - A pointer to the proto.ConfirmTOTPResponse containing the single-use recovery codes.
+//   - An error if the operation fails, for example, if no enrollment is pending, if the code is invalid,
+//     or if there is an internal error while storing the recovery codes.
+//
+// The recovery codes are returned in plaintext only once; the server keeps them encrypted with the
+// database key. After a successful confirmation Authorize requires a second factor for the user.
+func (g *GophkeeperServer) ConfirmTOTP(ctx context.Context, in *proto.ConfirmTOTPRequest) (*proto.ConfirmTOTPResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	if in.Code == "" {
+		logger.Log.Error("you must provide second factor code")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide second factor code")
+	}
+
+	t, err := g.Storage.GetUserTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("two-factor authentication is not enrolled")
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enrolled")
+	}
+	if err != nil {
+		logger.Log.Error("error get user totp from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user totp from DB")
+	}
+	if t.Confirmed {
+		logger.Log.Error("two-factor authentication is already enabled")
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret, err := g.decryptSecret(t.Secret)
+	if err != nil {
+		logger.Log.Error("error decrypt totp secret", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error decrypt totp secret")
+	}
+
+	step, ok, err := totp.Validate(in.Code, secret, time.Now(), totp.DefaultOptions, totpSkew)
+	if err != nil || !ok {
+		logger.Log.Error("invalid second factor code", zap.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid second factor code")
+	}
+
+	recoveryCodes, err := generateRecoveryCodes()
+	if err != nil {
+		logger.Log.Error("error generate recovery codes", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error generate recovery codes")
+	}
+	encCodes, err := g.encryptRecoveryCodes(recoveryCodes)
+	if err != nil {
+		logger.Log.Error("error encrypt recovery codes", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error encrypt recovery codes")
+	}
+
+	if err = g.Storage.ConfirmUserTOTP(ctx, userID, step, encCodes); err != nil {
+		logger.Log.Error("error confirm user totp", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error confirm user totp")
+	}
+	return &proto.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// DisableTOTP turns off two-factor authentication for the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.DisableTOTPRequest structure containing a TOTP or recovery code.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the operation fails, for example, if two-factor authentication is not enabled,
+//     if the code is invalid, or if there is an internal error while removing the secret.
+//
+// A valid second factor is required so that a stolen access token alone can't downgrade the account.
+// The secret and all recovery codes are deleted.
+func (g *GophkeeperServer) DisableTOTP(ctx context.Context, in *proto.DisableTOTPRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	if in.Code == "" {
+		logger.Log.Error("you must provide second factor code")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide second factor code")
+	}
+
+	t, err := g.Storage.GetUserTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !t.Confirmed) {
+		logger.Log.Error("two-factor authentication is not enabled")
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	}
+	if err != nil {
+		logger.Log.Error("error get user totp from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user totp from DB")
+	}
+
+	ok, err := g.verifySecondFactor(ctx, t, in.Code)
+	if err != nil {
+		logger.Log.Error("error verify second factor", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error verify second factor")
+	}
+	if !ok {
+		logger.Log.Error("invalid second factor code")
+		return nil, status.Errorf(codes.PermissionDenied, "invalid second factor code")
+	}
+
+	if err = g.Storage.RemoveUserTOTP(ctx, userID); err != nil {
+		logger.Log.Error("error remove user totp", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error remove user totp")
+	}
+	return &emptypb.Empty{}, nil
+}
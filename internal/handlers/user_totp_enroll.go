@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/totp"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// EnrollTOTP starts the enrollment of a TOTP second factor for the user.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - _: A pointer to the proto.EnrollTOTPRequest structure (not used in this method).
+//
+// Returns:
+//   - A pointer to the proto.EnrollTOTPResponse containing the new secret and its otpauth:// URI.
+//   - An error if the operation fails, for example, if two-factor authentication is already enabled
+//     or if there is an internal error while storing the secret.
+//
+// The function generates a new random secret, encrypts it with the database key and stores it as
+// unconfirmed. Two-factor authentication is not enforced until the enrollment is confirmed with
+// ConfirmTOTP.
+func (g *GophkeeperServer) EnrollTOTP(ctx context.Context, _ *proto.EnrollTOTPRequest) (*proto.EnrollTOTPResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+
+	t, err := g.Storage.GetUserTOTP(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Log.Error("error get user totp from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user totp from DB")
+	}
+	if err == nil && t.Confirmed {
+		logger.Log.Error("two-factor authentication is already enabled")
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	u, err := g.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error get user from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user from DB")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Log.Error("error generate totp secret", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error generate totp secret")
+	}
+
+	encSecret, err := g.encryptSecret(secret)
+	if err != nil {
+		logger.Log.Error("error encrypt totp secret", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error encrypt totp secret")
+	}
+
+	if err = g.Storage.SetUserTOTP(ctx, userID, encSecret); err != nil {
+		logger.Log.Error("error save user totp", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error save user totp")
+	}
+
+	return &proto.EnrollTOTPResponse{
+		Secret: secret,
+		Uri:    totp.URI(TOTPIssuer, u.Login, secret, totp.DefaultOptions),
+	}, nil
+}
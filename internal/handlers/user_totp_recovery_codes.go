@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RegenerateRecoveryCodes replaces all recovery codes of the user with a new set.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RegenerateRecoveryCodesRequest structure containing a TOTP or recovery code.
+//
+// Returns:
+//   - A pointer to the proto.RegenerateRecoveryCodesResponse containing the new recovery codes.
+//   - An error if the operation fails, for example, if two-factor authentication is not enabled,
+//     if the code is invalid, or if there is an internal error while storing the codes.
+func (g *GophkeeperServer) RegenerateRecoveryCodes(ctx context.Context, in *proto.RegenerateRecoveryCodesRequest) (*proto.RegenerateRecoveryCodesResponse, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	if in.Code == "" {
+		logger.Log.Error("you must provide second factor code")
+		return nil, status.Errorf(codes.InvalidArgument, "you must provide second factor code")
+	}
+
+	t, err := g.Storage.GetUserTOTP(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !t.Confirmed) {
+		logger.Log.Error("two-factor authentication is not enabled")
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	}
+	if err != nil {
+		logger.Log.Error("error get user totp from DB", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error get user totp from DB")
+	}
+
+	ok, err := g.verifySecondFactor(ctx, t, in.Code)
+	if err != nil {
+		logger.Log.Error("error verify second factor", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error verify second factor")
+	}
+	if !ok {
+		logger.Log.Error("invalid second factor code")
+		return nil, status.Errorf(codes.PermissionDenied, "invalid second factor code")
+	}
+
+	recoveryCodes, err := generateRecoveryCodes()
+	if err != nil {
+		logger.Log.Error("error generate recovery codes", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error generate recovery codes")
+	}
+	encCodes, err := g.encryptRecoveryCodes(recoveryCodes)
+	if err != nil {
+		logger.Log.Error("error encrypt recovery codes", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error encrypt recovery codes")
+	}
+
+	if err = g.Storage.ReplaceRecoveryCodes(ctx, userID, encCodes); err != nil {
+		logger.Log.Error("error save recovery codes", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error save recovery codes")
+	}
+	return &proto.RegenerateRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
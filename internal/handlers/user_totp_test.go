@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/totp"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestTOTP(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+
+	resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+	require.False(t, resp.SecondFactorRequired)
+
+	md := metadata.New(map[string]string{"token": resp.Token})
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+
+	t.Run("test confirm totp: not enrolled", func(t *testing.T) {
+		_, err = client.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: "123456"})
+		require.ErrorContains(t, err, "two-factor authentication is not enrolled")
+	})
+
+	t.Run("test disable totp: not enabled", func(t *testing.T) {
+		_, err = client.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: "123456"})
+		require.ErrorContains(t, err, "two-factor authentication is not enabled")
+	})
+
+	enrollResp, err := client.EnrollTOTP(ctx, &proto.EnrollTOTPRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, enrollResp.Uri, "otpauth://totp/GophKeeper:login")
+
+	t.Run("test authorize: unconfirmed totp isn't enforced", func(t *testing.T) {
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+	})
+
+	t.Run("test confirm totp: empty code", func(t *testing.T) {
+		_, err = client.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: ""})
+		require.ErrorContains(t, err, "you must provide second factor code")
+	})
+
+	t.Run("test confirm totp: invalid code", func(t *testing.T) {
+		_, err = client.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: "abcdef"})
+		require.ErrorContains(t, err, "invalid second factor code")
+	})
+
+	now := time.Now()
+	code, err := totp.GenerateCode(enrollResp.Secret, now, nil)
+	require.NoError(t, err)
+	confirmResp, err := client.ConfirmTOTP(ctx, &proto.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+	require.Len(t, confirmResp.RecoveryCodes, RecoveryCodesCount)
+
+	t.Run("test enroll totp: already enabled", func(t *testing.T) {
+		_, err = client.EnrollTOTP(ctx, &proto.EnrollTOTPRequest{})
+		require.ErrorContains(t, err, "two-factor authentication is already enabled")
+	})
+
+	t.Run("test authorize: second factor required", func(t *testing.T) {
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		assert.True(t, resp.SecondFactorRequired)
+		assert.Empty(t, resp.Token)
+	})
+
+	t.Run("test authorize: replayed code", func(t *testing.T) {
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred, OtpCode: code})
+		require.ErrorContains(t, err, "invalid second factor code")
+	})
+
+	t.Run("test authorize: invalid code", func(t *testing.T) {
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred, OtpCode: "badCode"})
+		require.ErrorContains(t, err, "invalid second factor code")
+	})
+
+	t.Run("test authorize: ok with totp code", func(t *testing.T) {
+		nextCode, err := totp.GenerateCode(enrollResp.Secret, now.Add(30*time.Second), nil)
+		require.NoError(t, err)
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred, OtpCode: nextCode})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+	})
+
+	t.Run("test authorize: recovery code is single-use", func(t *testing.T) {
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred, OtpCode: confirmResp.RecoveryCodes[0]})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Token)
+
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred, OtpCode: confirmResp.RecoveryCodes[0]})
+		require.ErrorContains(t, err, "invalid second factor code")
+	})
+
+	t.Run("test regenerate recovery codes: ok", func(t *testing.T) {
+		resp, err := client.RegenerateRecoveryCodes(ctx, &proto.RegenerateRecoveryCodesRequest{Code: confirmResp.RecoveryCodes[1]})
+		require.NoError(t, err)
+		require.Len(t, resp.RecoveryCodes, RecoveryCodesCount)
+
+		_, err = client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred, OtpCode: confirmResp.RecoveryCodes[2]})
+		require.ErrorContains(t, err, "invalid second factor code")
+		confirmResp.RecoveryCodes = resp.RecoveryCodes
+	})
+
+	t.Run("test disable totp: invalid code", func(t *testing.T) {
+		_, err = client.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: "badCode"})
+		require.ErrorContains(t, err, "invalid second factor code")
+	})
+
+	t.Run("test disable totp: ok", func(t *testing.T) {
+		_, err = client.DisableTOTP(ctx, &proto.DisableTOTPRequest{Code: confirmResp.RecoveryCodes[0]})
+		require.NoError(t, err)
+
+		resp, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		assert.False(t, resp.SecondFactorRequired)
+		assert.NotEmpty(t, resp.Token)
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/pkg/password"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// maxVaultVerifierLength limits the length of the client-provided vault key verifier.
+const maxVaultVerifierLength = 256
+
+// vaultFromProto validates the vault parameters sent by a client and converts them to the model.
+func vaultFromProto(v *proto.Vault) (*model.Vault, error) {
+	if v.Parallelism > math.MaxUint8 {
+		return nil, password.ErrWeakKDFParams
+	}
+	vault := &model.Vault{
+		Salt:        v.Salt,
+		Verifier:    v.Verifier,
+		Memory:      v.Memory,
+		Iterations:  v.Iterations,
+		Parallelism: uint8(v.Parallelism),
+	}
+	err := password.CheckKDFParams(vault.Salt, &password.Params{
+		Memory:      vault.Memory,
+		Iterations:  vault.Iterations,
+		Parallelism: vault.Parallelism,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if vault.Verifier == "" || len(vault.Verifier) > maxVaultVerifierLength {
+		return nil, errors.New("invalid vault key verifier")
+	}
+	return vault, nil
+}
+
+// vaultToProto converts the vault parameters of a user to the proto message.
+func vaultToProto(v *model.Vault) *proto.Vault {
+	return &proto.Vault{
+		Salt:        v.Salt,
+		Memory:      v.Memory,
+		Iterations:  v.Iterations,
+		Parallelism: uint32(v.Parallelism),
+		Verifier:    v.Verifier,
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/internal/storage"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// RekeyVault replaces all the items of the user with their copies re-encrypted by the client under a new vault key.
+//
+// Parameters:
+//   - ctx: The context for the gRPC call, which may contain user identification information.
+//   - in: A pointer to the proto.RekeyVaultRequest structure containing every bank card, note, credentials,
+//     TOTP authenticator entry, custom item, file, folder and tag of the user, the private sharing key of the user
+//     if there is one and the parameters of the new vault key.
+//
+// Returns:
+//   - An empty response (emptypb.Empty) if the operation is successful.
+//   - An error if the request is invalid, if the items don't match the current items of the user,
+//     or if there is an internal error.
+//
+// The items are replaced in a single transaction, so either all of them are re-encrypted or none. Re-encrypted
+// file contents must be uploaded as staged uploads first, their object keys are referenced by the request.
+// If the items of the user have changed since the client fetched them, FailedPrecondition is returned
+// and the client has to run the rekey again. The objects of the replaced file contents are removed afterwards.
+func (g *GophkeeperServer) RekeyVault(ctx context.Context, in *proto.RekeyVaultRequest) (*emptypb.Empty, error) {
+	userID := ctx.Value(interceptors.UserID).(int64)
+	rekey := &model.VaultRekey{UserID: userID}
+
+	if in.Vault != nil {
+		vault, err := vaultFromProto(in.Vault)
+		if err != nil {
+			logger.Log.Error("invalid vault parameters", zap.Error(err))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid vault parameters")
+		}
+		rekey.Vault = vault
+	}
+
+	if in.SharingPrivateKey != "" {
+		if !validSharedKey(in.SharingPrivateKey) {
+			logger.Log.Error("invalid private sharing key")
+			return nil, status.Errorf(codes.InvalidArgument, "invalid private sharing key")
+		}
+		rekey.SharingKey = in.SharingPrivateKey
+	}
+
+	for _, card := range in.Cards {
+		if card.Cvv == "" || card.ExpireDate == "" || card.Number == "" || card.Owner == "" {
+			logger.Log.Error("you must provide: CVV, expire date, card number, card owner")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide: CVV, expire date, card number, card owner")
+		}
+		rekey.Cards = append(rekey.Cards, &model.BankCard{
+			ID:          card.Id,
+			CVV:         card.Cvv,
+			Owner:       card.Owner,
+			Number:      card.Number,
+			ExpireDate:  card.ExpireDate,
+			Description: card.Description,
+			Version:     card.Version,
+		})
+	}
+	for _, note := range in.Notes {
+		if note.Text == "" {
+			logger.Log.Error("you must provide note text")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide note text")
+		}
+		rekey.Notes = append(rekey.Notes, &model.Note{
+			ID:          note.Id,
+			Text:        note.Text,
+			Description: note.Description,
+			Version:     note.Version,
+		})
+	}
+	for _, cred := range in.Credentials {
+		if cred.Login == "" || cred.Password == "" {
+			logger.Log.Error("you must provide: login and password")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide: login and password")
+		}
+		rekey.Credentials = append(rekey.Credentials, &model.Credentials{
+			ID:          cred.Id,
+			Login:       cred.Login,
+			Password:    cred.Password,
+			Description: cred.Description,
+			Version:     cred.Version,
+		})
+	}
+	for _, item := range in.OtpItems {
+		if !validOTPItem(item) {
+			logger.Log.Error("invalid otp item")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide: secret, algorithm (SHA1, SHA256 or SHA512), digits (6-10), period")
+		}
+		rekey.OTPItems = append(rekey.OTPItems, &model.OTPItem{
+			ID:          item.Id,
+			Secret:      item.Secret,
+			Issuer:      item.Issuer,
+			Account:     item.Account,
+			Description: item.Description,
+			Version:     item.Version,
+		})
+	}
+	for _, item := range in.CustomItems {
+		if item.Name == "" {
+			logger.Log.Error("you must provide: name, fields")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide: name, fields")
+		}
+		fields, err := customItemFields(item.Fields)
+		if err != nil {
+			logger.Log.Error("invalid custom item fields", zap.Error(err))
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		rekey.CustomItems = append(rekey.CustomItems, &model.CustomItem{
+			ID:          item.Id,
+			Name:        item.Name,
+			Description: item.Description,
+			Fields:      fields,
+			Version:     item.Version,
+		})
+	}
+	for _, folder := range in.Folders {
+		if folder.Name == "" {
+			logger.Log.Error("you must provide folder name")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide folder name")
+		}
+		rekey.Folders = append(rekey.Folders, &model.Folder{ID: folder.Id, Name: folder.Name})
+	}
+	for _, tag := range in.Tags {
+		if tag.Name == "" {
+			logger.Log.Error("you must provide tag name")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide tag name")
+		}
+		rekey.Tags = append(rekey.Tags, &model.Tag{ID: tag.Id, Name: tag.Name})
+	}
+	for _, file := range in.Files {
+		if file.FileName == "" {
+			logger.Log.Error("you must provide file name")
+			return nil, status.Errorf(codes.InvalidArgument, "you must provide file name")
+		}
+		rekey.Files = append(rekey.Files, &model.File{
+			FileName:  norm.NFC.String(file.FileName),
+			ObjectKey: file.ObjectKey,
+			Version:   file.Version,
+		})
+	}
+
+	replacedKeys, err := g.Storage.RekeyVault(ctx, rekey)
+	if err != nil {
+		if errors.Is(err, storage.ErrVaultItemsChanged) {
+			logger.Log.Error("vault items changed", zap.Error(err))
+			return nil, status.Errorf(codes.FailedPrecondition, "vault items changed, run rekey again")
+		}
+		logger.Log.Error("error rekey vault", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "error rekey vault")
+	}
+
+	for _, key := range replacedKeys {
+		if errRm := g.Minio.RemoveObject(ctx, storage.MinioBucketName, key, minio.RemoveObjectOptions{ForceDelete: true}); errRm != nil {
+			logger.Log.Error("failed to remove replaced file from MinIO", zap.String("objectKey", key), zap.Error(errRm))
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
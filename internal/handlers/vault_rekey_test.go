@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/client"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+func TestRekeyVault(t *testing.T) {
+	storage, dbName := setupTestDB(t)
+	defer teardownTestDB(t, storage.Conn, dbName)
+
+	gs := &GophkeeperServer{
+		Storage:     storage,
+		JWTKey:      "JWTKey",
+		DatabaseKey: "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.ValidateToken(jwt.NewHMACKeySet("JWTKey"), storage)),
+		grpc.ChainStreamInterceptor(interceptors.ValidateTokenStream(jwt.NewHMACKeySet("JWTKey"), storage)))
+	proto.RegisterGophkeeperServer(s, gs)
+
+	listen, err := GetTLSListener(
+		"0.0.0.0:0",
+		"../../certs/public.crt",
+		"../../certs/private.key")
+	require.NoError(t, err)
+	go func() {
+		err = s.Serve(listen)
+		require.NoError(t, err)
+	}()
+	defer s.Stop()
+
+	addr := listen.Addr().(*net.TCPAddr)
+	viper.Set("address", fmt.Sprintf("127.0.0.1:%d", addr.Port))
+	viper.Set("crypto_key_public_path", "../../certs/public.crt")
+	client, conn, err := client.NewGophkeeperClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cred := proto.Credentials{
+		Login:    "login",
+		Password: "password",
+	}
+	_, err = client.RegisterUser(context.Background(), &proto.RegisterUserRequest{Credentials: &cred})
+	require.NoError(t, err)
+	auth, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+	require.NoError(t, err)
+	assert.Nil(t, auth.Vault)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{"token": auth.Token}))
+	_, err = client.AddNote(ctx, &proto.AddNoteRequest{Note: &proto.Note{Text: "old text", Description: "old"}})
+	require.NoError(t, err)
+	_, err = client.AddBankCard(ctx, &proto.AddBankCardRequest{Card: &proto.BankCard{
+		Number: "old", ExpireDate: "old", Cvv: "old", Owner: "old"}})
+	require.NoError(t, err)
+
+	notes, err := client.GetNotes(ctx, &proto.GetNotesRequest{})
+	require.NoError(t, err)
+	require.Len(t, notes.Notes, 1)
+	cards, err := client.GetBankCards(ctx, &proto.GetBankCardsRequest{})
+	require.NoError(t, err)
+	require.Len(t, cards.Cards, 1)
+
+	vault := &proto.Vault{
+		Salt:        []byte("0123456789abcdef"),
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		Verifier:    "verifier",
+	}
+	request := func() *proto.RekeyVaultRequest {
+		return &proto.RekeyVaultRequest{
+			Notes: []*proto.Note{{Id: notes.Notes[0].Id, Text: "new text", Description: "new"}},
+			Cards: []*proto.BankCard{{Id: cards.Cards[0].Id, Number: "new", ExpireDate: "new", Cvv: "new", Owner: "new"}},
+			Vault: vault,
+		}
+	}
+
+	t.Run("test rekey vault: invalid vault", func(t *testing.T) {
+		req := request()
+		req.Vault = &proto.Vault{Salt: []byte("salt"), Memory: 1, Iterations: 1, Parallelism: 1, Verifier: "verifier"}
+		_, err = client.RekeyVault(ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test rekey vault: empty note", func(t *testing.T) {
+		req := request()
+		req.Notes[0].Text = ""
+		_, err = client.RekeyVault(ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("test rekey vault: items changed", func(t *testing.T) {
+		req := request()
+		req.Cards = nil
+		_, err = client.RekeyVault(ctx, req)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		req = request()
+		req.Files = []*proto.RekeyFile{{FileName: "file.txt", ObjectKey: "1/staged"}}
+		_, err = client.RekeyVault(ctx, req)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		resp, err := client.GetNotes(ctx, &proto.GetNotesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, "old text", resp.Notes[0].Text)
+	})
+
+	t.Run("test rekey vault: ok", func(t *testing.T) {
+		_, err = client.RekeyVault(ctx, request())
+		require.NoError(t, err)
+
+		resp, err := client.GetNotes(ctx, &proto.GetNotesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Notes, 1)
+		assert.Equal(t, "new text", resp.Notes[0].Text)
+		assert.Equal(t, "new", resp.Notes[0].Description)
+
+		card, err := client.GetBankCard(ctx, &proto.GetBankCardRequest{Id: strconv.FormatInt(cards.Cards[0].Id, 10)})
+		require.NoError(t, err)
+		assert.Equal(t, "new", card.Card.Number)
+
+		auth, err := client.Authorize(context.Background(), &proto.AuthorizeRequest{Credentials: &cred})
+		require.NoError(t, err)
+		require.NotNil(t, auth.Vault)
+		assert.Equal(t, vault.Verifier, auth.Vault.Verifier)
+	})
+
+	t.Run("test rekey vault: without token", func(t *testing.T) {
+		_, err = client.RekeyVault(context.Background(), request())
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
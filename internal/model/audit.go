@@ -0,0 +1,53 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the AuditEvent struct, which represents a record of the audit log.
+package model
+
+import "time"
+
+// AuditEvent represents a call of an RPC recorded in the audit log.
+//
+// Fields:
+//   - CreatedAt: A time.Time representing the moment the call has completed.
+//   - Method: A string containing the full gRPC method name.
+//   - ItemType: A string containing the type of the item the call operated on, e.g. "card", empty if none.
+//   - ItemID: A string containing the ID (or the file name) of the item, empty if unknown.
+//   - PeerAddress: A string containing the network address of the client.
+//   - ClientSubject: A string containing the subject of the verified client certificate in the mutual TLS mode,
+//     empty otherwise.
+//   - Outcome: A string containing the gRPC status code of the call, e.g. "OK" or "PermissionDenied".
+//   - PrevHash: A string containing the hash of the previous event of the log.
+//   - Hash: A string containing the hash of the event, which covers PrevHash, so the events form a chain.
+//   - ID: An int64 representing the unique identifier of the event.
+//   - UserID: An int64 representing the user who made the call, 0 if the call is unauthenticated.
+//   - SessionID: An int64 representing the session of the call, 0 if the call is unauthenticated.
+type AuditEvent struct {
+	CreatedAt     time.Time
+	Method        string
+	ItemType      string
+	ItemID        string
+	PeerAddress   string
+	ClientSubject string
+	Outcome       string
+	PrevHash      string
+	Hash          string
+	ID            int64
+	UserID        int64
+	SessionID     int64
+}
+
+// AuditFilter selects the audit events of a user.
+//
+// Fields:
+//   - From: A time.Time selecting the events created at or after it, if not zero.
+//   - To: A time.Time selecting the events created before it, if not zero.
+//   - ItemType: A string selecting the events of the item type, if not empty.
+//   - UserID: An int64 representing the user whose events are selected.
+//   - Limit: An int limiting the number of the selected events, the most recent ones are selected.
+type AuditFilter struct {
+	From     time.Time
+	To       time.Time
+	ItemType string
+	UserID   int64
+	Limit    int
+}
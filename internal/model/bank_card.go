@@ -3,6 +3,8 @@
 // This package includes the BankCard struct, which represents a user's bank card information.
 package model
 
+import "time"
+
 // BankCard represents a bank card associated with a user.
 //
 // Fields:
@@ -12,13 +14,20 @@ package model
 //   - Number: A string representing the card number, usually 16 digits long.
 //   - Description: A string providing additional information about the bank card.
 //   - UserID: An int64 representing the unique identifier of the user associated with the bank card.
+//   - CollectionID: An int64 representing the unique identifier of the organization collection the bank card belongs to,
+//     the UserID is zero in this case.
 //   - ID: An int64 representing the unique identifier of the bank card itself.
+//   - UpdatedAt: A time.Time representing the moment of the last change of the bank card.
+//   - Version: An int64 representing the version of the bank card, increased by every update.
 type BankCard struct {
-	ExpireDate  string
-	Owner       string
-	CVV         string
-	Number      string
-	Description string
-	UserID      int64
-	ID          int64
+	ExpireDate   string
+	Owner        string
+	CVV          string
+	Number       string
+	Description  string
+	UserID       int64
+	CollectionID int64
+	ID           int64
+	UpdatedAt    time.Time
+	Version      int64
 }
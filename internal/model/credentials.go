@@ -3,6 +3,8 @@
 // This package includes the Credentials struct, which represents a user's login credentials.
 package model
 
+import "time"
+
 // Credentials represents the login credentials associated with a user.
 //
 // Fields:
@@ -10,11 +12,22 @@ package model
 //   - Password: A string containing the user's password, which should be stored securely.
 //   - Description: A string providing additional information about the credentials.
 //   - UserID: An int64 representing the unique identifier of the user associated with these credentials.
+//   - CollectionID: An int64 representing the unique identifier of the organization collection the credentials belongs to,
+//     the UserID is zero in this case.
+//   - CreatedAt: A time.Time representing the moment the credentials were stored.
 //   - ID: An int64 representing the unique identifier of the credentials themselves.
+//   - UpdatedAt: A time.Time representing the moment of the last change of the credentials.
+//   - Version: An int64 representing the version of the credentials, increased by every update.
+//   - PasswordChangedAt: A time.Time representing the moment the password was stored or last changed.
 type Credentials struct {
-	Login       string
-	Password    string
-	Description string
-	UserID      int64
-	ID          int64
+	Login             string
+	Password          string
+	Description       string
+	UserID            int64
+	CollectionID      int64
+	CreatedAt         time.Time
+	ID                int64
+	UpdatedAt         time.Time
+	Version           int64
+	PasswordChangedAt time.Time
 }
@@ -0,0 +1,72 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the CustomItem and ItemTemplate structs, which represent the items of the custom types
+// and the templates of the types defined by the users.
+package model
+
+import "time"
+
+// TemplateField represents a field of an item template.
+//
+// Fields:
+//   - Name: A string containing the name of the field, unique within the template.
+//   - Kind: A string representing the kind of the field value: text, secret, url, date or multiline.
+type TemplateField struct {
+	Name string
+	Kind string
+}
+
+// ItemTemplate represents a custom item type defined by a user.
+//
+// Fields:
+//   - Name: A string containing the name of the template, unique among the templates of the user.
+//   - Description: A string providing additional information about the template.
+//   - Fields: A slice of the ordered fields of the items of the template.
+//   - UserID: An int64 representing the unique identifier of the user who defined the template.
+//   - ID: An int64 representing the unique identifier of the template itself.
+type ItemTemplate struct {
+	Name        string
+	Description string
+	Fields      []TemplateField
+	UserID      int64
+	ID          int64
+}
+
+// ItemField represents a field of a custom item.
+//
+// Fields:
+//   - Name: A string containing the name of the field.
+//   - Kind: A string representing the kind of the field value: text, secret, url, date or multiline.
+//   - Value: A string containing the encrypted value of the field.
+type ItemField struct {
+	Name  string
+	Kind  string
+	Value string
+}
+
+// CustomItem represents an item of a custom type: the name of its template and the ordered list of its fields.
+//
+// Fields:
+//   - Template: A string containing the name of the template the item was created from.
+//   - Name: A string containing the encrypted name of the item.
+//   - Description: A string providing additional information about the item.
+//   - Fields: A slice of the ordered fields of the item.
+//   - UserID: An int64 representing the unique identifier of the user associated with the item.
+//   - CollectionID: An int64 representing the unique identifier of the organization collection the item belongs to,
+//     the UserID is zero in this case.
+//   - CreatedAt: A time.Time representing the moment the item was stored.
+//   - ID: An int64 representing the unique identifier of the item itself.
+//   - UpdatedAt: A time.Time representing the moment of the last change of the item.
+//   - Version: An int64 representing the version of the item, increased by every update.
+type CustomItem struct {
+	Template     string
+	Name         string
+	Description  string
+	Fields       []ItemField
+	UserID       int64
+	CollectionID int64
+	CreatedAt    time.Time
+	ID           int64
+	UpdatedAt    time.Time
+	Version      int64
+}
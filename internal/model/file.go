@@ -3,22 +3,33 @@
 // This package includes the File struct, which represents a file associated with a user.
 package model
 
+import "time"
+
 // File represents a file uploaded by a user.
 //
 // Fields:
 //   - CreatedAt: A string representing the date and time when the file was created (e.g., in ISO 8601 format).
 //   - BucketName: A string representing the name of the storage bucket where the file is stored.
 //   - FileName: A string containing the name of the file, including its extension.
+//   - ObjectKey: A string representing the opaque key of the file object in the storage bucket.
 //   - Description: A string providing additional information about the file.
 //   - UserID: An int64 representing the unique identifier of the user who uploaded the file.
+//   - CollectionID: An int64 representing the unique identifier of the organization collection the file belongs to,
+//     the UserID is zero in this case.
 //   - ID: An int64 representing the unique identifier of the file itself.
+//   - UpdatedAt: A time.Time representing the moment of the last change of the file.
+//   - Version: An int64 representing the version of the file, increased by every update.
 //   - FileSize: An int64 representing the size of the file in bytes.
 type File struct {
-	CreatedAt   string
-	BucketName  string
-	FileName    string
-	Description string
-	UserID      int64
-	ID          int64
-	FileSize    int64
+	CreatedAt    string
+	BucketName   string
+	FileName     string
+	ObjectKey    string
+	Description  string
+	UserID       int64
+	CollectionID int64
+	ID           int64
+	UpdatedAt    time.Time
+	Version      int64
+	FileSize     int64
 }
@@ -0,0 +1,55 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the Folder, Tag, ItemFolder and ItemTag structs, which organize the personal items
+// of a user into hierarchical folders and tag them.
+package model
+
+// Folder represents a folder of the personal items of a user.
+//
+// Fields:
+//   - Name: A string containing the name of the folder encrypted by the client.
+//   - UserID: An int64 representing the unique identifier of the user who owns the folder.
+//   - ParentID: An int64 representing the unique identifier of the parent folder, or zero for a top-level folder.
+//   - ID: An int64 representing the unique identifier of the folder itself.
+type Folder struct {
+	Name     string
+	UserID   int64
+	ParentID int64
+	ID       int64
+}
+
+// Tag represents a free-form tag of the personal items of a user.
+//
+// Fields:
+//   - Name: A string containing the name of the tag encrypted by the client.
+//   - UserID: An int64 representing the unique identifier of the user who owns the tag.
+//   - ID: An int64 representing the unique identifier of the tag itself.
+type Tag struct {
+	Name   string
+	UserID int64
+	ID     int64
+}
+
+// ItemFolder represents the folder an item is placed in, an item is placed in one folder at most.
+//
+// Fields:
+//   - ItemType: A string containing the type of the item, one of the ItemType constants.
+//   - ItemID: An int64 representing the unique identifier of the item.
+//   - FolderID: An int64 representing the unique identifier of the folder.
+type ItemFolder struct {
+	ItemType string
+	ItemID   int64
+	FolderID int64
+}
+
+// ItemTag represents a tag of an item.
+//
+// Fields:
+//   - ItemType: A string containing the type of the item, one of the ItemType constants.
+//   - ItemID: An int64 representing the unique identifier of the item.
+//   - TagID: An int64 representing the unique identifier of the tag.
+type ItemTag struct {
+	ItemType string
+	ItemID   int64
+	TagID    int64
+}
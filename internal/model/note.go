@@ -3,16 +3,25 @@
 // This package includes the Note struct, which represents a note created by a user.
 package model
 
+import "time"
+
 // Note represents a note associated with a user.
 //
 // Fields:
 //   - Text: A string containing the content of the note.
 //   - Description: A string providing additional information about the note.
 //   - UserID: An int64 representing the unique identifier of the user who created the note.
+//   - CollectionID: An int64 representing the unique identifier of the organization collection the note belongs to,
+//     the UserID is zero in this case.
 //   - ID: An int64 representing the unique identifier of the note itself.
+//   - UpdatedAt: A time.Time representing the moment of the last change of the note.
+//   - Version: An int64 representing the version of the note, increased by every update.
 type Note struct {
-	Text        string
-	Description string
-	UserID      int64
-	ID          int64
+	Text         string
+	Description  string
+	UserID       int64
+	CollectionID int64
+	ID           int64
+	UpdatedAt    time.Time
+	Version      int64
 }
@@ -0,0 +1,53 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the Organization, OrgMember and Collection structs, which describe the vaults
+// shared by the members of an organization.
+package model
+
+// The roles of the organization members, from the most to the least privileged.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Organization represents an organization owning collections of items.
+//
+// Fields:
+//   - Name: A string containing the name of the organization.
+//   - Role: A string containing the role of the user the organization is retrieved for.
+//   - ID: An int64 representing the unique identifier of the organization.
+type Organization struct {
+	Name string
+	Role string
+	ID   int64
+}
+
+// OrgMember represents the membership of a user in an organization.
+//
+// Fields:
+//   - Login: A string containing the login of the member.
+//   - Role: A string containing the role of the member, one of the Role constants.
+//   - WrappedKey: A string containing the organization key wrapped with the public sharing key of the member.
+//   - OrgID: An int64 representing the unique identifier of the organization.
+//   - UserID: An int64 representing the unique identifier of the member.
+type OrgMember struct {
+	Login      string
+	Role       string
+	WrappedKey string
+	OrgID      int64
+	UserID     int64
+}
+
+// Collection represents a collection of items of an organization.
+//
+// Fields:
+//   - Name: A string containing the name of the collection.
+//   - OrgID: An int64 representing the unique identifier of the organization.
+//   - ID: An int64 representing the unique identifier of the collection.
+type Collection struct {
+	Name  string
+	OrgID int64
+	ID    int64
+}
@@ -0,0 +1,38 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the OTPItem struct, which represents a TOTP authenticator entry of a user.
+package model
+
+import "time"
+
+// OTPItem represents a TOTP authenticator entry: the shared secret of an account and the parameters
+// of its one-time codes.
+//
+// Fields:
+//   - Secret: A string containing the encrypted base32 shared secret.
+//   - Issuer: A string containing the encrypted name of the service issuing the codes.
+//   - Account: A string containing the encrypted name of the account at the service.
+//   - Algorithm: A string representing the HMAC algorithm of the codes: SHA1, SHA256 or SHA512.
+//   - Description: A string providing additional information about the entry.
+//   - UserID: An int64 representing the unique identifier of the user associated with the entry.
+//   - CollectionID: An int64 representing the unique identifier of the organization collection the entry belongs to,
+//     the UserID is zero in this case.
+//   - ID: An int64 representing the unique identifier of the entry itself.
+//   - UpdatedAt: A time.Time representing the moment of the last change of the entry.
+//   - Version: An int64 representing the version of the entry, increased by every update.
+//   - Digits: An int32 representing the number of digits of the codes.
+//   - Period: An int32 representing the validity period of the codes, in seconds.
+type OTPItem struct {
+	Secret       string
+	Issuer       string
+	Account      string
+	Algorithm    string
+	Description  string
+	UserID       int64
+	CollectionID int64
+	ID           int64
+	UpdatedAt    time.Time
+	Version      int64
+	Digits       int32
+	Period       int32
+}
@@ -0,0 +1,35 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the VaultRekey struct, which describes the user data re-encrypted under a new key.
+package model
+
+// VaultRekey represents all the items of a user re-encrypted by the client under a new vault key.
+//
+// Fields:
+//   - Cards: A slice of the re-encrypted bank cards of the user.
+//   - Notes: A slice of the re-encrypted notes of the user.
+//   - Credentials: A slice of the re-encrypted credentials of the user.
+//   - OTPItems: A slice of the re-encrypted TOTP authenticator entries of the user.
+//   - CustomItems: A slice of the re-encrypted custom items of the user.
+//   - Folders: A slice of the folders of the user with the re-encrypted names.
+//   - Tags: A slice of the tags of the user with the re-encrypted names.
+//   - Files: A slice of the files of the user, where ObjectKey refers to the staged upload with the re-encrypted
+//     content, or is empty if the file content is kept as is.
+//   - Vault: A pointer to the parameters of the new vault key, or nil if the new key is not derived from
+//     a master password.
+//   - SharingKey: A string containing the private sharing key of the user re-encrypted under the new vault key,
+//     or empty if the user has no sharing keys.
+//   - UserID: An int64 representing the unique identifier of the user.
+type VaultRekey struct {
+	Cards       []*BankCard
+	Notes       []*Note
+	Credentials []*Credentials
+	OTPItems    []*OTPItem
+	CustomItems []*CustomItem
+	Folders     []*Folder
+	Tags        []*Tag
+	Files       []*File
+	Vault       *Vault
+	SharingKey  string
+	UserID      int64
+}
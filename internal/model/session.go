@@ -0,0 +1,31 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the Session struct, which represents a server-side login session.
+package model
+
+import "time"
+
+// Session represents a login session of a user. A session is created by a successful authorization
+// and lives as long as its refresh tokens keep being rotated, until it expires or is revoked.
+//
+// Fields:
+//   - ExpiresAt: A time.Time representing the moment after which the session can't be refreshed anymore.
+//   - CreatedAt: A time.Time representing the moment the session was created.
+//   - LastSeenAt: A time.Time representing the moment the session was last used.
+//   - DeviceName: A string containing the name of the device the user has logged in from.
+//   - ClientVersion: A string containing the version of the client application.
+//   - PeerAddress: A string containing the network address the session was last used from.
+//   - ID: An int64 representing the unique identifier of the session.
+//   - UserID: An int64 representing the unique identifier of the session owner.
+//   - Revoked: A bool indicating whether the session has been revoked by logout or refresh token reuse.
+type Session struct {
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	LastSeenAt    time.Time
+	DeviceName    string
+	ClientVersion string
+	PeerAddress   string
+	ID            int64
+	UserID        int64
+	Revoked       bool
+}
@@ -0,0 +1,63 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the SharingKeys, SharedItem and ItemShare structs, which describe the items
+// shared between users.
+package model
+
+// The types of the items, the cards, the credentials and the notes can be shared.
+const (
+	ItemTypeCard        = "card"
+	ItemTypeCredentials = "credentials"
+	ItemTypeNote        = "note"
+	ItemTypeFile        = "file"
+	ItemTypeOTP         = "otp"
+	ItemTypeCustom      = "custom"
+)
+
+// SharingKeys represents the X25519 key pair a user shares items with.
+//
+// Fields:
+//   - PublicKey: A byte slice containing the public key, which wraps the data keys of the items shared with the user.
+//   - PrivateKey: A string containing the private key encrypted by the client with the vault key of the user.
+//   - UserID: An int64 representing the unique identifier of the user.
+type SharingKeys struct {
+	PublicKey  []byte
+	PrivateKey string
+	UserID     int64
+}
+
+// SharedItem represents a copy of an item shared by its owner, encrypted with a data key of its own.
+//
+// Fields:
+//   - ItemType: A string containing the type of the item, one of the ItemType constants.
+//   - Data: A string containing the item encrypted with the data key.
+//   - OwnerKey: A string containing the data key wrapped for the owner.
+//   - OwnerLogin: A string containing the login of the owner.
+//   - Shares: A slice of the data keys wrapped for the recipients.
+//   - ID: An int64 representing the unique identifier of the shared item.
+//   - OwnerID: An int64 representing the unique identifier of the owner.
+//   - ItemID: An int64 representing the unique identifier of the original item.
+type SharedItem struct {
+	ItemType   string
+	Data       string
+	OwnerKey   string
+	OwnerLogin string
+	Shares     []*ItemShare
+	ID         int64
+	OwnerID    int64
+	ItemID     int64
+}
+
+// ItemShare represents the access of a recipient to a shared item.
+//
+// Fields:
+//   - RecipientLogin: A string containing the login of the recipient.
+//   - WrappedKey: A string containing the data key of the item wrapped for the recipient.
+//   - PublicKey: A byte slice containing the public sharing key of the recipient.
+//   - RecipientID: An int64 representing the unique identifier of the recipient.
+type ItemShare struct {
+	RecipientLogin string
+	WrappedKey     string
+	PublicKey      []byte
+	RecipientID    int64
+}
@@ -0,0 +1,30 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the TOTP and RecoveryCode structs, which represent a user's second authentication factor.
+package model
+
+// TOTP represents the time-based one-time password settings of a user.
+//
+// Fields:
+//   - Secret: A string containing the shared TOTP secret, encrypted with the database key.
+//   - UserID: An int64 representing the unique identifier of the user.
+//   - LastUsedStep: An int64 representing the time step of the last accepted code, used to reject replays.
+//   - Confirmed: A bool indicating whether the user has confirmed the enrollment with a valid code.
+type TOTP struct {
+	Secret       string
+	UserID       int64
+	LastUsedStep int64
+	Confirmed    bool
+}
+
+// RecoveryCode represents a single-use backup code that can replace a TOTP code.
+//
+// Fields:
+//   - Code: A string containing the recovery code, encrypted with the database key.
+//   - UserID: An int64 representing the unique identifier of the user.
+//   - ID: An int64 representing the unique identifier of the recovery code itself.
+type RecoveryCode struct {
+	Code   string
+	UserID int64
+	ID     int64
+}
@@ -0,0 +1,23 @@
+// Package model defines the data structures used in the application.
+//
+// This package includes the Vault struct, which describes how the vault key of a user is derived.
+package model
+
+// Vault represents the parameters a client needs to derive the vault encryption key of a user
+// from the master password. The server never sees the master password or the derived key.
+//
+// Fields:
+//   - Salt: A byte slice containing the random Argon2id salt.
+//   - Verifier: A string containing a known value encrypted with the vault key, used to detect a wrong master password.
+//   - UserID: An int64 representing the unique identifier of the user.
+//   - Memory: A uint32 representing the Argon2id memory cost, in KiB.
+//   - Iterations: A uint32 representing the Argon2id time cost.
+//   - Parallelism: A uint8 representing the Argon2id parallelism.
+type Vault struct {
+	Salt        []byte
+	Verifier    string
+	UserID      int64
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
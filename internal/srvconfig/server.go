@@ -4,12 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"go.uber.org/zap"
 
 	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/keyring"
+	"github.com/Vidkin/gophkeeper/pkg/password"
+	"github.com/Vidkin/gophkeeper/pkg/signature"
 )
 
 // ServerConfig holds the configuration settings for the server.
@@ -21,17 +29,32 @@ import (
 type ServerConfig struct {
 	ServerAddress        *ServerAddress `json:"address"`
 	LogLevel             string
-	MinioEndpoint        string `env:"MINIO_ENDPOINT"`
-	MinioAccessKeyID     string `env:"MINIO_ACCESS_KEY_ID"`
-	MinioSecretAccessKey string `env:"MINIO_SECRET_ACCESS_KEY"`
-	ConfigPath           string `env:"CONFIG"`
-	DatabaseDSN          string `env:"DATABASE_DSN" json:"database_dsn"`
-	DatabaseKey          string `env:"DATABASE_KEY"`
-	JWTKey               string `env:"JWT_KEY"`
-	Key                  string `env:"KEY" json:"hash_key"`
-	CryptoKeyPublic      string `env:"CRYPTO_KEY_PUBLIC"`
-	CryptoKeyPrivate     string `env:"CRYPTO_KEY_PRIVATE"`
+	MinioEndpoint        string        `env:"MINIO_ENDPOINT"`
+	MinioAccessKeyID     string        `env:"MINIO_ACCESS_KEY_ID"`
+	MinioSecretAccessKey string        `env:"MINIO_SECRET_ACCESS_KEY"`
+	ConfigPath           string        `env:"CONFIG"`
+	DatabaseDSN          string        `env:"DATABASE_DSN" json:"database_dsn"`
+	DatabaseKey          string        `env:"DATABASE_KEY"`
+	DatabaseKeys         string        `env:"DATABASE_KEYS"`
+	DatabaseKeyID        string        `env:"DATABASE_KEY_ID"`
+	JWTKey               string        `env:"JWT_KEY"`
+	JWTSigningKey        string        `env:"JWT_SIGNING_KEY"`
+	JWTVerificationKeys  string        `env:"JWT_VERIFICATION_KEYS"`
+	Key                  string        `env:"KEY" json:"hash_key"`
+	SignatureSkew        time.Duration `env:"SIGNATURE_SKEW"`
+	CryptoKeyPublic      string        `env:"CRYPTO_KEY_PUBLIC"`
+	CryptoKeyPrivate     string        `env:"CRYPTO_KEY_PRIVATE"`
+	ClientCA             string        `env:"CLIENT_CA"`
 	RetryCount           int
+	Argon2Memory         uint          `env:"ARGON2_MEMORY"`
+	Argon2Time           uint          `env:"ARGON2_TIME"`
+	Argon2Threads        uint          `env:"ARGON2_THREADS"`
+	AuthFreeAttempts     int           `env:"AUTH_FREE_ATTEMPTS"`
+	AuthMaxFailures      int           `env:"AUTH_MAX_FAILURES"`
+	AuthMaxAddrFailures  int           `env:"AUTH_MAX_ADDRESS_FAILURES"`
+	AuthBackoff          time.Duration `env:"AUTH_BACKOFF"`
+	AuthBackoffMax       time.Duration `env:"AUTH_BACKOFF_MAX"`
+	AuthLockout          time.Duration `env:"AUTH_LOCKOUT"`
 }
 
 // NewServerConfig initializes a new ServerConfig instance with default values
@@ -59,14 +82,29 @@ func (config *ServerConfig) parseFlags() error {
 	fs.StringVar(&config.ConfigPath, "config", "", "Path to json config file")
 	fs.StringVar(&config.LogLevel, "l", "info", "Log level")
 	fs.StringVar(&config.DatabaseDSN, "d", "", "Database DSN")
-	fs.StringVar(&config.Key, "k", "", "Hash key")
-	fs.StringVar(&config.JWTKey, "j", "", "JWT secret key")
+	fs.StringVar(&config.Key, "k", "", "Request signing key")
+	fs.DurationVar(&config.SignatureSkew, "signature-skew", signature.DefaultSkew, "Maximum difference between the request signature timestamp and the server clock")
+	fs.StringVar(&config.JWTKey, "j", "", "JWT secret key (HS256), only verifies tokens if -jwt-signing-key is passed")
+	fs.StringVar(&config.JWTSigningKey, "jwt-signing-key", "", "Path to Ed25519 or RSA private key pem file to sign JWT with")
+	fs.StringVar(&config.JWTVerificationKeys, "jwt-verification-keys", "", "Path to directory with public key pem files (*.pem, *.pub) of the JWT keys being rotated in or out")
 	fs.StringVar(&config.MinioEndpoint, "minio-endpoint", "", "Minio endpoint host:port")
 	fs.StringVar(&config.MinioSecretAccessKey, "minio-secret", "", "Minio secret access key")
 	fs.StringVar(&config.MinioAccessKeyID, "minio-id", "", "Minio access key ID")
 	fs.StringVar(&config.DatabaseKey, "db-key", "", "Database secret key to encrypt/decrypt data (32 bytes length)")
+	fs.StringVar(&config.DatabaseKeys, "db-keys", "", "Additional database secret keys in the id=key,id=key form")
+	fs.StringVar(&config.DatabaseKeyID, "db-key-id", keyring.LegacyKeyID, "ID of the database secret key to encrypt new data with (the -db-key is \""+keyring.LegacyKeyID+"\")")
 	fs.StringVar(&config.CryptoKeyPublic, "crypto-key-public", "", "Path to public key pem file")
 	fs.StringVar(&config.CryptoKeyPrivate, "crypto-key-private", "", "Path to private key pem file")
+	fs.StringVar(&config.ClientCA, "client-ca", "", "Path to CA certificate pem file, enables mutual TLS: clients must present a certificate signed by this CA")
+	fs.UintVar(&config.Argon2Memory, "argon2-memory", uint(password.DefaultParams.Memory), "Argon2id memory cost for password hashing (KiB)")
+	fs.UintVar(&config.Argon2Time, "argon2-time", uint(password.DefaultParams.Iterations), "Argon2id time cost (iterations) for password hashing")
+	fs.UintVar(&config.Argon2Threads, "argon2-threads", uint(password.DefaultParams.Parallelism), "Argon2id parallelism for password hashing")
+	fs.IntVar(&config.AuthFreeAttempts, "auth-free-attempts", interceptors.DefaultRateLimits.FreeAttempts, "Failed authorization attempts allowed before the next attempts are delayed")
+	fs.IntVar(&config.AuthMaxFailures, "auth-max-failures", interceptors.DefaultRateLimits.MaxLoginFailures, "Failed authorization attempts after which the login is locked out")
+	fs.IntVar(&config.AuthMaxAddrFailures, "auth-max-address-failures", interceptors.DefaultRateLimits.MaxAddressFailures, "Failed authorization or registration attempts after which the client address is locked out")
+	fs.DurationVar(&config.AuthBackoff, "auth-backoff", interceptors.DefaultRateLimits.BackoffBase, "Initial delay of the attempts after the free ones, doubled after every failure")
+	fs.DurationVar(&config.AuthBackoffMax, "auth-backoff-max", interceptors.DefaultRateLimits.BackoffMax, "Maximum delay of the attempts after the free ones")
+	fs.DurationVar(&config.AuthLockout, "auth-lockout", interceptors.DefaultRateLimits.Lockout, "Lockout duration, failed attempts are also forgotten after it")
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		logger.Log.Error("error parse server flags", zap.Error(err))
@@ -91,10 +129,14 @@ func (config *ServerConfig) parseFlags() error {
 		return errors.New("you must pass the path to public and private keys pem files, see --help")
 	}
 
-	if config.DatabaseKey == "" || len(config.DatabaseKey) != 32 {
+	if config.DatabaseKey == "" && config.DatabaseKeys == "" {
 		return errors.New("you must pass the database secret key (32-bytes), see --help")
 	}
 
+	if _, err = config.Keyring(); err != nil {
+		return fmt.Errorf("you must pass correct database secret keys: %w, see --help", err)
+	}
+
 	if config.MinioEndpoint == "" {
 		return errors.New("you must pass correct endpoint to minio service, see --help")
 	}
@@ -107,13 +149,90 @@ func (config *ServerConfig) parseFlags() error {
 		return errors.New("you must pass correct minio access key id, see --help")
 	}
 
-	if config.JWTKey == "" {
-		return errors.New("you must pass the JWT secret key, see --help")
+	if config.JWTKey == "" && config.JWTSigningKey == "" {
+		return errors.New("you must pass the JWT secret key or signing key, see --help")
+	}
+
+	if _, err = config.JWTKeys(); err != nil {
+		return fmt.Errorf("you must pass correct JWT keys: %w, see --help", err)
+	}
+
+	if config.Argon2Memory == 0 || config.Argon2Time == 0 || config.Argon2Threads == 0 || config.Argon2Threads > 255 {
+		return errors.New("you must pass correct argon2 parameters, see --help")
+	}
+
+	if config.SignatureSkew <= 0 {
+		return errors.New("you must pass correct signature clock skew, see --help")
+	}
+
+	if config.AuthFreeAttempts < 0 || config.AuthMaxFailures <= config.AuthFreeAttempts ||
+		config.AuthMaxAddrFailures <= config.AuthFreeAttempts || config.AuthBackoff <= 0 ||
+		config.AuthBackoffMax < config.AuthBackoff || config.AuthLockout <= 0 {
+		return errors.New("you must pass correct authorization attempt limits, see --help")
 	}
 
 	return nil
 }
 
+// PasswordParams returns the Argon2id parameters used to hash user passwords.
+func (config *ServerConfig) PasswordParams() *password.Params {
+	return &password.Params{
+		Memory:      uint32(config.Argon2Memory),
+		Iterations:  uint32(config.Argon2Time),
+		Parallelism: uint8(config.Argon2Threads),
+		SaltLength:  password.DefaultParams.SaltLength,
+		KeyLength:   password.DefaultParams.KeyLength,
+	}
+}
+
+// RateLimits returns the limits of failed authorization and registration attempts.
+func (config *ServerConfig) RateLimits() *interceptors.RateLimits {
+	return &interceptors.RateLimits{
+		FreeAttempts:       config.AuthFreeAttempts,
+		MaxLoginFailures:   config.AuthMaxFailures,
+		MaxAddressFailures: config.AuthMaxAddrFailures,
+		BackoffBase:        config.AuthBackoff,
+		BackoffMax:         config.AuthBackoffMax,
+		Lockout:            config.AuthLockout,
+	}
+}
+
+// Keyring returns the database secret keys. The -db-key is the key with the keyring.LegacyKeyID ID,
+// it also decrypts the data encrypted before the key IDs were introduced.
+func (config *ServerConfig) Keyring() (*keyring.Keyring, error) {
+	keys, err := keyring.ParseKeys(config.DatabaseKeys)
+	if err != nil {
+		return nil, err
+	}
+	if config.DatabaseKey != "" {
+		if _, ok := keys[keyring.LegacyKeyID]; ok {
+			return nil, fmt.Errorf("%w: key %q is passed twice", keyring.ErrInvalidKey, keyring.LegacyKeyID)
+		}
+		keys[keyring.LegacyKeyID] = config.DatabaseKey
+	}
+	return keyring.New(config.DatabaseKeyID, keys)
+}
+
+// JWTKeys loads the keys to sign and verify the access tokens. The -j secret key signs the tokens
+// unless the -jwt-signing-key is passed, in which case it only verifies the tokens issued before.
+// Every *.pem and *.pub file of the -jwt-verification-keys directory is a trusted public key.
+func (config *ServerConfig) JWTKeys() (*jwt.KeySet, error) {
+	var files []string
+	if config.JWTVerificationKeys != "" {
+		entries, err := os.ReadDir(config.JWTVerificationKeys)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.Type().IsRegular() && (ext == ".pem" || ext == ".pub") {
+				files = append(files, filepath.Join(config.JWTVerificationKeys, entry.Name()))
+			}
+		}
+	}
+	return jwt.LoadKeySet(config.JWTKey, config.JWTSigningKey, files)
+}
+
 func (config *ServerConfig) loadJSONConfig(path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
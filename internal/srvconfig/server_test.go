@@ -1,11 +1,21 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/interceptors"
+	"github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/signature"
 )
 
 func TestNewServerConfig(t *testing.T) {
@@ -26,6 +36,162 @@ func TestNewServerConfig(t *testing.T) {
 	assert.Equal(t, "localhost:8080", config.ServerAddress.Address)
 	assert.Equal(t, "debug", config.LogLevel)
 	assert.Equal(t, 3, config.RetryCount)
+	assert.Equal(t, uint32(64*1024), config.PasswordParams().Memory)
+	assert.Equal(t, uint32(3), config.PasswordParams().Iterations)
+	assert.Equal(t, uint8(2), config.PasswordParams().Parallelism)
+}
+
+func TestNewServerConfig_Argon2Params(t *testing.T) {
+	os.Args = []string{
+		"cmd",
+		"-crypto-key-private", "path",
+		"-crypto-key-public", "path",
+		"-db-key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+		"-minio-endpoint", "test",
+		"-minio-secret", "test",
+		"-minio-id", "test",
+		"-j", "test",
+		"-argon2-memory", "1024",
+		"-argon2-time", "1",
+		"-argon2-threads", "4"}
+	config, err := NewServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1024), config.PasswordParams().Memory)
+	assert.Equal(t, uint32(1), config.PasswordParams().Iterations)
+	assert.Equal(t, uint8(4), config.PasswordParams().Parallelism)
+
+	os.Args = append(os.Args, "-argon2-threads", "0")
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass correct argon2 parameters")
+}
+
+func TestNewServerConfig_RateLimits(t *testing.T) {
+	os.Args = []string{
+		"cmd",
+		"-crypto-key-private", "path",
+		"-crypto-key-public", "path",
+		"-db-key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+		"-minio-endpoint", "test",
+		"-minio-secret", "test",
+		"-minio-id", "test",
+		"-j", "test"}
+	config, err := NewServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, interceptors.DefaultRateLimits, config.RateLimits())
+
+	os.Args = append(os.Args, "-auth-max-failures", "5", "-auth-lockout", "1h")
+	config, err = NewServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5, config.RateLimits().MaxLoginFailures)
+	assert.Equal(t, time.Hour, config.RateLimits().Lockout)
+
+	os.Args = append(os.Args, "-auth-free-attempts", "5")
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass correct authorization attempt limits")
+}
+
+func TestNewServerConfig_SignatureSkew(t *testing.T) {
+	os.Args = []string{
+		"cmd",
+		"-crypto-key-private", "path",
+		"-crypto-key-public", "path",
+		"-db-key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+		"-minio-endpoint", "test",
+		"-minio-secret", "test",
+		"-minio-id", "test",
+		"-j", "test"}
+	config, err := NewServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, signature.DefaultSkew, config.SignatureSkew)
+
+	os.Args = append(os.Args, "-signature-skew", "30s")
+	config, err = NewServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, config.SignatureSkew)
+
+	os.Args = append(os.Args, "-signature-skew", "0s")
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass correct signature clock skew")
+}
+
+func TestNewServerConfig_JWTKeys(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "jwt.key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	der, err = x509.MarshalPKIXPublicKey(private.Public())
+	require.NoError(t, err)
+	verificationDir := filepath.Join(dir, "trusted")
+	require.NoError(t, os.Mkdir(verificationDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(verificationDir, "jwt.pub"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(verificationDir, "README"), []byte("not a key"), 0600))
+
+	args := []string{
+		"cmd",
+		"-crypto-key-private", "path",
+		"-crypto-key-public", "path",
+		"-db-key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+		"-minio-endpoint", "test",
+		"-minio-secret", "test",
+		"-minio-id", "test"}
+
+	os.Args = append(args, "-jwt-signing-key", keyFile)
+	config, err := NewServerConfig()
+	require.NoError(t, err)
+	keys, err := config.JWTKeys()
+	require.NoError(t, err)
+	assert.NotEqual(t, jwt.HMACKeyID, keys.SigningKeyID())
+
+	os.Args = append(args, "-j", "test", "-jwt-verification-keys", verificationDir)
+	config, err = NewServerConfig()
+	require.NoError(t, err)
+	keys, err = config.JWTKeys()
+	require.NoError(t, err)
+	assert.Len(t, keys.JWKS(), 1)
+
+	os.Args = append(args, "-j", "test", "-jwt-verification-keys", filepath.Join(dir, "missing"))
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass correct JWT keys")
+
+	os.Args = args
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass the JWT secret key or signing key")
+}
+
+func TestNewServerConfig_DatabaseKeys(t *testing.T) {
+	args := []string{
+		"cmd",
+		"-crypto-key-private", "path",
+		"-crypto-key-public", "path",
+		"-minio-endpoint", "test",
+		"-minio-secret", "test",
+		"-minio-id", "test",
+		"-j", "test"}
+
+	os.Args = append(args,
+		"-db-key", "strongDBKey2Ks5nM2J5JaI59PPEhL1x",
+		"-db-keys", "2025=newDBKey2Ks5nM2J5JaI59PPEhL1xAbc",
+		"-db-key-id", "2025")
+	config, err := NewServerConfig()
+	require.NoError(t, err)
+	ring, err := config.Keyring()
+	require.NoError(t, err)
+	assert.Equal(t, "2025", ring.ActiveID())
+
+	os.Args = append(args, "-db-keys", "2025=newDBKey2Ks5nM2J5JaI59PPEhL1xAbc")
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass correct database secret keys")
+
+	os.Args = append(args, "-db-key", "short")
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass correct database secret keys")
+
+	os.Args = args
+	_, err = NewServerConfig()
+	require.ErrorContains(t, err, "you must pass the database secret key")
 }
 
 func TestNewServerConfig_MissingRequiredFields(t *testing.T) {
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+// auditChainLock is the key of the advisory lock serializing the appends to the audit log, so every event
+// is chained to the one appended right before it.
+const auditChainLock = 7270917
+
+// auditBatchSize is the number of events read at once while verifying the audit log.
+const auditBatchSize = 1000
+
+// ErrAuditLogTampered is returned by VerifyAuditLog when an event has been edited or deleted.
+var ErrAuditLogTampered = errors.New("audit log has been tampered with")
+
+// AuditEventHash computes the hash of an audit event chained to the previous event.
+//
+// Parameters:
+//   - prevHash: The hash of the previous event, empty for the first event of the log.
+//   - event: The event. Its ID, PrevHash and Hash are not covered, the order of the events is
+//     covered by the chain itself. The client certificate subject is only covered if it is set,
+//     so the hashes of the events recorded before it was audited stay valid.
+//
+// Returns:
+//   - The hex-encoded SHA-256 of the length-prefixed fields of the event.
+func AuditEventHash(prevHash string, event *model.AuditEvent) string {
+	h := sha256.New()
+	fields := []string{
+		prevHash,
+		strconv.FormatInt(event.UserID, 10),
+		strconv.FormatInt(event.SessionID, 10),
+		event.Method,
+		event.ItemType,
+		event.ItemID,
+		event.PeerAddress,
+		event.Outcome,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if event.ClientSubject != "" {
+		fields = append(fields, event.ClientSubject)
+	}
+	for _, field := range fields {
+		h.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AddAuditEvent appends an event to the audit log.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - event: A pointer to the model.AuditEvent. Its CreatedAt, PrevHash, Hash and ID are set by the method.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddAuditEvent(ctx context.Context, event *model.AuditEvent) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", auditChainLock); err != nil {
+		return err
+	}
+	var prevHash string
+	row := tx.QueryRowContext(ctx, "SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1")
+	if err = row.Scan(&prevHash); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.PrevHash = prevHash
+	event.Hash = AuditEventHash(prevHash, event)
+	row = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO audit_events (user_id, session_id, method, item_type, item_id, peer_address, client_subject, outcome, created_at, prev_hash, hash) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id",
+		event.UserID, event.SessionID, event.Method, event.ItemType, event.ItemID, event.PeerAddress, event.ClientSubject, event.Outcome,
+		event.CreatedAt, event.PrevHash, event.Hash)
+	if err = row.Scan(&event.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetAuditEvents retrieves the audit events of a user, most recent first.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - filter: A pointer to the model.AuditFilter selecting the events.
+//
+// Returns:
+//   - A slice of pointers to model.AuditEvent instances.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	query := "SELECT id, user_id, session_id, method, item_type, item_id, peer_address, client_subject, outcome, created_at, prev_hash, hash " +
+		"FROM audit_events WHERE user_id = $1"
+	args := []interface{}{filter.UserID}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+	if filter.ItemType != "" {
+		args = append(args, filter.ItemType)
+		query += fmt.Sprintf(" AND item_type = $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	return p.queryAuditEvents(ctx, query, args...)
+}
+
+// VerifyAuditLog walks the whole audit log and checks the hash chain.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//
+// Returns:
+//   - The number of the verified events.
+//   - The hash of the last event, which can be kept elsewhere to detect the removal of the latest events too.
+//   - ErrAuditLogTampered with the ID of the first mismatching event if an event has been edited or deleted,
+//     or another error if the operation fails.
+func (p *PostgresStorage) VerifyAuditLog(ctx context.Context) (int, string, error) {
+	var verified int
+	var prevHash string
+	var afterID int64
+	for {
+		events, err := p.queryAuditEvents(ctx,
+			"SELECT id, user_id, session_id, method, item_type, item_id, peer_address, client_subject, outcome, created_at, prev_hash, hash "+
+				"FROM audit_events WHERE id > $1 ORDER BY id LIMIT $2",
+			afterID, auditBatchSize)
+		if err != nil {
+			return verified, prevHash, err
+		}
+		for _, e := range events {
+			if e.PrevHash != prevHash || e.Hash != AuditEventHash(prevHash, e) {
+				return verified, prevHash, fmt.Errorf("%w: event %d doesn't match the chain", ErrAuditLogTampered, e.ID)
+			}
+			prevHash = e.Hash
+			afterID = e.ID
+			verified++
+		}
+		if len(events) < auditBatchSize {
+			return verified, prevHash, nil
+		}
+	}
+}
+
+func (p *PostgresStorage) queryAuditEvents(ctx context.Context, query string, args ...interface{}) ([]*model.AuditEvent, error) {
+	rows, err := p.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var events []*model.AuditEvent
+	for rows.Next() {
+		var e model.AuditEvent
+		if err = rows.Scan(&e.ID, &e.UserID, &e.SessionID, &e.Method, &e.ItemType, &e.ItemID, &e.PeerAddress,
+			&e.ClientSubject, &e.Outcome, &e.CreatedAt, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+func TestPostgresStorage_AuditEvents(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	events := []*model.AuditEvent{
+		{UserID: 1, SessionID: 1, Method: "/gophkeeper.Gophkeeper/GetBankCard", ItemType: "card", ItemID: "1", Outcome: "OK"},
+		{UserID: 1, SessionID: 1, Method: "/gophkeeper.Gophkeeper/GetNote", ItemType: "note", ItemID: "2", Outcome: "NotFound"},
+		{UserID: 2, SessionID: 3, Method: "/gophkeeper.Gophkeeper/GetBankCard", ItemType: "card", ItemID: "3", Outcome: "OK"},
+		{Method: "/gophkeeper.Gophkeeper/Authorize", ItemType: "user", Outcome: "PermissionDenied"},
+	}
+	for _, e := range events {
+		require.NoError(t, db.AddAuditEvent(ctx, e))
+	}
+	assert.Empty(t, events[0].PrevHash)
+	assert.Equal(t, events[0].Hash, events[1].PrevHash)
+
+	t.Run("test get audit events", func(t *testing.T) {
+		got, err := db.GetAuditEvents(ctx, &model.AuditFilter{UserID: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, events[1].ID, got[0].ID)
+		assert.Equal(t, events[0].ID, got[1].ID)
+		assert.Equal(t, events[0].Hash, got[1].Hash)
+
+		got, err = db.GetAuditEvents(ctx, &model.AuditFilter{UserID: 1, ItemType: "card", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "1", got[0].ItemID)
+
+		got, err = db.GetAuditEvents(ctx, &model.AuditFilter{UserID: 1, From: time.Now().Add(time.Hour), Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+
+		got, err = db.GetAuditEvents(ctx, &model.AuditFilter{UserID: 1, To: time.Now().Add(time.Hour), Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("test verify audit log", func(t *testing.T) {
+		verified, last, err := db.VerifyAuditLog(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, len(events), verified)
+		assert.Equal(t, events[len(events)-1].Hash, last)
+	})
+
+	t.Run("test edited event is detected", func(t *testing.T) {
+		_, err := db.Conn.ExecContext(ctx, "UPDATE audit_events SET outcome = 'OK' WHERE id = $1", events[3].ID)
+		require.NoError(t, err)
+
+		_, _, err = db.VerifyAuditLog(ctx)
+		assert.ErrorIs(t, err, ErrAuditLogTampered)
+	})
+
+	t.Run("test deleted event is detected", func(t *testing.T) {
+		_, err := db.Conn.ExecContext(ctx, "DELETE FROM audit_events WHERE id >= $1", events[3].ID)
+		require.NoError(t, err)
+		_, _, err = db.VerifyAuditLog(ctx)
+		require.NoError(t, err)
+
+		_, err = db.Conn.ExecContext(ctx, "DELETE FROM audit_events WHERE id = $1", events[1].ID)
+		require.NoError(t, err)
+		verified, _, err := db.VerifyAuditLog(ctx)
+		assert.ErrorIs(t, err, ErrAuditLogTampered)
+		assert.Equal(t, 1, verified)
+	})
+}
+
+func TestAuditEventHash(t *testing.T) {
+	event := &model.AuditEvent{
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		UserID:    1,
+		Method:    "/gophkeeper.Gophkeeper/GetNote",
+		ItemType:  "note",
+		ItemID:    "12",
+		Outcome:   "OK",
+	}
+	hash := AuditEventHash("", event)
+	assert.Len(t, hash, 64)
+	assert.NotEqual(t, hash, AuditEventHash("previous", event))
+
+	// The fields are length-prefixed, moving characters between them changes the hash.
+	moved := *event
+	moved.ItemType, moved.ItemID = "note1", "2"
+	assert.NotEqual(t, hash, AuditEventHash("", &moved))
+
+	local := *event
+	local.CreatedAt = event.CreatedAt.In(time.FixedZone("UTC+3", 3*60*60))
+	assert.Equal(t, hash, AuditEventHash("", &local))
+
+	// The client certificate subject is covered, the events without it keep their hashes.
+	mtls := *event
+	mtls.ClientSubject = "CN=alice,O=dev,C=US"
+	assert.NotEqual(t, hash, AuditEventHash("", &mtls))
+	mtls.ClientSubject = "CN=mallory,O=dev,C=US"
+	assert.NotEqual(t, AuditEventHash("", &mtls), AuditEventHash("", &local))
+	assert.Equal(t, "381b4ebc7b48c336529dbd60fa4a2180312d4df4f4d1ebb43dfd7738d1064689", hash)
+}
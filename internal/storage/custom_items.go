@@ -0,0 +1,388 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+// ErrTemplateExists is returned by AddItemTemplate when the user already has a template with the name.
+var ErrTemplateExists = errors.New("item template already exists")
+
+// AddItemTemplate stores a new custom item type defined by a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - template: A pointer to a model.ItemTemplate instance containing the template and its ordered fields.
+//
+// Returns:
+//   - ErrTemplateExists if the user already has a template with the name, or an error if the operation fails.
+func (p *PostgresStorage) AddItemTemplate(ctx context.Context, template *model.ItemTemplate) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO item_templates (user_id, name, description) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (user_id, name) DO NOTHING RETURNING id",
+		template.UserID, template.Name, template.Description).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTemplateExists
+	}
+	if err != nil {
+		return err
+	}
+	for i, field := range template.Fields {
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO item_template_fields (template_id, position, name, kind) VALUES ($1, $2, $3, $4)",
+			id, i, field.Name, field.Kind)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetItemTemplates retrieves the custom item types defined by a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.ItemTemplate instances ordered by name.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetItemTemplates(ctx context.Context, userID int64) ([]*model.ItemTemplate, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT t.id, t.user_id, t.name, COALESCE(t.description, ''), f.name, f.kind "+
+			"FROM item_templates t JOIN item_template_fields f ON f.template_id = t.id "+
+			"WHERE t.user_id = $1 ORDER BY t.name, f.position",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var templates []*model.ItemTemplate
+	for rows.Next() {
+		var t model.ItemTemplate
+		var field model.TemplateField
+		if err = rows.Scan(&t.ID, &t.UserID, &t.Name, &t.Description, &field.Name, &field.Kind); err != nil {
+			return nil, err
+		}
+		if len(templates) == 0 || templates[len(templates)-1].ID != t.ID {
+			templates = append(templates, &t)
+		}
+		last := templates[len(templates)-1]
+		last.Fields = append(last.Fields, field)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetItemTemplate retrieves a custom item type defined by a user by its name.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - name: A string containing the name of the template.
+//
+// Returns:
+//   - A pointer to a model.ItemTemplate instance containing the template and its ordered fields.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such template.
+func (p *PostgresStorage) GetItemTemplate(ctx context.Context, userID int64, name string) (*model.ItemTemplate, error) {
+	templates, err := p.GetItemTemplates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// RemoveItemTemplate deletes a custom item type defined by a user. The items created from the template are kept.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - name: A string containing the name of the template.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such template.
+func (p *PostgresStorage) RemoveItemTemplate(ctx context.Context, userID int64, name string) error {
+	res, err := p.Conn.ExecContext(ctx, "DELETE FROM item_templates WHERE user_id = $1 AND name = $2", userID, name)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// AddCustomItem adds a new custom item with its fields to the database.
+// The item belongs to the organization collection if its CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - item: A pointer to a model.CustomItem instance containing the item information to add.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddCustomItem(ctx context.Context, item *model.CustomItem) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO custom_items (user_id, collection_id, template, name, description) "+
+			"VALUES (NULLIF($1::BIGINT, 0), NULLIF($2::BIGINT, 0), $3, $4, $5) RETURNING id",
+		item.UserID, item.CollectionID, item.Template, item.Name, item.Description).Scan(&id)
+	if err != nil {
+		return err
+	}
+	if err = insertItemFields(ctx, tx, id, item.Fields); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetCustomItems retrieves all custom items associated with a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.CustomItem instances containing the user's items with their fields.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetCustomItems(ctx context.Context, userID int64) ([]*model.CustomItem, error) {
+	return p.getCustomItems(ctx, ownerUser, userID)
+}
+
+func (p *PostgresStorage) getCustomItems(ctx context.Context, owner itemOwner, ownerID int64) ([]*model.CustomItem, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), template, name, COALESCE(description, ''), "+
+			"created_at, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM custom_items WHERE "+string(owner)+" = $1 ORDER BY id",
+		ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var items []*model.CustomItem
+	byID := make(map[int64]*model.CustomItem)
+	for rows.Next() {
+		var i model.CustomItem
+		if err = rows.Scan(&i.ID, &i.UserID, &i.CollectionID, &i.Template, &i.Name, &i.Description, &i.CreatedAt, &i.Version, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+		byID[i.ID] = &i
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	fieldRows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT f.item_id, f.name, f.kind, f.value FROM custom_item_fields f JOIN custom_items i ON i.id = f.item_id "+
+			"WHERE i."+string(owner)+" = $1 ORDER BY f.item_id, f.position",
+		ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(fieldRows)
+
+	for fieldRows.Next() {
+		var itemID int64
+		var f model.ItemField
+		if err = fieldRows.Scan(&itemID, &f.Name, &f.Kind, &f.Value); err != nil {
+			return nil, err
+		}
+		// An item added after the first query has no entry and is skipped.
+		if item, ok := byID[itemID]; ok {
+			item.Fields = append(item.Fields, f)
+		}
+	}
+	if err = fieldRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetCustomItem retrieves a specific custom item with its fields by its ID from the database.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the item owner.
+//   - id: An int64 representing the unique identifier of the item to retrieve.
+//
+// Returns:
+//   - A pointer to a model.CustomItem instance containing the item information.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such item.
+func (p *PostgresStorage) GetCustomItem(ctx context.Context, userID, id int64) (*model.CustomItem, error) {
+	return p.getCustomItem(ctx, ownerUser, userID, id)
+}
+
+func (p *PostgresStorage) getCustomItem(ctx context.Context, owner itemOwner, ownerID, id int64) (*model.CustomItem, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), template, name, COALESCE(description, ''), "+
+			"created_at, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM custom_items WHERE id = $1 AND "+string(owner)+" = $2",
+		id, ownerID)
+
+	var item model.CustomItem
+	if err := row.Scan(&item.ID, &item.UserID, &item.CollectionID, &item.Template, &item.Name, &item.Description, &item.CreatedAt, &item.Version, &item.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT name, kind, value FROM custom_item_fields WHERE item_id = $1 ORDER BY position",
+		item.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	for rows.Next() {
+		var f model.ItemField
+		if err = rows.Scan(&f.Name, &f.Kind, &f.Value); err != nil {
+			return nil, err
+		}
+		item.Fields = append(item.Fields, f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// UpdateCustomItem replaces the name, the description and the fields of a custom item if its version matches,
+// the item belongs to the organization collection if its CollectionID is set, otherwise to the user.
+// The template of the item is kept.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - item: A pointer to a model.CustomItem instance containing the new item information and the version it is based on.
+//
+// Returns:
+//   - The new version of the item.
+//   - sql.ErrNoRows if the owner has no such item, ErrVersionConflict if the version is stale,
+//     or an error if the operation fails.
+func (p *PostgresStorage) UpdateCustomItem(ctx context.Context, item *model.CustomItem) (int64, error) {
+	owner, ownerID := ownerOf(item.UserID, item.CollectionID)
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var currentVersion int64
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT version FROM custom_items WHERE id = $1 AND "+string(owner)+" = $2 FOR UPDATE",
+		item.ID, ownerID).Scan(&currentVersion)
+	if err != nil {
+		return 0, err
+	}
+	if currentVersion != item.Version {
+		return 0, ErrVersionConflict
+	}
+
+	var version int64
+	err = tx.QueryRowContext(
+		ctx,
+		"UPDATE custom_items SET name = $2, description = $3, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $1 RETURNING version",
+		item.ID, item.Name, item.Description).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	if err = replaceItemFields(ctx, tx, item.ID, item.Fields); err != nil {
+		return 0, err
+	}
+	return version, tx.Commit()
+}
+
+// RemoveCustomItem deletes a custom item with its fields from the database by its ID.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the item owner.
+//   - id: An int64 representing the unique identifier of the item to delete.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such item.
+func (p *PostgresStorage) RemoveCustomItem(ctx context.Context, userID, id int64) error {
+	return p.removeItem(ctx, "custom_items", ownerUser, userID, id)
+}
+
+// insertItemFields stores the fields of a custom item in their order.
+func insertItemFields(ctx context.Context, tx *sql.Tx, itemID int64, fields []model.ItemField) error {
+	for i, field := range fields {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO custom_item_fields (item_id, position, name, kind, value) VALUES ($1, $2, $3, $4, $5)",
+			itemID, i, field.Name, field.Kind, field.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceItemFields replaces all the fields of a custom item.
+func replaceItemFields(ctx context.Context, tx *sql.Tx, itemID int64, fields []model.ItemField) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM custom_item_fields WHERE item_id = $1", itemID); err != nil {
+		return err
+	}
+	return insertItemFields(ctx, tx, itemID, fields)
+}
@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+// ErrFolderCycle is returned by MoveFolder when the folder is moved into itself or into one of its subfolders.
+var ErrFolderCycle = errors.New("folder can't be moved into itself")
+
+// labeledItemTables maps the types of the items that can be placed in folders and tagged to the tables of the items.
+var labeledItemTables = map[string]string{
+	model.ItemTypeCard:        "bank_cards",
+	model.ItemTypeCredentials: "user_credentials",
+	model.ItemTypeNote:        "notes",
+	model.ItemTypeFile:        "files",
+	model.ItemTypeOTP:         "otp_items",
+	model.ItemTypeCustom:      "custom_items",
+}
+
+// IsLabeledItemType reports whether the items of the type can be placed in folders and tagged.
+func IsLabeledItemType(itemType string) bool {
+	_, ok := labeledItemTables[itemType]
+	return ok
+}
+
+// AddFolder stores a new folder of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - folder: A pointer to a model.Folder instance containing the folder, a zero ParentID places it at the top level.
+//
+// Returns:
+//   - An int64 representing the unique identifier of the new folder.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such parent folder.
+func (p *PostgresStorage) AddFolder(ctx context.Context, folder *model.Folder) (int64, error) {
+	var id int64
+	err := p.Conn.QueryRowContext(
+		ctx,
+		"INSERT INTO folders (user_id, parent_id, name) SELECT $1, NULLIF($2::BIGINT, 0), $3 "+
+			"WHERE $2::BIGINT = 0 OR EXISTS (SELECT 1 FROM folders WHERE id = $2 AND user_id = $1) RETURNING id",
+		folder.UserID, folder.ParentID, folder.Name).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetFolders retrieves the folders of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.Folder instances ordered by ID, so that every parent precedes its subfolders
+//     unless the subfolders were moved.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetFolders(ctx context.Context, userID int64) ([]*model.Folder, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, user_id, COALESCE(parent_id, 0), name FROM folders WHERE user_id = $1 ORDER BY id",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var folders []*model.Folder
+	for rows.Next() {
+		var f model.Folder
+		if err = rows.Scan(&f.ID, &f.UserID, &f.ParentID, &f.Name); err != nil {
+			return nil, err
+		}
+		folders = append(folders, &f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// RenameFolder replaces the encrypted name of a folder of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - id: An int64 representing the unique identifier of the folder.
+//   - name: A string containing the new encrypted name of the folder.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such folder.
+func (p *PostgresStorage) RenameFolder(ctx context.Context, userID, id int64, name string) error {
+	res, err := p.Conn.ExecContext(ctx, "UPDATE folders SET name = $1 WHERE id = $2 AND user_id = $3", name, id, userID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// MoveFolder moves a folder of a user with its subfolders and items into another folder of the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - id: An int64 representing the unique identifier of the folder.
+//   - parentID: An int64 representing the unique identifier of the new parent folder, or zero for the top level.
+//
+// Returns:
+//   - ErrFolderCycle if the parent folder is the folder itself or one of its subfolders.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such folder or parent folder.
+func (p *PostgresStorage) MoveFolder(ctx context.Context, userID, id, parentID int64) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The folders of the user are locked, so that concurrent moves can't create a cycle.
+	rows, err := tx.QueryContext(ctx, "SELECT id, COALESCE(parent_id, 0) FROM folders WHERE user_id = $1 FOR UPDATE", userID)
+	if err != nil {
+		return err
+	}
+	parents := make(map[int64]int64)
+	for rows.Next() {
+		var folderID, folderParentID int64
+		if err = rows.Scan(&folderID, &folderParentID); err != nil {
+			rows.Close()
+			return err
+		}
+		parents[folderID] = folderParentID
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := parents[id]; !ok {
+		return sql.ErrNoRows
+	}
+	if parentID != 0 {
+		if _, ok := parents[parentID]; !ok {
+			return sql.ErrNoRows
+		}
+		for ancestor := parentID; ancestor != 0; ancestor = parents[ancestor] {
+			if ancestor == id {
+				return ErrFolderCycle
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE folders SET parent_id = NULLIF($1::BIGINT, 0) WHERE id = $2 AND user_id = $3",
+		parentID, id, userID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveFolder deletes a folder of a user with its subfolders, the items of the folders are left without a folder.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - id: An int64 representing the unique identifier of the folder.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such folder.
+func (p *PostgresStorage) RemoveFolder(ctx context.Context, userID, id int64) error {
+	res, err := p.Conn.ExecContext(ctx, "DELETE FROM folders WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// GetItemFolders retrieves the folders the personal items of a user are placed in.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.ItemFolder instances.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetItemFolders(ctx context.Context, userID int64) ([]*model.ItemFolder, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT item_type, item_id, folder_id FROM item_folders WHERE user_id = $1 ORDER BY item_type, item_id",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var items []*model.ItemFolder
+	for rows.Next() {
+		var item model.ItemFolder
+		if err = rows.Scan(&item.ItemType, &item.ItemID, &item.FolderID); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetItemFolder places a personal item of a user in a folder of the user, replacing its previous folder.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - item: A pointer to a model.ItemFolder instance selecting the item and the folder, a zero FolderID takes
+//     the item out of its folder.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such item or folder.
+func (p *PostgresStorage) SetItemFolder(ctx context.Context, userID int64, item *model.ItemFolder) error {
+	if err := p.checkLabeledItem(ctx, userID, item.ItemType, item.ItemID); err != nil {
+		return err
+	}
+	if item.FolderID == 0 {
+		_, err := p.Conn.ExecContext(
+			ctx,
+			"DELETE FROM item_folders WHERE user_id = $1 AND item_type = $2 AND item_id = $3",
+			userID, item.ItemType, item.ItemID)
+		return err
+	}
+
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO item_folders (user_id, item_type, item_id, folder_id) "+
+			"SELECT $1, $2, $3, id FROM folders WHERE id = $4 AND user_id = $1 "+
+			"ON CONFLICT (user_id, item_type, item_id) DO UPDATE SET folder_id = EXCLUDED.folder_id",
+		userID, item.ItemType, item.ItemID, item.FolderID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// checkLabeledItem checks that the personal item of the user exists, it returns sql.ErrNoRows otherwise.
+func (p *PostgresStorage) checkLabeledItem(ctx context.Context, userID int64, itemType string, itemID int64) error {
+	table, ok := labeledItemTables[itemType]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	var exists bool
+	err := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1 AND user_id = $2)",
+		itemID, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/keyring"
+)
+
+// rotationBatchSize is the number of rows read at once while rotating the database key.
+const rotationBatchSize = 100
+
+// encryptedColumn describes a column with values encrypted with the database key.
+type encryptedColumn struct {
+	table  string
+	id     string
+	column string
+	filter string // Additional condition selecting the encrypted values, if the column contains other values too
+}
+
+// encryptedColumns lists all the columns encrypted with the database key. Passwords hashed with Argon2id are
+// not encrypted, only the passwords stored by older server versions are.
+var encryptedColumns = []encryptedColumn{
+	{table: "user_totp", id: "user_id", column: "secret"},
+	{table: "user_recovery_codes", id: "id", column: "code"},
+	{table: "users", id: "id", column: "password", filter: "password NOT LIKE '$argon2id$%'"},
+}
+
+// encryptedValue is a value encrypted with the database key and the ID of its row.
+type encryptedValue struct {
+	value string
+	id    int64
+}
+
+// getEncryptedValues reads the next batch of encrypted values of the column, ordered by the row ID.
+func (p *PostgresStorage) getEncryptedValues(ctx context.Context, c encryptedColumn, afterID int64) ([]encryptedValue, error) {
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s > $1", c.id, c.column, c.table, c.id)
+	if c.filter != "" {
+		query += " AND " + c.filter
+	}
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $2", c.id)
+
+	rows, err := p.Conn.QueryContext(ctx, query, afterID, rotationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var values []encryptedValue
+	for rows.Next() {
+		var v encryptedValue
+		if err = rows.Scan(&v.id, &v.value); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// replaceEncryptedValue replaces the encrypted value of a row, unless it has been changed in the meantime.
+func (p *PostgresStorage) replaceEncryptedValue(ctx context.Context, c encryptedColumn, id int64, oldValue, newValue string) (bool, error) {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET %s = $1 WHERE %s = $2 AND %s = $3", c.table, c.column, c.id, c.column),
+		newValue, id, oldValue)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RotateDatabaseKey re-encrypts all the values encrypted with the database key under the active key of the keyring.
+//
+// The rows are processed in small batches and every value is replaced only if it hasn't been changed
+// concurrently, so the rotation can run while the server is serving requests: the server decrypts values
+// with any key of the keyring and encrypts new values with the active key. Values that can't be decrypted
+// are logged and left untouched. Once the rotation has completed without failures, the retired keys can be
+// removed from the configuration.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - db: The database storage containing the encrypted values.
+//   - keys: The keyring with the active key and all the retired keys still in use.
+//
+// Returns:
+//   - The number of re-encrypted values.
+//   - An error if the database could not be read, or if some values could not be re-encrypted.
+func RotateDatabaseKey(ctx context.Context, db *PostgresStorage, keys *keyring.Keyring) (int, error) {
+	var rotated, failed int
+	for _, c := range encryptedColumns {
+		var afterID int64
+		var count int
+		for {
+			values, err := db.getEncryptedValues(ctx, c, afterID)
+			if err != nil {
+				return rotated, err
+			}
+			if len(values) == 0 {
+				break
+			}
+			for _, v := range values {
+				afterID = v.id
+				newValue, ok, err := keys.Rotate(v.value)
+				if err != nil {
+					logger.Log.Error("error re-encrypt value", zap.String("table", c.table), zap.Int64("id", v.id),
+						zap.String("keyID", keyring.KeyID(v.value)), zap.Error(err))
+					failed++
+					continue
+				}
+				if !ok {
+					continue
+				}
+				ok, err = db.replaceEncryptedValue(ctx, c, v.id, v.value, newValue)
+				if err != nil {
+					return rotated, err
+				}
+				if ok {
+					count++
+				}
+			}
+		}
+		logger.Log.Info("database key rotated", zap.String("table", c.table), zap.Int("rotated", count))
+		rotated += count
+	}
+
+	if failed > 0 {
+		return rotated, fmt.Errorf("%d values could not be re-encrypted with the key %q", failed, keys.ActiveID())
+	}
+	return rotated, nil
+}
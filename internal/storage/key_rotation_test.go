@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+	"github.com/Vidkin/gophkeeper/pkg/keyring"
+)
+
+func TestRotateDatabaseKey(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	const (
+		oldKey = "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+		newKey = "newDBKey2Ks5nM2J5JaI59PPEhL1xAbc"
+	)
+	ctx := context.Background()
+
+	legacyPassword, err := aes.Encrypt(oldKey, "password")
+	require.NoError(t, err)
+	require.NoError(t, db.AddUser(ctx, "legacy", legacyPassword))
+	require.NoError(t, db.AddUser(ctx, "hashed", "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA"))
+
+	secret, err := aes.Encrypt(oldKey, "totp secret")
+	require.NoError(t, err)
+	require.NoError(t, db.SetUserTOTP(ctx, 1, secret))
+	code, err := aes.Encrypt(oldKey, "recoverycode")
+	require.NoError(t, err)
+	require.NoError(t, db.ReplaceRecoveryCodes(ctx, 1, []string{code}))
+
+	ring, err := keyring.New("2025", map[string]string{keyring.LegacyKeyID: oldKey, "2025": newKey})
+	require.NoError(t, err)
+
+	rotated, err := RotateDatabaseKey(ctx, db, ring)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rotated)
+
+	newRing, err := keyring.New("2025", map[string]string{"2025": newKey})
+	require.NoError(t, err)
+
+	u, err := db.GetUserByID(ctx, 1)
+	require.NoError(t, err)
+	plain, err := newRing.Decrypt(u.Password)
+	require.NoError(t, err)
+	assert.Equal(t, "password", plain)
+
+	u, err = db.GetUserByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA", u.Password)
+
+	totp, err := db.GetUserTOTP(ctx, 1)
+	require.NoError(t, err)
+	plain, err = newRing.Decrypt(totp.Secret)
+	require.NoError(t, err)
+	assert.Equal(t, "totp secret", plain)
+
+	codes, err := db.GetRecoveryCodes(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, codes, 1)
+	plain, err = newRing.Decrypt(codes[0].Code)
+	require.NoError(t, err)
+	assert.Equal(t, "recoverycode", plain)
+
+	t.Run("rotation is idempotent", func(t *testing.T) {
+		rotated, err = RotateDatabaseKey(ctx, db, ring)
+		require.NoError(t, err)
+		assert.Equal(t, 0, rotated)
+	})
+
+	t.Run("unknown keys are reported", func(t *testing.T) {
+		other, err := keyring.New("2026", map[string]string{"2026": oldKey})
+		require.NoError(t, err)
+		_, err = RotateDatabaseKey(ctx, db, other)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,7 @@
+DROP TRIGGER notes_remove_shared_item ON notes;
+DROP TRIGGER user_credentials_remove_shared_item ON user_credentials;
+DROP TRIGGER bank_cards_remove_shared_item ON bank_cards;
+DROP FUNCTION remove_shared_item;
+DROP TABLE item_shares;
+DROP TABLE shared_items;
+DROP TABLE user_sharing_keys;
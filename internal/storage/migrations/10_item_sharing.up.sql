@@ -0,0 +1,46 @@
+CREATE TABLE user_sharing_keys (
+    user_id INT PRIMARY KEY,
+    public_key BYTEA NOT NULL,
+    private_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE shared_items (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id INT NOT NULL,
+    item_type TEXT NOT NULL,
+    item_id INT NOT NULL,
+    data TEXT NOT NULL,
+    owner_key TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_owner FOREIGN KEY(owner_id) REFERENCES users(id),
+    CONSTRAINT shared_items_item_key UNIQUE (owner_id, item_type, item_id)
+);
+
+CREATE TABLE item_shares (
+    shared_item_id BIGINT NOT NULL,
+    recipient_id INT NOT NULL,
+    wrapped_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shared_item_id, recipient_id),
+    CONSTRAINT fk_shared_item FOREIGN KEY(shared_item_id) REFERENCES shared_items(id) ON DELETE CASCADE,
+    CONSTRAINT fk_recipient FOREIGN KEY(recipient_id) REFERENCES users(id)
+);
+
+CREATE INDEX item_shares_recipient_id_idx ON item_shares (recipient_id);
+
+-- The shared copy of an item is removed together with the item.
+CREATE FUNCTION remove_shared_item() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM shared_items WHERE owner_id = OLD.user_id AND item_type = TG_ARGV[0] AND item_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bank_cards_remove_shared_item AFTER DELETE ON bank_cards
+    FOR EACH ROW EXECUTE FUNCTION remove_shared_item('card');
+CREATE TRIGGER user_credentials_remove_shared_item AFTER DELETE ON user_credentials
+    FOR EACH ROW EXECUTE FUNCTION remove_shared_item('credentials');
+CREATE TRIGGER notes_remove_shared_item AFTER DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION remove_shared_item('note');
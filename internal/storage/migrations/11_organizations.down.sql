@@ -0,0 +1,13 @@
+DELETE FROM files WHERE collection_id IS NOT NULL;
+DELETE FROM notes WHERE collection_id IS NOT NULL;
+DELETE FROM user_credentials WHERE collection_id IS NOT NULL;
+DELETE FROM bank_cards WHERE collection_id IS NOT NULL;
+
+ALTER TABLE files DROP COLUMN collection_id, ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE notes DROP COLUMN collection_id, ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE user_credentials DROP COLUMN collection_id, ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE bank_cards DROP COLUMN collection_id, ALTER COLUMN user_id SET NOT NULL;
+
+DROP TABLE collections;
+DROP TABLE org_members;
+DROP TABLE organizations;
@@ -0,0 +1,51 @@
+CREATE TABLE organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE org_members (
+    org_id BIGINT NOT NULL,
+    user_id INT NOT NULL,
+    role TEXT NOT NULL,
+    wrapped_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, user_id),
+    CONSTRAINT fk_org FOREIGN KEY(org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id),
+    CONSTRAINT org_members_role_check CHECK (role IN ('owner', 'admin', 'editor', 'viewer'))
+);
+
+CREATE INDEX org_members_user_id_idx ON org_members (user_id);
+
+CREATE TABLE collections (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_org FOREIGN KEY(org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT collections_name_key UNIQUE (org_id, name)
+);
+
+-- An item belongs either to a user or to a collection of an organization.
+ALTER TABLE bank_cards
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN collection_id BIGINT REFERENCES collections(id) ON DELETE CASCADE,
+    ADD CONSTRAINT bank_cards_owner_check CHECK ((user_id IS NULL) <> (collection_id IS NULL));
+ALTER TABLE user_credentials
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN collection_id BIGINT REFERENCES collections(id) ON DELETE CASCADE,
+    ADD CONSTRAINT user_credentials_owner_check CHECK ((user_id IS NULL) <> (collection_id IS NULL));
+ALTER TABLE notes
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN collection_id BIGINT REFERENCES collections(id) ON DELETE CASCADE,
+    ADD CONSTRAINT notes_owner_check CHECK ((user_id IS NULL) <> (collection_id IS NULL));
+ALTER TABLE files
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN collection_id BIGINT REFERENCES collections(id) ON DELETE CASCADE,
+    ADD CONSTRAINT files_owner_check CHECK ((user_id IS NULL) <> (collection_id IS NULL));
+
+CREATE INDEX bank_cards_collection_id_idx ON bank_cards (collection_id);
+CREATE INDEX user_credentials_collection_id_idx ON user_credentials (collection_id);
+CREATE INDEX notes_collection_id_idx ON notes (collection_id);
+CREATE UNIQUE INDEX files_collection_id_file_name_idx ON files (collection_id, file_name);
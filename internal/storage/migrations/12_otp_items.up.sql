@@ -0,0 +1,23 @@
+-- The secret, the issuer, the account and the description are encrypted by the client, the code parameters
+-- are stored as is, so that the server can validate them.
+CREATE TABLE otp_items (
+    id SERIAL PRIMARY KEY,
+    user_id INT,
+    collection_id BIGINT REFERENCES collections(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    account TEXT NOT NULL,
+    algorithm VARCHAR(10) NOT NULL,
+    digits INT NOT NULL,
+    period INT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id),
+    CONSTRAINT otp_items_owner_check CHECK ((user_id IS NULL) <> (collection_id IS NULL)),
+    CONSTRAINT otp_items_algorithm_check CHECK (algorithm IN ('SHA1', 'SHA256', 'SHA512')),
+    CONSTRAINT otp_items_digits_check CHECK (digits BETWEEN 6 AND 10),
+    CONSTRAINT otp_items_period_check CHECK (period > 0)
+);
+
+CREATE INDEX otp_items_user_id_idx ON otp_items (user_id);
+CREATE INDEX otp_items_collection_id_idx ON otp_items (collection_id);
@@ -0,0 +1,5 @@
+ALTER TABLE otp_items DROP COLUMN version, DROP COLUMN updated_at;
+ALTER TABLE files DROP COLUMN version, DROP COLUMN updated_at;
+ALTER TABLE notes DROP COLUMN version, DROP COLUMN updated_at;
+ALTER TABLE user_credentials DROP COLUMN version, DROP COLUMN updated_at;
+ALTER TABLE bank_cards DROP COLUMN version, DROP COLUMN updated_at;
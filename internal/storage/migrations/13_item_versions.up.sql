@@ -0,0 +1,22 @@
+-- Every update of an item increases its version, updates carrying a stale version are rejected.
+ALTER TABLE bank_cards
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE user_credentials
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE notes
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE files
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE otp_items
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE bank_cards SET updated_at = created_at WHERE created_at IS NOT NULL;
+UPDATE user_credentials SET updated_at = created_at WHERE created_at IS NOT NULL;
+UPDATE notes SET updated_at = created_at WHERE created_at IS NOT NULL;
+UPDATE files SET updated_at = created_at WHERE created_at IS NOT NULL;
+UPDATE otp_items SET updated_at = created_at WHERE created_at IS NOT NULL;
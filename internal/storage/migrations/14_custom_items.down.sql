@@ -0,0 +1,4 @@
+DROP TABLE custom_item_fields;
+DROP TABLE custom_items;
+DROP TABLE item_template_fields;
+DROP TABLE item_templates;
@@ -0,0 +1,50 @@
+-- The templates of the custom item types defined by the users, the built-in templates are not stored.
+CREATE TABLE item_templates (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id),
+    CONSTRAINT item_templates_name_key UNIQUE (user_id, name)
+);
+
+CREATE TABLE item_template_fields (
+    template_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    PRIMARY KEY (template_id, position),
+    CONSTRAINT fk_template FOREIGN KEY(template_id) REFERENCES item_templates(id) ON DELETE CASCADE,
+    CONSTRAINT item_template_fields_kind_check CHECK (kind IN ('text', 'secret', 'url', 'date', 'multiline'))
+);
+
+-- The name, the field values and the description of the custom items are encrypted by the client.
+-- An item keeps the names and the kinds of its fields, so it doesn't depend on its template after it's added.
+CREATE TABLE custom_items (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT,
+    collection_id BIGINT REFERENCES collections(id) ON DELETE CASCADE,
+    template VARCHAR(64) NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id),
+    CONSTRAINT custom_items_owner_check CHECK ((user_id IS NULL) <> (collection_id IS NULL))
+);
+
+CREATE INDEX custom_items_user_id_idx ON custom_items (user_id);
+CREATE INDEX custom_items_collection_id_idx ON custom_items (collection_id);
+
+CREATE TABLE custom_item_fields (
+    item_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (item_id, position),
+    CONSTRAINT fk_item FOREIGN KEY(item_id) REFERENCES custom_items(id) ON DELETE CASCADE,
+    CONSTRAINT custom_item_fields_kind_check CHECK (kind IN ('text', 'secret', 'url', 'date', 'multiline'))
+);
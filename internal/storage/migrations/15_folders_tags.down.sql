@@ -0,0 +1,11 @@
+DROP TRIGGER custom_items_remove_item_labels ON custom_items;
+DROP TRIGGER otp_items_remove_item_labels ON otp_items;
+DROP TRIGGER files_remove_item_labels ON files;
+DROP TRIGGER notes_remove_item_labels ON notes;
+DROP TRIGGER user_credentials_remove_item_labels ON user_credentials;
+DROP TRIGGER bank_cards_remove_item_labels ON bank_cards;
+DROP FUNCTION remove_item_labels;
+DROP TABLE item_tags;
+DROP TABLE item_folders;
+DROP TABLE tags;
+DROP TABLE folders;
@@ -0,0 +1,66 @@
+-- The names of the folders and the tags are encrypted by the client.
+CREATE TABLE folders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    parent_id BIGINT REFERENCES folders(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX folders_user_id_idx ON folders (user_id);
+
+CREATE TABLE tags (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX tags_user_id_idx ON tags (user_id);
+
+-- An item is placed in one folder at most, the items of a removed folder are left without a folder.
+CREATE TABLE item_folders (
+    user_id INT NOT NULL,
+    item_type TEXT NOT NULL,
+    item_id BIGINT NOT NULL,
+    folder_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, item_type, item_id),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id),
+    CONSTRAINT fk_folder FOREIGN KEY(folder_id) REFERENCES folders(id) ON DELETE CASCADE
+);
+
+CREATE TABLE item_tags (
+    user_id INT NOT NULL,
+    item_type TEXT NOT NULL,
+    item_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    PRIMARY KEY (tag_id, item_type, item_id),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id),
+    CONSTRAINT fk_tag FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX item_tags_user_id_idx ON item_tags (user_id);
+
+-- The folder and the tags of an item are removed together with the item.
+CREATE FUNCTION remove_item_labels() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM item_folders WHERE user_id = OLD.user_id AND item_type = TG_ARGV[0] AND item_id = OLD.id;
+    DELETE FROM item_tags WHERE user_id = OLD.user_id AND item_type = TG_ARGV[0] AND item_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bank_cards_remove_item_labels AFTER DELETE ON bank_cards
+    FOR EACH ROW EXECUTE FUNCTION remove_item_labels('card');
+CREATE TRIGGER user_credentials_remove_item_labels AFTER DELETE ON user_credentials
+    FOR EACH ROW EXECUTE FUNCTION remove_item_labels('credentials');
+CREATE TRIGGER notes_remove_item_labels AFTER DELETE ON notes
+    FOR EACH ROW EXECUTE FUNCTION remove_item_labels('note');
+CREATE TRIGGER files_remove_item_labels AFTER DELETE ON files
+    FOR EACH ROW EXECUTE FUNCTION remove_item_labels('file');
+CREATE TRIGGER otp_items_remove_item_labels AFTER DELETE ON otp_items
+    FOR EACH ROW EXECUTE FUNCTION remove_item_labels('otp');
+CREATE TRIGGER custom_items_remove_item_labels AFTER DELETE ON custom_items
+    FOR EACH ROW EXECUTE FUNCTION remove_item_labels('custom');
@@ -0,0 +1,2 @@
+ALTER TABLE audit_events
+    DROP COLUMN client_subject;
@@ -0,0 +1,2 @@
+ALTER TABLE audit_events
+    ADD COLUMN client_subject TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE user_credentials
+    DROP COLUMN password_changed_at;
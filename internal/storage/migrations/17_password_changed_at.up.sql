@@ -0,0 +1,6 @@
+-- The moment the password of the credentials was last changed, unlike updated_at it isn't changed by the edits
+-- of the other fields or by a rekey of the vault. The earlier changes of the passwords are unknown.
+ALTER TABLE user_credentials
+    ADD COLUMN password_changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE user_credentials SET password_changed_at = created_at;
@@ -0,0 +1,3 @@
+DROP TABLE user_recovery_codes CASCADE;
+
+DROP TABLE user_totp CASCADE;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE refresh_tokens CASCADE;
+
+DROP TABLE sessions CASCADE;
\ No newline at end of file
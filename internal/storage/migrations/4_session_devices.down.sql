@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    DROP COLUMN device_name,
+    DROP COLUMN client_version,
+    DROP COLUMN peer_address,
+    DROP COLUMN last_seen_at;
@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    ADD COLUMN device_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN client_version TEXT NOT NULL DEFAULT '',
+    ADD COLUMN peer_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,3 @@
+DROP INDEX files_user_id_file_name_idx;
+
+ALTER TABLE files DROP COLUMN object_key;
@@ -0,0 +1,3 @@
+ALTER TABLE files ADD COLUMN object_key VARCHAR(255) UNIQUE;
+
+CREATE UNIQUE INDEX files_user_id_file_name_idx ON files (user_id, file_name);
@@ -0,0 +1,10 @@
+CREATE TABLE user_vaults (
+    user_id INT PRIMARY KEY,
+    kdf_salt BYTEA NOT NULL,
+    kdf_memory BIGINT NOT NULL,
+    kdf_iterations BIGINT NOT NULL,
+    kdf_parallelism INT NOT NULL,
+    verifier TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id)
+);
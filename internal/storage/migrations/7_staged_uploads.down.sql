@@ -0,0 +1 @@
+DROP TABLE staged_uploads;
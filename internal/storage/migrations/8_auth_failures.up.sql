@@ -0,0 +1,5 @@
+CREATE TABLE auth_failures (
+    key TEXT PRIMARY KEY,
+    failures INT NOT NULL,
+    last_failure TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
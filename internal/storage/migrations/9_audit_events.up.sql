@@ -0,0 +1,15 @@
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL DEFAULT 0,
+    session_id BIGINT NOT NULL DEFAULT 0,
+    method TEXT NOT NULL,
+    item_type TEXT NOT NULL DEFAULT '',
+    item_id TEXT NOT NULL DEFAULT '',
+    peer_address TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX audit_events_user_id_idx ON audit_events (user_id, created_at);
@@ -36,6 +36,11 @@ func (m *MockMinioClient) PutObject(ctx context.Context, bucketName string, obje
 	return args.Get(0).(minio.UploadInfo), args.Error(1)
 }
 
+func (m *MockMinioClient) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	args := m.Called(ctx, dst, src)
+	return args.Get(0).(minio.UploadInfo), args.Error(1)
+}
+
 func (m *MockMinioClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
 	args := m.Called(ctx, bucketName, objectName, opts)
 	return args.Error(0)
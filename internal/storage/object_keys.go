@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+)
+
+// objectIDLength is the number of random bytes in the generated part of an object key.
+const objectIDLength = 16
+
+// NewObjectKey generates an opaque key for a new object of the user in the MinIO bucket.
+//
+// The key has the form "<userID>/<random hex ID>", so objects of different users never collide,
+// even if they upload files with the same name.
+//
+// Parameters:
+//   - userID: An int64 representing the unique identifier of the file owner.
+//
+// Returns:
+//   - The generated object key.
+//   - An error if random bytes could not be generated.
+func NewObjectKey(userID int64) (string, error) {
+	b := make([]byte, objectIDLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%s", userID, hex.EncodeToString(b)), nil
+}
+
+// MigrateLegacyObjects moves files uploaded before per-user object keys were introduced to their own keys.
+//
+// Each legacy file gets a new object key: the object stored under the bare file name is copied to the new key
+// and the key is saved in the database. A legacy object is removed once no file refers to it anymore. Files
+// whose object cannot be copied are logged and left untouched, so the migration can be repeated later.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - db: The database storage containing the file metadata.
+//   - minioClient: The MinIO client storing the file objects.
+//
+// Returns:
+//   - An error if the legacy files could not be read from the database.
+func MigrateLegacyObjects(ctx context.Context, db *PostgresStorage, minioClient MinioClientInterface) error {
+	files, err := db.GetLegacyFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	type legacyObject struct {
+		bucket, name string
+	}
+	migrated := make(map[legacyObject]struct{})
+	var count int
+	for _, f := range files {
+		key, err := NewObjectKey(f.UserID)
+		if err != nil {
+			return err
+		}
+
+		_, err = minioClient.CopyObject(ctx,
+			minio.CopyDestOptions{Bucket: f.BucketName, Object: key},
+			minio.CopySrcOptions{Bucket: f.BucketName, Object: f.FileName})
+		if err != nil {
+			logger.Log.Error("error copy legacy object", zap.Int64("fileID", f.ID), zap.Error(err))
+			continue
+		}
+
+		if err = db.SetFileObjectKey(ctx, f.ID, key); err != nil {
+			logger.Log.Error("error save object key", zap.Int64("fileID", f.ID), zap.Error(err))
+			if errRm := minioClient.RemoveObject(ctx, f.BucketName, key, minio.RemoveObjectOptions{ForceDelete: true}); errRm != nil {
+				logger.Log.Error("error remove copied object", zap.String("objectKey", key), zap.Error(errRm))
+			}
+			continue
+		}
+		migrated[legacyObject{bucket: f.BucketName, name: f.FileName}] = struct{}{}
+		count++
+	}
+
+	for obj := range migrated {
+		referenced, err := db.IsLegacyObjectReferenced(ctx, obj.bucket, obj.name)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			continue
+		}
+		if err = minioClient.RemoveObject(ctx, obj.bucket, obj.name, minio.RemoveObjectOptions{ForceDelete: true}); err != nil {
+			logger.Log.Error("error remove legacy object", zap.String("objectName", obj.name), zap.Error(err))
+		}
+	}
+
+	if len(files) > 0 {
+		logger.Log.Info("legacy file objects migrated", zap.Int("migrated", count), zap.Int("total", len(files)))
+	}
+	return nil
+}
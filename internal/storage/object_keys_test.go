@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewObjectKey(t *testing.T) {
+	first, err := NewObjectKey(7)
+	require.NoError(t, err)
+	second, err := NewObjectKey(7)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "7/"))
+	assert.Len(t, strings.TrimPrefix(first, "7/"), objectIDLength*2)
+	assert.NotEqual(t, first, second)
+}
+
+func TestMigrateLegacyObjects(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	require.NoError(t, db.AddUser(ctx, "first", "password"))
+	require.NoError(t, db.AddUser(ctx, "second", "password"))
+
+	addLegacyFile := func(userID int64, fileName string) {
+		_, err := db.Conn.ExecContext(ctx,
+			"INSERT INTO files (user_id, bucket_name, file_name, file_size, description) VALUES ($1, $2, $3, $4, $5)",
+			userID, MinioBucketName, fileName, 12, "description")
+		require.NoError(t, err)
+	}
+	addLegacyFile(1, "report.pdf")
+	addLegacyFile(2, "report.pdf")
+	addLegacyFile(2, "broken.txt")
+
+	file, err := db.GetFile(ctx, 1, "report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "report.pdf", file.ObjectKey)
+
+	mockClient := new(MockMinioClient)
+	isSource := func(name string) interface{} {
+		return mock.MatchedBy(func(src minio.CopySrcOptions) bool { return src.Object == name })
+	}
+	mockClient.On("CopyObject", mock.Anything, mock.Anything, isSource("report.pdf")).Return(minio.UploadInfo{}, nil).Twice()
+	mockClient.On("CopyObject", mock.Anything, mock.Anything, isSource("broken.txt")).Return(minio.UploadInfo{}, errors.New("no such key")).Once()
+	mockClient.On("RemoveObject", mock.Anything, MinioBucketName, "report.pdf", mock.Anything).Return(nil).Once()
+
+	err = MigrateLegacyObjects(ctx, db, mockClient)
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	first, err := db.GetFile(ctx, 1, "report.pdf")
+	require.NoError(t, err)
+	second, err := db.GetFile(ctx, 2, "report.pdf")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first.ObjectKey, "1/"))
+	assert.True(t, strings.HasPrefix(second.ObjectKey, "2/"))
+
+	legacy, err := db.GetLegacyFiles(ctx)
+	require.NoError(t, err)
+	require.Len(t, legacy, 1)
+	assert.Equal(t, "broken.txt", legacy[0].FileName)
+}
@@ -0,0 +1,548 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+var (
+	// ErrOrgMemberExists is returned by AddOrgMember when the user is already a member of the organization.
+	ErrOrgMemberExists = errors.New("user is already a member of the organization")
+	// ErrLastOwner is returned when a change would leave an organization without an owner.
+	ErrLastOwner = errors.New("organization must have an owner")
+	// ErrCollectionExists is returned by AddCollection when the organization already has a collection with the name.
+	ErrCollectionExists = errors.New("collection already exists")
+)
+
+// AddOrganization creates an organization owned by a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - name: A string containing the name of the organization.
+//   - owner: A pointer to a model.OrgMember instance containing the user ID and the wrapped organization key of the owner.
+//
+// Returns:
+//   - The ID of the new organization.
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddOrganization(ctx context.Context, name string, owner *model.OrgMember) (int64, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var orgID int64
+	if err = tx.QueryRowContext(ctx, "INSERT INTO organizations (name) VALUES ($1) RETURNING id", name).Scan(&orgID); err != nil {
+		return 0, err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO org_members (org_id, user_id, role, wrapped_key) VALUES ($1, $2, $3, $4)",
+		orgID, owner.UserID, model.RoleOwner, owner.WrappedKey)
+	if err != nil {
+		return 0, err
+	}
+	return orgID, tx.Commit()
+}
+
+// GetOrganizations retrieves the organizations a user is a member of together with the role of the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.Organization instances ordered by ID.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetOrganizations(ctx context.Context, userID int64) ([]*model.Organization, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT o.id, o.name, m.role FROM organizations o JOIN org_members m ON m.org_id = o.id WHERE m.user_id = $1 ORDER BY o.id",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var orgs []*model.Organization
+	for rows.Next() {
+		var o model.Organization
+		if err = rows.Scan(&o.ID, &o.Name, &o.Role); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, &o)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// RemoveOrganization deletes an organization with its members, collections and the items of the collections.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - orgID: An int64 representing the unique identifier of the organization.
+//
+// Returns:
+//   - The object keys of the removed files. The caller is responsible for removing these objects.
+//   - An error if the operation fails, or sql.ErrNoRows if there is no such organization.
+func (p *PostgresStorage) RemoveOrganization(ctx context.Context, orgID int64) ([]string, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	keys, err := collectionObjectKeys(ctx, tx, "SELECT id FROM collections WHERE org_id = $1", orgID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM organizations WHERE id = $1", orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkRowsAffected(res); err != nil {
+		return nil, err
+	}
+	return keys, tx.Commit()
+}
+
+// GetOrgMember retrieves the membership of a user in an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - orgID: An int64 representing the unique identifier of the organization.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A pointer to a model.OrgMember instance.
+//   - An error if the operation fails, or sql.ErrNoRows if the user isn't a member of the organization.
+func (p *PostgresStorage) GetOrgMember(ctx context.Context, orgID, userID int64) (*model.OrgMember, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT m.org_id, m.user_id, u.login, m.role, m.wrapped_key FROM org_members m JOIN users u ON u.id = m.user_id "+
+			"WHERE m.org_id = $1 AND m.user_id = $2",
+		orgID, userID)
+
+	var m model.OrgMember
+	if err := row.Scan(&m.OrgID, &m.UserID, &m.Login, &m.Role, &m.WrappedKey); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetCollectionMember retrieves the membership of a user in the organization a collection belongs to.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - collectionID: An int64 representing the unique identifier of the collection.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A pointer to a model.OrgMember instance.
+//   - An error if the operation fails, or sql.ErrNoRows if there is no such collection or the user isn't
+//     a member of its organization.
+func (p *PostgresStorage) GetCollectionMember(ctx context.Context, collectionID, userID int64) (*model.OrgMember, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT m.org_id, m.user_id, u.login, m.role, m.wrapped_key FROM collections c "+
+			"JOIN org_members m ON m.org_id = c.org_id JOIN users u ON u.id = m.user_id "+
+			"WHERE c.id = $1 AND m.user_id = $2",
+		collectionID, userID)
+
+	var m model.OrgMember
+	if err := row.Scan(&m.OrgID, &m.UserID, &m.Login, &m.Role, &m.WrappedKey); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetOrgMembers retrieves the members of an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - orgID: An int64 representing the unique identifier of the organization.
+//
+// Returns:
+//   - A slice of pointers to model.OrgMember instances ordered by login, without the wrapped keys.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetOrgMembers(ctx context.Context, orgID int64) ([]*model.OrgMember, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT m.org_id, m.user_id, u.login, m.role FROM org_members m JOIN users u ON u.id = m.user_id "+
+			"WHERE m.org_id = $1 ORDER BY u.login",
+		orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var members []*model.OrgMember
+	for rows.Next() {
+		var m model.OrgMember
+		if err = rows.Scan(&m.OrgID, &m.UserID, &m.Login, &m.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddOrgMember adds a user to an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - member: A pointer to a model.OrgMember instance containing the organization, the user, the role
+//     and the wrapped organization key.
+//
+// Returns:
+//   - ErrOrgMemberExists if the user is already a member of the organization.
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddOrgMember(ctx context.Context, member *model.OrgMember) error {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO org_members (org_id, user_id, role, wrapped_key) VALUES ($1, $2, $3, $4) ON CONFLICT (org_id, user_id) DO NOTHING",
+		member.OrgID, member.UserID, member.Role, member.WrappedKey)
+	if err != nil {
+		return err
+	}
+	if err = checkRowsAffected(res); errors.Is(err, sql.ErrNoRows) {
+		return ErrOrgMemberExists
+	}
+	return err
+}
+
+// SetOrgMemberRole changes the role of a member of an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - orgID: An int64 representing the unique identifier of the organization.
+//   - userID: An int64 representing the unique identifier of the member.
+//   - role: A string containing the new role, one of the model.Role constants.
+//
+// Returns:
+//   - ErrLastOwner if the member is the last owner of the organization and the new role isn't owner.
+//   - An error if the operation fails, or sql.ErrNoRows if the user isn't a member of the organization.
+func (p *PostgresStorage) SetOrgMemberRole(ctx context.Context, orgID, userID int64, role string) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if role != model.RoleOwner {
+		if err = checkNotLastOwner(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+	}
+	res, err := tx.ExecContext(ctx, "UPDATE org_members SET role = $1 WHERE org_id = $2 AND user_id = $3", role, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if err = checkRowsAffected(res); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveOrgMember removes a user from an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - orgID: An int64 representing the unique identifier of the organization.
+//   - userID: An int64 representing the unique identifier of the member.
+//
+// Returns:
+//   - ErrLastOwner if the member is the last owner of the organization.
+//   - An error if the operation fails, or sql.ErrNoRows if the user isn't a member of the organization.
+func (p *PostgresStorage) RemoveOrgMember(ctx context.Context, orgID, userID int64) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = checkNotLastOwner(ctx, tx, orgID, userID); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM org_members WHERE org_id = $1 AND user_id = $2", orgID, userID)
+	if err != nil {
+		return err
+	}
+	if err = checkRowsAffected(res); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkNotLastOwner locks the owners of an organization and returns ErrLastOwner if the user is the only one.
+func checkNotLastOwner(ctx context.Context, tx *sql.Tx, orgID, userID int64) error {
+	rows, err := tx.QueryContext(ctx, "SELECT user_id FROM org_members WHERE org_id = $1 AND role = $2 FOR UPDATE", orgID, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var owners []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return err
+		}
+		owners = append(owners, id)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if len(owners) == 1 && owners[0] == userID {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// AddCollection creates a collection in an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - collection: A pointer to a model.Collection instance containing the organization and the name of the collection.
+//
+// Returns:
+//   - The ID of the new collection.
+//   - ErrCollectionExists if the organization already has a collection with the name.
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddCollection(ctx context.Context, collection *model.Collection) (int64, error) {
+	var id int64
+	err := p.Conn.QueryRowContext(
+		ctx,
+		"INSERT INTO collections (org_id, name) VALUES ($1, $2) ON CONFLICT (org_id, name) DO NOTHING RETURNING id",
+		collection.OrgID, collection.Name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrCollectionExists
+	}
+	return id, err
+}
+
+// GetCollections retrieves the collections of an organization.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - orgID: An int64 representing the unique identifier of the organization.
+//
+// Returns:
+//   - A slice of pointers to model.Collection instances ordered by name.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetCollections(ctx context.Context, orgID int64) ([]*model.Collection, error) {
+	rows, err := p.Conn.QueryContext(ctx, "SELECT id, org_id, name FROM collections WHERE org_id = $1 ORDER BY name", orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var collections []*model.Collection
+	for rows.Next() {
+		var c model.Collection
+		if err = rows.Scan(&c.ID, &c.OrgID, &c.Name); err != nil {
+			return nil, err
+		}
+		collections = append(collections, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// RemoveCollection deletes a collection with its items.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - collectionID: An int64 representing the unique identifier of the collection.
+//
+// Returns:
+//   - The object keys of the removed files. The caller is responsible for removing these objects.
+//   - An error if the operation fails, or sql.ErrNoRows if there is no such collection.
+func (p *PostgresStorage) RemoveCollection(ctx context.Context, collectionID int64) ([]string, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	keys, err := collectionObjectKeys(ctx, tx, "SELECT id FROM collections WHERE id = $1", collectionID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM collections WHERE id = $1", collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkRowsAffected(res); err != nil {
+		return nil, err
+	}
+	return keys, tx.Commit()
+}
+
+// collectionObjectKeys locks the files of the collections selected by the query and returns their object keys.
+func collectionObjectKeys(ctx context.Context, tx *sql.Tx, collections string, id int64) ([]string, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT COALESCE(object_key, file_name) FROM files WHERE collection_id IN ("+collections+") FOR UPDATE",
+		id)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetCollectionCredentials retrieves all credentials of a collection.
+func (p *PostgresStorage) GetCollectionCredentials(ctx context.Context, collectionID int64) ([]*model.Credentials, error) {
+	return p.getCredentials(ctx, ownerCollection, collectionID)
+}
+
+// GetCollectionCredential retrieves credentials of a collection by ID, it returns sql.ErrNoRows if the collection
+// has no such credentials.
+func (p *PostgresStorage) GetCollectionCredential(ctx context.Context, collectionID, id int64) (*model.Credentials, error) {
+	return p.getCredential(ctx, ownerCollection, collectionID, id)
+}
+
+// RemoveCollectionCredential deletes credentials of a collection by ID, it returns sql.ErrNoRows if the collection
+// has no such credentials.
+func (p *PostgresStorage) RemoveCollectionCredential(ctx context.Context, collectionID, id int64) error {
+	return p.removeItem(ctx, "user_credentials", ownerCollection, collectionID, id)
+}
+
+// GetCollectionNotes retrieves all notes of a collection.
+func (p *PostgresStorage) GetCollectionNotes(ctx context.Context, collectionID int64) ([]*model.Note, error) {
+	return p.getNotes(ctx, ownerCollection, collectionID)
+}
+
+// GetCollectionNote retrieves a note of a collection by ID, it returns sql.ErrNoRows if the collection has no such note.
+func (p *PostgresStorage) GetCollectionNote(ctx context.Context, collectionID, id int64) (*model.Note, error) {
+	return p.getNote(ctx, ownerCollection, collectionID, id)
+}
+
+// RemoveCollectionNote deletes a note of a collection by ID, it returns sql.ErrNoRows if the collection has no such note.
+func (p *PostgresStorage) RemoveCollectionNote(ctx context.Context, collectionID, id int64) error {
+	return p.removeItem(ctx, "notes", ownerCollection, collectionID, id)
+}
+
+// GetCollectionBankCards retrieves all bank cards of a collection.
+func (p *PostgresStorage) GetCollectionBankCards(ctx context.Context, collectionID int64) ([]*model.BankCard, error) {
+	return p.getBankCards(ctx, ownerCollection, collectionID)
+}
+
+// GetCollectionBankCard retrieves a bank card of a collection by ID, it returns sql.ErrNoRows if the collection
+// has no such card.
+func (p *PostgresStorage) GetCollectionBankCard(ctx context.Context, collectionID, id int64) (*model.BankCard, error) {
+	return p.getBankCard(ctx, ownerCollection, collectionID, id)
+}
+
+// RemoveCollectionBankCard deletes a bank card of a collection by ID, it returns sql.ErrNoRows if the collection
+// has no such card.
+func (p *PostgresStorage) RemoveCollectionBankCard(ctx context.Context, collectionID, id int64) error {
+	return p.removeItem(ctx, "bank_cards", ownerCollection, collectionID, id)
+}
+
+// GetCollectionOTPItems retrieves all TOTP authenticator entries of a collection.
+func (p *PostgresStorage) GetCollectionOTPItems(ctx context.Context, collectionID int64) ([]*model.OTPItem, error) {
+	return p.getOTPItems(ctx, ownerCollection, collectionID)
+}
+
+// GetCollectionOTPItem retrieves a TOTP authenticator entry of a collection by ID, it returns sql.ErrNoRows
+// if the collection has no such entry.
+func (p *PostgresStorage) GetCollectionOTPItem(ctx context.Context, collectionID, id int64) (*model.OTPItem, error) {
+	return p.getOTPItem(ctx, ownerCollection, collectionID, id)
+}
+
+// RemoveCollectionOTPItem deletes a TOTP authenticator entry of a collection by ID, it returns sql.ErrNoRows
+// if the collection has no such entry.
+func (p *PostgresStorage) RemoveCollectionOTPItem(ctx context.Context, collectionID, id int64) error {
+	return p.removeItem(ctx, "otp_items", ownerCollection, collectionID, id)
+}
+
+// GetCollectionCustomItems retrieves all custom items of a collection.
+func (p *PostgresStorage) GetCollectionCustomItems(ctx context.Context, collectionID int64) ([]*model.CustomItem, error) {
+	return p.getCustomItems(ctx, ownerCollection, collectionID)
+}
+
+// GetCollectionCustomItem retrieves a custom item of a collection by ID, it returns sql.ErrNoRows
+// if the collection has no such item.
+func (p *PostgresStorage) GetCollectionCustomItem(ctx context.Context, collectionID, id int64) (*model.CustomItem, error) {
+	return p.getCustomItem(ctx, ownerCollection, collectionID, id)
+}
+
+// RemoveCollectionCustomItem deletes a custom item of a collection by ID, it returns sql.ErrNoRows
+// if the collection has no such item.
+func (p *PostgresStorage) RemoveCollectionCustomItem(ctx context.Context, collectionID, id int64) error {
+	return p.removeItem(ctx, "custom_items", ownerCollection, collectionID, id)
+}
+
+// AddCollectionFile adds a new file or updates an existing file of a collection, see AddFile.
+func (p *PostgresStorage) AddCollectionFile(ctx context.Context, bucketName, fileName, objectKey, description string, collectionID, fileSize int64) (string, error) {
+	return p.addFile(ctx, bucketName, fileName, objectKey, description, ownerCollection, collectionID, fileSize)
+}
+
+// GetCollectionFile retrieves a file of a collection by its name, it returns sql.ErrNoRows if the collection
+// has no such file.
+func (p *PostgresStorage) GetCollectionFile(ctx context.Context, collectionID int64, fileName string) (*model.File, error) {
+	return p.getFile(ctx, ownerCollection, collectionID, fileName)
+}
+
+// GetCollectionFiles retrieves all files of a collection.
+func (p *PostgresStorage) GetCollectionFiles(ctx context.Context, collectionID int64) ([]*model.File, error) {
+	return p.getFiles(ctx, ownerCollection, collectionID)
+}
+
+// RemoveCollectionFile deletes a file of a collection by its name, it returns sql.ErrNoRows if the collection
+// has no such file.
+func (p *PostgresStorage) RemoveCollectionFile(ctx context.Context, collectionID int64, fileName string) error {
+	return p.removeFile(ctx, ownerCollection, collectionID, fileName)
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+func TestPostgresStorage_Organizations(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	for _, login := range []string{"alice", "bob"} {
+		require.NoError(t, db.AddUser(ctx, login, "password"))
+	}
+	const alice, bob = 1, 2
+
+	orgID, err := db.AddOrganization(ctx, "team", &model.OrgMember{UserID: alice, Role: model.RoleOwner, WrappedKey: "alice key"})
+	require.NoError(t, err)
+
+	t.Run("test members", func(t *testing.T) {
+		require.NoError(t, db.AddOrgMember(ctx, &model.OrgMember{OrgID: orgID, UserID: bob, Role: model.RoleViewer, WrappedKey: "bob key"}))
+		err := db.AddOrgMember(ctx, &model.OrgMember{OrgID: orgID, UserID: bob, Role: model.RoleAdmin, WrappedKey: "bob key"})
+		assert.ErrorIs(t, err, ErrOrgMemberExists)
+
+		member, err := db.GetOrgMember(ctx, orgID, bob)
+		require.NoError(t, err)
+		assert.Equal(t, model.RoleViewer, member.Role)
+		assert.Equal(t, "bob key", member.WrappedKey)
+
+		members, err := db.GetOrgMembers(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+
+		orgs, err := db.GetOrganizations(ctx, bob)
+		require.NoError(t, err)
+		require.Len(t, orgs, 1)
+		assert.Equal(t, "team", orgs[0].Name)
+		assert.Equal(t, model.RoleViewer, orgs[0].Role)
+	})
+
+	t.Run("test last owner", func(t *testing.T) {
+		err := db.SetOrgMemberRole(ctx, orgID, alice, model.RoleAdmin)
+		assert.ErrorIs(t, err, ErrLastOwner)
+		err = db.RemoveOrgMember(ctx, orgID, alice)
+		assert.ErrorIs(t, err, ErrLastOwner)
+
+		require.NoError(t, db.SetOrgMemberRole(ctx, orgID, bob, model.RoleOwner))
+		require.NoError(t, db.SetOrgMemberRole(ctx, orgID, alice, model.RoleAdmin))
+		require.NoError(t, db.SetOrgMemberRole(ctx, orgID, alice, model.RoleOwner))
+		require.NoError(t, db.SetOrgMemberRole(ctx, orgID, bob, model.RoleViewer))
+
+		err = db.SetOrgMemberRole(ctx, orgID, 42, model.RoleViewer)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	var collectionID int64
+	t.Run("test collections", func(t *testing.T) {
+		collectionID, err = db.AddCollection(ctx, &model.Collection{OrgID: orgID, Name: "servers"})
+		require.NoError(t, err)
+		_, err = db.AddCollection(ctx, &model.Collection{OrgID: orgID, Name: "servers"})
+		assert.ErrorIs(t, err, ErrCollectionExists)
+
+		collections, err := db.GetCollections(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, collections, 1)
+		assert.Equal(t, "servers", collections[0].Name)
+
+		member, err := db.GetCollectionMember(ctx, collectionID, bob)
+		require.NoError(t, err)
+		assert.Equal(t, orgID, member.OrgID)
+		assert.Equal(t, model.RoleViewer, member.Role)
+	})
+
+	t.Run("test collection items are not personal", func(t *testing.T) {
+		require.NoError(t, db.AddNote(ctx, &model.Note{CollectionID: collectionID, Text: "text", Description: "description"}))
+		_, err := db.AddCollectionFile(ctx, "bucket", "file.txt", "org/file.txt", "description", collectionID, 3)
+		require.NoError(t, err)
+
+		notes, err := db.GetCollectionNotes(ctx, collectionID)
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, collectionID, notes[0].CollectionID)
+		assert.Zero(t, notes[0].UserID)
+
+		personal, err := db.GetNotes(ctx, alice)
+		require.NoError(t, err)
+		assert.Empty(t, personal)
+		err = db.RemoveNote(ctx, alice, notes[0].ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("test remove collection", func(t *testing.T) {
+		keys, err := db.RemoveCollection(ctx, collectionID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"org/file.txt"}, keys)
+
+		notes, err := db.GetCollectionNotes(ctx, collectionID)
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+		_, err = db.GetCollectionMember(ctx, collectionID, bob)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("test remove organization", func(t *testing.T) {
+		require.NoError(t, db.RemoveOrgMember(ctx, orgID, bob))
+		orgs, err := db.GetOrganizations(ctx, bob)
+		require.NoError(t, err)
+		assert.Empty(t, orgs)
+
+		_, err = db.RemoveOrganization(ctx, orgID)
+		require.NoError(t, err)
+		_, err = db.GetOrgMember(ctx, orgID, alice)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
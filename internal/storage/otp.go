@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+// AddOTPItem adds a new TOTP authenticator entry to the database.
+// The entry belongs to the organization collection if its CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - item: A pointer to a model.OTPItem instance containing the entry information to add.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddOTPItem(ctx context.Context, item *model.OTPItem) error {
+	_, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO otp_items (user_id, collection_id, secret, issuer, account, algorithm, digits, period, description) "+
+			"VALUES (NULLIF($1::BIGINT, 0), NULLIF($2::BIGINT, 0), $3, $4, $5, $6, $7, $8, $9)",
+		item.UserID, item.CollectionID, item.Secret, item.Issuer, item.Account, item.Algorithm, item.Digits, item.Period, item.Description)
+	return err
+}
+
+// GetOTPItems retrieves all TOTP authenticator entries associated with a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.OTPItem instances containing the user's entries.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetOTPItems(ctx context.Context, userID int64) ([]*model.OTPItem, error) {
+	return p.getOTPItems(ctx, ownerUser, userID)
+}
+
+func (p *PostgresStorage) getOTPItems(ctx context.Context, owner itemOwner, ownerID int64) ([]*model.OTPItem, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), secret, issuer, account, algorithm, digits, period, description, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM otp_items WHERE "+string(owner)+" = $1",
+		ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var items []*model.OTPItem
+	for rows.Next() {
+		var i model.OTPItem
+		if err = rows.Scan(&i.ID, &i.UserID, &i.CollectionID, &i.Secret, &i.Issuer, &i.Account, &i.Algorithm, &i.Digits, &i.Period, &i.Description, &i.Version, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetOTPItem retrieves a specific TOTP authenticator entry by its ID from the database.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the entry owner.
+//   - id: An int64 representing the unique identifier of the entry to retrieve.
+//
+// Returns:
+//   - A pointer to a model.OTPItem instance containing the entry information.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such entry.
+func (p *PostgresStorage) GetOTPItem(ctx context.Context, userID, id int64) (*model.OTPItem, error) {
+	return p.getOTPItem(ctx, ownerUser, userID, id)
+}
+
+func (p *PostgresStorage) getOTPItem(ctx context.Context, owner itemOwner, ownerID, id int64) (*model.OTPItem, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), secret, issuer, account, algorithm, digits, period, description, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM otp_items WHERE id = $1 AND "+string(owner)+" = $2",
+		id, ownerID)
+
+	var item model.OTPItem
+	if err := row.Scan(&item.ID, &item.UserID, &item.CollectionID, &item.Secret, &item.Issuer, &item.Account, &item.Algorithm, &item.Digits, &item.Period, &item.Description, &item.Version, &item.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// UpdateOTPItem updates a TOTP authenticator entry if its version matches, the entry belongs to the organization collection
+// if its CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - item: A pointer to a model.OTPItem instance containing the new entry information and the version it is based on.
+//
+// Returns:
+//   - The new version of the entry.
+//   - sql.ErrNoRows if the owner has no such entry, ErrVersionConflict if the version is stale,
+//     or an error if the operation fails.
+func (p *PostgresStorage) UpdateOTPItem(ctx context.Context, item *model.OTPItem) (int64, error) {
+	owner, ownerID := ownerOf(item.UserID, item.CollectionID)
+	return p.updateItem(
+		ctx, "otp_items", owner, ownerID, item.ID, item.Version, "",
+		[]string{"secret", "issuer", "account", "algorithm", "digits", "period", "description"},
+		item.Secret, item.Issuer, item.Account, item.Algorithm, item.Digits, item.Period, item.Description)
+}
+
+// RemoveOTPItem deletes a TOTP authenticator entry from the database by its ID.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the entry owner.
+//   - id: An int64 representing the unique identifier of the entry to delete.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such entry.
+func (p *PostgresStorage) RemoveOTPItem(ctx context.Context, userID, id int64) error {
+	return p.removeItem(ctx, "otp_items", ownerUser, userID, id)
+}
@@ -9,6 +9,8 @@ import (
 	"database/sql"
 	"embed"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -26,6 +28,32 @@ import (
 //go:embed migrations/*.sql
 var Migrations embed.FS
 
+var (
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again.
+	// The session the token belongs to is revoked in this case.
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	// ErrSessionRevoked is returned when a refresh token belongs to a revoked or expired session.
+	ErrSessionRevoked = errors.New("session is revoked or expired")
+	// ErrVaultItemsChanged is returned by RekeyVault when the re-encrypted items don't match the items of the user,
+	// for example, because an item was added or removed by another client in the meantime.
+	ErrVaultItemsChanged = errors.New("vault items have changed")
+	// ErrVersionConflict is returned when an item is updated with a version other than its current one,
+	// because it has been changed by another client in the meantime.
+	ErrVersionConflict = errors.New("item has been changed by another client")
+	// ErrFileExists is returned by UpdateFile when the owner already has a file with the new name.
+	ErrFileExists = errors.New("file already exists")
+)
+
+// itemOwner is the column of an item table referencing the owner of the items.
+type itemOwner string
+
+const (
+	// ownerUser selects the personal items of a user.
+	ownerUser itemOwner = "user_id"
+	// ownerCollection selects the items of an organization collection.
+	ownerCollection itemOwner = "collection_id"
+)
+
 // PostgresStorage represents a storage backend using PostgreSQL.
 type PostgresStorage struct {
 	Conn *sql.DB
@@ -109,81 +137,265 @@ func (p *PostgresStorage) AddUser(ctx context.Context, login, password string) e
 	return err
 }
 
-// AddFile adds a new file or updates an existing file for a user.
+// AddUserWithVault adds a new user together with the parameters of the user's vault key in a single transaction.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - bucketName: A string representing the name of the bucket where the file is stored.
-//   - fileName: A string representing the name of the file.
-//   - description: A string providing additional information about the file.
-//   - userID: An int64 representing the unique identifier of the user.
-//   - fileSize: An int64 representing the size of the file in bytes.
+//   - login: A string representing the user's login name.
+//   - password: A string representing the user's password.
+//   - vault: A pointer to a model.Vault instance with the key derivation parameters. Its UserID is ignored.
 //
 // Returns:
 //   - An error if the operation fails.
-func (p *PostgresStorage) AddFile(ctx context.Context, bucketName, fileName, description string, userID int64, fileSize int64) error {
-	var count int
-	row := p.Conn.QueryRowContext(
-		ctx,
-		"SELECT count(*) FROM files WHERE file_name=$1 and user_id=$2",
-		fileName, userID)
-	if err := row.Scan(&count); err != nil {
+func (p *PostgresStorage) AddUserWithVault(ctx context.Context, login, password string, vault *model.Vault) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	if count > 0 {
-		_, err := p.Conn.ExecContext(
-			ctx,
-			"UPDATE files SET file_size=$1, description=$2 WHERE file_name=$3", fileSize, description, fileName)
+	var userID int64
+	row := tx.QueryRowContext(ctx, "INSERT INTO users (login, password) VALUES ($1, $2) RETURNING id", login, password)
+	if err = row.Scan(&userID); err != nil {
 		return err
 	}
 
-	_, err := p.Conn.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
-		"INSERT INTO files (user_id, bucket_name, file_name, file_size, description) VALUES ($1, $2, $3, $4, $5)",
-		userID, bucketName, fileName, fileSize, description)
-	return err
+		"INSERT INTO user_vaults (user_id, kdf_salt, kdf_memory, kdf_iterations, kdf_parallelism, verifier) VALUES ($1, $2, $3, $4, $5, $6)",
+		userID, vault.Salt, vault.Memory, vault.Iterations, vault.Parallelism, vault.Verifier)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// GetFile retrieves a file by its name from the database.
+// GetUserVault retrieves the parameters of the vault key of a user.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - fileName: A string representing the name of the file to retrieve.
+//   - userID: An int64 representing the unique identifier of the user.
 //
 // Returns:
-//   - A pointer to a model.File instance containing the file information.
-//   - An error if the operation fails or if the file is not found.
-func (p *PostgresStorage) GetFile(ctx context.Context, fileName string) (*model.File, error) {
+//   - A pointer to a model.Vault instance containing the key derivation parameters.
+//   - An error if the operation fails or if the user has no vault (sql.ErrNoRows).
+func (p *PostgresStorage) GetUserVault(ctx context.Context, userID int64) (*model.Vault, error) {
 	row := p.Conn.QueryRowContext(
 		ctx,
-		"SELECT user_id, id, file_name, bucket_name, description, file_size, created_at FROM files WHERE file_name = $1",
-		fileName)
+		"SELECT user_id, kdf_salt, kdf_memory, kdf_iterations, kdf_parallelism, verifier FROM user_vaults WHERE user_id = $1",
+		userID)
 
-	var f model.File
-	if err := row.Scan(&f.UserID, &f.ID, &f.FileName, &f.BucketName, &f.Description, &f.FileSize, &f.CreatedAt); err != nil {
+	var v model.Vault
+	if err := row.Scan(&v.UserID, &v.Salt, &v.Memory, &v.Iterations, &v.Parallelism, &v.Verifier); err != nil {
 		return nil, err
 	}
-	return &f, nil
+	return &v, nil
 }
 
-// GetFiles retrieves all files associated with a user.
+// RekeyVault replaces all the items of a user with their copies re-encrypted under a new vault key
+// and stores the parameters of the new vault key in a single transaction.
+//
+// The items are matched by ID (files by name) and the set of items must be exactly the set of items the user has.
+// Items carrying a version are only replaced if they haven't been updated since they were read.
+// The private sharing key must be re-encrypted too if the user has sharing keys. Files with an object key
+// are switched to the staged upload with this key, which is consumed.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - userID: An int64 representing the unique identifier of the user.
+//   - rekey: A pointer to a model.VaultRekey instance containing the re-encrypted items.
 //
 // Returns:
-//   - A slice of pointers to model.File instances containing the user's files.
+//   - The object keys the re-encrypted files pointed to before the update. The caller is responsible
+//     for removing these objects.
+//   - ErrVaultItemsChanged if the items don't match the items of the user, an item has been updated
+//     or a staged upload is missing.
 //   - An error if the operation fails.
-func (p *PostgresStorage) GetFiles(ctx context.Context, userID int64) ([]*model.File, error) {
-	rows, err := p.Conn.QueryContext(
-		ctx,
-		"SELECT user_id, id, file_name, bucket_name, description, file_size, created_at id FROM files WHERE user_id = $1",
-		userID)
+func (p *PostgresStorage) RekeyVault(ctx context.Context, rekey *model.VaultRekey) ([]string, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userID := rekey.UserID
+	cardIDs := make([]int64, 0, len(rekey.Cards))
+	for _, card := range rekey.Cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+	noteIDs := make([]int64, 0, len(rekey.Notes))
+	for _, note := range rekey.Notes {
+		noteIDs = append(noteIDs, note.ID)
+	}
+	credIDs := make([]int64, 0, len(rekey.Credentials))
+	for _, cred := range rekey.Credentials {
+		credIDs = append(credIDs, cred.ID)
+	}
+	otpIDs := make([]int64, 0, len(rekey.OTPItems))
+	for _, item := range rekey.OTPItems {
+		otpIDs = append(otpIDs, item.ID)
+	}
+	customIDs := make([]int64, 0, len(rekey.CustomItems))
+	for _, item := range rekey.CustomItems {
+		customIDs = append(customIDs, item.ID)
+	}
+	folderIDs := make([]int64, 0, len(rekey.Folders))
+	for _, folder := range rekey.Folders {
+		folderIDs = append(folderIDs, folder.ID)
+	}
+	tagIDs := make([]int64, 0, len(rekey.Tags))
+	for _, tag := range rekey.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	for _, check := range []struct {
+		query string
+		ids   []int64
+	}{
+		{query: "SELECT id FROM bank_cards WHERE user_id = $1 FOR UPDATE", ids: cardIDs},
+		{query: "SELECT id FROM notes WHERE user_id = $1 FOR UPDATE", ids: noteIDs},
+		{query: "SELECT id FROM user_credentials WHERE user_id = $1 FOR UPDATE", ids: credIDs},
+		{query: "SELECT id FROM otp_items WHERE user_id = $1 FOR UPDATE", ids: otpIDs},
+		{query: "SELECT id FROM custom_items WHERE user_id = $1 FOR UPDATE", ids: customIDs},
+		{query: "SELECT id FROM folders WHERE user_id = $1 FOR UPDATE", ids: folderIDs},
+		{query: "SELECT id FROM tags WHERE user_id = $1 FOR UPDATE", ids: tagIDs},
+	} {
+		if err = checkUserItems(ctx, tx, check.query, userID, check.ids); err != nil {
+			return nil, err
+		}
+	}
+	previousKeys, err := lockUserFiles(ctx, tx, userID, rekey.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	// The re-encrypted items replace the items of the versions they were read at, a zero version skips the check.
+	const rekeyItem = "version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND ($3::BIGINT = 0 OR version = $3)"
+	for _, card := range rekey.Cards {
+		err = rekeyUserItem(
+			ctx, tx, "UPDATE bank_cards SET card_number = $4, expiration_date = $5, cvv = $6, owner = $7, description = $8, "+rekeyItem,
+			card.ID, userID, card.Version, card.Number, card.ExpireDate, card.CVV, card.Owner, card.Description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, note := range rekey.Notes {
+		err = rekeyUserItem(
+			ctx, tx, "UPDATE notes SET text = $4, description = $5, "+rekeyItem,
+			note.ID, userID, note.Version, note.Text, note.Description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, cred := range rekey.Credentials {
+		err = rekeyUserItem(
+			ctx, tx, "UPDATE user_credentials SET login = $4, password = $5, description = $6, "+rekeyItem,
+			cred.ID, userID, cred.Version, cred.Login, cred.Password, cred.Description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range rekey.OTPItems {
+		err = rekeyUserItem(
+			ctx, tx, "UPDATE otp_items SET secret = $4, issuer = $5, account = $6, description = $7, "+rekeyItem,
+			item.ID, userID, item.Version, item.Secret, item.Issuer, item.Account, item.Description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range rekey.CustomItems {
+		err = rekeyUserItem(
+			ctx, tx, "UPDATE custom_items SET name = $4, description = $5, "+rekeyItem,
+			item.ID, userID, item.Version, item.Name, item.Description)
+		if err != nil {
+			return nil, err
+		}
+		if err = replaceItemFields(ctx, tx, item.ID, item.Fields); err != nil {
+			return nil, err
+		}
+	}
+	for _, folder := range rekey.Folders {
+		err = rekeyUserItem(ctx, tx, "UPDATE folders SET name = $3 WHERE id = $1 AND user_id = $2", folder.ID, userID, folder.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, tag := range rekey.Tags {
+		err = rekeyUserItem(ctx, tx, "UPDATE tags SET name = $3 WHERE id = $1 AND user_id = $2", tag.ID, userID, tag.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var replacedKeys []string
+	for _, file := range rekey.Files {
+		if file.ObjectKey == "" {
+			continue
+		}
+		var fileSize int64
+		err = tx.QueryRowContext(
+			ctx,
+			"DELETE FROM staged_uploads WHERE object_key = $1 AND user_id = $2 AND file_name = $3 RETURNING file_size",
+			file.ObjectKey, userID, file.FileName).Scan(&fileSize)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVaultItemsChanged
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE files SET object_key = $1, file_size = $2, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+				"WHERE file_name = $3 AND user_id = $4",
+			file.ObjectKey, fileSize, file.FileName, userID)
+		if err != nil {
+			return nil, err
+		}
+		if previousKeys[file.FileName] != "" {
+			replacedKeys = append(replacedKeys, previousKeys[file.FileName])
+		}
+	}
+
+	var sharingKey string
+	err = tx.QueryRowContext(ctx, "SELECT private_key FROM user_sharing_keys WHERE user_id = $1 FOR UPDATE", userID).Scan(&sharingKey)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if (sharingKey == "") != (rekey.SharingKey == "") {
+		return nil, ErrVaultItemsChanged
+	}
+	if rekey.SharingKey != "" {
+		_, err = tx.ExecContext(ctx, "UPDATE user_sharing_keys SET private_key = $1 WHERE user_id = $2", rekey.SharingKey, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rekey.Vault != nil {
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO user_vaults (user_id, kdf_salt, kdf_memory, kdf_iterations, kdf_parallelism, verifier) "+
+				"VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (user_id) DO UPDATE SET kdf_salt = EXCLUDED.kdf_salt, "+
+				"kdf_memory = EXCLUDED.kdf_memory, kdf_iterations = EXCLUDED.kdf_iterations, "+
+				"kdf_parallelism = EXCLUDED.kdf_parallelism, verifier = EXCLUDED.verifier, created_at = CURRENT_TIMESTAMP",
+			userID, rekey.Vault.Salt, rekey.Vault.Memory, rekey.Vault.Iterations, rekey.Vault.Parallelism, rekey.Vault.Verifier)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM user_vaults WHERE user_id = $1", userID)
+	}
 	if err != nil {
 		return nil, err
 	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return replacedKeys, nil
+}
+
+// checkUserItems locks the items of a user selected by the query and checks that their IDs are exactly the given IDs.
+func checkUserItems(ctx context.Context, tx *sql.Tx, query string, userID int64, ids []int64) error {
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
 	defer func(rows *sql.Rows) {
 		err = rows.Close()
 		if err != nil {
@@ -191,80 +403,188 @@ func (p *PostgresStorage) GetFiles(ctx context.Context, userID int64) ([]*model.
 		}
 	}(rows)
 
-	var files []*model.File
+	existing := make(map[int64]bool)
 	for rows.Next() {
-		var f model.File
-		if err = rows.Scan(&f.UserID, &f.ID, &f.FileName, &f.BucketName, &f.Description, &f.FileSize, &f.CreatedAt); err != nil {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return err
+		}
+		existing[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if len(ids) != len(existing) {
+		return ErrVaultItemsChanged
+	}
+	for _, id := range ids {
+		if !existing[id] {
+			return ErrVaultItemsChanged
+		}
+		delete(existing, id)
+	}
+	return nil
+}
+
+// rekeyUserItem executes the update of a re-encrypted item, it returns ErrVaultItemsChanged if the item has been
+// updated since it was read.
+func rekeyUserItem(ctx context.Context, tx *sql.Tx, query string, args ...any) error {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if err = checkRowsAffected(res); errors.Is(err, sql.ErrNoRows) {
+		return ErrVaultItemsChanged
+	}
+	return err
+}
+
+// lockUserFiles locks the files of a user, checks that their names are exactly the names of the given files
+// and that the files carrying a version still have it, and returns the current object keys of the files by name.
+func lockUserFiles(ctx context.Context, tx *sql.Tx, userID int64, files []*model.File) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT file_name, object_key, version FROM files WHERE user_id = $1 FOR UPDATE", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	keys := make(map[string]string)
+	versions := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var key sql.NullString
+		var version int64
+		if err = rows.Scan(&name, &key, &version); err != nil {
 			return nil, err
 		}
-		files = append(files, &f)
+		keys[name] = key.String
+		versions[name] = version
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return files, nil
+	if len(files) != len(keys) {
+		return nil, ErrVaultItemsChanged
+	}
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		if _, ok := keys[file.FileName]; !ok || seen[file.FileName] {
+			return nil, ErrVaultItemsChanged
+		}
+		// A staged upload made from an older version of the file must not replace its newer content.
+		if file.Version != 0 && file.Version != versions[file.FileName] {
+			return nil, ErrVaultItemsChanged
+		}
+		seen[file.FileName] = true
+	}
+	return keys, nil
 }
 
-// RemoveFile deletes a file from the database by its name.
+// AddFile adds a new file or updates an existing file for a user.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - fileName: A string representing the name of the file to delete.
+//   - bucketName: A string representing the name of the bucket where the file is stored.
+//   - fileName: A string representing the name of the file.
+//   - objectKey: A string representing the key of the uploaded object in the bucket.
+//   - description: A string providing additional information about the file.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - fileSize: An int64 representing the size of the file in bytes.
 //
 // Returns:
+//   - The object key the file pointed to before the update, or an empty string if the file is new or
+//     has not been moved to a per-user object key yet. The caller is responsible for removing that object.
 //   - An error if the operation fails.
-func (p *PostgresStorage) RemoveFile(ctx context.Context, fileName string) error {
-	_, err := p.Conn.ExecContext(ctx, "DELETE FROM files WHERE file_name = $1", fileName)
-	return err
+func (p *PostgresStorage) AddFile(ctx context.Context, bucketName, fileName, objectKey, description string, userID int64, fileSize int64) (string, error) {
+	return p.addFile(ctx, bucketName, fileName, objectKey, description, ownerUser, userID, fileSize)
 }
 
-// GetUser retrieves a user by their login from the database.
-//
-// Parameters:
-//   - ctx: The context for the operation.
-//   - login: A string representing the user's login name.
-//
-// Returns:
-//   - A pointer to a model.User instance containing the user information.
-//   - An error if the operation fails or if the user is not found.
-func (p *PostgresStorage) GetUser(ctx context.Context, login string) (*model.User, error) {
-	row := p.Conn.QueryRowContext(ctx, "SELECT login, password, id FROM users WHERE login = $1", login)
+func (p *PostgresStorage) addFile(ctx context.Context, bucketName, fileName, objectKey, description string, owner itemOwner, ownerID, fileSize int64) (string, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
 
-	var u model.User
-	if err := row.Scan(&u.Login, &u.Password, &u.ID); err != nil {
-		return nil, err
+	var previousKey sql.NullString
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT object_key FROM files WHERE file_name = $1 AND "+string(owner)+" = $2 FOR UPDATE",
+		fileName, ownerID).Scan(&previousKey)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO files ("+string(owner)+", bucket_name, file_name, object_key, file_size, description) VALUES ($1, $2, $3, $4, $5, $6)",
+			ownerID, bucketName, fileName, objectKey, fileSize, description)
+	case err == nil:
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE files SET bucket_name = $1, object_key = $2, file_size = $3, description = $4, "+
+				"version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE file_name = $5 AND "+string(owner)+" = $6",
+			bucketName, objectKey, fileSize, description, fileName, ownerID)
 	}
-	return &u, nil
+	if err != nil {
+		return "", err
+	}
+	return previousKey.String, tx.Commit()
 }
 
-// AddUserCredentials adds new user credentials to the database.
+// GetFile retrieves a file of a user by its name from the database.
+// Files uploaded before per-user object keys were introduced report their file name as the object key.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - cred: A pointer to a model.Credentials instance containing the credentials to add.
+//   - userID: An int64 representing the unique identifier of the file owner.
+//   - fileName: A string representing the name of the file to retrieve.
 //
 // Returns:
-//   - An error if the operation fails.
-func (p *PostgresStorage) AddUserCredentials(ctx context.Context, cred *model.Credentials) error {
-	_, err := p.Conn.ExecContext(
+//   - A pointer to a model.File instance containing the file information.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such file.
+func (p *PostgresStorage) GetFile(ctx context.Context, userID int64, fileName string) (*model.File, error) {
+	return p.getFile(ctx, ownerUser, userID, fileName)
+}
+
+func (p *PostgresStorage) getFile(ctx context.Context, owner itemOwner, ownerID int64, fileName string) (*model.File, error) {
+	row := p.Conn.QueryRowContext(
 		ctx,
-		"INSERT INTO user_credentials (login, password, description, user_id) VALUES ($1, $2, $3, $4)",
-		cred.Login, cred.Password, cred.Description, cred.UserID)
-	return err
+		"SELECT COALESCE(user_id, 0), COALESCE(collection_id, 0), id, file_name, COALESCE(object_key, file_name), bucket_name, description, file_size, created_at, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM files WHERE file_name = $1 AND "+string(owner)+" = $2",
+		fileName, ownerID)
+
+	var f model.File
+	if err := row.Scan(&f.UserID, &f.CollectionID, &f.ID, &f.FileName, &f.ObjectKey, &f.BucketName, &f.Description, &f.FileSize, &f.CreatedAt, &f.Version, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &f, nil
 }
 
-// GetUserCredentials retrieves all credentials associated with a user.
+// GetFiles retrieves all files associated with a user.
 //
 // Parameters:
 //   - ctx: The context for the operation.
 //   - userID: An int64 representing the unique identifier of the user.
 //
 // Returns:
-//   - A slice of pointers to model.Credentials instances containing the user's credentials.
+//   - A slice of pointers to model.File instances containing the user's files.
 //   - An error if the operation fails.
-func (p *PostgresStorage) GetUserCredentials(ctx context.Context, userID int64) ([]*model.Credentials, error) {
-	rows, err := p.Conn.QueryContext(ctx, "SELECT id, user_id, login, password, description FROM user_credentials WHERE user_id = $1", userID)
+func (p *PostgresStorage) GetFiles(ctx context.Context, userID int64) ([]*model.File, error) {
+	return p.getFiles(ctx, ownerUser, userID)
+}
+
+func (p *PostgresStorage) getFiles(ctx context.Context, owner itemOwner, ownerID int64) ([]*model.File, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT COALESCE(user_id, 0), COALESCE(collection_id, 0), id, file_name, COALESCE(object_key, file_name), bucket_name, description, file_size, created_at, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM files WHERE "+string(owner)+" = $1",
+		ownerID)
 	if err != nil {
 		return nil, err
 	}
@@ -275,70 +595,394 @@ func (p *PostgresStorage) GetUserCredentials(ctx context.Context, userID int64)
 		}
 	}(rows)
 
-	var creds []*model.Credentials
+	var files []*model.File
 	for rows.Next() {
-		var c model.Credentials
-		if err = rows.Scan(&c.ID, &c.UserID, &c.Login, &c.Password, &c.Description); err != nil {
+		var f model.File
+		if err = rows.Scan(&f.UserID, &f.CollectionID, &f.ID, &f.FileName, &f.ObjectKey, &f.BucketName, &f.Description, &f.FileSize, &f.CreatedAt, &f.Version, &f.UpdatedAt); err != nil {
 			return nil, err
 		}
-		creds = append(creds, &c)
+		files = append(files, &f)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return creds, nil
+	return files, nil
 }
 
-// GetUserCredential retrieves a specific user credential by its ID.
+// RemoveFile deletes a file of a user from the database by its name.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - id: An int64 representing the unique identifier of the credential.
+//   - userID: An int64 representing the unique identifier of the file owner.
+//   - fileName: A string representing the name of the file to delete.
 //
 // Returns:
-//   - A pointer to a model.Credentials instance containing the credential information.
-//   - An error if the operation fails or if the credential is not found.
-func (p *PostgresStorage) GetUserCredential(ctx context.Context, id int64) (*model.Credentials, error) {
-	row := p.Conn.QueryRowContext(ctx, "SELECT id, user_id, login, password, description FROM user_credentials WHERE id = $1", id)
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such file.
+func (p *PostgresStorage) RemoveFile(ctx context.Context, userID int64, fileName string) error {
+	return p.removeFile(ctx, ownerUser, userID, fileName)
+}
 
-	var cred model.Credentials
-	if err := row.Scan(&cred.ID, &cred.UserID, &cred.Login, &cred.Password, &cred.Description); err != nil {
-		return nil, err
+func (p *PostgresStorage) removeFile(ctx context.Context, owner itemOwner, ownerID int64, fileName string) error {
+	res, err := p.Conn.ExecContext(ctx, "DELETE FROM files WHERE file_name = $1 AND "+string(owner)+" = $2", fileName, ownerID)
+	if err != nil {
+		return err
 	}
-
-	return &cred, nil
+	return checkRowsAffected(res)
 }
 
-// RemoveUserCredential deletes a user credential from the database by its ID.
+// UpdateFile updates the description of a file and optionally renames it if its version matches.
+// The object of the file is kept, files stored under their bare file name keep it as the object key.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - id: An int64 representing the unique identifier of the credential to delete.
+//   - file: A pointer to a model.File instance containing the current name, the owner, the new description
+//     and the version the update is based on.
+//   - newFileName: A string containing the new name of the file, or an empty string to keep the name.
 //
 // Returns:
-//   - An error if the operation fails.
-func (p *PostgresStorage) RemoveUserCredential(ctx context.Context, id int64) error {
-	_, err := p.Conn.ExecContext(ctx, "DELETE FROM user_credentials WHERE id = $1", id)
+//   - The new version of the file.
+//   - sql.ErrNoRows if the owner has no such file, ErrVersionConflict if the version is stale,
+//     ErrFileExists if the owner already has a file with the new name, or an error if the operation fails.
+func (p *PostgresStorage) UpdateFile(ctx context.Context, file *model.File, newFileName string) (int64, error) {
+	owner, ownerID := ownerOf(file.UserID, file.CollectionID)
+	if newFileName == "" {
+		newFileName = file.FileName
+	}
+
+	tx, err := p.Conn.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	defer tx.Rollback()
+
+	var version int64
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT version FROM files WHERE file_name = $1 AND "+string(owner)+" = $2 FOR UPDATE",
+		file.FileName, ownerID).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	if version != file.Version {
+		return 0, ErrVersionConflict
+	}
+
+	if newFileName != file.FileName {
+		var exists bool
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT EXISTS (SELECT 1 FROM files WHERE file_name = $1 AND "+string(owner)+" = $2)",
+			newFileName, ownerID).Scan(&exists)
+		if err != nil {
+			return 0, err
+		}
+		if exists {
+			return 0, ErrFileExists
+		}
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		"UPDATE files SET object_key = COALESCE(object_key, file_name), file_name = $1, description = $2, "+
+			"version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE file_name = $3 AND "+string(owner)+" = $4 RETURNING version",
+		newFileName, file.Description, file.FileName, ownerID).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, tx.Commit()
 }
 
-// AddNote adds a new note to the database.
+// GetLegacyFiles retrieves all files that are still stored in MinIO under their bare file name.
 //
 // Parameters:
 //   - ctx: The context for the operation.
-//   - note: A pointer to a model.Note instance containing the note information to add.
 //
 // Returns:
+//   - A slice of pointers to model.File instances whose ObjectKey equals the file name.
 //   - An error if the operation fails.
-func (p *PostgresStorage) AddNote(ctx context.Context, note *model.Note) error {
-	_, err := p.Conn.ExecContext(
+func (p *PostgresStorage) GetLegacyFiles(ctx context.Context) ([]*model.File, error) {
+	rows, err := p.Conn.QueryContext(
 		ctx,
-		"INSERT INTO notes (text, description, user_id) VALUES ($1, $2, $3)",
-		note.Text, note.Description, note.UserID)
+		"SELECT user_id, id, file_name, file_name, bucket_name, description, file_size, created_at FROM files WHERE object_key IS NULL ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var files []*model.File
+	for rows.Next() {
+		var f model.File
+		if err = rows.Scan(&f.UserID, &f.ID, &f.FileName, &f.ObjectKey, &f.BucketName, &f.Description, &f.FileSize, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		files = append(files, &f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// SetFileObjectKey assigns a per-user object key to a file that is still stored under its bare file name.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - fileID: An int64 representing the unique identifier of the file.
+//   - objectKey: A string representing the new key of the file object in the bucket.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the file does not exist or already has an object key.
+func (p *PostgresStorage) SetFileObjectKey(ctx context.Context, fileID int64, objectKey string) error {
+	res, err := p.Conn.ExecContext(ctx, "UPDATE files SET object_key = $1 WHERE id = $2 AND object_key IS NULL", objectKey, fileID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// IsLegacyObjectReferenced reports whether any file is still stored in the bucket under the given bare file name.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - bucketName: A string representing the name of the bucket.
+//   - fileName: A string representing the legacy object name.
+//
+// Returns:
+//   - true if at least one file without an object key refers to the object.
+//   - An error if the operation fails.
+func (p *PostgresStorage) IsLegacyObjectReferenced(ctx context.Context, bucketName, fileName string) (bool, error) {
+	var referenced bool
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM files WHERE bucket_name = $1 AND file_name = $2 AND object_key IS NULL)",
+		bucketName, fileName)
+	if err := row.Scan(&referenced); err != nil {
+		return false, err
+	}
+	return referenced, nil
+}
+
+// AddStagedUpload records an object uploaded by a user that is not yet referenced by any file.
+// The object is referenced by a file later with RekeyVault.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - objectKey: A string representing the key of the uploaded object in the bucket.
+//   - fileName: A string representing the name of the file the object is uploaded for.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - fileSize: An int64 representing the size of the object in bytes.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddStagedUpload(ctx context.Context, objectKey, fileName string, userID, fileSize int64) error {
+	_, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO staged_uploads (object_key, user_id, file_name, file_size) VALUES ($1, $2, $3, $4)",
+		objectKey, userID, fileName, fileSize)
+	return err
+}
+
+// GetUser retrieves a user by their login from the database.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - login: A string representing the user's login name.
+//
+// Returns:
+//   - A pointer to a model.User instance containing the user information.
+//   - An error if the operation fails or if the user is not found.
+func (p *PostgresStorage) GetUser(ctx context.Context, login string) (*model.User, error) {
+	row := p.Conn.QueryRowContext(ctx, "SELECT login, password, id FROM users WHERE login = $1", login)
+
+	var u model.User
+	if err := row.Scan(&u.Login, &u.Password, &u.ID); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetUserByID retrieves a user by their unique identifier from the database.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A pointer to a model.User instance containing the user information.
+//   - An error if the operation fails or if the user is not found.
+func (p *PostgresStorage) GetUserByID(ctx context.Context, userID int64) (*model.User, error) {
+	row := p.Conn.QueryRowContext(ctx, "SELECT login, password, id FROM users WHERE id = $1", userID)
+
+	var u model.User
+	if err := row.Scan(&u.Login, &u.Password, &u.ID); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateUserPassword replaces the stored password hash of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - password: A string representing the new encoded password hash.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) UpdateUserPassword(ctx context.Context, userID int64, password string) error {
+	_, err := p.Conn.ExecContext(ctx, "UPDATE users SET password = $1 WHERE id = $2", password, userID)
+	return err
+}
+
+// AddUserCredentials adds new user credentials to the database.
+// The credentials belong to the organization collection if their CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - cred: A pointer to a model.Credentials instance containing the credentials to add.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddUserCredentials(ctx context.Context, cred *model.Credentials) error {
+	_, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO user_credentials (login, password, description, user_id, collection_id) "+
+			"VALUES ($1, $2, $3, NULLIF($4::BIGINT, 0), NULLIF($5::BIGINT, 0))",
+		cred.Login, cred.Password, cred.Description, cred.UserID, cred.CollectionID)
+	return err
+}
+
+// GetUserCredentials retrieves all credentials associated with a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.Credentials instances containing the user's credentials.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetUserCredentials(ctx context.Context, userID int64) ([]*model.Credentials, error) {
+	return p.getCredentials(ctx, ownerUser, userID)
+}
+
+func (p *PostgresStorage) getCredentials(ctx context.Context, owner itemOwner, ownerID int64) ([]*model.Credentials, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), login, password, description, COALESCE(created_at, CURRENT_TIMESTAMP), "+
+			"version, COALESCE(updated_at, CURRENT_TIMESTAMP), COALESCE(password_changed_at, created_at, CURRENT_TIMESTAMP) FROM user_credentials WHERE "+string(owner)+" = $1",
+		ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var creds []*model.Credentials
+	for rows.Next() {
+		var c model.Credentials
+		if err = rows.Scan(&c.ID, &c.UserID, &c.CollectionID, &c.Login, &c.Password, &c.Description, &c.CreatedAt, &c.Version, &c.UpdatedAt, &c.PasswordChangedAt); err != nil {
+			return nil, err
+		}
+		creds = append(creds, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// GetUserCredential retrieves a specific user credential by its ID.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the credential owner.
+//   - id: An int64 representing the unique identifier of the credential.
+//
+// Returns:
+//   - A pointer to a model.Credentials instance containing the credential information.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such credential.
+func (p *PostgresStorage) GetUserCredential(ctx context.Context, userID, id int64) (*model.Credentials, error) {
+	return p.getCredential(ctx, ownerUser, userID, id)
+}
+
+func (p *PostgresStorage) getCredential(ctx context.Context, owner itemOwner, ownerID, id int64) (*model.Credentials, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), login, password, description, COALESCE(created_at, CURRENT_TIMESTAMP), "+
+			"version, COALESCE(updated_at, CURRENT_TIMESTAMP), COALESCE(password_changed_at, created_at, CURRENT_TIMESTAMP) FROM user_credentials WHERE id = $1 AND "+string(owner)+" = $2",
+		id, ownerID)
+
+	var cred model.Credentials
+	if err := row.Scan(&cred.ID, &cred.UserID, &cred.CollectionID, &cred.Login, &cred.Password, &cred.Description, &cred.CreatedAt, &cred.Version, &cred.UpdatedAt, &cred.PasswordChangedAt); err != nil {
+		return nil, err
+	}
+
+	return &cred, nil
+}
+
+// UpdateUserCredentials updates user credentials if their version matches, the credentials belong to the organization
+// collection if their CollectionID is set, otherwise to the user. The moment the password was changed is only
+// updated if the encrypted password differs from the stored one, so the client must send the stored value
+// of an unchanged password as is.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - cred: A pointer to a model.Credentials instance containing the new credentials information and the version it is based on.
+//   - sharedData: The credentials encrypted with the data key of their shared copy if they are shared, otherwise an empty string.
+//
+// Returns:
+//   - The new version of the credentials.
+//   - sql.ErrNoRows if the owner has no such credentials, ErrVersionConflict if the version is stale,
+//     ErrSharedItemChanged if sharedData is empty for the shared credentials or isn't empty for the credentials
+//     that aren't shared, or an error if the operation fails.
+func (p *PostgresStorage) UpdateUserCredentials(ctx context.Context, cred *model.Credentials, sharedData string) (int64, error) {
+	owner, ownerID := ownerOf(cred.UserID, cred.CollectionID)
+	return p.updateItemSet(
+		ctx, "user_credentials", owner, ownerID, cred.ID, cred.Version, sharedData,
+		"password_changed_at = CASE WHEN password IS DISTINCT FROM $5 THEN CURRENT_TIMESTAMP ELSE password_changed_at END, "+
+			"login = $4, password = $5, description = $6, ",
+		cred.Login, cred.Password, cred.Description)
+}
+
+// RemoveUserCredential deletes a user credential from the database by its ID.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the credential owner.
+//   - id: An int64 representing the unique identifier of the credential to delete.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such credential.
+func (p *PostgresStorage) RemoveUserCredential(ctx context.Context, userID, id int64) error {
+	return p.removeItem(ctx, "user_credentials", ownerUser, userID, id)
+}
+
+// AddNote adds a new note to the database.
+// The note belongs to the organization collection if its CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - note: A pointer to a model.Note instance containing the note information to add.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddNote(ctx context.Context, note *model.Note) error {
+	_, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO notes (text, description, user_id, collection_id) VALUES ($1, $2, NULLIF($3::BIGINT, 0), NULLIF($4::BIGINT, 0))",
+		note.Text, note.Description, note.UserID, note.CollectionID)
 	return err
 }
 
@@ -352,7 +996,15 @@ func (p *PostgresStorage) AddNote(ctx context.Context, note *model.Note) error {
 //   - A slice of pointers to model.Note instances containing the user's notes.
 //   - An error if the operation fails.
 func (p *PostgresStorage) GetNotes(ctx context.Context, userID int64) ([]*model.Note, error) {
-	rows, err := p.Conn.QueryContext(ctx, "SELECT id, user_id, text, description FROM notes WHERE user_id = $1", userID)
+	return p.getNotes(ctx, ownerUser, userID)
+}
+
+func (p *PostgresStorage) getNotes(ctx context.Context, owner itemOwner, ownerID int64) ([]*model.Note, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), text, description, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM notes WHERE "+string(owner)+" = $1",
+		ownerID)
 	if err != nil {
 		return nil, err
 	}
@@ -366,7 +1018,7 @@ func (p *PostgresStorage) GetNotes(ctx context.Context, userID int64) ([]*model.
 	var notes []*model.Note
 	for rows.Next() {
 		var n model.Note
-		if err = rows.Scan(&n.ID, &n.UserID, &n.Text, &n.Description); err != nil {
+		if err = rows.Scan(&n.ID, &n.UserID, &n.CollectionID, &n.Text, &n.Description, &n.Version, &n.UpdatedAt); err != nil {
 			return nil, err
 		}
 		notes = append(notes, &n)
@@ -382,39 +1034,67 @@ func (p *PostgresStorage) GetNotes(ctx context.Context, userID int64) ([]*model.
 //
 // Parameters:
 //   - ctx: The context for the operation
+//   - userID: An int64 representing the unique identifier of the note owner.
 //   - id: An int64 representing the unique identifier of the note to retrieve.
 //
 // Returns:
 //   - A pointer to a model.Note instance containing the note information.
-//   - An error if the operation fails or if the note is not found.
-func (p *PostgresStorage) GetNote(ctx context.Context, id int64) (*model.Note, error) {
-	row := p.Conn.QueryRowContext(ctx, "SELECT id, user_id, text, description FROM notes WHERE id = $1", id)
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such note.
+func (p *PostgresStorage) GetNote(ctx context.Context, userID, id int64) (*model.Note, error) {
+	return p.getNote(ctx, ownerUser, userID, id)
+}
+
+func (p *PostgresStorage) getNote(ctx context.Context, owner itemOwner, ownerID, id int64) (*model.Note, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), text, description, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM notes WHERE id = $1 AND "+string(owner)+" = $2",
+		id, ownerID)
 
 	var note model.Note
-	if err := row.Scan(&note.ID, &note.UserID, &note.Text, &note.Description); err != nil {
+	if err := row.Scan(&note.ID, &note.UserID, &note.CollectionID, &note.Text, &note.Description, &note.Version, &note.UpdatedAt); err != nil {
 		return nil, err
 	}
 
 	return &note, nil
 }
 
+// UpdateNote updates a note if its version matches, the note belongs to the organization collection
+// if its CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - note: A pointer to a model.Note instance containing the new note information and the version it is based on.
+//   - sharedData: The note encrypted with the data key of its shared copy if the note is shared, otherwise an empty string.
+//
+// Returns:
+//   - The new version of the note.
+//   - sql.ErrNoRows if the owner has no such note, ErrVersionConflict if the version is stale,
+//     ErrSharedItemChanged if sharedData is empty for a shared note or isn't empty for a note that isn't shared,
+//     or an error if the operation fails.
+func (p *PostgresStorage) UpdateNote(ctx context.Context, note *model.Note, sharedData string) (int64, error) {
+	owner, ownerID := ownerOf(note.UserID, note.CollectionID)
+	return p.updateItem(
+		ctx, "notes", owner, ownerID, note.ID, note.Version, sharedData,
+		[]string{"text", "description"},
+		note.Text, note.Description)
+}
+
 // RemoveNote deletes a note from the database by its ID.
 //
 // Parameters:
 //   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the note owner.
 //   - id: An int64 representing the unique identifier of the note to delete.
 //
 // Returns:
-//   - An error if the operation fails.
-func (p *PostgresStorage) RemoveNote(ctx context.Context, id int64) error {
-	_, err := p.Conn.ExecContext(ctx, "DELETE FROM notes WHERE id = $1", id)
-	if err != nil {
-		return err
-	}
-	return nil
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such note.
+func (p *PostgresStorage) RemoveNote(ctx context.Context, userID, id int64) error {
+	return p.removeItem(ctx, "notes", ownerUser, userID, id)
 }
 
 // AddCard adds a new bank card to the database.
+// The card belongs to the organization collection if its CollectionID is set, otherwise to the user.
 //
 // Parameters:
 //   - ctx: The context for the operation.
@@ -425,8 +1105,9 @@ func (p *PostgresStorage) RemoveNote(ctx context.Context, id int64) error {
 func (p *PostgresStorage) AddCard(ctx context.Context, card *model.BankCard) error {
 	_, err := p.Conn.ExecContext(
 		ctx,
-		"INSERT INTO bank_cards (user_id, card_number, expiration_date, cvv, owner, description) "+
-			"VALUES ($1, $2, $3, $4, $5, $6)", card.UserID, card.Number, card.ExpireDate, card.CVV, card.Owner, card.Description)
+		"INSERT INTO bank_cards (user_id, collection_id, card_number, expiration_date, cvv, owner, description) "+
+			"VALUES (NULLIF($1::BIGINT, 0), NULLIF($2::BIGINT, 0), $3, $4, $5, $6, $7)",
+		card.UserID, card.CollectionID, card.Number, card.ExpireDate, card.CVV, card.Owner, card.Description)
 	return err
 }
 
@@ -440,7 +1121,15 @@ func (p *PostgresStorage) AddCard(ctx context.Context, card *model.BankCard) err
 //   - A slice of pointers to model.BankCard instances containing the user's bank cards.
 //   - An error if the operation fails.
 func (p *PostgresStorage) GetBankCards(ctx context.Context, userID int64) ([]*model.BankCard, error) {
-	rows, err := p.Conn.QueryContext(ctx, "SELECT id, user_id, owner, card_number, expiration_date, cvv, description FROM bank_cards WHERE user_id = $1", userID)
+	return p.getBankCards(ctx, ownerUser, userID)
+}
+
+func (p *PostgresStorage) getBankCards(ctx context.Context, owner itemOwner, ownerID int64) ([]*model.BankCard, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), owner, card_number, expiration_date, cvv, description, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM bank_cards WHERE "+string(owner)+" = $1",
+		ownerID)
 	if err != nil {
 		return nil, err
 	}
@@ -454,7 +1143,7 @@ func (p *PostgresStorage) GetBankCards(ctx context.Context, userID int64) ([]*mo
 	var cards []*model.BankCard
 	for rows.Next() {
 		var b model.BankCard
-		if err = rows.Scan(&b.ID, &b.UserID, &b.Owner, &b.Number, &b.ExpireDate, &b.CVV, &b.Description); err != nil {
+		if err = rows.Scan(&b.ID, &b.UserID, &b.CollectionID, &b.Owner, &b.Number, &b.ExpireDate, &b.CVV, &b.Description, &b.Version, &b.UpdatedAt); err != nil {
 			return nil, err
 		}
 		cards = append(cards, &b)
@@ -470,34 +1159,620 @@ func (p *PostgresStorage) GetBankCards(ctx context.Context, userID int64) ([]*mo
 //
 // Parameters:
 //   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the bank card owner.
 //   - id: An int64 representing the unique identifier of the bank card to retrieve.
 //
 // Returns:
 //   - A pointer to a model.BankCard instance containing the bank card information.
-//   - An error if the operation fails or if the bank card is not found.
-func (p *PostgresStorage) GetBankCard(ctx context.Context, id int64) (*model.BankCard, error) {
-	row := p.Conn.QueryRowContext(ctx, "SELECT id, user_id, owner, card_number, expiration_date, cvv, description FROM bank_cards WHERE id = $1", id)
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such bank card.
+func (p *PostgresStorage) GetBankCard(ctx context.Context, userID, id int64) (*model.BankCard, error) {
+	return p.getBankCard(ctx, ownerUser, userID, id)
+}
+
+func (p *PostgresStorage) getBankCard(ctx context.Context, owner itemOwner, ownerID, id int64) (*model.BankCard, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT id, COALESCE(user_id, 0), COALESCE(collection_id, 0), owner, card_number, expiration_date, cvv, description, version, COALESCE(updated_at, CURRENT_TIMESTAMP) "+
+			"FROM bank_cards WHERE id = $1 AND "+string(owner)+" = $2",
+		id, ownerID)
 
 	var card model.BankCard
-	if err := row.Scan(&card.ID, &card.UserID, &card.Owner, &card.Number, &card.ExpireDate, &card.CVV, &card.Description); err != nil {
+	if err := row.Scan(&card.ID, &card.UserID, &card.CollectionID, &card.Owner, &card.Number, &card.ExpireDate, &card.CVV, &card.Description, &card.Version, &card.UpdatedAt); err != nil {
 		return nil, err
 	}
 
 	return &card, nil
 }
 
+// UpdateBankCard updates a bank card if its version matches, the bank card belongs to the organization collection
+// if its CollectionID is set, otherwise to the user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - card: A pointer to a model.BankCard instance containing the new bank card information and the version it is based on.
+//   - sharedData: The bank card encrypted with the data key of its shared copy if the card is shared, otherwise an empty string.
+//
+// Returns:
+//   - The new version of the bank card.
+//   - sql.ErrNoRows if the owner has no such bank card, ErrVersionConflict if the version is stale,
+//     ErrSharedItemChanged if sharedData is empty for a shared card or isn't empty for a card that isn't shared,
+//     or an error if the operation fails.
+func (p *PostgresStorage) UpdateBankCard(ctx context.Context, card *model.BankCard, sharedData string) (int64, error) {
+	owner, ownerID := ownerOf(card.UserID, card.CollectionID)
+	return p.updateItem(
+		ctx, "bank_cards", owner, ownerID, card.ID, card.Version, sharedData,
+		[]string{"card_number", "expiration_date", "cvv", "owner", "description"},
+		card.Number, card.ExpireDate, card.CVV, card.Owner, card.Description)
+}
+
 // RemoveBankCard deletes a bank card from the database by its ID.
 //
 // Parameters:
 //   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the bank card owner.
 //   - id: An int64 representing the unique identifier of the bank card to delete.
 //
 // Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such bank card.
+func (p *PostgresStorage) RemoveBankCard(ctx context.Context, userID, id int64) error {
+	return p.removeItem(ctx, "bank_cards", ownerUser, userID, id)
+}
+
+// SetUserTOTP stores a new, unconfirmed TOTP secret for a user, replacing any previous unconfirmed one.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - secret: A string containing the encrypted TOTP secret.
+//
+// Returns:
 //   - An error if the operation fails.
-func (p *PostgresStorage) RemoveBankCard(ctx context.Context, id int64) error {
-	_, err := p.Conn.ExecContext(ctx, "DELETE FROM bank_cards WHERE id = $1", id)
-	if err != nil {
-		return err
+func (p *PostgresStorage) SetUserTOTP(ctx context.Context, userID int64, secret string) error {
+	_, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO user_totp (user_id, secret, confirmed, last_used_step) VALUES ($1, $2, FALSE, 0) "+
+			"ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, confirmed = FALSE, last_used_step = 0, "+
+			"created_at = CURRENT_TIMESTAMP",
+		userID, secret)
+	return err
+}
+
+// GetUserTOTP retrieves the TOTP settings of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A pointer to a model.TOTP instance containing the TOTP settings.
+//   - An error if the operation fails or if the user has no TOTP secret (sql.ErrNoRows).
+func (p *PostgresStorage) GetUserTOTP(ctx context.Context, userID int64) (*model.TOTP, error) {
+	row := p.Conn.QueryRowContext(ctx, "SELECT user_id, secret, confirmed, last_used_step FROM user_totp WHERE user_id = $1", userID)
+
+	var t model.TOTP
+	if err := row.Scan(&t.UserID, &t.Secret, &t.Confirmed, &t.LastUsedStep); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ConfirmUserTOTP marks the TOTP secret of a user as confirmed and stores a fresh set of recovery codes
+// in a single transaction.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - step: An int64 representing the time step of the code used for confirmation.
+//   - codes: A slice of encrypted recovery codes.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) ConfirmUserTOTP(ctx context.Context, userID, step int64, codes []string) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "UPDATE user_totp SET confirmed = TRUE, last_used_step = $1 WHERE user_id = $2", step, userID); err != nil {
+		return err
+	}
+	if err = replaceRecoveryCodes(ctx, tx, userID, codes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateUserTOTPStep stores the time step of the last accepted TOTP code of a user. The step is only
+// updated if it is newer than the stored one, so that the same code can't be accepted twice.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - step: An int64 representing the time step of the accepted code.
+//
+// Returns:
+//   - true if the step was updated, false if the code has already been used.
+//   - An error if the operation fails.
+func (p *PostgresStorage) UpdateUserTOTPStep(ctx context.Context, userID, step int64) (bool, error) {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"UPDATE user_totp SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1", step, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RemoveUserTOTP deletes the TOTP secret and all recovery codes of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) RemoveUserTOTP(ctx context.Context, userID int64) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetRecoveryCodes retrieves all unused recovery codes of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.RecoveryCode instances.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetRecoveryCodes(ctx context.Context, userID int64) ([]*model.RecoveryCode, error) {
+	rows, err := p.Conn.QueryContext(ctx, "SELECT id, user_id, code FROM user_recovery_codes WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var codes []*model.RecoveryCode
+	for rows.Next() {
+		var c model.RecoveryCode
+		if err = rows.Scan(&c.ID, &c.UserID, &c.Code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// RemoveRecoveryCode deletes a used recovery code.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - id: An int64 representing the unique identifier of the recovery code.
+//
+// Returns:
+//   - true if the code was deleted, false if it has already been used concurrently.
+//   - An error if the operation fails.
+func (p *PostgresStorage) RemoveRecoveryCode(ctx context.Context, id int64) (bool, error) {
+	res, err := p.Conn.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ReplaceRecoveryCodes deletes all recovery codes of a user and stores a new set in a single transaction.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - codes: A slice of encrypted recovery codes.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) ReplaceRecoveryCodes(ctx context.Context, userID int64, codes []string) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, codes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int64, codes []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	for _, code := range codes {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO user_recovery_codes (user_id, code) VALUES ($1, $2)", userID, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddSession creates a new login session of a user together with its first refresh token
+// in a single transaction.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - session: A pointer to a model.Session instance containing the owner and the device information.
+//   - tokenHash: A string containing the hash of the refresh token.
+//   - ttl: A time.Duration after which the session can't be refreshed unless the token is rotated.
+//
+// Returns:
+//   - An int64 representing the unique identifier of the new session.
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddSession(ctx context.Context, session *model.Session, tokenHash string, ttl time.Duration) (int64, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var sessionID int64
+	row := tx.QueryRowContext(
+		ctx,
+		"INSERT INTO sessions (user_id, device_name, client_version, peer_address, expires_at) "+
+			"VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP + $5::interval) RETURNING id",
+		session.UserID, session.DeviceName, session.ClientVersion, session.PeerAddress, interval(ttl))
+	if err = row.Scan(&sessionID); err != nil {
+		return 0, err
+	}
+	if _, err = tx.ExecContext(ctx, "INSERT INTO refresh_tokens (session_id, token_hash) VALUES ($1, $2)", sessionID, tokenHash); err != nil {
+		return 0, err
+	}
+	return sessionID, tx.Commit()
+}
+
+// TouchSession checks whether a session exists and is neither revoked nor expired, and if so
+// records the current time as its last-seen time.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - sessionID: An int64 representing the unique identifier of the session.
+//
+// Returns:
+//   - true if the session is active.
+//   - An error if the operation fails.
+func (p *PostgresStorage) TouchSession(ctx context.Context, sessionID int64) (bool, error) {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"UPDATE sessions SET last_seen_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP",
+		sessionID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetSessions retrieves all active sessions of a user, most recently used first.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.Session instances.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetSessions(ctx context.Context, userID int64) ([]*model.Session, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT id, user_id, device_name, client_version, peer_address, created_at, last_seen_at, expires_at FROM sessions "+
+			"WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP ORDER BY last_seen_at DESC, id DESC",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var sessions []*model.Session
+	for rows.Next() {
+		var s model.Session
+		if err = rows.Scan(&s.ID, &s.UserID, &s.DeviceName, &s.ClientVersion, &s.PeerAddress,
+			&s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one in a single transaction.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - tokenHash: A string containing the hash of the presented refresh token.
+//   - newTokenHash: A string containing the hash of the refresh token that replaces it.
+//   - peerAddress: A string containing the network address the refresh request came from.
+//   - ttl: A time.Duration representing the new lifetime of the session, counted from now.
+//
+// Returns:
+//   - A pointer to a model.Session instance the token belongs to.
+//   - sql.ErrNoRows if the token is unknown, ErrSessionRevoked if the session is revoked or expired,
+//     ErrRefreshTokenReused if the token has already been rotated, or another error if the operation fails.
+//
+// Every refresh token can be used only once. Presenting an already used token means that it has been
+// stolen (either the attacker or the legitimate client already used it), so the whole session with
+// all its refresh tokens is revoked.
+func (p *PostgresStorage) RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash, peerAddress string, ttl time.Duration) (*model.Session, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var sessionID int64
+	row := tx.QueryRowContext(
+		ctx,
+		"UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_hash = $1 AND used_at IS NULL RETURNING session_id",
+		tokenHash)
+	err = row.Scan(&sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		row = tx.QueryRowContext(ctx, "SELECT session_id FROM refresh_tokens WHERE token_hash = $1", tokenHash)
+		if err = row.Scan(&sessionID); err != nil {
+			return nil, err
+		}
+		if _, err = tx.ExecContext(
+			ctx,
+			"UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL",
+			sessionID); err != nil {
+			return nil, err
+		}
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s model.Session
+	var active bool
+	row = tx.QueryRowContext(
+		ctx,
+		"SELECT id, user_id, created_at, revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP FROM sessions WHERE id = $1",
+		sessionID)
+	if err = row.Scan(&s.ID, &s.UserID, &s.CreatedAt, &active); err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrSessionRevoked
+	}
+
+	if _, err = tx.ExecContext(ctx, "INSERT INTO refresh_tokens (session_id, token_hash) VALUES ($1, $2)", sessionID, newTokenHash); err != nil {
+		return nil, err
+	}
+	row = tx.QueryRowContext(
+		ctx,
+		"UPDATE sessions SET expires_at = CURRENT_TIMESTAMP + $1::interval, last_seen_at = CURRENT_TIMESTAMP, "+
+			"peer_address = $2 WHERE id = $3 RETURNING expires_at, last_seen_at",
+		interval(ttl), peerAddress, sessionID)
+	if err = row.Scan(&s.ExpiresAt, &s.LastSeenAt); err != nil {
+		return nil, err
+	}
+	return &s, tx.Commit()
+}
+
+// RevokeSession revokes a session of a user, so that neither its access tokens nor its refresh tokens
+// are accepted anymore.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the session owner.
+//   - sessionID: An int64 representing the unique identifier of the session.
+//
+// Returns:
+//   - true if the session was revoked, false if it doesn't exist, belongs to another user or is already revoked.
+//   - An error if the operation fails.
+func (p *PostgresStorage) RevokeSession(ctx context.Context, userID, sessionID int64) (bool, error) {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+		sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetAuthFailures retrieves the number of failed authentication attempts for a key.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - key: A string identifying the login or the address the attempts were made for.
+//
+// Returns:
+//   - The number of failed attempts, or 0 if there are none.
+//   - The time elapsed since the last failed attempt.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetAuthFailures(ctx context.Context, key string) (int, time.Duration, error) {
+	var failures int
+	var elapsed float64
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT failures, EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - last_failure) FROM auth_failures WHERE key = $1",
+		key)
+	err := row.Scan(&failures, &elapsed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return failures, time.Duration(elapsed * float64(time.Second)), nil
+}
+
+// AddAuthFailure records a failed authentication attempt for a key.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - key: A string identifying the login or the address the attempt was made for.
+//   - window: The duration after which the previous failed attempts are forgotten.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddAuthFailure(ctx context.Context, key string, window time.Duration) error {
+	_, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO auth_failures (key, failures) VALUES ($1, 1) ON CONFLICT (key) DO UPDATE SET "+
+			"failures = CASE WHEN auth_failures.last_failure < CURRENT_TIMESTAMP - $2::interval THEN 1 "+
+			"ELSE auth_failures.failures + 1 END, last_failure = CURRENT_TIMESTAMP",
+		key, interval(window))
+	return err
+}
+
+// ResetAuthFailures removes the failed authentication attempts for a key.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - key: A string identifying the login or the address the attempts were made for.
+//
+// Returns:
+//   - An error if the operation fails.
+func (p *PostgresStorage) ResetAuthFailures(ctx context.Context, key string) error {
+	_, err := p.Conn.ExecContext(ctx, "DELETE FROM auth_failures WHERE key = $1", key)
+	return err
+}
+
+// interval formats a duration as a PostgreSQL interval literal. Expiration moments are computed by the
+// database itself, so that they are consistent with CURRENT_TIMESTAMP regardless of the time zones.
+func interval(d time.Duration) string {
+	return fmt.Sprintf("%d seconds", int64(d.Seconds()))
+}
+
+// removeItem deletes an item of the owner by its ID from the table, it returns sql.ErrNoRows if the owner has no such item.
+func (p *PostgresStorage) removeItem(ctx context.Context, table string, owner itemOwner, ownerID, id int64) error {
+	res, err := p.Conn.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = $1 AND "+string(owner)+" = $2", id, ownerID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// updateItem sets the columns of an item of the owner to the values if the item has the expected version,
+// increases the version and returns the new one. It returns sql.ErrNoRows if the owner has no such item
+// and ErrVersionConflict if the version of the item differs.
+//
+// The shared copy of a personal item that can be shared is replaced with sharedData in the same transaction.
+// ErrSharedItemChanged is returned if the item is shared and sharedData is empty, or if sharedData isn't empty
+// and the item isn't shared, because the client has checked the sharing of the item before it changed.
+func (p *PostgresStorage) updateItem(ctx context.Context, table string, owner itemOwner, ownerID, id, version int64, sharedData string, columns []string, values ...any) (int64, error) {
+	set := ""
+	for i, column := range columns {
+		set += fmt.Sprintf("%s = $%d, ", column, i+4)
+	}
+	return p.updateItemSet(ctx, table, owner, ownerID, id, version, sharedData, set, values...)
+}
+
+// updateItemSet is updateItem with the assignments of the columns given as the SET clause, which refers to
+// the values as $4, $5 and so on and ends with a comma.
+func (p *PostgresStorage) updateItemSet(ctx context.Context, table string, owner itemOwner, ownerID, id, version int64, sharedData, set string, values ...any) (int64, error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var newVersion int64
+	err = tx.QueryRowContext(
+		ctx,
+		"UPDATE "+table+" SET "+set+"version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $1 AND "+string(owner)+" = $2 AND version = $3 RETURNING version",
+		append([]any{id, ownerID, version}, values...)...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1 AND "+string(owner)+" = $2)",
+			id, ownerID).Scan(&exists)
+		if err != nil {
+			return 0, err
+		}
+		if exists {
+			return 0, ErrVersionConflict
+		}
+		return 0, sql.ErrNoRows
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if itemType, ok := sharedItemType(table); ok && owner == ownerUser {
+		if err = updateSharedCopy(ctx, tx, ownerID, itemType, id, sharedData); err != nil {
+			return 0, err
+		}
+	}
+	return newVersion, tx.Commit()
+}
+
+// ownerOf returns the owner column and the owner ID of an item belonging either to a user or to a collection.
+func ownerOf(userID, collectionID int64) (itemOwner, int64) {
+	if collectionID != 0 {
+		return ownerCollection, collectionID
+	}
+	return ownerUser, userID
+}
+
+// checkRowsAffected returns sql.ErrNoRows if the statement hasn't affected any rows.
+func checkRowsAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
 	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -128,15 +129,20 @@ func TestPostgresStorage_AddFile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.wantErr {
-				err := db.AddFile(context.Background(), "bucketName", "fileName", "description", 1, 12)
+				_, err := db.AddFile(context.Background(), "bucketName", "fileName", "1/fileName", "description", 1, 12)
 				assert.Error(t, err)
 			} else {
 				err := db.AddUser(context.Background(), "login", "password")
 				require.NoError(t, err)
-				err = db.AddFile(context.Background(), "bucketName", "fileName", "description", 1, 12)
+				previousKey, err := db.AddFile(context.Background(), "bucketName", "fileName", "1/first", "description", 1, 12)
 				assert.NoError(t, err)
-				err = db.AddFile(context.Background(), "bucketName", "fileName", "description", 1, 12)
+				assert.Empty(t, previousKey)
+				previousKey, err = db.AddFile(context.Background(), "bucketName", "fileName", "1/second", "description", 1, 12)
 				assert.NoError(t, err)
+				assert.Equal(t, "1/first", previousKey)
+				file, err := db.GetFile(context.Background(), 1, "fileName")
+				require.NoError(t, err)
+				assert.Equal(t, "1/second", file.ObjectKey)
 			}
 		})
 	}
@@ -165,15 +171,16 @@ func TestPostgresStorage_GetFile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.wantErr {
-				err = db.AddFile(context.Background(), "bucketName", "fileName", "description", 1, 12)
-				_, err = db.GetFile(context.Background(), "badName")
+				_, err = db.AddFile(context.Background(), "bucketName", "fileName", "1/fileName", "description", 1, 12)
+				_, err = db.GetFile(context.Background(), 1, "badName")
 				assert.Error(t, err)
 			} else {
-				err = db.AddFile(context.Background(), "bucketName", "goodFileName", "description", 1, 12)
+				_, err = db.AddFile(context.Background(), "bucketName", "goodFileName", "1/goodFileName", "description", 1, 12)
 				assert.NoError(t, err)
-				file, err := db.GetFile(context.Background(), "goodFileName")
+				file, err := db.GetFile(context.Background(), 1, "goodFileName")
 				assert.NoError(t, err)
 				assert.Equal(t, "goodFileName", file.FileName)
+				assert.Equal(t, "1/goodFileName", file.ObjectKey)
 				assert.Equal(t, "bucketName", file.BucketName)
 				assert.Equal(t, "description", file.Description)
 				assert.Equal(t, int64(12), file.FileSize)
@@ -209,7 +216,7 @@ func TestPostgresStorage_GetFiles(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Empty(t, creds)
 			} else {
-				err = db.AddFile(context.Background(), "bucketName", "goodFileName", "description", 1, 12)
+				_, err = db.AddFile(context.Background(), "bucketName", "goodFileName", "1/goodFileName", "description", 1, 12)
 				assert.NoError(t, err)
 				file, err := db.GetFiles(context.Background(), 1)
 				assert.NoError(t, err)
@@ -238,16 +245,114 @@ func TestPostgresStorage_RemoveFile(t *testing.T) {
 	require.NoError(t, err)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err = db.AddFile(context.Background(), "bucketName", "goodFileName", "description", 1, 12)
+			_, err = db.AddFile(context.Background(), "bucketName", "goodFileName", "1/goodFileName", "description", 1, 12)
 			assert.NoError(t, err)
-			err = db.RemoveFile(context.Background(), "goodFileName")
+			err = db.RemoveFile(context.Background(), 2, "goodFileName")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			_, err = db.GetFile(context.Background(), 2, "goodFileName")
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			err = db.RemoveFile(context.Background(), 1, "goodFileName")
 			assert.NoError(t, err)
-			_, err = db.GetFile(context.Background(), "goodFileName")
+			_, err = db.GetFile(context.Background(), 1, "goodFileName")
 			assert.Equal(t, "sql: no rows in result set", err.Error())
 		})
 	}
 }
 
+func TestPostgresStorage_UpdateFile(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	err := db.AddUser(ctx, "login", "password")
+	require.NoError(t, err)
+	_, err = db.AddFile(ctx, "bucketName", "fileName", "1/fileName", "description", 1, 12)
+	require.NoError(t, err)
+	_, err = db.AddFile(ctx, "bucketName", "other", "1/other", "description", 1, 12)
+	require.NoError(t, err)
+	// The files uploaded before the object keys were introduced are stored under their names.
+	_, err = db.Conn.ExecContext(
+		ctx,
+		"INSERT INTO files (user_id, bucket_name, file_name, file_size, description) VALUES ($1, $2, $3, $4, $5)",
+		1, "bucketName", "legacy", 12, "description")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		file          *model.File
+		newFileName   string
+		wantErr       error
+		wantVersion   int64
+		wantObjectKey string
+	}{
+		{
+			name:    "test update unknown file",
+			file:    &model.File{UserID: 1, FileName: "badName", Version: 1},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:    "test update file of another user",
+			file:    &model.File{UserID: 2, FileName: "fileName", Version: 1},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:    "test update file stale version",
+			file:    &model.File{UserID: 1, FileName: "fileName", Version: 2},
+			wantErr: ErrVersionConflict,
+		},
+		{
+			name:        "test rename file to existing name",
+			file:        &model.File{UserID: 1, FileName: "fileName", Version: 1},
+			newFileName: "other",
+			wantErr:     ErrFileExists,
+		},
+		{
+			name:          "test update file description",
+			file:          &model.File{UserID: 1, FileName: "fileName", Description: "new description", Version: 1},
+			wantVersion:   2,
+			wantObjectKey: "1/fileName",
+		},
+		{
+			name:          "test rename file",
+			file:          &model.File{UserID: 1, FileName: "fileName", Description: "new description", Version: 2},
+			newFileName:   "renamed",
+			wantVersion:   3,
+			wantObjectKey: "1/fileName",
+		},
+		{
+			name:          "test rename legacy file",
+			file:          &model.File{UserID: 1, FileName: "legacy", Description: "new description", Version: 1},
+			newFileName:   "renamedLegacy",
+			wantVersion:   2,
+			wantObjectKey: "legacy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := db.UpdateFile(ctx, tt.file, tt.newFileName)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, version)
+
+			name := tt.file.FileName
+			if tt.newFileName != "" {
+				name = tt.newFileName
+				_, err = db.GetFile(ctx, 1, tt.file.FileName)
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			}
+			file, err := db.GetFile(ctx, 1, name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantObjectKey, file.ObjectKey)
+			assert.Equal(t, tt.file.Description, file.Description)
+			assert.Equal(t, tt.wantVersion, file.Version)
+		})
+	}
+}
+
 func TestPostgresStorage_GetUser(t *testing.T) {
 	db, dbName := setupTestDB(t)
 	defer teardownTestDB(t, db.Conn, dbName)
@@ -284,6 +389,23 @@ func TestPostgresStorage_GetUser(t *testing.T) {
 	}
 }
 
+func TestPostgresStorage_UpdateUserPassword(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	err := db.AddUser(context.Background(), "login", "password")
+	require.NoError(t, err)
+
+	t.Run("test update user password ok", func(t *testing.T) {
+		err = db.UpdateUserPassword(context.Background(), 1, "newPassword")
+		assert.NoError(t, err)
+
+		user, err := db.GetUser(context.Background(), "login")
+		assert.NoError(t, err)
+		assert.Equal(t, "newPassword", user.Password)
+	})
+}
+
 func TestPostgresStorage_AddUserCredentials(t *testing.T) {
 	db, dbName := setupTestDB(t)
 	defer teardownTestDB(t, db.Conn, dbName)
@@ -357,10 +479,10 @@ func TestPostgresStorage_GetUserCredential(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.wantErr {
-				_, err = db.GetUserCredential(context.Background(), 2)
+				_, err = db.GetUserCredential(context.Background(), 1, 2)
 				assert.Error(t, err)
 			} else {
-				cred, err := db.GetUserCredential(context.Background(), 1)
+				cred, err := db.GetUserCredential(context.Background(), 1, 1)
 				assert.NoError(t, err)
 				assert.Equal(t, "login", cred.Login)
 				assert.Equal(t, "password", cred.Password)
@@ -439,9 +561,13 @@ func TestPostgresStorage_RemoveUserCredential(t *testing.T) {
 				UserID:      1,
 			})
 			assert.NoError(t, err)
-			err = db.RemoveUserCredential(context.Background(), 1)
+			err = db.RemoveUserCredential(context.Background(), 2, 1)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			_, err = db.GetUserCredential(context.Background(), 2, 1)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			err = db.RemoveUserCredential(context.Background(), 1, 1)
 			assert.NoError(t, err)
-			_, err = db.GetUserCredential(context.Background(), 1)
+			_, err = db.GetUserCredential(context.Background(), 1, 1)
 			assert.Equal(t, "sql: no rows in result set", err.Error())
 		})
 	}
@@ -560,10 +686,10 @@ func TestPostgresStorage_GetNote(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.wantErr {
-				_, err = db.GetNote(context.Background(), 2)
+				_, err = db.GetNote(context.Background(), 1, 2)
 				assert.Error(t, err)
 			} else {
-				note, err := db.GetNote(context.Background(), 1)
+				note, err := db.GetNote(context.Background(), 1, 1)
 				assert.NoError(t, err)
 				assert.Equal(t, "test", note.Text)
 				assert.Equal(t, "description", note.Description)
@@ -595,9 +721,13 @@ func TestPostgresStorage_RemoveNote(t *testing.T) {
 				UserID:      1,
 			})
 			assert.NoError(t, err)
-			err = db.RemoveNote(context.Background(), 1)
+			err = db.RemoveNote(context.Background(), 2, 1)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			_, err = db.GetNote(context.Background(), 2, 1)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			err = db.RemoveNote(context.Background(), 1, 1)
 			assert.NoError(t, err)
-			_, err = db.GetNote(context.Background(), 1)
+			_, err = db.GetNote(context.Background(), 1, 1)
 			assert.Equal(t, "sql: no rows in result set", err.Error())
 		})
 	}
@@ -731,10 +861,10 @@ func TestPostgresStorage_GetBankCard(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.wantErr {
-				_, err = db.GetBankCard(context.Background(), 2)
+				_, err = db.GetBankCard(context.Background(), 1, 2)
 				assert.Error(t, err)
 			} else {
-				card, err := db.GetBankCard(context.Background(), 1)
+				card, err := db.GetBankCard(context.Background(), 1, 1)
 				assert.NoError(t, err)
 				assert.Equal(t, "12.02.2024", card.ExpireDate)
 				assert.Equal(t, "owner", card.Owner)
@@ -772,10 +902,408 @@ func TestPostgresStorage_RemoveBankCard(t *testing.T) {
 				UserID:      1,
 			})
 			assert.NoError(t, err)
-			err = db.RemoveBankCard(context.Background(), 1)
+			err = db.RemoveBankCard(context.Background(), 2, 1)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			_, err = db.GetBankCard(context.Background(), 2, 1)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+			err = db.RemoveBankCard(context.Background(), 1, 1)
 			assert.NoError(t, err)
-			_, err = db.GetBankCard(context.Background(), 1)
+			_, err = db.GetBankCard(context.Background(), 1, 1)
 			assert.Equal(t, "sql: no rows in result set", err.Error())
 		})
 	}
 }
+
+func TestPostgresStorage_UpdateItem(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	err := db.AddUser(ctx, "login", "password")
+	require.NoError(t, err)
+	err = db.AddCard(ctx, &model.BankCard{ExpireDate: "exp", Owner: "owner", CVV: "cvv", Number: "number", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+	err = db.AddNote(ctx, &model.Note{Text: "text", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+	err = db.AddUserCredentials(ctx, &model.Credentials{Login: "login", Password: "password", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		update      func() (int64, error)
+		wantErr     error
+		wantVersion int64
+		check       func(t *testing.T)
+	}{
+		{
+			name: "test update unknown item",
+			update: func() (int64, error) {
+				return db.UpdateNote(ctx, &model.Note{ID: 2, UserID: 1, Version: 1, Text: "text2"}, "")
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "test update item of another user",
+			update: func() (int64, error) {
+				return db.UpdateBankCard(ctx, &model.BankCard{ID: 1, UserID: 2, Version: 1, Number: "number2"}, "")
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "test update bank card ok",
+			update: func() (int64, error) {
+				card := &model.BankCard{ID: 1, UserID: 1, Version: 1, ExpireDate: "exp2", Owner: "owner2", CVV: "cvv2", Number: "number2"}
+				return db.UpdateBankCard(ctx, card, "")
+			},
+			wantVersion: 2,
+			check: func(t *testing.T) {
+				card, err := db.GetBankCard(ctx, 1, 1)
+				require.NoError(t, err)
+				assert.Equal(t, "number2", card.Number)
+				assert.Equal(t, "owner2", card.Owner)
+				assert.Empty(t, card.Description)
+				assert.Equal(t, int64(2), card.Version)
+			},
+		},
+		{
+			name: "test update bank card stale version",
+			update: func() (int64, error) {
+				return db.UpdateBankCard(ctx, &model.BankCard{ID: 1, UserID: 1, Version: 1, Number: "number3"}, "")
+			},
+			wantErr: ErrVersionConflict,
+			check: func(t *testing.T) {
+				card, err := db.GetBankCard(ctx, 1, 1)
+				require.NoError(t, err)
+				assert.Equal(t, "number2", card.Number)
+			},
+		},
+		{
+			name: "test update note ok",
+			update: func() (int64, error) {
+				return db.UpdateNote(ctx, &model.Note{ID: 1, UserID: 1, Version: 1, Text: "text2", Description: "desc2"}, "")
+			},
+			wantVersion: 2,
+			check: func(t *testing.T) {
+				note, err := db.GetNote(ctx, 1, 1)
+				require.NoError(t, err)
+				assert.Equal(t, "text2", note.Text)
+				assert.Equal(t, "desc2", note.Description)
+			},
+		},
+		{
+			name: "test update credentials ok",
+			update: func() (int64, error) {
+				cred := &model.Credentials{ID: 1, UserID: 1, Version: 1, Login: "login2", Password: "password2", Description: "desc2"}
+				return db.UpdateUserCredentials(ctx, cred, "")
+			},
+			wantVersion: 2,
+			check: func(t *testing.T) {
+				cred, err := db.GetUserCredential(ctx, 1, 1)
+				require.NoError(t, err)
+				assert.Equal(t, "login2", cred.Login)
+				assert.Equal(t, "password2", cred.Password)
+			},
+		},
+		{
+			name: "test update credentials stale version",
+			update: func() (int64, error) {
+				cred := &model.Credentials{ID: 1, UserID: 1, Version: 1, Login: "login3", Password: "password3"}
+				return db.UpdateUserCredentials(ctx, cred, "")
+			},
+			wantErr: ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := tt.update()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantVersion, version)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+}
+
+func TestPostgresStorage_RotateRefreshToken(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	err := db.AddUser(context.Background(), "login", "password")
+	require.NoError(t, err)
+
+	sessionID, err := db.AddSession(context.Background(), &model.Session{UserID: 1, DeviceName: "laptop"}, "hash1", time.Hour)
+	require.NoError(t, err)
+
+	t.Run("test session is active", func(t *testing.T) {
+		active, err := db.TouchSession(context.Background(), sessionID)
+		require.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("test rotate unknown token", func(t *testing.T) {
+		_, err = db.RotateRefreshToken(context.Background(), "unknown", "hash2", "127.0.0.1:1234", time.Hour)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("test rotate ok", func(t *testing.T) {
+		s, err := db.RotateRefreshToken(context.Background(), "hash1", "hash2", "127.0.0.1:1234", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, sessionID, s.ID)
+		assert.Equal(t, int64(1), s.UserID)
+
+		sessions, err := db.GetSessions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "laptop", sessions[0].DeviceName)
+		assert.Equal(t, "127.0.0.1:1234", sessions[0].PeerAddress)
+	})
+
+	t.Run("test reuse revokes session", func(t *testing.T) {
+		_, err = db.RotateRefreshToken(context.Background(), "hash1", "hash3", "127.0.0.1:1234", time.Hour)
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+		_, err = db.RotateRefreshToken(context.Background(), "hash2", "hash3", "127.0.0.1:1234", time.Hour)
+		assert.ErrorIs(t, err, ErrSessionRevoked)
+
+		active, err := db.TouchSession(context.Background(), sessionID)
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("test revoke session", func(t *testing.T) {
+		id, err := db.AddSession(context.Background(), &model.Session{UserID: 1, DeviceName: "phone"}, "hash4", time.Hour)
+		require.NoError(t, err)
+
+		ok, err := db.RevokeSession(context.Background(), 2, id)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = db.RevokeSession(context.Background(), 1, id)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		active, err := db.TouchSession(context.Background(), id)
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+}
+
+func TestPostgresStorage_AuthFailures(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	failures, _, err := db.GetAuthFailures(ctx, "login:test")
+	require.NoError(t, err)
+	assert.Zero(t, failures)
+
+	require.NoError(t, db.AddAuthFailure(ctx, "login:test", time.Minute))
+	require.NoError(t, db.AddAuthFailure(ctx, "login:test", time.Minute))
+	require.NoError(t, db.AddAuthFailure(ctx, "addr:127.0.0.1", time.Minute))
+
+	failures, elapsed, err := db.GetAuthFailures(ctx, "login:test")
+	require.NoError(t, err)
+	assert.Equal(t, 2, failures)
+	assert.Less(t, elapsed, time.Minute)
+
+	t.Run("test old failures are forgotten", func(t *testing.T) {
+		_, err = db.Conn.ExecContext(ctx, "UPDATE auth_failures SET last_failure = last_failure - interval '2 minutes'")
+		require.NoError(t, err)
+		require.NoError(t, db.AddAuthFailure(ctx, "login:test", time.Minute))
+
+		failures, _, err = db.GetAuthFailures(ctx, "login:test")
+		require.NoError(t, err)
+		assert.Equal(t, 1, failures)
+	})
+
+	t.Run("test reset failures", func(t *testing.T) {
+		require.NoError(t, db.ResetAuthFailures(ctx, "login:test"))
+
+		failures, _, err = db.GetAuthFailures(ctx, "login:test")
+		require.NoError(t, err)
+		assert.Zero(t, failures)
+
+		failures, _, err = db.GetAuthFailures(ctx, "addr:127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, failures)
+	})
+}
+
+func TestPostgresStorage_RekeyVault(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	err := db.AddUser(ctx, "login", "password")
+	require.NoError(t, err)
+	err = db.AddCard(ctx, &model.BankCard{ExpireDate: "exp", Owner: "owner", CVV: "cvv", Number: "number", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+	err = db.AddNote(ctx, &model.Note{Text: "text", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+	err = db.AddUserCredentials(ctx, &model.Credentials{Login: "login", Password: "password", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+	_, err = db.AddFile(ctx, MinioBucketName, "file.txt", "1/old", "desc", 1, 100)
+	require.NoError(t, err)
+	_, err = db.AddFile(ctx, MinioBucketName, "plain.txt", "1/plain", "desc", 1, 10)
+	require.NoError(t, err)
+
+	rekey := func() *model.VaultRekey {
+		return &model.VaultRekey{
+			UserID:      1,
+			Cards:       []*model.BankCard{{ID: 1, ExpireDate: "exp2", Owner: "owner2", CVV: "cvv2", Number: "number2", Description: "desc2"}},
+			Notes:       []*model.Note{{ID: 1, Text: "text2", Description: "desc2"}},
+			Credentials: []*model.Credentials{{ID: 1, Login: "login2", Password: "password2", Description: "desc2"}},
+			Files:       []*model.File{{FileName: "file.txt", ObjectKey: "1/new"}, {FileName: "plain.txt"}},
+			Vault:       &model.Vault{Salt: []byte("0123456789abcdef"), Verifier: "verifier", Memory: 19456, Iterations: 2, Parallelism: 1},
+		}
+	}
+
+	t.Run("test items changed", func(t *testing.T) {
+		r := rekey()
+		r.Notes = nil
+		_, err = db.RekeyVault(ctx, r)
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+
+		r = rekey()
+		r.Files = r.Files[:1]
+		_, err = db.RekeyVault(ctx, r)
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+
+		r = rekey()
+		r.UserID = 2
+		_, err = db.RekeyVault(ctx, r)
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+	})
+
+	t.Run("test staged upload missing", func(t *testing.T) {
+		_, err = db.RekeyVault(ctx, rekey())
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+
+		note, err := db.GetNote(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "text", note.Text)
+		_, err = db.GetUserVault(ctx, 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("test rekey ok", func(t *testing.T) {
+		err = db.AddStagedUpload(ctx, "1/new", "file.txt", 1, 120)
+		require.NoError(t, err)
+
+		replaced, err := db.RekeyVault(ctx, rekey())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1/old"}, replaced)
+
+		card, err := db.GetBankCard(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "number2", card.Number)
+		note, err := db.GetNote(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "text2", note.Text)
+		cred, err := db.GetUserCredential(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "password2", cred.Password)
+
+		file, err := db.GetFile(ctx, 1, "file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "1/new", file.ObjectKey)
+		assert.Equal(t, int64(120), file.FileSize)
+		file, err = db.GetFile(ctx, 1, "plain.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "1/plain", file.ObjectKey)
+
+		vault, err := db.GetUserVault(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "verifier", vault.Verifier)
+	})
+
+	t.Run("test staged upload is consumed", func(t *testing.T) {
+		_, err = db.RekeyVault(ctx, rekey())
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+	})
+
+	t.Run("test file changed since staged", func(t *testing.T) {
+		file, err := db.GetFile(ctx, 1, "file.txt")
+		require.NoError(t, err)
+		err = db.AddStagedUpload(ctx, "1/newer", "file.txt", 1, 130)
+		require.NoError(t, err)
+
+		r := rekey()
+		r.Files[0] = &model.File{FileName: "file.txt", ObjectKey: "1/newer", Version: file.Version - 1}
+		_, err = db.RekeyVault(ctx, r)
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+
+		r.Files[0].Version = file.Version
+		replaced, err := db.RekeyVault(ctx, r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1/new"}, replaced)
+	})
+
+	t.Run("test rekey without vault", func(t *testing.T) {
+		r := rekey()
+		r.Files[0].ObjectKey = ""
+		r.Vault = nil
+		replaced, err := db.RekeyVault(ctx, r)
+		require.NoError(t, err)
+		assert.Empty(t, replaced)
+
+		_, err = db.GetUserVault(ctx, 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestPostgresStorage_PasswordChangedAt(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	err := db.AddUser(ctx, "login", "password")
+	require.NoError(t, err)
+	err = db.AddUserCredentials(ctx, &model.Credentials{Login: "login", Password: "password", Description: "desc", UserID: 1})
+	require.NoError(t, err)
+	_, err = db.Conn.ExecContext(ctx, "UPDATE user_credentials SET password_changed_at = password_changed_at - INTERVAL '400 days'")
+	require.NoError(t, err)
+
+	cred, err := db.GetUserCredential(ctx, 1, 1)
+	require.NoError(t, err)
+	changedAt := cred.PasswordChangedAt
+	assert.True(t, changedAt.Before(cred.UpdatedAt))
+
+	t.Run("test edit of other fields keeps the password age", func(t *testing.T) {
+		_, err = db.UpdateUserCredentials(ctx, &model.Credentials{ID: 1, UserID: 1, Version: 1, Login: "login", Password: "password", Description: "desc2"}, "")
+		require.NoError(t, err)
+
+		cred, err := db.GetUserCredential(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "desc2", cred.Description)
+		assert.Equal(t, changedAt, cred.PasswordChangedAt)
+	})
+
+	t.Run("test rekey keeps the password age", func(t *testing.T) {
+		_, err = db.RekeyVault(ctx, &model.VaultRekey{
+			UserID:      1,
+			Credentials: []*model.Credentials{{ID: 1, Version: 2, Login: "login2", Password: "password2", Description: "desc3"}},
+		})
+		require.NoError(t, err)
+
+		cred, err := db.GetUserCredential(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "password2", cred.Password)
+		assert.Equal(t, changedAt, cred.PasswordChangedAt)
+	})
+
+	t.Run("test password change resets the password age", func(t *testing.T) {
+		_, err = db.UpdateUserCredentials(ctx, &model.Credentials{ID: 1, UserID: 1, Version: 3, Login: "login2", Password: "password3", Description: "desc3"}, "")
+		require.NoError(t, err)
+
+		cred, err := db.GetUserCredential(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.True(t, cred.PasswordChangedAt.After(changedAt))
+	})
+}
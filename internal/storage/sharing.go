@@ -0,0 +1,367 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+var (
+	// ErrSharingKeysExist is returned by AddSharingKeys when the user already has sharing keys.
+	ErrSharingKeysExist = errors.New("sharing keys already exist")
+	// ErrSharedItemChanged is returned when the shared copy of an item doesn't match the state the client
+	// has prepared the request for, for example, because the item has been shared from another client.
+	ErrSharedItemChanged = errors.New("shared item has changed")
+)
+
+// sharedItemTables maps the types of the items that can be shared to the tables of the items.
+var sharedItemTables = map[string]string{
+	model.ItemTypeCard:        "bank_cards",
+	model.ItemTypeCredentials: "user_credentials",
+	model.ItemTypeNote:        "notes",
+}
+
+// sharedItemType returns the type of the items of the table if they can be shared.
+func sharedItemType(table string) (string, bool) {
+	for itemType, t := range sharedItemTables {
+		if t == table {
+			return itemType, true
+		}
+	}
+	return "", false
+}
+
+// updateSharedCopy replaces the shared copy of a changed item with the data, the item must be locked by the transaction.
+// It returns ErrSharedItemChanged if the item is shared and the data is empty, or if the item isn't shared
+// and the data isn't empty.
+func updateSharedCopy(ctx context.Context, tx *sql.Tx, ownerID int64, itemType string, itemID int64, data string) error {
+	var sharedItemID int64
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT id FROM shared_items WHERE owner_id = $1 AND item_type = $2 AND item_id = $3 FOR UPDATE",
+		ownerID, itemType, itemID).Scan(&sharedItemID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if data != "" {
+			return ErrSharedItemChanged
+		}
+		return nil
+	case err != nil:
+		return err
+	case data == "":
+		return ErrSharedItemChanged
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE shared_items SET data = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", data, sharedItemID)
+	return err
+}
+
+// AddSharingKeys stores the sharing key pair of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - keys: A pointer to a model.SharingKeys instance containing the keys.
+//
+// Returns:
+//   - ErrSharingKeysExist if the user already has sharing keys, which are never replaced, so that
+//     the items shared with the user stay accessible.
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddSharingKeys(ctx context.Context, keys *model.SharingKeys) error {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO user_sharing_keys (user_id, public_key, private_key) VALUES ($1, $2, $3) ON CONFLICT (user_id) DO NOTHING",
+		keys.UserID, keys.PublicKey, keys.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if err = checkRowsAffected(res); errors.Is(err, sql.ErrNoRows) {
+		return ErrSharingKeysExist
+	}
+	return err
+}
+
+// GetSharingKeys retrieves the sharing key pair of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A pointer to a model.SharingKeys instance.
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no sharing keys.
+func (p *PostgresStorage) GetSharingKeys(ctx context.Context, userID int64) (*model.SharingKeys, error) {
+	row := p.Conn.QueryRowContext(ctx, "SELECT user_id, public_key, private_key FROM user_sharing_keys WHERE user_id = $1", userID)
+
+	var keys model.SharingKeys
+	if err := row.Scan(&keys.UserID, &keys.PublicKey, &keys.PrivateKey); err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
+
+// GetPublicSharingKey retrieves the public sharing key of a user by login.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - login: A string representing the login of the user.
+//
+// Returns:
+//   - A pointer to a model.SharingKeys instance without the private key.
+//   - An error if the operation fails, or sql.ErrNoRows if there is no such user or the user has no sharing keys.
+func (p *PostgresStorage) GetPublicSharingKey(ctx context.Context, login string) (*model.SharingKeys, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT k.user_id, k.public_key FROM user_sharing_keys k JOIN users u ON u.id = k.user_id WHERE u.login = $1",
+		login)
+
+	var keys model.SharingKeys
+	if err := row.Scan(&keys.UserID, &keys.PublicKey); err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
+
+// GetSharedItem retrieves the shared copy of an item together with its recipients.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - ownerID: An int64 representing the unique identifier of the owner of the item.
+//   - itemType: A string representing the type of the item.
+//   - itemID: An int64 representing the unique identifier of the item.
+//
+// Returns:
+//   - A pointer to a model.SharedItem instance, whose shares contain the public keys of the recipients.
+//   - An error if the operation fails, or sql.ErrNoRows if the item isn't shared.
+func (p *PostgresStorage) GetSharedItem(ctx context.Context, ownerID int64, itemType string, itemID int64) (*model.SharedItem, error) {
+	row := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT id, owner_id, item_type, item_id, data, owner_key FROM shared_items WHERE owner_id = $1 AND item_type = $2 AND item_id = $3",
+		ownerID, itemType, itemID)
+
+	var item model.SharedItem
+	if err := row.Scan(&item.ID, &item.OwnerID, &item.ItemType, &item.ItemID, &item.Data, &item.OwnerKey); err != nil {
+		return nil, err
+	}
+
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT s.recipient_id, u.login, k.public_key, s.wrapped_key FROM item_shares s "+
+			"JOIN users u ON u.id = s.recipient_id JOIN user_sharing_keys k ON k.user_id = s.recipient_id "+
+			"WHERE s.shared_item_id = $1 ORDER BY u.login",
+		item.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	for rows.Next() {
+		var share model.ItemShare
+		if err = rows.Scan(&share.RecipientID, &share.RecipientLogin, &share.PublicKey, &share.WrappedKey); err != nil {
+			return nil, err
+		}
+		item.Shares = append(item.Shares, &share)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// ShareItem gives a recipient access to an item.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - item: A pointer to a model.SharedItem instance identifying the item by its owner, type and ID. If the item
+//     isn't shared yet, its Data and OwnerKey contain the new shared copy, otherwise they must be empty and the
+//     existing copy is kept.
+//   - share: A pointer to a model.ItemShare instance containing the recipient and the data key wrapped for them.
+//     The access of a recipient who already has it is replaced.
+//
+// Returns:
+//   - sql.ErrNoRows if the owner has no such item.
+//   - ErrSharedItemChanged if the item has been shared or unshared since the client has checked it.
+//   - An error if the operation fails.
+func (p *PostgresStorage) ShareItem(ctx context.Context, item *model.SharedItem, share *model.ItemShare) error {
+	table, ok := sharedItemTables[item.ItemType]
+	if !ok {
+		return fmt.Errorf("unknown item type %q", item.ItemType)
+	}
+
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM "+table+" WHERE id = $1 AND user_id = $2 FOR UPDATE", item.ItemID, item.OwnerID).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	var sharedItemID int64
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT id FROM shared_items WHERE owner_id = $1 AND item_type = $2 AND item_id = $3 FOR UPDATE",
+		item.OwnerID, item.ItemType, item.ItemID).Scan(&sharedItemID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if item.Data == "" {
+			return ErrSharedItemChanged
+		}
+		err = tx.QueryRowContext(
+			ctx,
+			"INSERT INTO shared_items (owner_id, item_type, item_id, data, owner_key) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+			item.OwnerID, item.ItemType, item.ItemID, item.Data, item.OwnerKey).Scan(&sharedItemID)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case item.Data != "":
+		return ErrSharedItemChanged
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO item_shares (shared_item_id, recipient_id, wrapped_key) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (shared_item_id, recipient_id) DO UPDATE SET wrapped_key = EXCLUDED.wrapped_key",
+		sharedItemID, share.RecipientID, share.WrappedKey)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RekeySharedItem revokes the access of a recipient to a shared item and replaces the shared copy with the copy
+// re-encrypted under a new data key, so that the data key known to the revoked recipient is useless.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - item: A pointer to a model.SharedItem instance identifying the item by its owner, type and ID, containing
+//     the re-encrypted copy and the new data key wrapped for the owner and for every remaining recipient.
+//   - revokedID: An int64 representing the unique identifier of the revoked recipient.
+//
+// Returns:
+//   - sql.ErrNoRows if the item isn't shared with the recipient.
+//   - ErrSharedItemChanged if the remaining recipients aren't exactly the recipients of item.Shares.
+//   - An error if the operation fails.
+//
+// If no recipients remain, the shared copy is removed.
+func (p *PostgresStorage) RekeySharedItem(ctx context.Context, item *model.SharedItem, revokedID int64) error {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var sharedItemID int64
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT id FROM shared_items WHERE owner_id = $1 AND item_type = $2 AND item_id = $3 FOR UPDATE",
+		item.OwnerID, item.ItemType, item.ItemID).Scan(&sharedItemID)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM item_shares WHERE shared_item_id = $1 AND recipient_id = $2", sharedItemID, revokedID)
+	if err != nil {
+		return err
+	}
+	if err = checkRowsAffected(res); err != nil {
+		return err
+	}
+
+	recipientIDs := make([]int64, 0, len(item.Shares))
+	for _, share := range item.Shares {
+		recipientIDs = append(recipientIDs, share.RecipientID)
+	}
+	if err = checkUserItems(ctx, tx, "SELECT recipient_id FROM item_shares WHERE shared_item_id = $1", sharedItemID, recipientIDs); err != nil {
+		if errors.Is(err, ErrVaultItemsChanged) {
+			return ErrSharedItemChanged
+		}
+		return err
+	}
+
+	if len(item.Shares) == 0 {
+		_, err = tx.ExecContext(ctx, "DELETE FROM shared_items WHERE id = $1", sharedItemID)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE shared_items SET data = $1, owner_key = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3",
+		item.Data, item.OwnerKey, sharedItemID)
+	if err != nil {
+		return err
+	}
+	for _, share := range item.Shares {
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE item_shares SET wrapped_key = $1 WHERE shared_item_id = $2 AND recipient_id = $3",
+			share.WrappedKey, sharedItemID, share.RecipientID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetReceivedItems retrieves the items shared with a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - recipientID: An int64 representing the unique identifier of the recipient.
+//
+// Returns:
+//   - A slice of pointers to model.SharedItem instances, each one with the single share of the recipient.
+//     The OwnerKey of the items is not filled in.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetReceivedItems(ctx context.Context, recipientID int64) ([]*model.SharedItem, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT i.id, i.owner_id, u.login, i.item_type, i.item_id, i.data, s.wrapped_key FROM item_shares s "+
+			"JOIN shared_items i ON i.id = s.shared_item_id JOIN users u ON u.id = i.owner_id "+
+			"WHERE s.recipient_id = $1 ORDER BY i.id",
+		recipientID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var items []*model.SharedItem
+	for rows.Next() {
+		item := model.SharedItem{}
+		share := model.ItemShare{RecipientID: recipientID}
+		if err = rows.Scan(&item.ID, &item.OwnerID, &item.OwnerLogin, &item.ItemType, &item.ItemID, &item.Data, &share.WrappedKey); err != nil {
+			return nil, err
+		}
+		item.Shares = []*model.ItemShare{&share}
+		items = append(items, &item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
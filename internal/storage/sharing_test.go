@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+func TestPostgresStorage_Sharing(t *testing.T) {
+	db, dbName := setupTestDB(t)
+	defer teardownTestDB(t, db.Conn, dbName)
+
+	ctx := context.Background()
+	for _, login := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, db.AddUser(ctx, login, "password"))
+	}
+	const alice, bob, carol = 1, 2, 3
+	require.NoError(t, db.AddNote(ctx, &model.Note{UserID: alice, Text: "text", Description: "description"}))
+
+	t.Run("test sharing keys", func(t *testing.T) {
+		for _, userID := range []int64{alice, bob, carol} {
+			keys := &model.SharingKeys{UserID: userID, PublicKey: []byte{byte(userID)}, PrivateKey: "private"}
+			require.NoError(t, db.AddSharingKeys(ctx, keys))
+		}
+		err := db.AddSharingKeys(ctx, &model.SharingKeys{UserID: alice, PublicKey: []byte{9}, PrivateKey: "other"})
+		assert.ErrorIs(t, err, ErrSharingKeysExist)
+
+		keys, err := db.GetSharingKeys(ctx, alice)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{alice}, keys.PublicKey)
+		assert.Equal(t, "private", keys.PrivateKey)
+
+		public, err := db.GetPublicSharingKey(ctx, "bob")
+		require.NoError(t, err)
+		assert.Equal(t, int64(bob), public.UserID)
+		assert.Empty(t, public.PrivateKey)
+
+		_, err = db.GetPublicSharingKey(ctx, "nobody")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	note := &model.SharedItem{OwnerID: alice, ItemType: model.ItemTypeNote, ItemID: 1}
+	t.Run("test share item", func(t *testing.T) {
+		_, err := db.GetSharedItem(ctx, alice, model.ItemTypeNote, 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		// The first share must create the shared copy.
+		err = db.ShareItem(ctx, note, &model.ItemShare{RecipientID: bob, WrappedKey: "bob key"})
+		assert.ErrorIs(t, err, ErrSharedItemChanged)
+
+		first := *note
+		first.Data, first.OwnerKey = "data", "owner key"
+		require.NoError(t, db.ShareItem(ctx, &first, &model.ItemShare{RecipientID: bob, WrappedKey: "bob key"}))
+		err = db.ShareItem(ctx, &first, &model.ItemShare{RecipientID: carol, WrappedKey: "carol key"})
+		assert.ErrorIs(t, err, ErrSharedItemChanged)
+		require.NoError(t, db.ShareItem(ctx, note, &model.ItemShare{RecipientID: carol, WrappedKey: "carol key"}))
+
+		missing := &model.SharedItem{OwnerID: bob, ItemType: model.ItemTypeNote, ItemID: 1, Data: "data", OwnerKey: "key"}
+		err = db.ShareItem(ctx, missing, &model.ItemShare{RecipientID: carol, WrappedKey: "carol key"})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		item, err := db.GetSharedItem(ctx, alice, model.ItemTypeNote, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "data", item.Data)
+		assert.Equal(t, "owner key", item.OwnerKey)
+		require.Len(t, item.Shares, 2)
+		assert.Equal(t, "bob", item.Shares[0].RecipientLogin)
+		assert.Equal(t, []byte{bob}, item.Shares[0].PublicKey)
+
+		received, err := db.GetReceivedItems(ctx, carol)
+		require.NoError(t, err)
+		require.Len(t, received, 1)
+		assert.Equal(t, "alice", received[0].OwnerLogin)
+		assert.Equal(t, "carol key", received[0].Shares[0].WrappedKey)
+	})
+
+	t.Run("test update shared item", func(t *testing.T) {
+		update := &model.Note{ID: 1, UserID: alice, Version: 1, Text: "new text", Description: "description"}
+		_, err := db.UpdateNote(ctx, update, "")
+		assert.ErrorIs(t, err, ErrSharedItemChanged)
+		stored, err := db.GetNote(ctx, alice, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "text", stored.Text)
+
+		version, err := db.UpdateNote(ctx, update, "new data")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+		received, err := db.GetReceivedItems(ctx, bob)
+		require.NoError(t, err)
+		require.Len(t, received, 1)
+		assert.Equal(t, "new data", received[0].Data)
+
+		// The notes that aren't shared have no shared copy to update.
+		require.NoError(t, db.AddNote(ctx, &model.Note{UserID: bob, Text: "text", Description: "description"}))
+		_, err = db.UpdateNote(ctx, &model.Note{ID: 2, UserID: bob, Version: 1, Text: "new text"}, "data")
+		assert.ErrorIs(t, err, ErrSharedItemChanged)
+		_, err = db.UpdateNote(ctx, &model.Note{ID: 2, UserID: bob, Version: 1, Text: "new text"}, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("test rekey shared item", func(t *testing.T) {
+		rekeyed := *note
+		rekeyed.Data, rekeyed.OwnerKey = "new data", "new owner key"
+		err := db.RekeySharedItem(ctx, &rekeyed, bob)
+		assert.ErrorIs(t, err, ErrSharedItemChanged)
+
+		rekeyed.Shares = []*model.ItemShare{{RecipientID: carol, WrappedKey: "new carol key"}}
+		require.NoError(t, db.RekeySharedItem(ctx, &rekeyed, bob))
+		err = db.RekeySharedItem(ctx, &rekeyed, bob)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		received, err := db.GetReceivedItems(ctx, bob)
+		require.NoError(t, err)
+		assert.Empty(t, received)
+		received, err = db.GetReceivedItems(ctx, carol)
+		require.NoError(t, err)
+		require.Len(t, received, 1)
+		assert.Equal(t, "new data", received[0].Data)
+		assert.Equal(t, "new carol key", received[0].Shares[0].WrappedKey)
+
+		require.NoError(t, db.RekeySharedItem(ctx, note, carol))
+		_, err = db.GetSharedItem(ctx, alice, model.ItemTypeNote, 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("test removed item is unshared", func(t *testing.T) {
+		first := *note
+		first.Data, first.OwnerKey = "data", "owner key"
+		require.NoError(t, db.ShareItem(ctx, &first, &model.ItemShare{RecipientID: bob, WrappedKey: "bob key"}))
+		require.NoError(t, db.RemoveNote(ctx, alice, 1))
+
+		received, err := db.GetReceivedItems(ctx, bob)
+		require.NoError(t, err)
+		assert.Empty(t, received)
+	})
+
+	t.Run("test rekey vault requires sharing key", func(t *testing.T) {
+		_, err := db.RekeyVault(ctx, &model.VaultRekey{UserID: alice})
+		assert.ErrorIs(t, err, ErrVaultItemsChanged)
+
+		_, err = db.RekeyVault(ctx, &model.VaultRekey{UserID: alice, SharingKey: "rekeyed private"})
+		require.NoError(t, err)
+		keys, err := db.GetSharingKeys(ctx, alice)
+		require.NoError(t, err)
+		assert.Equal(t, "rekeyed private", keys.PrivateKey)
+	})
+}
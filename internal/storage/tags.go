@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"go.uber.org/zap"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+)
+
+// AddTag stores a new tag of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - tag: A pointer to a model.Tag instance containing the tag.
+//
+// Returns:
+//   - An int64 representing the unique identifier of the new tag.
+//   - An error if the operation fails.
+func (p *PostgresStorage) AddTag(ctx context.Context, tag *model.Tag) (int64, error) {
+	var id int64
+	err := p.Conn.QueryRowContext(
+		ctx,
+		"INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id",
+		tag.UserID, tag.Name).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetTags retrieves the tags of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.Tag instances ordered by ID.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetTags(ctx context.Context, userID int64) ([]*model.Tag, error) {
+	rows, err := p.Conn.QueryContext(ctx, "SELECT id, user_id, name FROM tags WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var tags []*model.Tag
+	for rows.Next() {
+		var t model.Tag
+		if err = rows.Scan(&t.ID, &t.UserID, &t.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// RenameTag replaces the encrypted name of a tag of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - id: An int64 representing the unique identifier of the tag.
+//   - name: A string containing the new encrypted name of the tag.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such tag.
+func (p *PostgresStorage) RenameTag(ctx context.Context, userID, id int64, name string) error {
+	res, err := p.Conn.ExecContext(ctx, "UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3", name, id, userID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// RemoveTag deletes a tag of a user and removes it from all the items.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - id: An int64 representing the unique identifier of the tag.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such tag.
+func (p *PostgresStorage) RemoveTag(ctx context.Context, userID, id int64) error {
+	res, err := p.Conn.ExecContext(ctx, "DELETE FROM tags WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// GetItemTags retrieves the tags of the personal items of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//
+// Returns:
+//   - A slice of pointers to model.ItemTag instances.
+//   - An error if the operation fails.
+func (p *PostgresStorage) GetItemTags(ctx context.Context, userID int64) ([]*model.ItemTag, error) {
+	rows, err := p.Conn.QueryContext(
+		ctx,
+		"SELECT item_type, item_id, tag_id FROM item_tags WHERE user_id = $1 ORDER BY item_type, item_id, tag_id",
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logger.Log.Error("error close rows", zap.Error(err))
+		}
+	}(rows)
+
+	var items []*model.ItemTag
+	for rows.Next() {
+		var item model.ItemTag
+		if err = rows.Scan(&item.ItemType, &item.ItemID, &item.TagID); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// TagItem tags a personal item of a user with a tag of the user, tagging an item twice with a tag has no effect.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - item: A pointer to a model.ItemTag instance selecting the item and the tag.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the user has no such item or tag.
+func (p *PostgresStorage) TagItem(ctx context.Context, userID int64, item *model.ItemTag) error {
+	if err := p.checkLabeledItem(ctx, userID, item.ItemType, item.ItemID); err != nil {
+		return err
+	}
+
+	var exists bool
+	err := p.Conn.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM tags WHERE id = $1 AND user_id = $2)",
+		item.TagID, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	_, err = p.Conn.ExecContext(
+		ctx,
+		"INSERT INTO item_tags (user_id, item_type, item_id, tag_id) VALUES ($1, $2, $3, $4) "+
+			"ON CONFLICT (tag_id, item_type, item_id) DO NOTHING",
+		userID, item.ItemType, item.ItemID, item.TagID)
+	return err
+}
+
+// UntagItem removes a tag from a personal item of a user.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - userID: An int64 representing the unique identifier of the user.
+//   - item: A pointer to a model.ItemTag instance selecting the item and the tag.
+//
+// Returns:
+//   - An error if the operation fails, or sql.ErrNoRows if the item isn't tagged with the tag.
+func (p *PostgresStorage) UntagItem(ctx context.Context, userID int64, item *model.ItemTag) error {
+	res, err := p.Conn.ExecContext(
+		ctx,
+		"DELETE FROM item_tags WHERE user_id = $1 AND item_type = $2 AND item_id = $3 AND tag_id = $4",
+		userID, item.ItemType, item.ItemID, item.TagID)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
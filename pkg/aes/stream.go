@@ -0,0 +1,274 @@
+// Package aes provides functionality for AES encryption and decryption.
+//
+// This file implements a streaming authenticated encryption format for large files. The stream starts
+// with a header followed by chunks of StreamChunkSize plaintext bytes, each sealed with AES-GCM:
+//
+//	header = magic (4 bytes) || version (1 byte) || random nonce prefix (7 bytes)
+//	nonce  = nonce prefix (7 bytes) || chunk counter (4 bytes, big-endian) || last chunk flag (1 byte)
+//
+// Every chunk gets its own nonce, the counter authenticates the order of the chunks and the flag marks
+// the final chunk, so reordered, dropped or truncated chunks fail authentication. The header is passed
+// as additional data to every chunk.
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+const (
+	// StreamChunkSize is the number of plaintext bytes in each chunk of an encrypted stream.
+	StreamChunkSize = 1024 * 1024
+	// StreamHeaderSize is the size of the header at the beginning of an encrypted stream.
+	StreamHeaderSize = len(streamMagic) + 1 + streamNoncePrefixSize
+
+	streamMagic           = "GKEF"
+	streamVersion         = 1
+	streamNoncePrefixSize = 7
+	streamTagSize         = 16
+)
+
+var (
+	// ErrInvalidStreamHeader is returned when the data does not start with a supported stream header.
+	ErrInvalidStreamHeader = errors.New("invalid encrypted stream header")
+	// ErrStreamAuthentication is returned when a chunk of the stream is corrupted, reordered or missing.
+	ErrStreamAuthentication = errors.New("encrypted stream is corrupted or truncated")
+)
+
+// IsEncryptedStream reports whether the data starts with the header of an encrypted stream.
+func IsEncryptedStream(data []byte) bool {
+	return len(data) >= StreamHeaderSize &&
+		string(data[:len(streamMagic)]) == streamMagic &&
+		data[len(streamMagic)] == streamVersion
+}
+
+// EncryptedSize returns the size of the encrypted stream produced for a plaintext of the given size.
+func EncryptedSize(size int64) int64 {
+	chunks := (size + StreamChunkSize - 1) / StreamChunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+	return int64(StreamHeaderSize) + size + chunks*streamTagSize
+}
+
+type streamCipher struct {
+	aead    cipher.AEAD
+	header  []byte
+	nonce   []byte
+	counter uint64
+}
+
+func newStreamCipher(key string, header []byte) (*streamCipher, error) {
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, header[len(streamMagic)+1:])
+	return &streamCipher{aead: aead, header: header, nonce: nonce}, nil
+}
+
+// nextNonce returns the nonce of the next chunk and advances the chunk counter.
+func (s *streamCipher) nextNonce(last bool) ([]byte, error) {
+	if s.counter > math.MaxUint32 {
+		return nil, errors.New("encrypted stream is too long")
+	}
+	binary.BigEndian.PutUint32(s.nonce[streamNoncePrefixSize:], uint32(s.counter))
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+	s.counter++
+	return s.nonce, nil
+}
+
+type encryptReader struct {
+	src      io.Reader
+	stream   *streamCipher
+	cur      []byte
+	next     []byte
+	sealed   []byte
+	out      []byte
+	finished bool
+}
+
+// NewEncryptReader returns a reader that encrypts the data read from src into an encrypted stream.
+//
+// Parameters:
+//   - src: The reader of the plaintext data.
+//   - key: A string representing the AES key. The key must be either 16, 24, or 32 bytes long.
+//
+// Returns:
+//   - An io.Reader producing the header and the encrypted chunks.
+//   - An error if the key is invalid or the random nonce prefix could not be generated.
+func NewEncryptReader(src io.Reader, key string) (io.Reader, error) {
+	header := make([]byte, StreamHeaderSize)
+	copy(header, streamMagic)
+	header[len(streamMagic)] = streamVersion
+	if _, err := io.ReadFull(rand.Reader, header[len(streamMagic)+1:]); err != nil {
+		return nil, err
+	}
+
+	stream, err := newStreamCipher(key, header)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &encryptReader{
+		src:    src,
+		stream: stream,
+		cur:    make([]byte, StreamChunkSize),
+		next:   make([]byte, StreamChunkSize),
+		sealed: make([]byte, 0, StreamChunkSize+streamTagSize),
+		out:    header,
+	}
+	if r.cur, err = r.readChunk(r.cur); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// readChunk fills buf with up to StreamChunkSize bytes from the source.
+func (r *encryptReader) readChunk(buf []byte) ([]byte, error) {
+	n, err := io.ReadFull(r.src, buf[:StreamChunkSize])
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	return buf[:n], err
+}
+
+// Read implements io.Reader.
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.finished {
+			return 0, io.EOF
+		}
+		if err := r.sealChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// sealChunk encrypts the current chunk. A short chunk is always the last one, a full chunk is the last
+// one only if nothing follows it, which is checked by reading the next chunk ahead.
+func (r *encryptReader) sealChunk() error {
+	last := len(r.cur) < StreamChunkSize
+	if !last {
+		next, err := r.readChunk(r.next)
+		if err != nil {
+			return err
+		}
+		r.next = next
+		last = len(next) == 0
+	}
+
+	nonce, err := r.stream.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	r.out = r.stream.aead.Seal(r.sealed[:0], nonce, r.cur, r.stream.header)
+	r.cur, r.next = r.next, r.cur
+	r.finished = last
+	return nil
+}
+
+type decryptWriter struct {
+	dst      io.Writer
+	key      string
+	stream   *streamCipher
+	header   []byte
+	buf      []byte
+	plain    []byte
+	finished bool
+}
+
+// NewDecryptWriter returns a writer that decrypts an encrypted stream written to it and writes the plaintext to dst.
+//
+// The last chunk is decrypted by Close, so the caller must call Close and check its error: it reports
+// truncated streams, which are otherwise indistinguishable from complete ones.
+//
+// Parameters:
+//   - dst: The writer of the decrypted data.
+//   - key: A string representing the AES key used to encrypt the stream.
+//
+// Returns:
+//   - An io.WriteCloser accepting the encrypted stream.
+func NewDecryptWriter(dst io.Writer, key string) io.WriteCloser {
+	return &decryptWriter{
+		dst:    dst,
+		key:    key,
+		header: make([]byte, 0, StreamHeaderSize),
+	}
+}
+
+// Write implements io.Writer.
+func (w *decryptWriter) Write(p []byte) (int, error) {
+	if w.finished {
+		return 0, errors.New("write to closed encrypted stream")
+	}
+	n := len(p)
+
+	if w.stream == nil {
+		k := min(StreamHeaderSize-len(w.header), len(p))
+		w.header = append(w.header, p[:k]...)
+		p = p[k:]
+		if len(w.header) < StreamHeaderSize {
+			return n, nil
+		}
+		if !IsEncryptedStream(w.header) {
+			return 0, ErrInvalidStreamHeader
+		}
+		stream, err := newStreamCipher(w.key, w.header)
+		if err != nil {
+			return 0, err
+		}
+		w.stream = stream
+	}
+
+	w.buf = append(w.buf, p...)
+	// A full chunk is kept in the buffer until more data arrives, because only then it is known not to be the last one.
+	for len(w.buf) > StreamChunkSize+streamTagSize {
+		if err := w.openChunk(w.buf[:StreamChunkSize+streamTagSize], false); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[:copy(w.buf, w.buf[StreamChunkSize+streamTagSize:])]
+	}
+	return n, nil
+}
+
+// Close decrypts the last chunk of the stream.
+func (w *decryptWriter) Close() error {
+	if w.finished {
+		return nil
+	}
+	w.finished = true
+	if w.stream == nil {
+		return ErrInvalidStreamHeader
+	}
+	return w.openChunk(w.buf, true)
+}
+
+func (w *decryptWriter) openChunk(chunk []byte, last bool) error {
+	nonce, err := w.stream.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	w.plain, err = w.stream.aead.Open(w.plain[:0], nonce, chunk, w.stream.header)
+	if err != nil {
+		return ErrStreamAuthentication
+	}
+	_, err = w.dst.Write(w.plain)
+	return err
+}
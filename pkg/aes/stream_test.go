@@ -0,0 +1,109 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamKey = "examplekey1234567890123456789011"
+
+func encryptStream(t *testing.T, plain []byte) []byte {
+	r, err := NewEncryptReader(bytes.NewReader(plain), streamKey)
+	require.NoError(t, err)
+	encrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return encrypted
+}
+
+func decryptStream(encrypted []byte, key string, writeSize int) ([]byte, error) {
+	var out bytes.Buffer
+	w := NewDecryptWriter(&out, key)
+	for len(encrypted) > 0 {
+		n := min(writeSize, len(encrypted))
+		if _, err := w.Write(encrypted[:n]); err != nil {
+			return nil, err
+		}
+		encrypted = encrypted[n:]
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	sizes := []int{0, 1, StreamChunkSize - 1, StreamChunkSize, StreamChunkSize + 1, 2*StreamChunkSize + 5}
+	for _, size := range sizes {
+		plain := make([]byte, size)
+		_, err := rand.Read(plain)
+		require.NoError(t, err)
+
+		encrypted := encryptStream(t, plain)
+		assert.Equal(t, EncryptedSize(int64(size)), int64(len(encrypted)), "size %d", size)
+		assert.True(t, IsEncryptedStream(encrypted))
+
+		for _, writeSize := range []int{1024, StreamChunkSize + streamTagSize} {
+			decrypted, err := decryptStream(encrypted, streamKey, writeSize)
+			require.NoError(t, err, "size %d", size)
+			assert.True(t, bytes.Equal(plain, decrypted), "size %d", size)
+		}
+	}
+}
+
+func TestStreamUniqueNonces(t *testing.T) {
+	plain := []byte("the same plaintext")
+	assert.NotEqual(t, encryptStream(t, plain), encryptStream(t, plain))
+}
+
+func TestStreamTampering(t *testing.T) {
+	plain := make([]byte, 2*StreamChunkSize+5)
+	encrypted := encryptStream(t, plain)
+	encChunk := StreamChunkSize + streamTagSize
+	header := encrypted[:StreamHeaderSize]
+	first := encrypted[StreamHeaderSize : StreamHeaderSize+encChunk]
+	second := encrypted[StreamHeaderSize+encChunk : StreamHeaderSize+2*encChunk]
+	last := encrypted[StreamHeaderSize+2*encChunk:]
+
+	join := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+	flipped := bytes.Clone(encrypted)
+	flipped[StreamHeaderSize+10] ^= 1
+
+	tests := []struct {
+		name string
+		data []byte
+		key  string
+		err  error
+	}{
+		{name: "truncated after full chunk", data: join(header, first, second), key: streamKey, err: ErrStreamAuthentication},
+		{name: "last chunk dropped inside data", data: join(header, first, last), key: streamKey, err: ErrStreamAuthentication},
+		{name: "chunks reordered", data: join(header, second, first, last), key: streamKey, err: ErrStreamAuthentication},
+		{name: "modified ciphertext", data: flipped, key: streamKey, err: ErrStreamAuthentication},
+		{name: "only header", data: header, key: streamKey, err: ErrStreamAuthentication},
+		{name: "short header", data: header[:5], key: streamKey, err: ErrInvalidStreamHeader},
+		{name: "plaintext data", data: []byte("this is not an encrypted stream"), key: streamKey, err: ErrInvalidStreamHeader},
+		{name: "wrong key", data: encrypted, key: "examplekey1234567890123456789012", err: ErrStreamAuthentication},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decryptStream(tt.data, tt.key, 4096)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewEncryptReaderInvalidKey(t *testing.T) {
+	_, err := NewEncryptReader(bytes.NewReader(nil), "shortkey")
+	assert.Error(t, err)
+}
+
+func TestIsEncryptedStream(t *testing.T) {
+	assert.False(t, IsEncryptedStream([]byte("GKEF")))
+	assert.False(t, IsEncryptedStream([]byte("plain text file content")))
+}
@@ -0,0 +1,143 @@
+// Package breach provides functionality for checking passwords against a local corpus of breached passwords.
+//
+// The corpus is a text file in the Have I Been Pwned format: one "HASH:COUNT" line per breached password,
+// where HASH is the upper-case hex SHA-1 of the password and COUNT is how many times it has been seen,
+// sorted by the hash. The file is searched with a binary search on its bytes, so even the full multi-gigabyte
+// corpus is checked without loading it, and the passwords never leave the machine.
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+// hashLength is the length of the hex SHA-1 hashes in the corpus.
+const hashLength = sha1.Size * 2
+
+// ErrInvalidCorpus is returned when a line of the corpus has an unexpected format.
+var ErrInvalidCorpus = errors.New("invalid breach corpus line")
+
+// Corpus is an opened breach corpus file.
+type Corpus struct {
+	file *os.File
+	size int64
+}
+
+// Open opens a breach corpus file.
+//
+// Parameters:
+//   - path: The path to the corpus file.
+//
+// Returns:
+//   - A pointer to the Corpus, which must be closed by the caller.
+//   - An error if the file can't be opened.
+func Open(path string) (*Corpus, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &Corpus{file: file, size: info.Size()}, nil
+}
+
+// Close closes the corpus file.
+func (c *Corpus) Close() error {
+	return c.file.Close()
+}
+
+// Count returns how many times a password has been seen in the breaches.
+//
+// Parameters:
+//   - password: The plaintext password to check.
+//
+// Returns:
+//   - The number of times the password has been seen, or zero if it isn't in the corpus.
+//     Corpus lines without a count are reported as seen once.
+//   - An error if the file can't be read or has an unexpected format.
+func (c *Corpus) Count(password string) (int64, error) {
+	sum := sha1.Sum([]byte(password))
+	target := make([]byte, hashLength)
+	hex.Encode(target, sum[:])
+	target = bytes.ToUpper(target)
+
+	// Find the first line not less than the target: the answer is always the first line
+	// starting at or after lo.
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := c.lineFrom(mid)
+		if err != nil {
+			return 0, err
+		}
+		if line == nil || bytes.Compare(lineHash(line), target) >= 0 {
+			hi = mid
+		} else {
+			lo = start + 1
+		}
+	}
+
+	_, line, err := c.lineFrom(lo)
+	if err != nil || line == nil || !bytes.Equal(lineHash(line), target) {
+		return 0, err
+	}
+	return lineCount(line)
+}
+
+// lineFrom reads the first line starting at or after the offset. It returns a nil line if there is none.
+func (c *Corpus) lineFrom(offset int64) (int64, []byte, error) {
+	start := offset
+	if offset > 0 {
+		start--
+	}
+	reader := bufio.NewReader(io.NewSectionReader(c.file, start, c.size-start))
+	if offset > 0 {
+		// Skip the rest of the line the offset is in, unless the offset is at the start of a line.
+		skipped, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return c.size, nil, nil
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		start += int64(len(skipped))
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return 0, nil, err
+	}
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return c.size, nil, nil
+	}
+	return start, line, nil
+}
+
+// lineHash returns the upper-case hash of a corpus line.
+func lineHash(line []byte) []byte {
+	hash, _, _ := bytes.Cut(line, []byte(":"))
+	return bytes.ToUpper(hash)
+}
+
+// lineCount returns the count of a corpus line.
+func lineCount(line []byte) (int64, error) {
+	_, count, found := bytes.Cut(line, []byte(":"))
+	if !found {
+		return 1, nil
+	}
+	n, err := strconv.ParseInt(string(bytes.TrimSpace(count)), 10, 64)
+	if err != nil || n < 1 {
+		return 0, ErrInvalidCorpus
+	}
+	return n, nil
+}
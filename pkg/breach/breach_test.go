@@ -0,0 +1,93 @@
+package breach
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hashOf(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func writeCorpus(t *testing.T, lines []string, newline string) string {
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, newline)+newline), 0o600))
+	return path
+}
+
+func TestCorpus_Count(t *testing.T) {
+	breached := map[string]int64{"password": 10434004, "123456": 42, "qwerty": 7}
+	var lines []string
+	for password, count := range breached {
+		lines = append(lines, fmt.Sprintf("%s:%d", hashOf(password), count))
+	}
+	for i := 0; i < 500; i++ {
+		lines = append(lines, fmt.Sprintf("%s:%d", hashOf(fmt.Sprintf("filler%d", i)), i+1))
+	}
+
+	for _, newline := range []string{"\n", "\r\n"} {
+		corpus, err := Open(writeCorpus(t, append([]string(nil), lines...), newline))
+		require.NoError(t, err)
+
+		for password, count := range breached {
+			n, err := corpus.Count(password)
+			require.NoError(t, err)
+			assert.Equal(t, count, n, password)
+		}
+		for i := 0; i < 500; i += 37 {
+			n, err := corpus.Count(fmt.Sprintf("filler%d", i))
+			require.NoError(t, err)
+			assert.Equal(t, int64(i+1), n)
+		}
+		n, err := corpus.Count("correct horse battery staple")
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		require.NoError(t, corpus.Close())
+	}
+}
+
+func TestCorpus_Edges(t *testing.T) {
+	t.Run("empty corpus", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.txt")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		corpus, err := Open(path)
+		require.NoError(t, err)
+		defer corpus.Close()
+		n, err := corpus.Count("password")
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("lower-case hashes without counts", func(t *testing.T) {
+		corpus, err := Open(writeCorpus(t, []string{strings.ToLower(hashOf("password"))}, "\n"))
+		require.NoError(t, err)
+		defer corpus.Close()
+		n, err := corpus.Count("password")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		corpus, err := Open(writeCorpus(t, []string{hashOf("password") + ":many"}, "\n"))
+		require.NoError(t, err)
+		defer corpus.Close()
+		_, err = corpus.Count("password")
+		assert.ErrorIs(t, err, ErrInvalidCorpus)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Open(filepath.Join(t.TempDir(), "missing.txt"))
+		assert.Error(t, err)
+	})
+}
@@ -1,6 +1,9 @@
 /*
 Package main provides the entry point for creating and saving
 X.509 certificates and corresponding private keys in PEM format.
+
+Besides the server certificate, it creates the CA for the mutual TLS mode
+("ca" command) and issues client certificates signed by it ("client" command).
 */
 package main
 
@@ -13,17 +16,20 @@ import (
 )
 
 func main() {
-	if len(os.Args) != 5 {
+	var err error
+	switch {
+	case len(os.Args) == 6 && os.Args[1] == "ca":
+		err = x509.CreateCAAndSave(os.Args[2], os.Args[3], os.Args[4], os.Args[5])
+	case len(os.Args) == 7 && os.Args[1] == "client":
+		err = x509.IssueClientAndSave(os.Args[2], os.Args[3], os.Args[4], os.Args[5], os.Args[6])
+	case len(os.Args) == 5:
+		err = x509.CreateAndSave(os.Args[1], os.Args[2], os.Args[3], os.Args[4])
+	default:
 		fmt.Println("Usage: go run main.go <organization> <country> <pathCertPEM> <pathPrivateKeyPEM>")
+		fmt.Println("       go run main.go ca <organization> <country> <pathCACertPEM> <pathCAPrivateKeyPEM>")
+		fmt.Println("       go run main.go client <pathCACertPEM> <pathCAPrivateKeyPEM> <commonName> <pathCertPEM> <pathPrivateKeyPEM>")
 		return
 	}
-
-	organization := os.Args[1]
-	country := os.Args[2]
-	pathCertPEM := os.Args[3]
-	pathPrivateKeyPEM := os.Args[4]
-
-	err := x509.CreateAndSave(organization, country, pathCertPEM, pathPrivateKeyPEM)
 	if err != nil {
 		log.Fatalf("Error creating and saving certificate: %v", err)
 	}
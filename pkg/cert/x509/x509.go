@@ -11,6 +11,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"math/big"
 	"net"
 	"os"
@@ -105,22 +106,138 @@ func createPrivateKeyPEM(privateKey *rsa.PrivateKey) (bytes.Buffer, error) {
 	return privateKeyPEM, err
 }
 
-// CreateAndSave is the entry point of the program. It creates an X.509 certificate,
-// generates the corresponding private key, and saves them to files
-// cert.pem and privateKey.pem.
+// createCACertificate creates a new self-signed X.509 CA certificate and the corresponding
+// private RSA key. The CA signs the client certificates accepted by the server in the mutual
+// TLS mode, and has a validity period of 10 years.
 // Parameters:
 //   - organization: the name of the organization for which the certificate is created.
 //   - country: the country where the organization is registered.
-//   - pathCertPEM: path where to save cert pem file.
-//   - pathPrivateKeyPEM: path where to save private key pem file.
 //
-// Returns an error if operation fails.
-func CreateAndSave(organization, country, pathCertPEM, pathPrivateKeyPEM string) error {
-	privateKey, certBytes, err := createX509Certificate(organization, country)
+// Returns the private key, certificate bytes, and an error if the certificate creation fails.
+func createCACertificate(organization, country string) (*rsa.PrivateKey, []byte, error) {
+	serialNumber, err := randomSerialNumber()
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	cert := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{organization},
+			Country:      []string{country},
+			CommonName:   organization + " client CA",
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, cert, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return privateKey, certBytes, nil
+}
+
+// createClientCertificate creates a new X.509 client certificate signed by the CA and the
+// corresponding private RSA key. The certificate can only be used for client authentication
+// and has a validity period of 1 year.
+// Parameters:
+//   - caCert: the CA certificate.
+//   - caKey: the private key of the CA.
+//   - commonName: the name of the client, it becomes the common name of the certificate subject.
+//
+// Returns the private key, certificate bytes, and an error if the certificate creation fails.
+func createClientCertificate(caCert *x509.Certificate, caKey *rsa.PrivateKey, commonName string) (*rsa.PrivateKey, []byte, error) {
+	serialNumber, err := randomSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	cert := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: caCert.Subject.Organization,
+			Country:      caCert.Subject.Country,
+			CommonName:   commonName,
+		},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().AddDate(1, 0, 0),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, caCert, &privateKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	return privateKey, certBytes, nil
+}
+
+// randomSerialNumber generates a random 128-bit certificate serial number.
+func randomSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// loadCA reads the CA certificate and its private RSA key from PEM files.
+// Parameters:
+//   - pathCACertPEM: path to the CA certificate pem file.
+//   - pathCAPrivateKeyPEM: path to the CA private key pem file.
+//
+// Returns the CA certificate, its private key, and an error if the files can't be read or parsed.
+func loadCA(pathCACertPEM, pathCAPrivateKeyPEM string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(pathCACertPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, nil, errors.New("CA certificate pem file doesn't contain a certificate")
+	}
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !caCert.IsCA {
+		return nil, nil, errors.New("certificate is not a CA certificate")
+	}
+
+	keyPEM, err := os.ReadFile(pathCAPrivateKeyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ = pem.Decode(keyPEM)
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		return nil, nil, errors.New("CA private key pem file doesn't contain an RSA private key")
+	}
+	caKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return caCert, caKey, nil
+}
+
+// save encodes the certificate and the private key in PEM format and saves them to files.
+// Parameters:
+//   - privateKey: the private RSA key to save.
+//   - certBytes: the bytes of the certificate to save.
+//   - pathCertPEM: path where to save cert pem file.
+//   - pathPrivateKeyPEM: path where to save private key pem file.
+//
+// Returns an error if operation fails.
+func save(privateKey *rsa.PrivateKey, certBytes []byte, pathCertPEM, pathPrivateKeyPEM string) error {
 	certPEM, err := createCertPEM(certBytes)
 	if err != nil {
 		return err
@@ -142,3 +259,60 @@ func CreateAndSave(organization, country, pathCertPEM, pathPrivateKeyPEM string)
 	}
 	return nil
 }
+
+// CreateAndSave is the entry point of the program. It creates an X.509 certificate,
+// generates the corresponding private key, and saves them to files
+// cert.pem and privateKey.pem.
+// Parameters:
+//   - organization: the name of the organization for which the certificate is created.
+//   - country: the country where the organization is registered.
+//   - pathCertPEM: path where to save cert pem file.
+//   - pathPrivateKeyPEM: path where to save private key pem file.
+//
+// Returns an error if operation fails.
+func CreateAndSave(organization, country, pathCertPEM, pathPrivateKeyPEM string) error {
+	privateKey, certBytes, err := createX509Certificate(organization, country)
+	if err != nil {
+		return err
+	}
+	return save(privateKey, certBytes, pathCertPEM, pathPrivateKeyPEM)
+}
+
+// CreateCAAndSave creates a CA certificate for the mutual TLS mode, generates the corresponding
+// private key, and saves them to files.
+// Parameters:
+//   - organization: the name of the organization for which the certificate is created.
+//   - country: the country where the organization is registered.
+//   - pathCertPEM: path where to save CA cert pem file.
+//   - pathPrivateKeyPEM: path where to save CA private key pem file.
+//
+// Returns an error if operation fails.
+func CreateCAAndSave(organization, country, pathCertPEM, pathPrivateKeyPEM string) error {
+	privateKey, certBytes, err := createCACertificate(organization, country)
+	if err != nil {
+		return err
+	}
+	return save(privateKey, certBytes, pathCertPEM, pathPrivateKeyPEM)
+}
+
+// IssueClientAndSave issues a client certificate signed by the CA, generates the corresponding
+// private key, and saves them to files.
+// Parameters:
+//   - pathCACertPEM: path to the CA cert pem file created by CreateCAAndSave.
+//   - pathCAPrivateKeyPEM: path to the CA private key pem file created by CreateCAAndSave.
+//   - commonName: the name of the client, it becomes the common name of the certificate subject.
+//   - pathCertPEM: path where to save client cert pem file.
+//   - pathPrivateKeyPEM: path where to save client private key pem file.
+//
+// Returns an error if operation fails.
+func IssueClientAndSave(pathCACertPEM, pathCAPrivateKeyPEM, commonName, pathCertPEM, pathPrivateKeyPEM string) error {
+	caCert, caKey, err := loadCA(pathCACertPEM, pathCAPrivateKeyPEM)
+	if err != nil {
+		return err
+	}
+	privateKey, certBytes, err := createClientCertificate(caCert, caKey, commonName)
+	if err != nil {
+		return err
+	}
+	return save(privateKey, certBytes, pathCertPEM, pathPrivateKeyPEM)
+}
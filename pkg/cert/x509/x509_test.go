@@ -1,11 +1,14 @@
 package x509
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_createAndSave(t *testing.T) {
@@ -140,3 +143,40 @@ func Test_createX509Certificate(t *testing.T) {
 		})
 	}
 }
+
+func Test_issueClientAndSave(t *testing.T) {
+	dir := t.TempDir()
+	caCertPath := filepath.Join(dir, "ca.crt")
+	caKeyPath := filepath.Join(dir, "ca.key")
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+
+	require.NoError(t, CreateCAAndSave("test", "US", caCertPath, caKeyPath))
+	require.NoError(t, IssueClientAndSave(caCertPath, caKeyPath, "alice", certPath, keyPath))
+
+	caCert, _, err := loadCA(caCertPath, caKeyPath)
+	require.NoError(t, err)
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(t, err)
+	clientCert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "alice", clientCert.Subject.CommonName)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	_, err = clientCert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.NoError(t, err)
+
+	t.Run("server certificate is not a CA", func(t *testing.T) {
+		serverCertPath := filepath.Join(dir, "server.crt")
+		serverKeyPath := filepath.Join(dir, "server.key")
+		require.NoError(t, CreateAndSave("test", "US", serverCertPath, serverKeyPath))
+		err := IssueClientAndSave(serverCertPath, serverKeyPath, "alice", certPath, keyPath)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing CA", func(t *testing.T) {
+		err := IssueClientAndSave(filepath.Join(dir, "missing.crt"), caKeyPath, "alice", certPath, keyPath)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,158 @@
+// Package fuzzy provides the fuzzy matching of search terms against texts.
+//
+// A term matches a text if it is a word of the text, the beginning of a word or a part of the text, if it is
+// a word of the text with a typo, or if its characters appear in the text in order and close to each other.
+// The better the match, the higher the score: a whole word scores more than a typo or scattered characters.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The scores of the kinds of matches.
+const (
+	ScoreWord        = 100
+	ScorePrefix      = 80
+	ScoreSubstring   = 60
+	ScoreTypo        = 40
+	ScoreSubsequence = 20
+)
+
+// minSubsequenceLength is the length below which a term only matches as a part of the text,
+// the characters of a shorter term are found in order in almost any text.
+const minSubsequenceLength = 3
+
+// Terms splits a search query into its lower-case terms.
+func Terms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// words splits a lower-case text into its words, the runs of letters and digits.
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// maxTypos returns the number of the typos a term of the length may contain.
+func maxTypos(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Score returns how well a lower-case term matches a text, or zero if the term doesn't match it.
+// The text is compared case-insensitively.
+func Score(term, text string) int {
+	if term == "" {
+		return 0
+	}
+	text = strings.ToLower(text)
+
+	if strings.Contains(text, term) {
+		best := ScoreSubstring
+		for _, w := range words(text) {
+			if w == term {
+				return ScoreWord
+			}
+			if strings.HasPrefix(w, term) {
+				best = ScorePrefix
+			}
+		}
+		return best
+	}
+
+	termRunes := []rune(term)
+	if typos := maxTypos(len(termRunes)); typos > 0 {
+		best := 0
+		for _, w := range words(text) {
+			wordRunes := []rune(w)
+			if abs(len(wordRunes)-len(termRunes)) > typos {
+				continue
+			}
+			if d := distance(termRunes, wordRunes, typos); d <= typos {
+				if score := ScoreTypo - 10*(d-1); score > best {
+					best = score
+				}
+			}
+		}
+		if best > 0 {
+			return best
+		}
+	}
+
+	if len(termRunes) < minSubsequenceLength {
+		return 0
+	}
+	span := subsequenceSpan(termRunes, []rune(text))
+	if span == 0 || span > 2*len(termRunes) {
+		return 0
+	}
+	return ScoreSubsequence * len(termRunes) / span
+}
+
+// subsequenceSpan returns the length of the shortest part of the text containing the characters of the term
+// in order, or zero if the text doesn't contain them.
+func subsequenceSpan(term, text []rune) int {
+	best := 0
+	for start := range text {
+		if text[start] != term[0] {
+			continue
+		}
+		matched := 1
+		end := start
+		for i := start + 1; i < len(text) && matched < len(term); i++ {
+			if text[i] == term[matched] {
+				matched++
+				end = i
+			}
+		}
+		if matched < len(term) {
+			break
+		}
+		if span := end - start + 1; best == 0 || span < best {
+			best = span
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between the strings, or a value greater than limit
+// if the distance exceeds it.
+func distance(a, b []rune, limit int) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,40 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		term  string
+		text  string
+		score int
+	}{
+		{name: "word", term: "github", text: "Work GitHub account", score: ScoreWord},
+		{name: "prefix", term: "git", text: "Work GitHub account", score: ScorePrefix},
+		{name: "substring", term: "hub", text: "Work GitHub account", score: ScoreSubstring},
+		{name: "typo", term: "githib", text: "Work GitHub account", score: ScoreTypo},
+		{name: "two typos", term: "acount", text: "Work GitHub account", score: ScoreTypo},
+		{name: "two typos in a long word", term: "pasword1", text: "my password", score: ScoreTypo - 10},
+		{name: "no typos in a short word", term: "cat", text: "act"},
+		{name: "subsequence", term: "wgh", text: "w-g-h", score: ScoreSubsequence * 3 / 5},
+		{name: "scattered subsequence", term: "wga", text: "Work GitHub account"},
+		{name: "short term", term: "wg", text: "w g"},
+		{name: "cyrillic", term: "почта", text: "Рабочая ПОЧТА", score: ScoreWord},
+		{name: "no match", term: "bank", text: "Work GitHub account"},
+		{name: "empty term", term: "", text: "Work GitHub account"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.score, Score(tt.term, tt.text))
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"work", "github"}, Terms("  Work\tGitHub "))
+	assert.Empty(t, Terms(" "))
+}
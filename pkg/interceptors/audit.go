@@ -0,0 +1,224 @@
+// Package interceptors provides gRPC interceptors for handling requests and responses.
+//
+// This package includes the Audit function, which records every call in the tamper-evident
+// audit log.
+package interceptors
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/internal/model"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// auditSubjectKey is the context key of the user the audited call is made by.
+const auditSubjectKey contextKey = "AuditSubject"
+
+// AuditStore persists the audit log.
+type AuditStore interface {
+	// AddAuditEvent appends the event to the audit log.
+	AddAuditEvent(ctx context.Context, event *model.AuditEvent) error
+}
+
+// auditItemTypes maps the methods to the type of the items they operate on.
+var auditItemTypes = map[string]string{
+	proto.Gophkeeper_RegisterUser_FullMethodName:            "user",
+	proto.Gophkeeper_Authorize_FullMethodName:               "user",
+	proto.Gophkeeper_AddBankCard_FullMethodName:             "card",
+	proto.Gophkeeper_UpdateBankCard_FullMethodName:          "card",
+	proto.Gophkeeper_RemoveBankCard_FullMethodName:          "card",
+	proto.Gophkeeper_GetBankCards_FullMethodName:            "card",
+	proto.Gophkeeper_GetBankCard_FullMethodName:             "card",
+	proto.Gophkeeper_AddOTPItem_FullMethodName:              "otp",
+	proto.Gophkeeper_UpdateOTPItem_FullMethodName:           "otp",
+	proto.Gophkeeper_RemoveOTPItem_FullMethodName:           "otp",
+	proto.Gophkeeper_GetOTPItems_FullMethodName:             "otp",
+	proto.Gophkeeper_GetOTPItem_FullMethodName:              "otp",
+	proto.Gophkeeper_AddItemTemplate_FullMethodName:         "template",
+	proto.Gophkeeper_GetItemTemplates_FullMethodName:        "template",
+	proto.Gophkeeper_RemoveItemTemplate_FullMethodName:      "template",
+	proto.Gophkeeper_AddCustomItem_FullMethodName:           "custom",
+	proto.Gophkeeper_UpdateCustomItem_FullMethodName:        "custom",
+	proto.Gophkeeper_RemoveCustomItem_FullMethodName:        "custom",
+	proto.Gophkeeper_GetCustomItems_FullMethodName:          "custom",
+	proto.Gophkeeper_GetCustomItem_FullMethodName:           "custom",
+	proto.Gophkeeper_AddUserCredentials_FullMethodName:      "credentials",
+	proto.Gophkeeper_UpdateUserCredentials_FullMethodName:   "credentials",
+	proto.Gophkeeper_GetUserCredentials_FullMethodName:      "credentials",
+	proto.Gophkeeper_GetUserCredential_FullMethodName:       "credentials",
+	proto.Gophkeeper_RemoveUserCredentials_FullMethodName:   "credentials",
+	proto.Gophkeeper_AddNote_FullMethodName:                 "note",
+	proto.Gophkeeper_UpdateNote_FullMethodName:              "note",
+	proto.Gophkeeper_GetNotes_FullMethodName:                "note",
+	proto.Gophkeeper_GetNote_FullMethodName:                 "note",
+	proto.Gophkeeper_RemoveNote_FullMethodName:              "note",
+	proto.Gophkeeper_Upload_FullMethodName:                  "file",
+	proto.Gophkeeper_Download_FullMethodName:                "file",
+	proto.Gophkeeper_RemoveFile_FullMethodName:              "file",
+	proto.Gophkeeper_UpdateFile_FullMethodName:              "file",
+	proto.Gophkeeper_GetFiles_FullMethodName:                "file",
+	proto.Gophkeeper_EnrollTOTP_FullMethodName:              "totp",
+	proto.Gophkeeper_ConfirmTOTP_FullMethodName:             "totp",
+	proto.Gophkeeper_DisableTOTP_FullMethodName:             "totp",
+	proto.Gophkeeper_RegenerateRecoveryCodes_FullMethodName: "totp",
+	proto.Gophkeeper_RefreshToken_FullMethodName:            "session",
+	proto.Gophkeeper_Logout_FullMethodName:                  "session",
+	proto.Gophkeeper_ListSessions_FullMethodName:            "session",
+	proto.Gophkeeper_RevokeSession_FullMethodName:           "session",
+	proto.Gophkeeper_RekeyVault_FullMethodName:              "vault",
+	proto.Gophkeeper_GetAuditLog_FullMethodName:             "audit",
+	proto.Gophkeeper_SetSharingKeys_FullMethodName:          "share",
+	proto.Gophkeeper_GetSharingKeys_FullMethodName:          "share",
+	proto.Gophkeeper_GetPublicKey_FullMethodName:            "share",
+	proto.Gophkeeper_GetItemShares_FullMethodName:           "share",
+	proto.Gophkeeper_ShareItem_FullMethodName:               "share",
+	proto.Gophkeeper_ListSharedWithMe_FullMethodName:        "share",
+	proto.Gophkeeper_RevokeShare_FullMethodName:             "share",
+	proto.Gophkeeper_CreateOrganization_FullMethodName:      "org",
+	proto.Gophkeeper_ListOrganizations_FullMethodName:       "org",
+	proto.Gophkeeper_RemoveOrganization_FullMethodName:      "org",
+	proto.Gophkeeper_ListOrgMembers_FullMethodName:          "org",
+	proto.Gophkeeper_AddOrgMember_FullMethodName:            "org",
+	proto.Gophkeeper_SetOrgMemberRole_FullMethodName:        "org",
+	proto.Gophkeeper_RemoveOrgMember_FullMethodName:         "org",
+	proto.Gophkeeper_GetOrgKey_FullMethodName:               "org",
+	proto.Gophkeeper_CreateCollection_FullMethodName:        "org",
+	proto.Gophkeeper_ListCollections_FullMethodName:         "org",
+	proto.Gophkeeper_RemoveCollection_FullMethodName:        "org",
+	proto.Gophkeeper_CreateFolder_FullMethodName:            "folder",
+	proto.Gophkeeper_RenameFolder_FullMethodName:            "folder",
+	proto.Gophkeeper_MoveFolder_FullMethodName:              "folder",
+	proto.Gophkeeper_RemoveFolder_FullMethodName:            "folder",
+	proto.Gophkeeper_GetFolders_FullMethodName:              "folder",
+	proto.Gophkeeper_SetItemFolder_FullMethodName:           "folder",
+	proto.Gophkeeper_CreateTag_FullMethodName:               "tag",
+	proto.Gophkeeper_RenameTag_FullMethodName:               "tag",
+	proto.Gophkeeper_RemoveTag_FullMethodName:               "tag",
+	proto.Gophkeeper_GetTags_FullMethodName:                 "tag",
+	proto.Gophkeeper_TagItem_FullMethodName:                 "tag",
+	proto.Gophkeeper_UntagItem_FullMethodName:               "tag",
+}
+
+// auditItemIDFields are the request fields identifying the item of a call.
+var auditItemIDFields = []protoreflect.Name{"id", "file_name", "item_id", "collection_id", "org_id"}
+
+// auditItemFields are the request fields containing the item of a call, the "id" field of the item
+// identifies it before the auditItemIDFields.
+var auditItemFields = []protoreflect.Name{"card", "note", "credentials", "item"}
+
+// auditSubject is the user of an audited call. The inner interceptors and the handlers fill it in
+// when they authenticate the user.
+type auditSubject struct {
+	userID    int64
+	sessionID int64
+}
+
+// SetAuditUser records the user and the session of the call in the audit log. The calls with a token
+// are attributed to its user automatically; the handlers authenticating the user by other means,
+// like Authorize, call it themselves.
+func SetAuditUser(ctx context.Context, userID, sessionID int64) {
+	if s, ok := ctx.Value(auditSubjectKey).(*auditSubject); ok {
+		s.userID = userID
+		s.sessionID = sessionID
+	}
+}
+
+// Audit returns a gRPC unary server interceptor that records every call in the audit log.
+//
+// Parameters:
+//   - store: An AuditStore persisting the audit log.
+//
+// The event records the user and the session of the call (set by ValidateToken or SetAuditUser, so
+// the interceptor has to precede them in the chain), the method, the type of the item and its ID taken
+// from the "id" field of the item the request carries, like the card of UpdateBankCard, or else from the first set
+// of the "id", "file_name", "item_id", "collection_id" and "org_id" fields of the request,
+// the peer address, the subject of the client certificate (set by ClientCertificate, which has to precede
+// the interceptor in the chain) and the status code of the call.
+// The calls rejected by the other interceptors are recorded too.
+//
+// Returns:
+//   - A function that implements the gRPC UnaryServerInterceptor signature.
+//
+// Errors of the store are logged and don't fail the call.
+func Audit(store AuditStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		subject := &auditSubject{}
+		resp, err := handler(context.WithValue(ctx, auditSubjectKey, subject), req)
+		recordAuditEvent(ctx, store, info.FullMethod, subject, auditItemID(req), err)
+		return resp, err
+	}
+}
+
+// AuditStream returns a gRPC stream server interceptor that records every stream in the audit log,
+// it is the stream counterpart of Audit. The item ID is taken from the first message received from the client.
+func AuditStream(store AuditStore) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		subject := &auditSubject{}
+		var itemID string
+		stream := newWrappedStream(ss)
+		stream.ctx = context.WithValue(ss.Context(), auditSubjectKey, subject)
+		stream.check = func(m interface{}) error {
+			if itemID == "" {
+				itemID = auditItemID(m)
+			}
+			return nil
+		}
+		err := handler(srv, stream)
+		recordAuditEvent(ss.Context(), store, info.FullMethod, subject, itemID, err)
+		return err
+	}
+}
+
+// recordAuditEvent appends the event of a completed call to the audit log. The event is stored even if
+// the call has been canceled by the client.
+func recordAuditEvent(ctx context.Context, store AuditStore, method string, subject *auditSubject, itemID string, err error) {
+	event := &model.AuditEvent{
+		UserID:    subject.userID,
+		SessionID: subject.sessionID,
+		Method:    method,
+		ItemType:  auditItemTypes[method],
+		ItemID:    itemID,
+		Outcome:   status.Code(err).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.PeerAddress = p.Addr.String()
+	}
+	event.ClientSubject, _ = ctx.Value(ClientSubject).(string)
+	if err = store.AddAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		logger.Log.Error("error add audit event", zap.String("method", method), zap.Error(err))
+	}
+}
+
+// auditItemID returns the ID of the item a request operates on, or an empty string if it has none.
+func auditItemID(req interface{}) string {
+	msg, ok := req.(protobuf.Message)
+	if !ok {
+		return ""
+	}
+	m := msg.ProtoReflect()
+	for _, name := range auditItemFields {
+		field := m.Descriptor().Fields().ByName(name)
+		if field == nil || field.Message() == nil || !m.Has(field) {
+			continue
+		}
+		item := m.Get(field).Message()
+		if id := item.Descriptor().Fields().ByName("id"); id != nil && item.Has(id) {
+			return fmt.Sprint(item.Get(id).Interface())
+		}
+	}
+	for _, name := range auditItemIDFields {
+		if field := m.Descriptor().Fields().ByName(name); field != nil && m.Has(field) {
+			return fmt.Sprint(m.Get(field).Interface())
+		}
+	}
+	return ""
+}
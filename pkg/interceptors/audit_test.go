@@ -0,0 +1,171 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/internal/model"
+	pkgJwt "github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// memoryAuditStore is an AuditStore keeping the events in memory.
+type memoryAuditStore struct {
+	err    error
+	events []*model.AuditEvent
+}
+
+func (m *memoryAuditStore) AddAuditEvent(_ context.Context, event *model.AuditEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+// chainUnary calls the interceptors in order, like grpc.ChainUnaryInterceptor.
+func chainUnary(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	secretKey := "my_secret_key"
+	store := &memoryAuditStore{}
+	interceptor := chainUnary(Audit(store), ValidateToken(pkgJwt.NewHMACKeySet(secretKey), nil))
+	info := &grpc.UnaryServerInfo{FullMethod: proto.Gophkeeper_GetBankCard_FullMethodName}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	tokenString, err := pkgJwt.BuildJWTString(secretKey, 123, 7)
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", tokenString))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000}})
+
+	t.Run("successful call", func(t *testing.T) {
+		_, err := interceptor(ctx, &proto.GetBankCardRequest{Id: "42"}, info, ok)
+		require.NoError(t, err)
+
+		require.Len(t, store.events, 1)
+		e := store.events[0]
+		assert.Equal(t, int64(123), e.UserID)
+		assert.Equal(t, int64(7), e.SessionID)
+		assert.Equal(t, proto.Gophkeeper_GetBankCard_FullMethodName, e.Method)
+		assert.Equal(t, "card", e.ItemType)
+		assert.Equal(t, "42", e.ItemID)
+		assert.Equal(t, "127.0.0.1:5000", e.PeerAddress)
+		assert.Equal(t, codes.OK.String(), e.Outcome)
+	})
+
+	t.Run("rejected call", func(t *testing.T) {
+		store.events = nil
+		_, err := interceptor(context.Background(), &proto.GetBankCardRequest{Id: "42"}, info, ok)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		require.Len(t, store.events, 1)
+		assert.Zero(t, store.events[0].UserID)
+		assert.Equal(t, codes.PermissionDenied.String(), store.events[0].Outcome)
+	})
+
+	t.Run("failed call", func(t *testing.T) {
+		store.events = nil
+		_, err := interceptor(ctx, &proto.GetBankCardRequest{Id: "42"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		require.Len(t, store.events, 1)
+		assert.Equal(t, int64(123), store.events[0].UserID)
+		assert.Equal(t, codes.NotFound.String(), store.events[0].Outcome)
+	})
+
+	t.Run("user set by handler", func(t *testing.T) {
+		store.events = nil
+		authorize := &grpc.UnaryServerInfo{FullMethod: proto.Gophkeeper_Authorize_FullMethodName}
+		_, err := interceptor(context.Background(), &proto.AuthorizeRequest{}, authorize, func(ctx context.Context, req interface{}) (interface{}, error) {
+			SetAuditUser(ctx, 5, 9)
+			return nil, status.Error(codes.PermissionDenied, "invalid user login or password")
+		})
+		assert.Error(t, err)
+
+		require.Len(t, store.events, 1)
+		assert.Equal(t, int64(5), store.events[0].UserID)
+		assert.Equal(t, int64(9), store.events[0].SessionID)
+		assert.Equal(t, "user", store.events[0].ItemType)
+		assert.Empty(t, store.events[0].ItemID)
+	})
+
+	t.Run("client certificate subject", func(t *testing.T) {
+		store.events = nil
+		mtls := context.WithValue(ctx, ClientSubject, "CN=alice,O=dev,C=US")
+		_, err := interceptor(mtls, &proto.GetBankCardRequest{Id: "42"}, info, ok)
+		require.NoError(t, err)
+
+		require.Len(t, store.events, 1)
+		assert.Equal(t, "CN=alice,O=dev,C=US", store.events[0].ClientSubject)
+	})
+
+	t.Run("store error doesn't fail the call", func(t *testing.T) {
+		failing := &memoryAuditStore{err: errors.New("db is down")}
+		resp, err := Audit(failing)(ctx, &proto.GetNoteRequest{Id: "1"}, info, ok)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+}
+
+func TestAuditStream(t *testing.T) {
+	secretKey := "my_secret_key"
+	store := &memoryAuditStore{}
+	audit := AuditStream(store)
+	validate := ValidateTokenStream(pkgJwt.NewHMACKeySet(secretKey), nil)
+	info := &grpc.StreamServerInfo{FullMethod: proto.Gophkeeper_Upload_FullMethodName, IsClientStream: true}
+
+	tokenString, err := pkgJwt.BuildJWTString(secretKey, 123, 7)
+	require.NoError(t, err)
+	ss := &fakeServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", tokenString)),
+		messages: []protobuf.Message{
+			&proto.FileUploadRequest{FileName: "photo.png", Chunk: []byte("first")},
+			&proto.FileUploadRequest{Chunk: []byte("second")},
+		},
+	}
+	err = audit(nil, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return validate(srv, ss, info, receiveAll)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, store.events, 1)
+	e := store.events[0]
+	assert.Equal(t, int64(123), e.UserID)
+	assert.Equal(t, "file", e.ItemType)
+	assert.Equal(t, "photo.png", e.ItemID)
+	assert.Equal(t, codes.OK.String(), e.Outcome)
+}
+
+func TestAuditItemID(t *testing.T) {
+	assert.Equal(t, "42", auditItemID(&proto.GetBankCardRequest{Id: "42", CollectionId: 3}))
+	assert.Equal(t, "5", auditItemID(&proto.UpdateBankCardRequest{Card: &proto.BankCard{Id: 5}, CollectionId: 3}))
+	assert.Equal(t, "3", auditItemID(&proto.AddBankCardRequest{Card: &proto.BankCard{Number: "1"}, CollectionId: 3}))
+	assert.Equal(t, "", auditItemID(&proto.AddNoteRequest{Note: &proto.Note{Text: "text"}}))
+	assert.Equal(t, "report.pdf", auditItemID(&proto.UpdateFileRequest{FileName: "report.pdf", NewFileName: "new.pdf"}))
+}
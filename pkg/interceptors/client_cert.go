@@ -0,0 +1,66 @@
+// Package interceptors provides gRPC interceptors for handling requests and responses.
+//
+// This package includes the ClientCertificate function, which makes the subject of the
+// client certificate verified in the mutual TLS mode available to the handlers.
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientSubject is the context key of the subject of the verified client certificate, e.g.
+// "CN=alice,O=dev,C=US". It is only set when the server runs in the mutual TLS mode.
+const ClientSubject contextKey = "ClientSubject"
+
+// ClientCertificate is a gRPC unary server interceptor that stores the subject of the client
+// certificate in the request context under the ClientSubject key.
+//
+// Parameters:
+//   - ctx: A context.Context for managing request-scoped values and cancellation.
+//   - req: An interface{} representing the incoming request message.
+//   - info: A pointer to grpc.UnaryServerInfo containing information about the method being called.
+//   - handler: A grpc.UnaryHandler that processes the request and returns a response.
+//
+// Returns:
+//   - An interface{} containing the response from the handler.
+//   - An error if the handler returns an error.
+//
+// Only certificates verified during the TLS handshake are taken into account, so the interceptor
+// doesn't reject anything itself: in the mutual TLS mode connections without a valid client
+// certificate are refused by the handshake, in the regular mode the context is left untouched.
+func ClientCertificate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if subject, ok := clientSubject(ctx); ok {
+		ctx = context.WithValue(ctx, ClientSubject, subject)
+	}
+	return handler(ctx, req)
+}
+
+// ClientCertificateStream is a gRPC stream server interceptor that stores the subject of the client
+// certificate in the stream context under the ClientSubject key, it is the stream counterpart of
+// ClientCertificate.
+func ClientCertificateStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	subject, ok := clientSubject(ss.Context())
+	if !ok {
+		return handler(srv, ss)
+	}
+	stream := newWrappedStream(ss)
+	stream.ctx = context.WithValue(ss.Context(), ClientSubject, subject)
+	return handler(srv, stream)
+}
+
+// clientSubject returns the subject of the verified client certificate of the gRPC call.
+func clientSubject(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.String(), true
+}
@@ -40,6 +40,22 @@ func LoggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnarySe
 	itf, err := handler(ctx, req)
 	duration := time.Since(startTime)
 
+	logRequest(ctx, info.FullMethod, duration, err)
+	return itf, err
+}
+
+// LoggingStreamInterceptor is a gRPC stream server interceptor that logs the details of
+// streaming requests, it is the stream counterpart of LoggingInterceptor. The duration
+// covers the whole stream, from the first message to the last one.
+func LoggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	startTime := time.Now()
+	err := handler(srv, ss)
+	logRequest(ss.Context(), info.FullMethod, time.Since(startTime), err)
+	return err
+}
+
+// logRequest logs the method, the duration and the response status of a request.
+func logRequest(ctx context.Context, method string, duration time.Duration, err error) {
 	var respStatus string
 	if err != nil {
 		st, ok := status.FromError(err)
@@ -51,14 +67,16 @@ func LoggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnarySe
 	} else {
 		respStatus = codes.OK.String()
 	}
-	logger.Log.Info(
-		"Request data",
-		zap.String("method", info.FullMethod),
+	fields := []zap.Field{
+		zap.String("method", method),
 		zap.Duration("duration", duration),
-	)
+	}
+	if subject, ok := ctx.Value(ClientSubject).(string); ok {
+		fields = append(fields, zap.String("client", subject))
+	}
+	logger.Log.Info("Request data", fields...)
 	logger.Log.Info(
 		"Response data",
 		zap.String("status", respStatus),
 	)
-	return itf, err
 }
@@ -0,0 +1,186 @@
+// Package interceptors provides gRPC interceptors for handling requests and responses.
+//
+// This package includes the RateLimit function, which protects the authentication methods
+// from password guessing and login enumeration.
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+// AttemptStore persists the failed authentication attempts by key, so that lockouts survive server restarts.
+type AttemptStore interface {
+	// GetAuthFailures returns the number of failed attempts for the key and the time elapsed since the last one.
+	GetAuthFailures(ctx context.Context, key string) (int, time.Duration, error)
+	// AddAuthFailure records a failed attempt for the key. Failures older than window are forgotten.
+	AddAuthFailure(ctx context.Context, key string, window time.Duration) error
+	// ResetAuthFailures forgets the failed attempts for the key.
+	ResetAuthFailures(ctx context.Context, key string) error
+}
+
+// RateLimits configures the brute-force protection of the authentication methods.
+//
+// Every failed attempt is counted for the login and for the peer address. After FreeAttempts failures
+// every next attempt is delayed exponentially, starting from BackoffBase up to BackoffMax. After
+// MaxLoginFailures failures for a login (or MaxAddressFailures for an address) the login (address)
+// is locked out for Lockout. Failures are forgotten Lockout after the last one.
+type RateLimits struct {
+	FreeAttempts       int
+	MaxLoginFailures   int
+	MaxAddressFailures int
+	BackoffBase        time.Duration
+	BackoffMax         time.Duration
+	Lockout            time.Duration
+}
+
+// DefaultRateLimits are the brute-force protection limits used when no limits are configured.
+var DefaultRateLimits = &RateLimits{
+	FreeAttempts:       3,
+	MaxLoginFailures:   10,
+	MaxAddressFailures: 50,
+	BackoffBase:        time.Second,
+	BackoffMax:         time.Minute,
+	Lockout:            15 * time.Minute,
+}
+
+// otpKeyPrefix is the prefix of the keys counting the failed attempts with a second factor code for a login.
+// The counter is only reset by a successful authorization that issues a token, so a correct password without
+// a code doesn't reset it.
+const otpKeyPrefix = "otp:"
+
+// wait returns how long the next attempt has to be delayed after the given number of failures,
+// the last of which happened elapsed ago.
+func (l *RateLimits) wait(failures, maxFailures int, elapsed time.Duration) time.Duration {
+	if failures == 0 || elapsed >= l.Lockout {
+		return 0
+	}
+	if failures >= maxFailures {
+		return l.Lockout - elapsed
+	}
+	if failures < l.FreeAttempts {
+		return 0
+	}
+	delay := l.BackoffMax
+	if shift := failures - l.FreeAttempts; shift < 62 && l.BackoffBase <= l.BackoffMax>>shift {
+		delay = l.BackoffBase << shift
+	}
+	return max(delay-elapsed, 0)
+}
+
+// RateLimit returns a gRPC unary server interceptor that limits failed attempts of the Authorize and
+// RegisterUser methods.
+//
+// Authorize attempts failed with PermissionDenied (a wrong password or second factor code) are counted
+// for the login and for the peer address, the attempts with a second factor code are counted for the login
+// separately as well. An authorization that issues a token resets the counters of the login, a second factor
+// challenge doesn't reset them.
+// RegisterUser attempts failed with AlreadyExists are counted for the peer address, which slows down
+// the enumeration of existing logins. While an attempt is delayed or the login (address) is locked out,
+// requests are rejected with ResourceExhausted without calling the handler.
+//
+// Parameters:
+//   - store: An AttemptStore persisting the failed attempts.
+//   - limits: The limits of failed attempts. If nil, DefaultRateLimits are used.
+//
+// Returns:
+//   - A function that implements the gRPC UnaryServerInterceptor signature.
+//
+// Errors of the store are logged and don't block the authentication.
+func RateLimit(store AttemptStore, limits *RateLimits) grpc.UnaryServerInterceptor {
+	if limits == nil {
+		limits = DefaultRateLimits
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var keys []string
+		var maxFailures []int
+		var failureCode codes.Code
+		switch r := req.(type) {
+		case *proto.AuthorizeRequest:
+			if info.FullMethod != proto.Gophkeeper_Authorize_FullMethodName || r.Credentials == nil {
+				return handler(ctx, req)
+			}
+			keys = []string{"login:" + r.Credentials.Login, "addr:" + peerHost(ctx)}
+			maxFailures = []int{limits.MaxLoginFailures, limits.MaxAddressFailures}
+			if r.OtpCode != "" {
+				keys = append(keys, otpKeyPrefix+r.Credentials.Login)
+				maxFailures = append(maxFailures, limits.MaxLoginFailures)
+			}
+			failureCode = codes.PermissionDenied
+		case *proto.RegisterUserRequest:
+			if info.FullMethod != proto.Gophkeeper_RegisterUser_FullMethodName {
+				return handler(ctx, req)
+			}
+			keys = []string{"addr:" + peerHost(ctx)}
+			maxFailures = []int{limits.MaxAddressFailures}
+			failureCode = codes.AlreadyExists
+		default:
+			return handler(ctx, req)
+		}
+
+		var wait time.Duration
+		for i, key := range keys {
+			failures, elapsed, err := store.GetAuthFailures(ctx, key)
+			if err != nil {
+				logger.Log.Error("error get failed attempts", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			wait = max(wait, limits.wait(failures, maxFailures[i], elapsed))
+		}
+		if wait > 0 {
+			logger.Log.Warn("too many failed attempts", zap.Strings("keys", keys), zap.Duration("wait", wait))
+			return nil, status.Errorf(codes.ResourceExhausted, "too many failed attempts, retry in %s", retryAfter(wait))
+		}
+
+		resp, err := handler(ctx, req)
+		switch status.Code(err) {
+		case failureCode:
+			for _, key := range keys {
+				if errStore := store.AddAuthFailure(ctx, key, limits.Lockout); errStore != nil {
+					logger.Log.Error("error add failed attempt", zap.String("key", key), zap.Error(errStore))
+				}
+			}
+		case codes.OK:
+			// A second factor challenge only proves the password, the counters are reset once a token is issued.
+			if r, ok := resp.(*proto.AuthorizeResponse); ok && r.Token != "" {
+				login := req.(*proto.AuthorizeRequest).Credentials.Login
+				for _, key := range []string{keys[0], otpKeyPrefix + login} {
+					if errStore := store.ResetAuthFailures(ctx, key); errStore != nil {
+						logger.Log.Error("error reset failed attempts", zap.String("key", key), zap.Error(errStore))
+					}
+				}
+			}
+		}
+		return resp, err
+	}
+}
+
+// peerHost returns the host of the client of a gRPC call without the port, or an empty string if it is unknown.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// retryAfter formats the delay rounded up to whole seconds.
+func retryAfter(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,204 @@
+package interceptors
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/Vidkin/gophkeeper/proto"
+)
+
+type attempt struct {
+	failures int
+	last     time.Time
+}
+
+type memoryAttemptStore struct {
+	attempts map[string]*attempt
+	now      time.Time
+}
+
+func newMemoryAttemptStore() *memoryAttemptStore {
+	return &memoryAttemptStore{attempts: make(map[string]*attempt), now: time.Now()}
+}
+
+func (m *memoryAttemptStore) GetAuthFailures(_ context.Context, key string) (int, time.Duration, error) {
+	a, ok := m.attempts[key]
+	if !ok {
+		return 0, 0, nil
+	}
+	return a.failures, m.now.Sub(a.last), nil
+}
+
+func (m *memoryAttemptStore) AddAuthFailure(_ context.Context, key string, window time.Duration) error {
+	a, ok := m.attempts[key]
+	if !ok || m.now.Sub(a.last) > window {
+		a = &attempt{}
+		m.attempts[key] = a
+	}
+	a.failures++
+	a.last = m.now
+	return nil
+}
+
+func (m *memoryAttemptStore) ResetAuthFailures(_ context.Context, key string) error {
+	delete(m.attempts, key)
+	return nil
+}
+
+func TestRateLimits_wait(t *testing.T) {
+	limits := DefaultRateLimits
+	tests := []struct {
+		name     string
+		failures int
+		elapsed  time.Duration
+		want     time.Duration
+	}{
+		{name: "no failures", failures: 0, want: 0},
+		{name: "free attempts", failures: 2, want: 0},
+		{name: "first backoff", failures: 3, want: time.Second},
+		{name: "backoff doubles", failures: 5, want: 4 * time.Second},
+		{name: "backoff elapsed", failures: 5, elapsed: 5 * time.Second, want: 0},
+		{name: "backoff is capped", failures: 9, want: time.Minute},
+		{name: "lockout", failures: 10, elapsed: 5 * time.Minute, want: 10 * time.Minute},
+		{name: "lockout expired", failures: 10, elapsed: 15 * time.Minute, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, limits.wait(tt.failures, limits.MaxLoginFailures, tt.elapsed))
+		})
+	}
+}
+
+func TestRateLimit_Authorize(t *testing.T) {
+	store := newMemoryAttemptStore()
+	interceptor := RateLimit(store, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: proto.Gophkeeper_Authorize_FullMethodName}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5555}})
+	req := &proto.AuthorizeRequest{Credentials: &proto.Credentials{Login: "user", Password: "wrong"}}
+
+	var calls int
+	deny := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, status.Error(codes.PermissionDenied, "invalid login or password")
+	}
+	allow := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &proto.AuthorizeResponse{Token: "token"}, nil
+	}
+
+	for range DefaultRateLimits.FreeAttempts {
+		_, err := interceptor(ctx, req, info, deny)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+	assert.Equal(t, DefaultRateLimits.FreeAttempts, store.attempts["login:user"].failures)
+	assert.Equal(t, DefaultRateLimits.FreeAttempts, store.attempts["addr:10.0.0.1"].failures)
+
+	t.Run("attempts are delayed", func(t *testing.T) {
+		calls = 0
+		_, err := interceptor(ctx, req, info, allow)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, err.Error(), "retry in 1s")
+		assert.Zero(t, calls)
+	})
+
+	t.Run("success resets the login", func(t *testing.T) {
+		store.now = store.now.Add(time.Second)
+		resp, err := interceptor(ctx, req, info, allow)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.NotContains(t, store.attempts, "login:user")
+		assert.Contains(t, store.attempts, "addr:10.0.0.1")
+	})
+
+	t.Run("login is locked out", func(t *testing.T) {
+		for range DefaultRateLimits.MaxLoginFailures {
+			store.now = store.now.Add(DefaultRateLimits.BackoffMax)
+			_, err := interceptor(ctx, req, info, deny)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		}
+		store.now = store.now.Add(DefaultRateLimits.BackoffMax)
+		_, err := interceptor(ctx, req, info, allow)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		store.now = store.now.Add(DefaultRateLimits.Lockout)
+		_, err = interceptor(ctx, req, info, allow)
+		assert.NoError(t, err)
+	})
+
+	t.Run("second factor challenge doesn't reset the login", func(t *testing.T) {
+		challenge := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &proto.AuthorizeResponse{SecondFactorRequired: true}, nil
+		}
+		otpReq := &proto.AuthorizeRequest{Credentials: req.Credentials, OtpCode: "000000"}
+		for range DefaultRateLimits.MaxLoginFailures {
+			store.now = store.now.Add(DefaultRateLimits.BackoffMax)
+			_, err := interceptor(ctx, req, info, challenge)
+			require.NoError(t, err)
+			_, err = interceptor(ctx, otpReq, info, deny)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		}
+		assert.Equal(t, DefaultRateLimits.MaxLoginFailures, store.attempts["login:user"].failures)
+		assert.Equal(t, DefaultRateLimits.MaxLoginFailures, store.attempts["otp:user"].failures)
+
+		store.now = store.now.Add(DefaultRateLimits.BackoffMax)
+		calls = 0
+		_, err := interceptor(ctx, req, info, challenge)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		_, err = interceptor(ctx, otpReq, info, allow)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Zero(t, calls)
+
+		store.now = store.now.Add(DefaultRateLimits.Lockout)
+		_, err = interceptor(ctx, otpReq, info, allow)
+		require.NoError(t, err)
+		assert.NotContains(t, store.attempts, "login:user")
+		assert.NotContains(t, store.attempts, "otp:user")
+	})
+}
+
+func TestRateLimit_RegisterUser(t *testing.T) {
+	store := newMemoryAttemptStore()
+	interceptor := RateLimit(store, &RateLimits{
+		FreeAttempts:       1,
+		MaxLoginFailures:   2,
+		MaxAddressFailures: 2,
+		BackoffBase:        time.Second,
+		BackoffMax:         time.Second,
+		Lockout:            time.Minute,
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: proto.Gophkeeper_RegisterUser_FullMethodName}
+	req := &proto.RegisterUserRequest{Credentials: &proto.Credentials{Login: "user", Password: "password"}}
+	exists := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.AlreadyExists, "user already exists")
+	}
+
+	_, err := interceptor(context.Background(), req, info, exists)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Equal(t, 1, store.attempts["addr:"].failures)
+	assert.NotContains(t, store.attempts, "login:user")
+
+	_, err = interceptor(context.Background(), req, info, exists)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimit_OtherMethods(t *testing.T) {
+	store := newMemoryAttemptStore()
+	store.attempts["addr:"] = &attempt{failures: 100, last: store.now}
+	interceptor := RateLimit(store, nil)
+
+	resp, err := interceptor(context.Background(), &proto.GetFilesRequest{}, &grpc.UnaryServerInfo{FullMethod: proto.Gophkeeper_GetFiles_FullMethodName},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
@@ -0,0 +1,168 @@
+// Package interceptors provides gRPC interceptors for handling requests and responses.
+//
+// This package includes the SignatureInterceptor function, which verifies the integrity and
+// freshness of incoming requests by checking their HMAC-SHA256 signatures.
+package interceptors
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Vidkin/gophkeeper/internal/logger"
+	"github.com/Vidkin/gophkeeper/pkg/signature"
+)
+
+// SignatureFieldName is the name of the field carrying the signature of a message sent by the client in a stream.
+const SignatureFieldName = "signature"
+
+// SignatureInterceptor returns a gRPC unary server interceptor that verifies the signatures of
+// incoming requests.
+//
+// Parameters:
+//   - key: A string representing the signing key. If the key is empty, the interceptor will skip
+//     signature verification.
+//   - skew: The maximum difference between the request timestamp and the server clock.
+//
+// The interceptor extracts the signature, its version, the timestamp and the nonce from the
+// metadata of the incoming context, marshals the request and verifies the HMAC-SHA256 over the
+// method name, the timestamp, the nonce and the request (see signature.Sign). Requests outside of
+// the skew window and requests with an already used nonce are rejected, so captured requests
+// can't be replayed.
+//
+// Returns:
+//   - A function that implements the gRPC UnaryHandler signature, which processes the
+//     request if the signature is valid, or returns an InvalidArgument error otherwise.
+func SignatureInterceptor(key string, skew time.Duration) grpc.UnaryServerInterceptor {
+	verifier := signature.NewVerifier(key, skew)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "failed to get proto.Message")
+		}
+		body, err := proto.Marshal(msg)
+		if err != nil {
+			logger.Log.Error("failed to marshal request", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, "failed to marshal request")
+		}
+		if _, err = verifyRequest(ctx, verifier, info.FullMethod, body); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// SignatureStreamInterceptor returns a gRPC stream server interceptor that verifies the signatures
+// of streaming requests, it is the stream counterpart of SignatureInterceptor.
+//
+// Parameters:
+//   - key: A string representing the signing key. If the key is empty, the interceptor will skip
+//     signature verification.
+//   - skew: The maximum difference between the request timestamp and the server clock.
+//
+// The metadata is sent once per stream, before any message, so it signs the stream itself with an
+// empty body. Every message received from the client carries its own signature in the "signature"
+// field, computed with the nonce of the stream and the message index over the message with an empty
+// signature (see signature.SignMessage).
+//
+// Returns:
+//   - A function that implements the gRPC StreamServerInterceptor signature, which rejects the
+//     stream if its signature is invalid and fails it with an InvalidArgument error as soon as a
+//     message doesn't match its signature.
+func SignatureStreamInterceptor(key string, skew time.Duration) grpc.StreamServerInterceptor {
+	verifier := signature.NewVerifier(key, skew)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if key == "" {
+			return handler(srv, ss)
+		}
+
+		nonce, err := verifyRequest(ss.Context(), verifier, info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+
+		var index int
+		stream := newWrappedStream(ss)
+		stream.check = func(m interface{}) error {
+			msg, ok := m.(proto.Message)
+			if !ok {
+				return status.Errorf(codes.Internal, "failed to get proto.Message")
+			}
+			field := msg.ProtoReflect().Descriptor().Fields().ByName(SignatureFieldName)
+			if field == nil || field.Kind() != protoreflect.BytesKind {
+				logger.Log.Error("streamed message has no signature field", zap.String("method", info.FullMethod))
+				return status.Errorf(codes.Internal, "failed to verify signature")
+			}
+			sig := msg.ProtoReflect().Get(field).Bytes()
+			if len(sig) == 0 {
+				return status.Error(codes.InvalidArgument, "missing signature")
+			}
+
+			unsigned := proto.Clone(msg)
+			unsigned.ProtoReflect().Clear(field)
+			body, err := proto.Marshal(unsigned)
+			if err != nil {
+				logger.Log.Error("failed to marshal request", zap.Error(err))
+				return status.Errorf(codes.Internal, "failed to marshal request")
+			}
+			if err = verifier.VerifyMessage(nonce, index, body, sig); err != nil {
+				logger.Log.Error("error verify message signature", zap.String("method", info.FullMethod), zap.Int("index", index))
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			index++
+			return nil
+		}
+		return handler(srv, stream)
+	}
+}
+
+// verifyRequest verifies the signature sent in the metadata of the incoming context.
+//
+// Returns:
+//   - The nonce of the request.
+//   - An InvalidArgument status error if the signature is missing or the request is rejected.
+func verifyRequest(ctx context.Context, verifier *signature.Verifier, method string, body []byte) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	sigEnc := get(signature.SignatureKey)
+	if sigEnc == "" {
+		return "", status.Error(codes.InvalidArgument, "missing signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigEnc)
+	if err != nil {
+		logger.Log.Error("error decode signature from base64 string", zap.Error(err))
+		return "", status.Error(codes.InvalidArgument, "invalid signature")
+	}
+
+	nonce := get(signature.NonceKey)
+	err = verifier.Verify(get(signature.VersionKey), method, get(signature.TimestampKey), nonce, body, sig)
+	if err != nil {
+		if errors.Is(err, signature.ErrReplayed) || errors.Is(err, signature.ErrExpired) {
+			logger.Log.Warn("rejected request", zap.String("method", method), zap.Error(err))
+		} else {
+			logger.Log.Error("error verify signature", zap.String("method", method), zap.Error(err))
+		}
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nonce, nil
+}
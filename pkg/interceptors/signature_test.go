@@ -0,0 +1,137 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Vidkin/gophkeeper/pkg/signature"
+	gkProto "github.com/Vidkin/gophkeeper/proto"
+)
+
+type MockHandlerSignature struct {
+	mock.Mock
+}
+
+func (m *MockHandlerSignature) Invoke(ctx context.Context, req interface{}) (interface{}, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0), args.Error(1)
+}
+
+// signedContext returns an incoming context with the signature of the request.
+func signedContext(t *testing.T, key, method string, timestamp time.Time, nonce string, req proto.Message) context.Context {
+	var body []byte
+	if req != nil {
+		var err error
+		body, err = proto.Marshal(req)
+		require.NoError(t, err)
+	}
+	sig := signature.Sign(key, method, timestamp.Unix(), nonce, body)
+	md := metadata.Pairs(
+		signature.VersionKey, signature.Version,
+		signature.TimestampKey, strconv.FormatInt(timestamp.Unix(), 10),
+		signature.NonceKey, nonce,
+		signature.SignatureKey, base64.StdEncoding.EncodeToString(sig))
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestSignatureInterceptor(t *testing.T) {
+	key := "test-key"
+	interceptor := SignatureInterceptor(key, time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: gkProto.Gophkeeper_RemoveNote_FullMethodName}
+	echo := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		req := &gkProto.RemoveNoteRequest{Id: "1"}
+		ctx := signedContext(t, key, info.FullMethod, time.Now(), "nonce-valid", req)
+
+		mockHandler := new(MockHandlerSignature)
+		mockHandler.On("Invoke", ctx, req).Return(req, nil)
+
+		resp, err := interceptor(ctx, req, info, mockHandler.Invoke)
+
+		assert.NoError(t, err)
+		assert.Equal(t, req, resp)
+		mockHandler.AssertExpectations(t)
+	})
+
+	t.Run("replayed request", func(t *testing.T) {
+		req := &gkProto.RemoveNoteRequest{Id: "1"}
+		ctx := signedContext(t, key, info.FullMethod, time.Now(), "nonce-replayed", req)
+
+		_, err := interceptor(ctx, req, info, echo)
+		require.NoError(t, err)
+
+		resp, err := interceptor(ctx, req, info, echo)
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), signature.ErrReplayed.Error())
+	})
+
+	t.Run("expired request", func(t *testing.T) {
+		req := &gkProto.RemoveNoteRequest{Id: "1"}
+		ctx := signedContext(t, key, info.FullMethod, time.Now().Add(-2*time.Minute), "nonce-expired", req)
+
+		_, err := interceptor(ctx, req, info, echo)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), signature.ErrExpired.Error())
+	})
+
+	t.Run("signature of another method", func(t *testing.T) {
+		req := &gkProto.RemoveNoteRequest{Id: "1"}
+		ctx := signedContext(t, key, gkProto.Gophkeeper_GetNote_FullMethodName, time.Now(), "nonce-method", req)
+
+		_, err := interceptor(ctx, req, info, echo)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), signature.ErrInvalidSignature.Error())
+	})
+
+	t.Run("tampered request", func(t *testing.T) {
+		ctx := signedContext(t, key, info.FullMethod, time.Now(), "nonce-tampered", &gkProto.RemoveNoteRequest{Id: "1"})
+
+		_, err := interceptor(ctx, &gkProto.RemoveNoteRequest{Id: "2"}, info, echo)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), &gkProto.RemoveNoteRequest{Id: "1"}, info, echo)
+
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		req := &gkProto.RemoveNoteRequest{Id: "1"}
+		ctx := signedContext(t, key, info.FullMethod, time.Now(), "nonce-version", req)
+		md, _ := metadata.FromIncomingContext(ctx)
+		md.Set(signature.VersionKey, "v0")
+
+		_, err := interceptor(metadata.NewIncomingContext(context.Background(), md), req, info, echo)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), signature.ErrUnsupportedVersion.Error())
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		interceptor := SignatureInterceptor("", time.Minute)
+		req := &gkProto.RemoveNoteRequest{Id: "1"}
+
+		resp, err := interceptor(context.Background(), req, info, echo)
+
+		assert.NoError(t, err)
+		assert.Equal(t, req, resp)
+	})
+}
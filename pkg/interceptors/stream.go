@@ -0,0 +1,40 @@
+// Package interceptors provides gRPC interceptors for handling requests and responses.
+//
+// This package includes the wrappedStream type, which lets the stream interceptors pass values
+// to the handlers through the stream context and inspect the messages received from the client.
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// wrappedStream is a grpc.ServerStream with a replaced context and an optional check of every
+// received message.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	check func(m interface{}) error
+}
+
+// newWrappedStream wraps the stream without changing its context.
+func newWrappedStream(ss grpc.ServerStream) *wrappedStream {
+	return &wrappedStream{ServerStream: ss, ctx: ss.Context()}
+}
+
+// Context returns the context of the stream.
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// RecvMsg receives a message from the client and checks it, if the check is set.
+func (w *wrappedStream) RecvMsg(m interface{}) error {
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if w.check != nil {
+		return w.check(m)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pkgJwt "github.com/Vidkin/gophkeeper/pkg/jwt"
+	"github.com/Vidkin/gophkeeper/pkg/signature"
+	gkProto "github.com/Vidkin/gophkeeper/proto"
+)
+
+// fakeServerStream is a grpc.ServerStream receiving the queued messages.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []proto.Message
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(f.messages) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), f.messages[0])
+	f.messages = f.messages[1:]
+	return nil
+}
+
+// receiveAll is a stream handler receiving upload requests until the end of the stream.
+func receiveAll(_ interface{}, ss grpc.ServerStream) error {
+	for {
+		err := ss.RecvMsg(&gkProto.FileUploadRequest{})
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func signedChunk(t *testing.T, key, nonce string, index int, chunk []byte) *gkProto.FileUploadRequest {
+	req := &gkProto.FileUploadRequest{FileName: "file", FileSize: 10, Chunk: chunk}
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	req.Signature = signature.SignMessage(key, nonce, index, data)
+	return req
+}
+
+func TestSignatureStreamInterceptor(t *testing.T) {
+	key := "test-key"
+	interceptor := SignatureStreamInterceptor(key, time.Minute)
+	info := &grpc.StreamServerInfo{FullMethod: gkProto.Gophkeeper_Upload_FullMethodName, IsClientStream: true}
+
+	t.Run("every message is verified", func(t *testing.T) {
+		ss := &fakeServerStream{ctx: signedContext(t, key, info.FullMethod, time.Now(), "stream-ok", nil), messages: []proto.Message{
+			signedChunk(t, key, "stream-ok", 0, []byte("first")),
+			signedChunk(t, key, "stream-ok", 1, []byte("second")),
+		}}
+		assert.NoError(t, interceptor(nil, ss, info, receiveAll))
+	})
+
+	t.Run("tampered message", func(t *testing.T) {
+		tampered := signedChunk(t, key, "stream-tampered", 1, []byte("second"))
+		tampered.Chunk = []byte("SECOND")
+		ss := &fakeServerStream{ctx: signedContext(t, key, info.FullMethod, time.Now(), "stream-tampered", nil), messages: []proto.Message{
+			signedChunk(t, key, "stream-tampered", 0, []byte("first")),
+			tampered,
+		}}
+		err := interceptor(nil, ss, info, receiveAll)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), signature.ErrInvalidSignature.Error())
+	})
+
+	t.Run("reordered messages", func(t *testing.T) {
+		ss := &fakeServerStream{ctx: signedContext(t, key, info.FullMethod, time.Now(), "stream-reordered", nil), messages: []proto.Message{
+			signedChunk(t, key, "stream-reordered", 1, []byte("second")),
+			signedChunk(t, key, "stream-reordered", 0, []byte("first")),
+		}}
+		err := interceptor(nil, ss, info, receiveAll)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("message of another stream", func(t *testing.T) {
+		ss := &fakeServerStream{ctx: signedContext(t, key, info.FullMethod, time.Now(), "stream-new", nil), messages: []proto.Message{
+			signedChunk(t, key, "stream-ok", 0, []byte("first")),
+		}}
+		err := interceptor(nil, ss, info, receiveAll)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("missing message signature", func(t *testing.T) {
+		ss := &fakeServerStream{ctx: signedContext(t, key, info.FullMethod, time.Now(), "stream-unsigned", nil), messages: []proto.Message{
+			&gkProto.FileUploadRequest{FileName: "file", FileSize: 10, Chunk: []byte("first")},
+		}}
+		err := interceptor(nil, ss, info, receiveAll)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "missing signature")
+	})
+
+	t.Run("replayed stream", func(t *testing.T) {
+		ctx := signedContext(t, key, info.FullMethod, time.Now(), "stream-replayed", nil)
+		require.NoError(t, interceptor(nil, &fakeServerStream{ctx: ctx}, info, receiveAll))
+
+		called := false
+		err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(_ interface{}, ss grpc.ServerStream) error {
+			called = true
+			return nil
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.False(t, called)
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		ss := &fakeServerStream{ctx: context.Background(), messages: []proto.Message{
+			&gkProto.FileUploadRequest{FileName: "file", FileSize: 10, Chunk: []byte("first")},
+		}}
+		assert.NoError(t, SignatureStreamInterceptor("", time.Minute)(nil, ss, info, receiveAll))
+	})
+}
+
+func TestValidateTokenStream(t *testing.T) {
+	secretKey := "my_secret_key"
+	interceptor := ValidateTokenStream(pkgJwt.NewHMACKeySet(secretKey), nil)
+	info := &grpc.StreamServerInfo{FullMethod: gkProto.Gophkeeper_Download_FullMethodName}
+
+	t.Run("valid token", func(t *testing.T) {
+		tokenString, err := pkgJwt.BuildJWTString(secretKey, 123, 1)
+		require.NoError(t, err)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", tokenString))
+
+		var userID interface{}
+		err = interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(_ interface{}, ss grpc.ServerStream) error {
+			userID = ss.Context().Value(UserID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(123), userID)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		called := false
+		err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, info, func(_ interface{}, ss grpc.ServerStream) error {
+			called = true
+			return nil
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.False(t, called)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", "invalid.token.string"))
+		err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(_ interface{}, ss grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestLoggingStreamInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: gkProto.Gophkeeper_Upload_FullMethodName}
+	handlerErr := status.Error(codes.Internal, "internal error")
+
+	err := LoggingStreamInterceptor(nil, &fakeServerStream{ctx: context.Background()}, info, func(_ interface{}, ss grpc.ServerStream) error {
+		return handlerErr
+	})
+	assert.True(t, errors.Is(err, handlerErr))
+
+	err = LoggingStreamInterceptor(nil, &fakeServerStream{ctx: context.Background()}, info, receiveAll)
+	assert.NoError(t, err)
+}
+
+func TestClientCertificateStream_NoCertificate(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: gkProto.Gophkeeper_Upload_FullMethodName}
+	err := ClientCertificateStream(nil, &fakeServerStream{ctx: context.Background()}, info, func(_ interface{}, ss grpc.ServerStream) error {
+		_, ok := ss.Context().Value(ClientSubject).(string)
+		assert.False(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -6,9 +6,7 @@ package interceptors
 
 import (
 	"context"
-	"fmt"
 
-	"github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -25,68 +23,145 @@ type contextKey string
 const (
 	// UserID field name const
 	UserID contextKey = "UserID"
+	// SessionID field name const
+	SessionID contextKey = "SessionID"
 )
 
+// SessionChecker reports whether a server-side session is still active, i.e. it hasn't been revoked
+// by logout, remote revocation or refresh token reuse detection and hasn't expired. Implementations
+// also record the session as seen at the current time.
+type SessionChecker interface {
+	TouchSession(ctx context.Context, sessionID int64) (bool, error)
+}
+
 // ValidateToken returns a gRPC unary server interceptor that validates JWT tokens
 // for incoming requests, allowing access to secured methods based on the token's validity.
 //
 // Parameters:
-//   - key: A string representing the secret key used for signing the JWT tokens.
+//   - keys: The KeySet verifying the JWT tokens, the verification key is selected by the "kid" header of a token.
+//   - sessions: A SessionChecker used to reject tokens of revoked sessions. If nil, sessions aren't checked.
 //
 // The interceptor checks if the incoming request's method is one of the public methods
-// (RegisterUser, Authorize, or Echo). If it is, or if the key is empty, the interceptor
+// (RegisterUser, Authorize, RefreshToken, Echo or GetJWKS). If it is, or if the keys are nil, the interceptor
 // allows the request to proceed without validation. Otherwise, it extracts the token from
-// the metadata of the incoming context and attempts to parse it using the provided key.
+// the metadata of the incoming context and attempts to parse it using the provided keys.
 //
 // Returns:
 //   - A function that implements the gRPC UnaryHandler signature, which processes the
 //     request if the token is valid, or returns an error if the token is missing or invalid.
 //
-// If the token is successfully parsed and validated, the interceptor extracts the UserID
-// from the claims and stores it in the context for further use in the request handling.
-func ValidateToken(key string) func(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error) {
+// If the token is successfully parsed and validated, the interceptor makes sure that the session
+// the token was issued for is still active, extracts the UserID and SessionID from the claims
+// and stores them in the context for further use in the request handling.
+func ValidateToken(keys *jwtPKG.KeySet, sessions SessionChecker) func(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error) {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		if info.FullMethod == proto.Gophkeeper_RegisterUser_FullMethodName ||
 			info.FullMethod == proto.Gophkeeper_Authorize_FullMethodName ||
-			info.FullMethod == proto.Gophkeeper_Echo_FullMethodName {
+			info.FullMethod == proto.Gophkeeper_RefreshToken_FullMethodName ||
+			info.FullMethod == proto.Gophkeeper_Echo_FullMethodName ||
+			info.FullMethod == proto.Gophkeeper_GetJWKS_FullMethodName {
 			return handler(ctx, req)
 		}
-		if key == "" {
+		if keys == nil {
 			return handler(ctx, req)
 		}
 
-		var tokenString string
-		if md, ok := metadata.FromIncomingContext(ctx); ok {
-			values := md.Get("token")
-			if len(values) > 0 {
-				tokenString = values[0]
-			}
-		}
-		if len(tokenString) == 0 {
-			return nil, status.Error(codes.PermissionDenied, "missing token")
+		ctx, err := authenticate(ctx, keys, sessions)
+		if err != nil {
+			return nil, err
 		}
+		return handler(ctx, req)
+	}
+}
 
-		claims := &jwtPKG.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims,
-			func(t *jwt.Token) (interface{}, error) {
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					logger.Log.Error("unexpected signing method")
-					return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-				}
-				return []byte(key), nil
-			})
-
+// ValidateTokenStream returns a gRPC stream server interceptor that validates JWT tokens of the
+// streaming methods, it is the stream counterpart of ValidateToken.
+//
+// Parameters:
+//   - keys: The KeySet verifying the JWT tokens. If nil, tokens aren't validated.
+//   - sessions: A SessionChecker used to reject tokens of revoked sessions. If nil, sessions aren't checked.
+//
+// Returns:
+//   - A function that implements the gRPC StreamServerInterceptor signature, which processes the
+//     stream if the token is valid, or returns an error if the token is missing or invalid.
+//
+// The token is sent in the metadata of the stream. The UserID and SessionID from the claims are
+// stored in the stream context.
+func ValidateTokenStream(keys *jwtPKG.KeySet, sessions SessionChecker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if keys == nil {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), keys, sessions)
 		if err != nil {
-			logger.Log.Error("error parse claims", zap.Error(err))
-			return nil, status.Errorf(codes.PermissionDenied, "error parse claims")
+			return err
 		}
+		stream := newWrappedStream(ss)
+		stream.ctx = ctx
+		return handler(srv, stream)
+	}
+}
 
-		if !token.Valid {
-			logger.Log.Error("token  is not valid")
-			return nil, status.Errorf(codes.PermissionDenied, "token is not valid")
+// authenticate validates the JWT token from the metadata of the incoming context and makes sure
+// that its session is still active.
+//
+// Returns:
+//   - The context with the UserID and SessionID of the token.
+//   - A PermissionDenied status error if the token is missing or invalid, or an error of checkSession.
+func authenticate(ctx context.Context, keys *jwtPKG.KeySet, sessions SessionChecker) (context.Context, error) {
+	var tokenString string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		values := md.Get("token")
+		if len(values) > 0 {
+			tokenString = values[0]
 		}
+	}
+	if len(tokenString) == 0 {
+		return nil, status.Error(codes.PermissionDenied, "missing token")
+	}
 
-		ctx = context.WithValue(ctx, UserID, claims.UserID)
-		return handler(ctx, req)
+	claims, err := keys.Parse(tokenString)
+	if err != nil {
+		logger.Log.Error("error parse claims", zap.Error(err))
+		return nil, status.Errorf(codes.PermissionDenied, "error parse claims")
+	}
+	SetAuditUser(ctx, claims.UserID, claims.SessionID)
+
+	if err = checkSession(ctx, sessions, claims); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, UserID, claims.UserID)
+	ctx = context.WithValue(ctx, SessionID, claims.SessionID)
+	return ctx, nil
+}
+
+// checkSession makes sure that the session of a parsed token is still active.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - sessions: A SessionChecker used to look the session up. If nil, the check is skipped.
+//   - claims: The claims of a valid token.
+//
+// Returns:
+//   - A PermissionDenied status error if the token doesn't belong to an active session,
+//     or an Internal status error if the session can't be checked.
+func checkSession(ctx context.Context, sessions SessionChecker, claims *jwtPKG.Claims) error {
+	if sessions == nil {
+		return nil
+	}
+	if claims.SessionID == 0 {
+		logger.Log.Error("token has no session")
+		return status.Errorf(codes.PermissionDenied, "token is not valid")
+	}
+	active, err := sessions.TouchSession(ctx, claims.SessionID)
+	if err != nil {
+		logger.Log.Error("error check session", zap.Error(err))
+		return status.Errorf(codes.Internal, "error check session")
+	}
+	if !active {
+		logger.Log.Error("session is revoked", zap.Int64("sessionID", claims.SessionID))
+		return status.Errorf(codes.PermissionDenied, "session is revoked")
 	}
+	return nil
 }
@@ -2,6 +2,7 @@ package interceptors
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,7 +20,7 @@ func TestValidateToken_ValidToken(t *testing.T) {
 	secretKey := "my_secret_key"
 	userID := int64(123)
 
-	tokenString, err := pkgJwt.BuildJWTString(secretKey, userID)
+	tokenString, err := pkgJwt.BuildJWTString(secretKey, userID, 1)
 	require.NoError(t, err)
 
 	md := metadata.Pairs("token", tokenString)
@@ -28,7 +29,7 @@ func TestValidateToken_ValidToken(t *testing.T) {
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return nil, nil
 	}
-	interceptor := ValidateToken(secretKey)
+	interceptor := ValidateToken(pkgJwt.NewHMACKeySet(secretKey), nil)
 
 	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/gophkeeper.Gophkeeper/GetFiles"}, handler)
 
@@ -43,7 +44,7 @@ func TestValidateToken_MissingToken(t *testing.T) {
 		return nil, nil
 	}
 
-	interceptor := ValidateToken("my_secret_key")
+	interceptor := ValidateToken(pkgJwt.NewHMACKeySet("my_secret_key"), nil)
 
 	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/gophkeeper.Gophkeeper/GetFiles"}, handler)
 
@@ -62,7 +63,7 @@ func TestValidateToken_InvalidToken(t *testing.T) {
 		return nil, nil
 	}
 
-	interceptor := ValidateToken("my_secret_key")
+	interceptor := ValidateToken(pkgJwt.NewHMACKeySet("my_secret_key"), nil)
 
 	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/gophkeeper.Gophkeeper/GetFiles"}, handler)
 
@@ -74,7 +75,7 @@ func TestValidateToken_InvalidToken(t *testing.T) {
 func TestValidateToken_EmptyKey(t *testing.T) {
 	secretKey := "my_secret_key"
 	userID := int64(123)
-	tokenString, err := pkgJwt.BuildJWTString(secretKey, userID)
+	tokenString, err := pkgJwt.BuildJWTString(secretKey, userID, 1)
 	require.NoError(t, err)
 
 	md := metadata.Pairs("token", tokenString)
@@ -84,10 +85,55 @@ func TestValidateToken_EmptyKey(t *testing.T) {
 		return nil, nil
 	}
 
-	interceptor := ValidateToken("")
+	interceptor := ValidateToken(nil, nil)
 
 	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: proto.Gophkeeper_Echo_FullMethodName}, handler)
 
 	require.NoError(t, err)
 	assert.Nil(t, resp)
 }
+
+type sessionCheckerMock struct {
+	err    error
+	active map[int64]bool
+}
+
+func (m *sessionCheckerMock) TouchSession(_ context.Context, sessionID int64) (bool, error) {
+	return m.active[sessionID], m.err
+}
+
+func TestValidateToken_Session(t *testing.T) {
+	secretKey := "my_secret_key"
+	checker := &sessionCheckerMock{active: map[int64]bool{1: true}}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Equal(t, int64(123), ctx.Value(UserID))
+		assert.Equal(t, int64(1), ctx.Value(SessionID))
+		return nil, nil
+	}
+	interceptor := ValidateToken(pkgJwt.NewHMACKeySet(secretKey), checker)
+	info := &grpc.UnaryServerInfo{FullMethod: "/gophkeeper.Gophkeeper/GetFiles"}
+
+	tests := []struct {
+		name      string
+		err       error
+		sessionID int64
+		code      codes.Code
+	}{
+		{name: "active session", sessionID: 1, code: codes.OK},
+		{name: "revoked session", sessionID: 2, code: codes.PermissionDenied},
+		{name: "token without session", sessionID: 0, code: codes.PermissionDenied},
+		{name: "storage error", sessionID: 1, err: errors.New("db is down"), code: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker.err = tt.err
+			tokenString, err := pkgJwt.BuildJWTString(secretKey, 123, tt.sessionID)
+			require.NoError(t, err)
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("token", tokenString))
+
+			_, err = interceptor(ctx, nil, info, handler)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
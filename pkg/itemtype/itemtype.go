@@ -0,0 +1,215 @@
+// Package itemtype describes the custom item types: templates of named fields of a kind.
+//
+// A custom item carries the name of its template and an ordered list of fields with their names and kinds,
+// so it can be shown without the template. The built-in templates cover the common kinds of secrets,
+// the users can define their own templates in addition to them.
+package itemtype
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The kinds of the fields.
+const (
+	// KindText is a single line of plain text.
+	KindText = "text"
+	// KindSecret is a single line hidden by default when the item is shown.
+	KindSecret = "secret"
+	// KindURL is an absolute URL.
+	KindURL = "url"
+	// KindDate is a date in the "2006-01-02" format.
+	KindDate = "date"
+	// KindMultiline is a text of several lines, for example, a key in the PEM format.
+	KindMultiline = "multiline"
+)
+
+// Kinds are the kinds of the fields in the order they are listed to the user.
+var Kinds = []string{KindText, KindSecret, KindURL, KindDate, KindMultiline}
+
+// MaxNameLength limits the length of the names of the templates and the fields.
+const MaxNameLength = 64
+
+// MaxFields limits the number of fields of a template.
+const MaxFields = 32
+
+var (
+	// ErrInvalidTemplate is returned when a template has no valid name or fields.
+	ErrInvalidTemplate = errors.New("invalid item template")
+	// ErrInvalidValue is returned when a field value doesn't match the kind of the field.
+	ErrInvalidValue = errors.New("invalid field value")
+)
+
+// Field is a field of a template.
+//
+// Fields:
+//   - Name: The name of the field, unique within the template.
+//   - Kind: The kind of the field value, one of the Kind constants.
+type Field struct {
+	Name string
+	Kind string
+}
+
+// Template is a custom item type.
+//
+// Fields:
+//   - Name: The name of the template, unique among the templates of a user and the built-in templates.
+//   - Description: A short description of the template.
+//   - Fields: The ordered fields of the items of the template.
+type Template struct {
+	Name        string
+	Description string
+	Fields      []Field
+}
+
+// Builtin are the templates available to every user.
+var Builtin = []Template{
+	{
+		Name:        "ssh-key",
+		Description: "SSH key pair",
+		Fields: []Field{
+			{Name: "host", Kind: KindText},
+			{Name: "user", Kind: KindText},
+			{Name: "private_key", Kind: KindMultiline},
+			{Name: "public_key", Kind: KindMultiline},
+			{Name: "passphrase", Kind: KindSecret},
+		},
+	},
+	{
+		Name:        "api-token",
+		Description: "API token of a service",
+		Fields: []Field{
+			{Name: "service", Kind: KindText},
+			{Name: "url", Kind: KindURL},
+			{Name: "token", Kind: KindSecret},
+			{Name: "expires", Kind: KindDate},
+		},
+	},
+	{
+		Name:        "wifi",
+		Description: "Wi-Fi network",
+		Fields: []Field{
+			{Name: "ssid", Kind: KindText},
+			{Name: "password", Kind: KindSecret},
+			{Name: "security", Kind: KindText},
+		},
+	},
+	{
+		Name:        "licence",
+		Description: "Software licence",
+		Fields: []Field{
+			{Name: "product", Kind: KindText},
+			{Name: "licensee", Kind: KindText},
+			{Name: "key", Kind: KindSecret},
+			{Name: "url", Kind: KindURL},
+			{Name: "expires", Kind: KindDate},
+		},
+	},
+	{
+		Name:        "identity",
+		Description: "Identity document",
+		Fields: []Field{
+			{Name: "document", Kind: KindText},
+			{Name: "number", Kind: KindSecret},
+			{Name: "full_name", Kind: KindText},
+			{Name: "authority", Kind: KindText},
+			{Name: "issued", Kind: KindDate},
+			{Name: "expires", Kind: KindDate},
+		},
+	},
+}
+
+// FindBuiltin returns the built-in template with the name.
+func FindBuiltin(name string) (Template, bool) {
+	for _, t := range Builtin {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// ValidKind reports whether the kind is one of the Kind constants.
+func ValidKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidName reports whether the name can be used for a template or a field: it must be non-empty,
+// no longer than MaxNameLength and consist of letters, digits, '-', '_' and '.'.
+func ValidName(name string) bool {
+	if name == "" || len(name) > MaxNameLength {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that the template has a valid name and from 1 to MaxFields fields with valid unique names
+// and known kinds.
+func (t Template) Validate() error {
+	if !ValidName(t.Name) {
+		return fmt.Errorf("%w: bad name %q", ErrInvalidTemplate, t.Name)
+	}
+	if len(t.Fields) == 0 || len(t.Fields) > MaxFields {
+		return fmt.Errorf("%w: a template must have from 1 to %d fields", ErrInvalidTemplate, MaxFields)
+	}
+	return ValidateFields(t.Fields)
+}
+
+// ValidateFields checks that the fields have valid unique names and known kinds.
+func ValidateFields(fields []Field) error {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if !ValidName(f.Name) {
+			return fmt.Errorf("%w: bad field name %q", ErrInvalidTemplate, f.Name)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("%w: duplicate field %q", ErrInvalidTemplate, f.Name)
+		}
+		seen[f.Name] = true
+		if !ValidKind(f.Kind) {
+			return fmt.Errorf("%w: unknown kind %q of field %q, use one of %s",
+				ErrInvalidTemplate, f.Kind, f.Name, strings.Join(Kinds, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateValue checks that a value can be stored in a field of the kind. An empty value is always valid,
+// the fields are optional.
+func ValidateValue(kind, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch kind {
+	case KindText, KindSecret:
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("%w: a %s value must be a single line", ErrInvalidValue, kind)
+		}
+	case KindURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: %q is not an absolute URL", ErrInvalidValue, value)
+		}
+	case KindDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fmt.Errorf("%w: %q is not a date in the YYYY-MM-DD format", ErrInvalidValue, value)
+		}
+	case KindMultiline:
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidValue, kind)
+	}
+	return nil
+}
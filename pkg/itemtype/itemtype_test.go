@@ -0,0 +1,109 @@
+package itemtype
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, tmpl := range Builtin {
+		require.NoError(t, tmpl.Validate(), tmpl.Name)
+		assert.False(t, seen[tmpl.Name], "duplicate template %s", tmpl.Name)
+		seen[tmpl.Name] = true
+
+		found, ok := FindBuiltin(tmpl.Name)
+		require.True(t, ok)
+		assert.Equal(t, tmpl, found)
+	}
+
+	_, ok := FindBuiltin("unknown")
+	assert.False(t, ok)
+}
+
+func TestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template Template
+		valid    bool
+	}{
+		{
+			name:     "ok",
+			template: Template{Name: "db-account", Fields: []Field{{Name: "host", Kind: KindText}, {Name: "password", Kind: KindSecret}}},
+			valid:    true,
+		},
+		{
+			name:     "empty name",
+			template: Template{Fields: []Field{{Name: "host", Kind: KindText}}},
+		},
+		{
+			name:     "bad name",
+			template: Template{Name: "db account", Fields: []Field{{Name: "host", Kind: KindText}}},
+		},
+		{
+			name:     "no fields",
+			template: Template{Name: "db-account"},
+		},
+		{
+			name:     "bad field name",
+			template: Template{Name: "db-account", Fields: []Field{{Name: "host=", Kind: KindText}}},
+		},
+		{
+			name:     "duplicate field",
+			template: Template{Name: "db-account", Fields: []Field{{Name: "host", Kind: KindText}, {Name: "host", Kind: KindURL}}},
+		},
+		{
+			name:     "unknown kind",
+			template: Template{Name: "db-account", Fields: []Field{{Name: "host", Kind: "number"}}},
+		},
+		{
+			name:     "too long name",
+			template: Template{Name: strings.Repeat("a", MaxNameLength+1), Fields: []Field{{Name: "host", Kind: KindText}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidTemplate)
+			}
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		kind  string
+		value string
+		valid bool
+	}{
+		{kind: KindText, value: "", valid: true},
+		{kind: KindText, value: "home network", valid: true},
+		{kind: KindText, value: "two\nlines"},
+		{kind: KindSecret, value: "s3cr3t", valid: true},
+		{kind: KindSecret, value: "s3cr3t\r\n"},
+		{kind: KindURL, value: "https://example.com/api", valid: true},
+		{kind: KindURL, value: "example.com"},
+		{kind: KindDate, value: "2027-04-23", valid: true},
+		{kind: KindDate, value: "23.04.2027"},
+		{kind: KindMultiline, value: "-----BEGIN KEY-----\nAAAA\n-----END KEY-----", valid: true},
+		{kind: "number", value: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+" "+tt.value, func(t *testing.T) {
+			err := ValidateValue(tt.kind, tt.value)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidValue)
+			}
+		})
+	}
+}
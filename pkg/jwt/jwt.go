@@ -1,51 +1,55 @@
 // Package jwt provides functionality for creating and managing JSON Web Tokens (JWT).
 //
-// This package includes the Claims struct and the BuildJWTString function for generating
-// signed JWTs with user-specific claims.
+// This package includes the Claims struct and the KeySet type for generating signed JWTs with
+// user-specific claims and verifying them with the key selected by the "kid" header, as well as
+// helpers for opaque refresh tokens.
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
 // Claims represents the custom claims for the JWT, including registered claims
-// and a UserID field.
+// (with a unique token ID in jti), a UserID field and the ID of the server-side session
+// the token belongs to.
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID int64
+	UserID    int64
+	SessionID int64
 }
 
-// TokenExpireTime defines the duration for which the JWT is valid.
-const TokenExpireTime = time.Hour * 1
+// TokenExpireTime defines the duration for which the JWT (access token) is valid.
+const TokenExpireTime = time.Minute * 15
 
-// BuildJWTString generates a signed JWT string for a given user ID using the provided secret key.
+// BuildJWTString generates a JWT string for a given user ID signed with the HS256 secret key.
 //
 // Parameters:
 //   - secretKey: A string representing the secret key used for signing the JWT.
 //   - userID: An int64 representing the user ID to be included in the JWT claims.
+//   - sessionID: An int64 representing the server-side session the token is issued for.
 //
 // Returns:
 //   - A string containing the signed JWT.
-//   - An error if the token could not be created or signed.
+//   - An error if the secret key is empty or the token could not be created or signed.
 //
-// The function creates a new JWT with claims that include an expiration time set to
-// one hour from the current time and the specified user ID. It then signs the token
-// using the provided secret key and returns the resulting token string. If any error
-// occurs during the signing process, it returns an error.
-func BuildJWTString(secretKey string, userID int64) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpireTime)),
-		},
-		UserID: userID,
-	})
+// It is a shortcut for NewHMACKeySet(secretKey).BuildJWTString(userID, sessionID).
+func BuildJWTString(secretKey string, userID, sessionID int64) (string, error) {
+	ks := NewHMACKeySet(secretKey)
+	if ks == nil {
+		return "", fmt.Errorf("%w: empty secret key", ErrInvalidKey)
+	}
+	return ks.BuildJWTString(userID, sessionID)
+}
 
-	tokenString, err := token.SignedString([]byte(secretKey))
-	if err != nil {
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-
-	return tokenString, nil
+	return hex.EncodeToString(b), nil
 }
@@ -15,8 +15,9 @@ import (
 func TestBuildJWTString_ValidInput(t *testing.T) {
 	secretKey := "my_secret_key"
 	userID := int64(123)
+	sessionID := int64(7)
 
-	tokenString, err := BuildJWTString(secretKey, userID)
+	tokenString, err := BuildJWTString(secretKey, userID, sessionID)
 
 	require.NoError(t, err)
 
@@ -36,6 +37,30 @@ func TestBuildJWTString_ValidInput(t *testing.T) {
 	assert.NotNil(t, token)
 
 	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, sessionID, claims.SessionID)
+	assert.NotEmpty(t, claims.ID)
 
 	assert.False(t, claims.ExpiresAt.Time.Before(time.Now()))
 }
+
+func TestBuildJWTString_UniqueID(t *testing.T) {
+	first, err := BuildJWTString("my_secret_key", 1, 1)
+	require.NoError(t, err)
+	second, err := BuildJWTString("my_secret_key", 1, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestRefreshToken(t *testing.T) {
+	token, err := GenerateRefreshToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	other, err := GenerateRefreshToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	assert.Equal(t, HashRefreshToken(token), HashRefreshToken(token))
+	assert.NotEqual(t, HashRefreshToken(token), HashRefreshToken(other))
+	assert.NotEqual(t, token, HashRefreshToken(token))
+}
@@ -0,0 +1,317 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// HMACKeyID is the ID of the HS256 secret key. Tokens without a key ID have been signed with it.
+const HMACKeyID = "hs256"
+
+// minRSAKeyBits is the minimum size of the RSA keys.
+const minRSAKeyBits = 2048
+
+var (
+	// ErrInvalidKey is returned when a key is malformed or of an unsupported type.
+	ErrInvalidKey = errors.New("invalid JWT key")
+	// ErrUnknownKey is returned when a token is signed with a key that is not in the key set.
+	ErrUnknownKey = errors.New("token is signed with an unknown key")
+)
+
+// key is a JWT signing or verification key.
+type key struct {
+	method  jwt.SigningMethod
+	private interface{} // nil for the verification only keys
+	public  interface{}
+}
+
+// KeySet holds the JWT keys by ID (the "kid" header of the tokens). The signing key signs new tokens,
+// the other keys only verify them: they are the keys being rotated in, which are trusted before they sign
+// anything, and the retired keys, which are trusted until the tokens signed with them expire.
+//
+// Asymmetric keys (Ed25519 signing with EdDSA and RSA signing with RS256) allow the services that only
+// validate tokens to hold the public keys alone. The HS256 secret key is supported for compatibility.
+// A KeySet is safe for concurrent use, Update replaces its keys in place.
+type KeySet struct {
+	keys      map[string]*key
+	signingID string
+	mu        sync.RWMutex
+}
+
+// NewHMACKeySet creates a key set signing and verifying tokens with the HS256 secret key.
+//
+// Returns:
+//   - A pointer to the created KeySet, or nil if the secret is empty.
+func NewHMACKeySet(secret string) *KeySet {
+	if secret == "" {
+		return nil
+	}
+	return &KeySet{
+		keys:      map[string]*key{HMACKeyID: hmacKey(secret)},
+		signingID: HMACKeyID,
+	}
+}
+
+// LoadKeySet loads the JWT keys.
+//
+// Parameters:
+//   - secret: The HS256 secret key. It signs new tokens if signingKeyFile is empty and only verifies them otherwise,
+//     so the tokens issued before switching to the asymmetric keys stay valid. Ignored if empty.
+//   - signingKeyFile: The path to the PEM-encoded Ed25519 or RSA private key (PKCS #8 or PKCS #1) to sign new tokens with.
+//   - verificationKeyFiles: The paths to the PEM-encoded public (PKIX) or private keys trusted in addition to the signing key.
+//
+// Returns:
+//   - A pointer to the loaded KeySet.
+//   - An error if a file could not be read, a key is invalid, or there is no key to sign tokens with.
+func LoadKeySet(secret, signingKeyFile string, verificationKeyFiles []string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*key)}
+	if secret != "" {
+		ks.keys[HMACKeyID] = hmacKey(secret)
+		ks.signingID = HMACKeyID
+	}
+	if signingKeyFile != "" {
+		id, k, err := loadKey(signingKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if k.private == nil {
+			return nil, fmt.Errorf("%w: %s is not a private key", ErrInvalidKey, signingKeyFile)
+		}
+		ks.keys[id] = k
+		ks.signingID = id
+	}
+	if ks.signingID == "" {
+		return nil, fmt.Errorf("%w: no signing key", ErrInvalidKey)
+	}
+	for _, file := range verificationKeyFiles {
+		id, k, err := loadKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ks.keys[id]; !ok {
+			ks.keys[id] = &key{method: k.method, public: k.public}
+		}
+	}
+	return ks, nil
+}
+
+// Update replaces the keys of the key set with the keys of another one, e.g. reloaded from the files.
+func (ks *KeySet) Update(other *KeySet) {
+	other.mu.RLock()
+	keys, signingID := other.keys, other.signingID
+	other.mu.RUnlock()
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys, ks.signingID = keys, signingID
+}
+
+// SigningKeyID returns the ID of the key signing new tokens.
+func (ks *KeySet) SigningKeyID() string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.signingID
+}
+
+// BuildJWTString generates a JWT string for a given user ID signed with the signing key of the key set,
+// the ID of the key is set in the "kid" header.
+//
+// Parameters:
+//   - userID: An int64 representing the user ID to be included in the JWT claims.
+//   - sessionID: An int64 representing the server-side session the token is issued for.
+//
+// Returns:
+//   - A string containing the signed JWT.
+//   - An error if the token could not be created or signed.
+func (ks *KeySet) BuildJWTString(userID, sessionID int64) (string, error) {
+	ks.mu.RLock()
+	id, k := ks.signingID, ks.keys[ks.signingID]
+	ks.mu.RUnlock()
+
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(k.method, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(TokenExpireTime)),
+		},
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	token.Header["kid"] = id
+
+	return token.SignedString(k.private)
+}
+
+// Parse parses the token and verifies it with the key selected by its "kid" header.
+//
+// Returns:
+//   - The claims of the token.
+//   - ErrUnknownKey if the token is signed with a key that is not in the key set, or an error
+//     if the token is malformed, its signature is invalid or it has expired.
+func (ks *KeySet) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, ks.keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+	return claims, nil
+}
+
+// keyfunc returns the verification key of a token, making sure that the token is signed with
+// the algorithm of the key, so a public key can never be used as an HMAC secret.
+func (ks *KeySet) keyfunc(t *jwt.Token) (interface{}, error) {
+	id := HMACKeyID
+	if kid, ok := t.Header["kid"]; ok {
+		if id, ok = kid.(string); !ok {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	ks.mu.RLock()
+	k, ok := ks.keys[id]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	}
+	return k.public, nil
+}
+
+// JWK is a public key in the JSON Web Key form (RFC 7517), which other services use to verify the tokens.
+type JWK struct {
+	KeyID     string // "kid"
+	KeyType   string // "kty": "OKP" for Ed25519 or "RSA"
+	Algorithm string // "alg": "EdDSA" or "RS256"
+	Curve     string // "crv" of the OKP keys
+	X         string // "x", the base64url-encoded Ed25519 public key
+	N         string // "n", the base64url-encoded RSA modulus
+	E         string // "e", the base64url-encoded RSA public exponent
+}
+
+// JWKS returns the public keys of the key set sorted by ID. The HS256 secret key is never published.
+func (ks *KeySet) JWKS() []JWK {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	jwks := make([]JWK, 0, len(ks.keys))
+	for id, k := range ks.keys {
+		jwk := JWK{KeyID: id, Algorithm: k.method.Alg()}
+		switch pub := k.public.(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		default:
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+	sort.Slice(jwks, func(i, j int) bool {
+		return jwks[i].KeyID < jwks[j].KeyID
+	})
+	return jwks
+}
+
+// KeyID returns the ID of a public key: the first 8 bytes of the SHA-256 of its PKIX form, hex-encoded.
+// The ID only depends on the key, so every service loading the same key agrees on it.
+func KeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func hmacKey(secret string) *key {
+	return &key{method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+}
+
+// loadKey reads a PEM-encoded private or public key from the file.
+func loadKey(file string) (string, *key, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", nil, fmt.Errorf("%w: %s is not a PEM file", ErrInvalidKey, file)
+	}
+	k, err := parseKey(block)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s: %v", ErrInvalidKey, file, err)
+	}
+	id, err := KeyID(k.public)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s: %v", ErrInvalidKey, file, err)
+	}
+	return id, k, nil
+}
+
+// parseKey parses a PKCS #8 or PKCS #1 private key or a PKIX public key.
+func parseKey(block *pem.Block) (*key, error) {
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		return &key{method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &key{method: jwt.SigningMethodEdDSA, public: k}, nil
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits long", minRSAKeyBits)
+		}
+		return &key{method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits long", minRSAKeyBits)
+		}
+		return &key{method: jwt.SigningMethodRS256, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
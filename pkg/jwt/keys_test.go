@@ -0,0 +1,200 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKey writes the private key and its public key into PEM files and returns their paths.
+func writeKey(t *testing.T, dir, name string, private interface{}, public interface{}) (string, string) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	privateFile := filepath.Join(dir, name+".key")
+	publicFile := filepath.Join(dir, name+".pub")
+	require.NoError(t, os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600))
+	require.NoError(t, os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644))
+	return privateFile, publicFile
+}
+
+func writeEd25519Key(t *testing.T, dir, name string) (string, string) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return writeKey(t, dir, name, private, public)
+}
+
+func TestKeySet_Ed25519(t *testing.T) {
+	private, public := writeEd25519Key(t, t.TempDir(), "ed25519")
+
+	signer, err := LoadKeySet("", private, nil)
+	require.NoError(t, err)
+	tokenString, err := signer.BuildJWTString(123, 7)
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Header["alg"])
+	assert.Equal(t, signer.SigningKeyID(), token.Header["kid"])
+
+	verifier, err := LoadKeySet("unused-secret", "", []string{public})
+	require.NoError(t, err)
+	claims, err := verifier.Parse(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, int64(123), claims.UserID)
+	assert.Equal(t, int64(7), claims.SessionID)
+}
+
+func TestKeySet_RSA(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	private, public := writeKey(t, dir, "rsa", key, &key.PublicKey)
+
+	signer, err := LoadKeySet("", private, nil)
+	require.NoError(t, err)
+	tokenString, err := signer.BuildJWTString(1, 1)
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", token.Header["alg"])
+
+	verifier, err := LoadKeySet("unused-secret", "", []string{public})
+	require.NoError(t, err)
+	_, err = verifier.Parse(tokenString)
+	require.NoError(t, err)
+
+	jwks := verifier.JWKS()
+	require.Len(t, jwks, 1)
+	assert.Equal(t, "RSA", jwks[0].KeyType)
+	assert.Equal(t, "AQAB", jwks[0].E)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	smallPrivate, _ := writeKey(t, dir, "small", small, &small.PublicKey)
+	_, err = LoadKeySet("", smallPrivate, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	oldPrivate, oldPublic := writeEd25519Key(t, dir, "old")
+	newPrivate, newPublic := writeEd25519Key(t, dir, "new")
+
+	// The new key is trusted before it signs anything.
+	keys, err := LoadKeySet("", oldPrivate, []string{newPublic})
+	require.NoError(t, err)
+	oldToken, err := keys.BuildJWTString(1, 1)
+	require.NoError(t, err)
+
+	// The new key signs the tokens, the old one still verifies the tokens signed before.
+	rotated, err := LoadKeySet("", newPrivate, []string{oldPublic})
+	require.NoError(t, err)
+	keys.Update(rotated)
+	assert.Equal(t, rotated.SigningKeyID(), keys.SigningKeyID())
+	newToken, err := keys.BuildJWTString(1, 1)
+	require.NoError(t, err)
+	_, err = keys.Parse(oldToken)
+	require.NoError(t, err)
+	_, err = keys.Parse(newToken)
+	require.NoError(t, err)
+	assert.Len(t, keys.JWKS(), 2)
+
+	// The old key is retired.
+	retired, err := LoadKeySet("", newPrivate, nil)
+	require.NoError(t, err)
+	keys.Update(retired)
+	_, err = keys.Parse(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = keys.Parse(newToken)
+	require.NoError(t, err)
+}
+
+func TestKeySet_HMAC(t *testing.T) {
+	private, _ := writeEd25519Key(t, t.TempDir(), "ed25519")
+
+	legacyToken, err := BuildJWTString("secret", 1, 1)
+	require.NoError(t, err)
+	_, err = NewHMACKeySet("secret").Parse(legacyToken)
+	require.NoError(t, err)
+
+	// Tokens issued before the switch to the asymmetric keys stay valid.
+	keys, err := LoadKeySet("secret", private, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, HMACKeyID, keys.SigningKeyID())
+	_, err = keys.Parse(legacyToken)
+	require.NoError(t, err)
+
+	// Tokens without a key ID have been signed with the secret key.
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 1})
+	noKeyID, err := token.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = keys.Parse(noKeyID)
+	require.NoError(t, err)
+
+	// The secret key is never published.
+	for _, jwk := range keys.JWKS() {
+		assert.NotEqual(t, HMACKeyID, jwk.KeyID)
+	}
+
+	withoutSecret, err := LoadKeySet("", private, nil)
+	require.NoError(t, err)
+	_, err = withoutSecret.Parse(legacyToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	assert.Nil(t, NewHMACKeySet(""))
+	_, err = BuildJWTString("", 1, 1)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestKeySet_AlgorithmConfusion(t *testing.T) {
+	private, public := writeEd25519Key(t, t.TempDir(), "ed25519")
+	keys, err := LoadKeySet("", private, nil)
+	require.NoError(t, err)
+
+	// A token signed with HS256 using the public key as the secret must not be accepted.
+	publicPEM, err := os.ReadFile(public)
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           1,
+	})
+	token.Header["kid"] = keys.SigningKeyID()
+	forged, err := token.SignedString(publicPEM)
+	require.NoError(t, err)
+
+	_, err = keys.Parse(forged)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, public := writeEd25519Key(t, dir, "ed25519")
+
+	_, err := LoadKeySet("", "", nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = LoadKeySet("", public, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a key"), 0600))
+	_, err = LoadKeySet("secret", "", []string{notPEM})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = LoadKeySet("secret", "", []string{filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+}
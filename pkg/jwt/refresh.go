@@ -0,0 +1,25 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// RefreshTokenExpireTime defines the duration for which a refresh token and its session stay valid
+// since the last refresh.
+const RefreshTokenExpireTime = time.Hour * 24 * 30
+
+// GenerateRefreshToken returns a new opaque random refresh token.
+//
+// Refresh tokens are not JWTs: the server only keeps their SHA-256 hashes (see HashRefreshToken),
+// so a leaked database doesn't allow to resume anyone's session.
+func GenerateRefreshToken() (string, error) {
+	return randomHex(32)
+}
+
+// HashRefreshToken returns the hex-encoded SHA-256 hash of a refresh token which is stored on the server.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
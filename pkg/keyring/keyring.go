@@ -0,0 +1,175 @@
+// Package keyring provides functionality for encrypting values with a set of versioned AES keys.
+//
+// Every ciphertext produced by a Keyring is tagged with the ID of the key it was encrypted with, so the
+// active key can be replaced while the values encrypted with the retired keys can still be decrypted.
+// Values without a key ID were encrypted before key IDs were introduced and belong to LegacyKeyID.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+)
+
+// LegacyKeyID is the ID of the key that decrypts values without a key ID.
+const LegacyKeyID = "default"
+
+// KeyLength is the required length of the keys, which is suitable for AES-256.
+const KeyLength = 32
+
+// separator separates the key ID from the base64-encoded ciphertext, it never occurs in either of them.
+const separator = ":"
+
+var (
+	// ErrUnknownKey is returned when a value is encrypted with a key that is not in the keyring.
+	ErrUnknownKey = errors.New("value is encrypted with an unknown key")
+	// ErrInvalidKey is returned when a key or a key ID is malformed.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// Keyring holds the encryption keys by ID. The active key encrypts new values, the other (retired) keys
+// are only used to decrypt the values that haven't been re-encrypted with the active key yet.
+type Keyring struct {
+	keys     map[string]string
+	activeID string
+}
+
+// New creates a keyring with the given keys.
+//
+// Parameters:
+//   - activeID: The ID of the key used to encrypt new values.
+//   - keys: The keys mapped by ID. IDs consist of letters, digits, '-' and '_'; keys must be KeyLength bytes long.
+//
+// Returns:
+//   - A pointer to the created Keyring.
+//   - ErrInvalidKey if a key or an ID is malformed or the active key is missing.
+func New(activeID string, keys map[string]string) (*Keyring, error) {
+	k := &Keyring{
+		keys:     make(map[string]string, len(keys)),
+		activeID: activeID,
+	}
+	for id, key := range keys {
+		if !validID(id) {
+			return nil, fmt.Errorf("%w: malformed key ID %q", ErrInvalidKey, id)
+		}
+		if len(key) != KeyLength {
+			return nil, fmt.Errorf("%w: key %q must be %d bytes long", ErrInvalidKey, id, KeyLength)
+		}
+		k.keys[id] = key
+	}
+	if _, ok := k.keys[activeID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q is not configured", ErrInvalidKey, activeID)
+	}
+	return k, nil
+}
+
+// ParseKeys parses keys in the "id=key,id=key" form.
+//
+// Parameters:
+//   - spec: The comma-separated list of keys with their IDs.
+//
+// Returns:
+//   - The keys mapped by ID.
+//   - ErrInvalidKey if the list is malformed or an ID is repeated.
+func ParseKeys(spec string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if strings.TrimSpace(spec) == "" {
+		return keys, nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		id, key, ok := strings.Cut(entry, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%w: entry must have the id=key form", ErrInvalidKey)
+		}
+		if _, ok = keys[id]; ok {
+			return nil, fmt.Errorf("%w: key %q is repeated", ErrInvalidKey, id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// ActiveID returns the ID of the key used to encrypt new values.
+func (k *Keyring) ActiveID() string {
+	return k.activeID
+}
+
+// Encrypt encrypts the value with the active key and tags the result with the key ID.
+func (k *Keyring) Encrypt(src string) (string, error) {
+	enc, err := aes.Encrypt(k.keys[k.activeID], src)
+	if err != nil {
+		return "", err
+	}
+	return k.activeID + separator + enc, nil
+}
+
+// Decrypt decrypts a value encrypted with any key of the keyring.
+//
+// Returns:
+//   - The decrypted value.
+//   - ErrUnknownKey if the value is encrypted with a key that is not in the keyring.
+//   - An error if the value could not be decrypted.
+func (k *Keyring) Decrypt(src string) (string, error) {
+	id, enc := split(src)
+	key, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	return aes.Decrypt(key, enc)
+}
+
+// KeyID returns the ID of the key the value is encrypted with.
+func KeyID(src string) string {
+	id, _ := split(src)
+	return id
+}
+
+// IsCurrent reports whether the value is encrypted with the active key.
+func (k *Keyring) IsCurrent(src string) bool {
+	return KeyID(src) == k.activeID
+}
+
+// Rotate re-encrypts the value with the active key, unless it is already encrypted with it.
+//
+// Returns:
+//   - The value encrypted with the active key.
+//   - true if the value has been re-encrypted.
+//   - An error if the value could not be decrypted or encrypted.
+func (k *Keyring) Rotate(src string) (string, bool, error) {
+	if k.IsCurrent(src) {
+		return src, false, nil
+	}
+	plain, err := k.Decrypt(src)
+	if err != nil {
+		return "", false, err
+	}
+	enc, err := k.Encrypt(plain)
+	if err != nil {
+		return "", false, err
+	}
+	return enc, true, nil
+}
+
+// split separates the key ID from the ciphertext. Values without a key ID belong to LegacyKeyID.
+func split(src string) (string, string) {
+	if id, enc, ok := strings.Cut(src, separator); ok {
+		return id, enc
+	}
+	return LegacyKeyID, src
+}
+
+// validID reports whether the key ID can be used to tag values.
+func validID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
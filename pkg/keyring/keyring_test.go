@@ -0,0 +1,109 @@
+package keyring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+)
+
+const (
+	oldKey = "strongDBKey2Ks5nM2J5JaI59PPEhL1x"
+	newKey = "newDBKey2Ks5nM2J5JaI59PPEhL1xAbc"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		activeID string
+		keys     map[string]string
+		wantErr  bool
+	}{
+		{name: "ok", activeID: "2025", keys: map[string]string{LegacyKeyID: oldKey, "2025": newKey}},
+		{name: "missing active key", activeID: "2026", keys: map[string]string{"2025": newKey}, wantErr: true},
+		{name: "short key", activeID: "2025", keys: map[string]string{"2025": "short"}, wantErr: true},
+		{name: "malformed id", activeID: "a:b", keys: map[string]string{"a:b": newKey}, wantErr: true},
+		{name: "empty id", activeID: "", keys: map[string]string{"": newKey}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.activeID, tt.keys)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidKey)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("2024=" + oldKey + ", 2025=" + newKey)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"2024": oldKey, "2025": newKey}, keys)
+
+	keys, err = ParseKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = ParseKeys("2024")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = ParseKeys("2024=" + oldKey + ",2024=" + newKey)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestKeyring(t *testing.T) {
+	old, err := New(LegacyKeyID, map[string]string{LegacyKeyID: oldKey})
+	require.NoError(t, err)
+	ring, err := New("2025", map[string]string{LegacyKeyID: oldKey, "2025": newKey})
+	require.NoError(t, err)
+	assert.Equal(t, "2025", ring.ActiveID())
+
+	legacy, err := aes.Encrypt(oldKey, "legacy secret")
+	require.NoError(t, err)
+	tagged, err := old.Encrypt("tagged secret")
+	require.NoError(t, err)
+	assert.Equal(t, LegacyKeyID, KeyID(legacy))
+	assert.Equal(t, LegacyKeyID, KeyID(tagged))
+
+	t.Run("decrypt with retired keys", func(t *testing.T) {
+		plain, err := ring.Decrypt(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, "legacy secret", plain)
+
+		plain, err = ring.Decrypt(tagged)
+		require.NoError(t, err)
+		assert.Equal(t, "tagged secret", plain)
+	})
+
+	t.Run("encrypt with the active key", func(t *testing.T) {
+		enc, err := ring.Encrypt("new secret")
+		require.NoError(t, err)
+		assert.Equal(t, "2025", KeyID(enc))
+		assert.True(t, ring.IsCurrent(enc))
+
+		_, err = old.Decrypt(enc)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		rotated, ok, err := ring.Rotate(legacy)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, ring.IsCurrent(rotated))
+
+		plain, err := ring.Decrypt(rotated)
+		require.NoError(t, err)
+		assert.Equal(t, "legacy secret", plain)
+
+		same, ok, err := ring.Rotate(rotated)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, rotated, same)
+
+		_, _, err = ring.Rotate("2020:" + legacy)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
@@ -0,0 +1,259 @@
+// Package passgen provides functionality for generating random passwords and passphrases.
+//
+// Passwords are drawn from the character classes allowed by a Policy, passphrases are diceware-style
+// sequences of words from the embedded EFF large word list (7776 words, about 12.9 bits per word).
+// All the randomness comes from crypto/rand.
+//
+// The word list is published by the Electronic Frontier Foundation under the CC BY 3.0 license:
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
+package passgen
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// The character classes of the passwords.
+const (
+	LowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	UpperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	DigitChars  = "0123456789"
+	SymbolChars = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+)
+
+// AmbiguousChars are the characters easily confused with each other when read or typed.
+const AmbiguousChars = "0OoIl1|"
+
+// MaxLength limits the length of the generated passwords.
+const MaxLength = 1024
+
+// MaxWords limits the number of words in the generated passphrases.
+const MaxWords = 64
+
+// ErrInvalidPolicy is returned when a policy can't produce a password.
+var ErrInvalidPolicy = errors.New("invalid password policy")
+
+//go:embed eff_large_wordlist.txt
+var effLargeWordList string
+
+// words is the word list the passphrases are made of.
+var words = parseWordList(effLargeWordList)
+
+// Policy describes the passwords to generate.
+//
+// Fields:
+//   - Length: The length of the password.
+//   - MinLower, MinUpper, MinDigits, MinSymbols: The minimum number of characters of the class,
+//     the class must be allowed if it's positive.
+//   - Lower, Upper, Digits, Symbols: Whether the character class may be used.
+//   - ExcludeAmbiguous: Whether the AmbiguousChars are excluded.
+type Policy struct {
+	Length           int
+	MinLower         int
+	MinUpper         int
+	MinDigits        int
+	MinSymbols       int
+	Lower            bool
+	Upper            bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool
+}
+
+// DefaultPolicy returns the policy of 20 characters of all the classes, at least one of each.
+func DefaultPolicy() Policy {
+	return Policy{
+		Length:     20,
+		Lower:      true,
+		Upper:      true,
+		Digits:     true,
+		Symbols:    true,
+		MinLower:   1,
+		MinUpper:   1,
+		MinDigits:  1,
+		MinSymbols: 1,
+	}
+}
+
+// class is a character class of a policy with the minimum number of its characters.
+type class struct {
+	chars []rune
+	min   int
+}
+
+// classes returns the allowed character classes of the policy.
+func (p Policy) classes() ([]class, error) {
+	var classes []class
+	for _, c := range []struct {
+		chars   string
+		allowed bool
+		min     int
+	}{
+		{LowerChars, p.Lower, p.MinLower},
+		{UpperChars, p.Upper, p.MinUpper},
+		{DigitChars, p.Digits, p.MinDigits},
+		{SymbolChars, p.Symbols, p.MinSymbols},
+	} {
+		if c.min < 0 || (c.min > 0 && !c.allowed) {
+			return nil, ErrInvalidPolicy
+		}
+		if !c.allowed {
+			continue
+		}
+		chars := c.chars
+		if p.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(AmbiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, class{chars: []rune(chars), min: c.min})
+	}
+	return classes, nil
+}
+
+// Entropy returns the entropy of the passwords generated with the policy, in bits, ignoring the minimum counts.
+func (p Policy) Entropy() float64 {
+	classes, err := p.classes()
+	if err != nil {
+		return 0
+	}
+	size := 0
+	for _, c := range classes {
+		size += len(c.chars)
+	}
+	if size == 0 {
+		return 0
+	}
+	return float64(p.Length) * math.Log2(float64(size))
+}
+
+// Password generates a random password following the policy.
+//
+// Parameters:
+//   - p: The policy of the password.
+//
+// Returns:
+//   - The password.
+//   - ErrInvalidPolicy if no class is allowed, the length is out of range or the minimum counts exceed it,
+//     or an error if the random source fails.
+func Password(p Policy) (string, error) {
+	classes, err := p.classes()
+	if err != nil {
+		return "", err
+	}
+	if len(classes) == 0 || p.Length < 1 || p.Length > MaxLength {
+		return "", ErrInvalidPolicy
+	}
+
+	var all []rune
+	required := 0
+	for _, c := range classes {
+		all = append(all, c.chars...)
+		required += c.min
+	}
+	if required > p.Length {
+		return "", ErrInvalidPolicy
+	}
+
+	password := make([]rune, 0, p.Length)
+	for _, c := range classes {
+		for i := 0; i < c.min; i++ {
+			r, err := pick(c.chars)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, r)
+		}
+	}
+	for len(password) < p.Length {
+		r, err := pick(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, r)
+	}
+
+	// The required characters come first, the shuffle spreads them over the password.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// Passphrase generates a diceware-style passphrase of random words from the EFF large word list.
+//
+// Parameters:
+//   - count: The number of words, from 1 to MaxWords.
+//   - separator: The string the words are joined with.
+//   - capitalize: Whether the first letter of every word is upper-case.
+//
+// Returns:
+//   - The passphrase.
+//   - ErrInvalidPolicy if the number of words is out of range, or an error if the random source fails.
+func Passphrase(count int, separator string, capitalize bool) (string, error) {
+	if count < 1 || count > MaxWords {
+		return "", ErrInvalidPolicy
+	}
+
+	chosen := make([]string, count)
+	for i := range chosen {
+		n, err := randomInt(len(words))
+		if err != nil {
+			return "", err
+		}
+		chosen[i] = words[n]
+		if capitalize {
+			first := []rune(chosen[i])
+			first[0] = unicode.ToUpper(first[0])
+			chosen[i] = string(first)
+		}
+	}
+	return strings.Join(chosen, separator), nil
+}
+
+// PassphraseEntropy returns the entropy of the passphrases of the number of words, in bits.
+func PassphraseEntropy(count int) float64 {
+	return float64(count) * math.Log2(float64(len(words)))
+}
+
+// pick returns a random character of the set.
+func pick(chars []rune) (rune, error) {
+	n, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n], nil
+}
+
+// randomInt returns a uniform random integer in [0, n) from crypto/rand.
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+// parseWordList returns the words of a word list in the EFF format: a line of the dice rolls
+// and the word separated by a tab for every word.
+func parseWordList(list string) []string {
+	var words []string
+	for _, line := range strings.Split(list, "\n") {
+		if _, word, found := strings.Cut(strings.TrimSpace(line), "\t"); found && word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,89 @@
+package passgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countIn(s, chars string) int {
+	n := 0
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPassword(t *testing.T) {
+	t.Run("default policy", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for i := 0; i < 50; i++ {
+			password, err := Password(DefaultPolicy())
+			require.NoError(t, err)
+			assert.Len(t, password, 20)
+			assert.GreaterOrEqual(t, countIn(password, LowerChars), 1)
+			assert.GreaterOrEqual(t, countIn(password, UpperChars), 1)
+			assert.GreaterOrEqual(t, countIn(password, DigitChars), 1)
+			assert.GreaterOrEqual(t, countIn(password, SymbolChars), 1)
+			assert.False(t, seen[password])
+			seen[password] = true
+		}
+	})
+
+	t.Run("minimum counts and excluded ambiguous characters", func(t *testing.T) {
+		p := Policy{Length: 12, Lower: true, Digits: true, MinDigits: 8, ExcludeAmbiguous: true}
+		for i := 0; i < 50; i++ {
+			password, err := Password(p)
+			require.NoError(t, err)
+			assert.Len(t, password, 12)
+			assert.GreaterOrEqual(t, countIn(password, DigitChars), 8)
+			assert.Zero(t, countIn(password, AmbiguousChars))
+			assert.Zero(t, countIn(password, UpperChars+SymbolChars))
+		}
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		for name, p := range map[string]Policy{
+			"no classes":            {Length: 10},
+			"zero length":           {Lower: true},
+			"too long":              {Length: MaxLength + 1, Lower: true},
+			"minimum of disallowed": {Length: 10, Lower: true, MinDigits: 1},
+			"negative minimum":      {Length: 10, Lower: true, MinLower: -1},
+			"minimums over length":  {Length: 3, Lower: true, Upper: true, MinLower: 2, MinUpper: 2},
+		} {
+			_, err := Password(p)
+			assert.ErrorIs(t, err, ErrInvalidPolicy, name)
+		}
+	})
+
+	assert.InDelta(t, 129.8, DefaultPolicy().Entropy(), 0.1)
+}
+
+func TestPassphrase(t *testing.T) {
+	assert.Len(t, words, 7776)
+	assert.Equal(t, "abacus", words[0])
+	assert.InDelta(t, 77.5, PassphraseEntropy(6), 0.1)
+
+	passphrase, err := Passphrase(6, "-", false)
+	require.NoError(t, err)
+	parts := strings.Split(passphrase, "-")
+	require.Len(t, parts, 6)
+	for _, part := range parts {
+		assert.Contains(t, words, part)
+	}
+
+	passphrase, err = Passphrase(4, " ", true)
+	require.NoError(t, err)
+	for _, part := range strings.Split(passphrase, " ") {
+		assert.Equal(t, strings.ToUpper(part[:1]), part[:1])
+	}
+
+	_, err = Passphrase(0, "-", false)
+	assert.ErrorIs(t, err, ErrInvalidPolicy)
+	_, err = Passphrase(MaxWords+1, "-", false)
+	assert.ErrorIs(t, err, ErrInvalidPolicy)
+}
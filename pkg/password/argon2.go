@@ -0,0 +1,152 @@
+// Package password provides functionality for hashing and verifying user passwords.
+//
+// This package includes the Hash and Verify functions, which use the Argon2id key derivation
+// function and store the result as an encoded PHC string:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Prefix is the prefix of every encoded Argon2id hash produced by this package.
+const Prefix = "$argon2id$"
+
+// ErrInvalidHash is returned when an encoded hash has an unexpected format.
+var ErrInvalidHash = errors.New("invalid encoded argon2id hash")
+
+// ErrIncompatibleVersion is returned when an encoded hash was produced by an unsupported Argon2 version.
+var ErrIncompatibleVersion = errors.New("incompatible argon2 version")
+
+// Params holds the tunable Argon2id parameters.
+//
+// Fields:
+//   - Memory: The amount of memory used by the algorithm, in KiB.
+//   - Iterations: The number of passes over the memory.
+//   - Parallelism: The number of threads used by the algorithm.
+//   - SaltLength: The length of the random salt, in bytes.
+//   - KeyLength: The length of the generated hash, in bytes.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams contains the recommended Argon2id parameters for interactive logins.
+var DefaultParams = &Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Hash derives an Argon2id hash of the password using a random salt.
+//
+// Parameters:
+//   - password: The plaintext password to hash.
+//   - p: The Argon2id parameters. If nil, DefaultParams are used.
+//
+// Returns:
+//   - The encoded PHC string containing the parameters, salt and hash.
+//   - An error if the random salt could not be generated.
+func Hash(password string, p *Params) (string, error) {
+	if p == nil {
+		p = DefaultParams
+	}
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		Prefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify checks whether the password matches the encoded Argon2id hash.
+//
+// Parameters:
+//   - password: The plaintext password to check.
+//   - encoded: The encoded PHC string produced by Hash.
+//
+// Returns:
+//   - true if the password matches the hash.
+//   - An error if the encoded hash could not be parsed.
+//
+// The comparison of the derived and stored hashes is performed in constant time.
+func Verify(password, encoded string) (bool, error) {
+	p, salt, key, err := decode(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+// IsHash reports whether the value looks like an encoded Argon2id hash.
+func IsHash(encoded string) bool {
+	return strings.HasPrefix(encoded, Prefix)
+}
+
+// NeedsRehash reports whether the encoded hash was produced with parameters different from p.
+// Hashes that can't be parsed always need a rehash.
+func NeedsRehash(encoded string, p *Params) bool {
+	if p == nil {
+		p = DefaultParams
+	}
+	hp, _, _, err := decode(encoded)
+	if err != nil {
+		return true
+	}
+	return hp.Memory != p.Memory || hp.Iterations != p.Iterations ||
+		hp.Parallelism != p.Parallelism || hp.KeyLength != p.KeyLength
+}
+
+func decode(encoded string) (*Params, []byte, []byte, error) {
+	vals := strings.Split(encoded, "$")
+	if len(vals) != 6 || vals[1] != "argon2id" {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(vals[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, ErrIncompatibleVersion
+	}
+
+	p := &Params{}
+	if _, err := fmt.Sscanf(vals[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(vals[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.SaltLength = uint32(len(salt))
+
+	key, err := base64.RawStdEncoding.DecodeString(vals[5])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.KeyLength = uint32(len(key))
+
+	return p, salt, key, nil
+}
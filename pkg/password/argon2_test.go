@@ -0,0 +1,98 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testParams = &Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHash(t *testing.T) {
+	encoded, err := Hash("password", testParams)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, IsHash(encoded))
+
+	other, err := Hash("password", testParams)
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, other, "hashes of the same password must use different salts")
+}
+
+func TestVerify(t *testing.T) {
+	encoded, err := Hash("password", testParams)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "valid password",
+			password: "password",
+			encoded:  encoded,
+			want:     true,
+		},
+		{
+			name:     "invalid password",
+			password: "badPassword",
+			encoded:  encoded,
+			want:     false,
+		},
+		{
+			name:     "invalid hash format",
+			password: "password",
+			encoded:  "not a hash",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid salt encoding",
+			password: "password",
+			encoded:  "$argon2id$v=19$m=1024,t=1,p=1$!!!$AAAA",
+			wantErr:  true,
+		},
+		{
+			name:     "incompatible version",
+			password: "password",
+			encoded:  "$argon2id$v=16$m=1024,t=1,p=1$AAAA$AAAA",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify(tt.password, tt.encoded)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestIsHash(t *testing.T) {
+	assert.True(t, IsHash("$argon2id$v=19$m=1024,t=1,p=1$AAAA$AAAA"))
+	assert.False(t, IsHash("klaukCkDbN4VoPJ8dD1x+C92TyHBeO0h8krNeMNC9ZDaQY4w2Q=="))
+}
+
+func TestNeedsRehash(t *testing.T) {
+	encoded, err := Hash("password", testParams)
+	require.NoError(t, err)
+
+	assert.False(t, NeedsRehash(encoded, testParams))
+	assert.True(t, NeedsRehash(encoded, &Params{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}))
+	assert.True(t, NeedsRehash("not a hash", testParams))
+}
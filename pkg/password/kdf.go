@@ -0,0 +1,75 @@
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// KeyLength is the length of the keys derived by DeriveKey, which is suitable for AES-256.
+const KeyLength = 32
+
+// ErrWeakKDFParams is returned when key derivation parameters are outside the accepted bounds.
+var ErrWeakKDFParams = errors.New("key derivation parameters are out of bounds")
+
+// KDF parameter bounds. The lower bounds follow the OWASP recommendations for Argon2id, the upper
+// bounds protect clients from parameters that would take unreasonably long to evaluate.
+const (
+	minKDFSaltLength  = 16
+	minKDFMemory      = 19 * 1024
+	maxKDFMemory      = 2 * 1024 * 1024
+	minKDFIterations  = 2
+	maxKDFIterations  = 32
+	maxKDFParallelism = 16
+)
+
+// NewSalt generates a random salt for DeriveKey.
+//
+// Parameters:
+//   - p: The Argon2id parameters defining the salt length. If nil, DefaultParams are used.
+//
+// Returns:
+//   - The generated salt.
+//   - An error if random bytes could not be generated.
+func NewSalt(p *Params) ([]byte, error) {
+	if p == nil {
+		p = DefaultParams
+	}
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// DeriveKey derives an encryption key of KeyLength bytes from the password with Argon2id.
+//
+// Unlike Hash, the salt is provided by the caller, so the same key is derived every time the same
+// password, salt and parameters are used. The KeyLength and SaltLength fields of the parameters are ignored.
+//
+// Parameters:
+//   - password: The password to derive the key from.
+//   - salt: The salt, at least 16 bytes long.
+//   - p: The Argon2id parameters.
+//
+// Returns:
+//   - The derived key.
+//   - ErrWeakKDFParams if the salt or the parameters are out of bounds.
+func DeriveKey(password string, salt []byte, p *Params) ([]byte, error) {
+	if err := CheckKDFParams(salt, p); err != nil {
+		return nil, err
+	}
+	return argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, KeyLength), nil
+}
+
+// CheckKDFParams reports whether the salt and the Argon2id parameters are acceptable for DeriveKey.
+func CheckKDFParams(salt []byte, p *Params) error {
+	if p == nil || len(salt) < minKDFSaltLength ||
+		p.Memory < minKDFMemory || p.Memory > maxKDFMemory ||
+		p.Iterations < minKDFIterations || p.Iterations > maxKDFIterations ||
+		p.Parallelism < 1 || p.Parallelism > maxKDFParallelism {
+		return ErrWeakKDFParams
+	}
+	return nil
+}
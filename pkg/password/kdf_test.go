@@ -0,0 +1,79 @@
+package password
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKDFParams = &Params{
+	Memory:      minKDFMemory,
+	Iterations:  minKDFIterations,
+	Parallelism: 1,
+	SaltLength:  16,
+}
+
+func TestNewSalt(t *testing.T) {
+	salt, err := NewSalt(testKDFParams)
+	require.NoError(t, err)
+	assert.Len(t, salt, 16)
+
+	other, err := NewSalt(nil)
+	require.NoError(t, err)
+	assert.Len(t, other, int(DefaultParams.SaltLength))
+	assert.False(t, bytes.Equal(salt, other))
+}
+
+func TestDeriveKey(t *testing.T) {
+	salt, err := NewSalt(testKDFParams)
+	require.NoError(t, err)
+
+	key, err := DeriveKey("master", salt, testKDFParams)
+	require.NoError(t, err)
+	assert.Len(t, key, KeyLength)
+
+	same, err := DeriveKey("master", salt, testKDFParams)
+	require.NoError(t, err)
+	assert.Equal(t, key, same)
+
+	other, err := DeriveKey("other", salt, testKDFParams)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	otherSalt, err := NewSalt(testKDFParams)
+	require.NoError(t, err)
+	other, err = DeriveKey("master", otherSalt, testKDFParams)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestCheckKDFParams(t *testing.T) {
+	salt := make([]byte, 16)
+	tests := []struct {
+		name    string
+		salt    []byte
+		params  *Params
+		wantErr bool
+	}{
+		{name: "defaults", salt: salt, params: DefaultParams},
+		{name: "minimal", salt: salt, params: testKDFParams},
+		{name: "nil params", salt: salt, params: nil, wantErr: true},
+		{name: "short salt", salt: salt[:8], params: DefaultParams, wantErr: true},
+		{name: "low memory", salt: salt, params: &Params{Memory: 1024, Iterations: 3, Parallelism: 1}, wantErr: true},
+		{name: "huge memory", salt: salt, params: &Params{Memory: 1 << 30, Iterations: 3, Parallelism: 1}, wantErr: true},
+		{name: "single iteration", salt: salt, params: &Params{Memory: 64 * 1024, Iterations: 1, Parallelism: 1}, wantErr: true},
+		{name: "zero parallelism", salt: salt, params: &Params{Memory: 64 * 1024, Iterations: 3}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKDFParams(tt.salt, tt.params)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrWeakKDFParams)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
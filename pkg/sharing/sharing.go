@@ -0,0 +1,154 @@
+// Package sharing provides functionality for sharing encrypted items between users.
+//
+// Every user has an X25519 key pair. A shared item is encrypted with its own random data key, which is
+// wrapped for every user having access to the item: an ephemeral X25519 key agreement with the public key
+// of the user produces, through HKDF-SHA256, the AES-256 key encrypting the data key. Only the holder of the
+// private key can unwrap it, the server storing the wrapped keys can't.
+package sharing
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+)
+
+// KeySize is the size of the public and private X25519 keys and of the data keys, in bytes.
+const KeySize = 32
+
+// wrapInfo binds the keys derived for wrapping to their purpose.
+const wrapInfo = "gophkeeper item data key"
+
+var (
+	// ErrInvalidKey is returned when a public or private key is malformed.
+	ErrInvalidKey = errors.New("invalid sharing key")
+	// ErrUnwrap is returned when a wrapped data key is malformed or has been wrapped for another key.
+	ErrUnwrap = errors.New("failed to unwrap data key")
+)
+
+// GenerateKeyPair generates a new X25519 key pair.
+//
+// Returns:
+//   - The public key.
+//   - The private key.
+//   - An error if the random source fails.
+func GenerateKeyPair() ([]byte, []byte, error) {
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return private.PublicKey().Bytes(), private.Bytes(), nil
+}
+
+// PublicKey returns the public key of a private key.
+//
+// Returns:
+//   - The public key.
+//   - ErrInvalidKey if the private key is malformed.
+func PublicKey(private []byte) ([]byte, error) {
+	key, err := ecdh.X25519().NewPrivateKey(private)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return key.PublicKey().Bytes(), nil
+}
+
+// NewDataKey generates a random data key, which encrypts an item with the aes package.
+func NewDataKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+// WrapKey encrypts a data key for the owner of a public key.
+//
+// Parameters:
+//   - public: The X25519 public key of the user the data key is wrapped for.
+//   - dataKey: The data key.
+//
+// Returns:
+//   - The base64-encoded ephemeral public key followed by the data key encrypted with AES-GCM.
+//   - ErrInvalidKey if the public key is malformed, or another error if the encryption fails.
+func WrapKey(public []byte, dataKey string) (string, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(public)
+	if err != nil {
+		return "", ErrInvalidKey
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return "", err
+	}
+	key, err := wrappingKey(secret, ephemeral.PublicKey().Bytes(), public)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := aes.Encrypt(key, dataKey)
+	if err != nil {
+		return "", err
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(append(ephemeral.PublicKey().Bytes(), cipherText...)), nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey.
+//
+// Parameters:
+//   - private: The X25519 private key of the user the data key has been wrapped for.
+//   - wrapped: The wrapped data key.
+//
+// Returns:
+//   - The data key.
+//   - ErrInvalidKey if the private key is malformed, or ErrUnwrap if the data key can't be unwrapped.
+func UnwrapKey(private []byte, wrapped string) (string, error) {
+	key, err := ecdh.X25519().NewPrivateKey(private)
+	if err != nil {
+		return "", ErrInvalidKey
+	}
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(data) <= KeySize {
+		return "", ErrUnwrap
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(data[:KeySize])
+	if err != nil {
+		return "", ErrUnwrap
+	}
+	secret, err := key.ECDH(ephemeral)
+	if err != nil {
+		return "", ErrUnwrap
+	}
+	wrapKey, err := wrappingKey(secret, data[:KeySize], key.PublicKey().Bytes())
+	if err != nil {
+		return "", ErrUnwrap
+	}
+	dataKey, err := aes.Decrypt(wrapKey, base64.StdEncoding.EncodeToString(data[KeySize:]))
+	if err != nil {
+		return "", ErrUnwrap
+	}
+	return dataKey, nil
+}
+
+// wrappingKey derives the AES-256 key wrapping a data key from the X25519 shared secret. The ephemeral
+// public key and the public key of the recipient are mixed into the derivation.
+func wrappingKey(secret, ephemeral, recipient []byte) (string, error) {
+	salt := append(append([]byte{}, ephemeral...), recipient...)
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(wrapInfo)), key); err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
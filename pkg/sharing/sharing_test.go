@@ -0,0 +1,84 @@
+package sharing
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vidkin/gophkeeper/pkg/aes"
+)
+
+func TestWrapKey(t *testing.T) {
+	public, private, err := GenerateKeyPair()
+	require.NoError(t, err)
+	assert.Len(t, public, KeySize)
+	assert.Len(t, private, KeySize)
+
+	derived, err := PublicKey(private)
+	require.NoError(t, err)
+	assert.Equal(t, public, derived)
+
+	dataKey, err := NewDataKey()
+	require.NoError(t, err)
+	assert.Len(t, dataKey, KeySize)
+
+	wrapped, err := WrapKey(public, dataKey)
+	require.NoError(t, err)
+	unwrapped, err := UnwrapKey(private, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// The data key encrypts the items with the aes package.
+	encrypted, err := aes.Encrypt(unwrapped, "secret")
+	require.NoError(t, err)
+	text, err := aes.Decrypt(dataKey, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", text)
+
+	// Every wrap uses a new ephemeral key.
+	again, err := WrapKey(public, dataKey)
+	require.NoError(t, err)
+	assert.NotEqual(t, wrapped, again)
+}
+
+func TestUnwrapKey_Errors(t *testing.T) {
+	public, private, err := GenerateKeyPair()
+	require.NoError(t, err)
+	_, otherPrivate, err := GenerateKeyPair()
+	require.NoError(t, err)
+	dataKey, err := NewDataKey()
+	require.NoError(t, err)
+	wrapped, err := WrapKey(public, dataKey)
+	require.NoError(t, err)
+
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 1
+	tampered := base64.StdEncoding.EncodeToString(data)
+
+	tests := []struct {
+		name    string
+		private []byte
+		wrapped string
+		wantErr error
+	}{
+		{name: "another key", private: otherPrivate, wrapped: wrapped, wantErr: ErrUnwrap},
+		{name: "tampered", private: private, wrapped: tampered, wantErr: ErrUnwrap},
+		{name: "not base64", private: private, wrapped: "not base64", wantErr: ErrUnwrap},
+		{name: "too short", private: private, wrapped: base64.StdEncoding.EncodeToString(data[:KeySize]), wantErr: ErrUnwrap},
+		{name: "invalid private key", private: []byte("short"), wrapped: wrapped, wantErr: ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnwrapKey(tt.private, tt.wrapped)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	_, err = WrapKey([]byte("short"), dataKey)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = PublicKey([]byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,198 @@
+// Package signature provides functionality for signing requests with HMAC-SHA256 and verifying them.
+//
+// A request signature covers the scheme version, the full method name, a timestamp, a random nonce and
+// the marshaled request body, so a captured request can't be replayed for another method, outside of the
+// clock skew window, or twice within it. Every message sent by the client in a stream is additionally
+// signed with the nonce of the stream and the message index, so messages can't be moved between streams,
+// reordered or repeated.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Version is the version of the signing scheme.
+const Version = "v1"
+
+// Metadata keys carrying the signature of a request.
+const (
+	VersionKey   = "signature-version"
+	TimestampKey = "signature-timestamp"
+	NonceKey     = "signature-nonce"
+	SignatureKey = "signature"
+)
+
+// DefaultSkew is the default maximum difference between the request timestamp and the server clock.
+const DefaultSkew = time.Minute
+
+// maxNonceLength limits the nonces kept in the cache.
+const maxNonceLength = 64
+
+var (
+	// ErrUnsupportedVersion is returned when a request is signed with an unknown scheme version.
+	ErrUnsupportedVersion = errors.New("unsupported signature version")
+	// ErrInvalidSignature is returned when a signature is malformed or doesn't match the request.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrExpired is returned when the request timestamp is outside of the clock skew window.
+	ErrExpired = errors.New("request timestamp is outside of the allowed window")
+	// ErrReplayed is returned when the nonce of a request has already been used.
+	ErrReplayed = errors.New("request nonce has already been used")
+)
+
+// NewNonce generates a random nonce for a request.
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign computes the signature of a request.
+//
+// Parameters:
+//   - key: The signing key shared by the client and the server.
+//   - method: The full gRPC method name, e.g. "/gophkeeper.Gophkeeper/AddBankCard".
+//   - timestamp: The Unix time of the request in seconds.
+//   - nonce: A random nonce, unique for every request.
+//   - body: The marshaled request, empty for the requests opening a client stream.
+//
+// Returns:
+//   - The HMAC-SHA256 of the request.
+func Sign(key, method string, timestamp int64, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(Version + "\n" + method + "\n" + strconv.FormatInt(timestamp, 10) + "\n" + nonce + "\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// SignMessage computes the signature of a message sent by the client in a stream.
+//
+// Parameters:
+//   - key: The signing key shared by the client and the server.
+//   - nonce: The nonce of the request that opened the stream.
+//   - index: The index of the message in the stream, starting from 0.
+//   - body: The marshaled message without its signature.
+//
+// Returns:
+//   - The HMAC-SHA256 of the message.
+func SignMessage(key, nonce string, index int, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(Version + "\nmessage\n" + nonce + "\n" + strconv.Itoa(index) + "\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Verifier verifies request signatures and remembers the nonces of the verified requests.
+type Verifier struct {
+	now    func() time.Time
+	nonces *NonceCache
+	key    string
+	skew   time.Duration
+}
+
+// NewVerifier creates a verifier of the requests signed with the key.
+//
+// Parameters:
+//   - key: The signing key shared by the client and the server.
+//   - skew: The maximum difference between the request timestamp and the server clock. The nonces are
+//     remembered for twice as long, which covers all the timestamps accepted within the window.
+//
+// Returns:
+//   - A pointer to the created Verifier.
+func NewVerifier(key string, skew time.Duration) *Verifier {
+	return &Verifier{
+		now:    time.Now,
+		nonces: NewNonceCache(2 * skew),
+		key:    key,
+		skew:   skew,
+	}
+}
+
+// Verify checks the signature of a request and records its nonce.
+//
+// Parameters:
+//   - version: The version of the signing scheme used by the client.
+//   - method: The full gRPC method name.
+//   - timestamp: The Unix time of the request in seconds, as sent by the client.
+//   - nonce: The nonce of the request.
+//   - body: The marshaled request, empty for the requests opening a client stream.
+//   - signature: The signature sent by the client.
+//
+// Returns:
+//   - ErrUnsupportedVersion, ErrInvalidSignature, ErrExpired or ErrReplayed if the request is rejected.
+func (v *Verifier) Verify(version, method, timestamp, nonce string, body, signature []byte) error {
+	if version != Version {
+		return ErrUnsupportedVersion
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || nonce == "" || len(nonce) > maxNonceLength {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(signature, Sign(v.key, method, ts, nonce, body)) {
+		return ErrInvalidSignature
+	}
+	now := v.now()
+	if diff := now.Sub(time.Unix(ts, 0)); diff > v.skew || diff < -v.skew {
+		return ErrExpired
+	}
+	if !v.nonces.Add(nonce, now) {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// VerifyMessage checks the signature of a message sent by the client in a stream.
+//
+// Returns:
+//   - ErrInvalidSignature if the signature doesn't match the message.
+func (v *Verifier) VerifyMessage(nonce string, index int, body, signature []byte) error {
+	if !hmac.Equal(signature, SignMessage(v.key, nonce, index, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NonceCache remembers the nonces of the recent requests for a limited time.
+type NonceCache struct {
+	nonces    map[string]time.Time
+	lastSweep time.Time
+	ttl       time.Duration
+	mu        sync.Mutex
+}
+
+// NewNonceCache creates a cache remembering the nonces for ttl.
+func NewNonceCache(ttl time.Duration) *NonceCache {
+	return &NonceCache{
+		nonces: make(map[string]time.Time),
+		ttl:    ttl,
+	}
+}
+
+// Add records the nonce and reports whether it hasn't been recorded within ttl before now.
+// Expired nonces are swept at most once per ttl.
+func (c *NonceCache) Add(nonce string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for n, expires := range c.nonces {
+			if !now.Before(expires) {
+				delete(c.nonces, n)
+			}
+		}
+		c.lastSweep = now
+	}
+	if expires, ok := c.nonces[nonce]; ok && now.Before(expires) {
+		return false
+	}
+	c.nonces[nonce] = now.Add(c.ttl)
+	return true
+}
@@ -0,0 +1,177 @@
+package signature
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	const (
+		key    = "test-key"
+		method = "/gophkeeper.Gophkeeper/GetNote"
+	)
+	now := time.Unix(1700000000, 0)
+	body := []byte("request")
+
+	newVerifier := func() *Verifier {
+		v := NewVerifier(key, time.Minute)
+		v.now = func() time.Time { return now }
+		return v
+	}
+	ts := func(t time.Time) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	tests := []struct {
+		name      string
+		version   string
+		method    string
+		timestamp time.Time
+		nonce     string
+		body      []byte
+		signature []byte
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			version:   Version,
+			method:    method,
+			timestamp: now,
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign(key, method, now.Unix(), "nonce", body),
+		},
+		{
+			name:      "within skew",
+			version:   Version,
+			method:    method,
+			timestamp: now.Add(-time.Minute),
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign(key, method, now.Add(-time.Minute).Unix(), "nonce", body),
+		},
+		{
+			name:      "unsupported version",
+			version:   "v0",
+			method:    method,
+			timestamp: now,
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign(key, method, now.Unix(), "nonce", body),
+			wantErr:   ErrUnsupportedVersion,
+		},
+		{
+			name:      "wrong key",
+			version:   Version,
+			method:    method,
+			timestamp: now,
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign("another-key", method, now.Unix(), "nonce", body),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "tampered body",
+			version:   Version,
+			method:    method,
+			timestamp: now,
+			nonce:     "nonce",
+			body:      []byte("tampered"),
+			signature: Sign(key, method, now.Unix(), "nonce", body),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "another method",
+			version:   Version,
+			method:    "/gophkeeper.Gophkeeper/RemoveNote",
+			timestamp: now,
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign(key, method, now.Unix(), "nonce", body),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "empty nonce",
+			version:   Version,
+			method:    method,
+			timestamp: now,
+			body:      body,
+			signature: Sign(key, method, now.Unix(), "", body),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "expired",
+			version:   Version,
+			method:    method,
+			timestamp: now.Add(-2 * time.Minute),
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign(key, method, now.Add(-2*time.Minute).Unix(), "nonce", body),
+			wantErr:   ErrExpired,
+		},
+		{
+			name:      "from the future",
+			version:   Version,
+			method:    method,
+			timestamp: now.Add(2 * time.Minute),
+			nonce:     "nonce",
+			body:      body,
+			signature: Sign(key, method, now.Add(2*time.Minute).Unix(), "nonce", body),
+			wantErr:   ErrExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newVerifier().Verify(tt.version, tt.method, ts(tt.timestamp), tt.nonce, tt.body, tt.signature)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("replayed", func(t *testing.T) {
+		v := newVerifier()
+		sig := Sign(key, method, now.Unix(), "nonce", body)
+		require.NoError(t, v.Verify(Version, method, ts(now), "nonce", body, sig))
+		assert.ErrorIs(t, v.Verify(Version, method, ts(now), "nonce", body, sig), ErrReplayed)
+	})
+}
+
+func TestVerifier_VerifyMessage(t *testing.T) {
+	v := NewVerifier("test-key", time.Minute)
+	sig := SignMessage("test-key", "nonce", 1, []byte("chunk"))
+
+	assert.NoError(t, v.VerifyMessage("nonce", 1, []byte("chunk"), sig))
+	assert.ErrorIs(t, v.VerifyMessage("nonce", 0, []byte("chunk"), sig), ErrInvalidSignature)
+	assert.ErrorIs(t, v.VerifyMessage("another", 1, []byte("chunk"), sig), ErrInvalidSignature)
+	assert.ErrorIs(t, v.VerifyMessage("nonce", 1, []byte("tampered"), sig), ErrInvalidSignature)
+}
+
+func TestNonceCache_Add(t *testing.T) {
+	c := NewNonceCache(time.Minute)
+	now := time.Unix(1700000000, 0)
+
+	assert.True(t, c.Add("first", now))
+	assert.False(t, c.Add("first", now.Add(30*time.Second)))
+	assert.True(t, c.Add("second", now.Add(30*time.Second)))
+
+	assert.True(t, c.Add("first", now.Add(time.Minute)))
+	_, ok := c.nonces["second"]
+	assert.True(t, ok)
+
+	c.Add("third", now.Add(3*time.Minute))
+	assert.Len(t, c.nonces, 1)
+}
+
+func TestNewNonce(t *testing.T) {
+	first, err := NewNonce()
+	require.NoError(t, err)
+	second, err := NewNonce()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, first)
+	assert.LessOrEqual(t, len(first), maxNonceLength)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,222 @@
+// Package strength provides a password strength estimator.
+//
+// The estimate starts from the entropy of a random password of the same length drawn from the character
+// classes the password uses, and lowers it for the patterns attackers try first: common passwords with
+// letter substitutions and affixes, repeated characters, alphabetical or numerical sequences and keyboard walks.
+package strength
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// WeakEntropy is the estimated entropy, in bits, below which a password is considered weak.
+const WeakEntropy = 50
+
+// MinLength is the length below which a password is reported as too short.
+const MinLength = 10
+
+// The issues found in the passwords.
+const (
+	IssueEmpty          = "empty password"
+	IssueShort          = "too short"
+	IssueCommon         = "based on a common password"
+	IssueRepeats        = "repeated characters"
+	IssueSequence       = "character sequence"
+	IssueKeyboard       = "keyboard pattern"
+	IssueSingleCharType = "only one type of characters"
+)
+
+// patternBits is the entropy of a character continuing a repeat, a sequence or a keyboard walk.
+const patternBits = 1
+
+// commonPasswords are the most frequent passwords and the words they are based on.
+var commonPasswords = map[string]bool{
+	"password": true, "passw": true, "pass": true, "qwerty": true, "qwertyuiop": true, "asdfgh": true,
+	"letmein": true, "welcome": true, "admin": true, "administrator": true, "login": true, "master": true,
+	"secret": true, "iloveyou": true, "love": true, "princess": true, "dragon": true, "monkey": true,
+	"football": true, "baseball": true, "soccer": true, "hockey": true, "superman": true, "batman": true,
+	"sunshine": true, "shadow": true, "trustno": true, "starwars": true, "whatever": true, "freedom": true,
+	"michael": true, "jordan": true, "charlie": true, "jennifer": true, "hunter": true, "killer": true,
+	"ninja": true, "mustang": true, "access": true, "flower": true, "hello": true, "test": true, "guest": true,
+	"root": true, "changeme": true, "default": true, "summer": true, "winter": true, "spring": true,
+	"autumn": true, "gophkeeper": true, "user": true, "abc": true, "zaq": true, "zxcvbnm": true,
+}
+
+// leet maps the common letter substitutions back to the letters.
+var leet = strings.NewReplacer("@", "a", "4", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t")
+
+// keyboardRows are the rows of the QWERTY layout used to detect keyboard walks.
+var keyboardRows = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+
+// Report is the result of a password strength estimation.
+//
+// Fields:
+//   - Issues: The weaknesses found in the password, Issue constants.
+//   - Entropy: The estimated entropy of the password, in bits.
+type Report struct {
+	Issues  []string
+	Entropy float64
+}
+
+// Weak reports whether the estimated entropy of the password is below WeakEntropy.
+func (r Report) Weak() bool {
+	return r.Entropy < WeakEntropy
+}
+
+// Estimate estimates the strength of a password.
+//
+// Parameters:
+//   - password: The plaintext password.
+//
+// Returns:
+//   - The Report with the estimated entropy and the issues found.
+func Estimate(password string) Report {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Report{Issues: []string{IssueEmpty}}
+	}
+
+	var r Report
+	issue := func(name string) {
+		for _, found := range r.Issues {
+			if found == name {
+				return
+			}
+		}
+		r.Issues = append(r.Issues, name)
+	}
+	if len(runes) < MinLength {
+		issue(IssueShort)
+	}
+
+	classes, size := charset(runes)
+	if classes == 1 {
+		issue(IssueSingleCharType)
+	}
+	charBits := math.Log2(float64(size))
+
+	// The characters of a common password are replaced by the guess of the word in the list.
+	start, end := commonWord(runes)
+	if start < end {
+		issue(IssueCommon)
+		r.Entropy += math.Log2(float64(len(commonPasswords)))
+	}
+
+	// A character continuing the pattern of the previous one adds almost nothing to the guesses.
+	prevStep := stepNone
+	for i := range runes {
+		step := stepNone
+		if i > 0 {
+			step = stepOf(runes[i-1], runes[i])
+		}
+		continuing := step != stepNone && step == prevStep
+		prevStep = step
+		switch {
+		case i >= start && i < end:
+		case continuing:
+			issue(stepIssues[step])
+			r.Entropy += patternBits
+		default:
+			r.Entropy += charBits
+		}
+	}
+	return r
+}
+
+// The kinds of the steps between adjacent characters.
+const (
+	stepNone = iota
+	stepRepeat
+	stepUp
+	stepDown
+	stepKeyboard
+)
+
+// stepIssues maps the kinds of the steps to the issues they are reported as.
+var stepIssues = map[int]string{
+	stepRepeat:   IssueRepeats,
+	stepUp:       IssueSequence,
+	stepDown:     IssueSequence,
+	stepKeyboard: IssueKeyboard,
+}
+
+// stepOf returns the kind of the step between two adjacent characters, ignoring the case.
+func stepOf(prev, cur rune) int {
+	prev, cur = unicode.ToLower(prev), unicode.ToLower(cur)
+	switch {
+	case cur == prev:
+		return stepRepeat
+	case cur-prev == 1:
+		return stepUp
+	case prev-cur == 1:
+		return stepDown
+	case keyboardAdjacent(prev, cur):
+		return stepKeyboard
+	}
+	return stepNone
+}
+
+// charset returns the number of the character classes the password uses and the size of their union.
+func charset(runes []rune) (int, int) {
+	var lower, upper, digit, symbol, other bool
+	for _, c := range runes {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digit = true
+		case c < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	classes, size := 0, 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			classes++
+			size += class.size
+		}
+	}
+	return classes, size
+}
+
+// commonWord returns the bounds of the longest common password contained in the password, ignoring
+// the case and the letter substitutions. The bounds are equal if there is none.
+func commonWord(runes []rune) (int, int) {
+	normalized := []rune(leet.Replace(strings.ToLower(string(runes))))
+	if len(normalized) != len(runes) {
+		return 0, 0
+	}
+
+	bestStart, bestEnd := 0, 0
+	for i := range normalized {
+		for j := len(normalized); j-i > bestEnd-bestStart && j-i >= 3; j-- {
+			if commonPasswords[string(normalized[i:j])] {
+				bestStart, bestEnd = i, j
+				break
+			}
+		}
+	}
+	return bestStart, bestEnd
+}
+
+// keyboardAdjacent reports whether two characters are neighbours in a row of the keyboard.
+func keyboardAdjacent(a, b rune) bool {
+	for _, row := range keyboardRows {
+		i := strings.IndexRune(row, a)
+		j := strings.IndexRune(row, b)
+		if i >= 0 && j >= 0 && (i-j == 1 || j-i == 1) {
+			return true
+		}
+	}
+	return false
+}
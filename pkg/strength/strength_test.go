@@ -0,0 +1,45 @@
+package strength
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		issues   []string
+		weak     bool
+	}{
+		{name: "empty", password: "", issues: []string{IssueEmpty}, weak: true},
+		{name: "common", password: "password", issues: []string{IssueShort, IssueSingleCharType, IssueCommon}, weak: true},
+		{name: "common with substitutions and affixes", password: "P@ssw0rd123!", issues: []string{IssueCommon, IssueSequence}, weak: true},
+		{name: "repeats", password: "aaaaaaaaaaaaaaaa", issues: []string{IssueSingleCharType, IssueRepeats}, weak: true},
+		{name: "sequence", password: "abcdefghijklmnop", issues: []string{IssueSingleCharType, IssueSequence}, weak: true},
+		{name: "keyboard walk", password: "Sdfghjkl;'", issues: []string{IssueKeyboard}, weak: true},
+		{name: "short random", password: "x7#Kq", issues: []string{IssueShort}, weak: true},
+		{name: "random", password: "x7#Kq9vL2!mZ", weak: false},
+		{name: "passphrase", password: "correct horse battery staple", weak: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Estimate(tt.password)
+			for _, issue := range tt.issues {
+				assert.Contains(t, r.Issues, issue)
+			}
+			if tt.issues == nil {
+				assert.Empty(t, r.Issues)
+			}
+			assert.Equal(t, tt.weak, r.Weak(), "entropy %.1f", r.Entropy)
+		})
+	}
+}
+
+func TestEstimate_PatternsLowerEntropy(t *testing.T) {
+	random := Estimate("q8Zr2mX4")
+	walk := Estimate("qwertyui")
+	assert.Less(t, walk.Entropy, random.Entropy)
+	assert.Less(t, Estimate("Summer2024").Entropy, Estimate("Sxmqer2094").Entropy)
+}
@@ -0,0 +1,259 @@
+// Package totp provides functionality for generating and validating time-based one-time passwords.
+//
+// This package implements RFC 6238 (TOTP) on top of RFC 4226 (HOTP) and includes helpers for
+// generating shared secrets and building otpauth:// provisioning URIs understood by authenticator apps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported HMAC algorithms.
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+)
+
+// SecretSize is the size of generated secrets in bytes (160 bits, as recommended by RFC 4226).
+const SecretSize = 20
+
+// ErrInvalidSecret is returned when a secret is not a valid base32 string.
+var ErrInvalidSecret = errors.New("invalid base32 totp secret")
+
+// Options holds the TOTP generation parameters.
+//
+// Fields:
+//   - Algorithm: The HMAC algorithm (SHA1, SHA256 or SHA512).
+//   - Digits: The number of digits in a code (6 or 8).
+//   - Period: The validity period of a code, in seconds.
+type Options struct {
+	Algorithm string
+	Digits    int
+	Period    uint
+}
+
+// DefaultOptions contains the parameters used by most authenticator apps.
+var DefaultOptions = &Options{
+	Algorithm: AlgorithmSHA1,
+	Digits:    6,
+	Period:    30,
+}
+
+// GenerateSecret returns a new random secret encoded as unpadded base32.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// Counter returns the time step counter for the given moment.
+func Counter(t time.Time, opts *Options) int64 {
+	if opts == nil {
+		opts = DefaultOptions
+	}
+	return t.Unix() / int64(opts.Period)
+}
+
+// GenerateCode returns the TOTP code for the given moment.
+//
+// Parameters:
+//   - secret: The base32-encoded shared secret.
+//   - t: The moment for which the code is generated.
+//   - opts: The TOTP parameters. If nil, DefaultOptions are used.
+//
+// Returns:
+//   - The zero-padded numeric code.
+//   - An error if the secret or the options are invalid.
+func GenerateCode(secret string, t time.Time, opts *Options) (string, error) {
+	return GenerateCodeForCounter(secret, Counter(t, opts), opts)
+}
+
+// GenerateCodeForCounter returns the HOTP code for the given counter value.
+func GenerateCodeForCounter(secret string, counter int64, opts *Options) (string, error) {
+	if opts == nil {
+		opts = DefaultOptions
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	newHash, err := hashFunc(opts.Algorithm)
+	if err != nil {
+		return "", err
+	}
+	if opts.Digits < 6 || opts.Digits > 10 {
+		return "", fmt.Errorf("unsupported number of digits: %d", opts.Digits)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := int64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+
+	mod := int64(1)
+	for i := 0; i < opts.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", opts.Digits, value%mod), nil
+}
+
+// Validate checks the code against the secret, accepting codes from up to skew time steps
+// before or after the given moment to tolerate clock drift.
+//
+// Returns:
+//   - The counter value of the matching time step, which callers may persist to reject replays.
+//   - true if the code is valid.
+//   - An error if the secret or the options are invalid.
+//
+// Codes are compared in constant time.
+func Validate(code, secret string, t time.Time, opts *Options, skew uint) (int64, bool, error) {
+	code = strings.TrimSpace(code)
+	current := Counter(t, opts)
+	for i := -int64(skew); i <= int64(skew); i++ {
+		expected, err := GenerateCodeForCounter(secret, current+i, opts)
+		if err != nil {
+			return 0, false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return current + i, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// URI builds an otpauth:// provisioning URI which can be imported into authenticator apps.
+//
+// Parameters:
+//   - issuer: The name of the service issuing the secret.
+//   - account: The account name, usually the user login.
+//   - secret: The base32-encoded shared secret.
+//   - opts: The TOTP parameters. If nil, DefaultOptions are used.
+func URI(issuer, account, secret string, opts *Options) string {
+	if opts == nil {
+		opts = DefaultOptions
+	}
+	v := url.Values{}
+	v.Set("secret", secret)
+	if issuer != "" {
+		v.Set("issuer", issuer)
+	}
+	v.Set("algorithm", opts.Algorithm)
+	v.Set("digits", strconv.Itoa(opts.Digits))
+	v.Set("period", strconv.FormatUint(uint64(opts.Period), 10))
+
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+	}
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// ParseURI parses an otpauth:// provisioning URI of a TOTP secret, as shown in the QR codes of the services.
+//
+// Parameters:
+//   - uri: The URI in the otpauth://totp/Issuer:account?secret=...&issuer=... form.
+//
+// Returns:
+//   - The issuer, taken from the issuer parameter or the label prefix.
+//   - The account name.
+//   - The base32-encoded shared secret, normalized to upper case without padding.
+//   - The TOTP parameters, DefaultOptions for the ones missing in the URI.
+//   - An error if the URI is not a valid TOTP URI or the secret or the parameters are invalid.
+func ParseURI(uri string) (string, string, string, *Options, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return "", "", "", nil, fmt.Errorf("invalid otpauth uri: %w", err)
+	}
+	if u.Scheme != "otpauth" {
+		return "", "", "", nil, fmt.Errorf("invalid otpauth uri scheme: %s", u.Scheme)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return "", "", "", nil, fmt.Errorf("unsupported otpauth type: %s", u.Host)
+	}
+
+	q := u.Query()
+	secret := strings.TrimRight(strings.ToUpper(strings.ReplaceAll(q.Get("secret"), " ", "")), "=")
+	if _, err = decodeSecret(secret); err != nil {
+		return "", "", "", nil, err
+	}
+
+	issuer, account := "", strings.TrimPrefix(u.Path, "/")
+	if prefix, name, found := strings.Cut(account, ":"); found {
+		issuer, account = strings.TrimSpace(prefix), strings.TrimSpace(name)
+	}
+	if q.Has("issuer") {
+		issuer = q.Get("issuer")
+	}
+
+	opts := *DefaultOptions
+	if algorithm := q.Get("algorithm"); algorithm != "" {
+		opts.Algorithm = strings.ToUpper(algorithm)
+		if _, err = hashFunc(opts.Algorithm); err != nil {
+			return "", "", "", nil, err
+		}
+	}
+	if digits := q.Get("digits"); digits != "" {
+		opts.Digits, err = strconv.Atoi(digits)
+		if err != nil || opts.Digits < 6 || opts.Digits > 10 {
+			return "", "", "", nil, fmt.Errorf("unsupported number of digits: %s", digits)
+		}
+	}
+	if period := q.Get("period"); period != "" {
+		p, err := strconv.ParseUint(period, 10, 32)
+		if err != nil || p == 0 {
+			return "", "", "", nil, fmt.Errorf("invalid period: %s", period)
+		}
+		opts.Period = uint(p)
+	}
+	return issuer, account, secret, &opts, nil
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case AlgorithmSHA1, "":
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported totp algorithm: %s", algorithm)
+	}
+}
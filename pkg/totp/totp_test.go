@@ -0,0 +1,148 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rfcSecretSHA1   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	rfcSecretSHA256 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA"
+	rfcSecretSHA512 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNA"
+)
+
+// TestGenerateCode checks the test vectors from RFC 6238, Appendix B.
+func TestGenerateCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		alg      string
+		unixTime int64
+		expected string
+	}{
+		{name: "SHA1 59", secret: rfcSecretSHA1, alg: AlgorithmSHA1, unixTime: 59, expected: "94287082"},
+		{name: "SHA256 59", secret: rfcSecretSHA256, alg: AlgorithmSHA256, unixTime: 59, expected: "46119246"},
+		{name: "SHA512 59", secret: rfcSecretSHA512, alg: AlgorithmSHA512, unixTime: 59, expected: "90693936"},
+		{name: "SHA1 1111111109", secret: rfcSecretSHA1, alg: AlgorithmSHA1, unixTime: 1111111109, expected: "07081804"},
+		{name: "SHA256 1234567890", secret: rfcSecretSHA256, alg: AlgorithmSHA256, unixTime: 1234567890, expected: "91819424"},
+		{name: "SHA512 20000000000", secret: rfcSecretSHA512, alg: AlgorithmSHA512, unixTime: 20000000000, expected: "47863826"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := GenerateCode(tt.secret, time.Unix(tt.unixTime, 0), &Options{Algorithm: tt.alg, Digits: 8, Period: 30})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+
+	t.Run("invalid secret", func(t *testing.T) {
+		_, err := GenerateCode("not base32!", time.Now(), nil)
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := GenerateCode(rfcSecretSHA1, time.Now(), &Options{Algorithm: "MD5", Digits: 6, Period: 30})
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now, nil)
+	require.NoError(t, err)
+
+	counter, ok, err := Validate(code, secret, now, nil, 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Counter(now, nil), counter)
+
+	_, ok, err = Validate(code, secret, now.Add(30*time.Second), nil, 1)
+	require.NoError(t, err)
+	assert.True(t, ok, "code from the previous step must be accepted with skew 1")
+
+	_, ok, err = Validate(code, secret, now.Add(90*time.Second), nil, 1)
+	require.NoError(t, err)
+	assert.False(t, ok, "code older than the skew window must be rejected")
+
+	_, ok, err = Validate("000000", secret, now, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, code == "000000", ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("GophKeeper", "login", rfcSecretSHA1, nil)
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/GophKeeper:login", u.Path)
+	assert.Equal(t, rfcSecretSHA1, u.Query().Get("secret"))
+	assert.Equal(t, "GophKeeper", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		issuer  string
+		account string
+		secret  string
+		opts    Options
+		wantErr bool
+	}{
+		{
+			name:    "round trip",
+			uri:     URI("GophKeeper", "login", rfcSecretSHA1, &Options{Algorithm: AlgorithmSHA256, Digits: 8, Period: 60}),
+			issuer:  "GophKeeper",
+			account: "login",
+			secret:  rfcSecretSHA1,
+			opts:    Options{Algorithm: AlgorithmSHA256, Digits: 8, Period: 60},
+		},
+		{
+			name:    "defaults and label issuer",
+			uri:     "otpauth://totp/Example%20Co:alice@example.com?secret=jbswy3dpehpk3pxp",
+			issuer:  "Example Co",
+			account: "alice@example.com",
+			secret:  "JBSWY3DPEHPK3PXP",
+			opts:    *DefaultOptions,
+		},
+		{
+			name:    "issuer parameter wins",
+			uri:     "otpauth://totp/Old:bob?secret=JBSWY3DPEHPK3PXP&issuer=New&algorithm=sha512",
+			issuer:  "New",
+			account: "bob",
+			secret:  "JBSWY3DPEHPK3PXP",
+			opts:    Options{Algorithm: AlgorithmSHA512, Digits: 6, Period: 30},
+		},
+		{name: "hotp", uri: "otpauth://hotp/bob?secret=JBSWY3DPEHPK3PXP&counter=1", wantErr: true},
+		{name: "bad scheme", uri: "https://totp/bob?secret=JBSWY3DPEHPK3PXP", wantErr: true},
+		{name: "bad secret", uri: "otpauth://totp/bob?secret=not-base32!", wantErr: true},
+		{name: "no secret", uri: "otpauth://totp/bob", wantErr: true},
+		{name: "bad digits", uri: "otpauth://totp/bob?secret=JBSWY3DPEHPK3PXP&digits=4", wantErr: true},
+		{name: "bad period", uri: "otpauth://totp/bob?secret=JBSWY3DPEHPK3PXP&period=0", wantErr: true},
+		{name: "bad algorithm", uri: "otpauth://totp/bob?secret=JBSWY3DPEHPK3PXP&algorithm=MD5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer, account, secret, opts, err := ParseURI(tt.uri)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.issuer, issuer)
+			assert.Equal(t, tt.account, account)
+			assert.Equal(t, tt.secret, secret)
+			assert.Equal(t, tt.opts, *opts)
+		})
+	}
+}
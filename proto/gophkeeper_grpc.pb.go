@@ -20,25 +20,83 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Gophkeeper_RegisterUser_FullMethodName          = "/gophkeeper.Gophkeeper/RegisterUser"
-	Gophkeeper_Authorize_FullMethodName             = "/gophkeeper.Gophkeeper/Authorize"
-	Gophkeeper_Echo_FullMethodName                  = "/gophkeeper.Gophkeeper/Echo"
-	Gophkeeper_AddBankCard_FullMethodName           = "/gophkeeper.Gophkeeper/AddBankCard"
-	Gophkeeper_RemoveBankCard_FullMethodName        = "/gophkeeper.Gophkeeper/RemoveBankCard"
-	Gophkeeper_GetBankCards_FullMethodName          = "/gophkeeper.Gophkeeper/GetBankCards"
-	Gophkeeper_GetBankCard_FullMethodName           = "/gophkeeper.Gophkeeper/GetBankCard"
-	Gophkeeper_AddUserCredentials_FullMethodName    = "/gophkeeper.Gophkeeper/AddUserCredentials"
-	Gophkeeper_GetUserCredentials_FullMethodName    = "/gophkeeper.Gophkeeper/GetUserCredentials"
-	Gophkeeper_GetUserCredential_FullMethodName     = "/gophkeeper.Gophkeeper/GetUserCredential"
-	Gophkeeper_RemoveUserCredentials_FullMethodName = "/gophkeeper.Gophkeeper/RemoveUserCredentials"
-	Gophkeeper_AddNote_FullMethodName               = "/gophkeeper.Gophkeeper/AddNote"
-	Gophkeeper_GetNotes_FullMethodName              = "/gophkeeper.Gophkeeper/GetNotes"
-	Gophkeeper_GetNote_FullMethodName               = "/gophkeeper.Gophkeeper/GetNote"
-	Gophkeeper_RemoveNote_FullMethodName            = "/gophkeeper.Gophkeeper/RemoveNote"
-	Gophkeeper_Upload_FullMethodName                = "/gophkeeper.Gophkeeper/Upload"
-	Gophkeeper_Download_FullMethodName              = "/gophkeeper.Gophkeeper/Download"
-	Gophkeeper_RemoveFile_FullMethodName            = "/gophkeeper.Gophkeeper/RemoveFile"
-	Gophkeeper_GetFiles_FullMethodName              = "/gophkeeper.Gophkeeper/GetFiles"
+	Gophkeeper_RegisterUser_FullMethodName            = "/gophkeeper.Gophkeeper/RegisterUser"
+	Gophkeeper_Authorize_FullMethodName               = "/gophkeeper.Gophkeeper/Authorize"
+	Gophkeeper_Echo_FullMethodName                    = "/gophkeeper.Gophkeeper/Echo"
+	Gophkeeper_AddBankCard_FullMethodName             = "/gophkeeper.Gophkeeper/AddBankCard"
+	Gophkeeper_UpdateBankCard_FullMethodName          = "/gophkeeper.Gophkeeper/UpdateBankCard"
+	Gophkeeper_RemoveBankCard_FullMethodName          = "/gophkeeper.Gophkeeper/RemoveBankCard"
+	Gophkeeper_GetBankCards_FullMethodName            = "/gophkeeper.Gophkeeper/GetBankCards"
+	Gophkeeper_GetBankCard_FullMethodName             = "/gophkeeper.Gophkeeper/GetBankCard"
+	Gophkeeper_AddOTPItem_FullMethodName              = "/gophkeeper.Gophkeeper/AddOTPItem"
+	Gophkeeper_UpdateOTPItem_FullMethodName           = "/gophkeeper.Gophkeeper/UpdateOTPItem"
+	Gophkeeper_RemoveOTPItem_FullMethodName           = "/gophkeeper.Gophkeeper/RemoveOTPItem"
+	Gophkeeper_GetOTPItems_FullMethodName             = "/gophkeeper.Gophkeeper/GetOTPItems"
+	Gophkeeper_GetOTPItem_FullMethodName              = "/gophkeeper.Gophkeeper/GetOTPItem"
+	Gophkeeper_AddItemTemplate_FullMethodName         = "/gophkeeper.Gophkeeper/AddItemTemplate"
+	Gophkeeper_GetItemTemplates_FullMethodName        = "/gophkeeper.Gophkeeper/GetItemTemplates"
+	Gophkeeper_RemoveItemTemplate_FullMethodName      = "/gophkeeper.Gophkeeper/RemoveItemTemplate"
+	Gophkeeper_AddCustomItem_FullMethodName           = "/gophkeeper.Gophkeeper/AddCustomItem"
+	Gophkeeper_UpdateCustomItem_FullMethodName        = "/gophkeeper.Gophkeeper/UpdateCustomItem"
+	Gophkeeper_RemoveCustomItem_FullMethodName        = "/gophkeeper.Gophkeeper/RemoveCustomItem"
+	Gophkeeper_GetCustomItems_FullMethodName          = "/gophkeeper.Gophkeeper/GetCustomItems"
+	Gophkeeper_GetCustomItem_FullMethodName           = "/gophkeeper.Gophkeeper/GetCustomItem"
+	Gophkeeper_AddUserCredentials_FullMethodName      = "/gophkeeper.Gophkeeper/AddUserCredentials"
+	Gophkeeper_UpdateUserCredentials_FullMethodName   = "/gophkeeper.Gophkeeper/UpdateUserCredentials"
+	Gophkeeper_GetUserCredentials_FullMethodName      = "/gophkeeper.Gophkeeper/GetUserCredentials"
+	Gophkeeper_GetUserCredential_FullMethodName       = "/gophkeeper.Gophkeeper/GetUserCredential"
+	Gophkeeper_RemoveUserCredentials_FullMethodName   = "/gophkeeper.Gophkeeper/RemoveUserCredentials"
+	Gophkeeper_AddNote_FullMethodName                 = "/gophkeeper.Gophkeeper/AddNote"
+	Gophkeeper_UpdateNote_FullMethodName              = "/gophkeeper.Gophkeeper/UpdateNote"
+	Gophkeeper_GetNotes_FullMethodName                = "/gophkeeper.Gophkeeper/GetNotes"
+	Gophkeeper_GetNote_FullMethodName                 = "/gophkeeper.Gophkeeper/GetNote"
+	Gophkeeper_RemoveNote_FullMethodName              = "/gophkeeper.Gophkeeper/RemoveNote"
+	Gophkeeper_Upload_FullMethodName                  = "/gophkeeper.Gophkeeper/Upload"
+	Gophkeeper_Download_FullMethodName                = "/gophkeeper.Gophkeeper/Download"
+	Gophkeeper_RemoveFile_FullMethodName              = "/gophkeeper.Gophkeeper/RemoveFile"
+	Gophkeeper_UpdateFile_FullMethodName              = "/gophkeeper.Gophkeeper/UpdateFile"
+	Gophkeeper_GetFiles_FullMethodName                = "/gophkeeper.Gophkeeper/GetFiles"
+	Gophkeeper_EnrollTOTP_FullMethodName              = "/gophkeeper.Gophkeeper/EnrollTOTP"
+	Gophkeeper_ConfirmTOTP_FullMethodName             = "/gophkeeper.Gophkeeper/ConfirmTOTP"
+	Gophkeeper_DisableTOTP_FullMethodName             = "/gophkeeper.Gophkeeper/DisableTOTP"
+	Gophkeeper_RegenerateRecoveryCodes_FullMethodName = "/gophkeeper.Gophkeeper/RegenerateRecoveryCodes"
+	Gophkeeper_RefreshToken_FullMethodName            = "/gophkeeper.Gophkeeper/RefreshToken"
+	Gophkeeper_Logout_FullMethodName                  = "/gophkeeper.Gophkeeper/Logout"
+	Gophkeeper_ListSessions_FullMethodName            = "/gophkeeper.Gophkeeper/ListSessions"
+	Gophkeeper_RevokeSession_FullMethodName           = "/gophkeeper.Gophkeeper/RevokeSession"
+	Gophkeeper_RekeyVault_FullMethodName              = "/gophkeeper.Gophkeeper/RekeyVault"
+	Gophkeeper_GetJWKS_FullMethodName                 = "/gophkeeper.Gophkeeper/GetJWKS"
+	Gophkeeper_GetAuditLog_FullMethodName             = "/gophkeeper.Gophkeeper/GetAuditLog"
+	Gophkeeper_SetSharingKeys_FullMethodName          = "/gophkeeper.Gophkeeper/SetSharingKeys"
+	Gophkeeper_GetSharingKeys_FullMethodName          = "/gophkeeper.Gophkeeper/GetSharingKeys"
+	Gophkeeper_GetPublicKey_FullMethodName            = "/gophkeeper.Gophkeeper/GetPublicKey"
+	Gophkeeper_GetItemShares_FullMethodName           = "/gophkeeper.Gophkeeper/GetItemShares"
+	Gophkeeper_ShareItem_FullMethodName               = "/gophkeeper.Gophkeeper/ShareItem"
+	Gophkeeper_ListSharedWithMe_FullMethodName        = "/gophkeeper.Gophkeeper/ListSharedWithMe"
+	Gophkeeper_RevokeShare_FullMethodName             = "/gophkeeper.Gophkeeper/RevokeShare"
+	Gophkeeper_CreateOrganization_FullMethodName      = "/gophkeeper.Gophkeeper/CreateOrganization"
+	Gophkeeper_ListOrganizations_FullMethodName       = "/gophkeeper.Gophkeeper/ListOrganizations"
+	Gophkeeper_RemoveOrganization_FullMethodName      = "/gophkeeper.Gophkeeper/RemoveOrganization"
+	Gophkeeper_ListOrgMembers_FullMethodName          = "/gophkeeper.Gophkeeper/ListOrgMembers"
+	Gophkeeper_AddOrgMember_FullMethodName            = "/gophkeeper.Gophkeeper/AddOrgMember"
+	Gophkeeper_SetOrgMemberRole_FullMethodName        = "/gophkeeper.Gophkeeper/SetOrgMemberRole"
+	Gophkeeper_RemoveOrgMember_FullMethodName         = "/gophkeeper.Gophkeeper/RemoveOrgMember"
+	Gophkeeper_GetOrgKey_FullMethodName               = "/gophkeeper.Gophkeeper/GetOrgKey"
+	Gophkeeper_CreateCollection_FullMethodName        = "/gophkeeper.Gophkeeper/CreateCollection"
+	Gophkeeper_ListCollections_FullMethodName         = "/gophkeeper.Gophkeeper/ListCollections"
+	Gophkeeper_RemoveCollection_FullMethodName        = "/gophkeeper.Gophkeeper/RemoveCollection"
+	Gophkeeper_CreateFolder_FullMethodName            = "/gophkeeper.Gophkeeper/CreateFolder"
+	Gophkeeper_RenameFolder_FullMethodName            = "/gophkeeper.Gophkeeper/RenameFolder"
+	Gophkeeper_MoveFolder_FullMethodName              = "/gophkeeper.Gophkeeper/MoveFolder"
+	Gophkeeper_RemoveFolder_FullMethodName            = "/gophkeeper.Gophkeeper/RemoveFolder"
+	Gophkeeper_GetFolders_FullMethodName              = "/gophkeeper.Gophkeeper/GetFolders"
+	Gophkeeper_SetItemFolder_FullMethodName           = "/gophkeeper.Gophkeeper/SetItemFolder"
+	Gophkeeper_CreateTag_FullMethodName               = "/gophkeeper.Gophkeeper/CreateTag"
+	Gophkeeper_RenameTag_FullMethodName               = "/gophkeeper.Gophkeeper/RenameTag"
+	Gophkeeper_RemoveTag_FullMethodName               = "/gophkeeper.Gophkeeper/RemoveTag"
+	Gophkeeper_GetTags_FullMethodName                 = "/gophkeeper.Gophkeeper/GetTags"
+	Gophkeeper_TagItem_FullMethodName                 = "/gophkeeper.Gophkeeper/TagItem"
+	Gophkeeper_UntagItem_FullMethodName               = "/gophkeeper.Gophkeeper/UntagItem"
 )
 
 // GophkeeperClient is the client API for Gophkeeper service.
@@ -49,21 +107,79 @@ type GophkeeperClient interface {
 	Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeResponse, error)
 	Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error)
 	AddBankCard(ctx context.Context, in *AddBankCardRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateBankCard(ctx context.Context, in *UpdateBankCardRequest, opts ...grpc.CallOption) (*UpdateBankCardResponse, error)
 	RemoveBankCard(ctx context.Context, in *RemoveBankCardRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetBankCards(ctx context.Context, in *GetBankCardsRequest, opts ...grpc.CallOption) (*GetBankCardsResponse, error)
 	GetBankCard(ctx context.Context, in *GetBankCardRequest, opts ...grpc.CallOption) (*GetBankCardResponse, error)
+	AddOTPItem(ctx context.Context, in *AddOTPItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateOTPItem(ctx context.Context, in *UpdateOTPItemRequest, opts ...grpc.CallOption) (*UpdateOTPItemResponse, error)
+	RemoveOTPItem(ctx context.Context, in *RemoveOTPItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOTPItems(ctx context.Context, in *GetOTPItemsRequest, opts ...grpc.CallOption) (*GetOTPItemsResponse, error)
+	GetOTPItem(ctx context.Context, in *GetOTPItemRequest, opts ...grpc.CallOption) (*GetOTPItemResponse, error)
+	AddItemTemplate(ctx context.Context, in *AddItemTemplateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetItemTemplates(ctx context.Context, in *GetItemTemplatesRequest, opts ...grpc.CallOption) (*GetItemTemplatesResponse, error)
+	RemoveItemTemplate(ctx context.Context, in *RemoveItemTemplateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AddCustomItem(ctx context.Context, in *AddCustomItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateCustomItem(ctx context.Context, in *UpdateCustomItemRequest, opts ...grpc.CallOption) (*UpdateCustomItemResponse, error)
+	RemoveCustomItem(ctx context.Context, in *RemoveCustomItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetCustomItems(ctx context.Context, in *GetCustomItemsRequest, opts ...grpc.CallOption) (*GetCustomItemsResponse, error)
+	GetCustomItem(ctx context.Context, in *GetCustomItemRequest, opts ...grpc.CallOption) (*GetCustomItemResponse, error)
 	AddUserCredentials(ctx context.Context, in *AddUserCredentialsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateUserCredentials(ctx context.Context, in *UpdateUserCredentialsRequest, opts ...grpc.CallOption) (*UpdateUserCredentialsResponse, error)
 	GetUserCredentials(ctx context.Context, in *GetUserCredentialsRequest, opts ...grpc.CallOption) (*GetUserCredentialsResponse, error)
 	GetUserCredential(ctx context.Context, in *GetUserCredentialRequest, opts ...grpc.CallOption) (*GetUserCredentialResponse, error)
 	RemoveUserCredentials(ctx context.Context, in *RemoveUserCredentialsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	AddNote(ctx context.Context, in *AddNoteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*UpdateNoteResponse, error)
 	GetNotes(ctx context.Context, in *GetNotesRequest, opts ...grpc.CallOption) (*GetNotesResponse, error)
 	GetNote(ctx context.Context, in *GetNoteRequest, opts ...grpc.CallOption) (*GetNoteResponse, error)
 	RemoveNote(ctx context.Context, in *RemoveNoteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[FileUploadRequest, FileUploadResponse], error)
 	Download(ctx context.Context, in *FileDownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileDownloadResponse], error)
 	RemoveFile(ctx context.Context, in *FileRemoveRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateFile(ctx context.Context, in *UpdateFileRequest, opts ...grpc.CallOption) (*UpdateFileResponse, error)
 	GetFiles(ctx context.Context, in *GetFilesRequest, opts ...grpc.CallOption) (*GetFilesResponse, error)
+	EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, opts ...grpc.CallOption) (*EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest, opts ...grpc.CallOption) (*ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RegenerateRecoveryCodes(ctx context.Context, in *RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*RegenerateRecoveryCodesResponse, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RekeyVault(ctx context.Context, in *RekeyVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetJWKS(ctx context.Context, in *GetJWKSRequest, opts ...grpc.CallOption) (*GetJWKSResponse, error)
+	GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error)
+	SetSharingKeys(ctx context.Context, in *SetSharingKeysRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetSharingKeys(ctx context.Context, in *GetSharingKeysRequest, opts ...grpc.CallOption) (*GetSharingKeysResponse, error)
+	GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error)
+	GetItemShares(ctx context.Context, in *GetItemSharesRequest, opts ...grpc.CallOption) (*GetItemSharesResponse, error)
+	ShareItem(ctx context.Context, in *ShareItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListSharedWithMe(ctx context.Context, in *ListSharedWithMeRequest, opts ...grpc.CallOption) (*ListSharedWithMeResponse, error)
+	RevokeShare(ctx context.Context, in *RevokeShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*CreateOrganizationResponse, error)
+	ListOrganizations(ctx context.Context, in *ListOrganizationsRequest, opts ...grpc.CallOption) (*ListOrganizationsResponse, error)
+	RemoveOrganization(ctx context.Context, in *RemoveOrganizationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListOrgMembers(ctx context.Context, in *ListOrgMembersRequest, opts ...grpc.CallOption) (*ListOrgMembersResponse, error)
+	AddOrgMember(ctx context.Context, in *AddOrgMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SetOrgMemberRole(ctx context.Context, in *SetOrgMemberRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveOrgMember(ctx context.Context, in *RemoveOrgMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOrgKey(ctx context.Context, in *GetOrgKeyRequest, opts ...grpc.CallOption) (*GetOrgKeyResponse, error)
+	CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CreateCollectionResponse, error)
+	ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error)
+	RemoveCollection(ctx context.Context, in *RemoveCollectionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*CreateFolderResponse, error)
+	RenameFolder(ctx context.Context, in *RenameFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveFolder(ctx context.Context, in *RemoveFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetFolders(ctx context.Context, in *GetFoldersRequest, opts ...grpc.CallOption) (*GetFoldersResponse, error)
+	SetItemFolder(ctx context.Context, in *SetItemFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*CreateTagResponse, error)
+	RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveTag(ctx context.Context, in *RemoveTagRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetTags(ctx context.Context, in *GetTagsRequest, opts ...grpc.CallOption) (*GetTagsResponse, error)
+	TagItem(ctx context.Context, in *TagItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UntagItem(ctx context.Context, in *UntagItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type gophkeeperClient struct {
@@ -114,6 +230,16 @@ func (c *gophkeeperClient) AddBankCard(ctx context.Context, in *AddBankCardReque
 	return out, nil
 }
 
+func (c *gophkeeperClient) UpdateBankCard(ctx context.Context, in *UpdateBankCardRequest, opts ...grpc.CallOption) (*UpdateBankCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateBankCardResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_UpdateBankCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophkeeperClient) RemoveBankCard(ctx context.Context, in *RemoveBankCardRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -144,6 +270,136 @@ func (c *gophkeeperClient) GetBankCard(ctx context.Context, in *GetBankCardReque
 	return out, nil
 }
 
+func (c *gophkeeperClient) AddOTPItem(ctx context.Context, in *AddOTPItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_AddOTPItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) UpdateOTPItem(ctx context.Context, in *UpdateOTPItemRequest, opts ...grpc.CallOption) (*UpdateOTPItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateOTPItemResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_UpdateOTPItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveOTPItem(ctx context.Context, in *RemoveOTPItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveOTPItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetOTPItems(ctx context.Context, in *GetOTPItemsRequest, opts ...grpc.CallOption) (*GetOTPItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOTPItemsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetOTPItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetOTPItem(ctx context.Context, in *GetOTPItemRequest, opts ...grpc.CallOption) (*GetOTPItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOTPItemResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetOTPItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) AddItemTemplate(ctx context.Context, in *AddItemTemplateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_AddItemTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetItemTemplates(ctx context.Context, in *GetItemTemplatesRequest, opts ...grpc.CallOption) (*GetItemTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetItemTemplatesResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetItemTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveItemTemplate(ctx context.Context, in *RemoveItemTemplateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveItemTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) AddCustomItem(ctx context.Context, in *AddCustomItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_AddCustomItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) UpdateCustomItem(ctx context.Context, in *UpdateCustomItemRequest, opts ...grpc.CallOption) (*UpdateCustomItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateCustomItemResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_UpdateCustomItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveCustomItem(ctx context.Context, in *RemoveCustomItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveCustomItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetCustomItems(ctx context.Context, in *GetCustomItemsRequest, opts ...grpc.CallOption) (*GetCustomItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCustomItemsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetCustomItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetCustomItem(ctx context.Context, in *GetCustomItemRequest, opts ...grpc.CallOption) (*GetCustomItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCustomItemResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetCustomItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophkeeperClient) AddUserCredentials(ctx context.Context, in *AddUserCredentialsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -154,6 +410,16 @@ func (c *gophkeeperClient) AddUserCredentials(ctx context.Context, in *AddUserCr
 	return out, nil
 }
 
+func (c *gophkeeperClient) UpdateUserCredentials(ctx context.Context, in *UpdateUserCredentialsRequest, opts ...grpc.CallOption) (*UpdateUserCredentialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateUserCredentialsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_UpdateUserCredentials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophkeeperClient) GetUserCredentials(ctx context.Context, in *GetUserCredentialsRequest, opts ...grpc.CallOption) (*GetUserCredentialsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetUserCredentialsResponse)
@@ -194,6 +460,16 @@ func (c *gophkeeperClient) AddNote(ctx context.Context, in *AddNoteRequest, opts
 	return out, nil
 }
 
+func (c *gophkeeperClient) UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*UpdateNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateNoteResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_UpdateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophkeeperClient) GetNotes(ctx context.Context, in *GetNotesRequest, opts ...grpc.CallOption) (*GetNotesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetNotesResponse)
@@ -266,6 +542,16 @@ func (c *gophkeeperClient) RemoveFile(ctx context.Context, in *FileRemoveRequest
 	return out, nil
 }
 
+func (c *gophkeeperClient) UpdateFile(ctx context.Context, in *UpdateFileRequest, opts ...grpc.CallOption) (*UpdateFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateFileResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_UpdateFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophkeeperClient) GetFiles(ctx context.Context, in *GetFilesRequest, opts ...grpc.CallOption) (*GetFilesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetFilesResponse)
@@ -276,437 +562,2123 @@ func (c *gophkeeperClient) GetFiles(ctx context.Context, in *GetFilesRequest, op
 	return out, nil
 }
 
-// GophkeeperServer is the server API for Gophkeeper service.
-// All implementations must embed UnimplementedGophkeeperServer
-// for forward compatibility.
-type GophkeeperServer interface {
-	RegisterUser(context.Context, *RegisterUserRequest) (*emptypb.Empty, error)
-	Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error)
-	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
-	AddBankCard(context.Context, *AddBankCardRequest) (*emptypb.Empty, error)
-	RemoveBankCard(context.Context, *RemoveBankCardRequest) (*emptypb.Empty, error)
-	GetBankCards(context.Context, *GetBankCardsRequest) (*GetBankCardsResponse, error)
-	GetBankCard(context.Context, *GetBankCardRequest) (*GetBankCardResponse, error)
-	AddUserCredentials(context.Context, *AddUserCredentialsRequest) (*emptypb.Empty, error)
-	GetUserCredentials(context.Context, *GetUserCredentialsRequest) (*GetUserCredentialsResponse, error)
-	GetUserCredential(context.Context, *GetUserCredentialRequest) (*GetUserCredentialResponse, error)
-	RemoveUserCredentials(context.Context, *RemoveUserCredentialsRequest) (*emptypb.Empty, error)
-	AddNote(context.Context, *AddNoteRequest) (*emptypb.Empty, error)
-	GetNotes(context.Context, *GetNotesRequest) (*GetNotesResponse, error)
-	GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error)
-	RemoveNote(context.Context, *RemoveNoteRequest) (*emptypb.Empty, error)
-	Upload(grpc.ClientStreamingServer[FileUploadRequest, FileUploadResponse]) error
-	Download(*FileDownloadRequest, grpc.ServerStreamingServer[FileDownloadResponse]) error
-	RemoveFile(context.Context, *FileRemoveRequest) (*emptypb.Empty, error)
-	GetFiles(context.Context, *GetFilesRequest) (*GetFilesResponse, error)
-	mustEmbedUnimplementedGophkeeperServer()
+func (c *gophkeeperClient) EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, opts ...grpc.CallOption) (*EnrollTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollTOTPResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_EnrollTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedGophkeeperServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedGophkeeperServer struct{}
-
-func (UnimplementedGophkeeperServer) RegisterUser(context.Context, *RegisterUserRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterUser not implemented")
-}
-func (UnimplementedGophkeeperServer) Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+func (c *gophkeeperClient) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest, opts ...grpc.CallOption) (*ConfirmTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmTOTPResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_ConfirmTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) Echo(context.Context, *EchoRequest) (*EchoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Echo not implemented")
+
+func (c *gophkeeperClient) DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_DisableTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) AddBankCard(context.Context, *AddBankCardRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddBankCard not implemented")
+
+func (c *gophkeeperClient) RegenerateRecoveryCodes(ctx context.Context, in *RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*RegenerateRecoveryCodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegenerateRecoveryCodesResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_RegenerateRecoveryCodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) RemoveBankCard(context.Context, *RemoveBankCardRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RemoveBankCard not implemented")
+
+func (c *gophkeeperClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshTokenResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetBankCards(context.Context, *GetBankCardsRequest) (*GetBankCardsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBankCards not implemented")
+
+func (c *gophkeeperClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetBankCard(context.Context, *GetBankCardRequest) (*GetBankCardResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBankCard not implemented")
+
+func (c *gophkeeperClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) AddUserCredentials(context.Context, *AddUserCredentialsRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddUserCredentials not implemented")
+
+func (c *gophkeeperClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetUserCredentials(context.Context, *GetUserCredentialsRequest) (*GetUserCredentialsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUserCredentials not implemented")
+
+func (c *gophkeeperClient) RekeyVault(ctx context.Context, in *RekeyVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RekeyVault_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetUserCredential(context.Context, *GetUserCredentialRequest) (*GetUserCredentialResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUserCredential not implemented")
+
+func (c *gophkeeperClient) GetJWKS(ctx context.Context, in *GetJWKSRequest, opts ...grpc.CallOption) (*GetJWKSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJWKSResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetJWKS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) RemoveUserCredentials(context.Context, *RemoveUserCredentialsRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RemoveUserCredentials not implemented")
+
+func (c *gophkeeperClient) GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAuditLogResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) AddNote(context.Context, *AddNoteRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddNote not implemented")
+
+func (c *gophkeeperClient) SetSharingKeys(ctx context.Context, in *SetSharingKeysRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_SetSharingKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetNotes(context.Context, *GetNotesRequest) (*GetNotesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetNotes not implemented")
+
+func (c *gophkeeperClient) GetSharingKeys(ctx context.Context, in *GetSharingKeysRequest, opts ...grpc.CallOption) (*GetSharingKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSharingKeysResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetSharingKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetNote not implemented")
+
+func (c *gophkeeperClient) GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPublicKeyResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetPublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) RemoveNote(context.Context, *RemoveNoteRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RemoveNote not implemented")
+
+func (c *gophkeeperClient) GetItemShares(ctx context.Context, in *GetItemSharesRequest, opts ...grpc.CallOption) (*GetItemSharesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetItemSharesResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetItemShares_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) Upload(grpc.ClientStreamingServer[FileUploadRequest, FileUploadResponse]) error {
-	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+
+func (c *gophkeeperClient) ShareItem(ctx context.Context, in *ShareItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_ShareItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) Download(*FileDownloadRequest, grpc.ServerStreamingServer[FileDownloadResponse]) error {
-	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+
+func (c *gophkeeperClient) ListSharedWithMe(ctx context.Context, in *ListSharedWithMeRequest, opts ...grpc.CallOption) (*ListSharedWithMeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSharedWithMeResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_ListSharedWithMe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) RemoveFile(context.Context, *FileRemoveRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RemoveFile not implemented")
+
+func (c *gophkeeperClient) RevokeShare(ctx context.Context, in *RevokeShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RevokeShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) GetFiles(context.Context, *GetFilesRequest) (*GetFilesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetFiles not implemented")
+
+func (c *gophkeeperClient) CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*CreateOrganizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOrganizationResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_CreateOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedGophkeeperServer) mustEmbedUnimplementedGophkeeperServer() {}
-func (UnimplementedGophkeeperServer) testEmbeddedByValue()                    {}
 
-// UnsafeGophkeeperServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to GophkeeperServer will
-// result in compilation errors.
-type UnsafeGophkeeperServer interface {
+func (c *gophkeeperClient) ListOrganizations(ctx context.Context, in *ListOrganizationsRequest, opts ...grpc.CallOption) (*ListOrganizationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrganizationsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_ListOrganizations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveOrganization(ctx context.Context, in *RemoveOrganizationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) ListOrgMembers(ctx context.Context, in *ListOrgMembersRequest, opts ...grpc.CallOption) (*ListOrgMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrgMembersResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_ListOrgMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) AddOrgMember(ctx context.Context, in *AddOrgMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_AddOrgMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) SetOrgMemberRole(ctx context.Context, in *SetOrgMemberRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_SetOrgMemberRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveOrgMember(ctx context.Context, in *RemoveOrgMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveOrgMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetOrgKey(ctx context.Context, in *GetOrgKeyRequest, opts ...grpc.CallOption) (*GetOrgKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrgKeyResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetOrgKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CreateCollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCollectionResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_CreateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCollectionsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_ListCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveCollection(ctx context.Context, in *RemoveCollectionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*CreateFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFolderResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_CreateFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RenameFolder(ctx context.Context, in *RenameFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RenameFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_MoveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveFolder(ctx context.Context, in *RemoveFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetFolders(ctx context.Context, in *GetFoldersRequest, opts ...grpc.CallOption) (*GetFoldersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFoldersResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetFolders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) SetItemFolder(ctx context.Context, in *SetItemFolderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_SetItemFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*CreateTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTagResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_CreateTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RenameTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) RemoveTag(ctx context.Context, in *RemoveTagRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_RemoveTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) GetTags(ctx context.Context, in *GetTagsRequest, opts ...grpc.CallOption) (*GetTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTagsResponse)
+	err := c.cc.Invoke(ctx, Gophkeeper_GetTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) TagItem(ctx context.Context, in *TagItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_TagItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophkeeperClient) UntagItem(ctx context.Context, in *UntagItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gophkeeper_UntagItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GophkeeperServer is the server API for Gophkeeper service.
+// All implementations must embed UnimplementedGophkeeperServer
+// for forward compatibility.
+type GophkeeperServer interface {
+	RegisterUser(context.Context, *RegisterUserRequest) (*emptypb.Empty, error)
+	Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error)
+	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
+	AddBankCard(context.Context, *AddBankCardRequest) (*emptypb.Empty, error)
+	UpdateBankCard(context.Context, *UpdateBankCardRequest) (*UpdateBankCardResponse, error)
+	RemoveBankCard(context.Context, *RemoveBankCardRequest) (*emptypb.Empty, error)
+	GetBankCards(context.Context, *GetBankCardsRequest) (*GetBankCardsResponse, error)
+	GetBankCard(context.Context, *GetBankCardRequest) (*GetBankCardResponse, error)
+	AddOTPItem(context.Context, *AddOTPItemRequest) (*emptypb.Empty, error)
+	UpdateOTPItem(context.Context, *UpdateOTPItemRequest) (*UpdateOTPItemResponse, error)
+	RemoveOTPItem(context.Context, *RemoveOTPItemRequest) (*emptypb.Empty, error)
+	GetOTPItems(context.Context, *GetOTPItemsRequest) (*GetOTPItemsResponse, error)
+	GetOTPItem(context.Context, *GetOTPItemRequest) (*GetOTPItemResponse, error)
+	AddItemTemplate(context.Context, *AddItemTemplateRequest) (*emptypb.Empty, error)
+	GetItemTemplates(context.Context, *GetItemTemplatesRequest) (*GetItemTemplatesResponse, error)
+	RemoveItemTemplate(context.Context, *RemoveItemTemplateRequest) (*emptypb.Empty, error)
+	AddCustomItem(context.Context, *AddCustomItemRequest) (*emptypb.Empty, error)
+	UpdateCustomItem(context.Context, *UpdateCustomItemRequest) (*UpdateCustomItemResponse, error)
+	RemoveCustomItem(context.Context, *RemoveCustomItemRequest) (*emptypb.Empty, error)
+	GetCustomItems(context.Context, *GetCustomItemsRequest) (*GetCustomItemsResponse, error)
+	GetCustomItem(context.Context, *GetCustomItemRequest) (*GetCustomItemResponse, error)
+	AddUserCredentials(context.Context, *AddUserCredentialsRequest) (*emptypb.Empty, error)
+	UpdateUserCredentials(context.Context, *UpdateUserCredentialsRequest) (*UpdateUserCredentialsResponse, error)
+	GetUserCredentials(context.Context, *GetUserCredentialsRequest) (*GetUserCredentialsResponse, error)
+	GetUserCredential(context.Context, *GetUserCredentialRequest) (*GetUserCredentialResponse, error)
+	RemoveUserCredentials(context.Context, *RemoveUserCredentialsRequest) (*emptypb.Empty, error)
+	AddNote(context.Context, *AddNoteRequest) (*emptypb.Empty, error)
+	UpdateNote(context.Context, *UpdateNoteRequest) (*UpdateNoteResponse, error)
+	GetNotes(context.Context, *GetNotesRequest) (*GetNotesResponse, error)
+	GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error)
+	RemoveNote(context.Context, *RemoveNoteRequest) (*emptypb.Empty, error)
+	Upload(grpc.ClientStreamingServer[FileUploadRequest, FileUploadResponse]) error
+	Download(*FileDownloadRequest, grpc.ServerStreamingServer[FileDownloadResponse]) error
+	RemoveFile(context.Context, *FileRemoveRequest) (*emptypb.Empty, error)
+	UpdateFile(context.Context, *UpdateFileRequest) (*UpdateFileResponse, error)
+	GetFiles(context.Context, *GetFilesRequest) (*GetFilesResponse, error)
+	EnrollTOTP(context.Context, *EnrollTOTPRequest) (*EnrollTOTPResponse, error)
+	ConfirmTOTP(context.Context, *ConfirmTOTPRequest) (*ConfirmTOTPResponse, error)
+	DisableTOTP(context.Context, *DisableTOTPRequest) (*emptypb.Empty, error)
+	RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error)
+	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	Logout(context.Context, *LogoutRequest) (*emptypb.Empty, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*emptypb.Empty, error)
+	RekeyVault(context.Context, *RekeyVaultRequest) (*emptypb.Empty, error)
+	GetJWKS(context.Context, *GetJWKSRequest) (*GetJWKSResponse, error)
+	GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error)
+	SetSharingKeys(context.Context, *SetSharingKeysRequest) (*emptypb.Empty, error)
+	GetSharingKeys(context.Context, *GetSharingKeysRequest) (*GetSharingKeysResponse, error)
+	GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error)
+	GetItemShares(context.Context, *GetItemSharesRequest) (*GetItemSharesResponse, error)
+	ShareItem(context.Context, *ShareItemRequest) (*emptypb.Empty, error)
+	ListSharedWithMe(context.Context, *ListSharedWithMeRequest) (*ListSharedWithMeResponse, error)
+	RevokeShare(context.Context, *RevokeShareRequest) (*emptypb.Empty, error)
+	CreateOrganization(context.Context, *CreateOrganizationRequest) (*CreateOrganizationResponse, error)
+	ListOrganizations(context.Context, *ListOrganizationsRequest) (*ListOrganizationsResponse, error)
+	RemoveOrganization(context.Context, *RemoveOrganizationRequest) (*emptypb.Empty, error)
+	ListOrgMembers(context.Context, *ListOrgMembersRequest) (*ListOrgMembersResponse, error)
+	AddOrgMember(context.Context, *AddOrgMemberRequest) (*emptypb.Empty, error)
+	SetOrgMemberRole(context.Context, *SetOrgMemberRoleRequest) (*emptypb.Empty, error)
+	RemoveOrgMember(context.Context, *RemoveOrgMemberRequest) (*emptypb.Empty, error)
+	GetOrgKey(context.Context, *GetOrgKeyRequest) (*GetOrgKeyResponse, error)
+	CreateCollection(context.Context, *CreateCollectionRequest) (*CreateCollectionResponse, error)
+	ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error)
+	RemoveCollection(context.Context, *RemoveCollectionRequest) (*emptypb.Empty, error)
+	CreateFolder(context.Context, *CreateFolderRequest) (*CreateFolderResponse, error)
+	RenameFolder(context.Context, *RenameFolderRequest) (*emptypb.Empty, error)
+	MoveFolder(context.Context, *MoveFolderRequest) (*emptypb.Empty, error)
+	RemoveFolder(context.Context, *RemoveFolderRequest) (*emptypb.Empty, error)
+	GetFolders(context.Context, *GetFoldersRequest) (*GetFoldersResponse, error)
+	SetItemFolder(context.Context, *SetItemFolderRequest) (*emptypb.Empty, error)
+	CreateTag(context.Context, *CreateTagRequest) (*CreateTagResponse, error)
+	RenameTag(context.Context, *RenameTagRequest) (*emptypb.Empty, error)
+	RemoveTag(context.Context, *RemoveTagRequest) (*emptypb.Empty, error)
+	GetTags(context.Context, *GetTagsRequest) (*GetTagsResponse, error)
+	TagItem(context.Context, *TagItemRequest) (*emptypb.Empty, error)
+	UntagItem(context.Context, *UntagItemRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedGophkeeperServer()
 }
 
-func RegisterGophkeeperServer(s grpc.ServiceRegistrar, srv GophkeeperServer) {
-	// If the following call pancis, it indicates UnimplementedGophkeeperServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+// UnimplementedGophkeeperServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGophkeeperServer struct{}
+
+func (UnimplementedGophkeeperServer) RegisterUser(context.Context, *RegisterUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterUser not implemented")
+}
+func (UnimplementedGophkeeperServer) Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
+func (UnimplementedGophkeeperServer) Echo(context.Context, *EchoRequest) (*EchoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Echo not implemented")
+}
+func (UnimplementedGophkeeperServer) AddBankCard(context.Context, *AddBankCardRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBankCard not implemented")
+}
+func (UnimplementedGophkeeperServer) UpdateBankCard(context.Context, *UpdateBankCardRequest) (*UpdateBankCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBankCard not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveBankCard(context.Context, *RemoveBankCardRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBankCard not implemented")
+}
+func (UnimplementedGophkeeperServer) GetBankCards(context.Context, *GetBankCardsRequest) (*GetBankCardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBankCards not implemented")
+}
+func (UnimplementedGophkeeperServer) GetBankCard(context.Context, *GetBankCardRequest) (*GetBankCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBankCard not implemented")
+}
+func (UnimplementedGophkeeperServer) AddOTPItem(context.Context, *AddOTPItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOTPItem not implemented")
+}
+func (UnimplementedGophkeeperServer) UpdateOTPItem(context.Context, *UpdateOTPItemRequest) (*UpdateOTPItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOTPItem not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveOTPItem(context.Context, *RemoveOTPItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOTPItem not implemented")
+}
+func (UnimplementedGophkeeperServer) GetOTPItems(context.Context, *GetOTPItemsRequest) (*GetOTPItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOTPItems not implemented")
+}
+func (UnimplementedGophkeeperServer) GetOTPItem(context.Context, *GetOTPItemRequest) (*GetOTPItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOTPItem not implemented")
+}
+func (UnimplementedGophkeeperServer) AddItemTemplate(context.Context, *AddItemTemplateRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddItemTemplate not implemented")
+}
+func (UnimplementedGophkeeperServer) GetItemTemplates(context.Context, *GetItemTemplatesRequest) (*GetItemTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItemTemplates not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveItemTemplate(context.Context, *RemoveItemTemplateRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveItemTemplate not implemented")
+}
+func (UnimplementedGophkeeperServer) AddCustomItem(context.Context, *AddCustomItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCustomItem not implemented")
+}
+func (UnimplementedGophkeeperServer) UpdateCustomItem(context.Context, *UpdateCustomItemRequest) (*UpdateCustomItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCustomItem not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveCustomItem(context.Context, *RemoveCustomItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCustomItem not implemented")
+}
+func (UnimplementedGophkeeperServer) GetCustomItems(context.Context, *GetCustomItemsRequest) (*GetCustomItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomItems not implemented")
+}
+func (UnimplementedGophkeeperServer) GetCustomItem(context.Context, *GetCustomItemRequest) (*GetCustomItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomItem not implemented")
+}
+func (UnimplementedGophkeeperServer) AddUserCredentials(context.Context, *AddUserCredentialsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddUserCredentials not implemented")
+}
+func (UnimplementedGophkeeperServer) UpdateUserCredentials(context.Context, *UpdateUserCredentialsRequest) (*UpdateUserCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserCredentials not implemented")
+}
+func (UnimplementedGophkeeperServer) GetUserCredentials(context.Context, *GetUserCredentialsRequest) (*GetUserCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserCredentials not implemented")
+}
+func (UnimplementedGophkeeperServer) GetUserCredential(context.Context, *GetUserCredentialRequest) (*GetUserCredentialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserCredential not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveUserCredentials(context.Context, *RemoveUserCredentialsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveUserCredentials not implemented")
+}
+func (UnimplementedGophkeeperServer) AddNote(context.Context, *AddNoteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNote not implemented")
+}
+func (UnimplementedGophkeeperServer) UpdateNote(context.Context, *UpdateNoteRequest) (*UpdateNoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNote not implemented")
+}
+func (UnimplementedGophkeeperServer) GetNotes(context.Context, *GetNotesRequest) (*GetNotesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotes not implemented")
+}
+func (UnimplementedGophkeeperServer) GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNote not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveNote(context.Context, *RemoveNoteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNote not implemented")
+}
+func (UnimplementedGophkeeperServer) Upload(grpc.ClientStreamingServer[FileUploadRequest, FileUploadResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedGophkeeperServer) Download(*FileDownloadRequest, grpc.ServerStreamingServer[FileDownloadResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveFile(context.Context, *FileRemoveRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFile not implemented")
+}
+func (UnimplementedGophkeeperServer) UpdateFile(context.Context, *UpdateFileRequest) (*UpdateFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFile not implemented")
+}
+func (UnimplementedGophkeeperServer) GetFiles(context.Context, *GetFilesRequest) (*GetFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFiles not implemented")
+}
+func (UnimplementedGophkeeperServer) EnrollTOTP(context.Context, *EnrollTOTPRequest) (*EnrollTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedGophkeeperServer) ConfirmTOTP(context.Context, *ConfirmTOTPRequest) (*ConfirmTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedGophkeeperServer) DisableTOTP(context.Context, *DisableTOTPRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedGophkeeperServer) RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegenerateRecoveryCodes not implemented")
+}
+func (UnimplementedGophkeeperServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedGophkeeperServer) Logout(context.Context, *LogoutRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedGophkeeperServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedGophkeeperServer) RevokeSession(context.Context, *RevokeSessionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedGophkeeperServer) RekeyVault(context.Context, *RekeyVaultRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RekeyVault not implemented")
+}
+func (UnimplementedGophkeeperServer) GetJWKS(context.Context, *GetJWKSRequest) (*GetJWKSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJWKS not implemented")
+}
+func (UnimplementedGophkeeperServer) GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditLog not implemented")
+}
+func (UnimplementedGophkeeperServer) SetSharingKeys(context.Context, *SetSharingKeysRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSharingKeys not implemented")
+}
+func (UnimplementedGophkeeperServer) GetSharingKeys(context.Context, *GetSharingKeysRequest) (*GetSharingKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSharingKeys not implemented")
+}
+func (UnimplementedGophkeeperServer) GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
+}
+func (UnimplementedGophkeeperServer) GetItemShares(context.Context, *GetItemSharesRequest) (*GetItemSharesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItemShares not implemented")
+}
+func (UnimplementedGophkeeperServer) ShareItem(context.Context, *ShareItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareItem not implemented")
+}
+func (UnimplementedGophkeeperServer) ListSharedWithMe(context.Context, *ListSharedWithMeRequest) (*ListSharedWithMeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSharedWithMe not implemented")
+}
+func (UnimplementedGophkeeperServer) RevokeShare(context.Context, *RevokeShareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShare not implemented")
+}
+func (UnimplementedGophkeeperServer) CreateOrganization(context.Context, *CreateOrganizationRequest) (*CreateOrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedGophkeeperServer) ListOrganizations(context.Context, *ListOrganizationsRequest) (*ListOrganizationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrganizations not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveOrganization(context.Context, *RemoveOrganizationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOrganization not implemented")
+}
+func (UnimplementedGophkeeperServer) ListOrgMembers(context.Context, *ListOrgMembersRequest) (*ListOrgMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrgMembers not implemented")
+}
+func (UnimplementedGophkeeperServer) AddOrgMember(context.Context, *AddOrgMemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOrgMember not implemented")
+}
+func (UnimplementedGophkeeperServer) SetOrgMemberRole(context.Context, *SetOrgMemberRoleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOrgMemberRole not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveOrgMember(context.Context, *RemoveOrgMemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOrgMember not implemented")
+}
+func (UnimplementedGophkeeperServer) GetOrgKey(context.Context, *GetOrgKeyRequest) (*GetOrgKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrgKey not implemented")
+}
+func (UnimplementedGophkeeperServer) CreateCollection(context.Context, *CreateCollectionRequest) (*CreateCollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCollection not implemented")
+}
+func (UnimplementedGophkeeperServer) ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollections not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveCollection(context.Context, *RemoveCollectionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCollection not implemented")
+}
+func (UnimplementedGophkeeperServer) CreateFolder(context.Context, *CreateFolderRequest) (*CreateFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
+}
+func (UnimplementedGophkeeperServer) RenameFolder(context.Context, *RenameFolderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFolder not implemented")
+}
+func (UnimplementedGophkeeperServer) MoveFolder(context.Context, *MoveFolderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFolder not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveFolder(context.Context, *RemoveFolderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFolder not implemented")
+}
+func (UnimplementedGophkeeperServer) GetFolders(context.Context, *GetFoldersRequest) (*GetFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFolders not implemented")
+}
+func (UnimplementedGophkeeperServer) SetItemFolder(context.Context, *SetItemFolderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetItemFolder not implemented")
+}
+func (UnimplementedGophkeeperServer) CreateTag(context.Context, *CreateTagRequest) (*CreateTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedGophkeeperServer) RenameTag(context.Context, *RenameTagRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameTag not implemented")
+}
+func (UnimplementedGophkeeperServer) RemoveTag(context.Context, *RemoveTagRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTag not implemented")
+}
+func (UnimplementedGophkeeperServer) GetTags(context.Context, *GetTagsRequest) (*GetTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTags not implemented")
+}
+func (UnimplementedGophkeeperServer) TagItem(context.Context, *TagItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagItem not implemented")
+}
+func (UnimplementedGophkeeperServer) UntagItem(context.Context, *UntagItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UntagItem not implemented")
+}
+func (UnimplementedGophkeeperServer) mustEmbedUnimplementedGophkeeperServer() {}
+func (UnimplementedGophkeeperServer) testEmbeddedByValue()                    {}
+
+// UnsafeGophkeeperServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GophkeeperServer will
+// result in compilation errors.
+type UnsafeGophkeeperServer interface {
+	mustEmbedUnimplementedGophkeeperServer()
+}
+
+func RegisterGophkeeperServer(s grpc.ServiceRegistrar, srv GophkeeperServer) {
+	// If the following call pancis, it indicates UnimplementedGophkeeperServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Gophkeeper_ServiceDesc, srv)
+}
+
+func _Gophkeeper_RegisterUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RegisterUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RegisterUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RegisterUser(ctx, req.(*RegisterUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_Authorize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).Authorize(ctx, req.(*AuthorizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).Echo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_Echo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).Echo(ctx, req.(*EchoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_AddBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBankCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).AddBankCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_AddBankCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).AddBankCard(ctx, req.(*AddBankCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_UpdateBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBankCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).UpdateBankCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_UpdateBankCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).UpdateBankCard(ctx, req.(*UpdateBankCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBankCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveBankCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveBankCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveBankCard(ctx, req.(*RemoveBankCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetBankCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBankCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetBankCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetBankCards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetBankCards(ctx, req.(*GetBankCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBankCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetBankCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetBankCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetBankCard(ctx, req.(*GetBankCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_AddOTPItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOTPItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).AddOTPItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_AddOTPItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).AddOTPItem(ctx, req.(*AddOTPItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_UpdateOTPItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOTPItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).UpdateOTPItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_UpdateOTPItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).UpdateOTPItem(ctx, req.(*UpdateOTPItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveOTPItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOTPItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveOTPItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveOTPItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveOTPItem(ctx, req.(*RemoveOTPItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetOTPItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOTPItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetOTPItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetOTPItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetOTPItems(ctx, req.(*GetOTPItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetOTPItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOTPItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetOTPItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetOTPItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetOTPItem(ctx, req.(*GetOTPItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_AddItemTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddItemTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).AddItemTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_AddItemTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).AddItemTemplate(ctx, req.(*AddItemTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetItemTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetItemTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetItemTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetItemTemplates(ctx, req.(*GetItemTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveItemTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveItemTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveItemTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveItemTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveItemTemplate(ctx, req.(*RemoveItemTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_AddCustomItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCustomItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).AddCustomItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_AddCustomItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).AddCustomItem(ctx, req.(*AddCustomItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_UpdateCustomItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCustomItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).UpdateCustomItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_UpdateCustomItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).UpdateCustomItem(ctx, req.(*UpdateCustomItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveCustomItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCustomItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveCustomItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveCustomItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveCustomItem(ctx, req.(*RemoveCustomItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetCustomItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetCustomItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetCustomItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetCustomItems(ctx, req.(*GetCustomItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetCustomItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetCustomItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetCustomItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetCustomItem(ctx, req.(*GetCustomItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_AddUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddUserCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).AddUserCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_AddUserCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).AddUserCredentials(ctx, req.(*AddUserCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_UpdateUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).UpdateUserCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_UpdateUserCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).UpdateUserCredentials(ctx, req.(*UpdateUserCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetUserCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetUserCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetUserCredentials(ctx, req.(*GetUserCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetUserCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserCredentialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetUserCredential(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetUserCredential_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetUserCredential(ctx, req.(*GetUserCredentialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveUserCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveUserCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveUserCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveUserCredentials(ctx, req.(*RemoveUserCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_AddNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).AddNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_AddNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).AddNote(ctx, req.(*AddNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_UpdateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).UpdateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_UpdateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).UpdateNote(ctx, req.(*UpdateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetNotes(ctx, req.(*GetNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetNote(ctx, req.(*GetNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveNote(ctx, req.(*RemoveNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GophkeeperServer).Upload(&grpc.GenericServerStream[FileUploadRequest, FileUploadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Gophkeeper_UploadServer = grpc.ClientStreamingServer[FileUploadRequest, FileUploadResponse]
+
+func _Gophkeeper_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileDownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GophkeeperServer).Download(m, &grpc.GenericServerStream[FileDownloadRequest, FileDownloadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Gophkeeper_DownloadServer = grpc.ServerStreamingServer[FileDownloadResponse]
+
+func _Gophkeeper_RemoveFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileRemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveFile(ctx, req.(*FileRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_UpdateFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).UpdateFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_UpdateFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).UpdateFile(ctx, req.(*UpdateFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetFiles(ctx, req.(*GetFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_EnrollTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).EnrollTOTP(ctx, req.(*EnrollTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_ConfirmTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).ConfirmTOTP(ctx, req.(*ConfirmTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_DisableTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).DisableTOTP(ctx, req.(*DisableTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RegenerateRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateRecoveryCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RegenerateRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RegenerateRecoveryCodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RegenerateRecoveryCodes(ctx, req.(*RegenerateRecoveryCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RekeyVault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RekeyVaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RekeyVault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RekeyVault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RekeyVault(ctx, req.(*RekeyVaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetJWKS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJWKSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetJWKS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetJWKS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetJWKS(ctx, req.(*GetJWKSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetAuditLog(ctx, req.(*GetAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_SetSharingKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSharingKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).SetSharingKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_SetSharingKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).SetSharingKeys(ctx, req.(*SetSharingKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetSharingKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSharingKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetSharingKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetSharingKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetSharingKeys(ctx, req.(*GetSharingKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetPublicKey(ctx, req.(*GetPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_GetItemShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemSharesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetItemShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetItemShares_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetItemShares(ctx, req.(*GetItemSharesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_ShareItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).ShareItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_ShareItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).ShareItem(ctx, req.(*ShareItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_ListSharedWithMe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSharedWithMeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).ListSharedWithMe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_ListSharedWithMe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).ListSharedWithMe(ctx, req.(*ListSharedWithMeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RevokeShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RevokeShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RevokeShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RevokeShare(ctx, req.(*RevokeShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_CreateOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).CreateOrganization(ctx, req.(*CreateOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_ListOrganizations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrganizationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).ListOrganizations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_ListOrganizations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).ListOrganizations(ctx, req.(*ListOrganizationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_RemoveOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveOrganization(ctx, req.(*RemoveOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gophkeeper_ListOrgMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrgMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&Gophkeeper_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(GophkeeperServer).ListOrgMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_ListOrgMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).ListOrgMembers(ctx, req.(*ListOrgMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_RegisterUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterUserRequest)
+func _Gophkeeper_AddOrgMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOrgMemberRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).RegisterUser(ctx, in)
+		return srv.(GophkeeperServer).AddOrgMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_RegisterUser_FullMethodName,
+		FullMethod: Gophkeeper_AddOrgMember_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).RegisterUser(ctx, req.(*RegisterUserRequest))
+		return srv.(GophkeeperServer).AddOrgMember(ctx, req.(*AddOrgMemberRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AuthorizeRequest)
+func _Gophkeeper_SetOrgMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOrgMemberRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).Authorize(ctx, in)
+		return srv.(GophkeeperServer).SetOrgMemberRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_Authorize_FullMethodName,
+		FullMethod: Gophkeeper_SetOrgMemberRole_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).Authorize(ctx, req.(*AuthorizeRequest))
+		return srv.(GophkeeperServer).SetOrgMemberRole(ctx, req.(*SetOrgMemberRoleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EchoRequest)
+func _Gophkeeper_RemoveOrgMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOrgMemberRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).Echo(ctx, in)
+		return srv.(GophkeeperServer).RemoveOrgMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_Echo_FullMethodName,
+		FullMethod: Gophkeeper_RemoveOrgMember_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).Echo(ctx, req.(*EchoRequest))
+		return srv.(GophkeeperServer).RemoveOrgMember(ctx, req.(*RemoveOrgMemberRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_AddBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddBankCardRequest)
+func _Gophkeeper_GetOrgKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrgKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).AddBankCard(ctx, in)
+		return srv.(GophkeeperServer).GetOrgKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_AddBankCard_FullMethodName,
+		FullMethod: Gophkeeper_GetOrgKey_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).AddBankCard(ctx, req.(*AddBankCardRequest))
+		return srv.(GophkeeperServer).GetOrgKey(ctx, req.(*GetOrgKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_RemoveBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveBankCardRequest)
+func _Gophkeeper_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCollectionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).RemoveBankCard(ctx, in)
+		return srv.(GophkeeperServer).CreateCollection(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_RemoveBankCard_FullMethodName,
+		FullMethod: Gophkeeper_CreateCollection_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).RemoveBankCard(ctx, req.(*RemoveBankCardRequest))
+		return srv.(GophkeeperServer).CreateCollection(ctx, req.(*CreateCollectionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetBankCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBankCardsRequest)
+func _Gophkeeper_ListCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCollectionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetBankCards(ctx, in)
+		return srv.(GophkeeperServer).ListCollections(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetBankCards_FullMethodName,
+		FullMethod: Gophkeeper_ListCollections_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetBankCards(ctx, req.(*GetBankCardsRequest))
+		return srv.(GophkeeperServer).ListCollections(ctx, req.(*ListCollectionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetBankCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBankCardRequest)
+func _Gophkeeper_RemoveCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCollectionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetBankCard(ctx, in)
+		return srv.(GophkeeperServer).RemoveCollection(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetBankCard_FullMethodName,
+		FullMethod: Gophkeeper_RemoveCollection_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetBankCard(ctx, req.(*GetBankCardRequest))
+		return srv.(GophkeeperServer).RemoveCollection(ctx, req.(*RemoveCollectionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_AddUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddUserCredentialsRequest)
+func _Gophkeeper_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFolderRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).AddUserCredentials(ctx, in)
+		return srv.(GophkeeperServer).CreateFolder(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_AddUserCredentials_FullMethodName,
+		FullMethod: Gophkeeper_CreateFolder_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).AddUserCredentials(ctx, req.(*AddUserCredentialsRequest))
+		return srv.(GophkeeperServer).CreateFolder(ctx, req.(*CreateFolderRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetUserCredentialsRequest)
+func _Gophkeeper_RenameFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFolderRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetUserCredentials(ctx, in)
+		return srv.(GophkeeperServer).RenameFolder(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetUserCredentials_FullMethodName,
+		FullMethod: Gophkeeper_RenameFolder_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetUserCredentials(ctx, req.(*GetUserCredentialsRequest))
+		return srv.(GophkeeperServer).RenameFolder(ctx, req.(*RenameFolderRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetUserCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetUserCredentialRequest)
+func _Gophkeeper_MoveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveFolderRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetUserCredential(ctx, in)
+		return srv.(GophkeeperServer).MoveFolder(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetUserCredential_FullMethodName,
+		FullMethod: Gophkeeper_MoveFolder_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetUserCredential(ctx, req.(*GetUserCredentialRequest))
+		return srv.(GophkeeperServer).MoveFolder(ctx, req.(*MoveFolderRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_RemoveUserCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveUserCredentialsRequest)
+func _Gophkeeper_RemoveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveFolderRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).RemoveUserCredentials(ctx, in)
+		return srv.(GophkeeperServer).RemoveFolder(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_RemoveUserCredentials_FullMethodName,
+		FullMethod: Gophkeeper_RemoveFolder_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).RemoveUserCredentials(ctx, req.(*RemoveUserCredentialsRequest))
+		return srv.(GophkeeperServer).RemoveFolder(ctx, req.(*RemoveFolderRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_AddNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddNoteRequest)
+func _Gophkeeper_GetFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFoldersRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).AddNote(ctx, in)
+		return srv.(GophkeeperServer).GetFolders(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_AddNote_FullMethodName,
+		FullMethod: Gophkeeper_GetFolders_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).AddNote(ctx, req.(*AddNoteRequest))
+		return srv.(GophkeeperServer).GetFolders(ctx, req.(*GetFoldersRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetNotesRequest)
+func _Gophkeeper_SetItemFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetItemFolderRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetNotes(ctx, in)
+		return srv.(GophkeeperServer).SetItemFolder(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetNotes_FullMethodName,
+		FullMethod: Gophkeeper_SetItemFolder_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetNotes(ctx, req.(*GetNotesRequest))
+		return srv.(GophkeeperServer).SetItemFolder(ctx, req.(*SetItemFolderRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetNoteRequest)
+func _Gophkeeper_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetNote(ctx, in)
+		return srv.(GophkeeperServer).CreateTag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetNote_FullMethodName,
+		FullMethod: Gophkeeper_CreateTag_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetNote(ctx, req.(*GetNoteRequest))
+		return srv.(GophkeeperServer).CreateTag(ctx, req.(*CreateTagRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_RemoveNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveNoteRequest)
+func _Gophkeeper_RenameTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameTagRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).RemoveNote(ctx, in)
+		return srv.(GophkeeperServer).RenameTag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_RemoveNote_FullMethodName,
+		FullMethod: Gophkeeper_RenameTag_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).RemoveNote(ctx, req.(*RemoveNoteRequest))
+		return srv.(GophkeeperServer).RenameTag(ctx, req.(*RenameTagRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(GophkeeperServer).Upload(&grpc.GenericServerStream[FileUploadRequest, FileUploadResponse]{ServerStream: stream})
+func _Gophkeeper_RemoveTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophkeeperServer).RemoveTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_RemoveTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).RemoveTag(ctx, req.(*RemoveTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Gophkeeper_UploadServer = grpc.ClientStreamingServer[FileUploadRequest, FileUploadResponse]
-
-func _Gophkeeper_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(FileDownloadRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Gophkeeper_GetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(GophkeeperServer).Download(m, &grpc.GenericServerStream[FileDownloadRequest, FileDownloadResponse]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(GophkeeperServer).GetTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gophkeeper_GetTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophkeeperServer).GetTags(ctx, req.(*GetTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Gophkeeper_DownloadServer = grpc.ServerStreamingServer[FileDownloadResponse]
-
-func _Gophkeeper_RemoveFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FileRemoveRequest)
+func _Gophkeeper_TagItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).RemoveFile(ctx, in)
+		return srv.(GophkeeperServer).TagItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_RemoveFile_FullMethodName,
+		FullMethod: Gophkeeper_TagItem_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).RemoveFile(ctx, req.(*FileRemoveRequest))
+		return srv.(GophkeeperServer).TagItem(ctx, req.(*TagItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Gophkeeper_GetFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetFilesRequest)
+func _Gophkeeper_UntagItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UntagItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophkeeperServer).GetFiles(ctx, in)
+		return srv.(GophkeeperServer).UntagItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Gophkeeper_GetFiles_FullMethodName,
+		FullMethod: Gophkeeper_UntagItem_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophkeeperServer).GetFiles(ctx, req.(*GetFilesRequest))
+		return srv.(GophkeeperServer).UntagItem(ctx, req.(*UntagItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -734,6 +2706,10 @@ var Gophkeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddBankCard",
 			Handler:    _Gophkeeper_AddBankCard_Handler,
 		},
+		{
+			MethodName: "UpdateBankCard",
+			Handler:    _Gophkeeper_UpdateBankCard_Handler,
+		},
 		{
 			MethodName: "RemoveBankCard",
 			Handler:    _Gophkeeper_RemoveBankCard_Handler,
@@ -746,10 +2722,66 @@ var Gophkeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBankCard",
 			Handler:    _Gophkeeper_GetBankCard_Handler,
 		},
+		{
+			MethodName: "AddOTPItem",
+			Handler:    _Gophkeeper_AddOTPItem_Handler,
+		},
+		{
+			MethodName: "UpdateOTPItem",
+			Handler:    _Gophkeeper_UpdateOTPItem_Handler,
+		},
+		{
+			MethodName: "RemoveOTPItem",
+			Handler:    _Gophkeeper_RemoveOTPItem_Handler,
+		},
+		{
+			MethodName: "GetOTPItems",
+			Handler:    _Gophkeeper_GetOTPItems_Handler,
+		},
+		{
+			MethodName: "GetOTPItem",
+			Handler:    _Gophkeeper_GetOTPItem_Handler,
+		},
+		{
+			MethodName: "AddItemTemplate",
+			Handler:    _Gophkeeper_AddItemTemplate_Handler,
+		},
+		{
+			MethodName: "GetItemTemplates",
+			Handler:    _Gophkeeper_GetItemTemplates_Handler,
+		},
+		{
+			MethodName: "RemoveItemTemplate",
+			Handler:    _Gophkeeper_RemoveItemTemplate_Handler,
+		},
+		{
+			MethodName: "AddCustomItem",
+			Handler:    _Gophkeeper_AddCustomItem_Handler,
+		},
+		{
+			MethodName: "UpdateCustomItem",
+			Handler:    _Gophkeeper_UpdateCustomItem_Handler,
+		},
+		{
+			MethodName: "RemoveCustomItem",
+			Handler:    _Gophkeeper_RemoveCustomItem_Handler,
+		},
+		{
+			MethodName: "GetCustomItems",
+			Handler:    _Gophkeeper_GetCustomItems_Handler,
+		},
+		{
+			MethodName: "GetCustomItem",
+			Handler:    _Gophkeeper_GetCustomItem_Handler,
+		},
 		{
 			MethodName: "AddUserCredentials",
 			Handler:    _Gophkeeper_AddUserCredentials_Handler,
 		},
+		{
+			MethodName: "UpdateUserCredentials",
+			Handler:    _Gophkeeper_UpdateUserCredentials_Handler,
+		},
 		{
 			MethodName: "GetUserCredentials",
 			Handler:    _Gophkeeper_GetUserCredentials_Handler,
@@ -766,6 +2798,10 @@ var Gophkeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddNote",
 			Handler:    _Gophkeeper_AddNote_Handler,
 		},
+		{
+			MethodName: "UpdateNote",
+			Handler:    _Gophkeeper_UpdateNote_Handler,
+		},
 		{
 			MethodName: "GetNotes",
 			Handler:    _Gophkeeper_GetNotes_Handler,
@@ -782,10 +2818,178 @@ var Gophkeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveFile",
 			Handler:    _Gophkeeper_RemoveFile_Handler,
 		},
+		{
+			MethodName: "UpdateFile",
+			Handler:    _Gophkeeper_UpdateFile_Handler,
+		},
 		{
 			MethodName: "GetFiles",
 			Handler:    _Gophkeeper_GetFiles_Handler,
 		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _Gophkeeper_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _Gophkeeper_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _Gophkeeper_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "RegenerateRecoveryCodes",
+			Handler:    _Gophkeeper_RegenerateRecoveryCodes_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _Gophkeeper_RefreshToken_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Gophkeeper_Logout_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Gophkeeper_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Gophkeeper_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RekeyVault",
+			Handler:    _Gophkeeper_RekeyVault_Handler,
+		},
+		{
+			MethodName: "GetJWKS",
+			Handler:    _Gophkeeper_GetJWKS_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _Gophkeeper_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "SetSharingKeys",
+			Handler:    _Gophkeeper_SetSharingKeys_Handler,
+		},
+		{
+			MethodName: "GetSharingKeys",
+			Handler:    _Gophkeeper_GetSharingKeys_Handler,
+		},
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _Gophkeeper_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "GetItemShares",
+			Handler:    _Gophkeeper_GetItemShares_Handler,
+		},
+		{
+			MethodName: "ShareItem",
+			Handler:    _Gophkeeper_ShareItem_Handler,
+		},
+		{
+			MethodName: "ListSharedWithMe",
+			Handler:    _Gophkeeper_ListSharedWithMe_Handler,
+		},
+		{
+			MethodName: "RevokeShare",
+			Handler:    _Gophkeeper_RevokeShare_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _Gophkeeper_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "ListOrganizations",
+			Handler:    _Gophkeeper_ListOrganizations_Handler,
+		},
+		{
+			MethodName: "RemoveOrganization",
+			Handler:    _Gophkeeper_RemoveOrganization_Handler,
+		},
+		{
+			MethodName: "ListOrgMembers",
+			Handler:    _Gophkeeper_ListOrgMembers_Handler,
+		},
+		{
+			MethodName: "AddOrgMember",
+			Handler:    _Gophkeeper_AddOrgMember_Handler,
+		},
+		{
+			MethodName: "SetOrgMemberRole",
+			Handler:    _Gophkeeper_SetOrgMemberRole_Handler,
+		},
+		{
+			MethodName: "RemoveOrgMember",
+			Handler:    _Gophkeeper_RemoveOrgMember_Handler,
+		},
+		{
+			MethodName: "GetOrgKey",
+			Handler:    _Gophkeeper_GetOrgKey_Handler,
+		},
+		{
+			MethodName: "CreateCollection",
+			Handler:    _Gophkeeper_CreateCollection_Handler,
+		},
+		{
+			MethodName: "ListCollections",
+			Handler:    _Gophkeeper_ListCollections_Handler,
+		},
+		{
+			MethodName: "RemoveCollection",
+			Handler:    _Gophkeeper_RemoveCollection_Handler,
+		},
+		{
+			MethodName: "CreateFolder",
+			Handler:    _Gophkeeper_CreateFolder_Handler,
+		},
+		{
+			MethodName: "RenameFolder",
+			Handler:    _Gophkeeper_RenameFolder_Handler,
+		},
+		{
+			MethodName: "MoveFolder",
+			Handler:    _Gophkeeper_MoveFolder_Handler,
+		},
+		{
+			MethodName: "RemoveFolder",
+			Handler:    _Gophkeeper_RemoveFolder_Handler,
+		},
+		{
+			MethodName: "GetFolders",
+			Handler:    _Gophkeeper_GetFolders_Handler,
+		},
+		{
+			MethodName: "SetItemFolder",
+			Handler:    _Gophkeeper_SetItemFolder_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _Gophkeeper_CreateTag_Handler,
+		},
+		{
+			MethodName: "RenameTag",
+			Handler:    _Gophkeeper_RenameTag_Handler,
+		},
+		{
+			MethodName: "RemoveTag",
+			Handler:    _Gophkeeper_RemoveTag_Handler,
+		},
+		{
+			MethodName: "GetTags",
+			Handler:    _Gophkeeper_GetTags_Handler,
+		},
+		{
+			MethodName: "TagItem",
+			Handler:    _Gophkeeper_TagItem_Handler,
+		},
+		{
+			MethodName: "UntagItem",
+			Handler:    _Gophkeeper_UntagItem_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{